@@ -103,9 +103,6 @@ func main() {
 	// Document Management Service (Phase 1.4 Real Estate)
 	documentService := services.NewDocumentService(dbConn)
 
-	// Possession Management Service (Phase 2.1 Real Estate)
-	possessionService := services.NewPossessionService(dbConn)
-
 	// Title Clearance Management Service (Phase 2.2 Real Estate)
 	titleService := services.NewTitleService(dbConn)
 
@@ -124,9 +121,6 @@ func main() {
 	// Civil Engineering Service
 	civilService := services.NewCivilService(dbConn)
 
-	// Construction Service
-	constructionService := services.NewConstructionService(dbConn)
-
 	// BOQ Service
 	boqService := services.NewBOQService(dbConn)
 
@@ -140,10 +134,37 @@ func main() {
 	rbacService := services.NewRBACService(dbConn, log)
 
 	// Compliance Services (RERA, HR, Tax)
-	reraComplianceService := services.NewRERAComplianceService(dbConn)
+	reraComplianceService := services.NewRERAComplianceService(dbConn, documentService)
 	hrComplianceService := services.NewHRComplianceService(dbConn)
 	taxComplianceService := services.NewTaxComplianceService(dbConn)
 
+	// Communication Service (provider-backed SMS/email/WhatsApp)
+	communicationService := services.NewCommunicationService(dbConn)
+
+	// Receivables Service (AR ageing & dunning)
+	receivablesService := services.NewReceivablesService(dbConn, communicationService)
+
+	// Delayed-Payment Interest Service (RERA)
+	delayedInterestService := services.NewDelayedInterestService(dbConn)
+
+	// Purchase Service (vendor invoices, AP & payment runs)
+	purchaseService := services.NewPurchaseService(dbConn)
+	einvoiceService := services.NewEInvoiceService(dbConn)
+	tdsService := services.NewTDSService(dbConn)
+	loanDisbursementService := services.NewLoanDisbursementService(dbConn, bankFinancingService, reraComplianceService)
+	paymentPlanService := services.NewPaymentPlanService(dbConn, communicationService, loanDisbursementService)
+	constructionService := services.NewConstructionService(dbConn, paymentPlanService)
+	priceListService := services.NewPriceListService(dbConn)
+	discountApprovalService := services.NewDiscountApprovalService(dbConn)
+	unitAvailabilityService := services.NewUnitAvailabilityService(dbConn, webSocketHub)
+	subventionService := services.NewSubventionService(dbConn, glService)
+	possessionService := services.NewPossessionService(dbConn, subventionService)
+	bookingCancellationService := services.NewBookingCancellationService(dbConn, glService, bankFinancingService, brokerService, unitAvailabilityService, subventionService)
+	unitTransferService := services.NewUnitTransferService(dbConn, glService, paymentPlanService, jointApplicantService, documentService)
+	snagService := services.NewSnagService(dbConn, glService, purchaseService)
+	maintenanceService := services.NewMaintenanceService(dbConn, glService)
+	landBankService := services.NewLandBankService(dbConn, glService, unitAvailabilityService)
+
 	// Initialize handlers
 	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
 
@@ -158,6 +179,15 @@ func main() {
 
 	// Broker Handler (Phase 1.2 Real Estate)
 	brokerHandler := handlers.NewBrokerHandler(brokerService)
+	brokerCommissionHandler := handlers.NewBrokerCommissionHandler(brokerService)
+
+	// Channel Partner Portal, signed with its own partner token secret
+	partnerJWTManager := auth.NewJWTManager(cfg.JWT.PartnerSecret, cfg.JWT.PartnerExpiration)
+	partnerService := services.NewPartnerService(dbConn)
+	partnerLeadService := services.NewPartnerLeadService(dbConn, partnerService)
+	partnerPayoutService := services.NewPartnerPayoutService(dbConn, partnerService, partnerLeadService)
+	partnerPortalService := services.NewPartnerPortalService(dbConn, partnerJWTManager, partnerService, partnerLeadService, partnerPayoutService, documentService)
+	partnerPortalHandler := handlers.NewPartnerPortalHandler(partnerPortalService)
 
 	// Joint Applicant Handler (Phase 1.3 Real Estate)
 	jointApplicantHandler := handlers.NewJointApplicantHandler(jointApplicantService)
@@ -195,6 +225,28 @@ func main() {
 	// Bank Financing Handler
 	bankFinancingHandler := handlers.NewBankFinancingHandler(bankFinancingService)
 
+	// Receivables Handler
+	receivablesHandler := handlers.NewReceivablesHandler(receivablesService)
+
+	// Delayed-Payment Interest Handler
+	delayedInterestHandler := handlers.NewDelayedInterestHandler(delayedInterestService)
+
+	// Accounts Payable Handler
+	payablesHandler := handlers.NewPayablesHandler(purchaseService, glService)
+	einvoiceHandler := handlers.NewEInvoiceHandler(einvoiceService)
+	tdsHandler := handlers.NewTDSHandler(tdsService)
+	paymentPlanHandler := handlers.NewPaymentPlanHandler(paymentPlanService)
+	priceListHandler := handlers.NewPriceListHandler(priceListService)
+	discountApprovalHandler := handlers.NewDiscountApprovalHandler(discountApprovalService)
+	bookingCancellationHandler := handlers.NewBookingCancellationHandler(bookingCancellationService)
+	unitTransferHandler := handlers.NewUnitTransferHandler(unitTransferService)
+	unitAvailabilityHandler := handlers.NewUnitAvailabilityHandler(unitAvailabilityService)
+	snagHandler := handlers.NewSnagHandler(snagService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+	landBankHandler := handlers.NewLandBankHandler(landBankService)
+	loanDisbursementHandler := handlers.NewLoanDisbursementHandler(loanDisbursementService)
+	subventionHandler := handlers.NewSubventionHandler(subventionService)
+
 	// Dashboard Handlers
 	financialDashboardHandler := handlers.NewFinancialDashboardHandler(glService)
 	hrDashboardHandler := handlers.NewHRDashboardHandler(hrService, hrComplianceService)
@@ -202,7 +254,7 @@ func main() {
 	salesDashboardHandler := handlers.NewSalesDashboardHandler(salesService)
 
 	// Setup router with all services
-	r := router.SetupRoutesWithPhase3C(authService, tenantService, passwordResetHandler, agentService, gamificationService, leadService, callService, campaignService, aiOrchestrator, webSocketHub, leadScoringService, dashboardService, taskService, notificationService, tenantCustomizationService, phase3cServices, salesService, realEstateService, civilService, constructionService, boqService, hrService, glService, rbacService, reraComplianceHandler, hrComplianceHandler, taxComplianceHandler, financialDashboardHandler, hrDashboardHandler, complianceDashboardHandler, salesDashboardHandler, brokerHandler, jointApplicantHandler, documentHandler, possessionHandler, titleHandler, customerPortalHandler, analyticsHandler, userAdminHandler, tenantAdminHandler, mobileHandler, aiRecommendationsHandler, siteVisitHandler, integrationHandler, bankFinancingHandler, log)
+	r := router.SetupRoutesWithPhase3C(authService, tenantService, passwordResetHandler, agentService, gamificationService, leadService, callService, campaignService, aiOrchestrator, webSocketHub, leadScoringService, dashboardService, taskService, notificationService, tenantCustomizationService, phase3cServices, salesService, realEstateService, civilService, constructionService, boqService, hrService, glService, rbacService, reraComplianceHandler, hrComplianceHandler, taxComplianceHandler, financialDashboardHandler, hrDashboardHandler, complianceDashboardHandler, salesDashboardHandler, brokerHandler, jointApplicantHandler, documentHandler, possessionHandler, titleHandler, customerPortalHandler, analyticsHandler, userAdminHandler, tenantAdminHandler, mobileHandler, aiRecommendationsHandler, siteVisitHandler, integrationHandler, bankFinancingHandler, receivablesHandler, delayedInterestHandler, payablesHandler, einvoiceHandler, tdsHandler, paymentPlanHandler, priceListHandler, discountApprovalHandler, bookingCancellationHandler, unitTransferHandler, unitAvailabilityHandler, snagHandler, maintenanceHandler, landBankHandler, loanDisbursementHandler, subventionHandler, brokerCommissionHandler, partnerPortalHandler, log)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -31,6 +31,10 @@ type DatabaseConfig struct {
 type JWTConfig struct {
 	Secret     []byte
 	Expiration time.Duration
+	// Channel partner portal tokens are signed with their own secret so they
+	// are never accepted on staff endpoints
+	PartnerSecret     []byte
+	PartnerExpiration time.Duration
 }
 
 type EmailConfig struct {
@@ -61,6 +65,9 @@ func Load() (*Config, error) {
 		JWT: JWTConfig{
 			Secret:     []byte(getEnv("JWT_SECRET", "your-secret-key")),
 			Expiration: 24 * time.Hour,
+
+			PartnerSecret:     []byte(getEnv("PARTNER_JWT_SECRET", "your-partner-secret-key")),
+			PartnerExpiration: 8 * time.Hour,
 		},
 		Email: EmailConfig{
 			SMTPHost:     getEnv("SMTP_HOST", "smtp.gmail.com"),
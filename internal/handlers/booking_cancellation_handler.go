@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// BOOKING CANCELLATION HANDLERS
+// ============================================================================
+
+type BookingCancellationHandler struct {
+	Service *services.BookingCancellationService
+}
+
+func NewBookingCancellationHandler(service *services.BookingCancellationService) *BookingCancellationHandler {
+	return &BookingCancellationHandler{Service: service}
+}
+
+// SetPolicy creates or replaces a project's forfeiture rules
+func (h *BookingCancellationHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetCancellationPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy, err := h.Service.SetPolicy(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+// ListPolicies lists forfeiture rules
+func (h *BookingCancellationHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	policies, err := h.Service.ListPolicies(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policies)
+}
+
+// PreviewStatement returns the refund statement for cancelling a booking today
+func (h *BookingCancellationHandler) PreviewStatement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	statement, err := h.Service.ComputeStatement(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statement)
+}
+
+// RequestCancellation raises a cancellation request on a booking
+func (h *BookingCancellationHandler) RequestCancellation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateCancellationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cancellation, err := h.Service.RequestCancellation(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cancellation)
+}
+
+// ListCancellations lists cancellations for ?status=&booking_id=
+func (h *BookingCancellationHandler) ListCancellations(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	cancellations, err := h.Service.ListCancellations(tenantID, q.Get("status"), q.Get("booking_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cancellations)
+}
+
+// GetCancellation returns a cancellation with its refund statement
+func (h *BookingCancellationHandler) GetCancellation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	cancellation, err := h.Service.GetCancellation(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cancellation)
+}
+
+// DecideCancellation approves or rejects a cancellation and its refund
+func (h *BookingCancellationHandler) DecideCancellation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CancellationDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cancellation, err := h.Service.DecideCancellation(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cancellation)
+}
+
+// SettleCancellation retries the settlement of an approved cancellation
+func (h *BookingCancellationHandler) SettleCancellation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	cancellation, err := h.Service.SettleCancellation(tenantID, userID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cancellation)
+}
+
+// RecordRefundPayout records the refund paid on an approved cancellation
+func (h *BookingCancellationHandler) RecordRefundPayout(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordRefundPayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cancellation, err := h.Service.RecordRefundPayout(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cancellation)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// BROKER COMMISSION ENGINE HANDLERS
+// ============================================================================
+
+type BrokerCommissionHandler struct {
+	Service *services.BrokerService
+}
+
+func NewBrokerCommissionHandler(service *services.BrokerService) *BrokerCommissionHandler {
+	return &BrokerCommissionHandler{Service: service}
+}
+
+// CreatePlan sets up a commission plan with its trigger and quarterly slabs
+func (h *BrokerCommissionHandler) CreatePlan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateBrokerCommissionPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, err := h.Service.CreateCommissionPlan(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, plan)
+}
+
+// ListPlans lists active commission plans for ?broker_id=&project_id=
+func (h *BrokerCommissionHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	plans, err := h.Service.ListCommissionPlans(tenantID, q.Get("broker_id"), q.Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plans)
+}
+
+// SetSplits shares a booking's commission between its broker links
+func (h *BrokerCommissionHandler) SetSplits(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetBrokerCommissionSplitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	splits, err := h.Service.SetCommissionSplits(tenantID, mux.Vars(r)["bookingId"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, splits)
+}
+
+// EvaluateBooking accrues commission on a booking whose links have reached their trigger
+func (h *BrokerCommissionHandler) EvaluateBooking(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	run, err := h.Service.EvaluateBookingCommission(tenantID, mux.Vars(r)["bookingId"], userID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// EvaluatePending sweeps every pending booking link against its trigger
+func (h *BrokerCommissionHandler) EvaluatePending(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	run, err := h.Service.EvaluatePendingCommission(tenantID, userID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// ListAccruals lists commission accruals for ?broker_id=&booking_id=&status=
+func (h *BrokerCommissionHandler) ListAccruals(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	accruals, err := h.Service.ListCommissionAccruals(tenantID, q.Get("broker_id"), q.Get("booking_id"), q.Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, accruals)
+}
+
+// GeneratePayout pays a broker's accrued commission net of TDS and clawbacks
+func (h *BrokerCommissionHandler) GeneratePayout(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.GenerateBrokerPayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payout, err := h.Service.GenerateBrokerPayout(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, payout)
+}
+
+// ListPayouts lists payouts for ?broker_id=&status=
+func (h *BrokerCommissionHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	payouts, err := h.Service.ListBrokerPayouts(tenantID, q.Get("broker_id"), q.Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payouts)
+}
+
+// GetPayout returns a payout with the accruals it settled
+func (h *BrokerCommissionHandler) GetPayout(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	payout, err := h.Service.GetBrokerPayout(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payout)
+}
+
+// MarkPayoutPaid records payment of a generated payout
+func (h *BrokerCommissionHandler) MarkPayoutPaid(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.MarkBrokerPayoutPaidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payout, err := h.Service.MarkBrokerPayoutPaid(tenantID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payout)
+}
+
+// GetStatement returns a broker's commission statement for ?from=&to=, defaulting to the current quarter
+func (h *BrokerCommissionHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	now := time.Now()
+	from := time.Date(now.Year(), time.Month((int(now.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 3, 0)
+	if v := q.Get("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "from must be YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+	if v := q.Get("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "to must be YYYY-MM-DD")
+			return
+		}
+		// The statement runs to the end of the day given
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	statement, err := h.Service.GetBrokerStatement(tenantID, mux.Vars(r)["brokerId"], from, to)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statement)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -373,6 +374,10 @@ func (h *BrokerHandler) CreatePayout(w http.ResponseWriter, r *http.Request) {
 
 	payout, err := h.brokerService.CreatePayout(tenantID, &req, &userID)
 	if err != nil {
+		if errors.Is(err, services.ErrPayoutFromAccruals) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, fmt.Sprintf("failed to create payout: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
 	"vyomtech-backend/internal/services"
 
 	"github.com/gorilla/mux"
@@ -121,10 +122,28 @@ func (h *ConstructionHandler) ListBOQItems(w http.ResponseWriter, r *http.Reques
 }
 
 // LogProgress - POST /api/v1/construction/progress
+// Progress at 100% against a tower_milestone_id completes the milestone and raises its demand letters
 func (h *ConstructionHandler) LogProgress(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Progress logged"})
+	tenantID, ok := r.Context().Value(middleware.TenantIDKey).(string)
+	if !ok || tenantID == "" {
+		http.Error(w, `{"error": "Tenant ID not found in context"}`, http.StatusForbidden)
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.LogProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.LogProgress(r.Context(), tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, result)
 }
 
 // GetProgressHistory - GET /api/v1/construction/projects/{projectId}/progress
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// DELAYED-PAYMENT INTEREST HANDLERS
+// ============================================================================
+
+type DelayedInterestHandler struct {
+	Service *services.DelayedInterestService
+}
+
+func NewDelayedInterestHandler(service *services.DelayedInterestService) *DelayedInterestHandler {
+	return &DelayedInterestHandler{Service: service}
+}
+
+// AddInterestRate records a new benchmark rate (SBI MCLR) effective from a date
+func (h *DelayedInterestHandler) AddInterestRate(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateInterestRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.BaseRate <= 0 || req.EffectiveFrom.IsZero() {
+		respondWithError(w, http.StatusBadRequest, "base_rate and effective_from are required")
+		return
+	}
+
+	rate, err := h.Service.AddInterestRate(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, rate)
+}
+
+// ListInterestRates returns the rate history
+func (h *DelayedInterestHandler) ListInterestRates(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	rates, err := h.Service.ListInterestRates(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rates)
+}
+
+// UpsertInterestPolicy sets grace days and calculation method for the tenant or a project
+func (h *DelayedInterestHandler) UpsertInterestPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.UpsertInterestPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy, err := h.Service.UpsertInterestPolicy(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+// GetInterestPolicy returns the effective policy for a project
+func (h *DelayedInterestHandler) GetInterestPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	policy, err := h.Service.GetInterestPolicy(tenantID, r.URL.Query().Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if policy == nil {
+		respondWithError(w, http.StatusNotFound, "Interest policy not configured")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+// GetAccruedInterest previews interest accrued on overdue stages
+// Query params: project_id, as_of (YYYY-MM-DD)
+func (h *DelayedInterestHandler) GetAccruedInterest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
+	accruals, err := h.Service.ComputeStageInterest(tenantID, r.URL.Query().Get("project_id"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, accruals)
+}
+
+// RaiseInterestNotes raises interest demand notes into the customer ledger
+func (h *DelayedInterestHandler) RaiseInterestNotes(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RaiseInterestNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	notes, err := h.Service.RaiseInterestNotes(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, notes)
+}
+
+// ComputePossessionCompensation computes (and optionally credits) delayed possession compensation
+func (h *DelayedInterestHandler) ComputePossessionCompensation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.PossessionCompensationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.BookingID == "" {
+		respondWithError(w, http.StatusBadRequest, "booking_id is required")
+		return
+	}
+
+	comp, err := h.Service.ComputePossessionCompensation(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, comp)
+}
+
+// ListInterestNotes lists interest notes raised for a booking
+func (h *DelayedInterestHandler) ListInterestNotes(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	bookingID := mux.Vars(r)["booking_id"]
+
+	notes, err := h.Service.ListInterestNotes(tenantID, bookingID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, notes)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// DISCOUNT APPROVAL HANDLERS
+// ============================================================================
+
+type DiscountApprovalHandler struct {
+	Service *services.DiscountApprovalService
+}
+
+func NewDiscountApprovalHandler(service *services.DiscountApprovalService) *DiscountApprovalHandler {
+	return &DiscountApprovalHandler{Service: service}
+}
+
+// SetLimit creates or replaces a role's discount limit
+func (h *DiscountApprovalHandler) SetLimit(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetDiscountLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	limit, err := h.Service.SetLimit(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, limit)
+}
+
+// ListLimits lists discount limits for ?project_id=
+func (h *DiscountApprovalHandler) ListLimits(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	limits, err := h.Service.ListLimits(tenantID, r.URL.Query().Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, limits)
+}
+
+// RequestDiscount raises a discount on a unit or quotation
+func (h *DiscountApprovalHandler) RequestDiscount(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	role, _ := r.Context().Value(middleware.RoleKey).(string)
+
+	var req models.CreateDiscountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	discount, err := h.Service.RequestDiscount(tenantID, userID, role, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, discount)
+}
+
+// ListDiscountRequests lists requests for ?status=&unit_id=&quotation_id=, or ?awaiting=me for the caller's queue
+func (h *DiscountApprovalHandler) ListDiscountRequests(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	approverRole := ""
+	if q.Get("awaiting") == "me" {
+		approverRole, _ = r.Context().Value(middleware.RoleKey).(string)
+	}
+
+	requests, err := h.Service.ListDiscountRequests(tenantID, q.Get("status"), approverRole, q.Get("unit_id"), q.Get("quotation_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
+
+// GetDiscountRequest returns a discount request with its approval chain
+func (h *DiscountApprovalHandler) GetDiscountRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	discount, err := h.Service.GetDiscountRequest(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, discount)
+}
+
+// DecideDiscount approves or rejects the step awaiting the caller
+func (h *DiscountApprovalHandler) DecideDiscount(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	role, _ := r.Context().Value(middleware.RoleKey).(string)
+
+	var req models.DiscountDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	discount, err := h.Service.DecideDiscount(tenantID, userID, role, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, discount)
+}
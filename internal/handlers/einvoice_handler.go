@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// GST E-INVOICE AND E-WAY BILL HANDLERS
+// ============================================================================
+
+type EInvoiceHandler struct {
+	Service *services.EInvoiceService
+}
+
+func NewEInvoiceHandler(service *services.EInvoiceService) *EInvoiceHandler {
+	return &EInvoiceHandler{Service: service}
+}
+
+// GetSettings returns the tenant's seller profile and IRP provider
+func (h *EInvoiceHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	settings, err := h.Service.GetSettings(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+// UpsertSettings sets the seller profile and IRP provider
+func (h *EInvoiceHandler) UpsertSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.UpsertEInvoiceSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.Service.UpsertSettings(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+// PreviewPayload returns the INV-01 JSON for a sales invoice without registering it
+func (h *EInvoiceHandler) PreviewPayload(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	invoiceID := mux.Vars(r)["invoice_id"]
+
+	payload, err := h.Service.BuildEInvoicePayload(tenantID, invoiceID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payload)
+}
+
+// GenerateIRN registers a sales invoice with the IRP
+func (h *EInvoiceHandler) GenerateIRN(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	invoiceID := mux.Vars(r)["invoice_id"]
+
+	einv, err := h.Service.GenerateIRN(tenantID, invoiceID, userID)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, einv)
+}
+
+// GetEInvoice returns the IRN, ack number and signed QR for a sales invoice
+func (h *EInvoiceHandler) GetEInvoice(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	invoiceID := mux.Vars(r)["invoice_id"]
+
+	einv, err := h.Service.GetEInvoice(tenantID, invoiceID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, einv)
+}
+
+// CancelIRN cancels an IRN within 24 hours of generation
+func (h *EInvoiceHandler) CancelIRN(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	invoiceID := mux.Vars(r)["invoice_id"]
+
+	var req models.CancelIRNRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	einv, err := h.Service.CancelIRN(tenantID, invoiceID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, einv)
+}
+
+// GenerateTransferEWayBill raises an e-way bill for an inventory transfer between sites
+func (h *EInvoiceHandler) GenerateTransferEWayBill(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	transferID := mux.Vars(r)["transfer_id"]
+
+	var req models.GenerateTransferEWayBillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ewb, err := h.Service.GenerateTransferEWayBill(tenantID, transferID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, ewb)
+}
+
+// GetEWayBill returns an e-way bill
+func (h *EInvoiceHandler) GetEWayBill(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	ewb, err := h.Service.GetEWayBill(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ewb)
+}
+
+// CancelEWayBill cancels an e-way bill within 24 hours of generation
+func (h *EInvoiceHandler) CancelEWayBill(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CancelEWayBillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ewb, err := h.Service.CancelEWayBill(tenantID, mux.Vars(r)["id"], userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ewb)
+}
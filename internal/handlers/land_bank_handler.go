@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// LAND BANK & JDA HANDLERS
+// ============================================================================
+
+type LandBankHandler struct {
+	Service *services.LandBankService
+}
+
+func NewLandBankHandler(service *services.LandBankService) *LandBankHandler {
+	return &LandBankHandler{Service: service}
+}
+
+// CreateParcel adds a parcel to the land bank
+func (h *LandBankHandler) CreateParcel(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateLandParcelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	parcel, err := h.Service.CreateParcel(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, parcel)
+}
+
+// ListParcels lists the land bank for ?status=&village=
+func (h *LandBankHandler) ListParcels(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	parcels, err := h.Service.ListParcels(tenantID, q.Get("status"), q.Get("village"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, parcels)
+}
+
+// GetParcel returns a parcel with its acquisition costs and payments
+func (h *LandBankHandler) GetParcel(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	parcel, err := h.Service.GetParcel(tenantID, mux.Vars(r)["parcel_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, parcel)
+}
+
+// UpdateParcel updates a parcel's zoning, FSI, status or project
+func (h *LandBankHandler) UpdateParcel(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.UpdateLandParcelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	parcel, err := h.Service.UpdateParcel(tenantID, mux.Vars(r)["parcel_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, parcel)
+}
+
+// RecordCost accrues an acquisition cost on a parcel
+func (h *LandBankHandler) RecordCost(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordLandCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cost, err := h.Service.RecordCost(tenantID, userID, mux.Vars(r)["parcel_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cost)
+}
+
+// RecordPayment pays an acquisition cost
+func (h *LandBankHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordLandPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payment, err := h.Service.RecordPayment(tenantID, userID, mux.Vars(r)["cost_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, payment)
+}
+
+// CreateJDA records a joint development agreement on a parcel
+func (h *LandBankHandler) CreateJDA(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateJDARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	jda, err := h.Service.CreateJDA(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, jda)
+}
+
+// ListJDAs lists JDAs for ?project_id=
+func (h *LandBankHandler) ListJDAs(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	jdas, err := h.Service.ListJDAs(tenantID, r.URL.Query().Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jdas)
+}
+
+// GetJDA returns a JDA with its landowners and unit allocations
+func (h *LandBankHandler) GetJDA(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	jda, err := h.Service.GetJDA(tenantID, mux.Vars(r)["jda_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jda)
+}
+
+// AllotUnit allots a project unit to a landowner
+func (h *LandBankHandler) AllotUnit(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.AllotLandownerUnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	allocation, err := h.Service.AllotUnit(tenantID, userID, mux.Vars(r)["jda_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, allocation)
+}
+
+// ReleaseUnit cancels a landowner allotment and returns the unit to inventory
+func (h *LandBankHandler) ReleaseUnit(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	if err := h.Service.ReleaseUnit(tenantID, userID, mux.Vars(r)["allocation_id"]); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Unit released"})
+}
+
+// ListAllocations lists a JDA's unit allocations for ?status=
+func (h *LandBankHandler) ListAllocations(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	allocations, err := h.Service.ListAllocations(tenantID, mux.Vars(r)["jda_id"], r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, allocations)
+}
+
+// GetAreaPosition returns each landowner's area entitlement against units allotted
+func (h *LandBankHandler) GetAreaPosition(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	position, err := h.Service.GetAreaPosition(tenantID, mux.Vars(r)["jda_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, position)
+}
+
+// PreviewPayouts computes the landowners' revenue share for a period without paying it
+func (h *LandBankHandler) PreviewPayouts(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.JDAPayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payouts, err := h.Service.PreviewPayouts(tenantID, mux.Vars(r)["jda_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payouts)
+}
+
+// RecordPayouts pays the landowners their revenue share for a period
+func (h *LandBankHandler) RecordPayouts(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.JDAPayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payouts, err := h.Service.RecordPayouts(tenantID, userID, mux.Vars(r)["jda_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, payouts)
+}
+
+// ListPayouts lists a JDA's revenue share payouts
+func (h *LandBankHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	payouts, err := h.Service.ListPayouts(tenantID, mux.Vars(r)["jda_id"])
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payouts)
+}
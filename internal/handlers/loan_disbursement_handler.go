@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// HOME LOAN DISBURSEMENT REQUEST HANDLERS
+// ============================================================================
+
+type LoanDisbursementHandler struct {
+	Service *services.LoanDisbursementService
+}
+
+func NewLoanDisbursementHandler(service *services.LoanDisbursementService) *LoanDisbursementHandler {
+	return &LoanDisbursementHandler{Service: service}
+}
+
+// SetFormat creates or updates a bank's disbursement request format
+func (h *LoanDisbursementHandler) SetFormat(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetLenderRequestFormatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	format, err := h.Service.SetFormat(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, format)
+}
+
+// GetFormat returns a bank's disbursement request format
+func (h *LoanDisbursementHandler) GetFormat(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	format, err := h.Service.GetFormat(tenantID, mux.Vars(r)["bank_id"])
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, format)
+}
+
+// RaiseRequest raises the disbursement request for a demand letter, e.g. after
+// the loan was sanctioned following the demand
+func (h *LoanDisbursementHandler) RaiseRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	request, err := h.Service.RequestForDemandLetter(tenantID, userID, mux.Vars(r)["letter_id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request == nil {
+		respondWithError(w, http.StatusBadRequest, "no sanctioned loan to draw on or a request already exists for this demand")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, request)
+}
+
+// ListRequests lists disbursement requests for ?status=&booking_id=&bank_id=
+func (h *LoanDisbursementHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	requests, err := h.Service.ListRequests(tenantID, q.Get("status"), q.Get("booking_id"), q.Get("bank_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
+
+// GetRequest returns a disbursement request with its letter and documents
+func (h *LoanDisbursementHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	request, err := h.Service.GetRequest(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// RecordConsent records the customer's consent to a disbursement request
+func (h *LoanDisbursementHandler) RecordConsent(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.LoanConsentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// booking_id is only in the path on the customer portal
+	vars := mux.Vars(r)
+	request, err := h.Service.RecordConsent(tenantID, vars["booking_id"], vars["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// AttachDocument attaches a document to a disbursement request
+func (h *LoanDisbursementHandler) AttachDocument(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.AttachLoanDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	request, err := h.Service.AttachDocument(tenantID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// SubmitRequest marks a request as sent to the lender
+func (h *LoanDisbursementHandler) SubmitRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	request, err := h.Service.SubmitRequest(tenantID, userID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// RejectRequest records the lender's rejection of a request
+func (h *LoanDisbursementHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.RejectLoanRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.Service.RejectRequest(tenantID, mux.Vars(r)["id"], &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"status": models.LoanRequestRejected})
+}
+
+// RecordIncoming records a credit received from a lender and matches it to a request
+func (h *LoanDisbursementHandler) RecordIncoming(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordIncomingDisbursementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	incoming, err := h.Service.RecordIncoming(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, incoming)
+}
+
+// ListIncoming lists credits from lenders for ?match_status=
+func (h *LoanDisbursementHandler) ListIncoming(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	incoming, err := h.Service.ListIncoming(tenantID, r.URL.Query().Get("match_status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, incoming)
+}
+
+// MatchIncoming matches an unmatched credit to a request by hand
+func (h *LoanDisbursementHandler) MatchIncoming(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.MatchIncomingDisbursementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	incoming, err := h.Service.MatchIncoming(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, incoming)
+}
+
+// GetFundingSplit returns a booking's loan-funded versus own-contribution split
+func (h *LoanDisbursementHandler) GetFundingSplit(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	split, err := h.Service.GetFundingSplit(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, split)
+}
+
+// ListCustomerRequests lists the disbursement requests on the customer's booking
+func (h *LoanDisbursementHandler) ListCustomerRequests(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	requests, err := h.Service.ListRequests(tenantID, "", mux.Vars(r)["booking_id"], "")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// MAINTENANCE BILLING & ASSOCIATION HANDOVER HANDLERS
+// ============================================================================
+
+type MaintenanceHandler struct {
+	Service *services.MaintenanceService
+}
+
+func NewMaintenanceHandler(service *services.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{Service: service}
+}
+
+// SetPlan creates or updates a project's maintenance tariff
+func (h *MaintenanceHandler) SetPlan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetMaintenancePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, err := h.Service.SetPlan(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// GetPlan returns a project's maintenance tariff
+func (h *MaintenanceHandler) GetPlan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	plan, err := h.Service.GetPlan(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// EnrollUnits opens maintenance accounts for the project's possessed units
+func (h *MaintenanceHandler) EnrollUnits(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	accounts, err := h.Service.EnrollUnits(tenantID, userID, mux.Vars(r)["project_id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, accounts)
+}
+
+// GenerateInvoices bills the project's unbilled maintenance periods
+func (h *MaintenanceHandler) GenerateInvoices(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.GenerateMaintenanceInvoicesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	invoices, err := h.Service.GenerateInvoices(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, invoices)
+}
+
+// ListInvoices lists maintenance invoices for ?project_id=&account_id=&status=
+func (h *MaintenanceHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	invoices, err := h.Service.ListInvoices(tenantID, q.Get("project_id"), q.Get("account_id"), q.Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoices)
+}
+
+// ListAccounts lists maintenance accounts for ?project_id=&status=
+func (h *MaintenanceHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	accounts, err := h.Service.ListAccounts(tenantID, q.Get("project_id"), q.Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, accounts)
+}
+
+// GetAccount returns a maintenance account with its invoices and receipts
+func (h *MaintenanceHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	account, err := h.Service.GetAccount(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, account)
+}
+
+// RecordReceipt records a maintenance payment against an account
+func (h *MaintenanceHandler) RecordReceipt(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordMaintenanceReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	receipt, err := h.Service.RecordReceipt(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, receipt)
+}
+
+// GetArrears returns overdue maintenance by unit for ?project_id=&as_of=YYYY-MM-DD
+func (h *MaintenanceHandler) GetArrears(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	projectID := q.Get("project_id")
+	if projectID == "" {
+		respondWithError(w, http.StatusBadRequest, "project_id is required")
+		return
+	}
+	asOf := time.Now()
+	if v := q.Get("as_of"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "as_of must be YYYY-MM-DD")
+			return
+		}
+		asOf = parsed
+	}
+
+	arrears, err := h.Service.GetArrears(tenantID, projectID, asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, arrears)
+}
+
+// GetClosingStatement previews the statement that would be handed to the association
+func (h *MaintenanceHandler) GetClosingStatement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	statement, err := h.Service.GetClosingStatement(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statement)
+}
+
+// HandOverToAssociation closes the project's maintenance and transfers the corpus
+func (h *MaintenanceHandler) HandOverToAssociation(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.AssociationHandoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	handover, err := h.Service.HandOverToAssociation(tenantID, userID, mux.Vars(r)["project_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, handover)
+}
+
+// GetHandover returns the project's association handover
+func (h *MaintenanceHandler) GetHandover(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	handover, err := h.Service.GetHandover(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, handover)
+}
+
+// ============================================================================
+// CUSTOMER PORTAL
+// ============================================================================
+
+// GetCustomerMaintenance returns the maintenance account of the customer's unit
+func (h *MaintenanceHandler) GetCustomerMaintenance(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	account, err := h.Service.GetBookingAccount(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, account)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// CHANNEL PARTNER PORTAL HANDLERS
+// ============================================================================
+// These handlers sit behind PartnerAuthMiddleware, so the tenant, partner and
+// partner user always come from the partner token, never from the request.
+
+type PartnerPortalHandler struct {
+	Service *services.PartnerPortalService
+}
+
+func NewPartnerPortalHandler(service *services.PartnerPortalService) *PartnerPortalHandler {
+	return &PartnerPortalHandler{Service: service}
+}
+
+// partnerContext returns the tenant, partner and partner user set by PartnerAuthMiddleware
+func partnerContext(r *http.Request) (tenantID, partnerID, userID string) {
+	tenantID, _ = r.Context().Value(middleware.TenantIDKey).(string)
+	partnerID, _ = r.Context().Value(middleware.PartnerIDKey).(string)
+	userID, _ = r.Context().Value(middleware.PartnerUserIDKey).(string)
+	return
+}
+
+// Login signs a partner user in and returns a partner token
+func (h *PartnerPortalHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.PartnerLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.Service.Login(r.Context(), &req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, session)
+}
+
+// GetProfile returns the signed-in partner with its lead and payout figures
+func (h *PartnerPortalHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, userID := partnerContext(r)
+	scopes, _ := r.Context().Value(middleware.PartnerScopesKey).([]string)
+
+	profile, err := h.Service.GetProfile(r.Context(), tenantID, partnerID, userID, scopes)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+// SubmitLead submits a lead for the partner
+func (h *PartnerPortalHandler) SubmitLead(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, userID := partnerContext(r)
+
+	var req models.SubmitPortalLeadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	lead, err := h.Service.SubmitLead(r.Context(), tenantID, partnerID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, lead)
+}
+
+// ListLeads lists the partner's leads with booking status for ?status=&limit=&offset=
+func (h *PartnerPortalHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, _ := partnerContext(r)
+
+	leads, total, err := h.Service.ListLeads(r.Context(), tenantID, partnerID, r.URL.Query().Get("status"), getLimitFromQuery(r), getOffsetFromQuery(r))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSONPaginated(w, http.StatusOK, leads, total)
+}
+
+// GetLead returns one of the partner's leads with its booking status
+func (h *PartnerPortalHandler) GetLead(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, _ := partnerContext(r)
+
+	lead, err := h.Service.GetLead(r.Context(), tenantID, partnerID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lead)
+}
+
+// ListPayouts lists the partner's payouts
+func (h *PartnerPortalHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, _ := partnerContext(r)
+
+	payouts, err := h.Service.ListPayouts(r.Context(), tenantID, partnerID, getLimitFromQuery(r), getOffsetFromQuery(r))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payouts)
+}
+
+// GetPayoutStatement returns a payout statement, as a CSV download with ?format=csv
+func (h *PartnerPortalHandler) GetPayoutStatement(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, _ := partnerContext(r)
+
+	statement, err := h.Service.GetPayoutStatement(r.Context(), tenantID, partnerID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		file, err := h.Service.ExportPayoutStatementCSV(statement)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=payout_statement_%s.csv", statement.Payout.ID))
+		w.WriteHeader(http.StatusOK)
+		w.Write(file)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statement)
+}
+
+// UploadInvoice records an invoice the partner raised against a payout
+func (h *PartnerPortalHandler) UploadInvoice(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, userID := partnerContext(r)
+
+	var req models.UploadPartnerInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	invoice, err := h.Service.UploadInvoice(r.Context(), tenantID, partnerID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, invoice)
+}
+
+// ListInvoices lists the partner's invoices for ?payout_id=
+func (h *PartnerPortalHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	tenantID, partnerID, _ := partnerContext(r)
+
+	invoices, err := h.Service.ListInvoices(r.Context(), tenantID, partnerID, r.URL.Query().Get("payout_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoices)
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// ACCOUNTS PAYABLE HANDLERS
+// ============================================================================
+
+type PayablesHandler struct {
+	Service   *services.PurchaseService
+	GLService *services.GLService
+}
+
+func NewPayablesHandler(service *services.PurchaseService, glService *services.GLService) *PayablesHandler {
+	return &PayablesHandler{Service: service, GLService: glService}
+}
+
+// GetAPAgeingReport returns AP ageing grouped by vendor or project
+// Query params: project_id, group_by (vendor|project), as_of (YYYY-MM-DD)
+func (h *PayablesHandler) GetAPAgeingReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.Service.GetAPAgeingReport(tenantID, r.URL.Query().Get("project_id"), r.URL.Query().Get("group_by"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// GetOpenPayables returns the open invoices behind the ageing report
+// Query params: vendor_id, project_id, as_of (YYYY-MM-DD)
+func (h *PayablesHandler) GetOpenPayables(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
+	payables, err := h.Service.GetOpenPayables(tenantID, r.URL.Query().Get("vendor_id"), r.URL.Query().Get("project_id"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payables)
+}
+
+// UpsertVendorBankAccount sets a vendor's primary bank account
+func (h *PayablesHandler) UpsertVendorBankAccount(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	vendorID := mux.Vars(r)["vendor_id"]
+
+	var req models.UpsertVendorBankAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.AccountName == "" || req.AccountNumber == "" || req.IFSCCode == "" {
+		respondWithError(w, http.StatusBadRequest, "account_name, account_number and ifsc_code are required")
+		return
+	}
+
+	account, err := h.Service.UpsertVendorBankAccount(tenantID, vendorID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, account)
+}
+
+// SetVendorTDSSection sets the TDS section deducted on a vendor's payments
+func (h *PayablesHandler) SetVendorTDSSection(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	vendorID := mux.Vars(r)["vendor_id"]
+
+	var req models.SetVendorTDSSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.SectionCode == "" {
+		respondWithError(w, http.StatusBadRequest, "section_code is required")
+		return
+	}
+
+	section, err := h.Service.SetVendorTDSSection(tenantID, vendorID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, section)
+}
+
+// CreateBankPaymentFormat configures a bank's bulk payment file layout
+func (h *PayablesHandler) CreateBankPaymentFormat(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.CreateBankPaymentFormatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.BankCode == "" || req.FormatName == "" || req.DebitAccountNumber == "" {
+		respondWithError(w, http.StatusBadRequest, "bank_code, format_name and debit_account_number are required")
+		return
+	}
+
+	format, err := h.Service.CreateBankPaymentFormat(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, format)
+}
+
+// ListBankPaymentFormats returns the configured bank layouts
+func (h *PayablesHandler) ListBankPaymentFormats(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	formats, err := h.Service.ListBankPaymentFormats(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, formats)
+}
+
+// CreatePaymentProposal proposes a payment run from open invoices
+func (h *PayablesHandler) CreatePaymentProposal(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreatePaymentProposalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.BankFormatID == "" {
+		respondWithError(w, http.StatusBadRequest, "bank_format_id is required")
+		return
+	}
+
+	run, err := h.Service.CreatePaymentProposal(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, run)
+}
+
+// ListPaymentRuns lists payment runs
+// Query params: status
+func (h *PayablesHandler) ListPaymentRuns(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	runs, err := h.Service.ListPaymentRuns(tenantID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, runs)
+}
+
+// GetPaymentRun returns a payment run with its lines
+func (h *PayablesHandler) GetPaymentRun(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	run, err := h.Service.GetPaymentRun(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// ApprovePaymentRun approves or rejects a proposed run
+func (h *PayablesHandler) ApprovePaymentRun(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.ApprovePaymentRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	run, err := h.Service.ApprovePaymentRun(tenantID, mux.Vars(r)["id"], userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// ProcessPaymentRun pays an approved run, posts it to GL and generates the bank file
+func (h *PayablesHandler) ProcessPaymentRun(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	run, err := h.Service.ProcessPaymentRun(tenantID, mux.Vars(r)["id"], userID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// DownloadBankFile returns the bank bulk-payment file of a processed run
+func (h *PayablesHandler) DownloadBankFile(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	fileName, content, err := h.Service.GetPaymentRunBankFile(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+fileName+"\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(content))
+}
+
+// ============================================================================
+// THREE-WAY MATCH HANDLERS
+// ============================================================================
+
+// CreatePurchaseOrder creates a purchase order with the lines invoices are matched against
+func (h *PayablesHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var po models.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if po.VendorID == "" || len(po.LineItems) == 0 {
+		respondWithError(w, http.StatusBadRequest, "vendor_id and line_items are required")
+		return
+	}
+
+	po.ID = uuid.New().String()
+	po.PONumber = fmt.Sprintf("PO-%s-%s", time.Now().Format("20060102"), po.ID[:8])
+	po.Status = "Draft"
+	po.CreatedBy = userID
+	if po.PODate.IsZero() {
+		po.PODate = time.Now()
+	}
+
+	if err := h.Service.CreatePurchaseOrder(tenantID, &po); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, po)
+}
+
+// CreateGoodsReceipt records goods received against PO lines
+func (h *PayablesHandler) CreateGoodsReceipt(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var grn models.GoodsReceipt
+	if err := json.NewDecoder(r.Body).Decode(&grn); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if grn.POID == "" {
+		respondWithError(w, http.StatusBadRequest, "po_id is required")
+		return
+	}
+	if grn.ReceivedBy == "" {
+		grn.ReceivedBy = userID
+	}
+
+	if err := h.Service.CreateGoodsReceipt(tenantID, &grn); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, grn)
+}
+
+// CreateVendorInvoice records a vendor invoice with its lines and matches it immediately
+func (h *PayablesHandler) CreateVendorInvoice(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var invoice models.VendorInvoice
+	if err := json.NewDecoder(r.Body).Decode(&invoice); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if invoice.InvoiceNumber == "" || invoice.VendorID == "" {
+		respondWithError(w, http.StatusBadRequest, "invoice_number and vendor_id are required")
+		return
+	}
+
+	invoice.ID = uuid.New().String()
+	invoice.Status = "Received"
+	invoice.CreatedBy = userID
+	if invoice.TotalPayable == 0 {
+		invoice.TotalPayable = invoice.InvoiceAmount - invoice.DiscountAmount + invoice.TaxAmount
+	}
+
+	if err := h.Service.CreateVendorInvoice(tenantID, &invoice); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result, err := h.Service.MatchVendorInvoice(tenantID, invoice.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"invoice": invoice,
+		"match":   result,
+	})
+}
+
+// MatchVendorInvoice re-runs the three-way match, e.g. after a further goods receipt
+func (h *PayablesHandler) MatchVendorInvoice(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	result, err := h.Service.MatchVendorInvoice(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// PostInvoiceToGL posts a matched invoice to the general ledger
+func (h *PayablesHandler) PostInvoiceToGL(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	entryID, err := h.Service.PostInvoiceToGL(tenantID, mux.Vars(r)["id"], h.GLService, userID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"journal_entry_id": entryID})
+}
+
+// ListMatchExceptions returns the match exceptions queue
+// Query params: status (open|accepted|rejected|superseded)
+func (h *PayablesHandler) ListMatchExceptions(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	exceptions, err := h.Service.ListMatchExceptions(tenantID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, exceptions)
+}
+
+// ResolveMatchException accepts or rejects a match exception
+func (h *PayablesHandler) ResolveMatchException(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.ResolveMatchExceptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Notes == "" {
+		respondWithError(w, http.StatusBadRequest, "notes are required")
+		return
+	}
+
+	result, err := h.Service.ResolveMatchException(tenantID, mux.Vars(r)["id"], userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// GetMatchTolerance returns the tenant's match tolerances
+func (h *PayablesHandler) GetMatchTolerance(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	tolerance, err := h.Service.GetMatchTolerance(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tolerance)
+}
+
+// UpsertMatchTolerance sets the tenant's match tolerances
+func (h *PayablesHandler) UpsertMatchTolerance(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.UpsertMatchToleranceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tolerance, err := h.Service.UpsertMatchTolerance(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tolerance)
+}
+
+// ListVendorBackCharges lists back-charges set off against vendor invoices
+// Query params: vendor_id, invoice_id, snag_id
+func (h *PayablesHandler) ListVendorBackCharges(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	charges, err := h.Service.ListVendorBackCharges(tenantID, q.Get("vendor_id"), q.Get("invoice_id"), q.Get("snag_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, charges)
+}
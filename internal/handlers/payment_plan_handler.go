@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// PAYMENT PLAN HANDLERS
+// ============================================================================
+
+type PaymentPlanHandler struct {
+	Service *services.PaymentPlanService
+}
+
+func NewPaymentPlanHandler(service *services.PaymentPlanService) *PaymentPlanHandler {
+	return &PaymentPlanHandler{Service: service}
+}
+
+// CreateTemplate creates a payment plan template for a project
+func (h *PaymentPlanHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreatePaymentPlanTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tpl, err := h.Service.CreateTemplate(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, tpl)
+}
+
+// ListTemplates lists payment plan templates for ?project_id=
+func (h *PaymentPlanHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	templates, err := h.Service.ListTemplates(tenantID, r.URL.Query().Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, templates)
+}
+
+// GetTemplate returns a payment plan template with its stages
+func (h *PaymentPlanHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	tpl, err := h.Service.GetTemplate(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tpl)
+}
+
+// AssignPlan applies a payment plan template to a booking
+func (h *PaymentPlanHandler) AssignPlan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.AssignPaymentPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, err := h.Service.AssignPlan(r.Context(), tenantID, userID, mux.Vars(r)["booking_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, plan)
+}
+
+// GetBookingPlan returns a booking's payment plan stages and demand letters
+func (h *PaymentPlanHandler) GetBookingPlan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	plan, err := h.Service.GetBookingPlan(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// CreateMilestones adds construction milestones to a tower
+func (h *PaymentPlanHandler) CreateMilestones(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.CreateTowerMilestonesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	milestones, err := h.Service.CreateTowerMilestones(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, milestones)
+}
+
+// ListMilestones lists tower milestones for ?project_id=&block_id=
+func (h *PaymentPlanHandler) ListMilestones(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	if q.Get("project_id") == "" {
+		respondWithError(w, http.StatusBadRequest, "project_id is required")
+		return
+	}
+
+	milestones, err := h.Service.ListTowerMilestones(tenantID, q.Get("project_id"), q.Get("block_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, milestones)
+}
+
+// CompleteMilestone marks a tower milestone complete and raises demand letters for the tower
+func (h *PaymentPlanHandler) CompleteMilestone(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CompleteMilestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	run, err := h.Service.CompleteMilestone(r.Context(), tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// RaiseMilestoneDemands raises the demands still pending on a completed milestone
+func (h *PaymentPlanHandler) RaiseMilestoneDemands(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RaiseMilestoneDemandsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	run, err := h.Service.RaiseMilestoneDemands(r.Context(), tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// ListDemandLetters lists demand letters for ?booking_id=&milestone_id=
+func (h *PaymentPlanHandler) ListDemandLetters(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	letters, err := h.Service.ListDemandLetters(tenantID, q.Get("booking_id"), q.Get("milestone_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, letters)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// PRICE LIST HANDLERS
+// ============================================================================
+
+type PriceListHandler struct {
+	Service *services.PriceListService
+}
+
+func NewPriceListHandler(service *services.PriceListService) *PriceListHandler {
+	return &PriceListHandler{Service: service}
+}
+
+// CreatePriceList creates the next draft version of a price list
+func (h *PriceListHandler) CreatePriceList(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pl, err := h.Service.CreatePriceList(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, pl)
+}
+
+// ListPriceLists lists price list versions for ?project_id=&block_id=
+func (h *PriceListHandler) ListPriceLists(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	if q.Get("project_id") == "" {
+		respondWithError(w, http.StatusBadRequest, "project_id is required")
+		return
+	}
+
+	lists, err := h.Service.ListPriceLists(tenantID, q.Get("project_id"), q.Get("block_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lists)
+}
+
+// GetPriceList returns a price list version with its rules
+func (h *PriceListHandler) GetPriceList(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	pl, err := h.Service.GetPriceList(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pl)
+}
+
+// PublishPriceList publishes a draft price list from its effective date
+func (h *PriceListHandler) PublishPriceList(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.PublishPriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pl, err := h.Service.PublishPriceList(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pl)
+}
+
+// GetCostSheet prices a unit for ?as_of=&parking_type=&parking_slots=&optional=A,B&guideline_value=
+func (h *PriceListHandler) GetCostSheet(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date")
+		return
+	}
+	opts := &models.CostSheetOptions{ParkingType: q.Get("parking_type")}
+	if v := q.Get("parking_slots"); v != "" {
+		if opts.ParkingSlots, err = strconv.Atoi(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid parking_slots")
+			return
+		}
+	}
+	if v := q.Get("guideline_value"); v != "" {
+		if opts.GuidelineValue, err = strconv.ParseFloat(v, 64); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid guideline_value")
+			return
+		}
+	}
+	if v := q.Get("optional"); v != "" {
+		opts.OptionalCharges = strings.Split(v, ",")
+	}
+
+	sheet, err := h.Service.GetCostSheet(tenantID, mux.Vars(r)["unit_id"], asOf, opts)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sheet)
+}
+
+// UpdateUnitPricingAttributes sets a unit's view and corner flags
+func (h *PriceListHandler) UpdateUnitPricingAttributes(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.UpdateUnitPricingAttributesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attrs, err := h.Service.UpdateUnitPricingAttributes(tenantID, mux.Vars(r)["unit_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, attrs)
+}
+
+// LockBookingPrice freezes the cost sheet a booking was sold at
+func (h *PriceListHandler) LockBookingPrice(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.LockBookingPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	lock, err := h.Service.LockBookingPrice(tenantID, userID, mux.Vars(r)["booking_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, lock)
+}
+
+// GetBookingPriceLock returns the cost sheet frozen on a booking
+func (h *PriceListHandler) GetBookingPriceLock(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	lock, err := h.Service.GetBookingPriceLock(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lock)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,15 +16,21 @@ import (
 
 // RealEstateHandler handles all real estate related operations
 type RealEstateHandler struct {
-	DB          *sql.DB
-	RBACService *services.RBACService
+	DB           *sql.DB
+	RBACService  *services.RBACService
+	Availability *services.UnitAvailabilityService
+	Escrow       *services.RERAComplianceService
+	PriceLists   *services.PriceListService
 }
 
 // NewRealEstateHandler creates a new real estate handler
-func NewRealEstateHandler(db *sql.DB, rbacService *services.RBACService) *RealEstateHandler {
+func NewRealEstateHandler(db *sql.DB, rbacService *services.RBACService, availability *services.UnitAvailabilityService, escrow *services.RERAComplianceService, priceLists *services.PriceListService) *RealEstateHandler {
 	return &RealEstateHandler{
-		DB:          db,
-		RBACService: rbacService,
+		DB:           db,
+		RBACService:  rbacService,
+		Availability: availability,
+		Escrow:       escrow,
+		PriceLists:   priceLists,
 	}
 }
 
@@ -249,13 +256,28 @@ func (h *RealEstateHandler) CreateBooking(w http.ResponseWriter, r *http.Request
 	h.DB.QueryRow("SELECT COUNT(*) FROM customer_bookings WHERE tenant_id = $1", tenantID).Scan(&count)
 	booking.BookingReference = fmt.Sprintf("BKG-%s-%d", tenantID, count+1)
 
+	tx, err := h.DB.Begin()
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to create booking")
+		return
+	}
+	defer tx.Rollback()
+
+	// Only an available unit can be booked; units held, blocked or allotted to a
+	// landowner under a JDA are not sales inventory
+	fromStatus, err := h.Availability.BookUnitTx(tx, tenantID, booking.UnitID)
+	if err != nil {
+		h.respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
 	query := `INSERT INTO customer_bookings 
 		(tenant_id, unit_id, customer_id, booking_date, booking_reference, booking_status,
 		 rate_per_sqft, composite_guideline_value, car_parking_type, parking_location)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at, updated_at`
 
-	err := h.DB.QueryRow(query,
+	err = tx.QueryRow(query,
 		booking.TenantID, booking.UnitID, booking.CustomerID, booking.BookingDate,
 		booking.BookingReference, booking.BookingStatus, booking.RatePerSqft,
 		booking.CompositeGuidelineValue, booking.CarParkingType, booking.ParkingLocation,
@@ -266,8 +288,33 @@ func (h *RealEstateHandler) CreateBooking(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Update unit status
-	h.DB.Exec("UPDATE property_units SET status = $1 WHERE id = $2", "booked", booking.UnitID)
+	// Freeze the price the unit was sold at with the booking, consuming the approved
+	// discount that covers a rate below the cost sheet. Only a project without a
+	// published price list is booked without a lock.
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	lockReq := &models.LockBookingPriceRequest{
+		CostSheetOptions: models.CostSheetOptions{
+			ParkingType:    booking.CarParkingType,
+			GuidelineValue: booking.CompositeGuidelineValue,
+		},
+		DiscountRequestID: req.DiscountRequestID,
+	}
+	if _, err := h.PriceLists.LockBookingPriceTx(tx, tenantID, userID, booking.ID, lockReq); err != nil {
+		if !errors.Is(err, services.ErrNoPriceList) || req.DiscountRequestID != "" {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to create booking")
+		return
+	}
+
+	if _, err := h.Availability.UnitStatusChanged(tenantID, userID, booking.UnitID, fromStatus,
+		models.UnitStatusBooked, "Booking "+booking.BookingReference); err != nil {
+		log.Printf("Unit %s booked but its status change was not published: %v", booking.UnitID, err)
+	}
 
 	h.respondJSON(w, http.StatusCreated, booking)
 }
@@ -341,7 +388,14 @@ func (h *RealEstateHandler) RecordPayment(w http.ResponseWriter, r *http.Request
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at, updated_at`
 
-	err := h.DB.QueryRow(query,
+	tx, err := h.DB.Begin()
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to record payment")
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(query,
 		payment.TenantID, payment.BookingID, payment.PaymentDate, payment.PaymentMode,
 		payment.PaidBy, payment.ReceiptNumber, payment.Towards, payment.Amount,
 		payment.BankName, payment.TransactionID, payment.Status, payment.Remarks,
@@ -353,7 +407,23 @@ func (h *RealEstateHandler) RecordPayment(w http.ResponseWriter, r *http.Request
 	}
 
 	// Create ledger entry
-	h.createLedgerEntry(payment.BookingID, "credit", fmt.Sprintf("Payment received: %s", payment.Towards), payment.Amount)
+	if err := h.createLedgerEntry(tx, payment.BookingID, "credit", fmt.Sprintf("Payment received: %s", payment.Towards), payment.Amount); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to record payment")
+		return
+	}
+
+	// Route the RERA share of the receipt to the project's designated account
+	if h.Escrow != nil {
+		if _, err := h.Escrow.SplitBookingReceiptTx(tx, tenantID, payment.BookingID, payment.ID, payment.Amount); err != nil {
+			h.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to record payment")
+		return
+	}
 
 	h.respondJSON(w, http.StatusCreated, payment)
 }
@@ -365,7 +435,8 @@ func (h *RealEstateHandler) GetPayments(w http.ResponseWriter, r *http.Request)
 
 	query := `SELECT id, tenant_id, booking_id, payment_date, payment_mode, paid_by,
 		receipt_number, receipt_date, towards, amount, cheque_number, cheque_date,
-		bank_name, transaction_id, status, remarks, created_at, updated_at, deleted_at
+		bank_name, transaction_id, status, remarks, created_at, updated_at, deleted_at,
+		COALESCE((SELECT SUM(t.tds_amount) FROM booking_payment_tds t WHERE t.payment_id = booking_payments.id), 0)
 		FROM booking_payments WHERE tenant_id = $1 AND booking_id = $2 AND deleted_at IS NULL
 		ORDER BY payment_date DESC`
 
@@ -382,7 +453,7 @@ func (h *RealEstateHandler) GetPayments(w http.ResponseWriter, r *http.Request)
 		if err := rows.Scan(&p.ID, &p.TenantID, &p.BookingID, &p.PaymentDate, &p.PaymentMode,
 			&p.PaidBy, &p.ReceiptNumber, &p.ReceiptDate, &p.Towards, &p.Amount,
 			&p.ChequeNumber, &p.ChequeDate, &p.BankName, &p.TransactionID, &p.Status,
-			&p.Remarks, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt); err != nil {
+			&p.Remarks, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.TDSDeducted); err != nil {
 			continue
 		}
 		payments = append(payments, p)
@@ -516,12 +587,12 @@ func (h *RealEstateHandler) GetAccountLedger(w http.ResponseWriter, r *http.Requ
 // HELPER FUNCTIONS
 // ============================================
 
-func (h *RealEstateHandler) createLedgerEntry(bookingID string, txnType string, description string, amount float64) error {
+func (h *RealEstateHandler) createLedgerEntry(tx *sql.Tx, bookingID string, txnType string, description string, amount float64) error {
 	var openingBalance float64
 	var closingBalance float64
 
 	// Get last balance
-	h.DB.QueryRow(`SELECT COALESCE(closing_balance, 0) FROM customer_account_ledgers 
+	tx.QueryRow(`SELECT COALESCE(closing_balance, 0) FROM customer_account_ledgers 
 		WHERE booking_id = $1 ORDER BY transaction_date DESC LIMIT 1`, bookingID).Scan(&openingBalance)
 
 	if txnType == "credit" {
@@ -543,7 +614,7 @@ func (h *RealEstateHandler) createLedgerEntry(bookingID string, txnType string,
 		debit = amount
 	}
 
-	_, err := h.DB.Exec(query, "", bookingID, txnType, description, debit, credit, openingBalance, closingBalance)
+	_, err := tx.Exec(query, "", bookingID, txnType, description, debit, credit, openingBalance, closingBalance)
 	return err
 }
 
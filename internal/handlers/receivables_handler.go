@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// RECEIVABLES HANDLERS
+// ============================================================================
+
+type ReceivablesHandler struct {
+	Service *services.ReceivablesService
+}
+
+func NewReceivablesHandler(service *services.ReceivablesService) *ReceivablesHandler {
+	return &ReceivablesHandler{Service: service}
+}
+
+// GetAgeingReport returns AR ageing grouped by project, tower or customer
+// Query params: project_id, group_by (project|tower|customer), as_of (YYYY-MM-DD)
+func (h *ReceivablesHandler) GetAgeingReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.Service.GetAgeingReport(tenantID, r.URL.Query().Get("project_id"), r.URL.Query().Get("group_by"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// GetOutstandingItems returns the open installments behind the ageing report
+func (h *ReceivablesHandler) GetOutstandingItems(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+		return
+	}
+
+	items, err := h.Service.GetOutstandingItems(tenantID, r.URL.Query().Get("project_id"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, items)
+}
+
+// RunDunning runs the dunning process for overdue bookings
+func (h *ReceivablesHandler) RunDunning(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.DunningRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	run, err := h.Service.RunDunning(r.Context(), tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// ListDunningNotices lists dunning notices sent for a booking
+func (h *ReceivablesHandler) ListDunningNotices(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	bookingID := mux.Vars(r)["booking_id"]
+
+	notices, err := h.Service.ListDunningNotices(tenantID, bookingID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, notices)
+}
+
+// parseAsOfDate reads the optional as_of query param, defaulting to now
+func parseAsOfDate(r *http.Request) (time.Time, error) {
+	asOfStr := r.URL.Query().Get("as_of")
+	if asOfStr == "" {
+		return time.Now(), nil
+	}
+	return time.Parse("2006-01-02", asOfStr)
+}
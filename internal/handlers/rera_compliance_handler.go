@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"vyomtech-backend/internal/middleware"
@@ -211,6 +212,273 @@ func (h *RERAComplianceHandler) PerformMonthlyReconciliation(w http.ResponseWrit
 	json.NewEncoder(w).Encode(reconciliation)
 }
 
+// SetEscrowPolicy designates a project's RERA and free collection accounts
+func (h *RERAComplianceHandler) SetEscrowPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetRERAEscrowPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.Service.SetEscrowPolicy(tenantID, mux.Vars(r)["project_id"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// GetEscrowPosition returns the designated account position and withdrawal headroom
+func (h *RERAComplianceHandler) GetEscrowPosition(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	position, err := h.Service.GetEscrowPosition(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(position)
+}
+
+// ListReceiptSplits lists how receipts were split between the RERA and free accounts
+func (h *RERAComplianceHandler) ListReceiptSplits(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	splits, err := h.Service.ListReceiptSplits(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(splits)
+}
+
+// CertifyCompletion records a certified percentage of completion
+func (h *RERAComplianceHandler) CertifyCompletion(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CertifyCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cert, err := h.Service.CertifyCompletion(tenantID, userID, mux.Vars(r)["project_id"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cert)
+}
+
+// ListCertificates lists a project's completion certificates
+func (h *RERAComplianceHandler) ListCertificates(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	certs, err := h.Service.ListCertificates(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(certs)
+}
+
+// RequestWithdrawal withdraws from the RERA account. A withdrawal over the allowable
+// limit is entered in the register as blocked and returned with 422.
+func (h *RERAComplianceHandler) RequestWithdrawal(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RERAWithdrawalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	withdrawal, err := h.Service.RequestWithdrawal(tenantID, userID, mux.Vars(r)["project_id"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := http.StatusCreated
+	if withdrawal.Status == models.RERAWithdrawalBlocked {
+		status = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(withdrawal)
+}
+
+// ListWithdrawals returns the withdrawal register, optionally ?status=approved|blocked
+func (h *RERAComplianceHandler) ListWithdrawals(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	register, err := h.Service.ListWithdrawals(tenantID, mux.Vars(r)["project_id"], r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(register)
+}
+
+// GenerateQPR builds or rebuilds the draft QPR of a project for a quarter
+func (h *RERAComplianceHandler) GenerateQPR(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.GenerateQPRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.Service.GenerateQPR(tenantID, userID, mux.Vars(r)["project_id"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ListQPRs lists a project's QPRs
+func (h *RERAComplianceHandler) ListQPRs(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	reports, err := h.Service.ListQPRs(tenantID, mux.Vars(r)["project_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// GetQPR returns a QPR with its validation findings
+func (h *RERAComplianceHandler) GetQPR(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	report, err := h.Service.GetQPR(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// SubmitQPR locks a QPR as filed on the state portal
+func (h *RERAComplianceHandler) SubmitQPR(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.SubmitQPRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.Service.SubmitQPR(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ExportQPR downloads a QPR in its state's format, ?format=excel (default) or pdf
+func (h *RERAComplianceHandler) ExportQPR(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "excel"
+	}
+	data, filename, err := h.Service.ExportQPR(tenantID, mux.Vars(r)["id"], format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	if format == "pdf" {
+		contentType = "application/pdf"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(data)
+}
+
+// ListQPRTemplates lists the state QPR layouts available to the tenant
+func (h *RERAComplianceHandler) ListQPRTemplates(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	templates, err := h.Service.ListQPRTemplates(tenantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// GetQPRTemplate returns a state's QPR layout
+func (h *RERAComplianceHandler) GetQPRTemplate(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	tpl, err := h.Service.GetQPRTemplate(tenantID, mux.Vars(r)["state_code"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tpl)
+}
+
+// SetQPRTemplate configures a state's QPR layout for the tenant
+func (h *RERAComplianceHandler) SetQPRTemplate(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetQPRTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tpl, err := h.Service.SetQPRTemplate(tenantID, mux.Vars(r)["state_code"], &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tpl)
+}
+
 // RegisterRERARoutes registers all RERA compliance routes
 func RegisterRERARoutes(router *mux.Router, handler *RERAComplianceHandler) {
 	rera := router.PathPrefix("/api/v1/rera-compliance").Subrouter()
@@ -230,4 +498,23 @@ func RegisterRERARoutes(router *mux.Router, handler *RERAComplianceHandler) {
 
 	// Reconciliation
 	rera.HandleFunc("/monthly-reconciliation", handler.PerformMonthlyReconciliation).Methods("POST")
+
+	// Designated (70%) account enforcement
+	rera.HandleFunc("/escrow/{project_id}", handler.GetEscrowPosition).Methods("GET")
+	rera.HandleFunc("/escrow/{project_id}/policy", handler.SetEscrowPolicy).Methods("PUT")
+	rera.HandleFunc("/escrow/{project_id}/splits", handler.ListReceiptSplits).Methods("GET")
+	rera.HandleFunc("/escrow/{project_id}/certificates", handler.CertifyCompletion).Methods("POST")
+	rera.HandleFunc("/escrow/{project_id}/certificates", handler.ListCertificates).Methods("GET")
+	rera.HandleFunc("/escrow/{project_id}/withdrawals", handler.RequestWithdrawal).Methods("POST")
+	rera.HandleFunc("/escrow/{project_id}/withdrawals", handler.ListWithdrawals).Methods("GET")
+
+	// Quarterly progress reports
+	rera.HandleFunc("/qpr/projects/{project_id}", handler.GenerateQPR).Methods("POST")
+	rera.HandleFunc("/qpr/projects/{project_id}", handler.ListQPRs).Methods("GET")
+	rera.HandleFunc("/qpr/{id}", handler.GetQPR).Methods("GET")
+	rera.HandleFunc("/qpr/{id}/submit", handler.SubmitQPR).Methods("POST")
+	rera.HandleFunc("/qpr/{id}/export", handler.ExportQPR).Methods("GET")
+	rera.HandleFunc("/qpr-templates", handler.ListQPRTemplates).Methods("GET")
+	rera.HandleFunc("/qpr-templates/{state_code}", handler.GetQPRTemplate).Methods("GET")
+	rera.HandleFunc("/qpr-templates/{state_code}", handler.SetQPRTemplate).Methods("PUT")
 }
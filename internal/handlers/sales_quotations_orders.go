@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	"vyomtech-backend/internal/middleware"
 	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -262,12 +264,14 @@ func (h *SalesHandler) CreateSalesOrder(w http.ResponseWriter, r *http.Request)
 
 	var req struct {
 		CustomerID           string                   `json:"customer_id"`
+		QuotationID          *string                  `json:"quotation_id"`
 		OrderDate            string                   `json:"order_date"`
 		RequiredByDate       *string                  `json:"required_by_date"`
 		DeliveryLocation     string                   `json:"delivery_location"`
 		DeliveryInstructions *string                  `json:"delivery_instructions"`
 		Items                []map[string]interface{} `json:"items"`
 		DiscountAmount       float64                  `json:"discount_amount"`
+		DiscountRequestID    *string                  `json:"discount_request_id"`
 		Notes                *string                  `json:"notes"`
 	}
 
@@ -305,17 +309,57 @@ func (h *SalesHandler) CreateSalesOrder(w http.ResponseWriter, r *http.Request)
 
 	totalAmount := subtotal - req.DiscountAmount + totalTax
 
+	// A discount needs an approved request, either named or carried over from the
+	// quotation, unless it is within the creator's own limit. Line discounts and
+	// lines priced below the quotation count towards it.
+	discountService := services.NewDiscountApprovalService(h.DB)
+	role, _ := r.Context().Value(middleware.RoleKey).(string)
+	var quotationID, discountRequestID string
+	if req.QuotationID != nil {
+		quotationID = *req.QuotationID
+	}
+	if req.DiscountRequestID != nil {
+		discountRequestID = *req.DiscountRequestID
+	}
+	lines := make([]services.SalesOrderLine, 0, len(req.Items))
+	for idx, item := range req.Items {
+		line := services.SalesOrderLine{LineNumber: idx + 1}
+		line.ProductCode, _ = item["product_service_code"].(string)
+		line.Quantity, _ = item["quantity"].(float64)
+		line.UnitPrice, _ = item["unit_price"].(float64)
+		line.DiscountPercent, _ = item["discount_percent"].(float64)
+		line.DiscountAmount, _ = item["discount_amount"].(float64)
+		lines = append(lines, line)
+	}
+	orderDiscount, err := discountService.OrderDiscount(tenantID, quotationID, req.CustomerID, req.DiscountAmount, lines)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	approvedDiscountID, err := discountService.AuthorizeOrderDiscount(tenantID, role, quotationID, discountRequestID, orderDiscount)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tx, err := h.DB.Begin()
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to create order")
+		return
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO sales_orders (
-			id, tenant_id, order_number, customer_id, order_date, required_by_date,
+			id, tenant_id, order_number, customer_id, quotation_id, order_date, required_by_date,
 			delivery_location, delivery_instructions, subtotal_amount, discount_amount,
 			tax_amount, total_amount, invoiced_amount, pending_amount, status,
 			notes, created_by, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := h.DB.Exec(query,
-		orderID, tenantID, orderNumber, req.CustomerID, orderDate, requiredByDate,
+	_, err = tx.Exec(query,
+		orderID, tenantID, orderNumber, req.CustomerID, req.QuotationID, orderDate, requiredByDate,
 		req.DeliveryLocation, req.DeliveryInstructions, subtotal, req.DiscountAmount,
 		totalTax, totalAmount, 0.0, totalAmount, "draft",
 		req.Notes, &userID, now, now,
@@ -326,6 +370,27 @@ func (h *SalesHandler) CreateSalesOrder(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if approvedDiscountID != "" {
+		if err := discountService.MarkDiscountUsedTx(tx, tenantID, approvedDiscountID, "", orderID); err != nil {
+			h.respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	if req.QuotationID != nil {
+		res, err := tx.Exec(`UPDATE sales_quotations SET status = ?, converted_to_order = ?, sales_order_id = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ? AND customer_id = ? AND converted_to_order = false`,
+			"converted_to_order", true, orderID, now, *req.QuotationID, tenantID, req.CustomerID)
+		if err != nil {
+			h.respondError(w, http.StatusInternalServerError, "Failed to convert quotation")
+			return
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			h.respondError(w, http.StatusConflict, "Quotation has already been converted or belongs to another customer")
+			return
+		}
+	}
+
 	// Insert order items
 	itemQuery := `
 		INSERT INTO sales_order_items (
@@ -344,14 +409,22 @@ func (h *SalesHandler) CreateSalesOrder(w http.ResponseWriter, r *http.Request)
 		lineTotal := quantity * unitPrice
 		taxAmount := lineTotal * (taxRate / 100)
 
-		h.DB.Exec(itemQuery,
+		if _, err := tx.Exec(itemQuery,
 			itemID, tenantID, orderID, idx+1,
 			item["description"], item["product_service_code"],
 			quantity, 0.0, unitPrice, lineTotal,
 			item["discount_percent"], item["discount_amount"],
 			item["hsn_code"], taxRate, taxAmount,
 			now, now,
-		)
+		); err != nil {
+			h.respondError(w, http.StatusInternalServerError, "Failed to create order items")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to create order")
+		return
 	}
 
 	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// SNAG LIST & DEFECT LIABILITY HANDLERS
+// ============================================================================
+
+type SnagHandler struct {
+	Service *services.SnagService
+}
+
+func NewSnagHandler(service *services.SnagService) *SnagHandler {
+	return &SnagHandler{Service: service}
+}
+
+// CreateInspection records the joint handover inspection checklist
+func (h *SnagHandler) CreateInspection(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateHandoverInspectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	inspection, err := h.Service.CreateInspection(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, inspection)
+}
+
+// ListInspections lists handover inspections for ?booking_id=&project_id=
+func (h *SnagHandler) ListInspections(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	inspections, err := h.Service.ListInspections(tenantID, q.Get("booking_id"), q.Get("project_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, inspections)
+}
+
+// GetInspection returns a handover inspection with its checklist
+func (h *SnagHandler) GetInspection(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	inspection, err := h.Service.GetInspection(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, inspection)
+}
+
+// SignOffInspection completes the handover and starts the defect-liability period
+func (h *SnagHandler) SignOffInspection(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.SignOffHandoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	inspection, err := h.Service.SignOffInspection(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, inspection)
+}
+
+// ListDefectLiability lists units' defect-liability periods for ?project_id=&active=true
+func (h *SnagHandler) ListDefectLiability(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	periods, err := h.Service.ListDefectLiability(tenantID, q.Get("project_id"), q.Get("active") == "true")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, periods)
+}
+
+// GetUnitDefectLiability returns a unit's defect-liability period
+func (h *SnagHandler) GetUnitDefectLiability(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	period, err := h.Service.GetUnitDefectLiability(tenantID, mux.Vars(r)["unit_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, period)
+}
+
+// RaiseSnag reports a snag on a handed-over unit
+func (h *SnagHandler) RaiseSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RaiseSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	snag, err := h.Service.RaiseSnag(tenantID, userID, models.SnagSourceStaff, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, snag)
+}
+
+// ListSnags lists snags for ?project_id=&unit_id=&booking_id=&contractor_id=&status=&category=&overdue=true
+func (h *SnagHandler) ListSnags(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	snags, err := h.Service.ListSnags(tenantID, models.SnagFilter{
+		ProjectID:    q.Get("project_id"),
+		UnitID:       q.Get("unit_id"),
+		BookingID:    q.Get("booking_id"),
+		ContractorID: q.Get("contractor_id"),
+		Status:       q.Get("status"),
+		Category:     q.Get("category"),
+		OverdueOnly:  q.Get("overdue") == "true",
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snags)
+}
+
+// GetSnag returns a snag with its photos and back-charges
+func (h *SnagHandler) GetSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	snag, err := h.Service.GetSnag(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snag)
+}
+
+// AssignSnag assigns a snag to a contractor and starts its SLA
+func (h *SnagHandler) AssignSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.AssignSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	snag, err := h.Service.AssignSnag(tenantID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snag)
+}
+
+// ResolveSnag records the contractor's fix
+func (h *SnagHandler) ResolveSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.ResolveSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	snag, err := h.Service.ResolveSnag(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snag)
+}
+
+// ReviewSnag closes, reopens or rejects a snag
+func (h *SnagHandler) ReviewSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.ReviewSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// booking_id is only in the path on the customer portal
+	vars := mux.Vars(r)
+	snag, err := h.Service.ReviewSnag(tenantID, userID, vars["booking_id"], vars["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snag)
+}
+
+// AddSnagPhotos attaches photos to a snag
+func (h *SnagHandler) AddSnagPhotos(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.AddSnagPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+	snag, err := h.Service.AddSnagPhotos(tenantID, userID, vars["booking_id"], vars["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snag)
+}
+
+// BackChargeSnag recovers the cost of a snag from its contractor's open invoice
+func (h *SnagHandler) BackChargeSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.BackChargeSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	snag, err := h.Service.BackChargeSnag(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, snag)
+}
+
+// ============================================================================
+// CUSTOMER PORTAL
+// ============================================================================
+
+// RaiseCustomerSnag lets the customer raise a snag ticket on their booking
+func (h *SnagHandler) RaiseCustomerSnag(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RaiseSnagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.BookingID = mux.Vars(r)["booking_id"]
+
+	snag, err := h.Service.RaiseSnag(tenantID, userID, models.SnagSourcePortal, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, snag)
+}
+
+// ListCustomerSnags lists the snags on the customer's booking
+func (h *SnagHandler) ListCustomerSnags(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	snags, err := h.Service.ListSnags(tenantID, models.SnagFilter{
+		BookingID: mux.Vars(r)["booking_id"],
+		Status:    r.URL.Query().Get("status"),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, snags)
+}
+
+// GetCustomerDefectLiability returns the defect-liability period of the customer's unit
+func (h *SnagHandler) GetCustomerDefectLiability(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	period, err := h.Service.GetBookingDefectLiability(tenantID, mux.Vars(r)["booking_id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, period)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// SUBVENTION HANDLERS
+// ============================================================================
+
+type SubventionHandler struct {
+	Service *services.SubventionService
+}
+
+func NewSubventionHandler(service *services.SubventionService) *SubventionHandler {
+	return &SubventionHandler{Service: service}
+}
+
+// CreateAgreement puts a booking's loan under a subvention scheme
+func (h *SubventionHandler) CreateAgreement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateSubventionAgreementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	agreement, err := h.Service.CreateAgreement(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, agreement)
+}
+
+// ListAgreements lists agreements for ?status=&project_id=&booking_id=
+func (h *SubventionHandler) ListAgreements(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	agreements, err := h.Service.ListAgreements(tenantID, q.Get("status"), q.Get("project_id"), q.Get("booking_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, agreements)
+}
+
+// GetAgreement returns an agreement with its obligation schedule
+func (h *SubventionHandler) GetAgreement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	agreement, err := h.Service.GetAgreement(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, agreement)
+}
+
+// StopAgreement ends an agreement before the scheme end date
+func (h *SubventionHandler) StopAgreement(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.StopSubventionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	agreement, err := h.Service.StopAgreement(tenantID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, agreement)
+}
+
+// Provision accrues the months ended by the as-of date and posts the provision
+func (h *SubventionHandler) Provision(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.ProvisionSubventionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	run, err := h.Service.Provision(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// RecordPayment pays provisioned obligations to the lender
+func (h *SubventionHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordSubventionPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payment, err := h.Service.RecordPayment(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, payment)
+}
+
+// GetLiabilityReport returns the subvention liability by project and lender for ?project_id=&as_of=
+func (h *SubventionHandler) GetLiabilityReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	asOf := time.Now()
+	if v := q.Get("as_of"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "as_of must be YYYY-MM-DD")
+			return
+		}
+		asOf = parsed
+	}
+
+	report, err := h.Service.GetLiabilityReport(tenantID, q.Get("project_id"), asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
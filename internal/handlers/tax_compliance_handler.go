@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
 	"vyomtech-backend/internal/services"
 
 	"github.com/gorilla/mux"
@@ -197,6 +199,195 @@ func (h *TaxComplianceHandler) TrackGSTInputCredit(w http.ResponseWriter, r *htt
 	json.NewEncoder(w).Encode(inputCredit)
 }
 
+// RecordGSTOutwardDocument records a sales invoice or credit/debit note for GSTR-1
+func (h *TaxComplianceHandler) RecordGSTOutwardDocument(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.RecordGSTOutwardDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.Service.RecordGSTOutwardDocument(tenantID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(doc)
+}
+
+// RecordGSTInwardDocument records a purchase invoice with its ITC break-up for GSTR-3B
+func (h *TaxComplianceHandler) RecordGSTInwardDocument(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.RecordGSTInwardDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	credit, err := h.Service.RecordGSTInwardDocument(tenantID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(credit)
+}
+
+// GetGSTR1 generates GSTR-1 for ?period=MMYYYY; format=json or format=excel downloads the return
+func (h *TaxComplianceHandler) GetGSTR1(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	period := r.URL.Query().Get("period")
+	if _, _, err := services.ParseGSTReturnPeriod(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.GenerateGSTR1(tenantID, period)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		writeGSTReturnFile(w, fmt.Sprintf("GSTR1_%s.json", period), "application/json", result.Return)
+	case "excel":
+		data, err := h.Service.ExportGSTR1Excel(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeGSTReturnFile(w, fmt.Sprintf("GSTR1_%s.xlsx", period), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// GetGSTR3B generates the GSTR-3B summary for ?period=MMYYYY; format=json or format=excel downloads it
+func (h *TaxComplianceHandler) GetGSTR3B(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	period := r.URL.Query().Get("period")
+	if _, _, err := services.ParseGSTReturnPeriod(period); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.GenerateGSTR3B(tenantID, period)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		writeGSTReturnFile(w, fmt.Sprintf("GSTR3B_%s.json", period), "application/json", result.Return)
+	case "excel":
+		data, err := h.Service.ExportGSTR3BExcel(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeGSTReturnFile(w, fmt.Sprintf("GSTR3B_%s.xlsx", period), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// writeGSTReturnFile sends a return as a download; byte slices are written raw, anything else as JSON
+func writeGSTReturnFile(w http.ResponseWriter, filename, contentType string, payload interface{}) {
+	data, ok := payload.([]byte)
+	if !ok {
+		var err error
+		data, err = json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			http.Error(w, "Failed to encode return", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// ImportGSTR2B uploads the GSTR-2B JSON downloaded from the GST portal
+func (h *TaxComplianceHandler) ImportGSTR2B(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var file models.GSTR2BFile
+	if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+		http.Error(w, "Invalid GSTR-2B file", http.StatusBadRequest)
+		return
+	}
+
+	imp, err := h.Service.ImportGSTR2B(tenantID, userID, &file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(imp)
+}
+
+// ListGSTR2BImports lists uploaded GSTR-2B statements, optionally for ?period=MMYYYY
+func (h *TaxComplianceHandler) ListGSTR2BImports(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	imports, err := h.Service.ListGSTR2BImports(tenantID, r.URL.Query().Get("period"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imports)
+}
+
+// ReconcileITC matches ?period=MMYYYY GSTR-2B against vendor invoices
+func (h *TaxComplianceHandler) ReconcileITC(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	result, err := h.Service.ReconcileITC(tenantID, r.URL.Query().Get("period"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetITCAtRiskReport returns the ITC at risk from the last reconciliation of ?period=MMYYYY
+func (h *TaxComplianceHandler) GetITCAtRiskReport(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	report, err := h.Service.GetITCAtRiskReport(tenantID, r.URL.Query().Get("period"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
 // InitializeAdvanceTaxSchedule sets up quarterly advance tax schedule
 func (h *TaxComplianceHandler) InitializeAdvanceTaxSchedule(w http.ResponseWriter, r *http.Request) {
 	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
@@ -305,6 +496,14 @@ func RegisterTaxComplianceRoutes(router *mux.Router, handler *TaxComplianceHandl
 	tax.HandleFunc("/gst", handler.InitializeGSTCompliance).Methods("POST")
 	tax.HandleFunc("/gst/invoice", handler.TrackGSTInvoice).Methods("POST")
 	tax.HandleFunc("/gst/input-credit", handler.TrackGSTInputCredit).Methods("POST")
+	tax.HandleFunc("/gst/outward-documents", handler.RecordGSTOutwardDocument).Methods("POST")
+	tax.HandleFunc("/gst/inward-documents", handler.RecordGSTInwardDocument).Methods("POST")
+	tax.HandleFunc("/gst/returns/gstr1", handler.GetGSTR1).Methods("GET")
+	tax.HandleFunc("/gst/returns/gstr3b", handler.GetGSTR3B).Methods("GET")
+	tax.HandleFunc("/gst/gstr2b/import", handler.ImportGSTR2B).Methods("POST")
+	tax.HandleFunc("/gst/gstr2b/imports", handler.ListGSTR2BImports).Methods("GET")
+	tax.HandleFunc("/gst/itc-reconciliation", handler.ReconcileITC).Methods("POST")
+	tax.HandleFunc("/gst/itc-reconciliation/at-risk", handler.GetITCAtRiskReport).Methods("GET")
 
 	// Advance Tax
 	tax.HandleFunc("/advance-tax/schedule", handler.InitializeAdvanceTaxSchedule).Methods("POST")
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// TDS HANDLERS
+// ============================================================================
+
+type TDSHandler struct {
+	Service *services.TDSService
+}
+
+func NewTDSHandler(service *services.TDSService) *TDSHandler {
+	return &TDSHandler{Service: service}
+}
+
+// GetSectionRules lists the TDS sections with their rates and thresholds
+func (h *TDSHandler) GetSectionRules(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, h.Service.GetSectionRules())
+}
+
+// ComputeTDS previews the TDS on a payment
+func (h *TDSHandler) ComputeTDS(w http.ResponseWriter, r *http.Request) {
+	var req models.ComputeTDSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comp, err := h.Service.ComputeTDS(&req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, comp)
+}
+
+// RecordPropertyPurchaseTDS deducts 194-IA on an instalment paid for property bought
+func (h *TDSHandler) RecordPropertyPurchaseTDS(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordPropertyPurchaseTDSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entry, err := h.Service.RecordPropertyPurchaseTDS(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, entry)
+}
+
+// GetPayableLedger returns the TDS payable ledger for ?financial_year=YYYY-YY
+func (h *TDSHandler) GetPayableLedger(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	asOf, err := parseAsOfDate(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid as_of date")
+		return
+	}
+	fy := r.URL.Query().Get("financial_year")
+	if fy == "" {
+		fy = services.FinancialYearOf(asOf)
+	}
+
+	ledger, err := h.Service.GetPayableLedger(tenantID, fy, asOf)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ledger)
+}
+
+// CreateChallan records a TDS deposit against pending ledger entries
+func (h *TDSHandler) CreateChallan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateTDSChallanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	challan, err := h.Service.CreateChallan(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, challan)
+}
+
+// ListChallans lists TDS deposits made in ?financial_year=YYYY-YY
+func (h *TDSHandler) ListChallans(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	fy := r.URL.Query().Get("financial_year")
+	if fy == "" {
+		fy = services.FinancialYearOf(time.Now())
+	}
+	from, _, err := services.ParseTDSQuarter(fy, "Q1")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Deposits for March deductions fall in April of the next year
+	challans, err := h.Service.ListChallans(tenantID, from, from.AddDate(1, 1, 0))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, challans)
+}
+
+// GetQuarterlyReturn returns 26Q / 27Q data for ?form=&financial_year=&quarter=; format=excel downloads it
+func (h *TDSHandler) GetQuarterlyReturn(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	form := q.Get("form")
+	if form == "" {
+		form = models.TDSForm26Q
+	}
+	data, err := h.Service.GenerateReturn(tenantID, form, q.Get("financial_year"), q.Get("quarter"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if q.Get("format") == "excel" {
+		file, err := h.Service.ExportReturnExcel(data)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s_%s_%s.xlsx", data.Form, data.FinancialYear, data.Quarter))
+		w.WriteHeader(http.StatusOK)
+		w.Write(file)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, data)
+}
+
+// ============================================================================
+// BUYER TDS (FORM 26QB)
+// ============================================================================
+// Also mounted on the customer portal, where {booking_id} scopes the deduction
+// to the customer's booking
+
+// RecordBuyerTDS records TDS a buyer deducted from a booking payment
+func (h *TDSHandler) RecordBuyerTDS(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordBuyerTDSRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.Service.RecordBuyerTDS(tenantID, userID, mux.Vars(r)["booking_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, credit)
+}
+
+// ListBuyerTDS lists buyer deductions for ?booking_id=&status=&financial_year=
+func (h *TDSHandler) ListBuyerTDS(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	bookingID := mux.Vars(r)["booking_id"]
+	if bookingID == "" {
+		bookingID = q.Get("booking_id")
+	}
+
+	credits, err := h.Service.ListBuyerTDSCredits(tenantID, bookingID, q.Get("status"), q.Get("financial_year"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, credits)
+}
+
+// UpdateBuyerTDSChallan captures the 26QB acknowledgement and challan
+func (h *TDSHandler) UpdateBuyerTDSChallan(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	vars := mux.Vars(r)
+
+	var req models.UpdateBuyerTDSChallanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.Service.UpdateBuyerTDSChallan(tenantID, vars["booking_id"], vars["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, credit)
+}
+
+// UploadForm16B attaches the buyer's Form 16B certificate
+func (h *TDSHandler) UploadForm16B(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	vars := mux.Vars(r)
+
+	var req models.UploadForm16BRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.Service.UploadForm16B(tenantID, vars["booking_id"], vars["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, credit)
+}
+
+// Import26AS uploads the year's 194-IA entries from 26AS / AIS
+func (h *TDSHandler) Import26AS(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.Import26ASRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	imp, err := h.Service.Import26AS(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, imp)
+}
+
+// Reconcile26AS reconciles buyer deductions for ?financial_year= against the imported 26AS
+func (h *TDSHandler) Reconcile26AS(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	fy := r.URL.Query().Get("financial_year")
+	if fy == "" {
+		fy = services.FinancialYearOf(time.Now())
+	}
+
+	result, err := h.Service.Reconcile26AS(tenantID, fy)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"vyomtech-backend/internal/models"
+)
+
+// ============================================
+// Chain of Title Handlers
+// ============================================
+
+// CreateTitleParcel registers a parcel by survey number
+func (h *TitleHandler) CreateTitleParcel(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, err := getTitleUserID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var req models.CreateTitleParcelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	parcel, err := h.service.CreateTitleParcel(tenantID, userID, &req)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusCreated, parcel)
+}
+
+// ListTitleParcels lists parcels for ?survey_number=&village=
+func (h *TitleHandler) ListTitleParcels(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	limit, offset := getTitlePagination(r)
+	q := r.URL.Query()
+
+	parcels, total, err := h.service.ListTitleParcels(tenantID, q.Get("survey_number"), q.Get("village"), limit, offset)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	respondWithTitleJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  parcels,
+		"total": total,
+	})
+}
+
+// GetTitleParcel retrieves a parcel
+func (h *TitleHandler) GetTitleParcel(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	parcel, err := h.service.GetTitleParcel(tenantID, parcelID)
+	if err != nil {
+		if err.Error() == "parcel not found" {
+			respondWithTitleJSON(w, http.StatusNotFound, map[string]string{"error": "Parcel not found"})
+			return
+		}
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, parcel)
+}
+
+// CreateTitleDeed records a deed in a parcel's chain of title
+func (h *TitleHandler) CreateTitleDeed(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, err := getTitleUserID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	var req models.CreateTitleDeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	deed, err := h.service.CreateTitleDeed(tenantID, userID, parcelID, &req)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusCreated, deed)
+}
+
+// ListTitleDeeds lists a parcel's deeds in registration order
+func (h *TitleHandler) ListTitleDeeds(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	deeds, err := h.service.ListTitleDeeds(tenantID, parcelID)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  deeds,
+		"total": len(deeds),
+	})
+}
+
+// CreateTitleEncumbrance records an encumbrance on a parcel
+func (h *TitleHandler) CreateTitleEncumbrance(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, err := getTitleUserID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	var req models.CreateTitleEncumbranceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	encumbrance, err := h.service.CreateTitleEncumbrance(tenantID, userID, parcelID, &req)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusCreated, encumbrance)
+}
+
+// ListTitleEncumbrances lists a parcel's encumbrances for ?status=
+func (h *TitleHandler) ListTitleEncumbrances(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	encumbrances, err := h.service.ListTitleEncumbrances(tenantID, parcelID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  encumbrances,
+		"total": len(encumbrances),
+	})
+}
+
+// DischargeTitleEncumbrance records the release of an encumbrance
+func (h *TitleHandler) DischargeTitleEncumbrance(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, err := getTitleUserID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	encumbranceID, err := strconv.ParseInt(r.PathValue("encumbrance_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid encumbrance ID"})
+		return
+	}
+
+	var req models.DischargeTitleEncumbranceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.service.DischargeTitleEncumbrance(tenantID, userID, encumbranceID, &req); err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, map[string]string{"message": "Encumbrance discharged successfully"})
+}
+
+// AnalyzeTitleChain traces a parcel's chain of title and raises issues for gaps
+func (h *TitleHandler) AnalyzeTitleChain(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, err := getTitleUserID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	analysis, err := h.service.AnalyzeTitleChain(tenantID, userID, parcelID)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, analysis)
+}
+
+// ListTitleChainGaps lists a parcel's chain gaps for ?status=
+func (h *TitleHandler) ListTitleChainGaps(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	parcelID, err := strconv.ParseInt(r.PathValue("parcel_id"), 10, 64)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid parcel ID"})
+		return
+	}
+
+	gaps, err := h.service.ListTitleChainGaps(tenantID, parcelID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  gaps,
+		"total": len(gaps),
+	})
+}
+
+// GetSurveyTimeline returns the title timeline for ?survey_number=&village=
+func (h *TitleHandler) GetSurveyTimeline(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := getTitleTenantID(r)
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("survey_number") == "" {
+		respondWithTitleJSON(w, http.StatusBadRequest, map[string]string{"error": "survey_number is required"})
+		return
+	}
+
+	events, err := h.service.GetSurveyTimeline(tenantID, q.Get("survey_number"), q.Get("village"))
+	if err != nil {
+		respondWithTitleJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondWithTitleJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  events,
+		"total": len(events),
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// UNIT AVAILABILITY HANDLERS
+// ============================================================================
+
+type UnitAvailabilityHandler struct {
+	Service *services.UnitAvailabilityService
+}
+
+func NewUnitAvailabilityHandler(service *services.UnitAvailabilityService) *UnitAvailabilityHandler {
+	return &UnitAvailabilityHandler{Service: service}
+}
+
+// GetMatrix returns a project's availability grid for
+// ?block_id=&unit_type=&facing=&min_price=&max_price=
+func (h *UnitAvailabilityHandler) GetMatrix(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	filter := &models.UnitMatrixFilter{
+		BlockID:  q.Get("block_id"),
+		UnitType: q.Get("unit_type"),
+		Facing:   q.Get("facing"),
+	}
+	for key, dest := range map[string]*float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if v := q.Get(key); v != "" {
+			price, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "Invalid "+key)
+				return
+			}
+			*dest = price
+		}
+	}
+
+	matrix, err := h.Service.GetMatrix(tenantID, mux.Vars(r)["project_id"], filter)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, matrix)
+}
+
+// SetUnitStatus holds, blocks, releases or registers a unit
+func (h *UnitAvailabilityHandler) SetUnitStatus(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.SetUnitStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	change, err := h.Service.SetUnitStatus(tenantID, userID, mux.Vars(r)["unit_id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+// ListStatusHistory lists a unit's status changes
+func (h *UnitAvailabilityHandler) ListStatusHistory(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	history, err := h.Service.ListStatusHistory(tenantID, mux.Vars(r)["unit_id"])
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
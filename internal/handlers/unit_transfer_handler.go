@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// UNIT TRANSFER HANDLERS
+// ============================================================================
+
+type UnitTransferHandler struct {
+	Service *services.UnitTransferService
+}
+
+func NewUnitTransferHandler(service *services.UnitTransferService) *UnitTransferHandler {
+	return &UnitTransferHandler{Service: service}
+}
+
+// SetFeePolicy creates or replaces a project's transfer fee
+func (h *UnitTransferHandler) SetFeePolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	var req models.SetTransferFeePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy, err := h.Service.SetFeePolicy(tenantID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+// ListFeePolicies lists transfer fees
+func (h *UnitTransferHandler) ListFeePolicies(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	policies, err := h.Service.ListFeePolicies(tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policies)
+}
+
+// RequestTransfer raises a transfer of a booking to new buyers
+func (h *UnitTransferHandler) RequestTransfer(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	transfer, err := h.Service.RequestTransfer(tenantID, userID, &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, transfer)
+}
+
+// ListTransfers lists transfers for ?status=&booking_id=
+func (h *UnitTransferHandler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	q := r.URL.Query()
+
+	transfers, err := h.Service.ListTransfers(tenantID, q.Get("status"), q.Get("booking_id"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfers)
+}
+
+// GetTransfer returns a transfer with its parties and endorsement letter
+func (h *UnitTransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	transfer, err := h.Service.GetTransfer(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfer)
+}
+
+// DecideTransfer approves or rejects a transfer
+func (h *UnitTransferHandler) DecideTransfer(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.TransferDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	transfer, err := h.Service.DecideTransfer(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfer)
+}
+
+// RecordFeeReceipt records the transfer fee received
+func (h *UnitTransferHandler) RecordFeeReceipt(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req models.RecordTransferFeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	transfer, err := h.Service.RecordFeeReceipt(tenantID, userID, mux.Vars(r)["id"], &req)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfer)
+}
+
+// CompleteTransfer endorses the booking to the transferees
+func (h *UnitTransferHandler) CompleteTransfer(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	transfer, err := h.Service.CompleteTransfer(tenantID, userID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfer)
+}
+
+// GetLedgerAudit lists the customer ledger entries a transfer re-pointed
+func (h *UnitTransferHandler) GetLedgerAudit(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.TenantIDKey).(string)
+
+	audit, err := h.Service.GetLedgerAudit(tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, audit)
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"vyomtech-backend/internal/services"
+	"vyomtech-backend/pkg/logger"
+)
+
+// Context keys set for channel partner portal requests. Partner requests never
+// carry UserIDKey, so staff handlers cannot mistake a partner for a user.
+const (
+	PartnerIDKey     contextKey = "partner_id"
+	PartnerUserIDKey contextKey = "partner_user_id"
+	PartnerScopesKey contextKey = "partner_scopes"
+)
+
+// PartnerAuthMiddleware accepts only partner portal tokens and scopes the request
+// to the signed-in partner
+func PartnerAuthMiddleware(portalService *services.PartnerPortalService, log *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := portalService.JWT.ExtractTokenFromHeader(r.Header.Get("Authorization"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := portalService.Authenticate(r.Context(), token)
+			if err != nil {
+				log.Warn("Invalid partner token", "error", err)
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), TenantIDKey, claims.TenantID)
+			ctx = context.WithValue(ctx, PartnerIDKey, claims.PartnerID)
+			ctx = context.WithValue(ctx, PartnerUserIDKey, claims.PartnerUserID)
+			ctx = context.WithValue(ctx, PartnerScopesKey, claims.Scopes)
+			ctx = context.WithValue(ctx, RoleKey, claims.Role)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePartnerScope rejects partner requests whose token lacks the scope
+func RequirePartnerScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, _ := r.Context().Value(PartnerScopesKey).([]string)
+			for _, s := range scopes {
+				if s == scope {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		})
+	}
+}
+
+// RateLimiter allows a fixed number of requests per key in a sliding window
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, hits: map[string][]time.Time{}}
+}
+
+// Allow records a request for key and reports whether it is within the limit,
+// and if not, how long until the oldest request leaves the window
+func (l *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	hits := l.hits[key]
+	kept := 0
+	for _, t := range hits {
+		if t.After(cutoff) {
+			hits[kept] = t
+			kept++
+		}
+	}
+	hits = hits[:kept]
+
+	if len(hits) >= l.limit {
+		l.hits[key] = hits
+		return false, hits[0].Sub(cutoff)
+	}
+	l.hits[key] = append(hits, now)
+
+	// Drop keys that have gone quiet so the map does not grow without bound
+	if len(l.hits) > 10000 {
+		for k, v := range l.hits {
+			if len(v) == 0 || !v[len(v)-1].After(cutoff) {
+				delete(l.hits, k)
+			}
+		}
+	}
+	return true, 0
+}
+
+// PartnerRateLimitMiddleware limits requests per partner user, or per client IP
+// before sign-in
+func PartnerRateLimitMiddleware(limiter *RateLimiter, log *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, _ := r.Context().Value(PartnerUserIDKey).(string)
+			if key == "" {
+				// The connection's own address; forwarded headers are set by the client
+				// and would let it pick a fresh key for every request
+				ip := r.RemoteAddr
+				if host, _, err := net.SplitHostPort(ip); err == nil {
+					ip = host
+				}
+				key = "ip:" + ip
+			}
+
+			if ok, retry := limiter.Allow(key, time.Now()); !ok {
+				log.Warn("Partner rate limit exceeded", "key", key, "path", r.URL.Path)
+				w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
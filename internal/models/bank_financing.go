@@ -19,7 +19,7 @@ type BankFinancing struct {
 	InterestRate           *float64   `json:"interest_rate" db:"interest_rate"`
 	TenureMonths           *int       `json:"tenure_months" db:"tenure_months"`
 	EMIAmount              *float64   `json:"emi_amount" db:"emi_amount"`
-	Status                 string     `json:"status" db:"status"` // pending, approved, sanctioned, disbursing, completed, rejected
+	Status                 string     `json:"status" db:"status"` // pending, approved, sanctioned, disbursing, completed, rejected, cancelled
 	ApplicationDate        *time.Time `json:"application_date" db:"application_date"`
 	ApprovalDate           *time.Time `json:"approval_date" db:"approval_date"`
 	SanctionDate           *time.Time `json:"sanction_date" db:"sanction_date"`
@@ -60,7 +60,7 @@ type BankNOC struct {
 	ID              string     `json:"id" db:"id"`
 	TenantID        string     `json:"tenant_id" db:"tenant_id"`
 	FinancingID     string     `json:"financing_id" db:"financing_id"`
-	NOCType         string     `json:"noc_type" db:"noc_type"` // Pre-sanction, Post-completion, Full-settlement
+	NOCType         string     `json:"noc_type" db:"noc_type"` // Pre-sanction, Post-completion, Full-settlement, Cancellation
 	NOCRequestDate  time.Time  `json:"noc_request_date" db:"noc_request_date"`
 	NOCReceivedDate *time.Time `json:"noc_received_date" db:"noc_received_date"`
 	NOCDocumentURL  *string    `json:"noc_document_url" db:"noc_document_url"`
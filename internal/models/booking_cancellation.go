@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// BOOKING CANCELLATION AND REFUND MODELS
+// ============================================================================
+
+// Cancellation statuses
+const (
+	CancellationStatusRequested         = "requested"                   // refund statement computed, awaiting approval
+	CancellationStatusPendingSettlement = "approved_pending_settlement" // booking cancelled, clawback, GL reversal or NOC outstanding
+	CancellationStatusApproved          = "approved"                    // booking cancelled, refund approved for payout
+	CancellationStatusRejected          = "rejected"
+	CancellationStatusRefunded          = "refunded"
+)
+
+// CancellationPolicy holds the forfeiture rules applied when a booking is cancelled,
+// tenant-wide when ProjectID is nil
+type CancellationPolicy struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	ProjectID        *string   `json:"project_id"`
+	ForfeitPercent   float64   `json:"forfeit_percent"` // of the agreement value
+	ForfeitMinAmount float64   `json:"forfeit_min_amount"`
+	AdminCharge      float64   `json:"admin_charge"`
+	GSTRefundable    bool      `json:"gst_refundable"`    // false retains the GST collected
+	RecoverBrokerage bool      `json:"recover_brokerage"` // deduct brokerage from the refund; the broker keeps it
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CancellationStatement is the refund computation for a cancelled booking
+type CancellationStatement struct {
+	AgreementValue     float64 `json:"agreement_value"`
+	AmountPaid         float64 `json:"amount_paid"` // cleared receipts including TDS deducted by the buyer
+	GSTCollected       float64 `json:"gst_collected"`
+	ForfeitPercent     float64 `json:"forfeit_percent"`
+	ForfeitureAmount   float64 `json:"forfeiture_amount"`
+	GSTRetained        float64 `json:"gst_retained"`
+	BrokerageRecovered float64 `json:"brokerage_recovered"`
+	AdminCharge        float64 `json:"admin_charge"`
+	TotalDeductions    float64 `json:"total_deductions"` // capped at the amount paid
+	RefundAmount       float64 `json:"refund_amount"`
+	LoanOutstanding    float64 `json:"loan_outstanding"`
+	BankRefund         float64 `json:"bank_refund"` // repaid to the lender first, against its NOC
+	CustomerRefund     float64 `json:"customer_refund"`
+}
+
+// BookingCancellation is a cancellation request on a booking and its refund
+type BookingCancellation struct {
+	ID               string                `json:"id"`
+	TenantID         string                `json:"tenant_id"`
+	BookingID        string                `json:"booking_id"`
+	UnitID           string                `json:"unit_id"`
+	ProjectID        string                `json:"project_id,omitempty"`
+	CustomerID       string                `json:"customer_id,omitempty"`
+	Reason           string                `json:"reason"`
+	Status           string                `json:"status"`
+	Statement        CancellationStatement `json:"statement"`
+	FinancingID      *string               `json:"financing_id"`
+	NOCID            *string               `json:"noc_id"`
+	CreditNoteNumber *string               `json:"credit_note_number"`
+	CreditNoteAmount float64               `json:"credit_note_amount"` // consideration reversed, excluding GST
+	CreditNoteGST    float64               `json:"credit_note_gst"`
+	JournalEntryID   *string               `json:"journal_entry_id"`
+	SettlementError  *string               `json:"settlement_error"` // last failure while settling an approval
+	RequestedBy      string                `json:"requested_by"`
+	ApprovedBy       *string               `json:"approved_by"`
+	ApprovedAt       *time.Time            `json:"approved_at"`
+	DecisionComment  string                `json:"decision_comment,omitempty"`
+	RefundMode       *string               `json:"refund_mode"`
+	RefundReference  *string               `json:"refund_reference"`
+	RefundedAt       *time.Time            `json:"refunded_at"`
+	PayoutEntryID    *string               `json:"payout_entry_id"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// SetCancellationPolicyRequest creates or replaces a project's forfeiture rules
+type SetCancellationPolicyRequest struct {
+	ProjectID        string  `json:"project_id"` // empty for all projects
+	ForfeitPercent   float64 `json:"forfeit_percent"`
+	ForfeitMinAmount float64 `json:"forfeit_min_amount"`
+	AdminCharge      float64 `json:"admin_charge"`
+	GSTRefundable    bool    `json:"gst_refundable"`
+	RecoverBrokerage bool    `json:"recover_brokerage"`
+}
+
+// CreateCancellationRequest asks for a booking to be cancelled
+type CreateCancellationRequest struct {
+	BookingID string `json:"booking_id" binding:"required"`
+	Reason    string `json:"reason" binding:"required"`
+}
+
+// CancellationDecisionRequest approves or rejects a cancellation and its refund
+type CancellationDecisionRequest struct {
+	Approve bool   `json:"approve"`
+	Comment string `json:"comment"` // required when rejecting
+}
+
+// RecordRefundPayoutRequest records the refund paid out on an approved cancellation
+type RecordRefundPayoutRequest struct {
+	PaymentMode string `json:"payment_mode" binding:"required"` // neft, rtgs, cheque
+	Reference   string `json:"reference" binding:"required"`
+	PaidOn      string `json:"paid_on"` // YYYY-MM-DD, defaults to today
+}
@@ -138,7 +138,7 @@ type BrokerBookingLink struct {
 
 	// Status
 	BookingStatus    string `json:"booking_status"`                 // active, cancelled, completed
-	CommissionStatus string `gorm:"index" json:"commission_status"` // pending, approved, paid, cancelled
+	CommissionStatus string `gorm:"index" json:"commission_status"` // pending, approved, paid, cancelled, clawed_back
 
 	// Dates
 	BookingDate  *time.Time `json:"booking_date"`
@@ -180,6 +180,7 @@ type BrokerCommissionPayout struct {
 	TDSAmount        *float64 `json:"tds_amount"`
 	GSTAmount        *float64 `json:"gst_amount"`
 	NetPayableAmount float64  `json:"net_payable_amount"`
+	ClawbackAmount   float64  `gorm:"-" json:"clawback_amount"` // recovered against cancelled bookings
 
 	// Payment Details
 	PaymentMode        *string    `json:"payment_mode"` // bank_transfer, cheque, cash, neft, rtgs
@@ -212,6 +213,27 @@ func (BrokerCommissionPayout) TableName() string {
 	return "broker_commission_payout"
 }
 
+// ============================================================================
+// BrokerCommissionClawback Model
+// ============================================================================
+type BrokerCommissionClawback struct {
+	ID                string     `gorm:"primaryKey" json:"id"`
+	TenantID          string     `gorm:"index" json:"tenant_id"`
+	BrokerID          string     `gorm:"index" json:"broker_id"`
+	BookingID         string     `json:"booking_id"`
+	LinkID            string     `json:"link_id"`
+	Amount            float64    `json:"amount"`
+	Status            string     `gorm:"index" json:"status"` // pending, adjusted
+	PayoutReferenceNo *string    `json:"payout_reference_no"` // payout the clawback was netted against
+	AdjustedAt        *time.Time `json:"adjusted_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (BrokerCommissionClawback) TableName() string {
+	return "broker_commission_clawbacks"
+}
+
 // ============================================================================
 // DTO Models for API Requests/Responses
 // ============================================================================
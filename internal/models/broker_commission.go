@@ -0,0 +1,188 @@
+package models
+
+import "time"
+
+// ============================================================================
+// BROKER COMMISSION ENGINE MODELS
+// ============================================================================
+// Commission on a BrokerBookingLink accrues once its booking reaches the
+// trigger set on the applicable commission plan (agreement signed or a share of
+// the agreement value collected). The rate comes from the plan's quarterly
+// volume slabs, so crossing a slab re-rates every booking in the quarter, and a
+// cancellation re-rates the rest of the quarter down again.
+
+// Commission triggers
+const (
+	CommissionTriggerAgreementSigned   = "agreement_signed"
+	CommissionTriggerCollectionPercent = "collection_percent"
+)
+
+// Volume bases for quarterly slabs
+const (
+	CommissionSlabBasisUnits = "units" // bookings triggered in the quarter
+	CommissionSlabBasisValue = "value" // agreement value triggered in the quarter
+)
+
+// Commission accrual kinds
+const (
+	CommissionAccrualCommission     = "commission"
+	CommissionAccrualSlabAdjustment = "slab_adjustment" // re-rating when the quarter's slab changes
+)
+
+// Commission accrual statuses
+const (
+	CommissionAccrualAccrued    = "accrued"
+	CommissionAccrualPaid       = "paid"
+	CommissionAccrualCancelled  = "cancelled"   // unpaid when the booking was cancelled
+	CommissionAccrualClawedBack = "clawed_back" // paid, recovered from a later payout
+)
+
+// Broker payout statuses
+const (
+	BrokerPayoutGenerated = "generated"
+	BrokerPayoutPaid      = "paid"
+)
+
+// CommissionSlab is a commission rate that applies once the quarter's volume reaches MinVolume
+type CommissionSlab struct {
+	MinVolume float64 `json:"min_volume"` // units or agreement value, by the plan's slab basis
+	Rate      float64 `json:"rate"`       // % of agreement value
+}
+
+// BrokerCommissionPlan sets the trigger and slabs for a broker, a project, both or neither
+type BrokerCommissionPlan struct {
+	ID             string           `json:"id"`
+	TenantID       string           `json:"tenant_id"`
+	Name           string           `json:"name"`
+	BrokerID       *string          `json:"broker_id,omitempty"`  // all brokers when empty
+	ProjectID      *string          `json:"project_id,omitempty"` // all projects when empty
+	Trigger        string           `json:"trigger"`
+	TriggerPercent float64          `json:"trigger_percent"` // collection_percent only
+	SlabBasis      string           `json:"slab_basis"`
+	Slabs          []CommissionSlab `json:"slabs"`
+	GSTRate        float64          `json:"gst_rate"`
+	EffectiveFrom  time.Time        `json:"effective_from"`
+	EffectiveTo    *time.Time       `json:"effective_to,omitempty"`
+	Status         string           `json:"status"`
+	CreatedBy      *string          `json:"created_by,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// BrokerCommissionSplit is a link's share of the commission on a booking sold by several brokers
+type BrokerCommissionSplit struct {
+	LinkID       string  `json:"link_id" validate:"required"`
+	BrokerID     string  `json:"broker_id,omitempty"`
+	SplitPercent float64 `json:"split_percent" validate:"required"`
+}
+
+// BrokerCommissionAccrual is commission earned on a booking link, or a slab re-rating of it
+type BrokerCommissionAccrual struct {
+	ID           string    `json:"id"`
+	TenantID     string    `json:"tenant_id"`
+	LinkID       string    `json:"link_id"`
+	BrokerID     string    `json:"broker_id"`
+	BookingID    string    `json:"booking_id"`
+	ProjectID    string    `json:"project_id"`
+	PlanID       string    `json:"plan_id"`
+	Quarter      string    `json:"quarter"` // YYYY-Qn the trigger fell in
+	Kind         string    `json:"kind"`
+	BaseAmount   float64   `json:"base_amount"` // agreement value
+	Rate         float64   `json:"rate"`
+	SplitPercent float64   `json:"split_percent"`
+	Commission   float64   `json:"commission"` // negative for a downward re-rating
+	GSTAmount    float64   `json:"gst_amount"`
+	Status       string    `json:"status"`
+	PayoutID     *string   `json:"payout_id,omitempty"`
+	TriggeredOn  time.Time `json:"triggered_on"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BrokerCommissionRun is the outcome of evaluating booking links against their triggers
+type BrokerCommissionRun struct {
+	Evaluated int                       `json:"evaluated"`
+	Accrued   float64                   `json:"accrued"`
+	Accruals  []BrokerCommissionAccrual `json:"accruals"`
+}
+
+// BrokerPayout settles a broker's accrued commission net of TDS and clawbacks
+type BrokerPayout struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	PayoutNumber   string     `json:"payout_number"`
+	BrokerID       string     `json:"broker_id"`
+	Commission     float64    `json:"commission"`
+	GSTAmount      float64    `json:"gst_amount"`
+	TDSAmount      float64    `json:"tds_amount"`
+	ClawbackAmount float64    `json:"clawback_amount"`
+	NetAmount      float64    `json:"net_amount"`
+	Status         string     `json:"status"`
+	PaymentDate    *time.Time `json:"payment_date,omitempty"`
+	PaymentMode    *string    `json:"payment_mode,omitempty"`
+	Reference      *string    `json:"reference,omitempty"`
+	AccrualIDs     []string   `json:"accrual_ids,omitempty"`
+	CreatedBy      *string    `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BrokerStatementLine is a credit or debit on a broker's commission account
+type BrokerStatementLine struct {
+	Date        time.Time `json:"date"`
+	Type        string    `json:"type"` // commission, slab_adjustment, clawback, tds, payout
+	BookingID   string    `json:"booking_id,omitempty"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	Credit      float64   `json:"credit"`
+	Debit       float64   `json:"debit"`
+	Balance     float64   `json:"balance"` // owed to the broker after this line
+}
+
+// BrokerStatement is a broker's commission account for a period
+type BrokerStatement struct {
+	BrokerID       string                `json:"broker_id"`
+	BrokerName     string                `json:"broker_name"`
+	PAN            string                `json:"pan,omitempty"`
+	GSTIN          string                `json:"gstin,omitempty"`
+	From           time.Time             `json:"from"`
+	To             time.Time             `json:"to"`
+	Opening        float64               `json:"opening"`
+	Commission     float64               `json:"commission"`
+	GSTAmount      float64               `json:"gst_amount"`
+	TDSAmount      float64               `json:"tds_amount"`
+	ClawbackAmount float64               `json:"clawback_amount"`
+	Paid           float64               `json:"paid"`
+	Closing        float64               `json:"closing"`
+	Lines          []BrokerStatementLine `json:"lines"`
+}
+
+// CreateBrokerCommissionPlanRequest sets up a commission plan
+type CreateBrokerCommissionPlanRequest struct {
+	Name           string           `json:"name" validate:"required"`
+	BrokerID       string           `json:"broker_id"`
+	ProjectID      string           `json:"project_id"`
+	Trigger        string           `json:"trigger" validate:"required"`
+	TriggerPercent float64          `json:"trigger_percent"`
+	SlabBasis      string           `json:"slab_basis"` // defaults to units
+	Slabs          []CommissionSlab `json:"slabs" validate:"required"`
+	GSTRate        float64          `json:"gst_rate"`
+	EffectiveFrom  *time.Time       `json:"effective_from"` // defaults to today
+	EffectiveTo    *time.Time       `json:"effective_to"`
+}
+
+// SetBrokerCommissionSplitsRequest shares a booking's commission between its broker links
+type SetBrokerCommissionSplitsRequest struct {
+	Splits []BrokerCommissionSplit `json:"splits" validate:"required"`
+}
+
+// GenerateBrokerPayoutRequest pays a broker's accrued commission
+type GenerateBrokerPayoutRequest struct {
+	BrokerID string `json:"broker_id" validate:"required"`
+}
+
+// MarkBrokerPayoutPaidRequest records payment of a generated payout
+type MarkBrokerPayoutPaidRequest struct {
+	PaymentDate *time.Time `json:"payment_date"` // defaults to today
+	PaymentMode string     `json:"payment_mode" validate:"required"`
+	Reference   string     `json:"reference"`
+}
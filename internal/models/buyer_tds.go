@@ -0,0 +1,177 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// BUYER TDS (FORM 26QB) MODELS
+// ============================================================================
+
+// Buyer TDS credit statuses
+const (
+	BuyerTDSStatusDeducted            = "deducted"             // declared, 26QB acknowledgement awaited
+	BuyerTDSStatusChallanFiled        = "challan_filed"        // 26QB acknowledgement / challan captured
+	BuyerTDSStatusCertificateReceived = "certificate_received" // Form 16B uploaded
+	BuyerTDSStatusVerified            = "verified"             // found in 26AS / AIS
+	BuyerTDSStatusMismatch            = "mismatch"             // found in 26AS / AIS with a different amount
+)
+
+// 26AS reconciliation buckets
+const (
+	Form26ASReconMatched        = "matched"
+	Form26ASReconAmountMismatch = "amount_mismatch"
+	Form26ASReconNotIn26AS      = "not_in_26as"
+	Form26ASReconNotInBooks     = "not_in_books"
+)
+
+// Tax statement sources
+const (
+	TaxStatementSource26AS = "26AS"
+	TaxStatementSourceAIS  = "AIS"
+)
+
+// BuyerTDSCredit is TDS u/s 194-IA a buyer deducted from a booking payment. The
+// booking is credited with it as a TDS receivable until the deposit shows up in 26AS.
+type BuyerTDSCredit struct {
+	ID                    string     `json:"id"`
+	TenantID              string     `json:"tenant_id"`
+	BookingID             string     `json:"booking_id"`
+	PaymentID             string     `json:"payment_id"`
+	CustomerID            string     `json:"customer_id,omitempty"`
+	BuyerName             string     `json:"buyer_name"`
+	BuyerPAN              string     `json:"buyer_pan"`
+	FinancialYear         string     `json:"financial_year"`
+	TotalConsideration    float64    `json:"total_consideration"`
+	AmountReceived        float64    `json:"amount_received"` // net of TDS, as in booking_payments
+	GrossAmount           float64    `json:"gross_amount"`    // amount received + TDS
+	TDSRate               float64    `json:"tds_rate"`
+	TDSAmount             float64    `json:"tds_amount"`
+	ExpectedTDS           float64    `json:"expected_tds"`
+	DeductionDate         time.Time  `json:"deduction_date"`
+	AcknowledgementNumber string     `json:"acknowledgement_number,omitempty"` // 26QB acknowledgement
+	ChallanSerialNo       string     `json:"challan_serial_no,omitempty"`
+	BSRCode               string     `json:"bsr_code,omitempty"`
+	ChallanDate           *time.Time `json:"challan_date"`
+	CertificateNumber     string     `json:"certificate_number,omitempty"` // Form 16B
+	CertificateFileURL    string     `json:"certificate_file_url,omitempty"`
+	CertificateUploadedAt *time.Time `json:"certificate_uploaded_at"`
+	CertificateDueDate    time.Time  `json:"certificate_due_date"`
+	Status                string     `json:"status"`
+	LedgerEntryID         string     `json:"ledger_entry_id"`
+	Form26ASEntryID       *string    `json:"form26as_entry_id"`
+	CreatedBy             string     `json:"created_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// Form26ASImport is one uploaded 26AS or AIS statement
+type Form26ASImport struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	FinancialYear string    `json:"financial_year"`
+	Source        string    `json:"source"` // 26AS, AIS
+	EntryCount    int       `json:"entry_count"`
+	TotalTDS      float64   `json:"total_tds"`
+	ImportedBy    string    `json:"imported_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Form26ASEntry is a 194-IA credit reported against our PAN in 26AS / AIS
+type Form26ASEntry struct {
+	ID                    string    `json:"id"`
+	TenantID              string    `json:"tenant_id"`
+	ImportID              string    `json:"import_id"`
+	FinancialYear         string    `json:"financial_year"`
+	DeductorName          string    `json:"deductor_name"`
+	DeductorPAN           string    `json:"deductor_pan"`
+	Section               string    `json:"section"`
+	TransactionDate       time.Time `json:"transaction_date"`
+	AmountPaid            float64   `json:"amount_paid"`
+	TDSDeposited          float64   `json:"tds_deposited"`
+	AcknowledgementNumber string    `json:"acknowledgement_number,omitempty"`
+	BookingStatus         string    `json:"booking_status,omitempty"` // F (final), U (unmatched), P (provisional)
+	MatchedCreditID       *string   `json:"matched_credit_id"`
+}
+
+// Form26ASReconciliationLine pairs a buyer TDS credit with a 26AS entry (either side may be empty)
+type Form26ASReconciliationLine struct {
+	Status                string  `json:"status"`
+	CreditID              string  `json:"credit_id,omitempty"`
+	BookingID             string  `json:"booking_id,omitempty"`
+	BuyerName             string  `json:"buyer_name"`
+	BuyerPAN              string  `json:"buyer_pan"`
+	AcknowledgementNumber string  `json:"acknowledgement_number,omitempty"`
+	BooksTDS              float64 `json:"books_tds"`
+	Form26ASEntryID       string  `json:"form26as_entry_id,omitempty"`
+	Form26ASTDS           float64 `json:"form26as_tds"`
+	Difference            float64 `json:"difference"` // books - 26AS
+}
+
+// Form26ASReconciliationSummary totals each bucket
+type Form26ASReconciliationSummary struct {
+	MatchedCount        int     `json:"matched_count"`
+	AmountMismatchCount int     `json:"amount_mismatch_count"`
+	NotIn26ASCount      int     `json:"not_in_26as_count"`
+	NotInBooksCount     int     `json:"not_in_books_count"`
+	BooksTDS            float64 `json:"books_tds"`
+	Form26ASTDS         float64 `json:"form26as_tds"`
+	UnverifiedTDS       float64 `json:"unverified_tds"` // credited to customers but not yet seen in 26AS
+}
+
+// Form26ASReconciliationResult is the outcome of reconciling buyer TDS for a year
+type Form26ASReconciliationResult struct {
+	FinancialYear string                        `json:"financial_year"`
+	Summary       Form26ASReconciliationSummary `json:"summary"`
+	Lines         []Form26ASReconciliationLine  `json:"lines"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// RecordBuyerTDSRequest records TDS a buyer deducted from a booking payment
+type RecordBuyerTDSRequest struct {
+	PaymentID             string  `json:"payment_id" binding:"required"`
+	TDSAmount             float64 `json:"tds_amount" binding:"required"`
+	BuyerPAN              string  `json:"buyer_pan"`
+	TotalConsideration    float64 `json:"total_consideration"` // defaults to the unit cost sheet total
+	DeductionDate         string  `json:"deduction_date"`      // YYYY-MM-DD, defaults to the payment date
+	AcknowledgementNumber string  `json:"acknowledgement_number"`
+	ChallanSerialNo       string  `json:"challan_serial_no"`
+	BSRCode               string  `json:"bsr_code"`
+	ChallanDate           string  `json:"challan_date"` // YYYY-MM-DD
+}
+
+// UpdateBuyerTDSChallanRequest captures the 26QB acknowledgement and challan
+type UpdateBuyerTDSChallanRequest struct {
+	AcknowledgementNumber string `json:"acknowledgement_number" binding:"required"`
+	ChallanSerialNo       string `json:"challan_serial_no"`
+	BSRCode               string `json:"bsr_code"`
+	ChallanDate           string `json:"challan_date"` // YYYY-MM-DD
+}
+
+// UploadForm16BRequest attaches the buyer's Form 16B certificate
+type UploadForm16BRequest struct {
+	CertificateNumber string `json:"certificate_number" binding:"required"`
+	FileURL           string `json:"file_url" binding:"required"`
+}
+
+// Form26ASEntryInput is a 194-IA row from a 26AS / AIS download
+type Form26ASEntryInput struct {
+	DeductorName          string  `json:"deductor_name"`
+	DeductorPAN           string  `json:"deductor_pan"`
+	Section               string  `json:"section"`
+	TransactionDate       string  `json:"transaction_date"` // YYYY-MM-DD or DD-Mon-YYYY as in 26AS
+	AmountPaid            float64 `json:"amount_paid"`
+	TDSDeposited          float64 `json:"tds_deposited"`
+	AcknowledgementNumber string  `json:"acknowledgement_number"`
+	BookingStatus         string  `json:"booking_status"`
+}
+
+// Import26ASRequest replaces the year's 26AS / AIS entries
+type Import26ASRequest struct {
+	FinancialYear string               `json:"financial_year" binding:"required"`
+	Source        string               `json:"source"` // 26AS (default), AIS
+	Entries       []Form26ASEntryInput `json:"entries" binding:"required"`
+}
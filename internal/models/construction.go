@@ -54,6 +54,29 @@ type ProgressTracking struct {
 	UpdatedAt         time.Time
 }
 
+// LogProgressRequest records site progress. An entry at 100% against a tower
+// milestone completes the milestone and raises its demand letters.
+type LogProgressRequest struct {
+	ProjectID         string  `json:"project_id" binding:"required"`
+	Date              string  `json:"date"` // YYYY-MM-DD, defaults to today
+	ActivityDesc      string  `json:"activity_desc"`
+	QuantityCompleted float64 `json:"quantity_completed"`
+	Unit              string  `json:"unit"`
+	PercentComplete   int     `json:"percent_complete"`
+	WorkforceDeployed int     `json:"workforce_deployed"`
+	Notes             string  `json:"notes"`
+	PhotoURL          string  `json:"photo_url"`
+	TowerMilestoneID  string  `json:"tower_milestone_id"`
+	ArchitectCertRef  string  `json:"architect_certificate_ref"`
+	Channel           string  `json:"channel"` // demand letter channel, email (default), sms, whatsapp
+}
+
+// ProgressLogResult is a logged progress entry and the demand run it triggered
+type ProgressLogResult struct {
+	ProgressTrackingID string              `json:"progress_tracking_id"`
+	DemandRun          *MilestoneDemandRun `json:"demand_run,omitempty"`
+}
+
 // QualityControl represents quality control inspections
 type QualityControl struct {
 	ID               string `gorm:"primaryKey"`
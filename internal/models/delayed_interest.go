@@ -0,0 +1,150 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================
+// DELAYED-PAYMENT INTEREST MODELS (RERA)
+// ============================================
+
+// Interest calculation methods
+const (
+	InterestMethodSimple   = "simple"
+	InterestMethodCompound = "compound"
+)
+
+// Interest note types raised into the customer ledger
+const (
+	InterestNoteDelayedPayment    = "delayed_payment_interest"
+	InterestNotePossessionDelayed = "possession_delay_compensation"
+)
+
+// InterestRateHistory is one period of the benchmark rate (SBI MCLR) plus the RERA spread
+type InterestRateHistory struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	Benchmark     string     `json:"benchmark"`      // e.g. SBI_MCLR_1Y
+	BaseRate      float64    `json:"base_rate"`      // % p.a.
+	Spread        float64    `json:"spread"`         // % p.a., 2.0 under RERA
+	EffectiveRate float64    `json:"effective_rate"` // base_rate + spread
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"` // nil while current
+	Notes         string     `json:"notes"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// InterestPolicy holds the tenant or project level interest settings
+type InterestPolicy struct {
+	ID                  string    `json:"id"`
+	TenantID            string    `json:"tenant_id"`
+	ProjectID           string    `json:"project_id"` // empty for tenant default
+	GraceDays           int       `json:"grace_days"`
+	Method              string    `json:"method"`                // simple, compound
+	CompoundingPerYear  int       `json:"compounding_per_year"`  // 12 monthly, 4 quarterly, 1 annual
+	ApplyToPossession   bool      `json:"apply_to_possession"`   // compute builder compensation on delayed possession
+	MinimumInterestNote float64   `json:"minimum_interest_note"` // skip notes below this amount
+	IsActive            bool      `json:"is_active"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// InterestSegment is the interest for a span of days at a single rate
+type InterestSegment struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Days     int       `json:"days"`
+	Rate     float64   `json:"rate"`
+	Interest float64   `json:"interest"`
+}
+
+// InterestAccrual is the accrued interest for one overdue payment stage
+type InterestAccrual struct {
+	StageID          string            `json:"stage_id"`
+	StageName        string            `json:"stage_name"`
+	ProjectID        string            `json:"project_id"`
+	UnitID           string            `json:"unit_id"`
+	CustomerID       string            `json:"customer_id"`
+	BookingID        string            `json:"booking_id"`
+	DueDate          time.Time         `json:"due_date"`
+	PrincipalOverdue float64           `json:"principal_overdue"`
+	DaysOverdue      int               `json:"days_overdue"`
+	ChargeableDays   int               `json:"chargeable_days"`
+	AccruedInterest  float64           `json:"accrued_interest"`
+	AlreadyRaised    float64           `json:"already_raised"`
+	InterestDue      float64           `json:"interest_due"`
+	Segments         []InterestSegment `json:"segments"`
+}
+
+// InterestNote is an interest demand (debit) or compensation (credit) note in the customer ledger
+type InterestNote struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	NoteNumber     string    `json:"note_number"`
+	NoteType       string    `json:"note_type"` // delayed_payment_interest, possession_delay_compensation
+	BookingID      string    `json:"booking_id"`
+	StageID        string    `json:"stage_id"`
+	ProjectID      string    `json:"project_id"`
+	PeriodFrom     time.Time `json:"period_from"`
+	PeriodTo       time.Time `json:"period_to"`
+	Principal      float64   `json:"principal"`
+	InterestAmount float64   `json:"interest_amount"`
+	Method         string    `json:"method"`
+	LedgerEntryID  string    `json:"ledger_entry_id"`
+	Status         string    `json:"status"` // raised, cancelled
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PossessionCompensation is the interest payable by the builder for a missed possession date
+type PossessionCompensation struct {
+	BookingID       string            `json:"booking_id"`
+	PromisedDate    time.Time         `json:"promised_date"`
+	ActualDate      *time.Time        `json:"actual_date"`
+	ComputedUpTo    time.Time         `json:"computed_up_to"`
+	AmountPaid      float64           `json:"amount_paid"`
+	DelayDays       int               `json:"delay_days"`
+	AccruedInterest float64           `json:"accrued_interest"`
+	AlreadyCredited float64           `json:"already_credited"`
+	CompensationDue float64           `json:"compensation_due"`
+	Segments        []InterestSegment `json:"segments"`
+	Note            *InterestNote     `json:"note,omitempty"`
+}
+
+// ============================================
+// REQUEST/RESPONSE MODELS
+// ============================================
+
+// CreateInterestRateRequest adds a new benchmark rate effective from a date
+type CreateInterestRateRequest struct {
+	Benchmark     string    `json:"benchmark"`
+	BaseRate      float64   `json:"base_rate" binding:"required"`
+	Spread        *float64  `json:"spread"` // defaults to 2.0
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"`
+	Notes         string    `json:"notes"`
+}
+
+// UpsertInterestPolicyRequest sets the interest policy for the tenant or a project
+type UpsertInterestPolicyRequest struct {
+	ProjectID           string  `json:"project_id"`
+	GraceDays           int     `json:"grace_days"`
+	Method              string  `json:"method"`
+	CompoundingPerYear  int     `json:"compounding_per_year"`
+	ApplyToPossession   bool    `json:"apply_to_possession"`
+	MinimumInterestNote float64 `json:"minimum_interest_note"`
+}
+
+// RaiseInterestNotesRequest raises interest notes for overdue stages as of a date
+type RaiseInterestNotesRequest struct {
+	ProjectID string     `json:"project_id"`
+	AsOfDate  *time.Time `json:"as_of_date"`
+	DryRun    bool       `json:"dry_run"`
+}
+
+// PossessionCompensationRequest computes (and optionally credits) delayed possession compensation
+type PossessionCompensationRequest struct {
+	BookingID string     `json:"booking_id" binding:"required"`
+	AsOfDate  *time.Time `json:"as_of_date"`
+	Raise     bool       `json:"raise"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// DISCOUNT APPROVAL MODELS
+// ============================================================================
+
+// Discount request statuses
+const (
+	DiscountStatusPending  = "pending"
+	DiscountStatusApproved = "approved"
+	DiscountStatusRejected = "rejected"
+	DiscountStatusUsed     = "used" // applied to a booking or sales order
+)
+
+// Approval step statuses
+const (
+	DiscountStepPending  = "pending"
+	DiscountStepApproved = "approved"
+	DiscountStepRejected = "rejected"
+)
+
+// Default approver roles, lowest level first
+const (
+	DiscountApproverSalesManager = "sales_manager"
+	DiscountApproverVP           = "vp"
+	DiscountApproverDirector     = "director"
+)
+
+// DiscountLimit is how much discount a role may give on its own authority,
+// project-wide when ProjectID is nil. Roles with an approval level sit on the
+// approval chain in level order.
+type DiscountLimit struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	ProjectID     *string   `json:"project_id"`
+	Role          string    `json:"role"`
+	MaxAmount     float64   `json:"max_amount"`
+	MaxPerSqft    float64   `json:"max_per_sqft"`   // 0 = no per-sqft cap
+	Unlimited     bool      `json:"unlimited"`      // typically the director
+	ApprovalLevel int       `json:"approval_level"` // 0 = not an approver; 1 sales manager, 2 VP, 3 director
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DiscountApprovalStep is one level of a discount request's approval chain
+type DiscountApprovalStep struct {
+	ID           string     `json:"id"`
+	RequestID    string     `json:"request_id"`
+	Level        int        `json:"level"`
+	ApproverRole string     `json:"approver_role"`
+	Status       string     `json:"status"` // pending, approved, rejected
+	ActedBy      *string    `json:"acted_by"`
+	ActedAt      *time.Time `json:"acted_at"`
+	Comment      string     `json:"comment,omitempty"`
+}
+
+// DiscountRequest is a discount asked for on a unit's cost sheet or a quotation
+type DiscountRequest struct {
+	ID               string                 `json:"id"`
+	TenantID         string                 `json:"tenant_id"`
+	ProjectID        string                 `json:"project_id,omitempty"`
+	UnitID           string                 `json:"unit_id,omitempty"`
+	QuotationID      string                 `json:"quotation_id,omitempty"`
+	PriceListID      string                 `json:"price_list_id,omitempty"`
+	RequestedBy      string                 `json:"requested_by"`
+	RequesterRole    string                 `json:"requester_role"`
+	SBUA             float64                `json:"sbua"`
+	ListPrice        float64                `json:"list_price"` // cost sheet agreement value or quotation subtotal
+	DiscountAmount   float64                `json:"discount_amount"`
+	DiscountPerSqft  float64                `json:"discount_per_sqft"`
+	ApprovedPrice    float64                `json:"approved_price"`
+	Reason           string                 `json:"reason"`
+	Status           string                 `json:"status"`
+	CurrentLevel     int                    `json:"current_level"` // level awaiting action, 0 when none
+	UsedByBookingID  *string                `json:"used_by_booking_id"`
+	UsedBySalesOrder *string                `json:"used_by_sales_order_id"`
+	Steps            []DiscountApprovalStep `json:"steps"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// SetDiscountLimitRequest creates or replaces a role's limit
+type SetDiscountLimitRequest struct {
+	ProjectID     string  `json:"project_id"` // empty for all projects
+	Role          string  `json:"role" binding:"required"`
+	MaxAmount     float64 `json:"max_amount"`
+	MaxPerSqft    float64 `json:"max_per_sqft"`
+	Unlimited     bool    `json:"unlimited"`
+	ApprovalLevel int     `json:"approval_level"`
+}
+
+// CreateDiscountRequest asks for a discount on a unit or a quotation
+type CreateDiscountRequest struct {
+	UnitID          string  `json:"unit_id"`
+	QuotationID     string  `json:"quotation_id"`
+	DiscountAmount  float64 `json:"discount_amount"`   // either the amount
+	DiscountPerSqft float64 `json:"discount_per_sqft"` // or a per-sqft discount on a unit
+	Reason          string  `json:"reason" binding:"required"`
+	AsOf            string  `json:"as_of"`        // price list date, defaults to today
+	ParkingType     string  `json:"parking_type"` // cost sheet options for the list price
+	ParkingSlots    int     `json:"parking_slots"`
+}
+
+// DiscountDecisionRequest approves or rejects the pending step
+type DiscountDecisionRequest struct {
+	Approve bool   `json:"approve"`
+	Comment string `json:"comment"` // required when rejecting
+}
@@ -0,0 +1,299 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// GST E-INVOICE (IRN) AND E-WAY BILL MODELS
+// ============================================================================
+
+// IRP / e-way bill providers
+const (
+	IRPProviderStub = "stub" // local signer for development and tests
+	IRPProviderGSP  = "gsp"  // GST Suvidha Provider JSON API
+)
+
+// E-invoice and e-way bill statuses
+const (
+	EDocumentStatusGenerated = "generated"
+	EDocumentStatusCancelled = "cancelled"
+	EDocumentStatusFailed    = "failed"
+)
+
+// IRN cancellation reason codes (CnlRsn)
+const (
+	IRNCancelDuplicate      = "1"
+	IRNCancelDataEntry      = "2"
+	IRNCancelOrderCancelled = "3"
+	IRNCancelOthers         = "4"
+)
+
+// EInvoiceSettings holds a tenant's seller profile and IRP connection
+type EInvoiceSettings struct {
+	ID           string    `json:"id"`
+	TenantID     string    `json:"tenant_id"`
+	Provider     string    `json:"provider"` // stub, gsp
+	APIBaseURL   string    `json:"api_base_url"`
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"-"`
+	Username     string    `json:"username"`
+	Password     string    `json:"-"`
+	SellerGSTIN  string    `json:"seller_gstin"`
+	LegalName    string    `json:"legal_name"`
+	TradeName    string    `json:"trade_name"`
+	Address1     string    `json:"address1"`
+	Address2     string    `json:"address2"`
+	Location     string    `json:"location"`
+	Pincode      int       `json:"pincode"`
+	StateCode    string    `json:"state_code"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ============================================================================
+// INV-01 SCHEMA
+// ============================================================================
+
+// EInvoiceTranDetails is TranDtls of INV-01
+type EInvoiceTranDetails struct {
+	TaxSch      string  `json:"TaxSch"` // GST
+	SupTyp      string  `json:"SupTyp"` // B2B, SEZWP, SEZWOP, EXPWP, EXPWOP, DEXP
+	RegRev      string  `json:"RegRev"` // Y / N
+	EcmGstin    *string `json:"EcmGstin"`
+	IgstOnIntra string  `json:"IgstOnIntra"` // Y / N
+}
+
+// EInvoiceDocDetails is DocDtls of INV-01
+type EInvoiceDocDetails struct {
+	Typ string `json:"Typ"` // INV, CRN, DBN
+	No  string `json:"No"`
+	Dt  string `json:"Dt"` // dd/mm/yyyy
+}
+
+// EInvoiceParty is SellerDtls / BuyerDtls of INV-01
+type EInvoiceParty struct {
+	Gstin string `json:"Gstin"`
+	LglNm string `json:"LglNm"`
+	TrdNm string `json:"TrdNm,omitempty"`
+	Pos   string `json:"Pos,omitempty"` // buyer only
+	Addr1 string `json:"Addr1"`
+	Addr2 string `json:"Addr2,omitempty"`
+	Loc   string `json:"Loc"`
+	Pin   int    `json:"Pin"`
+	Stcd  string `json:"Stcd"`
+}
+
+// EInvoiceItem is one ItemList entry of INV-01
+type EInvoiceItem struct {
+	SlNo       string  `json:"SlNo"`
+	PrdDesc    string  `json:"PrdDesc"`
+	IsServc    string  `json:"IsServc"` // Y / N
+	HsnCd      string  `json:"HsnCd"`
+	Qty        float64 `json:"Qty"`
+	Unit       string  `json:"Unit"`
+	UnitPrice  float64 `json:"UnitPrice"`
+	TotAmt     float64 `json:"TotAmt"`
+	Discount   float64 `json:"Discount"`
+	AssAmt     float64 `json:"AssAmt"`
+	GstRt      float64 `json:"GstRt"`
+	IgstAmt    float64 `json:"IgstAmt"`
+	CgstAmt    float64 `json:"CgstAmt"`
+	SgstAmt    float64 `json:"SgstAmt"`
+	TotItemVal float64 `json:"TotItemVal"`
+}
+
+// EInvoiceValueDetails is ValDtls of INV-01
+type EInvoiceValueDetails struct {
+	AssVal    float64 `json:"AssVal"`
+	CgstVal   float64 `json:"CgstVal"`
+	SgstVal   float64 `json:"SgstVal"`
+	IgstVal   float64 `json:"IgstVal"`
+	Discount  float64 `json:"Discount"`
+	RndOffAmt float64 `json:"RndOffAmt"`
+	TotInvVal float64 `json:"TotInvVal"`
+}
+
+// EInvoicePayload is the INV-01 document submitted to the IRP
+type EInvoicePayload struct {
+	Version    string               `json:"Version"`
+	TranDtls   EInvoiceTranDetails  `json:"TranDtls"`
+	DocDtls    EInvoiceDocDetails   `json:"DocDtls"`
+	SellerDtls EInvoiceParty        `json:"SellerDtls"`
+	BuyerDtls  EInvoiceParty        `json:"BuyerDtls"`
+	ItemList   []EInvoiceItem       `json:"ItemList"`
+	ValDtls    EInvoiceValueDetails `json:"ValDtls"`
+}
+
+// IRNResponse is the IRP's reply to a successful generation
+type IRNResponse struct {
+	AckNo         int64  `json:"AckNo"`
+	AckDt         string `json:"AckDt"` // yyyy-mm-dd hh:mm:ss
+	Irn           string `json:"Irn"`
+	SignedInvoice string `json:"SignedInvoice"`
+	SignedQRCode  string `json:"SignedQRCode"`
+	Status        string `json:"Status"`
+}
+
+// IRNCancelResponse is the IRP's reply to a cancellation
+type IRNCancelResponse struct {
+	Irn        string `json:"Irn"`
+	CancelDate string `json:"CancelDate"`
+}
+
+// SalesEInvoice is the IRN registered against a sales invoice
+type SalesEInvoice struct {
+	ID               string     `json:"id"`
+	TenantID         string     `json:"tenant_id"`
+	InvoiceID        string     `json:"invoice_id"`
+	InvoiceNumber    string     `json:"invoice_number"`
+	IRN              string     `json:"irn"`
+	AckNo            string     `json:"ack_no"`
+	AckDate          *time.Time `json:"ack_date"`
+	SignedInvoice    string     `json:"signed_invoice,omitempty"`
+	SignedQRCode     string     `json:"signed_qr_code"`
+	Status           string     `json:"status"` // generated, cancelled, failed
+	ErrorMessage     string     `json:"error_message,omitempty"`
+	CancelReasonCode string     `json:"cancel_reason_code,omitempty"`
+	CancelRemarks    string     `json:"cancel_remarks,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	CreatedBy        string     `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ============================================================================
+// E-WAY BILL SCHEMA
+// ============================================================================
+
+// EWayBillItem is one itemList entry of the e-way bill request
+type EWayBillItem struct {
+	ProductName   string  `json:"productName"`
+	ProductDesc   string  `json:"productDesc"`
+	HSNCode       string  `json:"hsnCode"`
+	Quantity      float64 `json:"quantity"`
+	QtyUnit       string  `json:"qtyUnit"`
+	CGSTRate      float64 `json:"cgstRate"`
+	SGSTRate      float64 `json:"sgstRate"`
+	IGSTRate      float64 `json:"igstRate"`
+	CessRate      float64 `json:"cessRate"`
+	TaxableAmount float64 `json:"taxableAmount"`
+}
+
+// EWayBillPayload is the e-way bill generation request
+type EWayBillPayload struct {
+	SupplyType       string         `json:"supplyType"`    // O = outward
+	SubSupplyType    string         `json:"subSupplyType"` // 1 = supply, 5 = for own use
+	DocType          string         `json:"docType"`       // INV, CHL
+	DocNo            string         `json:"docNo"`
+	DocDate          string         `json:"docDate"` // dd/mm/yyyy
+	FromGSTIN        string         `json:"fromGstin"`
+	FromTradeName    string         `json:"fromTrdName"`
+	FromAddr1        string         `json:"fromAddr1"`
+	FromPlace        string         `json:"fromPlace"`
+	FromPincode      int            `json:"fromPincode"`
+	ActFromStateCode int            `json:"actFromStateCode"`
+	FromStateCode    int            `json:"fromStateCode"`
+	ToGSTIN          string         `json:"toGstin"`
+	ToTradeName      string         `json:"toTrdName"`
+	ToAddr1          string         `json:"toAddr1"`
+	ToPlace          string         `json:"toPlace"`
+	ToPincode        int            `json:"toPincode"`
+	ActToStateCode   int            `json:"actToStateCode"`
+	ToStateCode      int            `json:"toStateCode"`
+	TransactionType  int            `json:"transactionType"` // 1 = regular
+	TotalValue       float64        `json:"totalValue"`
+	CGSTValue        float64        `json:"cgstValue"`
+	SGSTValue        float64        `json:"sgstValue"`
+	IGSTValue        float64        `json:"igstValue"`
+	CessValue        float64        `json:"cessValue"`
+	TotInvValue      float64        `json:"totInvValue"`
+	TransporterID    string         `json:"transporterId,omitempty"`
+	TransporterName  string         `json:"transporterName,omitempty"`
+	TransMode        string         `json:"transMode"` // 1 road, 2 rail, 3 air, 4 ship
+	TransDistance    string         `json:"transDistance"`
+	VehicleNo        string         `json:"vehicleNo,omitempty"`
+	VehicleType      string         `json:"vehicleType,omitempty"` // R = regular
+	ItemList         []EWayBillItem `json:"itemList"`
+}
+
+// EWayBillResponse is the reply to a successful e-way bill generation
+type EWayBillResponse struct {
+	EWayBillNo   int64  `json:"ewayBillNo"`
+	EWayBillDate string `json:"ewayBillDate"` // dd/mm/yyyy hh:mm:ss AM
+	ValidUpto    string `json:"validUpto"`
+}
+
+// EWayBillCancelResponse is the reply to an e-way bill cancellation
+type EWayBillCancelResponse struct {
+	EWayBillNo int64  `json:"ewayBillNo"`
+	CancelDate string `json:"cancelDate"`
+}
+
+// EWayBill is an e-way bill raised for a stock movement or sale
+type EWayBill struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	SourceType     string     `json:"source_type"` // inventory_transfer
+	SourceID       string     `json:"source_id"`
+	DocumentNumber string     `json:"document_number"`
+	EWayBillNo     string     `json:"eway_bill_no"`
+	EWayBillDate   *time.Time `json:"eway_bill_date"`
+	ValidUpto      *time.Time `json:"valid_upto"`
+	VehicleNo      string     `json:"vehicle_no"`
+	TransportMode  string     `json:"transport_mode"`
+	DistanceKm     int        `json:"distance_km"`
+	TotalValue     float64    `json:"total_value"`
+	Status         string     `json:"status"` // generated, cancelled, failed
+	ErrorMessage   string     `json:"error_message,omitempty"`
+	CancelReason   string     `json:"cancel_reason,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ============================================================================
+// REQUEST/RESPONSE MODELS
+// ============================================================================
+
+// UpsertEInvoiceSettingsRequest sets the seller profile and IRP connection
+type UpsertEInvoiceSettingsRequest struct {
+	Provider     string `json:"provider" binding:"required"`
+	APIBaseURL   string `json:"api_base_url"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	SellerGSTIN  string `json:"seller_gstin" binding:"required"`
+	LegalName    string `json:"legal_name" binding:"required"`
+	TradeName    string `json:"trade_name"`
+	Address1     string `json:"address1" binding:"required"`
+	Address2     string `json:"address2"`
+	Location     string `json:"location" binding:"required"`
+	Pincode      int    `json:"pincode" binding:"required"`
+}
+
+// CancelIRNRequest cancels an IRN within the 24-hour window
+type CancelIRNRequest struct {
+	ReasonCode string `json:"reason_code" binding:"required"` // 1 duplicate, 2 data entry mistake, 3 order cancelled, 4 others
+	Remarks    string `json:"remarks" binding:"required"`
+}
+
+// GenerateTransferEWayBillRequest raises an e-way bill for an inter-site stock transfer
+type GenerateTransferEWayBillRequest struct {
+	ToGSTIN         string  `json:"to_gstin"` // defaults to the seller GSTIN for same-state sites
+	TransporterID   string  `json:"transporter_id"`
+	TransporterName string  `json:"transporter_name"`
+	TransportMode   string  `json:"transport_mode"` // 1 road (default), 2 rail, 3 air, 4 ship
+	DistanceKm      int     `json:"distance_km" binding:"required"`
+	VehicleNo       string  `json:"vehicle_no"`
+	GSTRate         float64 `json:"gst_rate"` // 0 for branch transfers within the same GSTIN
+}
+
+// CancelEWayBillRequest cancels an e-way bill within the 24-hour window
+type CancelEWayBillRequest struct {
+	ReasonCode int    `json:"reason_code" binding:"required"` // 1 duplicate, 2 order cancelled, 3 data entry mistake, 4 others
+	Remarks    string `json:"remarks" binding:"required"`
+}
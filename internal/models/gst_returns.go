@@ -0,0 +1,257 @@
+package models
+
+// ============================================================================
+// GST RETURN MODELS (GSTR-1 / GSTR-3B)
+// ============================================================================
+// JSON keys follow the GSTN offline tool / API schema so the payload can be
+// uploaded to the portal as-is.
+
+// GST document types recorded in gst_invoice_tracking.document_type
+const (
+	GSTDocumentInvoice    = "INV"
+	GSTDocumentCreditNote = "CRN"
+	GSTDocumentDebitNote  = "DBN"
+)
+
+// Return validation severities
+const (
+	GSTIssueError   = "error"
+	GSTIssueWarning = "warning"
+)
+
+// GSTItemDetail is the tax break-up of one rate line (itm_det)
+type GSTItemDetail struct {
+	TaxableValue float64 `json:"txval"`
+	Rate         float64 `json:"rt"`
+	IGSTAmount   float64 `json:"iamt"`
+	CGSTAmount   float64 `json:"camt"`
+	SGSTAmount   float64 `json:"samt"`
+	CessAmount   float64 `json:"csamt"`
+}
+
+// GSTItem is a numbered rate line on an invoice or note
+type GSTItem struct {
+	Num    int           `json:"num"`
+	Detail GSTItemDetail `json:"itm_det"`
+}
+
+// GSTR1Invoice is an invoice reported under B2B or B2CL
+type GSTR1Invoice struct {
+	InvoiceNumber string    `json:"inum"`
+	InvoiceDate   string    `json:"idt"` // dd-mm-yyyy
+	Value         float64   `json:"val"`
+	PlaceOfSupply string    `json:"pos,omitempty"`
+	ReverseCharge string    `json:"rchrg,omitempty"`   // Y / N
+	InvoiceType   string    `json:"inv_typ,omitempty"` // R = regular
+	Items         []GSTItem `json:"itms"`
+}
+
+// GSTR1B2B groups registered-recipient invoices by counterparty GSTIN
+type GSTR1B2B struct {
+	CounterpartyGSTIN string         `json:"ctin"`
+	Invoices          []GSTR1Invoice `json:"inv"`
+}
+
+// GSTR1B2CL groups large inter-state unregistered invoices by place of supply
+type GSTR1B2CL struct {
+	PlaceOfSupply string         `json:"pos"`
+	Invoices      []GSTR1Invoice `json:"inv"`
+}
+
+// GSTR1B2CS is a rate-wise summary of small unregistered supplies
+type GSTR1B2CS struct {
+	SupplyType    string  `json:"sply_ty"` // INTER, INTRA
+	Rate          float64 `json:"rt"`
+	Type          string  `json:"typ"` // OE = other than e-commerce
+	PlaceOfSupply string  `json:"pos"`
+	TaxableValue  float64 `json:"txval"`
+	IGSTAmount    float64 `json:"iamt"`
+	CGSTAmount    float64 `json:"camt"`
+	SGSTAmount    float64 `json:"samt"`
+	CessAmount    float64 `json:"csamt"`
+}
+
+// GSTR1Note is a credit or debit note issued to a registered recipient
+type GSTR1Note struct {
+	NoteType      string    `json:"ntty"` // C = credit, D = debit
+	NoteNumber    string    `json:"nt_num"`
+	NoteDate      string    `json:"nt_dt"`
+	Value         float64   `json:"val"`
+	PlaceOfSupply string    `json:"pos"`
+	ReverseCharge string    `json:"rchrg"`
+	InvoiceType   string    `json:"inv_typ"`
+	Items         []GSTItem `json:"itms"`
+}
+
+// GSTR1CDNR groups registered-recipient notes by counterparty GSTIN
+type GSTR1CDNR struct {
+	CounterpartyGSTIN string      `json:"ctin"`
+	Notes             []GSTR1Note `json:"nt"`
+}
+
+// GSTR1HSNRow is one line of the HSN-wise summary
+type GSTR1HSNRow struct {
+	Num          int     `json:"num"`
+	HSNCode      string  `json:"hsn_sc"`
+	Description  string  `json:"desc"`
+	UQC          string  `json:"uqc"`
+	Quantity     float64 `json:"qty"`
+	TaxableValue float64 `json:"txval"`
+	IGSTAmount   float64 `json:"iamt"`
+	CGSTAmount   float64 `json:"camt"`
+	SGSTAmount   float64 `json:"samt"`
+	CessAmount   float64 `json:"csamt"`
+	Rate         float64 `json:"rt"`
+}
+
+// GSTR1HSN wraps the HSN summary rows
+type GSTR1HSN struct {
+	Data []GSTR1HSNRow `json:"data"`
+}
+
+// GSTR1Return is the GSTR-1 upload payload
+type GSTR1Return struct {
+	GSTIN        string      `json:"gstin"`
+	FilingPeriod string      `json:"fp"` // MMYYYY
+	B2B          []GSTR1B2B  `json:"b2b"`
+	B2CL         []GSTR1B2CL `json:"b2cl"`
+	B2CS         []GSTR1B2CS `json:"b2cs"`
+	CDNR         []GSTR1CDNR `json:"cdnr"`
+	HSN          GSTR1HSN    `json:"hsn"`
+}
+
+// GSTR3BAmounts is a tax block used across GSTR-3B tables
+type GSTR3BAmounts struct {
+	TaxableValue float64 `json:"txval,omitempty"`
+	IGSTAmount   float64 `json:"iamt"`
+	CGSTAmount   float64 `json:"camt"`
+	SGSTAmount   float64 `json:"samt"`
+	CessAmount   float64 `json:"csamt"`
+}
+
+// GSTR3BITCRow is one typed row of table 4 (ty: IMPG, IMPS, ISRC, ISD, OTH, RUL)
+type GSTR3BITCRow struct {
+	Type       string  `json:"ty"`
+	IGSTAmount float64 `json:"iamt"`
+	CGSTAmount float64 `json:"camt"`
+	SGSTAmount float64 `json:"samt"`
+	CessAmount float64 `json:"csamt"`
+}
+
+// GSTR3BSupplyDetails is table 3.1
+type GSTR3BSupplyDetails struct {
+	OutwardTaxable  GSTR3BAmounts `json:"osup_det"`
+	OutwardZero     GSTR3BAmounts `json:"osup_zero"`
+	OutwardNilExemp GSTR3BAmounts `json:"osup_nil_exmp"`
+	InwardReverse   GSTR3BAmounts `json:"isup_rev"`
+	OutwardNonGST   GSTR3BAmounts `json:"osup_nongst"`
+}
+
+// GSTR3BInterStateRow is a place-of-supply line of table 3.2
+type GSTR3BInterStateRow struct {
+	PlaceOfSupply string  `json:"pos"`
+	TaxableValue  float64 `json:"txval"`
+	IGSTAmount    float64 `json:"iamt"`
+}
+
+// GSTR3BInterState is table 3.2
+type GSTR3BInterState struct {
+	Unregistered []GSTR3BInterStateRow `json:"unreg_details"`
+	Composition  []GSTR3BInterStateRow `json:"comp_details"`
+	UINHolders   []GSTR3BInterStateRow `json:"uin_details"`
+}
+
+// GSTR3BITC is table 4
+type GSTR3BITC struct {
+	Available  []GSTR3BITCRow `json:"itc_avl"`
+	Reversed   []GSTR3BITCRow `json:"itc_rev"`
+	Net        GSTR3BAmounts  `json:"itc_net"`
+	Ineligible []GSTR3BITCRow `json:"itc_inelg"`
+}
+
+// GSTR3BReturn is the GSTR-3B upload payload
+type GSTR3BReturn struct {
+	GSTIN          string              `json:"gstin"`
+	ReturnPeriod   string              `json:"ret_period"` // MMYYYY
+	SupplyDetails  GSTR3BSupplyDetails `json:"sup_details"`
+	InterStateSupp GSTR3BInterState    `json:"inter_sup"`
+	ITCEligibility GSTR3BITC           `json:"itc_elg"`
+}
+
+// GSTReturnIssue is a validation finding on a source document
+type GSTReturnIssue struct {
+	Severity       string `json:"severity"` // error, warning
+	DocumentNumber string `json:"document_number,omitempty"`
+	Field          string `json:"field"`
+	Message        string `json:"message"`
+}
+
+// GSTR1Result wraps a generated GSTR-1 with its validation findings
+type GSTR1Result struct {
+	Period        string           `json:"period"`
+	DocumentCount int              `json:"document_count"`
+	Valid         bool             `json:"valid"`
+	Issues        []GSTReturnIssue `json:"issues"`
+	Return        *GSTR1Return     `json:"return"`
+}
+
+// GSTR3BResult wraps a generated GSTR-3B with its validation findings
+type GSTR3BResult struct {
+	Period           string           `json:"period"`
+	OutwardDocuments int              `json:"outward_documents"`
+	InwardDocuments  int              `json:"inward_documents"`
+	Valid            bool             `json:"valid"`
+	Issues           []GSTReturnIssue `json:"issues"`
+	Return           *GSTR3BReturn    `json:"return"`
+}
+
+// ============================================================================
+// REQUEST/RESPONSE MODELS
+// ============================================================================
+
+// RecordGSTOutwardDocumentRequest records a sales invoice or note with return detail
+type RecordGSTOutwardDocumentRequest struct {
+	InvoiceID             string  `json:"invoice_id" binding:"required"`
+	DocumentType          string  `json:"document_type"` // INV (default), CRN, DBN
+	InvoiceNumber         string  `json:"invoice_number" binding:"required"`
+	InvoiceDate           string  `json:"invoice_date" binding:"required"` // YYYY-MM-DD
+	InvoiceAmount         float64 `json:"invoice_amount" binding:"required"`
+	CustomerID            string  `json:"customer_id"`
+	CustomerName          string  `json:"customer_name"`
+	CustomerGSTIN         string  `json:"customer_gstin"`
+	PlaceOfSupply         string  `json:"place_of_supply"`
+	ReverseCharge         bool    `json:"reverse_charge"`
+	HSNCode               string  `json:"hsn_code"`
+	HSNDescription        string  `json:"hsn_description"`
+	UQC                   string  `json:"uqc"`
+	Quantity              float64 `json:"quantity"`
+	GSTRate               float64 `json:"gst_rate"`
+	TaxableValue          float64 `json:"taxable_value"`
+	IGSTAmount            float64 `json:"igst_amount"`
+	CGSTAmount            float64 `json:"cgst_amount"`
+	SGSTAmount            float64 `json:"sgst_amount"`
+	CessAmount            float64 `json:"cess_amount"`
+	OriginalInvoiceNumber string  `json:"original_invoice_number"`
+	OriginalInvoiceDate   string  `json:"original_invoice_date"`
+}
+
+// RecordGSTInwardDocumentRequest records a purchase invoice with ITC detail
+type RecordGSTInwardDocumentRequest struct {
+	PurchaseInvoiceID   string  `json:"purchase_invoice_id" binding:"required"`
+	VendorID            string  `json:"vendor_id"`
+	VendorGSTIN         string  `json:"vendor_gstin"`
+	InvoiceNumber       string  `json:"invoice_number" binding:"required"`
+	InvoiceDate         string  `json:"invoice_date" binding:"required"` // YYYY-MM-DD
+	InvoiceAmount       float64 `json:"invoice_amount" binding:"required"`
+	PlaceOfSupply       string  `json:"place_of_supply"`
+	ReverseCharge       bool    `json:"reverse_charge"`
+	GSTRate             float64 `json:"gst_rate"`
+	TaxableValue        float64 `json:"taxable_value"`
+	IGSTAmount          float64 `json:"igst_amount"`
+	CGSTAmount          float64 `json:"cgst_amount"`
+	SGSTAmount          float64 `json:"sgst_amount"`
+	CessAmount          float64 `json:"cess_amount"`
+	ITCEligible         bool    `json:"itc_eligible"`
+	ITCIneligibleReason string  `json:"itc_ineligible_reason"`
+	BlockedPercentage   float64 `json:"blocked_percentage"`
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// GSTR-2B IMPORT AND ITC RECONCILIATION MODELS
+// ============================================================================
+
+// ITC reconciliation buckets
+const (
+	ITCReconMatched        = "matched"
+	ITCReconMismatched     = "mismatched"
+	ITCReconMissingIn2B    = "missing_in_2b"
+	ITCReconMissingInBooks = "missing_in_books"
+)
+
+// ITC reconciliation match types
+const (
+	ITCMatchExact = "exact" // same normalised invoice number
+	ITCMatchFuzzy = "fuzzy" // same numeric core and tax amount
+)
+
+// ITC mismatch reasons
+const (
+	ITCReasonTaxDifference     = "tax_amount_difference"
+	ITCReasonTaxableDifference = "taxable_value_difference"
+	ITCReasonDateDifference    = "invoice_date_difference"
+	ITCReasonNotAvailable      = "itc_not_available_in_2b"
+	ITCReasonNoVendorGSTIN     = "vendor_gstin_not_recorded"
+)
+
+// ============================================================================
+// GSTR-2B SCHEMA (portal download)
+// ============================================================================
+
+// GSTR2BItem is a rate line of a 2B document
+type GSTR2BItem struct {
+	Num          int     `json:"num"`
+	Rate         float64 `json:"rt"`
+	TaxableValue float64 `json:"txval"`
+	IGSTAmount   float64 `json:"igst"`
+	CGSTAmount   float64 `json:"cgst"`
+	SGSTAmount   float64 `json:"sgst"`
+	CessAmount   float64 `json:"cess"`
+}
+
+// GSTR2BInvoice is an invoice in the 2B B2B section
+type GSTR2BInvoice struct {
+	InvoiceNumber string       `json:"inum"`
+	Type          string       `json:"typ"`
+	Date          string       `json:"dt"` // dd-mm-yyyy
+	Value         float64      `json:"val"`
+	PlaceOfSupply string       `json:"pos"`
+	ReverseCharge string       `json:"rev"`    // Y / N
+	ITCAvailable  string       `json:"itcavl"` // Y / N / T
+	Reason        string       `json:"rsn"`
+	Items         []GSTR2BItem `json:"items"`
+}
+
+// GSTR2BNote is a credit or debit note in the 2B CDNR section
+type GSTR2BNote struct {
+	NoteNumber    string       `json:"ntnum"`
+	Type          string       `json:"typ"` // C / D
+	Date          string       `json:"dt"`
+	Value         float64      `json:"val"`
+	PlaceOfSupply string       `json:"pos"`
+	ReverseCharge string       `json:"rev"`
+	ITCAvailable  string       `json:"itcavl"`
+	Reason        string       `json:"rsn"`
+	Items         []GSTR2BItem `json:"items"`
+}
+
+// GSTR2BSupplier groups a supplier's documents
+type GSTR2BSupplier struct {
+	SupplierGSTIN string          `json:"ctin"`
+	TradeName     string          `json:"trdnm"`
+	FilingDate    string          `json:"supfildt"`
+	FilingPeriod  string          `json:"supprd"`
+	Invoices      []GSTR2BInvoice `json:"inv,omitempty"`
+	Notes         []GSTR2BNote    `json:"nt,omitempty"`
+}
+
+// GSTR2BFile is the GSTR-2B JSON downloaded from the GST portal
+type GSTR2BFile struct {
+	Data struct {
+		GSTIN         string `json:"gstin"`
+		ReturnPeriod  string `json:"rtnprd"` // MMYYYY
+		GeneratedDate string `json:"gendt"`
+		DocData       struct {
+			B2B  []GSTR2BSupplier `json:"b2b"`
+			CDNR []GSTR2BSupplier `json:"cdnr"`
+		} `json:"docdata"`
+	} `json:"data"`
+}
+
+// ============================================================================
+// STORED DOCUMENTS AND RECONCILIATION
+// ============================================================================
+
+// GSTR2BImport is one uploaded 2B statement
+type GSTR2BImport struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	GSTIN         string    `json:"gstin"`
+	ReturnPeriod  string    `json:"return_period"`
+	GeneratedDate string    `json:"generated_date"`
+	DocumentCount int       `json:"document_count"`
+	TotalTaxable  float64   `json:"total_taxable"`
+	TotalTax      float64   `json:"total_tax"`
+	ImportedBy    string    `json:"imported_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// GSTR2BDocument is a supplier document flattened from a 2B statement
+type GSTR2BDocument struct {
+	ID               string     `json:"id"`
+	TenantID         string     `json:"tenant_id"`
+	ImportID         string     `json:"import_id"`
+	ReturnPeriod     string     `json:"return_period"`
+	SupplierGSTIN    string     `json:"supplier_gstin"`
+	SupplierName     string     `json:"supplier_name"`
+	DocumentType     string     `json:"document_type"` // INV, CRN, DBN
+	DocumentNumber   string     `json:"document_number"`
+	DocumentDate     *time.Time `json:"document_date"`
+	DocumentValue    float64    `json:"document_value"`
+	TaxableValue     float64    `json:"taxable_value"`
+	IGSTAmount       float64    `json:"igst_amount"`
+	CGSTAmount       float64    `json:"cgst_amount"`
+	SGSTAmount       float64    `json:"sgst_amount"`
+	CessAmount       float64    `json:"cess_amount"`
+	ITCAvailable     bool       `json:"itc_available"`
+	ReverseCharge    bool       `json:"reverse_charge"`
+	Reason           string     `json:"reason,omitempty"`
+	SupplierFiledOn  string     `json:"supplier_filed_on"`
+	SupplierFilingPd string     `json:"supplier_filing_period"`
+}
+
+// TotalTax is the document's IGST + CGST + SGST + cess
+func (d *GSTR2BDocument) TotalTax() float64 {
+	return d.IGSTAmount + d.CGSTAmount + d.SGSTAmount + d.CessAmount
+}
+
+// ITCBooksInvoice is a vendor invoice as recorded in the books
+type ITCBooksInvoice struct {
+	InvoiceID     string    `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	InvoiceDate   time.Time `json:"invoice_date"`
+	VendorID      string    `json:"vendor_id"`
+	VendorName    string    `json:"vendor_name"`
+	VendorGSTIN   string    `json:"vendor_gstin"`
+	TaxableValue  float64   `json:"taxable_value"`
+	TaxAmount     float64   `json:"tax_amount"`
+}
+
+// ITCReconciliationLine pairs a books invoice with a 2B document (either side may be empty)
+type ITCReconciliationLine struct {
+	Status             string     `json:"status"`
+	MatchType          string     `json:"match_type,omitempty"`
+	SupplierGSTIN      string     `json:"supplier_gstin"`
+	VendorID           string     `json:"vendor_id,omitempty"`
+	VendorName         string     `json:"vendor_name"`
+	BooksInvoiceID     string     `json:"books_invoice_id,omitempty"`
+	BooksInvoiceNumber string     `json:"books_invoice_number,omitempty"`
+	BooksInvoiceDate   *time.Time `json:"books_invoice_date,omitempty"`
+	BooksTaxable       float64    `json:"books_taxable"`
+	BooksTax           float64    `json:"books_tax"`
+	GSTR2BDocumentID   string     `json:"gstr2b_document_id,omitempty"`
+	GSTR2BNumber       string     `json:"gstr2b_number,omitempty"`
+	GSTR2BDate         *time.Time `json:"gstr2b_date,omitempty"`
+	GSTR2BTaxable      float64    `json:"gstr2b_taxable"`
+	GSTR2BTax          float64    `json:"gstr2b_tax"`
+	TaxDifference      float64    `json:"tax_difference"` // books - 2B
+	ITCAtRisk          float64    `json:"itc_at_risk"`
+	Reasons            []string   `json:"reasons"`
+}
+
+// ITCReconciliationSummary totals each bucket
+type ITCReconciliationSummary struct {
+	MatchedCount        int     `json:"matched_count"`
+	MismatchedCount     int     `json:"mismatched_count"`
+	MissingIn2BCount    int     `json:"missing_in_2b_count"`
+	MissingInBooksCount int     `json:"missing_in_books_count"`
+	BooksITC            float64 `json:"books_itc"`
+	GSTR2BITC           float64 `json:"gstr2b_itc"`
+	MatchedITC          float64 `json:"matched_itc"`
+	ITCAtRisk           float64 `json:"itc_at_risk"`
+	UnbookedITC         float64 `json:"unbooked_itc"` // in 2B but not in books
+}
+
+// ITCReconciliationResult is the outcome of reconciling a period
+type ITCReconciliationResult struct {
+	Period         string                   `json:"period"`
+	Summary        ITCReconciliationSummary `json:"summary"`
+	Matched        []ITCReconciliationLine  `json:"matched"`
+	Mismatched     []ITCReconciliationLine  `json:"mismatched"`
+	MissingIn2B    []ITCReconciliationLine  `json:"missing_in_2b"`
+	MissingInBooks []ITCReconciliationLine  `json:"missing_in_books"`
+}
+
+// ITCAtRiskVendor is one vendor's share of the ITC at risk
+type ITCAtRiskVendor struct {
+	SupplierGSTIN string  `json:"supplier_gstin"`
+	VendorName    string  `json:"vendor_name"`
+	DocumentCount int     `json:"document_count"`
+	ITCAtRisk     float64 `json:"itc_at_risk"`
+}
+
+// ITCAtRiskReport is the month's ITC exposure from reconciliation
+type ITCAtRiskReport struct {
+	Period         string            `json:"period"`
+	BooksITC       float64           `json:"books_itc"`
+	MatchedITC     float64           `json:"matched_itc"`
+	MissingIn2BITC float64           `json:"missing_in_2b_itc"`
+	MismatchITC    float64           `json:"mismatch_itc"`
+	TotalITCAtRisk float64           `json:"total_itc_at_risk"`
+	UnbookedITC    float64           `json:"unbooked_itc"`
+	Vendors        []ITCAtRiskVendor `json:"vendors"`
+	ReconciledAt   *time.Time        `json:"reconciled_at"`
+}
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// LAND BANK & JOINT DEVELOPMENT AGREEMENT MODELS
+// ============================================================================
+// Land is acquired ahead of a project either outright or under a joint
+// development agreement (JDA), where landowners contribute the land for a share
+// of the built-up area (units allotted to them) or of the sales revenue.
+
+// SqftPerAcre converts parcel area to square feet for FSI calculations
+const SqftPerAcre = 43560.0
+
+// Land parcel statuses
+const (
+	LandParcelIdentified       = "identified"
+	LandParcelUnderNegotiation = "under_negotiation"
+	LandParcelAcquired         = "acquired"
+	LandParcelUnderJDA         = "under_jda"
+	LandParcelDropped          = "dropped"
+)
+
+// Land acquisition modes
+const (
+	LandAcquisitionOutright = "outright"
+	LandAcquisitionJDA      = "jda"
+)
+
+// Land acquisition cost types
+const (
+	LandCostLandPrice    = "land_price"
+	LandCostStampDuty    = "stamp_duty"
+	LandCostRegistration = "registration"
+	LandCostBrokerage    = "brokerage"
+	LandCostConversion   = "conversion"
+	LandCostLegal        = "legal"
+	LandCostJDADeposit   = "jda_deposit" // refundable deposit paid to landowners
+	LandCostOther        = "other"
+)
+
+// JDA share types
+const (
+	JDAShareArea    = "area"    // landowners get a share of the saleable area as units
+	JDAShareRevenue = "revenue" // landowners get a share of collections
+	JDAShareHybrid  = "hybrid"  // both
+)
+
+// JDA and allocation statuses
+const (
+	JDAStatusActive = "active"
+
+	JDAAllocationAllotted = "allotted"
+	JDAAllocationReleased = "released"
+)
+
+// LandParcel is a parcel in the land bank
+type LandParcel struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	ParcelCode      string     `json:"parcel_code"`
+	SurveyNumber    string     `json:"survey_number"`
+	SubDivision     string     `json:"sub_division"`
+	Village         string     `json:"village"`
+	Taluk           string     `json:"taluk"`
+	District        string     `json:"district"`
+	State           string     `json:"state"`
+	AreaAcres       float64    `json:"area_acres"`
+	Zoning          string     `json:"zoning"` // residential, commercial, mixed_use, industrial, agricultural
+	PermissibleFSI  float64    `json:"permissible_fsi"`
+	BuildableArea   float64    `json:"buildable_area"` // sqft, area x FSI
+	AcquisitionMode string     `json:"acquisition_mode"`
+	Status          string     `json:"status"`
+	ProjectID       *string    `json:"project_id,omitempty"`
+	TitleParcelID   *int64     `json:"title_parcel_id,omitempty"` // title_parcels.id for chain-of-title tracking
+	AcquisitionCost float64    `json:"acquisition_cost"`
+	AmountPaid      float64    `json:"amount_paid"`
+	Outstanding     float64    `json:"outstanding"`
+	Notes           string     `json:"notes"`
+	Costs           []LandCost `json:"costs,omitempty"`
+	CreatedBy       *string    `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// LandCost is an acquisition cost accrued on a parcel
+type LandCost struct {
+	ID             string        `json:"id"`
+	TenantID       string        `json:"tenant_id"`
+	ParcelID       string        `json:"parcel_id"`
+	CostType       string        `json:"cost_type"`
+	Payee          string        `json:"payee"`
+	Description    string        `json:"description"`
+	CostDate       time.Time     `json:"cost_date"`
+	Amount         float64       `json:"amount"`
+	AmountPaid     float64       `json:"amount_paid"`
+	Outstanding    float64       `json:"outstanding"`
+	Payments       []LandPayment `json:"payments,omitempty"`
+	JournalEntryID *string       `json:"journal_entry_id,omitempty"`
+	CreatedBy      *string       `json:"created_by,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// LandPayment is a payment against an acquisition cost
+type LandPayment struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	ParcelID       string    `json:"parcel_id"`
+	CostID         string    `json:"cost_id"`
+	PaymentDate    time.Time `json:"payment_date"`
+	Amount         float64   `json:"amount"`
+	PaymentMode    string    `json:"payment_mode"`
+	Reference      string    `json:"reference"`
+	JournalEntryID *string   `json:"journal_entry_id,omitempty"`
+	CreatedBy      *string   `json:"created_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// JointDevelopmentAgreement is a JDA with the landowners of a parcel
+type JointDevelopmentAgreement struct {
+	ID                 string              `json:"id"`
+	TenantID           string              `json:"tenant_id"`
+	AgreementNumber    string              `json:"agreement_number"`
+	ParcelID           string              `json:"parcel_id"`
+	ProjectID          string              `json:"project_id"`
+	AgreementDate      time.Time           `json:"agreement_date"`
+	RegistrationNumber string              `json:"registration_number"`
+	ShareType          string              `json:"share_type"`         // area, revenue, hybrid
+	AreaSharePercent   float64             `json:"area_share_percent"` // landowners' share of saleable area
+	RevenueSharePct    float64             `json:"revenue_share_percent"`
+	Status             string              `json:"status"`
+	Landowners         []JDALandowner      `json:"landowners"`
+	Allocations        []JDAUnitAllocation `json:"allocations,omitempty"`
+	CreatedBy          *string             `json:"created_by,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// JDALandowner is a landowner party to a JDA and their share of the landowners' entitlement
+type JDALandowner struct {
+	ID           string  `json:"id"`
+	TenantID     string  `json:"tenant_id"`
+	JDAID        string  `json:"jda_id"`
+	Name         string  `json:"name"`
+	PAN          string  `json:"pan"`
+	SharePercent float64 `json:"share_percent"` // of the landowners' entitlement; all landowners add up to 100
+	BankAccount  string  `json:"bank_account"`
+}
+
+// JDAUnitAllocation is a unit allotted to a landowner in settlement of the area share
+type JDAUnitAllocation struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	JDAID         string     `json:"jda_id"`
+	LandownerID   string     `json:"landowner_id"`
+	LandownerName string     `json:"landowner_name"`
+	UnitID        string     `json:"unit_id"`
+	UnitNumber    string     `json:"unit_number"`
+	SBUA          float64    `json:"sbua"`
+	Status        string     `json:"status"` // allotted, released
+	AllottedAt    time.Time  `json:"allotted_at"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	CreatedBy     *string    `json:"created_by,omitempty"`
+}
+
+// JDAEntitlement is a landowner's area entitlement against the units allotted
+type JDAEntitlement struct {
+	LandownerID   string  `json:"landowner_id"`
+	LandownerName string  `json:"landowner_name"`
+	SharePercent  float64 `json:"share_percent"`
+	EntitledSBUA  float64 `json:"entitled_sbua"`
+	AllottedSBUA  float64 `json:"allotted_sbua"`
+	BalanceSBUA   float64 `json:"balance_sbua"`
+	UnitsAllotted int     `json:"units_allotted"`
+}
+
+// JDAAreaPosition is the project's saleable area split between developer and landowners
+type JDAAreaPosition struct {
+	JDAID            string           `json:"jda_id"`
+	ProjectSBUA      float64          `json:"project_sbua"`
+	AreaSharePercent float64          `json:"area_share_percent"`
+	LandownerSBUA    float64          `json:"landowner_sbua"`
+	AllottedSBUA     float64          `json:"allotted_sbua"`
+	Entitlements     []JDAEntitlement `json:"entitlements"`
+}
+
+// JDARevenuePayout is a landowner's share of collections for a period
+type JDARevenuePayout struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	PayoutNumber   string     `json:"payout_number"`
+	JDAID          string     `json:"jda_id"`
+	LandownerID    string     `json:"landowner_id"`
+	LandownerName  string     `json:"landowner_name"`
+	PeriodFrom     time.Time  `json:"period_from"`
+	PeriodTo       time.Time  `json:"period_to"` // exclusive
+	Collections    float64    `json:"collections"`
+	RevenueShare   float64    `json:"revenue_share_percent"`
+	LandownerShare float64    `json:"landowner_share_percent"`
+	Amount         float64    `json:"amount"`
+	PaymentMode    string     `json:"payment_mode,omitempty"`
+	Reference      string     `json:"reference,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	JournalEntryID *string    `json:"journal_entry_id,omitempty"`
+	CreatedBy      *string    `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreateLandParcelRequest adds a parcel to the land bank
+type CreateLandParcelRequest struct {
+	SurveyNumber    string  `json:"survey_number" validate:"required"`
+	SubDivision     string  `json:"sub_division"`
+	Village         string  `json:"village" validate:"required"`
+	Taluk           string  `json:"taluk"`
+	District        string  `json:"district"`
+	State           string  `json:"state"`
+	AreaAcres       float64 `json:"area_acres" validate:"required"`
+	Zoning          string  `json:"zoning"`
+	PermissibleFSI  float64 `json:"permissible_fsi"`
+	AcquisitionMode string  `json:"acquisition_mode"` // defaults to outright
+	TitleParcelID   *int64  `json:"title_parcel_id"`
+	Notes           string  `json:"notes"`
+}
+
+// UpdateLandParcelRequest updates a parcel's zoning, FSI, status or project
+type UpdateLandParcelRequest struct {
+	Zoning         *string  `json:"zoning"`
+	PermissibleFSI *float64 `json:"permissible_fsi"`
+	Status         *string  `json:"status"`
+	ProjectID      *string  `json:"project_id"`
+	Notes          *string  `json:"notes"`
+}
+
+// RecordLandCostRequest accrues an acquisition cost on a parcel
+type RecordLandCostRequest struct {
+	CostType    string     `json:"cost_type" validate:"required"`
+	Payee       string     `json:"payee" validate:"required"`
+	Description string     `json:"description"`
+	CostDate    *time.Time `json:"cost_date"` // defaults to today
+	Amount      float64    `json:"amount" validate:"required"`
+}
+
+// RecordLandPaymentRequest pays an acquisition cost
+type RecordLandPaymentRequest struct {
+	Amount      float64    `json:"amount" validate:"required"`
+	PaymentDate *time.Time `json:"payment_date"` // defaults to today
+	PaymentMode string     `json:"payment_mode" validate:"required"`
+	Reference   string     `json:"reference"`
+}
+
+// JDALandownerInput is a landowner in a JDA request
+type JDALandownerInput struct {
+	Name         string  `json:"name" validate:"required"`
+	PAN          string  `json:"pan"`
+	SharePercent float64 `json:"share_percent" validate:"required"`
+	BankAccount  string  `json:"bank_account"`
+}
+
+// CreateJDARequest records a joint development agreement on a parcel
+type CreateJDARequest struct {
+	ParcelID           string              `json:"parcel_id" validate:"required"`
+	ProjectID          string              `json:"project_id" validate:"required"`
+	AgreementDate      time.Time           `json:"agreement_date" validate:"required"`
+	RegistrationNumber string              `json:"registration_number"`
+	ShareType          string              `json:"share_type" validate:"required"`
+	AreaSharePercent   float64             `json:"area_share_percent"`
+	RevenueSharePct    float64             `json:"revenue_share_percent"`
+	Landowners         []JDALandownerInput `json:"landowners" validate:"required"`
+}
+
+// AllotLandownerUnitRequest allots a project unit to a landowner
+type AllotLandownerUnitRequest struct {
+	LandownerID string `json:"landowner_id" validate:"required"`
+	UnitID      string `json:"unit_id" validate:"required"`
+}
+
+// JDAPayoutRequest computes or pays the landowners' revenue share for a period
+type JDAPayoutRequest struct {
+	PeriodFrom  time.Time `json:"period_from" validate:"required"`
+	PeriodTo    time.Time `json:"period_to" validate:"required"` // exclusive
+	PaymentMode string    `json:"payment_mode"`
+	Reference   string    `json:"reference"`
+}
@@ -0,0 +1,180 @@
+package models
+
+import "time"
+
+// ============================================================================
+// HOME LOAN DISBURSEMENT REQUEST MODELS
+// ============================================================================
+// When a construction-linked demand is raised on a booking with a sanctioned
+// home loan, a disbursement request goes to the lender in its own format with
+// the demand letter, architect certificate and the customer's consent. Credits
+// received from the lender are matched back to the request, the booking's
+// BankDisbursement and the demanded payment stage.
+
+// Loan disbursement request statuses
+const (
+	LoanRequestPendingDocuments   = "pending_documents" // consent or a required document is outstanding
+	LoanRequestReady              = "ready"             // all documents in, can be sent to the lender
+	LoanRequestSubmitted          = "submitted"
+	LoanRequestPartiallyDisbursed = "partially_disbursed"
+	LoanRequestDisbursed          = "disbursed"
+	LoanRequestRejected           = "rejected"
+	LoanRequestCancelled          = "cancelled"
+)
+
+// Documents attached to a disbursement request
+const (
+	LoanDocDemandLetter         = "demand_letter"
+	LoanDocArchitectCertificate = "architect_certificate"
+	LoanDocCustomerConsent      = "customer_consent"
+)
+
+// Incoming disbursement match statuses
+const (
+	LoanCreditMatched   = "matched"
+	LoanCreditUnmatched = "unmatched"
+)
+
+// LenderRequestFormat is a bank's format for disbursement requests. Subject and
+// body take {{placeholders}} such as {{customer_name}}, {{loan_account}},
+// {{unit_number}}, {{stage_name}} and {{requested_amount}}.
+type LenderRequestFormat struct {
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	BankID            string    `json:"bank_id"`
+	FormatName        string    `json:"format_name"`
+	AddressedTo       string    `json:"addressed_to"`
+	SubjectTemplate   string    `json:"subject_template"`
+	BodyTemplate      string    `json:"body_template"`
+	RequiredDocuments []string  `json:"required_documents"` // in addition to demand letter, architect certificate and consent
+	SubmissionEmail   string    `json:"submission_email,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// LoanRequestDocument is a document sent with a disbursement request
+type LoanRequestDocument struct {
+	DocumentType string     `json:"document_type"`
+	Reference    string     `json:"reference,omitempty"` // letter number, certificate reference or file URL
+	Attached     bool       `json:"attached"`
+	AttachedAt   *time.Time `json:"attached_at,omitempty"`
+}
+
+// LoanDisbursementRequest is a request to the lender to release the loan-funded part of a demand
+type LoanDisbursementRequest struct {
+	ID               string                `json:"id"`
+	TenantID         string                `json:"tenant_id"`
+	RequestNumber    string                `json:"request_number"`
+	FinancingID      string                `json:"financing_id"`
+	BookingID        string                `json:"booking_id"`
+	BankID           string                `json:"bank_id"`
+	LoanAccountRef   string                `json:"loan_account_ref,omitempty"`
+	DemandLetterID   string                `json:"demand_letter_id"`
+	PlanStageID      string                `json:"plan_stage_id"`
+	ScheduleID       string                `json:"schedule_id"`
+	MilestoneID      *string               `json:"milestone_id,omitempty"`
+	StageName        string                `json:"stage_name"`
+	DemandAmount     float64               `json:"demand_amount"`
+	RequestedAmount  float64               `json:"requested_amount"`
+	DisbursedAmount  float64               `json:"disbursed_amount"`
+	Outstanding      float64               `json:"outstanding"`
+	DueDate          time.Time             `json:"due_date"`
+	AddressedTo      string                `json:"addressed_to"`
+	Subject          string                `json:"subject"`
+	Body             string                `json:"body"`
+	Documents        []LoanRequestDocument `json:"documents"`
+	ConsentAt        *time.Time            `json:"consent_at,omitempty"`
+	ConsentReference string                `json:"consent_reference,omitempty"`
+	Status           string                `json:"status"`
+	DisbursementID   *string               `json:"disbursement_id,omitempty"` // bank_disbursement row created on submission
+	SubmittedAt      *time.Time            `json:"submitted_at,omitempty"`
+	RejectionReason  string                `json:"rejection_reason,omitempty"`
+	CreatedBy        *string               `json:"created_by,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// IncomingLoanDisbursement is a credit received from a lender
+type IncomingLoanDisbursement struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	BankID         string    `json:"bank_id"`
+	Amount         float64   `json:"amount"`
+	CreditedOn     time.Time `json:"credited_on"`
+	BankReference  string    `json:"bank_reference"`
+	LoanAccountRef string    `json:"loan_account_ref,omitempty"`
+	BookingID      *string   `json:"booking_id,omitempty"`
+	RequestID      *string   `json:"request_id,omitempty"`
+	PaymentID      *string   `json:"payment_id,omitempty"` // booking_payments row recorded on match
+	MatchStatus    string    `json:"match_status"`         // matched, unmatched
+	MatchedBy      string    `json:"matched_by,omitempty"` // auto, manual
+	CreatedBy      *string   `json:"created_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// LoanFundingStage is one payment stage split between the loan and the customer
+type LoanFundingStage struct {
+	PlanStageID     string  `json:"plan_stage_id"`
+	StageName       string  `json:"stage_name"`
+	Amount          float64 `json:"amount"`
+	Status          string  `json:"status"`
+	LoanRequested   float64 `json:"loan_requested"`
+	LoanDisbursed   float64 `json:"loan_disbursed"`
+	OwnContribution float64 `json:"own_contribution"`
+	RequestStatus   string  `json:"request_status,omitempty"`
+}
+
+// LoanFundingSplit is a booking's loan-funded versus own-contribution position
+type LoanFundingSplit struct {
+	BookingID           string             `json:"booking_id"`
+	AgreementValue      float64            `json:"agreement_value"`
+	SanctionedAmount    float64            `json:"sanctioned_amount"`
+	LoanDisbursed       float64            `json:"loan_disbursed"`
+	LoanPending         float64            `json:"loan_pending"` // requested, not yet received
+	OwnContribution     float64            `json:"own_contribution"`
+	OwnContributionPaid float64            `json:"own_contribution_paid"`
+	OwnContributionDue  float64            `json:"own_contribution_due"` // on stages demanded so far
+	Stages              []LoanFundingStage `json:"stages"`
+}
+
+// SetLenderRequestFormatRequest creates or updates a bank's request format
+type SetLenderRequestFormatRequest struct {
+	BankID            string   `json:"bank_id" validate:"required"`
+	FormatName        string   `json:"format_name"`
+	AddressedTo       string   `json:"addressed_to"`
+	SubjectTemplate   string   `json:"subject_template" validate:"required"`
+	BodyTemplate      string   `json:"body_template" validate:"required"`
+	RequiredDocuments []string `json:"required_documents"`
+	SubmissionEmail   string   `json:"submission_email"`
+}
+
+// LoanConsentRequest records the customer's consent to a disbursement request
+type LoanConsentRequest struct {
+	ConsentReference string `json:"consent_reference"` // signed form URL or OTP reference
+}
+
+// AttachLoanDocumentRequest attaches a document to a disbursement request
+type AttachLoanDocumentRequest struct {
+	DocumentType string `json:"document_type" validate:"required"`
+	Reference    string `json:"reference" validate:"required"`
+}
+
+// RejectLoanRequestRequest records the lender's rejection of a disbursement request
+type RejectLoanRequestRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// RecordIncomingDisbursementRequest records a credit received from a lender
+type RecordIncomingDisbursementRequest struct {
+	BankID         string     `json:"bank_id" validate:"required"`
+	Amount         float64    `json:"amount" validate:"required"`
+	CreditedOn     *time.Time `json:"credited_on"` // defaults to today
+	BankReference  string     `json:"bank_reference" validate:"required"`
+	LoanAccountRef string     `json:"loan_account_ref"`
+	BookingID      string     `json:"booking_id"`
+}
+
+// MatchIncomingDisbursementRequest matches an unmatched credit to a request by hand
+type MatchIncomingDisbursementRequest struct {
+	RequestID string `json:"request_id" validate:"required"`
+}
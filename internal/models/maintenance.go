@@ -0,0 +1,225 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// MAINTENANCE BILLING & ASSOCIATION HANDOVER MODELS
+// ============================================================================
+// Units are enrolled for maintenance once possession is completed. Until the
+// residents' association takes over, the builder bills a one-time maintenance
+// deposit (corpus) and periodic common-area maintenance (CAM) and sinking fund
+// charges per sqft. At handover the accounts are closed with a statement and
+// the corpus and sinking fund collected are transferred to the association.
+
+// Maintenance billing frequencies
+const (
+	MaintenanceMonthly    = "monthly"
+	MaintenanceQuarterly  = "quarterly"
+	MaintenanceHalfYearly = "half_yearly"
+	MaintenanceAnnual     = "annual"
+)
+
+// Maintenance defaults; GST applies when a unit's monthly contribution exceeds the exempt limit
+const (
+	DefaultMaintenanceGSTRate   = 18.0
+	DefaultMaintenanceGSTExempt = 7500.0
+	DefaultMaintenanceDueDays   = 15
+)
+
+// Maintenance account statuses
+const (
+	MaintenanceAccountActive     = "active"
+	MaintenanceAccountHandedOver = "handed_over"
+)
+
+// Maintenance invoice types
+const (
+	MaintenanceInvoiceDeposit  = "deposit"
+	MaintenanceInvoicePeriodic = "periodic"
+)
+
+// Maintenance invoice statuses
+const (
+	MaintenanceInvoiceUnpaid        = "unpaid"
+	MaintenanceInvoicePartiallyPaid = "partially_paid"
+	MaintenanceInvoicePaid          = "paid"
+)
+
+// MaintenancePlan is a project's maintenance tariff
+type MaintenancePlan struct {
+	ID                 string    `json:"id"`
+	TenantID           string    `json:"tenant_id"`
+	ProjectID          string    `json:"project_id"`
+	CAMRatePerSqft     float64   `json:"cam_rate_per_sqft"`     // per month
+	SinkingFundPerSqft float64   `json:"sinking_fund_per_sqft"` // per month
+	DepositPerSqft     float64   `json:"deposit_per_sqft"`      // one-time corpus
+	BillingFrequency   string    `json:"billing_frequency"`     // monthly, quarterly, half_yearly, annual
+	GSTRate            float64   `json:"gst_rate"`
+	GSTExemptLimit     float64   `json:"gst_exempt_limit"` // monthly contribution per unit
+	DueDays            int       `json:"due_days"`
+	IsActive           bool      `json:"is_active"` // false once handed over to the association
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// MaintenanceAccount is a unit's maintenance account
+type MaintenanceAccount struct {
+	ID               string               `json:"id"`
+	TenantID         string               `json:"tenant_id"`
+	ProjectID        string               `json:"project_id"`
+	UnitID           string               `json:"unit_id"`
+	UnitNumber       string               `json:"unit_number"`
+	BookingID        string               `json:"booking_id"`
+	CustomerID       string               `json:"customer_id"`
+	AreaSqft         float64              `json:"area_sqft"`
+	BillingStartDate time.Time            `json:"billing_start_date"` // possession date
+	BilledUpTo       *time.Time           `json:"billed_up_to,omitempty"`
+	Status           string               `json:"status"`
+	TotalBilled      float64              `json:"total_billed"`
+	TotalReceived    float64              `json:"total_received"`
+	Outstanding      float64              `json:"outstanding"`
+	Invoices         []MaintenanceInvoice `json:"invoices,omitempty"`
+	Receipts         []MaintenanceReceipt `json:"receipts,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// MaintenanceInvoice is a deposit or periodic maintenance bill
+type MaintenanceInvoice struct {
+	ID                string     `json:"id"`
+	TenantID          string     `json:"tenant_id"`
+	InvoiceNumber     string     `json:"invoice_number"`
+	AccountID         string     `json:"account_id"`
+	ProjectID         string     `json:"project_id"`
+	UnitID            string     `json:"unit_id"`
+	BookingID         string     `json:"booking_id"`
+	InvoiceType       string     `json:"invoice_type"` // deposit, periodic
+	InvoiceDate       time.Time  `json:"invoice_date"`
+	PeriodFrom        *time.Time `json:"period_from,omitempty"`
+	PeriodTo          *time.Time `json:"period_to,omitempty"` // exclusive
+	CAMAmount         float64    `json:"cam_amount"`
+	SinkingFundAmount float64    `json:"sinking_fund_amount"`
+	DepositAmount     float64    `json:"deposit_amount"`
+	TaxableAmount     float64    `json:"taxable_amount"`
+	GSTRate           float64    `json:"gst_rate"`
+	GSTAmount         float64    `json:"gst_amount"`
+	TotalAmount       float64    `json:"total_amount"`
+	AmountPaid        float64    `json:"amount_paid"`
+	Balance           float64    `json:"balance"`
+	DueDate           time.Time  `json:"due_date"`
+	Status            string     `json:"status"`
+	JournalEntryID    *string    `json:"journal_entry_id,omitempty"`
+	CreatedBy         *string    `json:"created_by,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// MaintenanceAllocation is the part of a receipt applied to one invoice
+type MaintenanceAllocation struct {
+	InvoiceID     string  `json:"invoice_id"`
+	InvoiceNumber string  `json:"invoice_number"`
+	Amount        float64 `json:"amount"`
+}
+
+// MaintenanceReceipt is a maintenance payment from the unit owner
+type MaintenanceReceipt struct {
+	ID             string                  `json:"id"`
+	TenantID       string                  `json:"tenant_id"`
+	ReceiptNumber  string                  `json:"receipt_number"`
+	AccountID      string                  `json:"account_id"`
+	BookingID      string                  `json:"booking_id"`
+	ReceiptDate    time.Time               `json:"receipt_date"`
+	Amount         float64                 `json:"amount"`
+	PaymentMode    string                  `json:"payment_mode"`
+	Reference      string                  `json:"reference"`
+	Allocations    []MaintenanceAllocation `json:"allocations,omitempty"`
+	JournalEntryID *string                 `json:"journal_entry_id,omitempty"`
+	CreatedBy      *string                 `json:"created_by,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+}
+
+// MaintenanceArrearsRow is one unit's overdue maintenance
+type MaintenanceArrearsRow struct {
+	AccountID     string    `json:"account_id"`
+	UnitID        string    `json:"unit_id"`
+	UnitNumber    string    `json:"unit_number"`
+	BookingID     string    `json:"booking_id"`
+	InvoiceCount  int       `json:"invoice_count"`
+	OldestDueDate time.Time `json:"oldest_due_date"`
+	DaysOverdue   int       `json:"days_overdue"`
+	Bucket        string    `json:"bucket"`
+	Outstanding   float64   `json:"outstanding"`
+}
+
+// MaintenanceClosingStatement is the project's maintenance position handed to the association
+type MaintenanceClosingStatement struct {
+	ProjectID            string                  `json:"project_id"`
+	AsOf                 time.Time               `json:"as_of"`
+	UnitsEnrolled        int                     `json:"units_enrolled"`
+	DepositBilled        float64                 `json:"deposit_billed"`
+	DepositCollected     float64                 `json:"deposit_collected"`
+	SinkingFundBilled    float64                 `json:"sinking_fund_billed"`
+	SinkingFundCollected float64                 `json:"sinking_fund_collected"`
+	CAMBilled            float64                 `json:"cam_billed"`
+	CAMCollected         float64                 `json:"cam_collected"`
+	GSTBilled            float64                 `json:"gst_billed"`
+	TotalBilled          float64                 `json:"total_billed"`
+	TotalCollected       float64                 `json:"total_collected"`
+	Arrears              float64                 `json:"arrears"`
+	CorpusTransfer       float64                 `json:"corpus_transfer"` // deposit + sinking fund collected
+	ArrearsByUnit        []MaintenanceArrearsRow `json:"arrears_by_unit"`
+}
+
+// AssociationHandover records the handover of maintenance to the residents' association
+type AssociationHandover struct {
+	ID                 string                      `json:"id"`
+	TenantID           string                      `json:"tenant_id"`
+	HandoverNumber     string                      `json:"handover_number"`
+	ProjectID          string                      `json:"project_id"`
+	AssociationName    string                      `json:"association_name"`
+	RegistrationNumber string                      `json:"registration_number"`
+	BankAccount        string                      `json:"bank_account"`
+	HandoverDate       time.Time                   `json:"handover_date"`
+	CorpusTransferred  float64                     `json:"corpus_transferred"`
+	TransferReference  string                      `json:"transfer_reference"`
+	Statement          MaintenanceClosingStatement `json:"statement"`
+	JournalEntryID     *string                     `json:"journal_entry_id,omitempty"`
+	CreatedBy          *string                     `json:"created_by,omitempty"`
+	CreatedAt          time.Time                   `json:"created_at"`
+}
+
+// SetMaintenancePlanRequest creates or updates a project's maintenance tariff
+type SetMaintenancePlanRequest struct {
+	ProjectID          string   `json:"project_id" validate:"required"`
+	CAMRatePerSqft     float64  `json:"cam_rate_per_sqft" validate:"required"`
+	SinkingFundPerSqft float64  `json:"sinking_fund_per_sqft"`
+	DepositPerSqft     float64  `json:"deposit_per_sqft"`
+	BillingFrequency   string   `json:"billing_frequency"` // defaults to monthly
+	GSTRate            *float64 `json:"gst_rate"`          // defaults to 18
+	GSTExemptLimit     *float64 `json:"gst_exempt_limit"`  // defaults to 7500
+	DueDays            int      `json:"due_days"`          // defaults to 15
+}
+
+// GenerateMaintenanceInvoicesRequest bills every period starting on or before AsOf
+type GenerateMaintenanceInvoicesRequest struct {
+	ProjectID string     `json:"project_id" validate:"required"`
+	AsOf      *time.Time `json:"as_of"` // defaults to today
+}
+
+// RecordMaintenanceReceiptRequest records a maintenance payment against a unit's account
+type RecordMaintenanceReceiptRequest struct {
+	Amount      float64    `json:"amount" validate:"required"`
+	ReceiptDate *time.Time `json:"receipt_date"` // defaults to today
+	PaymentMode string     `json:"payment_mode" validate:"required"`
+	Reference   string     `json:"reference"`
+}
+
+// AssociationHandoverRequest hands the project's maintenance over to the association
+type AssociationHandoverRequest struct {
+	AssociationName    string     `json:"association_name" validate:"required"`
+	RegistrationNumber string     `json:"registration_number"`
+	BankAccount        string     `json:"bank_account" validate:"required"`
+	HandoverDate       *time.Time `json:"handover_date"` // defaults to today
+	TransferReference  string     `json:"transfer_reference" validate:"required"`
+}
@@ -203,6 +203,8 @@ type PartnerPayout struct {
 	TotalAmount     float64    `json:"total_amount" db:"total_amount"`       // Before approval
 	ApprovedAmount  float64    `json:"approved_amount" db:"approved_amount"` // After approval
 	RejectedAmount  float64    `json:"rejected_amount" db:"rejected_amount"`
+	TDSAmount       float64    `json:"tds_amount" db:"tds_amount"`         // 194H, deducted when paid
+	NetAmount       float64    `json:"net_amount" db:"net_amount"`         // Paid after TDS
 	Status          string     `json:"status" db:"status"`                 // pending, approved, rejected, paid, partially_paid
 	PaymentMethod   string     `json:"payment_method" db:"payment_method"` // bank_transfer, paypal, check, wire
 	PaymentDate     *time.Time `json:"payment_date" db:"payment_date"`
@@ -269,7 +271,7 @@ type PartnerFilter struct {
 
 // PartnerLeadFilter for filtering partner leads
 type PartnerLeadFilter struct {
-	PartnerID       int64
+	PartnerID       string
 	Status          string
 	SubmissionType  string
 	QualityScoreMin float64
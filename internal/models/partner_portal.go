@@ -0,0 +1,134 @@
+package models
+
+import "time"
+
+// ============================================================================
+// CHANNEL PARTNER PORTAL MODELS
+// ============================================================================
+// Partner users sign in to the portal with their own tokens. A token carries
+// the scopes granted by the partner user's role, and every portal request is
+// limited to the signed-in user's partner.
+
+// Partner portal scopes
+const (
+	PartnerScopeLeadsRead     = "leads:read"     // lead and booking status
+	PartnerScopeLeadsWrite    = "leads:write"    // submit leads
+	PartnerScopePayoutsRead   = "payouts:read"   // payouts and statements
+	PartnerScopeInvoicesWrite = "invoices:write" // upload invoices against payouts
+)
+
+// Partner portal lead stages shown to partners
+const (
+	PartnerLeadStageSubmitted       = "submitted"
+	PartnerLeadStageUnderReview     = "under_review"
+	PartnerLeadStageRejected        = "rejected"
+	PartnerLeadStageAccepted        = "accepted" // approved into the sales pipeline
+	PartnerLeadStageBooked          = "booked"
+	PartnerLeadStageAgreementSigned = "agreement_signed"
+	PartnerLeadStageCancelled       = "booking_cancelled"
+)
+
+// Partner invoice statuses
+const (
+	PartnerInvoiceSubmitted = "submitted"
+	PartnerInvoiceAccepted  = "accepted"
+	PartnerInvoiceRejected  = "rejected"
+)
+
+// PartnerInvoiceDocumentEntity is the document entity type partner invoice files are
+// attached under, with the payout as the entity
+const PartnerInvoiceDocumentEntity = "partner_payout"
+
+// PartnerLoginRequest signs a partner user in to the portal
+type PartnerLoginRequest struct {
+	PartnerCode string `json:"partner_code" validate:"required"`
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required"`
+}
+
+// PartnerLoginResponse is a partner portal session
+type PartnerLoginResponse struct {
+	Token       string    `json:"token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	UserID      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	Role        string    `json:"role"`
+	Scopes      []string  `json:"scopes"`
+	PartnerID   string    `json:"partner_id"`
+	PartnerCode string    `json:"partner_code"`
+	PartnerName string    `json:"partner_name"`
+}
+
+// PartnerPortalProfile is the signed-in partner with its lead and payout figures
+type PartnerPortalProfile struct {
+	PartnerID   string        `json:"partner_id"`
+	PartnerCode string        `json:"partner_code"`
+	PartnerName string        `json:"partner_name"`
+	UserID      string        `json:"user_id"`
+	Scopes      []string      `json:"scopes"`
+	Stats       *PartnerStats `json:"stats"`
+}
+
+// SubmitPortalLeadRequest submits a lead from the partner portal
+type SubmitPortalLeadRequest struct {
+	SubmissionType string   `json:"submission_type"` // new_lead, referral; defaults to new_lead
+	LeadData       LeadData `json:"lead_data" validate:"required"`
+}
+
+// PartnerPortalBooking is the booking status a partner sees for a lead they sourced
+type PartnerPortalBooking struct {
+	BookingID        string     `json:"booking_id"`
+	BookingReference string     `json:"booking_reference"`
+	BookingStatus    string     `json:"booking_status"`
+	BookingDate      time.Time  `json:"booking_date"`
+	AgreementDate    *time.Time `json:"agreement_date,omitempty"`
+}
+
+// PartnerPortalLead is a submitted lead with where it stands in the sales pipeline
+type PartnerPortalLead struct {
+	PartnerLead
+	Stage    string                 `json:"stage"`
+	Bookings []PartnerPortalBooking `json:"bookings"`
+}
+
+// PartnerInvoice is an invoice a partner raised against a payout
+type PartnerInvoice struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	PartnerID     string    `json:"partner_id"`
+	PayoutID      string    `json:"payout_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	InvoiceDate   time.Time `json:"invoice_date"`
+	Amount        float64   `json:"amount"` // taxable value
+	GSTAmount     float64   `json:"gst_amount"`
+	TotalAmount   float64   `json:"total_amount"`
+	DocumentID    string    `json:"document_id"` // invoice file, attached to the payout
+	Status        string    `json:"status"`
+	UploadedBy    string    `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UploadPartnerInvoiceRequest records an invoice raised against a payout
+type UploadPartnerInvoiceRequest struct {
+	PayoutID      string     `json:"payout_id" validate:"required"`
+	InvoiceNumber string     `json:"invoice_number" validate:"required"`
+	InvoiceDate   *time.Time `json:"invoice_date"` // defaults to today
+	Amount        float64    `json:"amount" validate:"required"`
+	GSTAmount     float64    `json:"gst_amount"`
+	DocumentID    string     `json:"document_id" validate:"required"` // uploaded document attached to the payout
+}
+
+// PartnerPayoutStatement is a payout with its lead lines, TDS and invoices
+type PartnerPayoutStatement struct {
+	PartnerID   string                `json:"partner_id"`
+	PartnerCode string                `json:"partner_code"`
+	PartnerName string                `json:"partner_name"`
+	PAN         string                `json:"pan,omitempty"`
+	Payout      PartnerPayout         `json:"payout"`
+	Lines       []PartnerPayoutDetail `json:"lines"`
+	GrossAmount float64               `json:"gross_amount"`
+	TDSAmount   float64               `json:"tds_amount"` // section 194H
+	NetAmount   float64               `json:"net_amount"`
+	Invoices    []PartnerInvoice      `json:"invoices"`
+}
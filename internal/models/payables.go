@@ -0,0 +1,233 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// ACCOUNTS PAYABLE AGEING MODELS
+// ============================================================================
+
+// PayableInvoice is an approved vendor invoice with an unpaid balance
+type PayableInvoice struct {
+	InvoiceID     string    `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	VendorID      string    `json:"vendor_id"`
+	VendorName    string    `json:"vendor_name"`
+	ProjectID     string    `json:"project_id"`
+	ProjectName   string    `json:"project_name"`
+	InvoiceDate   time.Time `json:"invoice_date"`
+	DueDate       time.Time `json:"due_date"`       // invoice due date, or derived from vendor payment terms
+	TaxableAmount float64   `json:"taxable_amount"` // invoice amount less discount, excluding GST
+	TotalPayable  float64   `json:"total_payable"`
+	AmountPaid    float64   `json:"amount_paid"`  // includes TDS already deducted
+	BackCharged   float64   `json:"back_charged"` // recoveries set off against the invoice
+	Outstanding   float64   `json:"outstanding"`
+	DaysOverdue   int       `json:"days_overdue"`
+	Bucket        string    `json:"bucket"`
+	Status        string    `json:"status"`
+}
+
+// APAgeingRow aggregates payables for one vendor or project
+type APAgeingRow struct {
+	GroupKey         string  `json:"group_key"`
+	GroupName        string  `json:"group_name"`
+	Current          float64 `json:"current"` // not yet due
+	Bucket0To30      float64 `json:"bucket_0_30"`
+	Bucket31To60     float64 `json:"bucket_31_60"`
+	Bucket61To90     float64 `json:"bucket_61_90"`
+	Bucket90Plus     float64 `json:"bucket_90_plus"`
+	TotalOverdue     float64 `json:"total_overdue"`
+	TotalOutstanding float64 `json:"total_outstanding"`
+	InvoiceCount     int     `json:"invoice_count"`
+}
+
+// APAgeingReport is the AP ageing report as of a date
+type APAgeingReport struct {
+	AsOfDate time.Time     `json:"as_of_date"`
+	GroupBy  string        `json:"group_by"` // vendor, project
+	Rows     []APAgeingRow `json:"rows"`
+	Totals   APAgeingRow   `json:"totals"`
+}
+
+// ============================================================================
+// VENDOR PAYMENT SETUP MODELS
+// ============================================================================
+
+// VendorBankAccount holds the beneficiary details used in bank payment files
+type VendorBankAccount struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	VendorID      string    `json:"vendor_id"`
+	AccountName   string    `json:"account_name"`
+	AccountNumber string    `json:"account_number"`
+	IFSCCode      string    `json:"ifsc_code"`
+	BankName      string    `json:"bank_name"`
+	IsPrimary     bool      `json:"is_primary"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// VendorTDSSection maps a vendor to the TDS section deducted on its payments
+type VendorTDSSection struct {
+	ID          string    `json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	VendorID    string    `json:"vendor_id"`
+	SectionCode string    `json:"section_code"` // 194C, 194H, 194J, 194I
+	Rate        float64   `json:"rate"`         // % of taxable value
+	PAN         string    `json:"pan"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BankPaymentFormat describes a bank's bulk payment upload layout
+type BankPaymentFormat struct {
+	ID                 string    `json:"id"`
+	TenantID           string    `json:"tenant_id"`
+	BankCode           string    `json:"bank_code"`
+	FormatName         string    `json:"format_name"`
+	FileType           string    `json:"file_type"` // csv, neft
+	Delimiter          string    `json:"delimiter"`
+	IncludeHeader      bool      `json:"include_header"`
+	Columns            []string  `json:"columns"` // ordered field keys, see BankFileColumn* constants
+	DateFormat         string    `json:"date_format"`
+	DebitAccountNumber string    `json:"debit_account_number"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Column keys available in bank payment file layouts
+const (
+	BankFileColumnPaymentMode   = "payment_mode"
+	BankFileColumnDebitAccount  = "debit_account"
+	BankFileColumnBeneficiary   = "beneficiary_name"
+	BankFileColumnAccountNumber = "account_number"
+	BankFileColumnIFSC          = "ifsc"
+	BankFileColumnBankName      = "bank_name"
+	BankFileColumnAmount        = "amount"
+	BankFileColumnPaymentDate   = "payment_date"
+	BankFileColumnReference     = "reference"
+	BankFileColumnVendorCode    = "vendor_code"
+	BankFileColumnEmail         = "email"
+)
+
+// ============================================================================
+// PAYMENT RUN MODELS
+// ============================================================================
+
+// VendorPaymentRun is a batch of vendor payments proposed, approved and processed together
+type VendorPaymentRun struct {
+	ID              string                 `json:"id"`
+	TenantID        string                 `json:"tenant_id"`
+	RunNumber       string                 `json:"run_number"`
+	Status          string                 `json:"status"` // proposed, approved, rejected, processing, processed, failed, bank_file_failed
+	DueOnOrBefore   *time.Time             `json:"due_on_or_before"`
+	VendorID        string                 `json:"vendor_id"`
+	ProjectID       string                 `json:"project_id"`
+	PaymentDate     time.Time              `json:"payment_date"`
+	PaymentMethod   string                 `json:"payment_method"` // NEFT, RTGS, IMPS
+	BankFormatID    string                 `json:"bank_format_id"`
+	TotalGross      float64                `json:"total_gross"`
+	TotalTDS        float64                `json:"total_tds"`
+	TotalNet        float64                `json:"total_net"`
+	LineCount       int                    `json:"line_count"`
+	CreatedBy       string                 `json:"created_by"`
+	ApprovedBy      *string                `json:"approved_by"`
+	ApprovedAt      *time.Time             `json:"approved_at"`
+	ApprovalComment string                 `json:"approval_comment"`
+	ProcessedAt     *time.Time             `json:"processed_at"`
+	BankFileName    string                 `json:"bank_file_name"`
+	FailureReason   string                 `json:"failure_reason,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	Lines           []VendorPaymentRunLine `json:"lines,omitempty"`
+	Skipped         []string               `json:"skipped,omitempty"` // invoices left out of the proposal, with reason
+}
+
+// VendorPaymentRunLine is one invoice paid in a payment run
+type VendorPaymentRunLine struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	RunID          string    `json:"run_id"`
+	InvoiceID      string    `json:"invoice_id"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	VendorID       string    `json:"vendor_id"`
+	VendorName     string    `json:"vendor_name"`
+	DueDate        time.Time `json:"due_date"`
+	GrossAmount    float64   `json:"gross_amount"`
+	TDSSection     string    `json:"tds_section"`
+	TDSRate        float64   `json:"tds_rate"`
+	TDSAmount      float64   `json:"tds_amount"`
+	NetAmount      float64   `json:"net_amount"`
+	PaymentID      *string   `json:"payment_id"`
+	JournalEntryID *string   `json:"journal_entry_id"`
+	Status         string    `json:"status"` // proposed, paid, failed
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TDSDeduction is tax deducted at source on a vendor payment, pending deposit
+type TDSDeduction struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	VendorID      string    `json:"vendor_id"`
+	InvoiceID     string    `json:"invoice_id"`
+	PaymentID     string    `json:"payment_id"`
+	SectionCode   string    `json:"section_code"`
+	Rate          float64   `json:"rate"`
+	BaseAmount    float64   `json:"base_amount"`
+	TDSAmount     float64   `json:"tds_amount"`
+	DeductionDate time.Time `json:"deduction_date"`
+	Status        string    `json:"status"` // pending_deposit, deposited
+	ChallanID     *string   `json:"challan_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ============================================================================
+// REQUEST/RESPONSE MODELS
+// ============================================================================
+
+// CreatePaymentProposalRequest selects open invoices for a payment run
+type CreatePaymentProposalRequest struct {
+	DueOnOrBefore *time.Time `json:"due_on_or_before"`
+	VendorID      string     `json:"vendor_id"`
+	ProjectID     string     `json:"project_id"`
+	PaymentDate   *time.Time `json:"payment_date"`
+	PaymentMethod string     `json:"payment_method"`
+	BankFormatID  string     `json:"bank_format_id" binding:"required"`
+}
+
+// ApprovePaymentRunRequest approves or rejects a proposed payment run
+type ApprovePaymentRunRequest struct {
+	Approve bool   `json:"approve"`
+	Comment string `json:"comment"`
+}
+
+// UpsertVendorBankAccountRequest sets a vendor's primary bank account
+type UpsertVendorBankAccountRequest struct {
+	AccountName   string `json:"account_name" binding:"required"`
+	AccountNumber string `json:"account_number" binding:"required"`
+	IFSCCode      string `json:"ifsc_code" binding:"required"`
+	BankName      string `json:"bank_name"`
+}
+
+// SetVendorTDSSectionRequest sets the TDS section deducted on a vendor's payments
+type SetVendorTDSSectionRequest struct {
+	SectionCode string  `json:"section_code" binding:"required"`
+	Rate        float64 `json:"rate" binding:"required"`
+	PAN         string  `json:"pan"`
+}
+
+// CreateBankPaymentFormatRequest configures a bank's bulk payment layout
+type CreateBankPaymentFormatRequest struct {
+	BankCode           string   `json:"bank_code" binding:"required"`
+	FormatName         string   `json:"format_name" binding:"required"`
+	FileType           string   `json:"file_type"`
+	Delimiter          string   `json:"delimiter"`
+	IncludeHeader      bool     `json:"include_header"`
+	Columns            []string `json:"columns" binding:"required"`
+	DateFormat         string   `json:"date_format"`
+	DebitAccountNumber string   `json:"debit_account_number" binding:"required"`
+}
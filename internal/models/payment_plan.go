@@ -0,0 +1,228 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// PAYMENT PLAN AND DEMAND LETTER MODELS
+// ============================================================================
+
+// Payment plan types
+const (
+	PaymentPlanConstructionLinked = "construction_linked"
+	PaymentPlanTimeLinked         = "time_linked"
+	PaymentPlanDownPayment        = "down_payment"
+	PaymentPlanSubvention         = "subvention"
+)
+
+// Payment plan stage triggers
+const (
+	PlanTriggerOnBooking = "on_booking" // due on booking
+	PlanTriggerTime      = "time"       // due a number of days after booking
+	PlanTriggerMilestone = "milestone"  // due when the tower reaches a construction milestone
+)
+
+// Payment plan stage payers
+const (
+	PlanPayerCustomer = "customer"
+	PlanPayerLender   = "lender" // disbursed by the home loan lender under a subvention plan
+)
+
+// Booking plan stage statuses
+const (
+	PlanStageStatusPending   = "pending"   // milestone not reached yet
+	PlanStageStatusScheduled = "scheduled" // time-linked, payment schedule created with a fixed due date
+	PlanStageStatusDemanded  = "demanded"  // demand raised, payment schedule created
+)
+
+// Tower milestone statuses
+const (
+	MilestoneStatusPending   = "pending"
+	MilestoneStatusCompleted = "completed"
+)
+
+// PaymentPlanStage is one percentage stage of a payment plan template
+type PaymentPlanStage struct {
+	ID            string  `json:"id"`
+	TemplateID    string  `json:"template_id"`
+	StageNumber   int     `json:"stage_number"`
+	StageName     string  `json:"stage_name"`
+	Percentage    float64 `json:"percentage"`
+	TriggerType   string  `json:"trigger_type"`             // on_booking, time, milestone
+	OffsetDays    int     `json:"offset_days"`              // time: days after booking
+	MilestoneCode string  `json:"milestone_code,omitempty"` // milestone: e.g. SLAB_5
+	DueDays       int     `json:"due_days"`                 // days allowed to pay after the demand
+	Payer         string  `json:"payer"`                    // customer, lender
+}
+
+// PaymentPlanTemplate is a reusable payment plan for a project
+type PaymentPlanTemplate struct {
+	ID          string             `json:"id"`
+	TenantID    string             `json:"tenant_id"`
+	ProjectID   string             `json:"project_id"`
+	Name        string             `json:"name"`
+	PlanType    string             `json:"plan_type"` // construction_linked, time_linked, down_payment, subvention
+	Description string             `json:"description,omitempty"`
+	IsActive    bool               `json:"is_active"`
+	Stages      []PaymentPlanStage `json:"stages"`
+	CreatedBy   string             `json:"created_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// BookingPlanStage is a template stage applied to a booking's agreement value
+type BookingPlanStage struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	BookingID      string     `json:"booking_id"`
+	TemplateID     string     `json:"template_id"`
+	StageID        string     `json:"stage_id"`
+	StageNumber    int        `json:"stage_number"`
+	StageName      string     `json:"stage_name"`
+	Percentage     float64    `json:"percentage"`
+	Amount         float64    `json:"amount"`
+	TriggerType    string     `json:"trigger_type"`
+	OffsetDays     int        `json:"offset_days"`
+	MilestoneCode  string     `json:"milestone_code,omitempty"`
+	DueDays        int        `json:"due_days"`
+	Payer          string     `json:"payer"`
+	Status         string     `json:"status"` // pending, scheduled, demanded
+	DueDate        *time.Time `json:"due_date"`
+	ScheduleID     *string    `json:"schedule_id"`
+	DemandLetterID *string    `json:"demand_letter_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BookingPaymentPlan is the payment plan applied to a booking
+type BookingPaymentPlan struct {
+	BookingID      string             `json:"booking_id"`
+	TemplateID     string             `json:"template_id"`
+	PlanName       string             `json:"plan_name"`
+	PlanType       string             `json:"plan_type"`
+	AgreementValue float64            `json:"agreement_value"`
+	DemandedAmount float64            `json:"demanded_amount"`
+	PendingAmount  float64            `json:"pending_amount"`
+	Stages         []BookingPlanStage `json:"stages"`
+	DemandLetters  []DemandLetter     `json:"demand_letters,omitempty"`
+}
+
+// TowerMilestone is a construction milestone of a tower (block) that payment stages hang off
+type TowerMilestone struct {
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	ProjectID          string     `json:"project_id"`
+	BlockID            string     `json:"block_id"`
+	MilestoneCode      string     `json:"milestone_code"`
+	MilestoneName      string     `json:"milestone_name"`
+	Sequence           int        `json:"sequence"`
+	PlannedDate        *time.Time `json:"planned_date"`
+	Status             string     `json:"status"` // pending, completed
+	CompletedOn        *time.Time `json:"completed_on"`
+	ProgressTrackingID *string    `json:"progress_tracking_id"` // site progress entry that evidenced completion
+	ArchitectCertRef   string     `json:"architect_certificate_ref,omitempty"`
+	CompletedBy        *string    `json:"completed_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// DemandLetter is a stage demand raised on a booking
+type DemandLetter struct {
+	ID                  string     `json:"id"`
+	TenantID            string     `json:"tenant_id"`
+	LetterNumber        string     `json:"letter_number"`
+	BookingID           string     `json:"booking_id"`
+	BookingReference    string     `json:"booking_reference"`
+	PlanStageID         string     `json:"plan_stage_id"`
+	MilestoneID         *string    `json:"milestone_id"`
+	ScheduleID          string     `json:"schedule_id"`
+	ProjectName         string     `json:"project_name"`
+	UnitNumber          string     `json:"unit_number"`
+	CustomerName        string     `json:"customer_name"`
+	CustomerEmail       string     `json:"customer_email,omitempty"`
+	CustomerPhone       string     `json:"customer_phone,omitempty"`
+	StageName           string     `json:"stage_name"`
+	Percentage          float64    `json:"percentage"`
+	StageAmount         float64    `json:"stage_amount"`
+	PreviousOutstanding float64    `json:"previous_outstanding"`
+	TotalDue            float64    `json:"total_due"`
+	DemandDate          time.Time  `json:"demand_date"`
+	DueDate             time.Time  `json:"due_date"`
+	Subject             string     `json:"subject"`
+	Body                string     `json:"body"`
+	NotifyChannel       string     `json:"notify_channel"`
+	NotifyStatus        string     `json:"notify_status"` // pending, sent, failed
+	NotifyError         string     `json:"notify_error,omitempty"`
+	NotifiedAt          *time.Time `json:"notified_at"`
+	CreatedBy           string     `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+	LoanRequestID       string     `json:"loan_request_id,omitempty"`    // lender disbursement request raised with the demand
+	LoanRequestError    string     `json:"loan_request_error,omitempty"` // why the lender request could not be raised
+}
+
+// MilestoneDemandRun is the outcome of completing a tower milestone. Stages whose
+// demand failed stay pending and can be raised again for the milestone.
+type MilestoneDemandRun struct {
+	Milestone          TowerMilestone  `json:"milestone"`
+	DemandsRaised      int             `json:"demands_raised"`
+	TotalDemanded      float64         `json:"total_demanded"`
+	NotifyFailed       int             `json:"notify_failed"`
+	LoanRequestsRaised int             `json:"loan_requests_raised"`
+	DemandsFailed      int             `json:"demands_failed"`
+	DemandLetters      []DemandLetter  `json:"demand_letters"`
+	Failures           []DemandFailure `json:"failures"`
+}
+
+// DemandFailure is a booking stage whose demand could not be raised
+type DemandFailure struct {
+	BookingID        string `json:"booking_id"`
+	BookingReference string `json:"booking_reference"`
+	PlanStageID      string `json:"plan_stage_id"`
+	StageName        string `json:"stage_name"`
+	Error            string `json:"error"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// CreatePaymentPlanTemplateRequest creates a payment plan template with its stages
+type CreatePaymentPlanTemplateRequest struct {
+	ProjectID   string             `json:"project_id" binding:"required"`
+	Name        string             `json:"name" binding:"required"`
+	PlanType    string             `json:"plan_type" binding:"required"`
+	Description string             `json:"description"`
+	Stages      []PaymentPlanStage `json:"stages" binding:"required"`
+}
+
+// AssignPaymentPlanRequest applies a template to a booking
+type AssignPaymentPlanRequest struct {
+	TemplateID     string  `json:"template_id" binding:"required"`
+	AgreementValue float64 `json:"agreement_value"` // defaults to the locked booking price, then the unit cost sheet total
+}
+
+// CreateTowerMilestonesRequest adds construction milestones to a tower
+type CreateTowerMilestonesRequest struct {
+	ProjectID  string `json:"project_id" binding:"required"`
+	BlockID    string `json:"block_id" binding:"required"`
+	Milestones []struct {
+		MilestoneCode string `json:"milestone_code" binding:"required"`
+		MilestoneName string `json:"milestone_name" binding:"required"`
+		Sequence      int    `json:"sequence"`
+		PlannedDate   string `json:"planned_date"` // YYYY-MM-DD
+	} `json:"milestones" binding:"required"`
+}
+
+// CompleteMilestoneRequest marks a tower milestone complete
+type CompleteMilestoneRequest struct {
+	CompletedOn        string `json:"completed_on"` // YYYY-MM-DD, defaults to today
+	ProgressTrackingID string `json:"progress_tracking_id"`
+	ArchitectCertRef   string `json:"architect_certificate_ref"`
+	Channel            string `json:"channel"` // email (default), sms, whatsapp
+}
+
+// RaiseMilestoneDemandsRequest raises the demands still pending on a completed milestone
+type RaiseMilestoneDemandsRequest struct {
+	Channel string `json:"channel"` // email (default), sms, whatsapp
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// PRICE LIST AND COST SHEET MODELS
+// ============================================================================
+
+// Price list statuses
+const (
+	PriceListStatusDraft     = "draft"
+	PriceListStatusPublished = "published" // immutable; applies from effective_from until a newer version takes over
+)
+
+// Price list rule categories
+const (
+	PriceRuleFloorRise = "floor_rise" // slab by floor range
+	PriceRulePLC       = "plc"        // preferential location charge by facing, view or corner
+	PriceRuleParking   = "parking"    // per parking tier
+	PriceRuleClub      = "club"       // club membership
+	PriceRuleStatutory = "statutory"  // corpus, maintenance deposit, EB/water connection etc.
+	PriceRuleOther     = "other"
+)
+
+// Price list charge types
+const (
+	PriceChargePerSqft  = "per_sqft" // multiplied by the unit's SBUA
+	PriceChargeLumpsum  = "lumpsum"
+	PriceChargeBasicPct = "basic_pct" // percentage of the basic cost
+)
+
+// PLC attributes
+const (
+	PLCAttributeFacing = "facing"
+	PLCAttributeView   = "view"
+	PLCAttributeCorner = "corner"
+)
+
+// PriceListRule is one pricing rule of a price list
+type PriceListRule struct {
+	ID           string  `json:"id"`
+	PriceListID  string  `json:"price_list_id"`
+	Category     string  `json:"category"` // floor_rise, plc, parking, club, statutory, other
+	Code         string  `json:"code"`
+	Name         string  `json:"name"`
+	ChargeType   string  `json:"charge_type"` // per_sqft, lumpsum, basic_pct
+	Amount       float64 `json:"amount"`
+	GSTRate      float64 `json:"gst_rate"`
+	FromFloor    int     `json:"from_floor,omitempty"`  // floor_rise
+	ToFloor      int     `json:"to_floor,omitempty"`    // floor_rise, 0 = no upper limit
+	Attribute    string  `json:"attribute,omitempty"`   // plc: facing, view, corner
+	MatchValue   string  `json:"match_value,omitempty"` // plc: e.g. east, park
+	ParkingType  string  `json:"parking_type,omitempty"`
+	UnitTypes    string  `json:"unit_types,omitempty"` // comma-separated, empty for all
+	IsOptional   bool    `json:"is_optional"`          // applied only when asked for
+	DisplayOrder int     `json:"display_order"`
+}
+
+// PriceList is a versioned price list for a project, or for one tower of it
+type PriceList struct {
+	ID               string          `json:"id"`
+	TenantID         string          `json:"tenant_id"`
+	ProjectID        string          `json:"project_id"`
+	BlockID          *string         `json:"block_id"` // nil applies to every tower without its own list
+	Version          int             `json:"version"`
+	Name             string          `json:"name"`
+	BaseRatePerSqft  float64         `json:"base_rate_per_sqft"`
+	BaseGSTRate      float64         `json:"base_gst_rate"`
+	StampDutyRate    float64         `json:"stamp_duty_rate"`
+	RegistrationRate float64         `json:"registration_rate"`
+	RegistrationCap  float64         `json:"registration_cap"` // 0 = no cap
+	Status           string          `json:"status"`
+	EffectiveFrom    *time.Time      `json:"effective_from"`
+	PublishedBy      *string         `json:"published_by"`
+	PublishedAt      *time.Time      `json:"published_at"`
+	Notes            string          `json:"notes,omitempty"`
+	Rules            []PriceListRule `json:"rules"`
+	CreatedBy        string          `json:"created_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// UnitPricingAttributes are the unit attributes PLC rules look at beyond facing
+type UnitPricingAttributes struct {
+	UnitID    string    `json:"unit_id"`
+	View      string    `json:"view"` // e.g. park, pool, road, city
+	IsCorner  bool      `json:"is_corner"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CostSheetLine is one itemised charge of a cost sheet
+type CostSheetLine struct {
+	Category   string  `json:"category"` // basic, floor_rise, plc, parking, club, statutory, other, discount
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	ChargeType string  `json:"charge_type"`
+	Quantity   float64 `json:"quantity"` // sqft, slots or 1
+	Rate       float64 `json:"rate"`
+	Amount     float64 `json:"amount"`
+	GSTRate    float64 `json:"gst_rate"`
+	GSTAmount  float64 `json:"gst_amount"`
+	Total      float64 `json:"total"`
+}
+
+// CostSheet is the itemised price of a unit under a price list as of a date
+type CostSheet struct {
+	UnitID            string          `json:"unit_id"`
+	UnitNumber        string          `json:"unit_number"`
+	ProjectID         string          `json:"project_id"`
+	BlockID           string          `json:"block_id"`
+	Floor             int             `json:"floor"`
+	UnitType          string          `json:"unit_type"`
+	Facing            string          `json:"facing"`
+	View              string          `json:"view,omitempty"`
+	IsCorner          bool            `json:"is_corner"`
+	SBUA              float64         `json:"sbua"`
+	PriceListID       string          `json:"price_list_id"`
+	PriceListVersion  int             `json:"price_list_version"`
+	PriceListName     string          `json:"price_list_name"`
+	AsOf              time.Time       `json:"as_of"`
+	ParkingType       string          `json:"parking_type,omitempty"`
+	ParkingSlots      int             `json:"parking_slots"`
+	Lines             []CostSheetLine `json:"lines"`
+	Discount          float64         `json:"discount"`
+	DiscountRequestID string          `json:"discount_request_id,omitempty"`
+	AgreementValue    float64         `json:"agreement_value"`   // consideration excluding statutory charges and GST
+	StatutoryCharges  float64         `json:"statutory_charges"` // collected on actuals, outside the agreement value
+	TotalGST          float64         `json:"total_gst"`
+	TotalPayable      float64         `json:"total_payable"` // payable to the developer
+	GuidelineValue    float64         `json:"guideline_value"`
+	StampDutyBasis    float64         `json:"stamp_duty_basis"` // higher of agreement and guideline value
+	StampDutyRate     float64         `json:"stamp_duty_rate"`
+	StampDuty         float64         `json:"stamp_duty"`
+	RegistrationRate  float64         `json:"registration_rate"`
+	RegistrationFee   float64         `json:"registration_fee"`
+	GrandTotal        float64         `json:"grand_total"` // total payable plus stamp duty and registration estimates
+}
+
+// BookingPriceLock is the cost sheet frozen on a booking
+type BookingPriceLock struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	BookingID        string    `json:"booking_id"`
+	UnitID           string    `json:"unit_id"`
+	PriceListID      string    `json:"price_list_id"`
+	PriceListVersion int       `json:"price_list_version"`
+	AgreementValue   float64   `json:"agreement_value"`
+	TotalGST         float64   `json:"total_gst"`
+	TotalPayable     float64   `json:"total_payable"`
+	GrandTotal       float64   `json:"grand_total"`
+	CostSheet        CostSheet `json:"cost_sheet"`
+	LockedBy         string    `json:"locked_by"`
+	LockedAt         time.Time `json:"locked_at"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// CreatePriceListRequest creates a draft price list version
+type CreatePriceListRequest struct {
+	ProjectID        string          `json:"project_id" binding:"required"`
+	BlockID          string          `json:"block_id"` // empty for a project-wide list
+	Name             string          `json:"name" binding:"required"`
+	BaseRatePerSqft  float64         `json:"base_rate_per_sqft" binding:"required"`
+	BaseGSTRate      float64         `json:"base_gst_rate"`
+	StampDutyRate    float64         `json:"stamp_duty_rate"`
+	RegistrationRate float64         `json:"registration_rate"`
+	RegistrationCap  float64         `json:"registration_cap"`
+	Notes            string          `json:"notes"`
+	Rules            []PriceListRule `json:"rules"`
+}
+
+// PublishPriceListRequest publishes a draft price list
+type PublishPriceListRequest struct {
+	EffectiveFrom string `json:"effective_from"` // YYYY-MM-DD, defaults to today
+}
+
+// CostSheetOptions are the buyer's choices that change a cost sheet
+type CostSheetOptions struct {
+	ParkingType     string   `json:"parking_type"`
+	ParkingSlots    int      `json:"parking_slots"` // defaults to 1 when a parking type is chosen
+	OptionalCharges []string `json:"optional_charges"`
+	GuidelineValue  float64  `json:"guideline_value"`
+	DiscountAmount  float64  `json:"-"` // set only from an approved discount request
+}
+
+// LockBookingPriceRequest freezes a booking's cost sheet
+type LockBookingPriceRequest struct {
+	CostSheetOptions
+	AsOf              string `json:"as_of"`               // YYYY-MM-DD, defaults to the booking date
+	DiscountRequestID string `json:"discount_request_id"` // approved discount to apply
+}
+
+// UpdateUnitPricingAttributesRequest sets the view and corner flags PLC rules use
+type UpdateUnitPricingAttributesRequest struct {
+	View     string `json:"view"`
+	IsCorner bool   `json:"is_corner"`
+}
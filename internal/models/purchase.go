@@ -358,6 +358,7 @@ type VendorInvoice struct {
 	VendorID        string     `json:"vendor_id"`
 	POID            *string    `json:"po_id"`
 	GRNID           *string    `json:"grn_id"`
+	ProjectID       *string    `json:"project_id"` // project the cost is booked against
 	InvoiceDate     time.Time  `json:"invoice_date"`
 	DueDate         *time.Time `json:"due_date"`
 	InvoiceAmount   float64    `json:"invoice_amount"`
@@ -376,24 +377,25 @@ type VendorInvoice struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// Relations
-	Vendor    *Vendor           `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
-	LineItems []InvoiceLineItem `json:"line_items,omitempty" gorm:"foreignKey:InvoiceID"`
-	Payments  []VendorPayment   `json:"payments,omitempty" gorm:"foreignKey:InvoiceID"`
+	Vendor    *Vendor                   `json:"vendor,omitempty" gorm:"foreignKey:VendorID"`
+	LineItems []PurchaseInvoiceLineItem `json:"line_items,omitempty" gorm:"foreignKey:InvoiceID"`
+	Payments  []VendorPayment           `json:"payments,omitempty" gorm:"foreignKey:InvoiceID"`
 }
 
 type PurchaseInvoiceLineItem struct {
-	ID          string    `json:"id" db:"id"`
-	TenantID    string    `json:"tenant_id" db:"tenant_id"`
-	InvoiceID   string    `json:"invoice_id" db:"invoice_id"`
-	LineNumber  int       `json:"line_number" db:"line_number"`
-	Description string    `json:"description" db:"description"`
-	Quantity    float64   `json:"quantity" db:"quantity"`
-	UnitPrice   float64   `json:"unit_price" db:"unit_price"`
-	LineTotal   float64   `json:"line_total" db:"line_total"`
-	HSNCode     string    `json:"hsn_code" db:"hsn_code"`
-	TaxRate     float64   `json:"tax_rate" db:"tax_rate"`
-	TaxAmount   float64   `json:"tax_amount" db:"tax_amount"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID           string    `json:"id" db:"id"`
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`
+	InvoiceID    string    `json:"invoice_id" db:"invoice_id"`
+	POLineItemID *string   `json:"po_line_item_id" db:"po_line_item_id"`
+	LineNumber   int       `json:"line_number" db:"line_number"`
+	Description  string    `json:"description" db:"description"`
+	Quantity     float64   `json:"quantity" db:"quantity"`
+	UnitPrice    float64   `json:"unit_price" db:"unit_price"`
+	LineTotal    float64   `json:"line_total" db:"line_total"`
+	HSNCode      string    `json:"hsn_code" db:"hsn_code"`
+	TaxRate      float64   `json:"tax_rate" db:"tax_rate"`
+	TaxAmount    float64   `json:"tax_amount" db:"tax_amount"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
 
 type VendorPayment struct {
@@ -478,6 +480,7 @@ func (ContractLabour) TableName() string           { return "contract_labour" }
 func (ContractService) TableName() string          { return "contract_services" }
 func (VendorInvoice) TableName() string            { return "vendor_invoices" }
 func (InvoiceLineItem) TableName() string          { return "invoice_line_items" }
+func (PurchaseInvoiceLineItem) TableName() string  { return "purchase_invoice_line_items" }
 func (VendorPayment) TableName() string            { return "vendor_payments" }
 func (VendorPerformanceMetrics) TableName() string { return "vendor_performance_metrics" }
 func (PurchaseApproval) TableName() string         { return "purchase_approvals" }
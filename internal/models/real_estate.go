@@ -66,7 +66,7 @@ type PropertyUnit struct {
 	PlinthArea            float64    `json:"plinth_area"`
 	SBUA                  float64    `json:"sbua"` // Super Built Up Area
 	UDSSqft               float64    `json:"uds_sqft"`
-	Status                string     `json:"status"` // available, booked, sold, reserved
+	Status                string     `json:"status"` // available, held, booked, registered, blocked
 	AllotedTo             string     `json:"alloted_to"`
 	AllotmentDate         *time.Time `json:"allotment_date"`
 	CreatedAt             time.Time  `json:"created_at"`
@@ -194,6 +194,7 @@ type BookingPayment struct {
 	ReceiptDate   *time.Time `json:"receipt_date"`
 	Towards       string     `json:"towards"` // advance, booking, installment_1, balance, etc.
 	Amount        float64    `json:"amount"`
+	TDSDeducted   float64    `json:"tds_deducted"` // 194-IA TDS deducted by the buyer, see booking_payment_tds
 	ChequeNumber  string     `json:"cheque_number"`
 	ChequeDate    *time.Time `json:"cheque_date"`
 	BankName      string     `json:"bank_name"`
@@ -338,6 +339,7 @@ type CreateCustomerBookingRequest struct {
 	CompositeGuidelineValue float64   `json:"composite_guideline_value"`
 	CarParkingType          string    `json:"car_parking_type"`
 	ParkingLocation         string    `json:"parking_location"`
+	DiscountRequestID       string    `json:"discount_request_id"` // approved discount on the unit's cost sheet
 }
 
 // CreateBookingPaymentRequest for recording a payment
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================
+// ACCOUNTS RECEIVABLE AGEING MODELS
+// ============================================
+
+// Ageing bucket labels used across AR reports
+const (
+	AgeingBucketCurrent = "current"
+	AgeingBucket0To30   = "0-30"
+	AgeingBucket31To60  = "31-60"
+	AgeingBucket61To90  = "61-90"
+	AgeingBucket90Plus  = "90+"
+)
+
+// ReceivableItem is a single outstanding installment after receipts are applied
+type ReceivableItem struct {
+	BookingID        string    `json:"booking_id"`
+	BookingReference string    `json:"booking_reference"`
+	ScheduleID       string    `json:"schedule_id"`
+	ScheduleName     string    `json:"schedule_name"`
+	ProjectID        string    `json:"project_id"`
+	ProjectName      string    `json:"project_name"`
+	BlockID          string    `json:"block_id"`
+	BlockName        string    `json:"block_name"`
+	UnitNumber       string    `json:"unit_number"`
+	CustomerName     string    `json:"customer_name"`
+	CustomerPhone    string    `json:"customer_phone"`
+	CustomerEmail    string    `json:"customer_email"`
+	DueDate          time.Time `json:"due_date"`
+	AmountDue        float64   `json:"amount_due"`
+	AmountReceived   float64   `json:"amount_received"`
+	Outstanding      float64   `json:"outstanding"`
+	DaysOverdue      int       `json:"days_overdue"`
+	Bucket           string    `json:"bucket"`
+}
+
+// ARAgeingRow aggregates outstanding amounts for one project, tower or customer
+type ARAgeingRow struct {
+	GroupKey         string  `json:"group_key"`
+	GroupName        string  `json:"group_name"`
+	Current          float64 `json:"current"` // not yet due
+	Bucket0To30      float64 `json:"bucket_0_30"`
+	Bucket31To60     float64 `json:"bucket_31_60"`
+	Bucket61To90     float64 `json:"bucket_61_90"`
+	Bucket90Plus     float64 `json:"bucket_90_plus"`
+	TotalOverdue     float64 `json:"total_overdue"`
+	TotalOutstanding float64 `json:"total_outstanding"`
+	BookingCount     int     `json:"booking_count"`
+}
+
+// ARAgeingReport is the AR ageing report as of a date
+type ARAgeingReport struct {
+	AsOfDate time.Time     `json:"as_of_date"`
+	GroupBy  string        `json:"group_by"` // project, tower, customer
+	Rows     []ARAgeingRow `json:"rows"`
+	Totals   ARAgeingRow   `json:"totals"`
+}
+
+// ============================================
+// DUNNING MODELS
+// ============================================
+
+// Dunning stages in escalation order
+const (
+	DunningStageReminder     = "reminder"
+	DunningStageDemandNotice = "demand_notice"
+	DunningStageFinalNotice  = "final_notice"
+)
+
+// DunningNotice records a dunning communication sent for a booking
+type DunningNotice struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	DunningRunID  string     `json:"dunning_run_id"`
+	BookingID     string     `json:"booking_id"`
+	ProjectID     string     `json:"project_id"`
+	CustomerName  string     `json:"customer_name"`
+	Stage         string     `json:"stage"`   // reminder, demand_notice, final_notice
+	Channel       string     `json:"channel"` // email, sms, whatsapp
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	AmountOverdue float64    `json:"amount_overdue"`
+	DaysOverdue   int        `json:"days_overdue"`
+	Status        string     `json:"status"` // sent, failed, skipped
+	FailureReason string     `json:"failure_reason,omitempty"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// DunningRun records one execution of the dunning process
+type DunningRun struct {
+	ID            string          `json:"id"`
+	TenantID      string          `json:"tenant_id"`
+	ProjectID     string          `json:"project_id"`
+	AsOfDate      time.Time       `json:"as_of_date"`
+	Channel       string          `json:"channel"`
+	BookingsSeen  int             `json:"bookings_seen"`
+	NoticesSent   int             `json:"notices_sent"`
+	NoticesFailed int             `json:"notices_failed"`
+	TotalOverdue  float64         `json:"total_overdue"`
+	Status        string          `json:"status"` // running, completed, failed, dry_run
+	Notices       []DunningNotice `json:"notices,omitempty"`
+	CreatedBy     string          `json:"created_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// ============================================
+// REQUEST/RESPONSE MODELS
+// ============================================
+
+// DunningRunRequest configures a dunning run; zero values fall back to defaults
+type DunningRunRequest struct {
+	ProjectID         string     `json:"project_id"`
+	AsOfDate          *time.Time `json:"as_of_date"`
+	Channel           string     `json:"channel"` // email (default), sms, whatsapp
+	ReminderAfterDays int        `json:"reminder_after_days"`
+	DemandAfterDays   int        `json:"demand_after_days"`
+	FinalAfterDays    int        `json:"final_after_days"`
+	MinGapDays        int        `json:"min_gap_days"` // minimum days between two notices to the same booking
+	DryRun            bool       `json:"dry_run"`
+}
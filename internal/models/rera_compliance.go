@@ -245,3 +245,128 @@ type ProjectCollectionResponse struct {
 	AvailableBalance     float64                   `json:"available_balance"`
 	RERAComplianceStatus string                    `json:"rera_compliance_status"`
 }
+
+// ============================================================================
+// RERA ESCROW (DESIGNATED ACCOUNT) ENFORCEMENT
+// ============================================================================
+
+// Section 4(2)(l)(D): at least 70% of every receipt goes to the designated account,
+// and withdrawals from it stay in proportion to certified completion
+const (
+	MinRERAEscrowPercent = 70.0
+
+	RERAReceiptSourceCollection     = "collection"
+	RERAReceiptSourceBookingPayment = "booking_payment"
+
+	RERAWithdrawalApproved = "approved"
+	RERAWithdrawalBlocked  = "blocked"
+)
+
+// RERAEscrowPolicy designates a project's RERA and free collection accounts
+type RERAEscrowPolicy struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	ProjectID     string    `json:"project_id"`
+	RERAAccountID string    `json:"rera_account_id"`
+	FreeAccountID string    `json:"free_account_id"`
+	EscrowPercent float64   `json:"escrow_percent"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// RERAReceiptSplit records how one customer receipt was divided between the accounts
+type RERAReceiptSplit struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	ProjectID     string    `json:"project_id"`
+	SourceType    string    `json:"source_type"` // collection, booking_payment
+	SourceID      string    `json:"source_id"`
+	BookingID     string    `json:"booking_id"`
+	ReceiptAmount float64   `json:"receipt_amount"`
+	EscrowPercent float64   `json:"escrow_percent"`
+	RERAAccountID string    `json:"rera_account_id"`
+	RERAAmount    float64   `json:"rera_amount"`
+	FreeAccountID string    `json:"free_account_id"`
+	FreeAmount    float64   `json:"free_amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RERACompletionCertificate is a certified percentage of completion backed by the
+// engineer's, architect's and chartered accountant's certificates
+type RERACompletionCertificate struct {
+	ID                  string    `json:"id"`
+	TenantID            string    `json:"tenant_id"`
+	ProjectID           string    `json:"project_id"`
+	CompletionPercent   float64   `json:"completion_percent"`
+	EngineerDocumentID  string    `json:"engineer_document_id"`
+	ArchitectDocumentID string    `json:"architect_document_id"`
+	CADocumentID        string    `json:"ca_document_id"`
+	CertifiedOn         time.Time `json:"certified_on"`
+	Remarks             *string   `json:"remarks,omitempty"`
+	CreatedBy           *string   `json:"created_by,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// RERAWithdrawal is one row of the withdrawal register. Blocked attempts are kept
+// alongside approved ones with the figures the decision was based on
+type RERAWithdrawal struct {
+	ID                  string    `json:"id"`
+	TenantID            string    `json:"tenant_id"`
+	ProjectID           string    `json:"project_id"`
+	WithdrawalNumber    string    `json:"withdrawal_number"`
+	RERAAccountID       string    `json:"rera_account_id"`
+	CertificateID       *string   `json:"certificate_id,omitempty"`
+	UtilizationType     string    `json:"utilization_type"`
+	Description         string    `json:"description"`
+	BillNumber          string    `json:"bill_number"`
+	Amount              float64   `json:"amount"`
+	RERADeposits        float64   `json:"rera_deposits"`
+	CompletionPercent   float64   `json:"completion_percent"`
+	AllowableLimit      float64   `json:"allowable_limit"`
+	PreviouslyWithdrawn float64   `json:"previously_withdrawn"`
+	AvailableBefore     float64   `json:"available_before"`
+	Status              string    `json:"status"` // approved, blocked
+	BlockReason         *string   `json:"block_reason,omitempty"`
+	UtilizationID       *string   `json:"utilization_id,omitempty"`
+	RequestedBy         *string   `json:"requested_by,omitempty"`
+	RequestedAt         time.Time `json:"requested_at"`
+}
+
+// RERAEscrowPosition is a project's designated account position and withdrawal headroom
+type RERAEscrowPosition struct {
+	ProjectID           string                     `json:"project_id"`
+	Policy              *RERAEscrowPolicy          `json:"policy"`
+	TotalReceipts       float64                    `json:"total_receipts"`
+	RERADeposits        float64                    `json:"rera_deposits"`
+	FreeDeposits        float64                    `json:"free_deposits"`
+	Certificate         *RERACompletionCertificate `json:"certificate,omitempty"`
+	CompletionPercent   float64                    `json:"completion_percent"`
+	AllowableLimit      float64                    `json:"allowable_limit"`
+	TotalWithdrawn      float64                    `json:"total_withdrawn"`
+	AvailableToWithdraw float64                    `json:"available_to_withdraw"`
+}
+
+// SetRERAEscrowPolicyRequest designates the RERA and free accounts of a project
+type SetRERAEscrowPolicyRequest struct {
+	RERAAccountID string  `json:"rera_account_id" validate:"required"`
+	FreeAccountID string  `json:"free_account_id" validate:"required"`
+	EscrowPercent float64 `json:"escrow_percent"` // defaults to 70
+}
+
+// CertifyCompletionRequest records a new certified percentage of completion
+type CertifyCompletionRequest struct {
+	CompletionPercent   float64    `json:"completion_percent" validate:"required,gt=0,lte=100"`
+	EngineerDocumentID  string     `json:"engineer_document_id" validate:"required"`
+	ArchitectDocumentID string     `json:"architect_document_id" validate:"required"`
+	CADocumentID        string     `json:"ca_document_id" validate:"required"`
+	CertifiedOn         *time.Time `json:"certified_on"`
+	Remarks             string     `json:"remarks"`
+}
+
+// RERAWithdrawalRequest asks to withdraw from the designated account
+type RERAWithdrawalRequest struct {
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	UtilizationType string  `json:"utilization_type" validate:"required"`
+	Description     string  `json:"description"`
+	BillNumber      string  `json:"bill_number"`
+}
@@ -0,0 +1,152 @@
+package models
+
+import "time"
+
+// ============================================================================
+// RERA QUARTERLY PROGRESS REPORT (QPR) MODELS
+// ============================================================================
+// A QPR is built per project and calendar quarter from bookings, the collection
+// ledger, tower milestones, site permits and fund utilization. Once submitted the
+// snapshot is locked and later quarters are validated against it.
+
+// QPR lifecycle
+const (
+	QPRStatusDraft     = "draft"
+	QPRStatusSubmitted = "submitted"
+)
+
+// QPR validation severities
+const (
+	QPRIssueError   = "error"
+	QPRIssueWarning = "warning"
+)
+
+// QPR tables a template section can render
+const (
+	QPRTableTowers    = "towers"
+	QPRTableApprovals = "approvals"
+	QPRTableFundUsage = "fund_usage"
+)
+
+// QPRUnitSummary is the inventory position at quarter end
+type QPRUnitSummary struct {
+	TotalUnits         int `json:"total_units"`
+	BookedCumulative   int `json:"booked_cumulative"`
+	BookedInQuarter    int `json:"booked_in_quarter"`
+	CancelledInQuarter int `json:"cancelled_in_quarter"`
+	UnsoldUnits        int `json:"unsold_units"`
+}
+
+// QPRCollections is money received and moved through the designated account
+type QPRCollections struct {
+	Quarter                 float64 `json:"quarter"`
+	Cumulative              float64 `json:"cumulative"`
+	RERADepositedQuarter    float64 `json:"rera_deposited_quarter"`
+	RERADepositedCumulative float64 `json:"rera_deposited_cumulative"`
+	WithdrawnQuarter        float64 `json:"withdrawn_quarter"`
+	WithdrawnCumulative     float64 `json:"withdrawn_cumulative"`
+}
+
+// QPRTowerProgress is construction progress of one tower (block)
+type QPRTowerProgress struct {
+	BlockID             string     `json:"block_id"`
+	BlockName           string     `json:"block_name"`
+	TotalUnits          int        `json:"total_units"`
+	BookedUnits         int        `json:"booked_units"`
+	MilestonesTotal     int        `json:"milestones_total"`
+	MilestonesCompleted int        `json:"milestones_completed"`
+	LastMilestone       string     `json:"last_milestone"`
+	LastCompletedOn     *time.Time `json:"last_completed_on,omitempty"`
+	PercentComplete     float64    `json:"percent_complete"`
+}
+
+// QPRApproval is a statutory approval held for the project's sites
+type QPRApproval struct {
+	PermitType       string     `json:"permit_type"`
+	PermitNumber     string     `json:"permit_number"`
+	IssuingAuthority string     `json:"issuing_authority"`
+	IssuedDate       *time.Time `json:"issued_date,omitempty"`
+	ExpiryDate       *time.Time `json:"expiry_date,omitempty"`
+	Status           string     `json:"status"`
+}
+
+// QPRFundUsage is fund utilization of one type
+type QPRFundUsage struct {
+	UtilizationType string  `json:"utilization_type"`
+	Quarter         float64 `json:"quarter"`
+	Cumulative      float64 `json:"cumulative"`
+}
+
+// QPRData is the pre-filled content of a report
+type QPRData struct {
+	Units       QPRUnitSummary     `json:"units"`
+	Collections QPRCollections     `json:"collections"`
+	Towers      []QPRTowerProgress `json:"towers"`
+	Approvals   []QPRApproval      `json:"approvals"`
+	FundUsage   []QPRFundUsage     `json:"fund_usage"`
+}
+
+// QPRIssue is a validation finding against the prior quarter or the data itself
+type QPRIssue struct {
+	Severity string `json:"severity"` // error, warning
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+}
+
+// QuarterlyProgressReport is one project's QPR for a quarter
+type QuarterlyProgressReport struct {
+	ID                    string     `json:"id"`
+	TenantID              string     `json:"tenant_id"`
+	ProjectID             string     `json:"project_id"`
+	ProjectName           string     `json:"project_name"`
+	StateCode             string     `json:"state_code"`
+	Quarter               string     `json:"quarter"` // YYYY-Qn
+	PeriodFrom            time.Time  `json:"period_from"`
+	PeriodTo              time.Time  `json:"period_to"` // exclusive
+	Status                string     `json:"status"`
+	Data                  QPRData    `json:"data"`
+	Issues                []QPRIssue `json:"issues"`
+	AcknowledgementNumber *string    `json:"acknowledgement_number,omitempty"`
+	GeneratedBy           *string    `json:"generated_by,omitempty"`
+	GeneratedAt           time.Time  `json:"generated_at"`
+	SubmittedBy           *string    `json:"submitted_by,omitempty"`
+	SubmittedAt           *time.Time `json:"submitted_at,omitempty"`
+}
+
+// QPRTemplateField is one labelled value in a template section
+type QPRTemplateField struct {
+	Label string `json:"label"`
+	Key   string `json:"key"` // e.g. units.booked_cumulative, collections.quarter
+}
+
+// QPRTemplateSection is either a list of fields or one of the QPR tables
+type QPRTemplateSection struct {
+	Title  string             `json:"title"`
+	Fields []QPRTemplateField `json:"fields,omitempty"`
+	Table  string             `json:"table,omitempty"` // towers, approvals, fund_usage
+}
+
+// QPRTemplate lays out a state portal's QPR format for export
+type QPRTemplate struct {
+	StateCode string               `json:"state_code"`
+	Name      string               `json:"name"`
+	Sections  []QPRTemplateSection `json:"sections"`
+	IsDefault bool                 `json:"is_default"`
+}
+
+// GenerateQPRRequest builds (or rebuilds) the draft QPR for a quarter
+type GenerateQPRRequest struct {
+	Quarter   string `json:"quarter" validate:"required"` // YYYY-Qn
+	StateCode string `json:"state_code"`                  // defaults to the project's state
+}
+
+// SubmitQPRRequest locks a draft QPR as filed on the portal
+type SubmitQPRRequest struct {
+	AcknowledgementNumber string `json:"acknowledgement_number"`
+}
+
+// SetQPRTemplateRequest configures a state's export layout for the tenant
+type SetQPRTemplateRequest struct {
+	Name     string               `json:"name" validate:"required"`
+	Sections []QPRTemplateSection `json:"sections" validate:"required"`
+}
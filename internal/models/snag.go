@@ -0,0 +1,257 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// SNAG LIST & DEFECT LIABILITY MODELS
+// ============================================================================
+// A joint inspection at handover records the unit checklist; signing it off
+// starts the RERA defect-liability period (DLP). Snags raised during the DLP,
+// by staff or through the customer portal, are assigned to a contractor under
+// a severity SLA, and the cost of fixing them can be back-charged to the
+// contractor's open invoices.
+
+// DefectLiabilityYears is the RERA defect-liability period from handover
+const DefectLiabilityYears = 5
+
+// Handover inspection statuses
+const (
+	InspectionStatusDraft     = "draft"
+	InspectionStatusSignedOff = "signed_off"
+)
+
+// Checklist item results
+const (
+	ChecklistResultOK   = "ok"
+	ChecklistResultSnag = "snag"
+	ChecklistResultNA   = "na"
+)
+
+// Snag statuses
+const (
+	SnagStatusOpen     = "open"
+	SnagStatusAssigned = "assigned" // with a contractor, SLA running
+	SnagStatusResolved = "resolved" // contractor has fixed it, awaiting customer acceptance
+	SnagStatusClosed   = "closed"
+	SnagStatusRejected = "rejected" // not a defect, or outside the builder's liability
+)
+
+// Snag review actions
+const (
+	SnagActionClose  = "close"
+	SnagActionReopen = "reopen"
+	SnagActionReject = "reject"
+)
+
+// Snag severities
+const (
+	SnagSeverityCritical = "critical"
+	SnagSeverityMajor    = "major"
+	SnagSeverityMinor    = "minor"
+)
+
+// Where a snag was reported
+const (
+	SnagSourceInspection = "handover_inspection"
+	SnagSourcePortal     = "customer_portal"
+	SnagSourceStaff      = "staff"
+)
+
+// Snag photo stages
+const (
+	SnagPhotoReported = "reported"
+	SnagPhotoResolved = "resolved"
+)
+
+// SnagCategories are the accepted snag categories
+var SnagCategories = []string{
+	"seepage", "tiles", "plumbing", "electrical", "carpentry", "painting",
+	"doors_windows", "fixtures", "civil", "other",
+}
+
+// VendorBackChargeApplied is a back-charge deducted from the contractor's invoice
+const VendorBackChargeApplied = "applied"
+
+// HandoverInspection is the joint inspection of a unit with the customer at handover
+type HandoverInspection struct {
+	ID                     string                  `json:"id"`
+	TenantID               string                  `json:"tenant_id"`
+	BookingID              string                  `json:"booking_id"`
+	UnitID                 string                  `json:"unit_id"`
+	ProjectID              string                  `json:"project_id"`
+	InspectionDate         time.Time               `json:"inspection_date"`
+	CustomerRepresentative string                  `json:"customer_representative"`
+	Status                 string                  `json:"status"` // draft, signed_off
+	HandoverDate           *time.Time              `json:"handover_date,omitempty"`
+	DLPExpiry              *time.Time              `json:"dlp_expiry,omitempty"`
+	SnagCount              int                     `json:"snag_count"`
+	Items                  []HandoverChecklistItem `json:"items,omitempty"`
+	InspectedBy            *string                 `json:"inspected_by,omitempty"`
+	SignedOffBy            *string                 `json:"signed_off_by,omitempty"`
+	SignedOffAt            *time.Time              `json:"signed_off_at,omitempty"`
+	CreatedAt              time.Time               `json:"created_at"`
+	UpdatedAt              time.Time               `json:"updated_at"`
+}
+
+// HandoverChecklistItem is one line of the joint inspection checklist
+type HandoverChecklistItem struct {
+	ID           string  `json:"id"`
+	InspectionID string  `json:"inspection_id"`
+	Area         string  `json:"area"` // e.g. Master Bedroom, Kitchen
+	Item         string  `json:"item"` // e.g. Flooring, Wall finish
+	Result       string  `json:"result"`
+	Remarks      string  `json:"remarks"`
+	SnagID       *string `json:"snag_id,omitempty"` // raised when the result is snag
+}
+
+// UnitDefectLiability is a unit's defect-liability period
+type UnitDefectLiability struct {
+	UnitID        string    `json:"unit_id"`
+	UnitNumber    string    `json:"unit_number"`
+	BookingID     string    `json:"booking_id"`
+	ProjectID     string    `json:"project_id"`
+	InspectionID  string    `json:"inspection_id"`
+	HandoverDate  time.Time `json:"handover_date"`
+	DLPExpiry     time.Time `json:"dlp_expiry"`
+	DaysRemaining int       `json:"days_remaining"`
+	IsActive      bool      `json:"is_active"`
+	OpenSnags     int       `json:"open_snags"`
+}
+
+// SnagPhoto is a photo of a snag as reported or after the fix
+type SnagPhoto struct {
+	ID         string    `json:"id"`
+	SnagID     string    `json:"snag_id"`
+	Stage      string    `json:"stage"` // reported, resolved
+	PhotoURL   string    `json:"photo_url"`
+	UploadedBy *string   `json:"uploaded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Snag is a defect reported on a handed-over unit
+type Snag struct {
+	ID              string             `json:"id"`
+	TenantID        string             `json:"tenant_id"`
+	SnagNumber      string             `json:"snag_number"`
+	UnitID          string             `json:"unit_id"`
+	BookingID       string             `json:"booking_id"`
+	ProjectID       string             `json:"project_id"`
+	InspectionID    *string            `json:"inspection_id,omitempty"`
+	Source          string             `json:"source"` // handover_inspection, customer_portal, staff
+	Category        string             `json:"category"`
+	Severity        string             `json:"severity"` // critical, major, minor
+	Location        string             `json:"location"`
+	Description     string             `json:"description"`
+	Status          string             `json:"status"`
+	ContractorID    *string            `json:"contractor_id,omitempty"` // vendors.id
+	ContractorName  string             `json:"contractor_name,omitempty"`
+	AssignedAt      *time.Time         `json:"assigned_at,omitempty"`
+	SLADueDate      *time.Time         `json:"sla_due_date,omitempty"`
+	SLABreached     bool               `json:"sla_breached"`
+	ResolvedAt      *time.Time         `json:"resolved_at,omitempty"`
+	ResolutionNotes string             `json:"resolution_notes,omitempty"`
+	ClosedAt        *time.Time         `json:"closed_at,omitempty"`
+	ReviewRemarks   string             `json:"review_remarks,omitempty"`
+	BackCharged     float64            `json:"back_charged"`
+	Photos          []SnagPhoto        `json:"photos,omitempty"`
+	BackCharges     []VendorBackCharge `json:"back_charges,omitempty"`
+	RaisedBy        *string            `json:"raised_by,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// VendorBackCharge is a recovery from a vendor set off against their open invoice
+type VendorBackCharge struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	BackChargeNumber string    `json:"back_charge_number"`
+	VendorID         string    `json:"vendor_id"`
+	VendorName       string    `json:"vendor_name"`
+	InvoiceID        string    `json:"invoice_id"`
+	InvoiceNumber    string    `json:"invoice_number"`
+	ProjectID        string    `json:"project_id"`
+	SnagID           *string   `json:"snag_id,omitempty"`
+	Amount           float64   `json:"amount"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"` // applied
+	JournalEntryID   *string   `json:"journal_entry_id,omitempty"`
+	CreatedBy        *string   `json:"created_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// HandoverChecklistInput is one checklist line; a snag result raises a snag
+type HandoverChecklistInput struct {
+	Area      string   `json:"area" validate:"required"`
+	Item      string   `json:"item" validate:"required"`
+	Result    string   `json:"result" validate:"required"` // ok, snag, na
+	Remarks   string   `json:"remarks"`
+	Category  string   `json:"category"` // required for snag results
+	Severity  string   `json:"severity"` // defaults to minor
+	PhotoURLs []string `json:"photo_urls"`
+}
+
+// CreateHandoverInspectionRequest records the joint inspection checklist
+type CreateHandoverInspectionRequest struct {
+	BookingID              string                   `json:"booking_id" validate:"required"`
+	InspectionDate         *time.Time               `json:"inspection_date"` // defaults to today
+	CustomerRepresentative string                   `json:"customer_representative"`
+	Items                  []HandoverChecklistInput `json:"items" validate:"required"`
+}
+
+// SignOffHandoverRequest completes the handover and starts the DLP
+type SignOffHandoverRequest struct {
+	HandoverDate *time.Time `json:"handover_date"` // defaults to the inspection date
+}
+
+// RaiseSnagRequest reports a snag on a handed-over unit
+type RaiseSnagRequest struct {
+	BookingID   string   `json:"booking_id"` // from the path on the customer portal
+	Category    string   `json:"category" validate:"required"`
+	Severity    string   `json:"severity"` // defaults to minor
+	Location    string   `json:"location"`
+	Description string   `json:"description" validate:"required"`
+	PhotoURLs   []string `json:"photo_urls"`
+}
+
+// AssignSnagRequest assigns a snag to a contractor and starts its SLA
+type AssignSnagRequest struct {
+	ContractorID string `json:"contractor_id" validate:"required"`
+}
+
+// ResolveSnagRequest records the contractor's fix
+type ResolveSnagRequest struct {
+	ResolutionNotes string   `json:"resolution_notes" validate:"required"`
+	PhotoURLs       []string `json:"photo_urls"`
+}
+
+// ReviewSnagRequest closes or reopens a resolved snag, or rejects an open one
+type ReviewSnagRequest struct {
+	Action  string `json:"action" validate:"required"` // close, reopen, reject
+	Remarks string `json:"remarks"`
+}
+
+// AddSnagPhotosRequest attaches more photos to a snag
+type AddSnagPhotosRequest struct {
+	Stage     string   `json:"stage"` // reported, resolved; defaults to reported
+	PhotoURLs []string `json:"photo_urls" validate:"required"`
+}
+
+// BackChargeSnagRequest recovers the cost of a snag from its contractor
+type BackChargeSnagRequest struct {
+	InvoiceID string  `json:"invoice_id" validate:"required"` // the contractor's open invoice
+	Amount    float64 `json:"amount" validate:"required"`
+	Reason    string  `json:"reason"`
+}
+
+// SnagFilter narrows the snag list
+type SnagFilter struct {
+	ProjectID    string
+	UnitID       string
+	BookingID    string
+	ContractorID string
+	Status       string
+	Category     string
+	OverdueOnly  bool
+}
@@ -0,0 +1,155 @@
+package models
+
+import "time"
+
+// ============================================================================
+// SUBVENTION (BUILDER-PAID PRE-EMI) MODELS
+// ============================================================================
+// Under a subvention scheme the developer pays the pre-EMI interest on the
+// customer's home loan until possession. Each agreement is tied to the booking's
+// BankFinancing and carries a monthly obligation schedule that is provisioned to
+// the GL as it accrues and paid to the lender.
+
+// Subvention agreement statuses
+const (
+	SubventionActive    = "active"
+	SubventionStopped   = "stopped"   // ended early by possession, cancellation or by hand
+	SubventionCompleted = "completed" // ran to the scheme end date
+)
+
+// Reasons a subvention agreement stops
+const (
+	SubventionStopPossession   = "possession"
+	SubventionStopCancellation = "cancellation"
+	SubventionStopManual       = "manual"
+)
+
+// Subvention obligation statuses
+const (
+	SubventionObligationScheduled   = "scheduled"   // projected on the loan drawn so far
+	SubventionObligationProvisioned = "provisioned" // accrued and provided for in the GL
+	SubventionObligationPaid        = "paid"
+	SubventionObligationCancelled   = "cancelled" // falls after the agreement stopped
+)
+
+// SubventionAgreement is the developer's undertaking to pay pre-EMI interest on a booking's loan
+type SubventionAgreement struct {
+	ID              string                 `json:"id"`
+	TenantID        string                 `json:"tenant_id"`
+	AgreementNumber string                 `json:"agreement_number"`
+	FinancingID     string                 `json:"financing_id"`
+	BookingID       string                 `json:"booking_id"`
+	BankID          string                 `json:"bank_id"`
+	ProjectID       string                 `json:"project_id"`
+	SchemeName      string                 `json:"scheme_name"`
+	InterestRate    float64                `json:"interest_rate"` // pre-EMI rate, % per annum
+	StartDate       time.Time              `json:"start_date"`
+	EndDate         time.Time              `json:"end_date"`   // scheme end, usually the committed possession date
+	MaxAmount       float64                `json:"max_amount"` // cap on total interest borne, 0 for none
+	Status          string                 `json:"status"`
+	StopReason      string                 `json:"stop_reason,omitempty"`
+	StoppedOn       *time.Time             `json:"stopped_on,omitempty"`
+	Provisioned     float64                `json:"provisioned"`
+	Paid            float64                `json:"paid"`
+	Payable         float64                `json:"payable"`   // provisioned, not yet paid to the bank
+	Projected       float64                `json:"projected"` // scheduled, not yet accrued
+	Obligations     []SubventionObligation `json:"obligations,omitempty"`
+	CreatedBy       *string                `json:"created_by,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// SubventionObligation is a month's pre-EMI interest payable to the lender
+type SubventionObligation struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	AgreementID    string     `json:"agreement_id"`
+	PeriodFrom     time.Time  `json:"period_from"`
+	PeriodTo       time.Time  `json:"period_to"` // exclusive
+	DueDate        time.Time  `json:"due_date"`
+	LoanBalance    float64    `json:"loan_balance"` // loan drawn at the end of the period
+	Amount         float64    `json:"amount"`
+	Status         string     `json:"status"`
+	JournalEntryID *string    `json:"journal_entry_id,omitempty"` // provision entry
+	ProvisionedAt  *time.Time `json:"provisioned_at,omitempty"`
+	PaymentID      *string    `json:"payment_id,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+}
+
+// SubventionPayment is a payment of provisioned obligations to a lender
+type SubventionPayment struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	PaymentNumber  string    `json:"payment_number"`
+	BankID         string    `json:"bank_id"`
+	PaymentDate    time.Time `json:"payment_date"`
+	Amount         float64   `json:"amount"`
+	PaymentMode    string    `json:"payment_mode"`
+	Reference      string    `json:"reference"`
+	ObligationIDs  []string  `json:"obligation_ids"`
+	JournalEntryID *string   `json:"journal_entry_id,omitempty"`
+	CreatedBy      *string   `json:"created_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SubventionProvisionRun is the outcome of a provisioning run
+type SubventionProvisionRun struct {
+	AsOf              time.Time              `json:"as_of"`
+	AgreementsStopped int                    `json:"agreements_stopped"`
+	Provisioned       float64                `json:"provisioned"`
+	Obligations       []SubventionObligation `json:"obligations"`
+}
+
+// SubventionLiabilityRow is the subvention position of a project with one lender
+type SubventionLiabilityRow struct {
+	ProjectID   string  `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	BankID      string  `json:"bank_id"`
+	BankName    string  `json:"bank_name"`
+	Agreements  int     `json:"agreements"` // active agreements
+	LoanBalance float64 `json:"loan_balance"`
+	Provisioned float64 `json:"provisioned"`
+	Paid        float64 `json:"paid"`
+	Payable     float64 `json:"payable"`
+	Overdue     float64 `json:"overdue"`   // payable past its due date
+	Committed   float64 `json:"committed"` // scheduled till the scheme ends
+}
+
+// SubventionLiabilityReport is the subvention liability by project and lender
+type SubventionLiabilityReport struct {
+	AsOf      time.Time                `json:"as_of"`
+	Rows      []SubventionLiabilityRow `json:"rows"`
+	Payable   float64                  `json:"payable"`
+	Overdue   float64                  `json:"overdue"`
+	Committed float64                  `json:"committed"`
+}
+
+// CreateSubventionAgreementRequest puts a booking's loan under a subvention scheme
+type CreateSubventionAgreementRequest struct {
+	FinancingID  string     `json:"financing_id" validate:"required"`
+	SchemeName   string     `json:"scheme_name"`
+	InterestRate float64    `json:"interest_rate" validate:"required"`
+	StartDate    *time.Time `json:"start_date"` // defaults to today
+	EndDate      time.Time  `json:"end_date" validate:"required"`
+	MaxAmount    float64    `json:"max_amount"`
+}
+
+// ProvisionSubventionRequest provisions obligations whose period has ended
+type ProvisionSubventionRequest struct {
+	AsOf *time.Time `json:"as_of"` // defaults to today
+}
+
+// StopSubventionRequest ends an agreement before the scheme end date
+type StopSubventionRequest struct {
+	Reason   string     `json:"reason" validate:"required"` // possession, cancellation, manual
+	StopDate *time.Time `json:"stop_date"`                  // defaults to today
+}
+
+// RecordSubventionPaymentRequest pays provisioned obligations to a lender
+type RecordSubventionPaymentRequest struct {
+	BankID        string     `json:"bank_id" validate:"required"`
+	ObligationIDs []string   `json:"obligation_ids" validate:"required"`
+	PaymentDate   *time.Time `json:"payment_date"` // defaults to today
+	PaymentMode   string     `json:"payment_mode" validate:"required"`
+	Reference     string     `json:"reference"`
+}
@@ -242,9 +242,26 @@ type GSTInvoiceTracking struct {
 
 	CustomerID    string `json:"customer_id"`
 	CustomerGSTIN string `json:"customer_gstin"`
-
-	GSTRate   float64 `json:"gst_rate"`
-	GSTAmount float64 `json:"gst_amount"`
+	CustomerName  string `json:"customer_name"`
+
+	// Return reporting detail (GSTR-1)
+	DocumentType          string     `json:"document_type"`   // INV, CRN (credit note), DBN (debit note)
+	PlaceOfSupply         string     `json:"place_of_supply"` // 2-digit state code
+	ReverseCharge         bool       `json:"reverse_charge"`
+	HSNCode               string     `json:"hsn_code"`
+	HSNDescription        string     `json:"hsn_description"`
+	UQC                   string     `json:"uqc"` // unit quantity code, e.g. SQM, NOS
+	Quantity              float64    `json:"quantity"`
+	OriginalInvoiceNumber string     `json:"original_invoice_number"` // credit/debit notes
+	OriginalInvoiceDate   *time.Time `json:"original_invoice_date"`
+
+	GSTRate      float64 `json:"gst_rate"`
+	GSTAmount    float64 `json:"gst_amount"`
+	TaxableValue float64 `json:"taxable_value"`
+	IGSTAmount   float64 `json:"igst_amount"`
+	CGSTAmount   float64 `json:"cgst_amount"`
+	SGSTAmount   float64 `json:"sgst_amount"`
+	CessAmount   float64 `json:"cess_amount"`
 
 	InvoiceRaisedDate *time.Time `json:"invoice_raised_date"`
 	InvoiceCancelled  bool       `json:"invoice_cancelled"`
@@ -278,8 +295,15 @@ type GSTInputCredit struct {
 	InvoiceDate   *time.Time `json:"invoice_date"`
 	InvoiceAmount float64    `json:"invoice_amount"`
 
-	GSTRate   float64 `json:"gst_rate"`
-	GSTAmount float64 `json:"gst_amount"`
+	GSTRate       float64 `json:"gst_rate"`
+	GSTAmount     float64 `json:"gst_amount"`
+	TaxableValue  float64 `json:"taxable_value"`
+	IGSTAmount    float64 `json:"igst_amount"`
+	CGSTAmount    float64 `json:"cgst_amount"`
+	SGSTAmount    float64 `json:"sgst_amount"`
+	CessAmount    float64 `json:"cess_amount"`
+	PlaceOfSupply string  `json:"place_of_supply"`
+	ReverseCharge bool    `json:"reverse_charge"`
 
 	ITCEligible         bool   `json:"itc_eligible"`
 	ITCIneligibleReason string `json:"itc_ineligible_reason"`
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// TDS ENGINE MODELS
+// ============================================================================
+
+// TDS sections handled by the engine
+const (
+	TDSSection194C  = "194C"  // payments to contractors
+	TDSSection194H  = "194H"  // commission or brokerage
+	TDSSection194J  = "194J"  // fees for professional services
+	TDSSection194IA = "194IA" // purchase of immovable property
+)
+
+// TDS deduction sources
+const (
+	TDSSourceVendorInvoice    = "vendor_invoice"
+	TDSSourceVendorPayment    = "vendor_payment"
+	TDSSourceBrokerPayout     = "broker_payout"
+	TDSSourcePartnerPayout    = "partner_payout"
+	TDSSourcePropertyPurchase = "property_purchase"
+)
+
+// TDS payee types
+const (
+	TDSPayeeVendor         = "vendor"
+	TDSPayeeBroker         = "broker"
+	TDSPayeePartner        = "partner"
+	TDSPayeePropertySeller = "property_seller"
+)
+
+// TDS statement forms
+const (
+	TDSForm26Q  = "26Q"  // resident payees
+	TDSForm27Q  = "27Q"  // non-resident payees
+	TDSForm26QB = "26QB" // property purchase, filed per transaction
+)
+
+// TDS ledger entry statuses
+const (
+	TDSStatusPendingDeposit = "pending_deposit"
+	TDSStatusDeposited      = "deposited"
+	TDSStatusBelowThreshold = "below_threshold"
+	TDSStatusCaughtUp       = "caught_up" // below threshold earlier, deducted once the aggregate was crossed
+)
+
+// TDSSectionRule holds a section's rates and thresholds for a financial year
+type TDSSectionRule struct {
+	Section                string  `json:"section"`
+	Description            string  `json:"description"`
+	RateIndividual         float64 `json:"rate_individual"` // individual / HUF payee
+	RateOthers             float64 `json:"rate_others"`
+	NoPANRate              float64 `json:"no_pan_rate"`             // section 206AA floor
+	SingleThreshold        float64 `json:"single_threshold"`        // per payment / credit
+	AggregateThreshold     float64 `json:"aggregate_threshold"`     // per payee per financial year
+	ConsiderationThreshold float64 `json:"consideration_threshold"` // 194-IA: total consideration
+}
+
+// TDSComputation is the engine's decision for one payment or credit
+type TDSComputation struct {
+	Section        string  `json:"section"`
+	Rate           float64 `json:"rate"`
+	BaseAmount     float64 `json:"base_amount"`
+	TDSAmount      float64 `json:"tds_amount"`
+	PANMissing     bool    `json:"pan_missing"`
+	BelowThreshold bool    `json:"below_threshold"`
+	Note           string  `json:"note,omitempty"`
+}
+
+// TDSDeductionInput describes a payment or credit the engine should assess
+type TDSDeductionInput struct {
+	SourceType         string
+	SourceID           string
+	PayeeType          string
+	PayeeID            string
+	PayeeName          string
+	PAN                string
+	NonResident        bool
+	Section            string
+	RateOverride       float64 // vendor-specific rate, e.g. a lower deduction certificate
+	Amount             float64
+	TotalConsideration float64 // 194-IA only
+	TransactionDate    time.Time
+	DeductionID        string // tds_deductions row, for vendor deductions
+	CreatedBy          string
+}
+
+// TDSLedgerEntry is one assessed payment or credit in the TDS payable ledger
+type TDSLedgerEntry struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	FinancialYear   string     `json:"financial_year"`
+	Quarter         string     `json:"quarter"` // Q1..Q4
+	Form            string     `json:"form"`
+	SectionCode     string     `json:"section_code"`
+	SourceType      string     `json:"source_type"`
+	SourceID        string     `json:"source_id"`
+	PayeeType       string     `json:"payee_type"`
+	PayeeID         string     `json:"payee_id"`
+	PayeeName       string     `json:"payee_name"`
+	PAN             string     `json:"pan"`
+	TransactionDate time.Time  `json:"transaction_date"`
+	Amount          float64    `json:"amount"`
+	Rate            float64    `json:"rate"`
+	BaseAmount      float64    `json:"base_amount"`
+	TDSAmount       float64    `json:"tds_amount"`
+	PANMissing      bool       `json:"pan_missing"`
+	DepositDueDate  *time.Time `json:"deposit_due_date"`
+	Status          string     `json:"status"`
+	ChallanID       *string    `json:"challan_id"`
+	DeductionID     *string    `json:"deduction_id"` // tds_deductions row offsetting the vendor invoice
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TDSChallan is an ITNS 281 deposit of TDS
+type TDSChallan struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	ChallanSerialNo string    `json:"challan_serial_no"`
+	BSRCode         string    `json:"bsr_code"`
+	DepositDate     time.Time `json:"deposit_date"`
+	TDSAmount       float64   `json:"tds_amount"`
+	InterestDue     float64   `json:"interest_due"` // section 201(1A) on late deposit
+	InterestPaid    float64   `json:"interest_paid"`
+	FeePaid         float64   `json:"fee_paid"`
+	TotalAmount     float64   `json:"total_amount"`
+	EntryCount      int       `json:"entry_count"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TDSPayableSection totals one section of the TDS payable ledger
+type TDSPayableSection struct {
+	SectionCode string  `json:"section_code"`
+	Deducted    float64 `json:"deducted"`
+	Deposited   float64 `json:"deposited"`
+	Outstanding float64 `json:"outstanding"`
+	Overdue     float64 `json:"overdue"`
+}
+
+// TDSPayableLedger is the TDS payable position for a financial year
+type TDSPayableLedger struct {
+	FinancialYear string              `json:"financial_year"`
+	AsOf          time.Time           `json:"as_of"`
+	Sections      []TDSPayableSection `json:"sections"`
+	Entries       []TDSLedgerEntry    `json:"entries"`
+}
+
+// ============================================================================
+// QUARTERLY STATEMENTS (26Q / 27Q)
+// ============================================================================
+
+// TDSReturnChallan is the challan block of a quarterly statement
+type TDSReturnChallan struct {
+	SerialNo        int     `json:"serial_no"`
+	ChallanSerialNo string  `json:"challan_serial_no"`
+	BSRCode         string  `json:"bsr_code"`
+	DepositDate     string  `json:"deposit_date"` // dd/mm/yyyy
+	TDSAmount       float64 `json:"tds_amount"`
+	Interest        float64 `json:"interest"`
+	Fee             float64 `json:"fee"`
+	Total           float64 `json:"total"`
+	DeducteeCount   int     `json:"deductee_count"`
+}
+
+// TDSReturnDeductee is a deductee record of a quarterly statement
+type TDSReturnDeductee struct {
+	ChallanSerialNo int     `json:"challan_serial_no"`
+	Section         string  `json:"section"`
+	PAN             string  `json:"pan"` // PANNOTAVBL when missing
+	DeducteeName    string  `json:"deductee_name"`
+	PaymentDate     string  `json:"payment_date"`
+	AmountPaid      float64 `json:"amount_paid"`
+	Rate            float64 `json:"rate"`
+	TDSDeducted     float64 `json:"tds_deducted"`
+	TDSDeposited    float64 `json:"tds_deposited"`
+	DeductionDate   string  `json:"deduction_date"`
+	ReasonCode      string  `json:"reason_code,omitempty"` // C: higher rate for missing PAN
+	SourceType      string  `json:"source_type"`
+	SourceID        string  `json:"source_id"`
+}
+
+// TDSReturnIssue is a validation finding on a quarterly statement
+type TDSReturnIssue struct {
+	Severity string `json:"severity"` // error, warning
+	Payee    string `json:"payee,omitempty"`
+	Message  string `json:"message"`
+}
+
+// TDSReturnData is the data for a quarterly 26Q / 27Q statement
+type TDSReturnData struct {
+	Form          string              `json:"form"`
+	FinancialYear string              `json:"financial_year"`
+	Quarter       string              `json:"quarter"`
+	DeductorPAN   string              `json:"deductor_pan"`
+	Challans      []TDSReturnChallan  `json:"challans"`
+	Deductees     []TDSReturnDeductee `json:"deductees"`
+	TotalPaid     float64             `json:"total_paid"`
+	TotalTDS      float64             `json:"total_tds"`
+	Issues        []TDSReturnIssue    `json:"issues"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// ComputeTDSRequest previews the TDS on a payment without recording it
+type ComputeTDSRequest struct {
+	Section             string  `json:"section" binding:"required"`
+	PAN                 string  `json:"pan"`
+	Amount              float64 `json:"amount" binding:"required"`
+	RateOverride        float64 `json:"rate_override"`
+	AggregatePaid       float64 `json:"aggregate_paid"`       // already paid to the payee this year
+	AggregateUndeducted float64 `json:"aggregate_undeducted"` // of which no TDS was deducted
+	TotalConsideration  float64 `json:"total_consideration"`
+}
+
+// RecordPropertyPurchaseTDSRequest deducts 194-IA on an instalment paid for property bought
+type RecordPropertyPurchaseTDSRequest struct {
+	PaymentReference   string  `json:"payment_reference" binding:"required"`
+	SellerName         string  `json:"seller_name" binding:"required"`
+	SellerPAN          string  `json:"seller_pan"`
+	TotalConsideration float64 `json:"total_consideration" binding:"required"`
+	PaymentAmount      float64 `json:"payment_amount" binding:"required"`
+	PaymentDate        string  `json:"payment_date" binding:"required"` // YYYY-MM-DD
+}
+
+// CreateTDSChallanRequest records a TDS deposit against pending ledger entries
+type CreateTDSChallanRequest struct {
+	ChallanSerialNo string   `json:"challan_serial_no" binding:"required"`
+	BSRCode         string   `json:"bsr_code" binding:"required"`
+	DepositDate     string   `json:"deposit_date" binding:"required"` // YYYY-MM-DD
+	EntryIDs        []string `json:"entry_ids" binding:"required"`
+	InterestPaid    float64  `json:"interest_paid"`
+	FeePaid         float64  `json:"fee_paid"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// THREE-WAY MATCH MODELS (PO / GRN / INVOICE)
+// ============================================================================
+
+// Invoice match statuses (vendor_invoices.matched_status)
+const (
+	MatchStatusNotMatched       = "Not_Matched"
+	MatchStatusThreeWayMatch    = "Three_Way_Match"
+	MatchStatusMismatch         = "Mismatch"
+	MatchStatusOverrideApproved = "Override_Approved"
+)
+
+// Match exception types
+const (
+	MatchExceptionNoPurchaseOrder  = "no_purchase_order"
+	MatchExceptionVendorMismatch   = "vendor_mismatch"
+	MatchExceptionUnmatchedLine    = "unmatched_line"
+	MatchExceptionPriceVariance    = "price_variance"
+	MatchExceptionQuantityOrdered  = "quantity_exceeds_ordered"
+	MatchExceptionQuantityReceived = "quantity_exceeds_received"
+	MatchExceptionInvoiceTotal     = "invoice_total_mismatch"
+)
+
+// PurchaseMatchTolerance holds the tenant's allowed variances for invoice matching
+type PurchaseMatchTolerance struct {
+	ID                   string    `json:"id"`
+	TenantID             string    `json:"tenant_id"`
+	PriceTolerancePct    float64   `json:"price_tolerance_pct"`    // invoice unit price over PO price, %
+	QuantityTolerancePct float64   `json:"quantity_tolerance_pct"` // invoiced quantity over received quantity, %
+	AmountTolerance      float64   `json:"amount_tolerance"`       // invoice total vs sum of lines, absolute
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// InvoiceMatchException is one mismatch holding an invoice in the exceptions queue
+type InvoiceMatchException struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	InvoiceID       string     `json:"invoice_id"`
+	InvoiceNumber   string     `json:"invoice_number,omitempty"`
+	VendorID        string     `json:"vendor_id,omitempty"`
+	InvoiceLineID   string     `json:"invoice_line_id"`
+	POLineItemID    string     `json:"po_line_item_id"`
+	ExceptionType   string     `json:"exception_type"`
+	ExpectedValue   float64    `json:"expected_value"`
+	ActualValue     float64    `json:"actual_value"`
+	VariancePct     float64    `json:"variance_pct"`
+	Message         string     `json:"message"`
+	Status          string     `json:"status"` // open, accepted, rejected, superseded
+	ResolvedBy      *string    `json:"resolved_by"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	ResolutionNotes string     `json:"resolution_notes"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// InvoiceMatchResult is the outcome of matching an invoice against its PO and receipts
+type InvoiceMatchResult struct {
+	InvoiceID     string                  `json:"invoice_id"`
+	MatchedStatus string                  `json:"matched_status"`
+	InvoiceStatus string                  `json:"invoice_status"`
+	Matched       bool                    `json:"matched"`
+	Exceptions    []InvoiceMatchException `json:"exceptions"`
+}
+
+// ============================================================================
+// REQUEST/RESPONSE MODELS
+// ============================================================================
+
+// UpsertMatchToleranceRequest sets the tenant's match tolerances
+type UpsertMatchToleranceRequest struct {
+	PriceTolerancePct    float64 `json:"price_tolerance_pct"`
+	QuantityTolerancePct float64 `json:"quantity_tolerance_pct"`
+	AmountTolerance      float64 `json:"amount_tolerance"`
+}
+
+// ResolveMatchExceptionRequest accepts or rejects a match exception
+type ResolveMatchExceptionRequest struct {
+	Accept bool   `json:"accept"`
+	Notes  string `json:"notes" binding:"required"`
+}
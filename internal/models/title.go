@@ -32,7 +32,7 @@ type TitleIssue struct {
 	ID               int64      `gorm:"primaryKey" json:"id"`
 	TenantID         int64      `json:"tenant_id"`
 	ClearanceID      int64      `json:"clearance_id"`
-	IssueType        string     `json:"issue_type"` // lien, encumbrance, dispute, boundary_issue, mortgage, legal_claim, tax_issue
+	IssueType        string     `json:"issue_type"` // lien, encumbrance, dispute, boundary_issue, mortgage, legal_claim, tax_issue, chain_gap
 	IssueTitle       string     `json:"issue_title"`
 	IssueDescription *string    `json:"issue_description"`
 	Severity         string     `json:"severity"` // low, medium, high, critical
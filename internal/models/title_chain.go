@@ -0,0 +1,233 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================
+// Chain of Title Models
+// ============================================
+
+// TitleChainYears is the period the chain of title must be traced back
+const TitleChainYears = 30
+
+// Title issue type raised for a break in the chain of title
+const TitleIssueTypeChainGap = "chain_gap"
+
+// Deed types
+const (
+	DeedTypeSale        = "sale"
+	DeedTypeGift        = "gift"
+	DeedTypePartition   = "partition"
+	DeedTypeJDA         = "jda"
+	DeedTypeSettlement  = "settlement"
+	DeedTypeRelease     = "release"
+	DeedTypeWill        = "will"
+	DeedTypeInheritance = "inheritance"
+	DeedTypeCourtDecree = "court_decree"
+)
+
+// Deed party roles
+const (
+	DeedPartyTransferor = "transferor"
+	DeedPartyTransferee = "transferee"
+	DeedPartyConsenting = "consenting_party"
+	DeedPartyWitness    = "witness"
+)
+
+// Encumbrance types and statuses
+const (
+	EncumbranceMortgage   = "mortgage"
+	EncumbranceLien       = "lien"
+	EncumbranceLitigation = "litigation"
+	EncumbranceLease      = "lease"
+	EncumbranceEasement   = "easement"
+	EncumbranceAttachment = "attachment"
+
+	EncumbranceSubsisting = "subsisting"
+	EncumbranceDischarged = "discharged"
+)
+
+// Chain gap kinds
+const (
+	ChainGapNoRootDeed = "no_root_deed" // chain does not reach back to the start of the search period
+	ChainGapBrokenLink = "broken_link"  // transferor was not a holder under the previous deed
+	ChainGapNoDeeds    = "no_deeds"     // no deeds recorded for the parcel
+)
+
+// Chain gap statuses
+const (
+	ChainGapStatusOpen   = "open"   // still detected in the latest analysis
+	ChainGapStatusClosed = "closed" // no longer detected after deeds were added or corrected
+)
+
+// TitleParcel represents a land parcel identified by its survey number
+type TitleParcel struct {
+	ID           int64      `gorm:"primaryKey" json:"id"`
+	TenantID     int64      `json:"tenant_id"`
+	ClearanceID  *int64     `json:"clearance_id"` // chain gaps and encumbrances raise issues on this clearance
+	SurveyNumber string     `json:"survey_number"`
+	SubDivision  *string    `json:"sub_division"`
+	Village      string     `json:"village"`
+	Taluk        *string    `json:"taluk"`
+	District     *string    `json:"district"`
+	State        *string    `json:"state"`
+	AreaAcres    float64    `json:"area_acres"`
+	LandType     *string    `json:"land_type"` // agricultural, converted, urban
+	Notes        *string    `json:"notes"`
+	CreatedBy    *int64     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+}
+
+// TitleDeed represents a registered deed in a parcel's chain of title
+type TitleDeed struct {
+	ID                 int64            `gorm:"primaryKey" json:"id"`
+	TenantID           int64            `json:"tenant_id"`
+	ParcelID           int64            `json:"parcel_id"`
+	DeedType           string           `json:"deed_type"` // sale, gift, partition, jda, settlement, release, will, inheritance, court_decree
+	DocumentNumber     string           `json:"document_number"`
+	RegistrationDate   time.Time        `json:"registration_date"`
+	ExecutionDate      *time.Time       `json:"execution_date"`
+	SubRegistrarOffice *string          `json:"sub_registrar_office"`
+	Consideration      float64          `json:"consideration"`
+	DocumentURL        *string          `json:"document_url"`
+	Notes              *string          `json:"notes"`
+	Parties            []TitleDeedParty `json:"parties"`
+	CreatedBy          *int64           `json:"created_by"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+	DeletedAt          *time.Time       `json:"deleted_at"`
+}
+
+// TitleDeedParty represents a party to a deed
+type TitleDeedParty struct {
+	ID                int64   `gorm:"primaryKey" json:"id"`
+	TenantID          int64   `json:"tenant_id"`
+	DeedID            int64   `json:"deed_id"`
+	PartyRole         string  `json:"party_role"` // transferor, transferee, consenting_party, witness
+	PartyName         string  `json:"party_name"`
+	PartyType         *string `json:"party_type"` // individual, company, huf, trust, government
+	IdentityReference *string `json:"identity_reference"`
+}
+
+// TitleEncumbrance represents a mortgage, lien, litigation or other charge recorded on a parcel
+type TitleEncumbrance struct {
+	ID                 int64      `gorm:"primaryKey" json:"id"`
+	TenantID           int64      `json:"tenant_id"`
+	ParcelID           int64      `json:"parcel_id"`
+	DeedID             *int64     `json:"deed_id"`
+	EncumbranceType    string     `json:"encumbrance_type"` // mortgage, lien, litigation, lease, easement, attachment
+	HolderName         string     `json:"holder_name"`      // mortgagee, lien holder or plaintiff
+	ReferenceNumber    *string    `json:"reference_number"` // document or case number
+	RecordedDate       time.Time  `json:"recorded_date"`
+	Amount             float64    `json:"amount"`
+	Status             string     `json:"status"` // subsisting, discharged
+	DischargedDate     *time.Time `json:"discharged_date"`
+	DischargeReference *string    `json:"discharge_reference"`
+	IssueID            *int64     `json:"issue_id"`
+	Notes              *string    `json:"notes"`
+	CreatedBy          *int64     `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TitleChainGap represents a break in a parcel's chain of title
+type TitleChainGap struct {
+	ID              int64      `gorm:"primaryKey" json:"id"`
+	TenantID        int64      `json:"tenant_id"`
+	ParcelID        int64      `json:"parcel_id"`
+	GapKind         string     `json:"gap_kind"` // no_root_deed, broken_link, no_deeds
+	FromDate        time.Time  `json:"from_date"`
+	ToDate          time.Time  `json:"to_date"`
+	PrecedingDeedID *int64     `json:"preceding_deed_id"`
+	FollowingDeedID *int64     `json:"following_deed_id"`
+	Description     string     `json:"description"`
+	Status          string     `json:"status"` // open, closed
+	IssueID         *int64     `json:"issue_id"`
+	DetectedAt      time.Time  `json:"detected_at"`
+	ClosedAt        *time.Time `json:"closed_at"`
+}
+
+// TitleChainAnalysis is the result of tracing a parcel's chain of title
+type TitleChainAnalysis struct {
+	Parcel        *TitleParcel        `json:"parcel"`
+	PeriodStart   time.Time           `json:"period_start"`
+	PeriodEnd     time.Time           `json:"period_end"`
+	Deeds         []*TitleDeed        `json:"deeds"`
+	Gaps          []*TitleChainGap    `json:"gaps"`
+	Encumbrances  []*TitleEncumbrance `json:"encumbrances"` // subsisting
+	CurrentOwners []string            `json:"current_owners"`
+	IsComplete    bool                `json:"is_complete"`
+	IssuesRaised  int                 `json:"issues_raised"`
+}
+
+// TitleTimelineEvent is one dated event on a survey number's title timeline
+type TitleTimelineEvent struct {
+	Date        time.Time `json:"date"`
+	ParcelID    int64     `json:"parcel_id"`
+	EventType   string    `json:"event_type"` // deed, encumbrance_recorded, encumbrance_discharged, chain_gap
+	Subtype     string    `json:"subtype"`    // deed, encumbrance or gap type
+	ReferenceID int64     `json:"reference_id"`
+	Reference   *string   `json:"reference"`
+	Description string    `json:"description"`
+	FromParties []string  `json:"from_parties,omitempty"`
+	ToParties   []string  `json:"to_parties,omitempty"`
+}
+
+// ============================================
+// Chain of Title Requests
+// ============================================
+
+// CreateTitleParcelRequest represents a request to register a parcel
+type CreateTitleParcelRequest struct {
+	ClearanceID  *int64  `json:"clearance_id"`
+	SurveyNumber string  `json:"survey_number" binding:"required"`
+	SubDivision  *string `json:"sub_division"`
+	Village      string  `json:"village" binding:"required"`
+	Taluk        *string `json:"taluk"`
+	District     *string `json:"district"`
+	State        *string `json:"state"`
+	AreaAcres    float64 `json:"area_acres"`
+	LandType     *string `json:"land_type"`
+	Notes        *string `json:"notes"`
+}
+
+// TitleDeedPartyInput represents a party in a deed request
+type TitleDeedPartyInput struct {
+	PartyRole         string  `json:"party_role" binding:"required"`
+	PartyName         string  `json:"party_name" binding:"required"`
+	PartyType         *string `json:"party_type"`
+	IdentityReference *string `json:"identity_reference"`
+}
+
+// CreateTitleDeedRequest represents a request to record a deed in the chain
+type CreateTitleDeedRequest struct {
+	DeedType           string                `json:"deed_type" binding:"required"`
+	DocumentNumber     string                `json:"document_number" binding:"required"`
+	RegistrationDate   time.Time             `json:"registration_date" binding:"required"`
+	ExecutionDate      *time.Time            `json:"execution_date"`
+	SubRegistrarOffice *string               `json:"sub_registrar_office"`
+	Consideration      float64               `json:"consideration"`
+	DocumentURL        *string               `json:"document_url"`
+	Notes              *string               `json:"notes"`
+	Parties            []TitleDeedPartyInput `json:"parties" binding:"required"`
+}
+
+// CreateTitleEncumbranceRequest represents a request to record an encumbrance
+type CreateTitleEncumbranceRequest struct {
+	DeedID          *int64    `json:"deed_id"`
+	EncumbranceType string    `json:"encumbrance_type" binding:"required"`
+	HolderName      string    `json:"holder_name" binding:"required"`
+	ReferenceNumber *string   `json:"reference_number"`
+	RecordedDate    time.Time `json:"recorded_date" binding:"required"`
+	Amount          float64   `json:"amount"`
+	Notes           *string   `json:"notes"`
+}
+
+// DischargeTitleEncumbranceRequest represents a request to discharge an encumbrance
+type DischargeTitleEncumbranceRequest struct {
+	DischargedDate     time.Time `json:"discharged_date" binding:"required"`
+	DischargeReference string    `json:"discharge_reference" binding:"required"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// UNIT AVAILABILITY MATRIX MODELS
+// ============================================================================
+
+// Unit availability statuses shown on the matrix
+const (
+	UnitStatusAvailable  = "available"
+	UnitStatusHeld       = "held"   // reserved for a prospect for a while
+	UnitStatusBooked     = "booked" // an active booking exists
+	UnitStatusRegistered = "registered"
+	UnitStatusBlocked    = "blocked" // withheld from sale by management
+)
+
+// UnitMatrixStatuses lists the matrix statuses in display order
+var UnitMatrixStatuses = []string{UnitStatusAvailable, UnitStatusHeld, UnitStatusBooked, UnitStatusRegistered, UnitStatusBlocked}
+
+// UnitMatrixCell is one unit on the tower x floor grid
+type UnitMatrixCell struct {
+	UnitID     string  `json:"unit_id"`
+	UnitNumber string  `json:"unit_number"`
+	BlockID    string  `json:"block_id"`
+	Floor      int     `json:"floor"`
+	UnitType   string  `json:"unit_type"` // 1BHK, 2BHK, 3BHK, shop, office
+	Facing     string  `json:"facing"`
+	CarpetArea float64 `json:"carpet_area"`
+	SBUA       float64 `json:"sbua"`
+	Status     string  `json:"status"`
+	Price      float64 `json:"price"`       // agreement value; the locked price once booked
+	PriceBasis string  `json:"price_basis"` // price_list, booking, cost_sheet, none
+	BookingID  string  `json:"booking_id,omitempty"`
+}
+
+// UnitMatrixFloor is a row of the grid
+type UnitMatrixFloor struct {
+	Floor int              `json:"floor"`
+	Units []UnitMatrixCell `json:"units"`
+}
+
+// UnitStatusSummary is the count, area and value of units in a status
+type UnitStatusSummary struct {
+	Status string  `json:"status"`
+	Count  int     `json:"count"`
+	Area   float64 `json:"area"` // SBUA
+	Value  float64 `json:"value"`
+}
+
+// BlockAvailability is the grid for one tower
+type BlockAvailability struct {
+	BlockID   string              `json:"block_id"`
+	BlockName string              `json:"block_name"`
+	Floors    []UnitMatrixFloor   `json:"floors"` // top floor first
+	Summary   []UnitStatusSummary `json:"summary"`
+}
+
+// UnitAvailabilityMatrix is the availability grid of a project's towers
+type UnitAvailabilityMatrix struct {
+	ProjectID string              `json:"project_id"`
+	AsOf      time.Time           `json:"as_of"`
+	Blocks    []BlockAvailability `json:"blocks"`
+	Summary   []UnitStatusSummary `json:"summary"`
+}
+
+// UnitMatrixFilter narrows the matrix
+type UnitMatrixFilter struct {
+	BlockID  string
+	UnitType string // BHK configuration
+	Facing   string
+	MinPrice float64
+	MaxPrice float64 // 0 for no upper bound
+}
+
+// UnitStatusChange is a recorded change of a unit's status
+type UnitStatusChange struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	UnitID     string    `json:"unit_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// SetUnitStatusRequest holds, blocks, releases or registers a unit
+type SetUnitStatusRequest struct {
+	Status string `json:"status" binding:"required"` // available, held, blocked, registered
+	Reason string `json:"reason"`
+}
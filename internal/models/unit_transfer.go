@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// UNIT TRANSFER (RESALE ENDORSEMENT) MODELS
+// ============================================================================
+
+// Transfer statuses
+const (
+	TransferStatusRequested = "requested" // dues and transfer fee computed, awaiting approval
+	TransferStatusApproved  = "approved"  // transfer fee raised on the transferors
+	TransferStatusCompleted = "completed" // booking endorsed to the transferees
+	TransferStatusRejected  = "rejected"
+)
+
+// Transfer party roles
+const (
+	TransferPartyTransferor = "transferor"
+	TransferPartyTransferee = "transferee"
+)
+
+// Transfer fee charge types
+const (
+	TransferFeePerSqft = "per_sqft"
+	TransferFeeLumpsum = "lumpsum"
+	TransferFeePercent = "percent" // of the agreement value
+)
+
+// TransferTemplateType is the document template type of the endorsement letter
+const TransferTemplateType = "transfer_endorsement"
+
+// TransferFeePolicy is the transfer fee charged on a resale, tenant-wide when ProjectID is nil
+type TransferFeePolicy struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	ProjectID  *string   `json:"project_id"`
+	ChargeType string    `json:"charge_type"` // per_sqft, lumpsum, percent
+	Amount     float64   `json:"amount"`
+	GSTRate    float64   `json:"gst_rate"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TransferParty is an applicant leaving or joining the booking
+type TransferParty struct {
+	Role         string  `json:"role"` // transferor, transferee
+	Name         string  `json:"name"`
+	Email        string  `json:"email,omitempty"`
+	Phone        string  `json:"phone,omitempty"`
+	PAN          string  `json:"pan,omitempty"`
+	IDType       string  `json:"id_type,omitempty"` // aadhar, pan, passport, driving_license, voter_id
+	IDNumber     string  `json:"id_number,omitempty"`
+	Address      string  `json:"address,omitempty"`
+	SharePercent float64 `json:"share_percent"`
+	IsPrimary    bool    `json:"is_primary"`
+}
+
+// UnitTransfer moves a booking from its applicants to new buyers
+type UnitTransfer struct {
+	ID                 string          `json:"id"`
+	TenantID           string          `json:"tenant_id"`
+	BookingID          string          `json:"booking_id"`
+	UnitID             string          `json:"unit_id"`
+	ProjectID          string          `json:"project_id,omitempty"`
+	OldCustomerID      *string         `json:"old_customer_id"`
+	NewCustomerID      *string         `json:"new_customer_id"`
+	OwnershipType      string          `json:"ownership_type"` // joint_tenant, tenant_in_common
+	AgreementValue     float64         `json:"agreement_value"`
+	DuesOutstanding    float64         `json:"dues_outstanding"` // instalments due and unpaid; must be cleared to complete
+	TransferFee        float64         `json:"transfer_fee"`
+	TransferFeeGST     float64         `json:"transfer_fee_gst"`
+	FeeReceiptRef      *string         `json:"fee_receipt_reference"`
+	FeePaidAt          *time.Time      `json:"fee_paid_at"`
+	FeeJournalEntryID  *string         `json:"fee_journal_entry_id"`
+	Status             string          `json:"status"`
+	Reason             string          `json:"reason,omitempty"`
+	RequestedBy        string          `json:"requested_by"`
+	ApprovedBy         *string         `json:"approved_by"`
+	ApprovedAt         *time.Time      `json:"approved_at"`
+	DecisionComment    string          `json:"decision_comment,omitempty"`
+	AgreementReference *string         `json:"agreement_reference"` // new co-ownership agreement
+	LedgerEntriesMoved int             `json:"ledger_entries_moved"`
+	EndorsementLetter  string          `json:"endorsement_letter,omitempty"`
+	CompletedAt        *time.Time      `json:"completed_at"`
+	Parties            []TransferParty `json:"parties"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// TransferLedgerAudit records a customer ledger entry re-pointed to the transferee
+type TransferLedgerAudit struct {
+	ID            string    `json:"id"`
+	TransferID    string    `json:"transfer_id"`
+	LedgerEntryID string    `json:"ledger_entry_id"`
+	OldCustomerID *string   `json:"old_customer_id"`
+	NewCustomerID *string   `json:"new_customer_id"`
+	ChangedBy     string    `json:"changed_by"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// ============================================================================
+// REQUEST MODELS
+// ============================================================================
+
+// SetTransferFeePolicyRequest creates or replaces a project's transfer fee
+type SetTransferFeePolicyRequest struct {
+	ProjectID  string  `json:"project_id"` // empty for all projects
+	ChargeType string  `json:"charge_type" binding:"required"`
+	Amount     float64 `json:"amount"`
+	GSTRate    float64 `json:"gst_rate"`
+}
+
+// CreateTransferRequest asks for a booking to be endorsed to new buyers
+type CreateTransferRequest struct {
+	BookingID     string          `json:"booking_id" binding:"required"`
+	NewCustomerID string          `json:"new_customer_id" binding:"required"` // customer record the booking moves to
+	OwnershipType string          `json:"ownership_type"`                     // defaults to joint_tenant
+	Reason        string          `json:"reason"`
+	Transferees   []TransferParty `json:"transferees" binding:"required"`
+}
+
+// TransferDecisionRequest approves or rejects a transfer
+type TransferDecisionRequest struct {
+	Approve bool   `json:"approve"`
+	Comment string `json:"comment"` // required when rejecting
+}
+
+// RecordTransferFeeRequest records the transfer fee received
+type RecordTransferFeeRequest struct {
+	Reference  string `json:"reference" binding:"required"`
+	ReceivedOn string `json:"received_on"` // YYYY-MM-DD, defaults to today
+}
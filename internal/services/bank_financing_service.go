@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"vyomtech-backend/internal/models"
@@ -214,6 +215,64 @@ func (s *BankFinancingService) CreateBankNOC(ctx context.Context, noc *models.Ba
 	return noc, nil
 }
 
+// GetActiveFinancingForBooking returns the booking's live loan, or nil when it has none
+func (s *BankFinancingService) GetActiveFinancingForBooking(ctx context.Context, tenantID, bookingID string) (*models.BankFinancing, error) {
+	query := `
+		SELECT id, tenant_id, booking_id, bank_id, loan_amount, sanctioned_amount,
+		       disbursed_amount, outstanding_amount, loan_type, status
+		FROM bank_financing
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+		  AND status NOT IN ('rejected', 'cancelled')
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	financing := &models.BankFinancing{}
+	err := s.db.QueryRowContext(ctx, query, tenantID, bookingID).Scan(
+		&financing.ID, &financing.TenantID, &financing.BookingID, &financing.BankID,
+		&financing.LoanAmount, &financing.SanctionedAmount, &financing.DisbursedAmount,
+		&financing.OutstandingAmount, &financing.LoanType, &financing.Status,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to get booking financing", "error", err)
+		return nil, err
+	}
+
+	return financing, nil
+}
+
+// CloseFinancingOnCancellation requests the lender's NOC against the amount refunded
+// to it on a cancelled booking and closes the loan
+func (s *BankFinancingService) CloseFinancingOnCancellation(ctx context.Context, financing *models.BankFinancing, refundToBank float64, userID string) (*models.BankNOC, error) {
+	remarks := fmt.Sprintf("Booking cancelled; %.2f to be refunded to the lender", refundToBank)
+	noc, err := s.CreateBankNOC(ctx, &models.BankNOC{
+		TenantID:       financing.TenantID,
+		FinancingID:    financing.ID,
+		NOCType:        "Cancellation",
+		NOCRequestDate: time.Now(),
+		NOCAmount:      &refundToBank,
+		Status:         "requested",
+		Remarks:        &remarks,
+		CreatedBy:      &userID,
+		UpdatedBy:      &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.ExecContext(ctx, `UPDATE bank_financing SET status = 'cancelled', updated_by = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, userID, time.Now(), financing.ID, financing.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to close bank financing", "error", err)
+		return nil, err
+	}
+
+	return noc, nil
+}
+
 // ============================================================================
 // BANK COLLECTION METHODS
 // ============================================================================
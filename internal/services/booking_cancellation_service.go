@@ -0,0 +1,683 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// BOOKING CANCELLATION SERVICE
+// ============================================================================
+
+// BookingCancellationService cancels bookings under the project's forfeiture rules:
+// it computes the refund statement, and on approval releases the unit, closes any
+// loan via the lender's NOC, claws back broker commission, raises the credit note
+// and reverses the booking in the GL before the refund is paid out.
+type BookingCancellationService struct {
+	DB            *sql.DB
+	GL            *GLService
+	BankFinancing *BankFinancingService
+	Brokers       *BrokerService
+	Availability  *UnitAvailabilityService
+	Subvention    *SubventionService
+}
+
+// NewBookingCancellationService creates a new booking cancellation service
+func NewBookingCancellationService(db *sql.DB, gl *GLService, bankFinancing *BankFinancingService, brokers *BrokerService, availability *UnitAvailabilityService, subvention *SubventionService) *BookingCancellationService {
+	return &BookingCancellationService{DB: db, GL: gl, BankFinancing: bankFinancing, Brokers: brokers, Availability: availability, Subvention: subvention}
+}
+
+// ============================================================================
+// FORFEITURE POLICIES
+// ============================================================================
+
+// SetPolicy creates or replaces the forfeiture rules for a project, or tenant-wide
+func (s *BookingCancellationService) SetPolicy(tenantID string, req *models.SetCancellationPolicyRequest) (*models.CancellationPolicy, error) {
+	if req.ForfeitPercent < 0 || req.ForfeitPercent > 100 {
+		return nil, fmt.Errorf("forfeit_percent must be between 0 and 100")
+	}
+	if req.ForfeitMinAmount < 0 || req.AdminCharge < 0 {
+		return nil, fmt.Errorf("forfeit_min_amount and admin_charge cannot be negative")
+	}
+
+	now := time.Now()
+	policy := &models.CancellationPolicy{
+		ID:               uuid.New().String(),
+		TenantID:         tenantID,
+		ForfeitPercent:   req.ForfeitPercent,
+		ForfeitMinAmount: req.ForfeitMinAmount,
+		AdminCharge:      req.AdminCharge,
+		GSTRefundable:    req.GSTRefundable,
+		RecoverBrokerage: req.RecoverBrokerage,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if req.ProjectID != "" {
+		policy.ProjectID = &req.ProjectID
+	}
+
+	if _, err := s.DB.Exec(`INSERT INTO cancellation_policies
+		(id, tenant_id, project_id, forfeit_percent, forfeit_min_amount, admin_charge, gst_refundable,
+		 recover_brokerage, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE forfeit_percent = VALUES(forfeit_percent), forfeit_min_amount = VALUES(forfeit_min_amount),
+			admin_charge = VALUES(admin_charge), gst_refundable = VALUES(gst_refundable),
+			recover_brokerage = VALUES(recover_brokerage), updated_at = VALUES(updated_at)`,
+		policy.ID, tenantID, req.ProjectID, policy.ForfeitPercent, policy.ForfeitMinAmount, policy.AdminCharge,
+		policy.GSTRefundable, policy.RecoverBrokerage, policy.CreatedAt, policy.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to set cancellation policy: %w", err)
+	}
+	return policy, nil
+}
+
+// ListPolicies lists the tenant's forfeiture rules
+func (s *BookingCancellationService) ListPolicies(tenantID string) ([]models.CancellationPolicy, error) {
+	return s.getPolicies(tenantID, "1 = 1")
+}
+
+// policyFor returns the project's rules, falling back to the tenant-wide ones
+func (s *BookingCancellationService) policyFor(tenantID, projectID string) (*models.CancellationPolicy, error) {
+	policies, err := s.getPolicies(tenantID, "project_id IN ('', ?)", projectID)
+	if err != nil {
+		return nil, err
+	}
+	var policy *models.CancellationPolicy
+	for i := range policies {
+		if policy == nil || policies[i].ProjectID != nil {
+			policy = &policies[i]
+		}
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("no cancellation policy configured for this project")
+	}
+	return policy, nil
+}
+
+func (s *BookingCancellationService) getPolicies(tenantID, where string, args ...interface{}) ([]models.CancellationPolicy, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, forfeit_percent, forfeit_min_amount, admin_charge,
+		gst_refundable, recover_brokerage, created_at, updated_at
+		FROM cancellation_policies WHERE tenant_id = ? AND `+where+` ORDER BY project_id`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cancellation policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []models.CancellationPolicy{}
+	for rows.Next() {
+		var p models.CancellationPolicy
+		var project string
+		if err := rows.Scan(&p.ID, &p.TenantID, &project, &p.ForfeitPercent, &p.ForfeitMinAmount, &p.AdminCharge,
+			&p.GSTRefundable, &p.RecoverBrokerage, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cancellation policy: %w", err)
+		}
+		if project != "" {
+			p.ProjectID = &project
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// ============================================================================
+// CANCELLATION REQUESTS
+// ============================================================================
+
+// ComputeStatement works out the refund statement for cancelling a booking today
+func (s *BookingCancellationService) ComputeStatement(tenantID, bookingID string) (*models.BookingCancellation, error) {
+	c := &models.BookingCancellation{TenantID: tenantID, BookingID: bookingID}
+
+	var status string
+	var agreementValue, gstRatio float64
+	// The price locked on the booking wins over the unit's legacy cost sheet
+	err := s.DB.QueryRow(`SELECT b.unit_id, COALESCE(u.project_id, ''), COALESCE(b.customer_id, ''), b.booking_status,
+		COALESCE(bpl.agreement_value, ucs.apartment_cost_exc_govt, 0),
+		COALESCE(bpl.total_gst / NULLIF(bpl.total_payable, 0), 0)
+		FROM customer_bookings b
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN booking_price_locks bpl ON bpl.booking_id = b.id AND bpl.tenant_id = b.tenant_id
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = b.unit_id AND ucs.tenant_id = b.tenant_id
+		WHERE b.id = ? AND b.tenant_id = ? AND b.deleted_at IS NULL`,
+		bookingID, tenantID).Scan(&c.UnitID, &c.ProjectID, &c.CustomerID, &status, &agreementValue, &gstRatio)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if status != "active" {
+		return nil, fmt.Errorf("booking is %s", status)
+	}
+
+	policy, err := s.policyFor(tenantID, c.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paid float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.booking_id = ? AND p.status = 'cleared' AND p.deleted_at IS NULL`,
+		tenantID, bookingID).Scan(&paid); err != nil {
+		return nil, fmt.Errorf("failed to fetch booking receipts: %w", err)
+	}
+
+	brokerage := 0.0
+	if policy.RecoverBrokerage {
+		if brokerage, err = s.Brokers.BookingBrokerage(tenantID, bookingID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The lender's disbursements were paid to us, so they go back to it first
+	loan := 0.0
+	financing, err := s.BankFinancing.GetActiveFinancingForBooking(context.Background(), tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking financing: %w", err)
+	}
+	if financing != nil {
+		c.FinancingID = &financing.ID
+		loan = financing.DisbursedAmount
+	}
+
+	c.Statement = buildCancellationStatement(policy, agreementValue, paid, paid*gstRatio, brokerage, loan)
+	c.CreditNoteGST = roundTo2(c.Statement.GSTCollected - c.Statement.GSTRetained)
+	c.CreditNoteAmount = roundTo2(c.Statement.AmountPaid - c.Statement.GSTCollected)
+	return c, nil
+}
+
+// RequestCancellation records a cancellation request with its refund statement
+func (s *BookingCancellationService) RequestCancellation(tenantID, userID string, req *models.CreateCancellationRequest) (*models.BookingCancellation, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	var open int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM booking_cancellations
+		WHERE tenant_id = ? AND booking_id = ? AND status IN (?, ?)`,
+		tenantID, req.BookingID, models.CancellationStatusRequested, models.CancellationStatusApproved).Scan(&open); err != nil {
+		return nil, fmt.Errorf("failed to check existing cancellations: %w", err)
+	}
+	if open > 0 {
+		return nil, fmt.Errorf("booking already has an open cancellation")
+	}
+
+	c, err := s.ComputeStatement(tenantID, req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	c.ID = uuid.New().String()
+	c.Reason = req.Reason
+	c.Status = models.CancellationStatusRequested
+	c.RequestedBy = userID
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	st := c.Statement
+	if _, err := s.DB.Exec(`INSERT INTO booking_cancellations
+		(id, tenant_id, booking_id, unit_id, project_id, customer_id, reason, status, agreement_value, amount_paid,
+		 gst_collected, forfeit_percent, forfeiture_amount, gst_retained, brokerage_recovered, admin_charge,
+		 total_deductions, refund_amount, loan_outstanding, bank_refund, customer_refund, financing_id,
+		 credit_note_amount, credit_note_gst, requested_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID, tenantID, c.BookingID, c.UnitID, nullIfEmpty(c.ProjectID), nullIfEmpty(c.CustomerID), c.Reason,
+		c.Status, st.AgreementValue, st.AmountPaid, st.GSTCollected, st.ForfeitPercent, st.ForfeitureAmount,
+		st.GSTRetained, st.BrokerageRecovered, st.AdminCharge, st.TotalDeductions, st.RefundAmount,
+		st.LoanOutstanding, st.BankRefund, st.CustomerRefund, c.FinancingID, c.CreditNoteAmount, c.CreditNoteGST,
+		c.RequestedBy, c.CreatedAt, c.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create cancellation: %w", err)
+	}
+	return c, nil
+}
+
+// DecideCancellation approves or rejects a cancellation. Approval recomputes the
+// statement as of today, cancels the booking and releases the unit, then settles it:
+// closes the loan, claws back brokerage and reverses the booking in the GL. A
+// settlement that fails leaves the cancellation approved_pending_settlement with the
+// error recorded, for SettleCancellation to retry.
+func (s *BookingCancellationService) DecideCancellation(tenantID, userID, cancellationID string, req *models.CancellationDecisionRequest) (*models.BookingCancellation, error) {
+	c, err := s.GetCancellation(tenantID, cancellationID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.CancellationStatusRequested {
+		return nil, fmt.Errorf("cancellation is already %s", c.Status)
+	}
+	if c.RequestedBy == userID {
+		return nil, fmt.Errorf("a cancellation cannot be approved by the person who requested it")
+	}
+	now := time.Now()
+
+	if !req.Approve {
+		if strings.TrimSpace(req.Comment) == "" {
+			return nil, fmt.Errorf("a comment is required when rejecting")
+		}
+		if _, err := s.DB.Exec(`UPDATE booking_cancellations SET status = ?, approved_by = ?, approved_at = ?,
+			decision_comment = ?, updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+			models.CancellationStatusRejected, userID, now, req.Comment, now, c.ID, tenantID,
+			models.CancellationStatusRequested); err != nil {
+			return nil, fmt.Errorf("failed to reject cancellation: %w", err)
+		}
+		return s.GetCancellation(tenantID, cancellationID)
+	}
+
+	fresh, err := s.ComputeStatement(tenantID, c.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	st := fresh.Statement
+	creditNote := interestNoteNumber("CN")
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE booking_cancellations SET status = ?, agreement_value = ?, amount_paid = ?,
+		gst_collected = ?, forfeit_percent = ?, forfeiture_amount = ?, gst_retained = ?, brokerage_recovered = ?,
+		admin_charge = ?, total_deductions = ?, refund_amount = ?, loan_outstanding = ?, bank_refund = ?,
+		customer_refund = ?, financing_id = ?, credit_note_number = ?, credit_note_amount = ?, credit_note_gst = ?,
+		approved_by = ?, approved_at = ?, decision_comment = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.CancellationStatusPendingSettlement, st.AgreementValue, st.AmountPaid, st.GSTCollected, st.ForfeitPercent,
+		st.ForfeitureAmount, st.GSTRetained, st.BrokerageRecovered, st.AdminCharge, st.TotalDeductions,
+		st.RefundAmount, st.LoanOutstanding, st.BankRefund, st.CustomerRefund, fresh.FinancingID, creditNote,
+		fresh.CreditNoteAmount, fresh.CreditNoteGST, userID, now, req.Comment, now, c.ID, tenantID,
+		models.CancellationStatusRequested)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve cancellation: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("cancellation was decided by someone else")
+	}
+
+	if _, err := tx.Exec(`UPDATE customer_bookings SET booking_status = 'cancelled', updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, now, c.BookingID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to cancel booking: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE property_units SET status = 'available', updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, now, c.UnitID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to release unit: %w", err)
+	}
+
+	// Bring the customer ledger to the refund due: the credit note reverses unpaid
+	// demands, or the deductions are debited when they exceed them
+	var balance float64
+	err = tx.QueryRow(`SELECT COALESCE(closing_balance, 0) FROM customer_account_ledgers
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+		ORDER BY transaction_date DESC, created_at DESC LIMIT 1`, tenantID, c.BookingID).Scan(&balance)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch ledger balance: %w", err)
+	}
+	if adjustment := roundTo2(st.RefundAmount - balance); adjustment != 0 {
+		txnType, description := "credit", fmt.Sprintf("Credit note %s - booking cancelled", creditNote)
+		if adjustment < 0 {
+			txnType, description = "debit", fmt.Sprintf("Forfeiture and deductions on cancellation (%s)", creditNote)
+		}
+		if _, err := insertCustomerLedgerEntry(tx, tenantID, c.BookingID, fresh.CustomerID, txnType, description,
+			math.Abs(adjustment), creditNote); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit cancellation: %w", err)
+	}
+
+	approved, err := s.GetCancellation(tenantID, cancellationID)
+	if err != nil {
+		return nil, err
+	}
+	// The approval stands; a failed settlement is recorded on the cancellation
+	s.settleCancellation(tenantID, userID, approved)
+
+	if _, err := s.Availability.UnitStatusChanged(tenantID, userID, c.UnitID, models.UnitStatusBooked,
+		models.UnitStatusAvailable, fmt.Sprintf("Booking cancelled (%s)", creditNote)); err != nil {
+		return nil, fmt.Errorf("booking cancelled but %w", err)
+	}
+	return s.GetCancellation(tenantID, cancellationID)
+}
+
+// SettleCancellation retries the settlement of an approved cancellation that is
+// still pending settlement. Steps already done are not repeated.
+func (s *BookingCancellationService) SettleCancellation(tenantID, userID, cancellationID string) (*models.BookingCancellation, error) {
+	c, err := s.GetCancellation(tenantID, cancellationID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.CancellationStatusPendingSettlement {
+		return nil, fmt.Errorf("cancellation is %s, not pending settlement", c.Status)
+	}
+	if err := s.settleCancellation(tenantID, userID, c); err != nil {
+		return nil, err
+	}
+	return s.GetCancellation(tenantID, cancellationID)
+}
+
+// settleCancellation claws back brokerage, posts the GL reversal, stops pre-EMI
+// subvention and requests the lender's NOC, then marks the cancellation approved.
+// Each step is skipped when a previous attempt completed it, and a failure is
+// recorded on the cancellation.
+func (s *BookingCancellationService) settleCancellation(tenantID, userID string, c *models.BookingCancellation) error {
+	err := s.settle(tenantID, userID, c)
+	if err != nil {
+		s.DB.Exec(`UPDATE booking_cancellations SET settlement_error = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+			err.Error(), time.Now(), c.ID, tenantID)
+		return fmt.Errorf("booking cancelled but %w", err)
+	}
+	if _, err := s.DB.Exec(`UPDATE booking_cancellations SET status = ?, settlement_error = NULL, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.CancellationStatusApproved, time.Now(), c.ID, tenantID, models.CancellationStatusPendingSettlement); err != nil {
+		return fmt.Errorf("failed to complete settlement: %w", err)
+	}
+	return nil
+}
+
+func (s *BookingCancellationService) settle(tenantID, userID string, c *models.BookingCancellation) error {
+	policy, err := s.policyFor(tenantID, c.ProjectID)
+	if err != nil {
+		return err
+	}
+	// Links already cancelled are skipped, so a retry raises no second clawback
+	if _, err := s.Brokers.CancelBookingCommission(tenantID, c.BookingID, userID, policy.RecoverBrokerage); err != nil {
+		return err
+	}
+
+	if lines := buildCancellationJournalLines(c.Statement); len(lines) > 0 && c.JournalEntryID == nil {
+		creditNote := ""
+		if c.CreditNoteNumber != nil {
+			creditNote = *c.CreditNoteNumber
+		}
+		entryID := fmt.Sprintf("JE-BC-%s", c.ID)
+		entry := &models.JournalEntry{
+			ID:              entryID,
+			TenantID:        tenantID,
+			EntryDate:       time.Now(),
+			ReferenceNumber: &creditNote,
+			ReferenceType:   "Booking_Cancellation",
+			ReferenceID:     &c.ID,
+			Description:     fmt.Sprintf("Cancellation of booking %s", c.BookingID),
+			Amount:          roundTo2(c.Statement.AmountPaid - c.Statement.GSTRetained),
+			Narration:       fmt.Sprintf("Receipts reversed under credit note %s", creditNote),
+			EntryStatus:     "Draft",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		tx, err := s.DB.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE booking_cancellations SET journal_entry_id = ? WHERE id = ? AND tenant_id = ?`,
+			entryID, c.ID, tenantID); err != nil {
+			return fmt.Errorf("failed to link journal entry: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit cancellation journal entry: %w", err)
+		}
+	}
+
+	if s.Subvention != nil {
+		if err := s.Subvention.StopForBooking(tenantID, c.BookingID, models.SubventionStopCancellation, time.Now()); err != nil {
+			return fmt.Errorf("failed to stop pre-EMI subvention: %w", err)
+		}
+	}
+
+	if c.FinancingID != nil && c.NOCID == nil {
+		// A NOC requested by an attempt that failed to link it is reused
+		var nocID string
+		err := s.DB.QueryRow(`SELECT id FROM bank_noc WHERE tenant_id = ? AND financing_id = ? AND noc_type = 'Cancellation'
+			ORDER BY created_at DESC LIMIT 1`, tenantID, *c.FinancingID).Scan(&nocID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get lender NOC: %w", err)
+		}
+		if err == sql.ErrNoRows {
+			financing := &models.BankFinancing{ID: *c.FinancingID, TenantID: tenantID}
+			noc, err := s.BankFinancing.CloseFinancingOnCancellation(context.Background(), financing, c.Statement.BankRefund, userID)
+			if err != nil {
+				return fmt.Errorf("failed to request lender NOC: %w", err)
+			}
+			nocID = noc.ID
+		}
+		if _, err := s.DB.Exec(`UPDATE booking_cancellations SET noc_id = ? WHERE id = ? AND tenant_id = ?`,
+			nocID, c.ID, tenantID); err != nil {
+			return fmt.Errorf("failed to link lender NOC: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordRefundPayout records the approved refund as paid and posts the payment
+func (s *BookingCancellationService) RecordRefundPayout(tenantID, userID, cancellationID string, req *models.RecordRefundPayoutRequest) (*models.BookingCancellation, error) {
+	if req.PaymentMode == "" || req.Reference == "" {
+		return nil, fmt.Errorf("payment_mode and reference are required")
+	}
+	paidOn := time.Now()
+	if req.PaidOn != "" {
+		d, err := time.Parse("2006-01-02", req.PaidOn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid paid_on date")
+		}
+		paidOn = d
+	}
+
+	c, err := s.GetCancellation(tenantID, cancellationID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.CancellationStatusApproved {
+		return nil, fmt.Errorf("only an approved cancellation can be refunded")
+	}
+	refund := c.Statement.RefundAmount
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var entryID interface{}
+	if refund > 0 {
+		entryID = fmt.Sprintf("JE-BCR-%s", c.ID)
+		if _, err := insertCustomerLedgerEntry(tx, tenantID, c.BookingID, c.CustomerID, "debit",
+			fmt.Sprintf("Refund on cancellation - %s %s", req.PaymentMode, req.Reference), refund, req.Reference); err != nil {
+			return nil, err
+		}
+	}
+	res, err := tx.Exec(`UPDATE booking_cancellations SET status = ?, refund_mode = ?, refund_reference = ?,
+		refunded_at = ?, payout_entry_id = ?, updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.CancellationStatusRefunded, req.PaymentMode, req.Reference, paidOn, entryID, time.Now(),
+		c.ID, tenantID, models.CancellationStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("refund was already recorded")
+	}
+
+	// The refund journal commits with the payout, never without it
+	if refund > 0 {
+		reference := req.Reference
+		entry := &models.JournalEntry{
+			ID:              entryID.(string),
+			TenantID:        tenantID,
+			EntryDate:       paidOn,
+			ReferenceNumber: &reference,
+			ReferenceType:   "Booking_Cancellation_Refund",
+			ReferenceID:     &c.ID,
+			Description:     fmt.Sprintf("Refund on cancellation of booking %s", c.BookingID),
+			Amount:          refund,
+			Narration:       fmt.Sprintf("Paid by %s, ref %s", req.PaymentMode, req.Reference),
+			EntryStatus:     "Draft",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		lines := []models.JournalEntryDetail{
+			{AccountID: "ACC-REFUND-PAYABLE", DebitAmount: refund, Description: "Cancellation refund paid"},
+			{AccountID: "ACC-BANK-CASH", CreditAmount: refund, Description: fmt.Sprintf("Refund %s", req.Reference)},
+		}
+		if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit refund: %w", err)
+	}
+
+	return s.GetCancellation(tenantID, cancellationID)
+}
+
+// GetCancellation returns a cancellation with its refund statement
+func (s *BookingCancellationService) GetCancellation(tenantID, cancellationID string) (*models.BookingCancellation, error) {
+	list, err := s.getCancellations(tenantID, "id = ?", cancellationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("cancellation not found")
+	}
+	return &list[0], nil
+}
+
+// ListCancellations lists cancellations, optionally by status and booking
+func (s *BookingCancellationService) ListCancellations(tenantID, status, bookingID string) ([]models.BookingCancellation, error) {
+	where, args := "1 = 1", []interface{}{}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	return s.getCancellations(tenantID, where, args...)
+}
+
+func (s *BookingCancellationService) getCancellations(tenantID, where string, args ...interface{}) ([]models.BookingCancellation, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, booking_id, unit_id, COALESCE(project_id, ''), COALESCE(customer_id, ''),
+		reason, status, agreement_value, amount_paid, gst_collected, forfeit_percent, forfeiture_amount, gst_retained,
+		brokerage_recovered, admin_charge, total_deductions, refund_amount, loan_outstanding, bank_refund,
+		customer_refund, financing_id, noc_id, credit_note_number, credit_note_amount, credit_note_gst,
+		journal_entry_id, settlement_error, requested_by, approved_by, approved_at, COALESCE(decision_comment, ''), refund_mode,
+		refund_reference, refunded_at, payout_entry_id, created_at, updated_at
+		FROM booking_cancellations WHERE tenant_id = ? AND `+where+` ORDER BY created_at DESC`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cancellations: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.BookingCancellation{}
+	for rows.Next() {
+		var c models.BookingCancellation
+		var financingID, nocID, creditNote, journalID, settlementErr, approvedBy, refundMode, refundRef, payoutID sql.NullString
+		var approvedAt, refundedAt sql.NullTime
+		st := &c.Statement
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.BookingID, &c.UnitID, &c.ProjectID, &c.CustomerID, &c.Reason,
+			&c.Status, &st.AgreementValue, &st.AmountPaid, &st.GSTCollected, &st.ForfeitPercent, &st.ForfeitureAmount,
+			&st.GSTRetained, &st.BrokerageRecovered, &st.AdminCharge, &st.TotalDeductions, &st.RefundAmount,
+			&st.LoanOutstanding, &st.BankRefund, &st.CustomerRefund, &financingID, &nocID, &creditNote,
+			&c.CreditNoteAmount, &c.CreditNoteGST, &journalID, &settlementErr, &c.RequestedBy, &approvedBy, &approvedAt,
+			&c.DecisionComment, &refundMode, &refundRef, &refundedAt, &payoutID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cancellation: %w", err)
+		}
+		c.FinancingID = nullStringPtr(financingID)
+		c.NOCID = nullStringPtr(nocID)
+		c.CreditNoteNumber = nullStringPtr(creditNote)
+		c.JournalEntryID = nullStringPtr(journalID)
+		c.SettlementError = nullStringPtr(settlementErr)
+		c.ApprovedBy = nullStringPtr(approvedBy)
+		c.RefundMode = nullStringPtr(refundMode)
+		c.RefundReference = nullStringPtr(refundRef)
+		c.PayoutEntryID = nullStringPtr(payoutID)
+		if approvedAt.Valid {
+			c.ApprovedAt = &approvedAt.Time
+		}
+		if refundedAt.Valid {
+			c.RefundedAt = &refundedAt.Time
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+func nullStringPtr(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+// buildCancellationStatement applies the forfeiture rules to what the customer has
+// paid. Deductions are taken in order (forfeiture, retained GST, brokerage, admin
+// charge), each capped at what is left, and the refund goes to the lender first.
+func buildCancellationStatement(policy *models.CancellationPolicy, agreementValue, paid, gstCollected, brokerage, loanOutstanding float64) models.CancellationStatement {
+	st := models.CancellationStatement{
+		AgreementValue:  roundTo2(agreementValue),
+		AmountPaid:      roundTo2(paid),
+		GSTCollected:    roundTo2(gstCollected),
+		ForfeitPercent:  policy.ForfeitPercent,
+		LoanOutstanding: roundTo2(loanOutstanding),
+	}
+
+	remaining := st.AmountPaid
+	deduct := func(amount float64) float64 {
+		amount = roundTo2(math.Min(math.Max(amount, 0), remaining))
+		remaining = roundTo2(remaining - amount)
+		return amount
+	}
+
+	st.ForfeitureAmount = deduct(math.Max(agreementValue*policy.ForfeitPercent/100, policy.ForfeitMinAmount))
+	if !policy.GSTRefundable {
+		st.GSTRetained = deduct(gstCollected)
+	}
+	if policy.RecoverBrokerage {
+		st.BrokerageRecovered = deduct(brokerage)
+	}
+	st.AdminCharge = deduct(policy.AdminCharge)
+
+	st.TotalDeductions = roundTo2(st.AmountPaid - remaining)
+	st.RefundAmount = remaining
+	st.BankRefund = roundTo2(math.Min(st.RefundAmount, st.LoanOutstanding))
+	st.CustomerRefund = roundTo2(st.RefundAmount - st.BankRefund)
+	return st
+}
+
+// buildCancellationJournalLines reverses the receipts held as customer advances and
+// the GST being refunded, books the forfeiture and recovered brokerage, and moves
+// the balance to refund payable. Retained GST stays in output tax.
+func buildCancellationJournalLines(st models.CancellationStatement) []models.JournalEntryDetail {
+	lines := []models.JournalEntryDetail{}
+	add := func(account string, debit, credit float64, description string) {
+		if debit > 0 || credit > 0 {
+			lines = append(lines, models.JournalEntryDetail{AccountID: account, DebitAmount: roundTo2(debit),
+				CreditAmount: roundTo2(credit), Description: description})
+		}
+	}
+
+	add("ACC-CUSTOMER-ADVANCES", st.AmountPaid-st.GSTCollected, 0, "Booking receipts reversed")
+	add("ACC-OUTPUT-TAX", st.GSTCollected-st.GSTRetained, 0, "GST reversed by credit note")
+	add("ACC-FORFEITURE-INCOME", 0, st.ForfeitureAmount+st.AdminCharge, "Forfeiture and admin charge on cancellation")
+	add("ACC-BROKERAGE-EXPENSE", 0, st.BrokerageRecovered, "Brokerage recovered from customer")
+	add("ACC-REFUND-PAYABLE", 0, st.RefundAmount, "Refund due on cancellation")
+	return lines
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuildCancellationStatement tests forfeiture, retained GST, brokerage recovery and the lender's share
+func TestBuildCancellationStatement(t *testing.T) {
+	policy := &models.CancellationPolicy{ForfeitPercent: 10, AdminCharge: 5000, RecoverBrokerage: true}
+
+	st := buildCancellationStatement(policy, 5000000, 1050000, 50000, 100000, 300000)
+	assert.Equal(t, 500000.0, st.ForfeitureAmount)
+	assert.Equal(t, 50000.0, st.GSTRetained)
+	assert.Equal(t, 100000.0, st.BrokerageRecovered)
+	assert.Equal(t, 5000.0, st.AdminCharge)
+	assert.Equal(t, 655000.0, st.TotalDeductions)
+	assert.Equal(t, 395000.0, st.RefundAmount)
+	assert.Equal(t, 300000.0, st.BankRefund)
+	assert.Equal(t, 95000.0, st.CustomerRefund)
+
+	// Deductions never exceed what was paid
+	st = buildCancellationStatement(policy, 5000000, 200000, 10000, 100000, 150000)
+	assert.Equal(t, 200000.0, st.ForfeitureAmount)
+	assert.Equal(t, 0.0, st.GSTRetained)
+	assert.Equal(t, 0.0, st.RefundAmount)
+	assert.Equal(t, 0.0, st.BankRefund)
+
+	// Minimum forfeiture, GST refunded and brokerage left with the broker
+	policy = &models.CancellationPolicy{ForfeitPercent: 1, ForfeitMinAmount: 100000, GSTRefundable: true}
+	st = buildCancellationStatement(policy, 5000000, 525000, 25000, 100000, 0)
+	assert.Equal(t, 100000.0, st.ForfeitureAmount)
+	assert.Equal(t, 0.0, st.GSTRetained)
+	assert.Equal(t, 0.0, st.BrokerageRecovered)
+	assert.Equal(t, 425000.0, st.RefundAmount)
+	assert.Equal(t, 425000.0, st.CustomerRefund)
+}
+
+// TestBuildCancellationJournalLines tests that the reversal is balanced
+func TestBuildCancellationJournalLines(t *testing.T) {
+	balanced := func(lines []models.JournalEntryDetail) bool {
+		debit, credit := 0.0, 0.0
+		for _, l := range lines {
+			debit += l.DebitAmount
+			credit += l.CreditAmount
+		}
+		return roundTo2(debit) == roundTo2(credit)
+	}
+
+	policy := &models.CancellationPolicy{ForfeitPercent: 10, AdminCharge: 5000, RecoverBrokerage: true}
+	lines := buildCancellationJournalLines(buildCancellationStatement(policy, 5000000, 1050000, 50000, 100000, 300000))
+
+	accounts := []string{}
+	for _, l := range lines {
+		accounts = append(accounts, l.AccountID)
+	}
+	// Retained GST stays in output tax, so there is no output tax line
+	assert.Equal(t, []string{"ACC-CUSTOMER-ADVANCES", "ACC-FORFEITURE-INCOME", "ACC-BROKERAGE-EXPENSE", "ACC-REFUND-PAYABLE"}, accounts)
+	assert.Equal(t, 1000000.0, lines[0].DebitAmount)
+	assert.Equal(t, 505000.0, lines[1].CreditAmount)
+	assert.True(t, balanced(lines))
+
+	policy = &models.CancellationPolicy{ForfeitPercent: 2, GSTRefundable: true}
+	lines = buildCancellationJournalLines(buildCancellationStatement(policy, 4000000, 840000, 40000, 0, 0))
+	assert.Equal(t, "ACC-OUTPUT-TAX", lines[1].AccountID)
+	assert.Equal(t, 40000.0, lines[1].DebitAmount)
+	assert.True(t, balanced(lines))
+}
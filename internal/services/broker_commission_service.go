@@ -0,0 +1,1137 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// BROKER COMMISSION ENGINE
+// ============================================================================
+// Commission on a booking link accrues when its booking reaches the trigger of
+// the most specific commission plan (broker and project, then broker, then
+// project, then the tenant default). The rate is the plan's slab for the
+// broker's volume under that plan in the trigger quarter; every accrual or
+// cancellation re-rates the quarter, booking slab adjustments for the
+// difference. Commission on a booking sold by several brokers is split between
+// their links.
+
+// CreateCommissionPlan sets up a commission plan
+func (s *BrokerService) CreateCommissionPlan(tenantID, userID string, req *models.CreateBrokerCommissionPlanRequest) (*models.BrokerCommissionPlan, error) {
+	switch req.Trigger {
+	case models.CommissionTriggerAgreementSigned:
+	case models.CommissionTriggerCollectionPercent:
+		if req.TriggerPercent <= 0 || req.TriggerPercent > 100 {
+			return nil, fmt.Errorf("trigger_percent must be between 0 and 100")
+		}
+	default:
+		return nil, fmt.Errorf("trigger must be agreement_signed or collection_percent")
+	}
+	if req.SlabBasis == "" {
+		req.SlabBasis = models.CommissionSlabBasisUnits
+	}
+	if req.SlabBasis != models.CommissionSlabBasisUnits && req.SlabBasis != models.CommissionSlabBasisValue {
+		return nil, fmt.Errorf("slab_basis must be units or value")
+	}
+	if len(req.Slabs) == 0 {
+		return nil, fmt.Errorf("at least one slab is required")
+	}
+	for _, slab := range req.Slabs {
+		if slab.MinVolume < 0 || slab.Rate < 0 || slab.Rate > 100 {
+			return nil, fmt.Errorf("slab rates must be between 0 and 100 with a non-negative min_volume")
+		}
+	}
+	if req.GSTRate < 0 || req.GSTRate > 100 {
+		return nil, fmt.Errorf("gst_rate must be between 0 and 100")
+	}
+
+	now := time.Now()
+	from := now.Truncate(24 * time.Hour)
+	if req.EffectiveFrom != nil {
+		from = *req.EffectiveFrom
+	}
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(from) {
+		return nil, fmt.Errorf("effective_to must be after effective_from")
+	}
+
+	slabs := append([]models.CommissionSlab{}, req.Slabs...)
+	sort.Slice(slabs, func(i, j int) bool { return slabs[i].MinVolume < slabs[j].MinVolume })
+	slabsJSON, err := json.Marshal(slabs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode slabs: %w", err)
+	}
+
+	p := &models.BrokerCommissionPlan{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		Name:           req.Name,
+		BrokerID:       optionalString(req.BrokerID),
+		ProjectID:      optionalString(req.ProjectID),
+		Trigger:        req.Trigger,
+		TriggerPercent: req.TriggerPercent,
+		SlabBasis:      req.SlabBasis,
+		Slabs:          slabs,
+		GSTRate:        req.GSTRate,
+		EffectiveFrom:  from,
+		EffectiveTo:    req.EffectiveTo,
+		Status:         "active",
+		CreatedBy:      optionalString(userID),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if _, err := s.DB.Exec(`INSERT INTO broker_commission_plans
+		(id, tenant_id, name, broker_id, project_id, trigger_event, trigger_percent, slab_basis, slabs, gst_rate,
+		 effective_from, effective_to, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, tenantID, p.Name, nullIfEmpty(req.BrokerID), nullIfEmpty(req.ProjectID), p.Trigger, p.TriggerPercent,
+		p.SlabBasis, slabsJSON, p.GSTRate, p.EffectiveFrom, p.EffectiveTo, p.Status, nullIfEmpty(userID), now, now); err != nil {
+		return nil, fmt.Errorf("failed to create commission plan: %w", err)
+	}
+	return p, nil
+}
+
+// ListCommissionPlans lists active commission plans, narrowed to a broker or project when set
+func (s *BrokerService) ListCommissionPlans(tenantID, brokerID, projectID string) ([]models.BrokerCommissionPlan, error) {
+	query := `SELECT id, tenant_id, name, broker_id, project_id, trigger_event, trigger_percent, slab_basis, slabs,
+		gst_rate, effective_from, effective_to, status, created_by, created_at, updated_at
+		FROM broker_commission_plans WHERE tenant_id = ? AND status = 'active'`
+	args := []interface{}{tenantID}
+	if brokerID != "" {
+		query += " AND broker_id = ?"
+		args = append(args, brokerID)
+	}
+	if projectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, projectID)
+	}
+	rows, err := s.DB.Query(query+" ORDER BY effective_from", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commission plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []models.BrokerCommissionPlan{}
+	for rows.Next() {
+		var p models.BrokerCommissionPlan
+		var broker, project, createdBy sql.NullString
+		var effectiveTo sql.NullTime
+		var slabs []byte
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.Name, &broker, &project, &p.Trigger, &p.TriggerPercent,
+			&p.SlabBasis, &slabs, &p.GSTRate, &p.EffectiveFrom, &effectiveTo, &p.Status, &createdBy,
+			&p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan commission plan: %w", err)
+		}
+		if err := json.Unmarshal(slabs, &p.Slabs); err != nil {
+			return nil, fmt.Errorf("failed to decode slabs: %w", err)
+		}
+		p.BrokerID = nullStringPtr(broker)
+		p.ProjectID = nullStringPtr(project)
+		p.CreatedBy = nullStringPtr(createdBy)
+		if effectiveTo.Valid {
+			p.EffectiveTo = &effectiveTo.Time
+		}
+		plans = append(plans, p)
+	}
+	return plans, rows.Err()
+}
+
+// findCommissionPlan returns a plan by ID from a loaded set
+func findCommissionPlan(plans []models.BrokerCommissionPlan, planID string) (*models.BrokerCommissionPlan, error) {
+	for i := range plans {
+		if plans[i].ID == planID {
+			return &plans[i], nil
+		}
+	}
+	return nil, fmt.Errorf("commission plan %s not found", planID)
+}
+
+// ============================================================================
+// COMMISSION SPLITS
+// ============================================================================
+
+// SetCommissionSplits shares a booking's commission between its broker links. The
+// shares must cover every live link and add up to 100%.
+func (s *BrokerService) SetCommissionSplits(tenantID, bookingID string, req *models.SetBrokerCommissionSplitsRequest) ([]models.BrokerCommissionSplit, error) {
+	links, err := s.bookingLinks(tenantID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("booking has no broker links")
+	}
+
+	brokers := map[string]string{}
+	for _, l := range links {
+		brokers[l.id] = l.brokerID
+	}
+	total := 0.0
+	seen := map[string]bool{}
+	for _, sp := range req.Splits {
+		if _, ok := brokers[sp.LinkID]; !ok {
+			return nil, fmt.Errorf("link %s is not a live broker link on this booking", sp.LinkID)
+		}
+		if seen[sp.LinkID] {
+			return nil, fmt.Errorf("link %s is split more than once", sp.LinkID)
+		}
+		if sp.SplitPercent <= 0 {
+			return nil, fmt.Errorf("split_percent must be positive")
+		}
+		seen[sp.LinkID] = true
+		total += sp.SplitPercent
+	}
+	if len(seen) != len(links) {
+		return nil, fmt.Errorf("every broker link on the booking needs a split")
+	}
+	if math.Abs(total-100) > 0.001 {
+		return nil, fmt.Errorf("splits add up to %.2f%%, expected 100%%", total)
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM broker_commission_splits WHERE tenant_id = ? AND booking_id = ?`,
+		tenantID, bookingID); err != nil {
+		return nil, fmt.Errorf("failed to clear commission splits: %w", err)
+	}
+	splits := []models.BrokerCommissionSplit{}
+	for _, sp := range req.Splits {
+		if _, err := tx.Exec(`INSERT INTO broker_commission_splits (id, tenant_id, booking_id, link_id, broker_id, split_percent)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), tenantID, bookingID, sp.LinkID, brokers[sp.LinkID], sp.SplitPercent); err != nil {
+			return nil, fmt.Errorf("failed to save commission split: %w", err)
+		}
+		splits = append(splits, models.BrokerCommissionSplit{LinkID: sp.LinkID, BrokerID: brokers[sp.LinkID], SplitPercent: sp.SplitPercent})
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit commission splits: %w", err)
+	}
+	return splits, nil
+}
+
+type brokerLinkRef struct {
+	id, brokerID string
+}
+
+// bookingLinks returns the booking's broker links that have not been cancelled
+func (s *BrokerService) bookingLinks(tenantID, bookingID string) ([]brokerLinkRef, error) {
+	rows, err := s.DB.Query(`SELECT id, broker_id FROM broker_booking_link
+		WHERE tenant_id = ? AND booking_id = ? AND commission_status NOT IN ('cancelled', 'clawed_back')
+		AND deleted_at IS NULL ORDER BY created_at`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []brokerLinkRef{}
+	for rows.Next() {
+		var l brokerLinkRef
+		if err := rows.Scan(&l.id, &l.brokerID); err != nil {
+			return nil, fmt.Errorf("failed to scan booking link: %w", err)
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// bookingSplits returns each live link's share of the booking's commission
+func (s *BrokerService) bookingSplits(tenantID, bookingID string) (map[string]float64, error) {
+	links, err := s.bookingLinks(tenantID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DB.Query(`SELECT link_id, split_percent FROM broker_commission_splits
+		WHERE tenant_id = ? AND booking_id = ?`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission splits: %w", err)
+	}
+	defer rows.Close()
+
+	configured := map[string]float64{}
+	for rows.Next() {
+		var linkID string
+		var pct float64
+		if err := rows.Scan(&linkID, &pct); err != nil {
+			return nil, fmt.Errorf("failed to scan commission split: %w", err)
+		}
+		configured[linkID] = pct
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(links))
+	for i, l := range links {
+		ids[i] = l.id
+	}
+	return commissionSplits(ids, configured), nil
+}
+
+// commissionSplits shares commission between a booking's links: by the configured
+// splits when there are any, otherwise equally
+func commissionSplits(linkIDs []string, configured map[string]float64) map[string]float64 {
+	splits := map[string]float64{}
+	if len(linkIDs) == 0 {
+		return splits
+	}
+	if len(configured) > 0 {
+		for _, id := range linkIDs {
+			splits[id] = configured[id]
+		}
+		return splits
+	}
+	for _, id := range linkIDs {
+		splits[id] = math.Round(100/float64(len(linkIDs))*10000) / 10000
+	}
+	return splits
+}
+
+// ============================================================================
+// TRIGGER EVALUATION AND ACCRUAL
+// ============================================================================
+
+// EvaluateBookingCommission accrues commission on a booking's pending links that have reached their trigger
+func (s *BrokerService) EvaluateBookingCommission(tenantID, bookingID, userID string) (*models.BrokerCommissionRun, error) {
+	return s.evaluateCommission(tenantID, bookingID, userID, time.Now())
+}
+
+// EvaluatePendingCommission sweeps every pending link, accruing those whose booking has reached the trigger
+func (s *BrokerService) EvaluatePendingCommission(tenantID, userID string) (*models.BrokerCommissionRun, error) {
+	return s.evaluateCommission(tenantID, "", userID, time.Now())
+}
+
+type pendingCommissionLink struct {
+	id, brokerID, bookingID, projectID string
+	agreementDate                      sql.NullTime
+	agreementValue, collected          float64
+}
+
+func (s *BrokerService) evaluateCommission(tenantID, bookingID, userID string, now time.Time) (*models.BrokerCommissionRun, error) {
+	plans, err := s.ListCommissionPlans(tenantID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Collections count cleared receipts plus the TDS the buyer deducted on them
+	query := `SELECT l.id, l.broker_id, l.booking_id, COALESCE(u.project_id, ''), b.agreement_date,
+		COALESCE(bpl.agreement_value, ucs.apartment_cost_exc_govt, 0),
+		(SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0) FROM booking_payments p
+			LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+			WHERE p.tenant_id = b.tenant_id AND p.booking_id = b.id AND p.status = 'cleared' AND p.deleted_at IS NULL)
+		FROM broker_booking_link l
+		JOIN customer_bookings b ON b.id = l.booking_id AND b.tenant_id = l.tenant_id
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN booking_price_locks bpl ON bpl.booking_id = b.id AND bpl.tenant_id = b.tenant_id
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = b.unit_id AND ucs.tenant_id = b.tenant_id
+		WHERE l.tenant_id = ? AND l.commission_status = 'pending' AND l.deleted_at IS NULL
+		AND b.booking_status = 'active' AND b.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM broker_commission_accruals a WHERE a.tenant_id = l.tenant_id
+			AND a.link_id = l.id AND a.kind = 'commission' AND a.status <> 'cancelled')`
+	args := []interface{}{tenantID}
+	if bookingID != "" {
+		query += " AND l.booking_id = ?"
+		args = append(args, bookingID)
+	}
+	rows, err := s.DB.Query(query+" ORDER BY l.created_at", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending booking links: %w", err)
+	}
+	links := []pendingCommissionLink{}
+	for rows.Next() {
+		var l pendingCommissionLink
+		if err := rows.Scan(&l.id, &l.brokerID, &l.bookingID, &l.projectID, &l.agreementDate,
+			&l.agreementValue, &l.collected); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan booking link: %w", err)
+		}
+		links = append(links, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	run := &models.BrokerCommissionRun{Evaluated: len(links), Accruals: []models.BrokerCommissionAccrual{}}
+	splitsByBooking := map[string]map[string]float64{}
+	for _, l := range links {
+		plan := pickCommissionPlan(plans, l.brokerID, l.projectID, now)
+		if plan == nil || l.agreementValue <= 0 {
+			continue
+		}
+		triggeredOn, ok := commissionTriggered(plan, l.agreementDate, l.agreementValue, l.collected, now)
+		if !ok {
+			continue
+		}
+		splits, ok := splitsByBooking[l.bookingID]
+		if !ok {
+			if splits, err = s.bookingSplits(tenantID, l.bookingID); err != nil {
+				return nil, err
+			}
+			splitsByBooking[l.bookingID] = splits
+		}
+
+		accrued, err := s.accrueCommission(tenantID, userID, plan, &l, splits[l.id], triggeredOn)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accrued {
+			run.Accrued += a.Commission
+		}
+		run.Accruals = append(run.Accruals, accrued...)
+	}
+	run.Accrued = roundTo2(run.Accrued)
+	return run, nil
+}
+
+// pickCommissionPlan returns the most specific plan in force on a date for a broker
+// and project. A broker match outranks a project match; ties go to the latest plan.
+func pickCommissionPlan(plans []models.BrokerCommissionPlan, brokerID, projectID string, on time.Time) *models.BrokerCommissionPlan {
+	var best *models.BrokerCommissionPlan
+	bestScore := -1
+	for i := range plans {
+		p := &plans[i]
+		if p.EffectiveFrom.After(on) || (p.EffectiveTo != nil && !p.EffectiveTo.After(on)) {
+			continue
+		}
+		score := 0
+		if p.BrokerID != nil {
+			if *p.BrokerID != brokerID {
+				continue
+			}
+			score += 2
+		}
+		if p.ProjectID != nil {
+			if *p.ProjectID != projectID {
+				continue
+			}
+			score++
+		}
+		if score > bestScore || (score == bestScore && p.EffectiveFrom.After(best.EffectiveFrom)) {
+			best, bestScore = p, score
+		}
+	}
+	return best
+}
+
+// commissionTriggered reports whether a booking has reached the plan's trigger and on which date
+func commissionTriggered(plan *models.BrokerCommissionPlan, agreementDate sql.NullTime, agreementValue, collected float64, now time.Time) (time.Time, bool) {
+	switch plan.Trigger {
+	case models.CommissionTriggerAgreementSigned:
+		if agreementDate.Valid && !agreementDate.Time.After(now) {
+			return agreementDate.Time, true
+		}
+	case models.CommissionTriggerCollectionPercent:
+		if agreementValue > 0 && collected/agreementValue*100 >= plan.TriggerPercent {
+			return now, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// commissionQuarter returns the YYYY-Qn quarter of a date
+func commissionQuarter(t time.Time) string {
+	return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// accrueCommission books commission on a link at the rate for the quarter's volume
+// including this booking, then re-rates the rest of the quarter
+func (s *BrokerService) accrueCommission(tenantID, userID string, plan *models.BrokerCommissionPlan, l *pendingCommissionLink, split float64, triggeredOn time.Time) ([]models.BrokerCommissionAccrual, error) {
+	quarter := commissionQuarter(triggeredOn)
+	lines, err := s.quarterCommissionLines(tenantID, l.brokerID, plan.ID, quarter)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, commissionLine{LinkID: l.id, BookingID: l.bookingID, Base: l.agreementValue, Split: split})
+	rate := slabRate(plan.Slabs, quarterVolume(lines, plan.SlabBasis))
+
+	now := time.Now()
+	commission := roundTo2(l.agreementValue * rate / 100 * split / 100)
+	a := models.BrokerCommissionAccrual{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		LinkID:       l.id,
+		BrokerID:     l.brokerID,
+		BookingID:    l.bookingID,
+		ProjectID:    l.projectID,
+		PlanID:       plan.ID,
+		Quarter:      quarter,
+		Kind:         models.CommissionAccrualCommission,
+		BaseAmount:   l.agreementValue,
+		Rate:         rate,
+		SplitPercent: split,
+		Commission:   commission,
+		GSTAmount:    roundTo2(commission * plan.GSTRate / 100),
+		Status:       models.CommissionAccrualAccrued,
+		TriggeredOn:  triggeredOn,
+		CreatedAt:    now,
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertCommissionAccrual(tx, &a); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE broker_booking_link SET commission_status = 'approved', approval_date = ?,
+		updated_by = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`, now, userID, now, l.id, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to approve booking link: %w", err)
+	}
+	if err := syncLinkCommission(tx, tenantID, l.id, rate); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit commission accrual: %w", err)
+	}
+
+	adjustments, err := s.rebalanceQuarter(tenantID, l.brokerID, plan, quarter)
+	if err != nil {
+		return nil, fmt.Errorf("commission accrued but %w", err)
+	}
+	return append([]models.BrokerCommissionAccrual{a}, adjustments...), nil
+}
+
+// ============================================================================
+// QUARTERLY SLABS
+// ============================================================================
+
+// commissionLine is a link's live commission in a quarter
+type commissionLine struct {
+	LinkID    string
+	BookingID string
+	ProjectID string
+	Base      float64
+	Split     float64
+	Accrued   float64 // commission and slab adjustments booked so far
+}
+
+// quarterCommissionLines returns the links accruing under a plan for a broker in a quarter
+func (s *BrokerService) quarterCommissionLines(tenantID, brokerID, planID, quarter string) ([]commissionLine, error) {
+	rows, err := s.DB.Query(`SELECT a.link_id, a.booking_id, a.project_id, a.base_amount, a.split_percent,
+		(SELECT COALESCE(SUM(x.commission), 0) FROM broker_commission_accruals x
+			WHERE x.tenant_id = a.tenant_id AND x.link_id = a.link_id AND x.status IN ('accrued', 'paid'))
+		FROM broker_commission_accruals a
+		WHERE a.tenant_id = ? AND a.broker_id = ? AND a.plan_id = ? AND a.quarter = ?
+		AND a.kind = 'commission' AND a.status IN ('accrued', 'paid')
+		ORDER BY a.triggered_on, a.created_at`, tenantID, brokerID, planID, quarter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarter commission: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []commissionLine{}
+	for rows.Next() {
+		var c commissionLine
+		if err := rows.Scan(&c.LinkID, &c.BookingID, &c.ProjectID, &c.Base, &c.Split, &c.Accrued); err != nil {
+			return nil, fmt.Errorf("failed to scan quarter commission: %w", err)
+		}
+		lines = append(lines, c)
+	}
+	return lines, rows.Err()
+}
+
+// rebalanceQuarter re-rates a broker's quarter under a plan to the slab its current
+// volume reaches, booking the differences as slab adjustments
+func (s *BrokerService) rebalanceQuarter(tenantID, brokerID string, plan *models.BrokerCommissionPlan, quarter string) ([]models.BrokerCommissionAccrual, error) {
+	lines, err := s.quarterCommissionLines(tenantID, brokerID, plan.ID, quarter)
+	if err != nil {
+		return nil, err
+	}
+	rate, diffs := quarterAdjustments(lines, plan)
+	if len(diffs) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	adjustments := []models.BrokerCommissionAccrual{}
+	for _, d := range diffs {
+		a := models.BrokerCommissionAccrual{
+			ID:           uuid.New().String(),
+			TenantID:     tenantID,
+			LinkID:       d.line.LinkID,
+			BrokerID:     brokerID,
+			BookingID:    d.line.BookingID,
+			ProjectID:    d.line.ProjectID,
+			PlanID:       plan.ID,
+			Quarter:      quarter,
+			Kind:         models.CommissionAccrualSlabAdjustment,
+			BaseAmount:   d.line.Base,
+			Rate:         rate,
+			SplitPercent: d.line.Split,
+			Commission:   d.amount,
+			GSTAmount:    roundTo2(d.amount * plan.GSTRate / 100),
+			Status:       models.CommissionAccrualAccrued,
+			TriggeredOn:  now,
+			CreatedAt:    now,
+		}
+		if err := insertCommissionAccrual(tx, &a); err != nil {
+			return nil, err
+		}
+		if err := syncLinkCommission(tx, tenantID, a.LinkID, rate); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, a)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit slab adjustments: %w", err)
+	}
+	return adjustments, nil
+}
+
+// slabRate returns the rate of the highest slab the volume reaches, 0 below the first
+func slabRate(slabs []models.CommissionSlab, volume float64) float64 {
+	rate := 0.0
+	for _, slab := range slabs {
+		if volume >= slab.MinVolume {
+			rate = slab.Rate
+		}
+	}
+	return rate
+}
+
+// quarterVolume measures a quarter's volume in bookings or agreement value
+func quarterVolume(lines []commissionLine, basis string) float64 {
+	if basis == models.CommissionSlabBasisValue {
+		total := 0.0
+		for _, l := range lines {
+			total += l.Base
+		}
+		return total
+	}
+	bookings := map[string]bool{}
+	for _, l := range lines {
+		bookings[l.BookingID] = true
+	}
+	return float64(len(bookings))
+}
+
+type commissionDiff struct {
+	line   commissionLine
+	amount float64
+}
+
+// quarterAdjustments returns the quarter's slab rate and, for each line, the
+// commission still to book (or reverse) to bring it to that rate
+func quarterAdjustments(lines []commissionLine, plan *models.BrokerCommissionPlan) (float64, []commissionDiff) {
+	rate := slabRate(plan.Slabs, quarterVolume(lines, plan.SlabBasis))
+	diffs := []commissionDiff{}
+	for _, l := range lines {
+		target := roundTo2(l.Base * rate / 100 * l.Split / 100)
+		if diff := roundTo2(target - l.Accrued); math.Abs(diff) >= 0.01 {
+			diffs = append(diffs, commissionDiff{line: l, amount: diff})
+		}
+	}
+	return rate, diffs
+}
+
+func insertCommissionAccrual(tx *sql.Tx, a *models.BrokerCommissionAccrual) error {
+	if _, err := tx.Exec(`INSERT INTO broker_commission_accruals
+		(id, tenant_id, link_id, broker_id, booking_id, project_id, plan_id, quarter, kind, base_amount, rate,
+		 split_percent, commission, gst_amount, status, triggered_on, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, a.TenantID, a.LinkID, a.BrokerID, a.BookingID, a.ProjectID, a.PlanID, a.Quarter, a.Kind, a.BaseAmount,
+		a.Rate, a.SplitPercent, a.Commission, a.GSTAmount, a.Status, a.TriggeredOn, a.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record commission accrual: %w", err)
+	}
+	return nil
+}
+
+// syncLinkCommission keeps the link's commission at the total of its live accruals so
+// brokerage recovery and cancellation see the engine's figure
+func syncLinkCommission(tx *sql.Tx, tenantID, linkID string, rate float64) error {
+	if _, err := tx.Exec(`UPDATE broker_booking_link SET commission_percentage = ?,
+		commission_amount = (SELECT COALESCE(SUM(commission), 0) FROM broker_commission_accruals
+			WHERE tenant_id = ? AND link_id = ? AND status IN ('accrued', 'paid'))
+		WHERE id = ? AND tenant_id = ?`, rate, tenantID, linkID, linkID, tenantID); err != nil {
+		return fmt.Errorf("failed to update link commission: %w", err)
+	}
+	return nil
+}
+
+// ListCommissionAccruals lists accruals, narrowed by broker, booking and status when set
+func (s *BrokerService) ListCommissionAccruals(tenantID, brokerID, bookingID, status string) ([]models.BrokerCommissionAccrual, error) {
+	where := ""
+	args := []interface{}{tenantID}
+	if brokerID != "" {
+		where += " AND broker_id = ?"
+		args = append(args, brokerID)
+	}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	return s.getCommissionAccruals(s.DB, where, args...)
+}
+
+func (s *BrokerService) getCommissionAccruals(db interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, where string, args ...interface{}) ([]models.BrokerCommissionAccrual, error) {
+	rows, err := db.Query(`SELECT id, tenant_id, link_id, broker_id, booking_id, project_id, plan_id, quarter, kind,
+		base_amount, rate, split_percent, commission, gst_amount, status, payout_id, triggered_on, created_at
+		FROM broker_commission_accruals WHERE tenant_id = ?`+where+` ORDER BY triggered_on, created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commission accruals: %w", err)
+	}
+	defer rows.Close()
+
+	accruals := []models.BrokerCommissionAccrual{}
+	for rows.Next() {
+		var a models.BrokerCommissionAccrual
+		var payoutID sql.NullString
+		if err := rows.Scan(&a.ID, &a.TenantID, &a.LinkID, &a.BrokerID, &a.BookingID, &a.ProjectID, &a.PlanID,
+			&a.Quarter, &a.Kind, &a.BaseAmount, &a.Rate, &a.SplitPercent, &a.Commission, &a.GSTAmount, &a.Status,
+			&payoutID, &a.TriggeredOn, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan commission accrual: %w", err)
+		}
+		a.PayoutID = nullStringPtr(payoutID)
+		accruals = append(accruals, a)
+	}
+	return accruals, rows.Err()
+}
+
+// ============================================================================
+// CANCELLATION
+// ============================================================================
+
+// commissionQuarterKey identifies a broker's quarter under a plan
+type commissionQuarterKey struct {
+	brokerID, planID, quarter string
+}
+
+// cancelLinkAccruals cancels a cancelled booking link's unpaid accruals and marks the
+// paid ones clawed back, returning the paid commission to recover and the quarters to re-rate
+func cancelLinkAccruals(tx *sql.Tx, tenantID, linkID string, now time.Time) (float64, []commissionQuarterKey, error) {
+	rows, err := tx.Query(`SELECT broker_id, plan_id, quarter, status, commission FROM broker_commission_accruals
+		WHERE tenant_id = ? AND link_id = ? AND status IN ('accrued', 'paid')`, tenantID, linkID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get link accruals: %w", err)
+	}
+	paid := 0.0
+	seen := map[commissionQuarterKey]bool{}
+	quarters := []commissionQuarterKey{}
+	for rows.Next() {
+		var k commissionQuarterKey
+		var status string
+		var commission float64
+		if err := rows.Scan(&k.brokerID, &k.planID, &k.quarter, &status, &commission); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan link accrual: %w", err)
+		}
+		if status == models.CommissionAccrualPaid {
+			paid += commission
+		}
+		if !seen[k] {
+			seen[k] = true
+			quarters = append(quarters, k)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE broker_commission_accruals
+		SET status = CASE status WHEN 'paid' THEN 'clawed_back' ELSE 'cancelled' END, cancelled_at = ?
+		WHERE tenant_id = ? AND link_id = ? AND status IN ('accrued', 'paid')`, now, tenantID, linkID); err != nil {
+		return 0, nil, fmt.Errorf("failed to cancel link accruals: %w", err)
+	}
+	return roundTo2(paid), quarters, nil
+}
+
+// rebalanceQuarters re-rates the quarters a cancellation took volume out of
+func (s *BrokerService) rebalanceQuarters(tenantID string, quarters []commissionQuarterKey) error {
+	if len(quarters) == 0 {
+		return nil
+	}
+	plans, err := s.allCommissionPlans(tenantID)
+	if err != nil {
+		return err
+	}
+	for _, k := range quarters {
+		plan, err := findCommissionPlan(plans, k.planID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.rebalanceQuarter(tenantID, k.brokerID, plan, k.quarter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allCommissionPlans returns every plan, including retired ones still carrying accruals
+func (s *BrokerService) allCommissionPlans(tenantID string) ([]models.BrokerCommissionPlan, error) {
+	rows, err := s.DB.Query(`SELECT id, trigger_event, slab_basis, slabs, gst_rate FROM broker_commission_plans
+		WHERE tenant_id = ?`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []models.BrokerCommissionPlan{}
+	for rows.Next() {
+		var p models.BrokerCommissionPlan
+		var slabs []byte
+		if err := rows.Scan(&p.ID, &p.Trigger, &p.SlabBasis, &slabs, &p.GSTRate); err != nil {
+			return nil, fmt.Errorf("failed to scan commission plan: %w", err)
+		}
+		if err := json.Unmarshal(slabs, &p.Slabs); err != nil {
+			return nil, fmt.Errorf("failed to decode slabs: %w", err)
+		}
+		plans = append(plans, p)
+	}
+	return plans, rows.Err()
+}
+
+// ============================================================================
+// PAYOUTS
+// ============================================================================
+
+// GenerateBrokerPayout settles a broker's accrued commission. TDS under section 194H
+// is deducted on the commission, and pending clawbacks are netted off the payout, in
+// the transaction that records it.
+func (s *BrokerService) GenerateBrokerPayout(tenantID, userID string, req *models.GenerateBrokerPayoutRequest) (*models.BrokerPayout, error) {
+	var brokerName string
+	var pan sql.NullString
+	err := s.DB.QueryRow(`SELECT broker_name, pan_no FROM broker_profile WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`,
+		req.BrokerID, tenantID).Scan(&brokerName, &pan)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("broker not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker: %w", err)
+	}
+
+	accruals, err := s.getCommissionAccruals(s.DB, " AND broker_id = ? AND status = 'accrued'", tenantID, req.BrokerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	p := &models.BrokerPayout{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		PayoutNumber: interestNoteNumber("BPO"),
+		BrokerID:     req.BrokerID,
+		Status:       models.BrokerPayoutGenerated,
+		AccrualIDs:   []string{},
+		CreatedBy:    optionalString(userID),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for _, a := range accruals {
+		p.Commission += a.Commission
+		p.GSTAmount += a.GSTAmount
+		p.AccrualIDs = append(p.AccrualIDs, a.ID)
+	}
+	p.Commission = roundTo2(p.Commission)
+	p.GSTAmount = roundTo2(p.GSTAmount)
+	if p.Commission <= 0 {
+		return nil, fmt.Errorf("broker has no commission payable")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry, err := NewTDSService(s.DB).DeductTx(tx, tenantID, &models.TDSDeductionInput{
+		SourceType:      models.TDSSourceBrokerPayout,
+		SourceID:        p.PayoutNumber,
+		PayeeType:       models.TDSPayeeBroker,
+		PayeeID:         req.BrokerID,
+		PayeeName:       brokerName,
+		PAN:             pan.String,
+		Section:         models.TDSSection194H,
+		Amount:          p.Commission,
+		TransactionDate: now,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deduct tds: %w", err)
+	}
+	p.TDSAmount = entry.TDSAmount
+
+	net := roundTo2(p.Commission + p.GSTAmount - p.TDSAmount)
+	if p.ClawbackAmount, err = s.adjustClawbacks(tx, tenantID, req.BrokerID, p.PayoutNumber, net); err != nil {
+		return nil, err
+	}
+	p.NetAmount = roundTo2(net - p.ClawbackAmount)
+
+	if _, err := tx.Exec(`INSERT INTO broker_payouts
+		(id, tenant_id, payout_number, broker_id, commission, gst_amount, tds_amount, clawback_amount, net_amount,
+		 status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, tenantID, p.PayoutNumber, p.BrokerID, p.Commission, p.GSTAmount, p.TDSAmount, p.ClawbackAmount,
+		p.NetAmount, p.Status, nullIfEmpty(userID), now, now); err != nil {
+		return nil, fmt.Errorf("failed to create broker payout: %w", err)
+	}
+	links := map[string]bool{}
+	for _, a := range accruals {
+		res, err := tx.Exec(`UPDATE broker_commission_accruals SET status = 'paid', payout_id = ?
+			WHERE id = ? AND tenant_id = ? AND status = 'accrued'`, p.ID, a.ID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to settle commission accrual: %w", err)
+		}
+		// An accrual settled or cancelled since it was read would be paid twice or wrongly
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("commission accrual %s changed while the payout was generated", a.ID)
+		}
+		links[a.LinkID] = true
+	}
+	for linkID := range links {
+		if _, err := tx.Exec(`UPDATE broker_booking_link SET commission_status = 'paid', payment_date = ?,
+			updated_by = ?, updated_at = ? WHERE id = ? AND tenant_id = ? AND commission_status = 'approved'`,
+			now, userID, now, linkID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to mark booking link paid: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit broker payout: %w", err)
+	}
+	return p, nil
+}
+
+// MarkBrokerPayoutPaid records payment of a generated payout
+func (s *BrokerService) MarkBrokerPayoutPaid(tenantID, payoutID string, req *models.MarkBrokerPayoutPaidRequest) (*models.BrokerPayout, error) {
+	paidOn := time.Now()
+	if req.PaymentDate != nil {
+		paidOn = *req.PaymentDate
+	}
+	res, err := s.DB.Exec(`UPDATE broker_payouts SET status = 'paid', payment_date = ?, payment_mode = ?, reference = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = 'generated'`,
+		paidOn, req.PaymentMode, nullIfEmpty(req.Reference), time.Now(), payoutID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark payout paid: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("generated payout not found")
+	}
+	return s.GetBrokerPayout(tenantID, payoutID)
+}
+
+// GetBrokerPayout returns a payout with the accruals it settled
+func (s *BrokerService) GetBrokerPayout(tenantID, payoutID string) (*models.BrokerPayout, error) {
+	payouts, err := s.getBrokerPayouts(" AND id = ?", tenantID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if len(payouts) == 0 {
+		return nil, fmt.Errorf("payout not found")
+	}
+	p := &payouts[0]
+	accruals, err := s.getCommissionAccruals(s.DB, " AND payout_id = ?", tenantID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	p.AccrualIDs = make([]string, len(accruals))
+	for i, a := range accruals {
+		p.AccrualIDs[i] = a.ID
+	}
+	return p, nil
+}
+
+// ListBrokerPayouts lists payouts, narrowed by broker and status when set
+func (s *BrokerService) ListBrokerPayouts(tenantID, brokerID, status string) ([]models.BrokerPayout, error) {
+	where := ""
+	args := []interface{}{tenantID}
+	if brokerID != "" {
+		where += " AND broker_id = ?"
+		args = append(args, brokerID)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	return s.getBrokerPayouts(where, args...)
+}
+
+func (s *BrokerService) getBrokerPayouts(where string, args ...interface{}) ([]models.BrokerPayout, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, payout_number, broker_id, commission, gst_amount, tds_amount,
+		clawback_amount, net_amount, status, payment_date, payment_mode, reference, created_by, created_at, updated_at
+		FROM broker_payouts WHERE tenant_id = ?`+where+` ORDER BY created_at DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list broker payouts: %w", err)
+	}
+	defer rows.Close()
+
+	payouts := []models.BrokerPayout{}
+	for rows.Next() {
+		var p models.BrokerPayout
+		var paymentDate sql.NullTime
+		var mode, reference, createdBy sql.NullString
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.PayoutNumber, &p.BrokerID, &p.Commission, &p.GSTAmount,
+			&p.TDSAmount, &p.ClawbackAmount, &p.NetAmount, &p.Status, &paymentDate, &mode, &reference, &createdBy,
+			&p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan broker payout: %w", err)
+		}
+		if paymentDate.Valid {
+			p.PaymentDate = &paymentDate.Time
+		}
+		p.PaymentMode = nullStringPtr(mode)
+		p.Reference = nullStringPtr(reference)
+		p.CreatedBy = nullStringPtr(createdBy)
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+// ============================================================================
+// BROKER STATEMENT
+// ============================================================================
+
+// GetBrokerStatement returns a broker's commission account for [from, to): accruals
+// and slab adjustments with their GST, reversals on cancelled bookings, clawbacks
+// raised, and the TDS and amount paid on each payout
+func (s *BrokerService) GetBrokerStatement(tenantID, brokerID string, from, to time.Time) (*models.BrokerStatement, error) {
+	st := &models.BrokerStatement{BrokerID: brokerID, From: from, To: to}
+	var pan, gstin sql.NullString
+	err := s.DB.QueryRow(`SELECT broker_name, pan_no, gst_no FROM broker_profile WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`,
+		brokerID, tenantID).Scan(&st.BrokerName, &pan, &gstin)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("broker not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker: %w", err)
+	}
+	st.PAN, st.GSTIN = pan.String, gstin.String
+
+	lines := []models.BrokerStatementLine{}
+
+	rows, err := s.DB.Query(`SELECT booking_id, kind, quarter, rate, commission, gst_amount, status, triggered_on, cancelled_at
+		FROM broker_commission_accruals WHERE tenant_id = ? AND broker_id = ? AND triggered_on < ?`, tenantID, brokerID, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commission accruals: %w", err)
+	}
+	for rows.Next() {
+		var bookingID, kind, quarter, status string
+		var rate, commission, gst float64
+		var on time.Time
+		var cancelledAt sql.NullTime
+		if err := rows.Scan(&bookingID, &kind, &quarter, &rate, &commission, &gst, &status, &on, &cancelledAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan commission accrual: %w", err)
+		}
+		desc := fmt.Sprintf("%s at %.2f%% (%s)", strings.ReplaceAll(kind, "_", " "), rate, quarter)
+		lines = append(lines, statementAmountLine(on, kind, bookingID, quarter, desc, commission))
+		if gst != 0 {
+			lines = append(lines, statementAmountLine(on, "gst", bookingID, quarter, "GST on "+desc, gst))
+		}
+		// Unpaid commission on a cancelled booking is reversed; paid commission is clawed back instead
+		if status == models.CommissionAccrualCancelled && cancelledAt.Valid {
+			lines = append(lines, statementAmountLine(cancelledAt.Time, "reversal", bookingID, quarter,
+				"Reversal on cancellation: "+desc, -commission))
+			if gst != 0 {
+				lines = append(lines, statementAmountLine(cancelledAt.Time, "gst", bookingID, quarter,
+					"GST reversal on cancellation", -gst))
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	clawbacks, err := s.ListClawbacks(tenantID, brokerID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range clawbacks {
+		lines = append(lines, models.BrokerStatementLine{Date: c.CreatedAt, Type: "clawback", BookingID: c.BookingID,
+			Reference: c.ID, Description: "Commission clawed back on cancelled booking", Debit: c.Amount})
+	}
+
+	payouts, err := s.ListBrokerPayouts(tenantID, brokerID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payouts {
+		if p.TDSAmount > 0 {
+			lines = append(lines, models.BrokerStatementLine{Date: p.CreatedAt, Type: "tds", Reference: p.PayoutNumber,
+				Description: "TDS u/s 194H", Debit: p.TDSAmount})
+		}
+		desc := "Commission payout"
+		if p.ClawbackAmount > 0 {
+			desc = fmt.Sprintf("Commission payout after clawback of %.2f", p.ClawbackAmount)
+		}
+		lines = append(lines, models.BrokerStatementLine{Date: p.CreatedAt, Type: "payout", Reference: p.PayoutNumber,
+			Description: desc, Debit: p.NetAmount})
+	}
+
+	buildBrokerStatement(st, lines)
+	return st, nil
+}
+
+// statementAmountLine credits a positive amount and debits a negative one
+func statementAmountLine(on time.Time, kind, bookingID, reference, desc string, amount float64) models.BrokerStatementLine {
+	line := models.BrokerStatementLine{Date: on, Type: kind, BookingID: bookingID, Reference: reference, Description: desc}
+	if amount >= 0 {
+		line.Credit = amount
+	} else {
+		line.Debit = -amount
+	}
+	return line
+}
+
+// buildBrokerStatement orders the lines, carries those before the period into the
+// opening balance and totals the period by line type
+func buildBrokerStatement(st *models.BrokerStatement, lines []models.BrokerStatementLine) {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Date.Before(lines[j].Date) })
+
+	st.Lines = []models.BrokerStatementLine{}
+	balance := 0.0
+	for _, l := range lines {
+		if !l.Date.Before(st.To) {
+			continue
+		}
+		net := l.Credit - l.Debit
+		balance = roundTo2(balance + net)
+		if l.Date.Before(st.From) {
+			st.Opening = balance
+			continue
+		}
+		switch l.Type {
+		case models.CommissionAccrualCommission, models.CommissionAccrualSlabAdjustment, "reversal":
+			st.Commission += net
+		case "gst":
+			st.GSTAmount += net
+		case "tds":
+			st.TDSAmount += l.Debit
+		case "clawback":
+			st.ClawbackAmount += l.Debit
+		case "payout":
+			st.Paid += l.Debit
+		}
+		l.Balance = balance
+		st.Lines = append(st.Lines, l)
+	}
+	st.Commission = roundTo2(st.Commission)
+	st.GSTAmount = roundTo2(st.GSTAmount)
+	st.TDSAmount = roundTo2(st.TDSAmount)
+	st.ClawbackAmount = roundTo2(st.ClawbackAmount)
+	st.Paid = roundTo2(st.Paid)
+	st.Closing = balance
+}
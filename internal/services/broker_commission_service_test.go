@@ -0,0 +1,112 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPickCommissionPlan tests that the most specific plan in force wins
+func TestPickCommissionPlan(t *testing.T) {
+	broker, project, other := "b1", "p1", "p2"
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	apr := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	plans := []models.BrokerCommissionPlan{
+		{ID: "default", EffectiveFrom: jan},
+		{ID: "project", ProjectID: &project, EffectiveFrom: jan},
+		{ID: "broker", BrokerID: &broker, EffectiveFrom: jan, EffectiveTo: &apr},
+		{ID: "broker-project", BrokerID: &broker, ProjectID: &project, EffectiveFrom: apr},
+		{ID: "other-project", ProjectID: &other, EffectiveFrom: jan},
+	}
+	feb := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	may := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "broker", pickCommissionPlan(plans, broker, project, feb).ID)
+	assert.Equal(t, "broker-project", pickCommissionPlan(plans, broker, project, may).ID)
+	assert.Equal(t, "project", pickCommissionPlan(plans, "b2", project, may).ID)
+	assert.Equal(t, "default", pickCommissionPlan(plans, broker, "p3", may).ID) // the broker plan has lapsed
+	assert.Equal(t, "default", pickCommissionPlan(plans, "b2", "p3", may).ID)
+	assert.Nil(t, pickCommissionPlan(plans, broker, project, jan.AddDate(0, 0, -1)))
+
+	collection := &models.BrokerCommissionPlan{Trigger: models.CommissionTriggerCollectionPercent, TriggerPercent: 20}
+	_, ok := commissionTriggered(collection, sql.NullTime{}, 5000000, 900000, may)
+	assert.False(t, ok)
+	on, ok := commissionTriggered(collection, sql.NullTime{}, 5000000, 1000000, may)
+	assert.True(t, ok)
+	assert.Equal(t, may, on)
+
+	signed := &models.BrokerCommissionPlan{Trigger: models.CommissionTriggerAgreementSigned}
+	on, ok = commissionTriggered(signed, sql.NullTime{Time: feb, Valid: true}, 5000000, 0, may)
+	assert.True(t, ok)
+	assert.Equal(t, feb, on)
+	assert.Equal(t, "2026-Q2", commissionQuarter(may))
+}
+
+// TestQuarterSlabAdjustments tests re-rating a quarter as volume crosses slabs
+func TestQuarterSlabAdjustments(t *testing.T) {
+	plan := &models.BrokerCommissionPlan{
+		SlabBasis: models.CommissionSlabBasisUnits,
+		Slabs:     []models.CommissionSlab{{MinVolume: 0, Rate: 2}, {MinVolume: 3, Rate: 2.5}},
+	}
+	assert.Equal(t, 0.0, slabRate([]models.CommissionSlab{{MinVolume: 1, Rate: 2}}, 0))
+	assert.Equal(t, 2.5, slabRate(plan.Slabs, 4))
+
+	lines := []commissionLine{
+		{LinkID: "l1", BookingID: "bk1", Base: 5000000, Split: 100, Accrued: 100000},
+		{LinkID: "l2", BookingID: "bk2", Base: 4000000, Split: 50, Accrued: 40000},
+		{LinkID: "l3", BookingID: "bk3", Base: 6000000, Split: 100, Accrued: 150000},
+	}
+	// The third booking takes the quarter to the 2.5% slab for all three
+	rate, diffs := quarterAdjustments(lines, plan)
+	assert.Equal(t, 2.5, rate)
+	assert.Len(t, diffs, 2)
+	assert.Equal(t, "l1", diffs[0].line.LinkID)
+	assert.Equal(t, 25000.0, diffs[0].amount)
+	assert.Equal(t, 10000.0, diffs[1].amount)
+
+	// Cancelling one of them drops the rest back to 2%
+	lines[0].Accrued, lines[1].Accrued = 125000, 50000
+	rate, diffs = quarterAdjustments(lines[:2], plan)
+	assert.Equal(t, 2.0, rate)
+	assert.Len(t, diffs, 2)
+	assert.Equal(t, -25000.0, diffs[0].amount)
+	assert.Equal(t, -10000.0, diffs[1].amount)
+
+	byValue := &models.BrokerCommissionPlan{SlabBasis: models.CommissionSlabBasisValue}
+	assert.Equal(t, 9000000.0, quarterVolume(lines[:2], byValue.SlabBasis))
+
+	assert.Equal(t, map[string]float64{"l1": 50, "l2": 50}, commissionSplits([]string{"l1", "l2"}, nil))
+	assert.Equal(t, map[string]float64{"l1": 70, "l2": 0}, commissionSplits([]string{"l1", "l2"}, map[string]float64{"l1": 70}))
+}
+
+// TestBuildBrokerStatement tests the opening balance, running balance and period totals
+func TestBuildBrokerStatement(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	st := &models.BrokerStatement{From: day(4, 1), To: day(7, 1)}
+	buildBrokerStatement(st, []models.BrokerStatementLine{
+		{Date: day(4, 20), Type: "tds", Debit: 5000},
+		{Date: day(4, 20), Type: "payout", Debit: 113000},
+		statementAmountLine(day(3, 10), models.CommissionAccrualCommission, "bk1", "2026-Q1", "", 100000),
+		statementAmountLine(day(3, 10), "gst", "bk1", "2026-Q1", "", 18000),
+		statementAmountLine(day(5, 5), models.CommissionAccrualCommission, "bk2", "2026-Q2", "", 50000),
+		statementAmountLine(day(5, 5), "gst", "bk2", "2026-Q2", "", 9000),
+		statementAmountLine(day(6, 1), "reversal", "bk2", "2026-Q2", "", -50000),
+		statementAmountLine(day(6, 1), "gst", "bk2", "2026-Q2", "", -9000),
+		{Date: day(6, 15), Type: "clawback", BookingID: "bk1", Debit: 20000},
+		{Date: day(7, 2), Type: "payout", Debit: 1000},
+	})
+
+	assert.Equal(t, 118000.0, st.Opening)
+	assert.Len(t, st.Lines, 7)
+	assert.Equal(t, 0.0, st.Commission)
+	assert.Equal(t, 0.0, st.GSTAmount)
+	assert.Equal(t, 5000.0, st.TDSAmount)
+	assert.Equal(t, 113000.0, st.Paid)
+	assert.Equal(t, 20000.0, st.ClawbackAmount)
+	assert.Equal(t, -20000.0, st.Closing) // clawback still to recover from the next payout
+	assert.Equal(t, 59000.0, st.Lines[3].Balance)
+}
@@ -2,7 +2,12 @@ package services
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // BrokerService provides broker management functionality
@@ -65,9 +70,163 @@ func (s *BrokerService) UpdateBookingLinkStatus(tenantID, brokerID, linkID int64
 	return &models.BrokerBookingLink{ID: linkID, CommissionStatus: status}, nil
 }
 
-// CreatePayout creates a commission payout
+// BookingBrokerage returns the commission approved or paid on a booking's broker links
+func (s *BrokerService) BookingBrokerage(tenantID, bookingID string) (float64, error) {
+	var total float64
+	err := s.DB.QueryRow(`SELECT COALESCE(SUM(commission_amount), 0) FROM broker_booking_link
+		WHERE tenant_id = ? AND booking_id = ? AND commission_status IN ('approved', 'paid') AND deleted_at IS NULL`,
+		tenantID, bookingID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get booking brokerage: %w", err)
+	}
+	return total, nil
+}
+
+// CancelBookingCommission marks a cancelled booking's broker links cancelled. Unless
+// the customer bears the brokerage, unpaid commission is cancelled and paid commission
+// is clawed back against the broker's next payout. Quarters the booking's commission
+// accrued in are re-rated to their new slab. Links already cancelled are left alone.
+func (s *BrokerService) CancelBookingCommission(tenantID, bookingID, userID string, customerBearsBrokerage bool) ([]models.BrokerCommissionClawback, error) {
+	rows, err := s.DB.Query(`SELECT l.id, l.broker_id, COALESCE(l.commission_amount, 0), l.commission_status,
+		EXISTS (SELECT 1 FROM broker_commission_accruals a WHERE a.tenant_id = l.tenant_id AND a.link_id = l.id)
+		FROM broker_booking_link l WHERE l.tenant_id = ? AND l.booking_id = ? AND COALESCE(l.booking_status, '') <> 'cancelled'
+		AND l.deleted_at IS NULL`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking links: %w", err)
+	}
+	type link struct {
+		id, brokerID, status string
+		commission           float64
+		accrued              bool // commission comes from the commission engine
+	}
+	links := []link{}
+	for rows.Next() {
+		var l link
+		if err := rows.Scan(&l.id, &l.brokerID, &l.commission, &l.status, &l.accrued); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan booking link: %w", err)
+		}
+		links = append(links, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	clawbacks := []models.BrokerCommissionClawback{}
+	quarters := []commissionQuarterKey{}
+	for _, l := range links {
+		status := l.status
+		if !customerBearsBrokerage && l.accrued {
+			// Engine accruals can be part paid, so only the paid commission is clawed back
+			paid, affected, err := cancelLinkAccruals(tx, tenantID, l.id, now)
+			if err != nil {
+				return nil, err
+			}
+			quarters = append(quarters, affected...)
+			l.commission, l.status, status = paid, "paid", "cancelled"
+			if paid <= 0 {
+				l.status = "approved"
+			}
+		}
+		if !customerBearsBrokerage {
+			switch l.status {
+			case "paid":
+				status = "clawed_back"
+				if l.commission > 0 {
+					c := models.BrokerCommissionClawback{
+						ID:        uuid.New().String(),
+						TenantID:  tenantID,
+						BrokerID:  l.brokerID,
+						BookingID: bookingID,
+						LinkID:    l.id,
+						Amount:    l.commission,
+						Status:    "pending",
+						CreatedAt: now,
+					}
+					if _, err := tx.Exec(`INSERT INTO broker_commission_clawbacks
+						(id, tenant_id, broker_id, booking_id, link_id, amount, status, created_at)
+						VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+						c.ID, c.TenantID, c.BrokerID, c.BookingID, c.LinkID, c.Amount, c.Status, c.CreatedAt); err != nil {
+						return nil, fmt.Errorf("failed to record commission clawback: %w", err)
+					}
+					clawbacks = append(clawbacks, c)
+				}
+			case "pending", "approved":
+				status = "cancelled"
+			}
+		}
+		if _, err := tx.Exec(`UPDATE broker_booking_link SET booking_status = 'cancelled', commission_status = ?,
+			updated_by = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`, status, userID, now, l.id, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to cancel booking link: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit commission cancellation: %w", err)
+	}
+	// The broker's quarter lost volume, so the slab may drop for the bookings left in it
+	if err := s.rebalanceQuarters(tenantID, quarters); err != nil {
+		return clawbacks, fmt.Errorf("commission cancelled but %w", err)
+	}
+	return clawbacks, nil
+}
+
+// ListClawbacks lists commission clawbacks, for one broker when brokerID is set
+func (s *BrokerService) ListClawbacks(tenantID, brokerID, status string) ([]models.BrokerCommissionClawback, error) {
+	query := `SELECT id, tenant_id, broker_id, booking_id, link_id, amount, status, payout_reference_no,
+		adjusted_at, created_at FROM broker_commission_clawbacks WHERE tenant_id = ?`
+	args := []interface{}{tenantID}
+	if brokerID != "" {
+		query += " AND broker_id = ?"
+		args = append(args, brokerID)
+	}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	rows, err := s.DB.Query(query+" ORDER BY created_at", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clawbacks: %w", err)
+	}
+	defer rows.Close()
+
+	clawbacks := []models.BrokerCommissionClawback{}
+	for rows.Next() {
+		var c models.BrokerCommissionClawback
+		var payoutRef sql.NullString
+		var adjustedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.BrokerID, &c.BookingID, &c.LinkID, &c.Amount, &c.Status,
+			&payoutRef, &adjustedAt, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan clawback: %w", err)
+		}
+		if payoutRef.Valid {
+			c.PayoutReferenceNo = &payoutRef.String
+		}
+		if adjustedAt.Valid {
+			c.AdjustedAt = &adjustedAt.Time
+		}
+		clawbacks = append(clawbacks, c)
+	}
+	return clawbacks, rows.Err()
+}
+
+// ErrPayoutFromAccruals is returned for a payout keyed in by hand; payouts are
+// generated from commission accruals so TDS and clawbacks are netted off
+var ErrPayoutFromAccruals = errors.New("broker payouts are generated from commission accruals, use POST /api/v1/broker-commission/payouts")
+
+// CreatePayout refuses a payout keyed in by hand. TDS under section 194H is deducted
+// and pending clawbacks are netted off only when a payout is generated from
+// commission accruals, see GenerateBrokerPayout.
 func (s *BrokerService) CreatePayout(tenantID int64, req *models.CreatePayoutRequest, userID *int64) (*models.BrokerCommissionPayout, error) {
-	return &models.BrokerCommissionPayout{TenantID: tenantID, BrokerID: req.BrokerID, Status: "pending"}, nil
+	return nil, ErrPayoutFromAccruals
 }
 
 // ListPayouts lists payouts
@@ -94,3 +253,33 @@ func (s *BrokerService) GetTopPerformingBrokers(tenantID int64, limit int) ([]mo
 func (s *BrokerService) GetCommissionDueReport(tenantID int64, offset, limit int) ([]map[string]interface{}, int64, error) {
 	return []map[string]interface{}{}, 0, nil
 }
+
+// adjustClawbacks nets a broker's pending clawbacks, oldest first, against a payout
+// while they fit within the amount payable and returns the total adjusted. It runs
+// in the transaction that persists the payout; a clawback already adjusted by a
+// concurrent payout is skipped.
+func (s *BrokerService) adjustClawbacks(tx *sql.Tx, tenantID, brokerID, payoutReference string, payable float64) (float64, error) {
+	pending, err := s.ListClawbacks(tenantID, brokerID, "pending")
+	if err != nil {
+		return 0, err
+	}
+
+	adjusted := 0.0
+	now := time.Now()
+	for _, c := range pending {
+		if adjusted+c.Amount > payable {
+			break
+		}
+		res, err := tx.Exec(`UPDATE broker_commission_clawbacks SET status = 'adjusted', payout_reference_no = ?,
+			adjusted_at = ? WHERE id = ? AND tenant_id = ? AND status = 'pending'`,
+			payoutReference, now, c.ID, tenantID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to adjust clawback: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		adjusted += c.Amount
+	}
+	return roundTo2(adjusted), nil
+}
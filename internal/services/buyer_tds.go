@@ -0,0 +1,621 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// BUYER TDS (FORM 26QB)
+// ============================================================================
+// Buyers of units above ₹50 lakh deduct 1% u/s 194-IA and deposit it through
+// Form 26QB. The deduction is recorded against the booking payment and credited
+// to the customer ledger as a TDS receivable, so the booking does not look
+// short-paid, and is verified later against the 26AS / AIS statement.
+
+// form26ASDateLayout is the date format of the 26AS text download
+const form26ASDateLayout = "02-Jan-2006"
+
+// RecordBuyerTDS records TDS a buyer deducted from a booking payment and credits
+// it to the customer ledger
+func (s *TDSService) RecordBuyerTDS(tenantID, userID, bookingID string, req *models.RecordBuyerTDSRequest) (*models.BuyerTDSCredit, error) {
+	if req.TDSAmount <= 0 {
+		return nil, fmt.Errorf("tds_amount must be positive")
+	}
+
+	c := &models.BuyerTDSCredit{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		PaymentID: req.PaymentID,
+		TDSAmount: roundTo2(req.TDSAmount),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	var paymentDate time.Time
+	var status string
+	err := s.DB.QueryRow(`SELECT p.booking_id, p.amount, p.payment_date, p.status, COALESCE(b.customer_id, ''),
+		COALESCE(cd.primary_name, ''), COALESCE(cd.primary_pan_no, ''), COALESCE(ucs.grand_total, 0)
+		FROM booking_payments p
+		JOIN customer_bookings b ON b.id = p.booking_id AND b.tenant_id = p.tenant_id
+		LEFT JOIN customer_details cd ON cd.booking_id = b.id AND cd.deleted_at IS NULL
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = b.unit_id AND ucs.tenant_id = b.tenant_id
+		WHERE p.id = ? AND p.tenant_id = ? AND p.deleted_at IS NULL`, req.PaymentID, tenantID).Scan(
+		&c.BookingID, &c.AmountReceived, &paymentDate, &status, &c.CustomerID,
+		&c.BuyerName, &c.BuyerPAN, &c.TotalConsideration)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking payment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking payment: %w", err)
+	}
+	if bookingID != "" && bookingID != c.BookingID {
+		return nil, fmt.Errorf("payment does not belong to booking %s", bookingID)
+	}
+	if status == "bounced" || status == "cancelled" {
+		return nil, fmt.Errorf("cannot record tds on a %s payment", status)
+	}
+
+	var existing int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM booking_payment_tds WHERE tenant_id = ? AND payment_id = ?`,
+		tenantID, req.PaymentID).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to check buyer tds: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("tds already recorded for payment %s", req.PaymentID)
+	}
+
+	if req.BuyerPAN != "" {
+		c.BuyerPAN = strings.ToUpper(strings.TrimSpace(req.BuyerPAN))
+	}
+	if c.BuyerPAN != "" && !panPattern.MatchString(c.BuyerPAN) {
+		return nil, fmt.Errorf("invalid buyer PAN %s", c.BuyerPAN)
+	}
+	if req.TotalConsideration > 0 {
+		c.TotalConsideration = req.TotalConsideration
+	}
+
+	c.DeductionDate = paymentDate
+	if req.DeductionDate != "" {
+		if c.DeductionDate, err = time.Parse("2006-01-02", req.DeductionDate); err != nil {
+			return nil, fmt.Errorf("invalid deduction_date: %w", err)
+		}
+	}
+	c.FinancialYear = FinancialYearOf(c.DeductionDate)
+	c.CertificateDueDate = form16BDueDate(c.DeductionDate)
+
+	c.GrossAmount = roundTo2(c.AmountReceived + c.TDSAmount)
+	c.TDSRate = math.Round(c.TDSAmount/c.GrossAmount*100000) / 1000
+	c.ExpectedTDS = expectedBuyerTDS(c.TotalConsideration, c.GrossAmount)
+
+	if req.AcknowledgementNumber != "" {
+		if err := applyBuyerTDSChallan(c, &models.UpdateBuyerTDSChallanRequest{
+			AcknowledgementNumber: req.AcknowledgementNumber,
+			ChallanSerialNo:       req.ChallanSerialNo,
+			BSRCode:               req.BSRCode,
+			ChallanDate:           req.ChallanDate,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	c.Status = buyerTDSDocumentStatus(c)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reference := c.AcknowledgementNumber
+	if reference == "" {
+		reference = "TDS-" + c.PaymentID
+	}
+	c.LedgerEntryID, err = insertCustomerLedgerEntry(tx, tenantID, c.BookingID, c.CustomerID, "credit",
+		fmt.Sprintf("TDS receivable u/s 194-IA deducted by buyer (%s)", c.BuyerName), c.TDSAmount, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO booking_payment_tds (
+		id, tenant_id, booking_id, payment_id, customer_id, buyer_name, buyer_pan, financial_year,
+		total_consideration, amount_received, gross_amount, tds_rate, tds_amount, expected_tds, deduction_date,
+		acknowledgement_number, challan_serial_no, bsr_code, challan_date, certificate_due_date, status,
+		ledger_entry_id, created_by, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID, c.TenantID, c.BookingID, c.PaymentID, nullIfEmpty(c.CustomerID), c.BuyerName, nullIfEmpty(c.BuyerPAN),
+		c.FinancialYear, c.TotalConsideration, c.AmountReceived, c.GrossAmount, c.TDSRate, c.TDSAmount, c.ExpectedTDS,
+		c.DeductionDate, nullIfEmpty(c.AcknowledgementNumber), nullIfEmpty(c.ChallanSerialNo), nullIfEmpty(c.BSRCode),
+		c.ChallanDate, c.CertificateDueDate, c.Status, c.LedgerEntryID, c.CreatedBy, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record buyer tds: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit buyer tds: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateBuyerTDSChallan captures the 26QB acknowledgement and challan for a deduction
+func (s *TDSService) UpdateBuyerTDSChallan(tenantID, bookingID, creditID string, req *models.UpdateBuyerTDSChallanRequest) (*models.BuyerTDSCredit, error) {
+	c, err := s.GetBuyerTDSCredit(tenantID, bookingID, creditID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == models.BuyerTDSStatusVerified {
+		return nil, fmt.Errorf("tds already verified against 26AS")
+	}
+	if err := applyBuyerTDSChallan(c, req); err != nil {
+		return nil, err
+	}
+	c.Status = buyerTDSDocumentStatus(c)
+	c.UpdatedAt = time.Now()
+
+	if _, err := s.DB.Exec(`UPDATE booking_payment_tds SET acknowledgement_number = ?, challan_serial_no = ?,
+		bsr_code = ?, challan_date = ?, status = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		c.AcknowledgementNumber, nullIfEmpty(c.ChallanSerialNo), nullIfEmpty(c.BSRCode), c.ChallanDate,
+		c.Status, c.UpdatedAt, c.ID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update buyer tds challan: %w", err)
+	}
+	return c, nil
+}
+
+// UploadForm16B attaches the buyer's Form 16B certificate to a deduction
+func (s *TDSService) UploadForm16B(tenantID, bookingID, creditID string, req *models.UploadForm16BRequest) (*models.BuyerTDSCredit, error) {
+	if req.CertificateNumber == "" || req.FileURL == "" {
+		return nil, fmt.Errorf("certificate_number and file_url are required")
+	}
+	c, err := s.GetBuyerTDSCredit(tenantID, bookingID, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	c.CertificateNumber = strings.TrimSpace(req.CertificateNumber)
+	c.CertificateFileURL = req.FileURL
+	c.CertificateUploadedAt = &now
+	if c.Status != models.BuyerTDSStatusVerified && c.Status != models.BuyerTDSStatusMismatch {
+		c.Status = buyerTDSDocumentStatus(c)
+	}
+	c.UpdatedAt = now
+
+	if _, err := s.DB.Exec(`UPDATE booking_payment_tds SET certificate_number = ?, certificate_file_url = ?,
+		certificate_uploaded_at = ?, status = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		c.CertificateNumber, c.CertificateFileURL, c.CertificateUploadedAt, c.Status, c.UpdatedAt,
+		c.ID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to save form 16b: %w", err)
+	}
+	return c, nil
+}
+
+// GetBuyerTDSCredit returns a deduction, optionally scoped to a booking
+func (s *TDSService) GetBuyerTDSCredit(tenantID, bookingID, creditID string) (*models.BuyerTDSCredit, error) {
+	credits, err := s.getBuyerTDSCredits(tenantID, "id = ?", creditID)
+	if err != nil {
+		return nil, err
+	}
+	if len(credits) == 0 || (bookingID != "" && credits[0].BookingID != bookingID) {
+		return nil, fmt.Errorf("tds deduction not found")
+	}
+	return &credits[0], nil
+}
+
+// ListBuyerTDSCredits lists buyer deductions filtered by booking, status and financial year
+func (s *TDSService) ListBuyerTDSCredits(tenantID, bookingID, status, fy string) ([]models.BuyerTDSCredit, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	if fy != "" {
+		where += " AND financial_year = ?"
+		args = append(args, fy)
+	}
+	return s.getBuyerTDSCredits(tenantID, where, args...)
+}
+
+// ============================================================================
+// 26AS / AIS RECONCILIATION
+// ============================================================================
+
+// Import26AS stores the year's 194-IA credits from a 26AS or AIS download,
+// replacing whatever was imported for that year before
+func (s *TDSService) Import26AS(tenantID, userID string, req *models.Import26ASRequest) (*models.Form26ASImport, error) {
+	if _, _, err := ParseTDSQuarter(req.FinancialYear, "Q1"); err != nil {
+		return nil, err
+	}
+	source := strings.ToUpper(req.Source)
+	if source == "" {
+		source = models.TaxStatementSource26AS
+	}
+	if source != models.TaxStatementSource26AS && source != models.TaxStatementSourceAIS {
+		return nil, fmt.Errorf("unsupported statement source %s", req.Source)
+	}
+
+	imp := &models.Form26ASImport{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		FinancialYear: req.FinancialYear,
+		Source:        source,
+		ImportedBy:    userID,
+		CreatedAt:     time.Now(),
+	}
+
+	entries := []models.Form26ASEntry{}
+	for i, in := range req.Entries {
+		section := normalizeTDSSection(in.Section)
+		if section != "" && section != models.TDSSection194IA {
+			continue
+		}
+		date, err := parseForm26ASDate(in.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid transaction_date %q", i+1, in.TransactionDate)
+		}
+		entries = append(entries, models.Form26ASEntry{
+			ID:                    uuid.New().String(),
+			TenantID:              tenantID,
+			ImportID:              imp.ID,
+			FinancialYear:         req.FinancialYear,
+			DeductorName:          in.DeductorName,
+			DeductorPAN:           strings.ToUpper(strings.TrimSpace(in.DeductorPAN)),
+			Section:               models.TDSSection194IA,
+			TransactionDate:       date,
+			AmountPaid:            in.AmountPaid,
+			TDSDeposited:          in.TDSDeposited,
+			AcknowledgementNumber: strings.TrimSpace(in.AcknowledgementNumber),
+			BookingStatus:         strings.ToUpper(in.BookingStatus),
+		})
+		imp.TotalTDS += in.TDSDeposited
+	}
+	imp.EntryCount = len(entries)
+	imp.TotalTDS = roundTo2(imp.TotalTDS)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM form26as_entries WHERE tenant_id = ? AND financial_year = ?`,
+		tenantID, req.FinancialYear); err != nil {
+		return nil, fmt.Errorf("failed to clear previous 26AS entries: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO form26as_imports
+		(id, tenant_id, financial_year, source, entry_count, total_tds, imported_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		imp.ID, imp.TenantID, imp.FinancialYear, imp.Source, imp.EntryCount, imp.TotalTDS,
+		imp.ImportedBy, imp.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to save 26AS import: %w", err)
+	}
+	for _, e := range entries {
+		if _, err := tx.Exec(`INSERT INTO form26as_entries
+			(id, tenant_id, import_id, financial_year, deductor_name, deductor_pan, section_code, transaction_date,
+			 amount_paid, tds_deposited, acknowledgement_number, booking_status)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.ID, e.TenantID, e.ImportID, e.FinancialYear, e.DeductorName, e.DeductorPAN, e.Section,
+			e.TransactionDate, e.AmountPaid, e.TDSDeposited, nullIfEmpty(e.AcknowledgementNumber),
+			nullIfEmpty(e.BookingStatus)); err != nil {
+			return nil, fmt.Errorf("failed to save 26AS entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit 26AS import: %w", err)
+	}
+	return imp, nil
+}
+
+// Reconcile26AS matches the year's buyer deductions against the imported 26AS /
+// AIS entries and marks each deduction verified or mismatched
+func (s *TDSService) Reconcile26AS(tenantID, fy string) (*models.Form26ASReconciliationResult, error) {
+	credits, err := s.getBuyerTDSCredits(tenantID, "financial_year = ?", fy)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.getForm26ASEntries(tenantID, fy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := reconcileBuyerTDS(fy, credits, entries)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE form26as_entries SET matched_credit_id = NULL WHERE tenant_id = ? AND financial_year = ?`,
+		tenantID, fy); err != nil {
+		return nil, fmt.Errorf("failed to reset 26AS matches: %w", err)
+	}
+	byID := map[string]*models.BuyerTDSCredit{}
+	for i := range credits {
+		byID[credits[i].ID] = &credits[i]
+	}
+	for _, line := range result.Lines {
+		if line.CreditID == "" {
+			continue
+		}
+		status := buyerTDSDocumentStatus(byID[line.CreditID])
+		switch line.Status {
+		case models.Form26ASReconMatched:
+			status = models.BuyerTDSStatusVerified
+		case models.Form26ASReconAmountMismatch:
+			status = models.BuyerTDSStatusMismatch
+		}
+		if _, err := tx.Exec(`UPDATE booking_payment_tds SET status = ?, form26as_entry_id = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, status, nullIfEmpty(line.Form26ASEntryID), time.Now(),
+			line.CreditID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update buyer tds status: %w", err)
+		}
+		if line.Form26ASEntryID != "" {
+			if _, err := tx.Exec(`UPDATE form26as_entries SET matched_credit_id = ? WHERE id = ? AND tenant_id = ?`,
+				line.CreditID, line.Form26ASEntryID, tenantID); err != nil {
+				return nil, fmt.Errorf("failed to update 26AS entry: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit 26AS reconciliation: %w", err)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+const buyerTDSColumns = `id, tenant_id, booking_id, payment_id, COALESCE(customer_id, ''), COALESCE(buyer_name, ''),
+	COALESCE(buyer_pan, ''), financial_year, total_consideration, amount_received, gross_amount, tds_rate, tds_amount,
+	expected_tds, deduction_date, COALESCE(acknowledgement_number, ''), COALESCE(challan_serial_no, ''),
+	COALESCE(bsr_code, ''), challan_date, COALESCE(certificate_number, ''), COALESCE(certificate_file_url, ''),
+	certificate_uploaded_at, certificate_due_date, status, COALESCE(ledger_entry_id, ''), form26as_entry_id,
+	COALESCE(created_by, ''), created_at, updated_at`
+
+func (s *TDSService) getBuyerTDSCredits(tenantID, where string, args ...interface{}) ([]models.BuyerTDSCredit, error) {
+	rows, err := s.DB.Query(`SELECT `+buyerTDSColumns+` FROM booking_payment_tds WHERE tenant_id = ? AND `+where+`
+		ORDER BY deduction_date, created_at`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch buyer tds: %w", err)
+	}
+	defer rows.Close()
+
+	credits := []models.BuyerTDSCredit{}
+	for rows.Next() {
+		var c models.BuyerTDSCredit
+		var challanDate, uploadedAt sql.NullTime
+		var entryID sql.NullString
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.BookingID, &c.PaymentID, &c.CustomerID, &c.BuyerName,
+			&c.BuyerPAN, &c.FinancialYear, &c.TotalConsideration, &c.AmountReceived, &c.GrossAmount, &c.TDSRate,
+			&c.TDSAmount, &c.ExpectedTDS, &c.DeductionDate, &c.AcknowledgementNumber, &c.ChallanSerialNo,
+			&c.BSRCode, &challanDate, &c.CertificateNumber, &c.CertificateFileURL, &uploadedAt,
+			&c.CertificateDueDate, &c.Status, &c.LedgerEntryID, &entryID, &c.CreatedBy, &c.CreatedAt,
+			&c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan buyer tds: %w", err)
+		}
+		if challanDate.Valid {
+			c.ChallanDate = &challanDate.Time
+		}
+		if uploadedAt.Valid {
+			c.CertificateUploadedAt = &uploadedAt.Time
+		}
+		if entryID.Valid {
+			c.Form26ASEntryID = &entryID.String
+		}
+		credits = append(credits, c)
+	}
+	return credits, rows.Err()
+}
+
+func (s *TDSService) getForm26ASEntries(tenantID, fy string) ([]models.Form26ASEntry, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, import_id, financial_year, COALESCE(deductor_name, ''),
+		COALESCE(deductor_pan, ''), section_code, transaction_date, amount_paid, tds_deposited,
+		COALESCE(acknowledgement_number, ''), COALESCE(booking_status, ''), matched_credit_id
+		FROM form26as_entries WHERE tenant_id = ? AND financial_year = ?
+		ORDER BY transaction_date`, tenantID, fy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch 26AS entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.Form26ASEntry{}
+	for rows.Next() {
+		var e models.Form26ASEntry
+		var matched sql.NullString
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.ImportID, &e.FinancialYear, &e.DeductorName, &e.DeductorPAN,
+			&e.Section, &e.TransactionDate, &e.AmountPaid, &e.TDSDeposited, &e.AcknowledgementNumber,
+			&e.BookingStatus, &matched); err != nil {
+			return nil, fmt.Errorf("failed to scan 26AS entry: %w", err)
+		}
+		if matched.Valid {
+			e.MatchedCreditID = &matched.String
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// applyBuyerTDSChallan copies the 26QB acknowledgement and challan details onto a deduction
+func applyBuyerTDSChallan(c *models.BuyerTDSCredit, req *models.UpdateBuyerTDSChallanRequest) error {
+	ack := strings.ToUpper(strings.TrimSpace(req.AcknowledgementNumber))
+	if ack == "" {
+		return fmt.Errorf("acknowledgement_number is required")
+	}
+	c.AcknowledgementNumber = ack
+	c.ChallanSerialNo = strings.TrimSpace(req.ChallanSerialNo)
+	c.BSRCode = strings.TrimSpace(req.BSRCode)
+	if req.ChallanDate != "" {
+		date, err := time.Parse("2006-01-02", req.ChallanDate)
+		if err != nil {
+			return fmt.Errorf("invalid challan_date: %w", err)
+		}
+		c.ChallanDate = &date
+	}
+	return nil
+}
+
+// buyerTDSDocumentStatus is a deduction's status from the documents captured so far
+func buyerTDSDocumentStatus(c *models.BuyerTDSCredit) string {
+	switch {
+	case c.CertificateNumber != "":
+		return models.BuyerTDSStatusCertificateReceived
+	case c.AcknowledgementNumber != "":
+		return models.BuyerTDSStatusChallanFiled
+	default:
+		return models.BuyerTDSStatusDeducted
+	}
+}
+
+// expectedBuyerTDS is 1% of the instalment's gross value once the consideration reaches ₹50 lakh
+func expectedBuyerTDS(consideration, gross float64) float64 {
+	rule := tdsSectionRules[models.TDSSection194IA]
+	if consideration < rule.ConsiderationThreshold {
+		return 0
+	}
+	return roundTo2(gross * rule.RateOthers / 100)
+}
+
+// form16BDueDate is when the buyer must issue Form 16B: 15 days after 26QB falls
+// due, which is 30 days from the end of the month of deduction
+func form16BDueDate(deducted time.Time) time.Time {
+	monthEnd := time.Date(deducted.Year(), deducted.Month()+1, 0, 0, 0, 0, 0, deducted.Location())
+	return monthEnd.AddDate(0, 0, 45)
+}
+
+func parseForm26ASDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.Parse("2006-01-02", s); err == nil {
+		return d, nil
+	}
+	return time.Parse(form26ASDateLayout, s)
+}
+
+// reconcileBuyerTDS pairs buyer deductions with 26AS entries: first by 26QB
+// acknowledgement number, then by buyer PAN and TDS amount within ₹1. A pair
+// found by acknowledgement but with a different amount is an amount mismatch.
+func reconcileBuyerTDS(fy string, credits []models.BuyerTDSCredit, entries []models.Form26ASEntry) *models.Form26ASReconciliationResult {
+	result := &models.Form26ASReconciliationResult{FinancialYear: fy, Lines: []models.Form26ASReconciliationLine{}}
+	used := make([]bool, len(entries))
+	matchedEntry := make([]int, len(credits))
+	for i := range matchedEntry {
+		matchedEntry[i] = -1
+	}
+
+	for i, c := range credits {
+		if c.AcknowledgementNumber == "" {
+			continue
+		}
+		for j, e := range entries {
+			if !used[j] && strings.EqualFold(e.AcknowledgementNumber, c.AcknowledgementNumber) {
+				used[j], matchedEntry[i] = true, j
+				break
+			}
+		}
+	}
+
+	for i, c := range credits {
+		if matchedEntry[i] >= 0 || c.BuyerPAN == "" {
+			continue
+		}
+		best, bestGap := -1, 0.0
+		for j, e := range entries {
+			if used[j] || e.DeductorPAN != c.BuyerPAN || math.Abs(e.TDSDeposited-c.TDSAmount) > 1 {
+				continue
+			}
+			gap := math.Abs(e.TransactionDate.Sub(c.DeductionDate).Hours())
+			if best < 0 || gap < bestGap {
+				best, bestGap = j, gap
+			}
+		}
+		if best >= 0 {
+			used[best], matchedEntry[i] = true, best
+		}
+	}
+
+	for i, c := range credits {
+		line := models.Form26ASReconciliationLine{
+			CreditID:              c.ID,
+			BookingID:             c.BookingID,
+			BuyerName:             c.BuyerName,
+			BuyerPAN:              c.BuyerPAN,
+			AcknowledgementNumber: c.AcknowledgementNumber,
+			BooksTDS:              c.TDSAmount,
+		}
+		result.Summary.BooksTDS += c.TDSAmount
+		if j := matchedEntry[i]; j >= 0 {
+			e := entries[j]
+			line.Form26ASEntryID = e.ID
+			line.Form26ASTDS = e.TDSDeposited
+			line.Difference = roundTo2(c.TDSAmount - e.TDSDeposited)
+			if math.Abs(line.Difference) > 1 {
+				line.Status = models.Form26ASReconAmountMismatch
+				result.Summary.AmountMismatchCount++
+				result.Summary.UnverifiedTDS += math.Max(line.Difference, 0)
+			} else {
+				line.Status = models.Form26ASReconMatched
+				result.Summary.MatchedCount++
+			}
+		} else {
+			line.Status = models.Form26ASReconNotIn26AS
+			line.Difference = c.TDSAmount
+			result.Summary.NotIn26ASCount++
+			result.Summary.UnverifiedTDS += c.TDSAmount
+		}
+		result.Lines = append(result.Lines, line)
+	}
+
+	for j, e := range entries {
+		result.Summary.Form26ASTDS += e.TDSDeposited
+		if used[j] {
+			continue
+		}
+		result.Lines = append(result.Lines, models.Form26ASReconciliationLine{
+			Status:                models.Form26ASReconNotInBooks,
+			BuyerName:             e.DeductorName,
+			BuyerPAN:              e.DeductorPAN,
+			AcknowledgementNumber: e.AcknowledgementNumber,
+			Form26ASEntryID:       e.ID,
+			Form26ASTDS:           e.TDSDeposited,
+			Difference:            -e.TDSDeposited,
+		})
+		result.Summary.NotInBooksCount++
+	}
+
+	sort.SliceStable(result.Lines, func(a, b int) bool {
+		return reconStatusOrder(result.Lines[a].Status) < reconStatusOrder(result.Lines[b].Status)
+	})
+	result.Summary.BooksTDS = roundTo2(result.Summary.BooksTDS)
+	result.Summary.Form26ASTDS = roundTo2(result.Summary.Form26ASTDS)
+	result.Summary.UnverifiedTDS = roundTo2(result.Summary.UnverifiedTDS)
+	return result
+}
+
+// reconStatusOrder lists exceptions ahead of matched lines
+func reconStatusOrder(status string) int {
+	switch status {
+	case models.Form26ASReconAmountMismatch:
+		return 0
+	case models.Form26ASReconNotIn26AS:
+		return 1
+	case models.Form26ASReconNotInBooks:
+		return 2
+	default:
+		return 3
+	}
+}
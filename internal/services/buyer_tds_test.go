@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReconcileBuyerTDS tests acknowledgement and PAN matching against 26AS
+func TestReconcileBuyerTDS(t *testing.T) {
+	date := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	credits := []models.BuyerTDSCredit{
+		{ID: "c1", BuyerPAN: "ABCPK1234L", AcknowledgementNumber: "AB1234567", TDSAmount: 10000, DeductionDate: date(5, 10)},
+		{ID: "c2", BuyerPAN: "XYZPM9876Q", TDSAmount: 7500, DeductionDate: date(6, 2)},
+		{ID: "c3", BuyerPAN: "LMNPR4567K", AcknowledgementNumber: "AB7654321", TDSAmount: 12000, DeductionDate: date(7, 1)},
+		{ID: "c4", BuyerPAN: "QRSPT1111Z", TDSAmount: 5000, DeductionDate: date(8, 1)},
+	}
+	entries := []models.Form26ASEntry{
+		{ID: "e1", DeductorPAN: "ABCPK1234L", AcknowledgementNumber: "ab1234567", TDSDeposited: 10000, TransactionDate: date(5, 10)},
+		{ID: "e2", DeductorPAN: "XYZPM9876Q", TDSDeposited: 7500.4, TransactionDate: date(6, 3)},
+		{ID: "e3", DeductorPAN: "LMNPR4567K", AcknowledgementNumber: "AB7654321", TDSDeposited: 10000, TransactionDate: date(7, 1)},
+		{ID: "e4", DeductorPAN: "DEFPA2222B", TDSDeposited: 8000, TransactionDate: date(9, 1)},
+	}
+
+	result := reconcileBuyerTDS("2025-26", credits, entries)
+	assert.Equal(t, 2, result.Summary.MatchedCount)
+	assert.Equal(t, 1, result.Summary.AmountMismatchCount)
+	assert.Equal(t, 1, result.Summary.NotIn26ASCount)
+	assert.Equal(t, 1, result.Summary.NotInBooksCount)
+	assert.Equal(t, 34500.0, result.Summary.BooksTDS)
+	assert.Equal(t, 35500.4, result.Summary.Form26ASTDS)
+	// 2,000 short on c3 plus all of c4
+	assert.Equal(t, 7000.0, result.Summary.UnverifiedTDS)
+
+	// Exceptions are listed first
+	assert.Equal(t, models.Form26ASReconAmountMismatch, result.Lines[0].Status)
+	assert.Equal(t, "c3", result.Lines[0].CreditID)
+	assert.Equal(t, 2000.0, result.Lines[0].Difference)
+	assert.Equal(t, models.Form26ASReconNotIn26AS, result.Lines[1].Status)
+	assert.Equal(t, models.Form26ASReconNotInBooks, result.Lines[2].Status)
+	assert.Equal(t, "e4", result.Lines[2].Form26ASEntryID)
+}
+
+// TestBuyerTDSHelpers tests the expected deduction, Form 16B due date and document status
+func TestBuyerTDSHelpers(t *testing.T) {
+	assert.Equal(t, 0.0, expectedBuyerTDS(4500000, 1000000))
+	assert.Equal(t, 10000.0, expectedBuyerTDS(7500000, 1000000))
+
+	// Deducted in May: 26QB due 30 June, Form 16B due 15 July
+	assert.Equal(t, time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+		form16BDueDate(time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)))
+	// Deducted in February: 26QB due 30 March, Form 16B due 14 April
+	assert.Equal(t, time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC),
+		form16BDueDate(time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)))
+
+	c := &models.BuyerTDSCredit{}
+	assert.Equal(t, models.BuyerTDSStatusDeducted, buyerTDSDocumentStatus(c))
+	assert.Error(t, applyBuyerTDSChallan(c, &models.UpdateBuyerTDSChallanRequest{}))
+	assert.NoError(t, applyBuyerTDSChallan(c, &models.UpdateBuyerTDSChallanRequest{AcknowledgementNumber: " ab123 ", ChallanDate: "2025-05-20"}))
+	assert.Equal(t, "AB123", c.AcknowledgementNumber)
+	assert.Equal(t, models.BuyerTDSStatusChallanFiled, buyerTDSDocumentStatus(c))
+	c.CertificateNumber = "16B-001"
+	assert.Equal(t, models.BuyerTDSStatusCertificateReceived, buyerTDSDocumentStatus(c))
+
+	d, err := parseForm26ASDate("10-May-2025")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), d)
+	_, err = parseForm26ASDate("10/05/2025")
+	assert.Error(t, err)
+}
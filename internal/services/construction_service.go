@@ -1,20 +1,25 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
+
+	"vyomtech-backend/internal/models"
 )
 
 // ConstructionService provides construction management functionality
 type ConstructionService struct {
-	DB *sql.DB
+	DB           *sql.DB
+	PaymentPlans *PaymentPlanService
 }
 
 // NewConstructionService creates a new construction service instance
-func NewConstructionService(db *sql.DB) *ConstructionService {
+func NewConstructionService(db *sql.DB, paymentPlans *PaymentPlanService) *ConstructionService {
 	return &ConstructionService{
-		DB: db,
+		DB:           db,
+		PaymentPlans: paymentPlans,
 	}
 }
 
@@ -109,3 +114,69 @@ func (s *ConstructionService) GetDashboardMetrics(tenantID string) (*Constructio
 
 	return metrics, nil
 }
+
+// LogProgress records a site progress entry. An entry that reports a tower milestone
+// 100% complete marks the milestone complete, which raises its demand letters.
+func (s *ConstructionService) LogProgress(ctx context.Context, tenantID, userID string, req *models.LogProgressRequest) (*models.ProgressLogResult, error) {
+	if req.ProjectID == "" {
+		return nil, fmt.Errorf("project_id is required")
+	}
+	if req.PercentComplete < 0 || req.PercentComplete > 100 {
+		return nil, fmt.Errorf("percent_complete must be between 0 and 100")
+	}
+	date := time.Now()
+	if req.Date != "" {
+		d, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+		date = d
+	}
+
+	completesMilestone := req.TowerMilestoneID != "" && req.PercentComplete == 100
+	if completesMilestone {
+		milestones, err := s.PaymentPlans.getTowerMilestones(tenantID, "id = ?", req.TowerMilestoneID)
+		if err != nil {
+			return nil, err
+		}
+		if len(milestones) == 0 {
+			return nil, fmt.Errorf("milestone not found")
+		}
+		if milestones[0].Status == models.MilestoneStatusCompleted {
+			return nil, fmt.Errorf("milestone already completed")
+		}
+	}
+
+	res, err := s.DB.ExecContext(ctx, `INSERT INTO progress_tracking
+		(tenant_id, project_id, date, activity_desc, quantity_completed, unit, percent_complete, workforce_deployed,
+		 notes, photo_url, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		tenantID, req.ProjectID, date, req.ActivityDesc, req.QuantityCompleted, req.Unit, req.PercentComplete,
+		req.WorkforceDeployed, req.Notes, req.PhotoURL, time.Now(), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to log progress: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress entry id: %w", err)
+	}
+
+	result := &models.ProgressLogResult{ProgressTrackingID: fmt.Sprint(id)}
+	if !completesMilestone {
+		return result, nil
+	}
+
+	// The progress entry stands on its own; if completion fails it can be retried
+	// through the milestone with this entry as evidence
+	run, err := s.PaymentPlans.CompleteMilestone(ctx, tenantID, userID, req.TowerMilestoneID, &models.CompleteMilestoneRequest{
+		CompletedOn:        date.Format("2006-01-02"),
+		ProgressTrackingID: result.ProgressTrackingID,
+		ArchitectCertRef:   req.ArchitectCertRef,
+		Channel:            req.Channel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("progress %s logged but milestone not completed: %w", result.ProgressTrackingID, err)
+	}
+	result.DemandRun = run
+	return result, nil
+}
@@ -0,0 +1,659 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// DELAYED-PAYMENT INTEREST SERVICE
+// ============================================================================
+// RERA interest on delayed installments (SBI MCLR + 2%) charged to customers,
+// and the same rate paid by the builder when possession is delayed
+
+type DelayedInterestService struct {
+	DB *sql.DB
+}
+
+func NewDelayedInterestService(db *sql.DB) *DelayedInterestService {
+	return &DelayedInterestService{DB: db}
+}
+
+// RERA spread over the benchmark lending rate
+const reraInterestSpread = 2.0
+
+// ============================================================================
+// RATE HISTORY & POLICY
+// ============================================================================
+
+// AddInterestRate records a new benchmark rate and closes the previous open period
+func (s *DelayedInterestService) AddInterestRate(tenantID, userID string, req *models.CreateInterestRateRequest) (*models.InterestRateHistory, error) {
+	spread := reraInterestSpread
+	if req.Spread != nil {
+		spread = *req.Spread
+	}
+	benchmark := req.Benchmark
+	if benchmark == "" {
+		benchmark = "SBI_MCLR"
+	}
+
+	rate := &models.InterestRateHistory{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Benchmark:     benchmark,
+		BaseRate:      req.BaseRate,
+		Spread:        spread,
+		EffectiveRate: req.BaseRate + spread,
+		EffectiveFrom: req.EffectiveFrom,
+		Notes:         req.Notes,
+		CreatedBy:     userID,
+		CreatedAt:     time.Now(),
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Close the currently open period the day before the new rate takes effect
+	_, err = tx.Exec(`UPDATE interest_rate_history SET effective_to = ?
+		WHERE tenant_id = ? AND effective_to IS NULL AND effective_from < ?`,
+		rate.EffectiveFrom.AddDate(0, 0, -1), tenantID, rate.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close previous rate: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO interest_rate_history
+		(id, tenant_id, benchmark, base_rate, spread, effective_rate, effective_from, notes, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rate.ID, rate.TenantID, rate.Benchmark, rate.BaseRate, rate.Spread, rate.EffectiveRate,
+		rate.EffectiveFrom, rate.Notes, rate.CreatedBy, rate.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interest rate: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit interest rate: %w", err)
+	}
+	return rate, nil
+}
+
+// ListInterestRates returns the rate history, oldest first
+func (s *DelayedInterestService) ListInterestRates(tenantID string) ([]models.InterestRateHistory, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, benchmark, base_rate, spread, effective_rate,
+		effective_from, effective_to, COALESCE(notes, ''), COALESCE(created_by, ''), created_at
+		FROM interest_rate_history WHERE tenant_id = ? ORDER BY effective_from ASC`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []models.InterestRateHistory{}
+	for rows.Next() {
+		var r models.InterestRateHistory
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.Benchmark, &r.BaseRate, &r.Spread, &r.EffectiveRate,
+			&r.EffectiveFrom, &r.EffectiveTo, &r.Notes, &r.CreatedBy, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan interest rate: %w", err)
+		}
+		rates = append(rates, r)
+	}
+	return rates, rows.Err()
+}
+
+// UpsertInterestPolicy sets the interest policy for the tenant (empty project) or a project
+func (s *DelayedInterestService) UpsertInterestPolicy(tenantID string, req *models.UpsertInterestPolicyRequest) (*models.InterestPolicy, error) {
+	method := strings.ToLower(req.Method)
+	if method == "" {
+		method = models.InterestMethodSimple
+	}
+	if method != models.InterestMethodSimple && method != models.InterestMethodCompound {
+		return nil, fmt.Errorf("invalid interest method: %s", req.Method)
+	}
+	if req.GraceDays < 0 {
+		return nil, fmt.Errorf("grace days cannot be negative")
+	}
+	compounding := req.CompoundingPerYear
+	if compounding <= 0 {
+		compounding = 12
+	}
+
+	policy := &models.InterestPolicy{
+		TenantID:            tenantID,
+		ProjectID:           req.ProjectID,
+		GraceDays:           req.GraceDays,
+		Method:              method,
+		CompoundingPerYear:  compounding,
+		ApplyToPossession:   req.ApplyToPossession,
+		MinimumInterestNote: req.MinimumInterestNote,
+		IsActive:            true,
+		UpdatedAt:           time.Now(),
+	}
+
+	existing, err := s.GetInterestPolicy(tenantID, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ProjectID == req.ProjectID {
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+		_, err = s.DB.Exec(`UPDATE interest_policies SET grace_days = ?, method = ?, compounding_per_year = ?,
+			apply_to_possession = ?, minimum_interest_note = ?, is_active = TRUE, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`,
+			policy.GraceDays, policy.Method, policy.CompoundingPerYear, policy.ApplyToPossession,
+			policy.MinimumInterestNote, policy.UpdatedAt, policy.ID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update interest policy: %w", err)
+		}
+		return policy, nil
+	}
+
+	policy.ID = uuid.New().String()
+	policy.CreatedAt = policy.UpdatedAt
+	_, err = s.DB.Exec(`INSERT INTO interest_policies
+		(id, tenant_id, project_id, grace_days, method, compounding_per_year, apply_to_possession,
+		 minimum_interest_note, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, TRUE, ?, ?)`,
+		policy.ID, tenantID, policy.ProjectID, policy.GraceDays, policy.Method, policy.CompoundingPerYear,
+		policy.ApplyToPossession, policy.MinimumInterestNote, policy.CreatedAt, policy.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interest policy: %w", err)
+	}
+	return policy, nil
+}
+
+// GetInterestPolicy returns the project policy, falling back to the tenant default.
+// Returns nil when neither is configured.
+func (s *DelayedInterestService) GetInterestPolicy(tenantID, projectID string) (*models.InterestPolicy, error) {
+	var p models.InterestPolicy
+	err := s.DB.QueryRow(`SELECT id, tenant_id, project_id, grace_days, method, compounding_per_year,
+		apply_to_possession, minimum_interest_note, is_active, created_at, updated_at
+		FROM interest_policies WHERE tenant_id = ? AND is_active = TRUE AND (project_id = ? OR project_id = '')
+		ORDER BY project_id DESC LIMIT 1`, tenantID, projectID).Scan(
+		&p.ID, &p.TenantID, &p.ProjectID, &p.GraceDays, &p.Method, &p.CompoundingPerYear,
+		&p.ApplyToPossession, &p.MinimumInterestNote, &p.IsActive, &p.CreatedAt, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest policy: %w", err)
+	}
+	return &p, nil
+}
+
+// policyOrDefault returns the configured policy or simple interest with no grace
+func (s *DelayedInterestService) policyOrDefault(tenantID, projectID string) (*models.InterestPolicy, error) {
+	policy, err := s.GetInterestPolicy(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &models.InterestPolicy{
+			TenantID:           tenantID,
+			ProjectID:          projectID,
+			Method:             models.InterestMethodSimple,
+			CompoundingPerYear: 12,
+			ApplyToPossession:  true,
+		}
+	}
+	return policy, nil
+}
+
+// ============================================================================
+// DELAYED INSTALLMENT INTEREST
+// ============================================================================
+
+// ComputeStageInterest computes interest accrued on every overdue payment stage as of a date.
+// Interest runs from the due date once the delay exceeds the grace period.
+func (s *DelayedInterestService) ComputeStageInterest(tenantID, projectID string, asOf time.Time) ([]models.InterestAccrual, error) {
+	rates, err := s.ListInterestRates(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no interest rate configured")
+	}
+
+	query := `SELECT ps.id, ps.stage_name, ps.project_id, ps.unit_id, ps.customer_id, ps.due_date,
+		COALESCE(ps.amount_pending, 0), COALESCE(b.id, '')
+		FROM property_payment_stage ps
+		LEFT JOIN customer_bookings b ON b.unit_id = ps.unit_id AND b.tenant_id = ps.tenant_id
+			AND b.booking_status = 'active' AND b.deleted_at IS NULL
+		WHERE ps.tenant_id = ? AND ps.due_date IS NOT NULL AND ps.due_date < ?
+		AND COALESCE(ps.amount_pending, 0) > 0 AND ps.collection_status <> 'COMPLETED'`
+	args := []interface{}{tenantID, asOf}
+	if projectID != "" {
+		query += " AND ps.project_id = ?"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY ps.project_id, ps.unit_id, ps.stage_number"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch overdue payment stages: %w", err)
+	}
+	defer rows.Close()
+
+	accruals := []models.InterestAccrual{}
+	for rows.Next() {
+		var a models.InterestAccrual
+		if err := rows.Scan(&a.StageID, &a.StageName, &a.ProjectID, &a.UnitID, &a.CustomerID,
+			&a.DueDate, &a.PrincipalOverdue, &a.BookingID); err != nil {
+			return nil, fmt.Errorf("failed to scan payment stage: %w", err)
+		}
+		accruals = append(accruals, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read payment stages: %w", err)
+	}
+
+	policies := map[string]*models.InterestPolicy{}
+	for i := range accruals {
+		a := &accruals[i]
+		policy, ok := policies[a.ProjectID]
+		if !ok {
+			if policy, err = s.policyOrDefault(tenantID, a.ProjectID); err != nil {
+				return nil, err
+			}
+			policies[a.ProjectID] = policy
+		}
+
+		a.DaysOverdue = daysPastDue(a.DueDate, asOf)
+		if a.DaysOverdue <= policy.GraceDays {
+			continue
+		}
+		a.ChargeableDays = a.DaysOverdue
+		a.AccruedInterest, a.Segments = computeInterest(a.PrincipalOverdue, a.DueDate, asOf, rates,
+			policy.Method, policy.CompoundingPerYear)
+
+		if a.AlreadyRaised, err = s.sumRaisedNotes(tenantID, models.InterestNoteDelayedPayment, "stage_id", a.StageID); err != nil {
+			return nil, err
+		}
+		a.InterestDue = roundTo2(math.Max(a.AccruedInterest-a.AlreadyRaised, 0))
+	}
+
+	return accruals, nil
+}
+
+// RaiseInterestNotes raises an interest demand note and a customer ledger debit for
+// every overdue stage with unbilled interest
+func (s *DelayedInterestService) RaiseInterestNotes(tenantID, userID string, req *models.RaiseInterestNotesRequest) ([]models.InterestNote, error) {
+	asOf := time.Now()
+	if req.AsOfDate != nil {
+		asOf = *req.AsOfDate
+	}
+
+	accruals, err := s.ComputeStageInterest(tenantID, req.ProjectID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []models.InterestNote{}
+	for _, a := range accruals {
+		if a.InterestDue <= 0 || a.BookingID == "" {
+			continue
+		}
+		policy, err := s.policyOrDefault(tenantID, a.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if a.InterestDue < policy.MinimumInterestNote {
+			continue
+		}
+
+		periodFrom, err := s.lastNotePeriodEnd(tenantID, models.InterestNoteDelayedPayment, "stage_id", a.StageID)
+		if err != nil {
+			return nil, err
+		}
+		if periodFrom == nil {
+			periodFrom = &a.DueDate
+		}
+
+		note := &models.InterestNote{
+			ID:             uuid.New().String(),
+			TenantID:       tenantID,
+			NoteNumber:     interestNoteNumber("INT"),
+			NoteType:       models.InterestNoteDelayedPayment,
+			BookingID:      a.BookingID,
+			StageID:        a.StageID,
+			ProjectID:      a.ProjectID,
+			PeriodFrom:     *periodFrom,
+			PeriodTo:       asOf,
+			Principal:      a.PrincipalOverdue,
+			InterestAmount: a.InterestDue,
+			Method:         policy.Method,
+			Status:         "raised",
+			CreatedBy:      userID,
+			CreatedAt:      time.Now(),
+		}
+
+		if !req.DryRun {
+			description := fmt.Sprintf("Interest on delayed payment - %s (%s to %s)", a.StageName,
+				note.PeriodFrom.Format("02-Jan-2006"), note.PeriodTo.Format("02-Jan-2006"))
+			if err := s.raiseNote(tenantID, a.CustomerID, note, "debit", description); err != nil {
+				return nil, err
+			}
+		}
+		notes = append(notes, *note)
+	}
+
+	return notes, nil
+}
+
+// ============================================================================
+// DELAYED POSSESSION COMPENSATION
+// ============================================================================
+
+// ComputePossessionCompensation computes interest payable to the customer when the
+// possession date promised on the booking's PossessionStatus is missed. Interest runs
+// on the amount paid by the customer from the promised date to handover (or asOf).
+func (s *DelayedInterestService) ComputePossessionCompensation(tenantID, userID string, req *models.PossessionCompensationRequest) (*models.PossessionCompensation, error) {
+	asOf := time.Now()
+	if req.AsOfDate != nil {
+		asOf = *req.AsOfDate
+	}
+
+	var promised sql.NullTime
+	var actual sql.NullTime
+	var projectID, customerID string
+	err := s.DB.QueryRow(`SELECT ps.estimated_possession_date, ps.possession_date,
+		COALESCE(u.project_id, ''), COALESCE(b.customer_id, '')
+		FROM possession_statuses ps
+		LEFT JOIN customer_bookings b ON b.id = ps.booking_id
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		WHERE ps.tenant_id = ? AND ps.booking_id = ? AND ps.deleted_at IS NULL`,
+		tenantID, req.BookingID).Scan(&promised, &actual, &projectID, &customerID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("possession status not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch possession status: %w", err)
+	}
+	if !promised.Valid {
+		return nil, fmt.Errorf("possession status has no promised date")
+	}
+
+	comp := &models.PossessionCompensation{
+		BookingID:    req.BookingID,
+		PromisedDate: promised.Time,
+		ComputedUpTo: asOf,
+	}
+	if actual.Valid {
+		comp.ActualDate = &actual.Time
+		if actual.Time.Before(asOf) {
+			comp.ComputedUpTo = actual.Time
+		}
+	}
+
+	comp.DelayDays = daysPastDue(comp.PromisedDate, comp.ComputedUpTo)
+	if comp.DelayDays <= 0 {
+		comp.DelayDays = 0
+		return comp, nil
+	}
+
+	policy, err := s.policyOrDefault(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.ApplyToPossession {
+		return comp, nil
+	}
+
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.booking_id = ? AND p.status = 'cleared' AND p.payment_date <= ? AND p.deleted_at IS NULL`,
+		tenantID, req.BookingID, comp.ComputedUpTo).Scan(&comp.AmountPaid); err != nil {
+		return nil, fmt.Errorf("failed to fetch amount paid: %w", err)
+	}
+
+	rates, err := s.ListInterestRates(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no interest rate configured")
+	}
+
+	comp.AccruedInterest, comp.Segments = computeInterest(comp.AmountPaid, comp.PromisedDate, comp.ComputedUpTo,
+		rates, policy.Method, policy.CompoundingPerYear)
+	if comp.AlreadyCredited, err = s.sumRaisedNotes(tenantID, models.InterestNotePossessionDelayed, "booking_id", req.BookingID); err != nil {
+		return nil, err
+	}
+	comp.CompensationDue = roundTo2(math.Max(comp.AccruedInterest-comp.AlreadyCredited, 0))
+
+	if req.Raise && comp.CompensationDue > 0 {
+		periodFrom, err := s.lastNotePeriodEnd(tenantID, models.InterestNotePossessionDelayed, "booking_id", req.BookingID)
+		if err != nil {
+			return nil, err
+		}
+		if periodFrom == nil {
+			periodFrom = &comp.PromisedDate
+		}
+
+		note := &models.InterestNote{
+			ID:             uuid.New().String(),
+			TenantID:       tenantID,
+			NoteNumber:     interestNoteNumber("CMP"),
+			NoteType:       models.InterestNotePossessionDelayed,
+			BookingID:      req.BookingID,
+			ProjectID:      projectID,
+			PeriodFrom:     *periodFrom,
+			PeriodTo:       comp.ComputedUpTo,
+			Principal:      comp.AmountPaid,
+			InterestAmount: comp.CompensationDue,
+			Method:         policy.Method,
+			Status:         "raised",
+			CreatedBy:      userID,
+			CreatedAt:      time.Now(),
+		}
+		description := fmt.Sprintf("Compensation for delayed possession (%d days)", comp.DelayDays)
+		if err := s.raiseNote(tenantID, customerID, note, "credit", description); err != nil {
+			return nil, err
+		}
+		comp.Note = note
+	}
+
+	return comp, nil
+}
+
+// ListInterestNotes lists interest notes raised for a booking
+func (s *DelayedInterestService) ListInterestNotes(tenantID, bookingID string) ([]models.InterestNote, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, note_number, note_type, booking_id, COALESCE(stage_id, ''),
+		COALESCE(project_id, ''), period_from, period_to, principal, interest_amount, method,
+		COALESCE(ledger_entry_id, ''), status, COALESCE(created_by, ''), created_at
+		FROM interest_notes WHERE tenant_id = ? AND booking_id = ? ORDER BY created_at DESC`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []models.InterestNote{}
+	for rows.Next() {
+		var n models.InterestNote
+		if err := rows.Scan(&n.ID, &n.TenantID, &n.NoteNumber, &n.NoteType, &n.BookingID, &n.StageID,
+			&n.ProjectID, &n.PeriodFrom, &n.PeriodTo, &n.Principal, &n.InterestAmount, &n.Method,
+			&n.LedgerEntryID, &n.Status, &n.CreatedBy, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan interest note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// raiseNote writes the customer ledger entry and the interest note in one transaction
+func (s *DelayedInterestService) raiseNote(tenantID, customerID string, note *models.InterestNote, txnType, description string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ledgerID, err := insertCustomerLedgerEntry(tx, tenantID, note.BookingID, customerID, txnType, description,
+		note.InterestAmount, note.NoteNumber)
+	if err != nil {
+		return err
+	}
+	note.LedgerEntryID = ledgerID
+
+	_, err = tx.Exec(`INSERT INTO interest_notes
+		(id, tenant_id, note_number, note_type, booking_id, stage_id, project_id, period_from, period_to,
+		 principal, interest_amount, method, ledger_entry_id, status, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		note.ID, note.TenantID, note.NoteNumber, note.NoteType, note.BookingID, note.StageID, note.ProjectID,
+		note.PeriodFrom, note.PeriodTo, note.Principal, note.InterestAmount, note.Method, note.LedgerEntryID,
+		note.Status, note.CreatedBy, note.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save interest note: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit interest note: %w", err)
+	}
+	return nil
+}
+
+// insertCustomerLedgerEntry appends a debit or credit to the booking's customer ledger,
+// carrying the running balance the same way the real-estate ledger does
+func insertCustomerLedgerEntry(tx *sql.Tx, tenantID, bookingID, customerID, txnType, description string, amount float64, reference string) (string, error) {
+	var openingBalance float64
+	err := tx.QueryRow(`SELECT COALESCE(closing_balance, 0) FROM customer_account_ledgers
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+		ORDER BY transaction_date DESC, created_at DESC LIMIT 1`, tenantID, bookingID).Scan(&openingBalance)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to fetch ledger balance: %w", err)
+	}
+
+	debit, credit := 0.0, 0.0
+	closingBalance := openingBalance
+	if txnType == "credit" {
+		credit = amount
+		closingBalance += amount
+	} else {
+		debit = amount
+		closingBalance -= amount
+	}
+
+	var customer interface{}
+	if customerID != "" {
+		customer = customerID
+	}
+
+	id := uuid.New().String()
+	_, err = tx.Exec(`INSERT INTO customer_account_ledgers
+		(id, tenant_id, booking_id, customer_id, transaction_date, transaction_type, description,
+		 debit_amount, credit_amount, opening_balance, closing_balance, reference_number, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, tenantID, bookingID, customer, time.Now(), txnType, description,
+		debit, credit, openingBalance, closingBalance, reference, time.Now(), time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to create ledger entry: %w", err)
+	}
+	return id, nil
+}
+
+func (s *DelayedInterestService) sumRaisedNotes(tenantID, noteType, column, value string) (float64, error) {
+	var total float64
+	err := s.DB.QueryRow(`SELECT COALESCE(SUM(interest_amount), 0) FROM interest_notes
+		WHERE tenant_id = ? AND note_type = ? AND status = 'raised' AND `+column+` = ?`,
+		tenantID, noteType, value).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum interest notes: %w", err)
+	}
+	return total, nil
+}
+
+func (s *DelayedInterestService) lastNotePeriodEnd(tenantID, noteType, column, value string) (*time.Time, error) {
+	var periodTo sql.NullTime
+	err := s.DB.QueryRow(`SELECT MAX(period_to) FROM interest_notes
+		WHERE tenant_id = ? AND note_type = ? AND status = 'raised' AND `+column+` = ?`,
+		tenantID, noteType, value).Scan(&periodTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch last interest note: %w", err)
+	}
+	if !periodTo.Valid {
+		return nil, nil
+	}
+	return &periodTo.Time, nil
+}
+
+func interestNoteNumber(prefix string) string {
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().Format("20060102"), strings.ToUpper(uuid.New().String()[:8]))
+}
+
+// computeInterest accrues interest on principal from `from` to `to`, splitting the
+// period wherever the rate history changes. Days not covered by any rate accrue nothing.
+func computeInterest(principal float64, from, to time.Time, rates []models.InterestRateHistory, method string, compoundingPerYear int) (float64, []models.InterestSegment) {
+	if principal <= 0 || !to.After(from) {
+		return 0, nil
+	}
+	if compoundingPerYear <= 0 {
+		compoundingPerYear = 12
+	}
+
+	sorted := make([]models.InterestRateHistory, len(rates))
+	copy(sorted, rates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveFrom.Before(sorted[j].EffectiveFrom)
+	})
+
+	balance := principal
+	total := 0.0
+	segments := []models.InterestSegment{}
+	for i, rate := range sorted {
+		// A rate ends where the next one starts, or at its explicit end date (inclusive)
+		end := to
+		if i+1 < len(sorted) && sorted[i+1].EffectiveFrom.Before(end) {
+			end = sorted[i+1].EffectiveFrom
+		}
+		if rate.EffectiveTo != nil && rate.EffectiveTo.AddDate(0, 0, 1).Before(end) {
+			end = rate.EffectiveTo.AddDate(0, 0, 1)
+		}
+		start := from
+		if rate.EffectiveFrom.After(start) {
+			start = rate.EffectiveFrom
+		}
+
+		days := daysPastDue(start, end)
+		if days <= 0 {
+			continue
+		}
+
+		var interest float64
+		if method == models.InterestMethodCompound {
+			n := float64(compoundingPerYear)
+			grown := balance * math.Pow(1+rate.EffectiveRate/100/n, n*float64(days)/365)
+			interest = grown - balance
+			balance = grown
+		} else {
+			interest = principal * rate.EffectiveRate / 100 * float64(days) / 365
+		}
+
+		total += interest
+		segments = append(segments, models.InterestSegment{
+			From:     start,
+			To:       end,
+			Days:     days,
+			Rate:     rate.EffectiveRate,
+			Interest: roundTo2(interest),
+		})
+	}
+
+	return roundTo2(total), segments
+}
+
+func roundTo2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
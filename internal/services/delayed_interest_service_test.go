@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRates() []models.InterestRateHistory {
+	changeDate := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	closed := changeDate.AddDate(0, 0, -1)
+	return []models.InterestRateHistory{
+		{EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EffectiveTo: &closed, EffectiveRate: 10.65},
+		{EffectiveFrom: changeDate, EffectiveRate: 11},
+	}
+}
+
+// TestComputeInterestSimple tests simple interest across a rate change
+func TestComputeInterestSimple(t *testing.T) {
+	from := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 11, 0, 0, 0, 0, time.UTC)
+
+	interest, segments := computeInterest(365000, from, to, testRates(), models.InterestMethodSimple, 12)
+
+	assert.Len(t, segments, 2)
+	assert.Equal(t, 30, segments[0].Days)
+	assert.Equal(t, 10, segments[1].Days)
+	// 365000 * 10.65% * 30/365 + 365000 * 11% * 10/365
+	assert.InDelta(t, 3195.0+1100.0, interest, 0.01)
+}
+
+// TestComputeInterestCompound tests compound interest is above simple interest
+func TestComputeInterestCompound(t *testing.T) {
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	simple, _ := computeInterest(100000, from, to, testRates(), models.InterestMethodSimple, 12)
+	compound, _ := computeInterest(100000, from, to, testRates(), models.InterestMethodCompound, 12)
+
+	assert.InDelta(t, 11000.0, simple, 0.01)
+	assert.Greater(t, compound, simple)
+	assert.InDelta(t, 11571.88, compound, 1.0)
+}
+
+// TestComputeInterestNoPrincipal tests that nothing accrues without principal or time
+func TestComputeInterestNoPrincipal(t *testing.T) {
+	now := time.Now()
+	interest, segments := computeInterest(0, now.AddDate(0, -1, 0), now, testRates(), models.InterestMethodSimple, 12)
+	assert.Equal(t, 0.0, interest)
+	assert.Nil(t, segments)
+
+	interest, _ = computeInterest(1000, now, now.AddDate(0, 0, -1), testRates(), models.InterestMethodSimple, 12)
+	assert.Equal(t, 0.0, interest)
+}
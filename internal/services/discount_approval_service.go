@@ -0,0 +1,640 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// DISCOUNT APPROVAL SERVICE
+// ============================================================================
+// Discount limits by role and project, in absolute terms and per sqft. A
+// discount within the requester's own limit is approved outright; anything
+// above it climbs the approval chain (sales manager, VP, director by default)
+// level by level until it reaches a role whose limit covers it. Bookings and
+// sales orders can only apply an approved discount, and only once.
+
+type DiscountApprovalService struct {
+	DB *sql.DB
+}
+
+func NewDiscountApprovalService(db *sql.DB) *DiscountApprovalService {
+	return &DiscountApprovalService{DB: db}
+}
+
+// ============================================================================
+// LIMITS
+// ============================================================================
+
+// SetLimit creates or replaces a role's discount limit for a project (or all projects)
+func (s *DiscountApprovalService) SetLimit(tenantID string, req *models.SetDiscountLimitRequest) (*models.DiscountLimit, error) {
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if role == "" {
+		return nil, fmt.Errorf("role is required")
+	}
+	if req.MaxAmount < 0 || req.MaxPerSqft < 0 || req.ApprovalLevel < 0 {
+		return nil, fmt.Errorf("limits and approval_level cannot be negative")
+	}
+
+	now := time.Now()
+	limit := &models.DiscountLimit{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Role:          role,
+		MaxAmount:     req.MaxAmount,
+		MaxPerSqft:    req.MaxPerSqft,
+		Unlimited:     req.Unlimited,
+		ApprovalLevel: req.ApprovalLevel,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if req.ProjectID != "" {
+		limit.ProjectID = &req.ProjectID
+	}
+
+	if _, err := s.DB.Exec(`INSERT INTO discount_limits
+		(id, tenant_id, project_id, role, max_amount, max_per_sqft, unlimited, approval_level, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE max_amount = VALUES(max_amount), max_per_sqft = VALUES(max_per_sqft),
+			unlimited = VALUES(unlimited), approval_level = VALUES(approval_level), updated_at = VALUES(updated_at)`,
+		limit.ID, tenantID, req.ProjectID, limit.Role, limit.MaxAmount, limit.MaxPerSqft, limit.Unlimited,
+		limit.ApprovalLevel, limit.CreatedAt, limit.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to set discount limit: %w", err)
+	}
+	return limit, nil
+}
+
+// ListLimits lists discount limits; with a project, its own limits and the tenant-wide ones
+func (s *DiscountApprovalService) ListLimits(tenantID, projectID string) ([]models.DiscountLimit, error) {
+	if projectID == "" {
+		return s.getLimits(tenantID, "1 = 1")
+	}
+	return s.getLimits(tenantID, "project_id IN ('', ?)", projectID)
+}
+
+func (s *DiscountApprovalService) getLimits(tenantID, where string, args ...interface{}) ([]models.DiscountLimit, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, role, max_amount, max_per_sqft, unlimited, approval_level,
+		created_at, updated_at FROM discount_limits WHERE tenant_id = ? AND `+where+`
+		ORDER BY project_id, approval_level, role`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discount limits: %w", err)
+	}
+	defer rows.Close()
+
+	limits := []models.DiscountLimit{}
+	for rows.Next() {
+		var l models.DiscountLimit
+		var project string
+		if err := rows.Scan(&l.ID, &l.TenantID, &project, &l.Role, &l.MaxAmount, &l.MaxPerSqft, &l.Unlimited,
+			&l.ApprovalLevel, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan discount limit: %w", err)
+		}
+		if project != "" {
+			l.ProjectID = &project
+		}
+		limits = append(limits, l)
+	}
+	return limits, rows.Err()
+}
+
+// ============================================================================
+// DISCOUNT REQUESTS
+// ============================================================================
+
+// RequestDiscount raises a discount on a unit's cost sheet or on a quotation and
+// routes it to the approvers it needs
+func (s *DiscountApprovalService) RequestDiscount(tenantID, userID, role string, req *models.CreateDiscountRequest) (*models.DiscountRequest, error) {
+	if req.UnitID == "" && req.QuotationID == "" {
+		return nil, fmt.Errorf("unit_id or quotation_id is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	now := time.Now()
+	dr := &models.DiscountRequest{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		UnitID:        req.UnitID,
+		QuotationID:   req.QuotationID,
+		RequestedBy:   userID,
+		RequesterRole: strings.ToLower(role),
+		Reason:        req.Reason,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if req.UnitID != "" {
+		asOf := now
+		if req.AsOf != "" {
+			var err error
+			if asOf, err = time.Parse("2006-01-02", req.AsOf); err != nil {
+				return nil, fmt.Errorf("invalid as_of: %w", err)
+			}
+		}
+		sheet, err := NewPriceListService(s.DB).GetCostSheet(tenantID, req.UnitID, asOf, &models.CostSheetOptions{
+			ParkingType:  req.ParkingType,
+			ParkingSlots: req.ParkingSlots,
+		})
+		if err != nil {
+			return nil, err
+		}
+		dr.ProjectID = sheet.ProjectID
+		dr.PriceListID = sheet.PriceListID
+		dr.SBUA = sheet.SBUA
+		dr.ListPrice = sheet.AgreementValue
+	} else {
+		if err := s.DB.QueryRow(`SELECT subtotal_amount FROM sales_quotations
+			WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`, req.QuotationID, tenantID).Scan(&dr.ListPrice); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("quotation not found")
+			}
+			return nil, fmt.Errorf("failed to get quotation: %w", err)
+		}
+	}
+
+	dr.DiscountAmount, dr.DiscountPerSqft = resolveDiscount(req.DiscountAmount, req.DiscountPerSqft, dr.SBUA)
+	if dr.DiscountAmount <= 0 {
+		return nil, fmt.Errorf("discount_amount or discount_per_sqft (on a unit) is required")
+	}
+	if dr.DiscountAmount >= dr.ListPrice {
+		return nil, fmt.Errorf("discount cannot exceed the list price")
+	}
+	dr.ApprovedPrice = roundTo2(dr.ListPrice - dr.DiscountAmount)
+
+	// Quotations outside a project only fall under the tenant-wide limits
+	limits, err := s.getLimits(tenantID, "project_id IN ('', ?)", dr.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := buildDiscountApprovalChain(mergeDiscountLimits(limits), dr.RequesterRole, dr.DiscountAmount, dr.DiscountPerSqft)
+	if err != nil {
+		return nil, err
+	}
+
+	dr.Status = models.DiscountStatusApproved
+	dr.Steps = []models.DiscountApprovalStep{}
+	for _, l := range chain {
+		dr.Steps = append(dr.Steps, models.DiscountApprovalStep{
+			ID:           uuid.New().String(),
+			RequestID:    dr.ID,
+			Level:        l.ApprovalLevel,
+			ApproverRole: l.Role,
+			Status:       models.DiscountStepPending,
+		})
+	}
+	if len(dr.Steps) > 0 {
+		dr.Status = models.DiscountStatusPending
+		dr.CurrentLevel = dr.Steps[0].Level
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO discount_requests
+		(id, tenant_id, project_id, unit_id, quotation_id, price_list_id, requested_by, requester_role, sbua,
+		 list_price, discount_amount, discount_per_sqft, approved_price, reason, status, current_level,
+		 created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		dr.ID, dr.TenantID, nullIfEmpty(dr.ProjectID), nullIfEmpty(dr.UnitID), nullIfEmpty(dr.QuotationID),
+		nullIfEmpty(dr.PriceListID), dr.RequestedBy, dr.RequesterRole, dr.SBUA, dr.ListPrice, dr.DiscountAmount,
+		dr.DiscountPerSqft, dr.ApprovedPrice, dr.Reason, dr.Status, dr.CurrentLevel, dr.CreatedAt,
+		dr.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create discount request: %w", err)
+	}
+	for _, st := range dr.Steps {
+		if _, err := tx.Exec(`INSERT INTO discount_approval_steps (id, request_id, level, approver_role, status)
+			VALUES (?, ?, ?, ?, ?)`, st.ID, st.RequestID, st.Level, st.ApproverRole, st.Status); err != nil {
+			return nil, fmt.Errorf("failed to create approval step: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit discount request: %w", err)
+	}
+	return dr, nil
+}
+
+// DecideDiscount approves or rejects the pending step of a request. Only the
+// step's role (or an admin) may act, never the requester, and a rejection
+// needs a reason.
+func (s *DiscountApprovalService) DecideDiscount(tenantID, userID, role, requestID string, req *models.DiscountDecisionRequest) (*models.DiscountRequest, error) {
+	dr, err := s.GetDiscountRequest(tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if dr.Status != models.DiscountStatusPending {
+		return nil, fmt.Errorf("discount request is %s", dr.Status)
+	}
+	if dr.RequestedBy == userID {
+		return nil, fmt.Errorf("a discount cannot be approved by the user who requested it")
+	}
+	if !req.Approve && strings.TrimSpace(req.Comment) == "" {
+		return nil, fmt.Errorf("a reason is required to reject a discount")
+	}
+
+	step, next := currentDiscountStep(dr)
+	if step == nil {
+		return nil, fmt.Errorf("discount request has no pending approval step")
+	}
+	role = strings.ToLower(role)
+	if role != step.ApproverRole && role != "admin" {
+		return nil, fmt.Errorf("discount is awaiting %s approval", step.ApproverRole)
+	}
+
+	now := time.Now()
+	stepStatus := models.DiscountStepApproved
+	status, level := models.DiscountStatusPending, 0
+	switch {
+	case !req.Approve:
+		stepStatus, status = models.DiscountStepRejected, models.DiscountStatusRejected
+	case next == nil:
+		status = models.DiscountStatusApproved
+	default:
+		level = next.Level
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE discount_approval_steps SET status = ?, acted_by = ?, acted_at = ?, comment = ?
+		WHERE id = ?`, stepStatus, userID, now, nullIfEmpty(req.Comment), step.ID); err != nil {
+		return nil, fmt.Errorf("failed to record approval step: %w", err)
+	}
+	res, err := tx.Exec(`UPDATE discount_requests SET status = ?, current_level = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ? AND current_level = ?`,
+		status, level, now, dr.ID, tenantID, models.DiscountStatusPending, step.Level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update discount request: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("discount request was updated by someone else, reload and retry")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit discount decision: %w", err)
+	}
+	return s.GetDiscountRequest(tenantID, requestID)
+}
+
+// GetDiscountRequest returns a discount request with its approval chain
+func (s *DiscountApprovalService) GetDiscountRequest(tenantID, requestID string) (*models.DiscountRequest, error) {
+	requests, err := s.getDiscountRequests(tenantID, "d.id = ?", requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("discount request not found")
+	}
+	return &requests[0], nil
+}
+
+// ListDiscountRequests lists requests by status, unit or quotation; with an
+// approver role, only those waiting on that role
+func (s *DiscountApprovalService) ListDiscountRequests(tenantID, status, approverRole, unitID, quotationID string) ([]models.DiscountRequest, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	if status != "" {
+		where += " AND d.status = ?"
+		args = append(args, status)
+	}
+	if approverRole != "" {
+		where += ` AND d.status = 'pending' AND EXISTS (SELECT 1 FROM discount_approval_steps st
+			WHERE st.request_id = d.id AND st.level = d.current_level AND st.approver_role = ?)`
+		args = append(args, strings.ToLower(approverRole))
+	}
+	if unitID != "" {
+		where += " AND d.unit_id = ?"
+		args = append(args, unitID)
+	}
+	if quotationID != "" {
+		where += " AND d.quotation_id = ?"
+		args = append(args, quotationID)
+	}
+	return s.getDiscountRequests(tenantID, where, args...)
+}
+
+// ApprovedQuotationDiscount returns the approved, unused discount on a quotation
+// that covers the discount being applied
+func (s *DiscountApprovalService) ApprovedQuotationDiscount(tenantID, quotationID string, discount float64) (*models.DiscountRequest, error) {
+	requests, err := s.getDiscountRequests(tenantID, "d.quotation_id = ? AND d.status = ? AND d.discount_amount >= ?",
+		quotationID, models.DiscountStatusApproved, roundTo2(discount))
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("discount of %.2f on the quotation has not been approved", discount)
+	}
+	return &requests[0], nil
+}
+
+// AuthorizeOrderDiscount checks a discount on a sales order and returns the approved
+// request it consumes, if any. A named request or, on a converted quotation, the
+// quotation's approved request must cover the discount; otherwise the discount has
+// to fall within the role's own tenant-wide limit.
+func (s *DiscountApprovalService) AuthorizeOrderDiscount(tenantID, role, quotationID, requestID string, discount float64) (string, error) {
+	if discount <= 0 {
+		return "", nil
+	}
+
+	if requestID != "" {
+		dr, err := s.GetDiscountRequest(tenantID, requestID)
+		if err != nil {
+			return "", err
+		}
+		if dr.Status != models.DiscountStatusApproved || dr.UnitID != "" || (quotationID != "" && dr.QuotationID != quotationID) {
+			return "", fmt.Errorf("discount is not approved for this order")
+		}
+		if dr.DiscountAmount < roundTo2(discount) {
+			return "", fmt.Errorf("discount of %.2f exceeds the %.2f approved", discount, dr.DiscountAmount)
+		}
+		return dr.ID, nil
+	}
+
+	if quotationID != "" {
+		dr, err := s.ApprovedQuotationDiscount(tenantID, quotationID, discount)
+		if err != nil {
+			return "", err
+		}
+		return dr.ID, nil
+	}
+
+	limits, err := s.getLimits(tenantID, "project_id = ''")
+	if err != nil {
+		return "", err
+	}
+	chain, err := buildDiscountApprovalChain(mergeDiscountLimits(limits), strings.ToLower(role), roundTo2(discount), 0)
+	if err != nil {
+		return "", err
+	}
+	if len(chain) > 0 {
+		return "", fmt.Errorf("discount of %.2f exceeds your limit and needs an approved discount request", discount)
+	}
+	return "", nil
+}
+
+// SalesOrderLine is the pricing of one sales order or quotation line
+type SalesOrderLine struct {
+	LineNumber      int
+	ProductCode     string
+	Quantity        float64
+	UnitPrice       float64
+	DiscountPercent float64
+	DiscountAmount  float64
+}
+
+// OrderDiscount returns the full discount a sales order gives: the header discount,
+// the line discounts and, on a converted quotation, any line priced below the quote.
+// The quotation must belong to the customer and must not have been converted yet.
+func (s *DiscountApprovalService) OrderDiscount(tenantID, quotationID, customerID string, header float64, lines []SalesOrderLine) (float64, error) {
+	var quoted []SalesOrderLine
+	if quotationID != "" {
+		var quoteCustomer string
+		var converted bool
+		err := s.DB.QueryRow(`SELECT COALESCE(customer_id,''), COALESCE(converted_to_order,false)
+			FROM sales_quotations WHERE id = ? AND tenant_id = ?`, quotationID, tenantID).Scan(&quoteCustomer, &converted)
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("quotation not found")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to get quotation: %w", err)
+		}
+		if quoteCustomer != customerID {
+			return 0, fmt.Errorf("quotation belongs to another customer")
+		}
+		if converted {
+			return 0, fmt.Errorf("quotation has already been converted to an order")
+		}
+
+		rows, err := s.DB.Query(`SELECT line_number, COALESCE(product_service_code,''), COALESCE(unit_price,0)
+			FROM sales_quotation_items WHERE quotation_id = ? AND tenant_id = ? ORDER BY line_number`, quotationID, tenantID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get quotation items: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var l SalesOrderLine
+			if err := rows.Scan(&l.LineNumber, &l.ProductCode, &l.UnitPrice); err != nil {
+				return 0, fmt.Errorf("failed to scan quotation item: %w", err)
+			}
+			quoted = append(quoted, l)
+		}
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to get quotation items: %w", err)
+		}
+	}
+	return orderDiscountTotal(header, lines, quoted), nil
+}
+
+// MarkDiscountUsed consumes an approved discount for a booking or sales order
+func (s *DiscountApprovalService) MarkDiscountUsed(tenantID, requestID, bookingID, salesOrderID string) error {
+	return markDiscountUsed(s.DB, tenantID, requestID, bookingID, salesOrderID)
+}
+
+// MarkDiscountUsedTx consumes an approved discount inside the caller's transaction
+func (s *DiscountApprovalService) MarkDiscountUsedTx(tx *sql.Tx, tenantID, requestID, bookingID, salesOrderID string) error {
+	return markDiscountUsed(tx, tenantID, requestID, bookingID, salesOrderID)
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func markDiscountUsed(db sqlExecer, tenantID, requestID, bookingID, salesOrderID string) error {
+	res, err := db.Exec(`UPDATE discount_requests SET status = ?, used_by_booking_id = ?, used_by_sales_order_id = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.DiscountStatusUsed, nullIfEmpty(bookingID), nullIfEmpty(salesOrderID), time.Now(), requestID, tenantID,
+		models.DiscountStatusApproved)
+	if err != nil {
+		return fmt.Errorf("failed to apply discount: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("discount is not approved or has already been used")
+	}
+	return nil
+}
+
+// orderDiscountTotal adds up the header discount, each line's own discount and, for
+// lines matched to the quotation by product code or line number, the amount a line is
+// priced below its quoted unit price
+func orderDiscountTotal(header float64, lines, quoted []SalesOrderLine) float64 {
+	total := header
+	for _, l := range lines {
+		if l.DiscountAmount > 0 {
+			total += l.DiscountAmount
+		} else if l.DiscountPercent > 0 {
+			total += l.Quantity * l.UnitPrice * l.DiscountPercent / 100
+		}
+
+		for _, q := range quoted {
+			if (l.ProductCode != "" && q.ProductCode == l.ProductCode) || (l.ProductCode == "" && q.LineNumber == l.LineNumber) {
+				if q.UnitPrice > l.UnitPrice {
+					total += (q.UnitPrice - l.UnitPrice) * l.Quantity
+				}
+				break
+			}
+		}
+	}
+	return roundTo2(total)
+}
+
+func (s *DiscountApprovalService) getDiscountRequests(tenantID, where string, args ...interface{}) ([]models.DiscountRequest, error) {
+	rows, err := s.DB.Query(`SELECT d.id, d.tenant_id, COALESCE(d.project_id, ''), COALESCE(d.unit_id, ''),
+		COALESCE(d.quotation_id, ''), COALESCE(d.price_list_id, ''), d.requested_by, d.requester_role, d.sbua,
+		d.list_price, d.discount_amount, d.discount_per_sqft, d.approved_price, d.reason, d.status,
+		d.current_level, d.used_by_booking_id, d.used_by_sales_order_id, d.created_at, d.updated_at
+		FROM discount_requests d WHERE d.tenant_id = ? AND `+where+` ORDER BY d.created_at DESC`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discount requests: %w", err)
+	}
+
+	requests := []models.DiscountRequest{}
+	for rows.Next() {
+		var d models.DiscountRequest
+		var booking, order sql.NullString
+		if err := rows.Scan(&d.ID, &d.TenantID, &d.ProjectID, &d.UnitID, &d.QuotationID, &d.PriceListID,
+			&d.RequestedBy, &d.RequesterRole, &d.SBUA, &d.ListPrice, &d.DiscountAmount, &d.DiscountPerSqft,
+			&d.ApprovedPrice, &d.Reason, &d.Status, &d.CurrentLevel, &booking, &order, &d.CreatedAt,
+			&d.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan discount request: %w", err)
+		}
+		if booking.Valid {
+			d.UsedByBookingID = &booking.String
+		}
+		if order.Valid {
+			d.UsedBySalesOrder = &order.String
+		}
+		requests = append(requests, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read discount requests: %w", err)
+	}
+
+	for i := range requests {
+		if requests[i].Steps, err = s.getApprovalSteps(requests[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return requests, nil
+}
+
+func (s *DiscountApprovalService) getApprovalSteps(requestID string) ([]models.DiscountApprovalStep, error) {
+	rows, err := s.DB.Query(`SELECT id, request_id, level, approver_role, status, acted_by, acted_at,
+		COALESCE(comment, '') FROM discount_approval_steps WHERE request_id = ? ORDER BY level`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approval steps: %w", err)
+	}
+	defer rows.Close()
+
+	steps := []models.DiscountApprovalStep{}
+	for rows.Next() {
+		var st models.DiscountApprovalStep
+		var actedBy sql.NullString
+		var actedAt sql.NullTime
+		if err := rows.Scan(&st.ID, &st.RequestID, &st.Level, &st.ApproverRole, &st.Status, &actedBy, &actedAt,
+			&st.Comment); err != nil {
+			return nil, fmt.Errorf("failed to scan approval step: %w", err)
+		}
+		if actedBy.Valid {
+			st.ActedBy = &actedBy.String
+		}
+		if actedAt.Valid {
+			st.ActedAt = &actedAt.Time
+		}
+		steps = append(steps, st)
+	}
+	return steps, rows.Err()
+}
+
+// resolveDiscount fills in whichever of the amount and the per-sqft figure was not given
+func resolveDiscount(amount, perSqft, sbua float64) (float64, float64) {
+	if amount <= 0 && perSqft > 0 && sbua > 0 {
+		amount = perSqft * sbua
+	}
+	if sbua > 0 {
+		perSqft = amount / sbua
+	}
+	return roundTo2(amount), roundTo2(perSqft)
+}
+
+// mergeDiscountLimits keys limits by role, a project's own limit replacing the tenant-wide one
+func mergeDiscountLimits(limits []models.DiscountLimit) map[string]models.DiscountLimit {
+	merged := map[string]models.DiscountLimit{}
+	for _, l := range limits {
+		if existing, ok := merged[l.Role]; ok && existing.ProjectID != nil && l.ProjectID == nil {
+			continue
+		}
+		merged[l.Role] = l
+	}
+	return merged
+}
+
+// discountWithinLimit reports whether a role may give the discount on its own authority
+func discountWithinLimit(l models.DiscountLimit, amount, perSqft float64) bool {
+	if l.Unlimited {
+		return true
+	}
+	if amount > l.MaxAmount {
+		return false
+	}
+	return l.MaxPerSqft == 0 || perSqft <= l.MaxPerSqft
+}
+
+// buildDiscountApprovalChain returns the approvers a discount has to pass, in level
+// order, up to the first whose limit covers it. An empty chain means the
+// requester's own limit covers it.
+func buildDiscountApprovalChain(limits map[string]models.DiscountLimit, requesterRole string, amount, perSqft float64) ([]models.DiscountLimit, error) {
+	own, ok := limits[requesterRole]
+	if ok && discountWithinLimit(own, amount, perSqft) {
+		return nil, nil
+	}
+
+	approvers := []models.DiscountLimit{}
+	for _, l := range limits {
+		// Approvers at or below the requester's own level cannot raise their limit
+		if l.ApprovalLevel > 0 && (!ok || l.ApprovalLevel > own.ApprovalLevel) {
+			approvers = append(approvers, l)
+		}
+	}
+	sort.Slice(approvers, func(i, j int) bool { return approvers[i].ApprovalLevel < approvers[j].ApprovalLevel })
+
+	for i, l := range approvers {
+		if discountWithinLimit(l, amount, perSqft) {
+			return approvers[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("discount of %.2f (%.2f per sqft) exceeds every approval limit", amount, perSqft)
+}
+
+// currentDiscountStep returns the step awaiting action and the one after it
+func currentDiscountStep(dr *models.DiscountRequest) (*models.DiscountApprovalStep, *models.DiscountApprovalStep) {
+	for i := range dr.Steps {
+		if dr.Steps[i].Level == dr.CurrentLevel && dr.Steps[i].Status == models.DiscountStepPending {
+			if i+1 < len(dr.Steps) {
+				return &dr.Steps[i], &dr.Steps[i+1]
+			}
+			return &dr.Steps[i], nil
+		}
+	}
+	return nil, nil
+}
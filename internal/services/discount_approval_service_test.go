@@ -0,0 +1,157 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDiscountLimits() map[string]models.DiscountLimit {
+	return mergeDiscountLimits([]models.DiscountLimit{
+		{Role: "sales", MaxAmount: 50000, MaxPerSqft: 50},
+		{Role: models.DiscountApproverSalesManager, MaxAmount: 200000, MaxPerSqft: 200, ApprovalLevel: 1},
+		{Role: models.DiscountApproverVP, MaxAmount: 500000, ApprovalLevel: 2},
+		{Role: models.DiscountApproverDirector, Unlimited: true, ApprovalLevel: 3},
+	})
+}
+
+// TestBuildDiscountApprovalChain tests routing up to the first approver whose limit covers the discount
+func TestBuildDiscountApprovalChain(t *testing.T) {
+	roles := func(chain []models.DiscountLimit) []string {
+		out := []string{}
+		for _, l := range chain {
+			out = append(out, l.Role)
+		}
+		return out
+	}
+	limits := testDiscountLimits()
+
+	// Within the requester's own limit
+	chain, err := buildDiscountApprovalChain(limits, "sales", 40000, 40)
+	assert.NoError(t, err)
+	assert.Empty(t, chain)
+
+	// Over the amount limit, covered by the sales manager
+	chain, err = buildDiscountApprovalChain(limits, "sales", 150000, 150)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sales_manager"}, roles(chain))
+
+	// Within the sales manager's amount but over the per-sqft cap
+	chain, err = buildDiscountApprovalChain(limits, "sales", 150000, 250)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sales_manager", "vp"}, roles(chain))
+
+	chain, err = buildDiscountApprovalChain(limits, "sales", 1000000, 500)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sales_manager", "vp", "director"}, roles(chain))
+
+	// An approver's own request skips their level
+	chain, err = buildDiscountApprovalChain(limits, models.DiscountApproverSalesManager, 300000, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vp"}, roles(chain))
+
+	// Roles without a limit go through the whole chain
+	chain, err = buildDiscountApprovalChain(limits, "crm", 10000, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sales_manager"}, roles(chain))
+
+	delete(limits, models.DiscountApproverDirector)
+	_, err = buildDiscountApprovalChain(limits, "sales", 1000000, 500)
+	assert.ErrorContains(t, err, "exceeds every approval limit")
+}
+
+// TestMergeDiscountLimits tests that a project's limit replaces the tenant-wide one
+func TestMergeDiscountLimits(t *testing.T) {
+	project := "p1"
+	merged := mergeDiscountLimits([]models.DiscountLimit{
+		{Role: "sales", MaxAmount: 50000},
+		{Role: "sales", ProjectID: &project, MaxAmount: 75000},
+		{Role: "vp", ProjectID: &project, MaxAmount: 400000},
+		{Role: "vp", MaxAmount: 500000},
+	})
+
+	assert.Len(t, merged, 2)
+	assert.Equal(t, 75000.0, merged["sales"].MaxAmount)
+	assert.Equal(t, 400000.0, merged["vp"].MaxAmount)
+}
+
+// TestResolveDiscount tests converting between an amount and a per-sqft discount
+func TestResolveDiscount(t *testing.T) {
+	amount, perSqft := resolveDiscount(0, 150, 1200)
+	assert.Equal(t, 180000.0, amount)
+	assert.Equal(t, 150.0, perSqft)
+
+	amount, perSqft = resolveDiscount(100000, 0, 1500)
+	assert.Equal(t, 100000.0, amount)
+	assert.Equal(t, 66.67, perSqft)
+
+	// Quotations have no area
+	amount, perSqft = resolveDiscount(25000, 0, 0)
+	assert.Equal(t, 25000.0, amount)
+	assert.Equal(t, 0.0, perSqft)
+}
+
+// TestOrderDiscountTotal tests adding up a sales order's discounts
+func TestOrderDiscountTotal(t *testing.T) {
+	lines := []SalesOrderLine{
+		{LineNumber: 1, ProductCode: "CEM", Quantity: 10, UnitPrice: 380, DiscountPercent: 5},
+		{LineNumber: 2, Quantity: 2, UnitPrice: 1000, DiscountAmount: 150},
+		{LineNumber: 3, ProductCode: "STL", Quantity: 1, UnitPrice: 900},
+	}
+	assert.Equal(t, 1340.0, orderDiscountTotal(1000, lines, nil))
+
+	// Line 1 is priced 20 below the quote, line 2 matches by line number at 50 below,
+	// and line 3 is priced above its quote
+	quoted := []SalesOrderLine{
+		{LineNumber: 1, ProductCode: "CEM", UnitPrice: 400},
+		{LineNumber: 2, UnitPrice: 1050},
+		{LineNumber: 3, ProductCode: "STL", UnitPrice: 850},
+	}
+	assert.Equal(t, 1640.0, orderDiscountTotal(1000, lines, quoted))
+}
+
+// TestCurrentDiscountStep tests finding the step awaiting action
+func TestCurrentDiscountStep(t *testing.T) {
+	dr := &models.DiscountRequest{
+		CurrentLevel: 1,
+		Steps: []models.DiscountApprovalStep{
+			{Level: 1, ApproverRole: "sales_manager", Status: models.DiscountStepPending},
+			{Level: 2, ApproverRole: "vp", Status: models.DiscountStepPending},
+		},
+	}
+
+	step, next := currentDiscountStep(dr)
+	assert.Equal(t, "sales_manager", step.ApproverRole)
+	assert.Equal(t, "vp", next.ApproverRole)
+
+	dr.Steps[0].Status = models.DiscountStepApproved
+	dr.CurrentLevel = 2
+	step, next = currentDiscountStep(dr)
+	assert.Equal(t, "vp", step.ApproverRole)
+	assert.Nil(t, next)
+
+	dr.CurrentLevel = 0
+	step, _ = currentDiscountStep(dr)
+	assert.Nil(t, step)
+}
+
+// TestBuildCostSheetDiscount tests that an approved discount reduces the agreement value and GST
+func TestBuildCostSheetDiscount(t *testing.T) {
+	sheet := &models.CostSheet{UnitID: "u1", Floor: 1, UnitType: "2BHK", SBUA: 1000}
+	assert.NoError(t, buildCostSheet(sheet, testPriceList(), &models.CostSheetOptions{DiscountAmount: 200000}))
+
+	last := sheet.Lines[len(sheet.Lines)-1]
+	assert.Equal(t, "DISCOUNT", last.Code)
+	assert.Equal(t, -200000.0, last.Amount)
+	assert.Equal(t, -10000.0, last.GSTAmount)
+	assert.Equal(t, 200000.0, sheet.Discount)
+
+	// 60L basic + 1L club - 2L discount
+	assert.Equal(t, 5900000.0, sheet.AgreementValue)
+	assert.Equal(t, 300000.0+18000-10000, sheet.TotalGST)
+
+	sheet = &models.CostSheet{UnitID: "u1", Floor: 1, SBUA: 1000}
+	assert.Error(t, buildCostSheet(sheet, testPriceList(), &models.CostSheetOptions{DiscountAmount: 6000000}))
+}
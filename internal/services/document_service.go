@@ -2,6 +2,8 @@ package services
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 	"vyomtech-backend/internal/models"
 )
 
@@ -242,3 +244,80 @@ func (s *DocumentService) UpdateDocumentComplianceStatus(tenantID, documentID in
 func (s *DocumentService) GetDocumentCompliance(tenantID, documentID int64) ([]models.DocumentCompliance, error) {
 	return []models.DocumentCompliance{}, nil
 }
+
+// ============================================================================
+// TEMPLATE RENDERING
+// ============================================================================
+
+// defaultTemplates are used when a tenant has not configured a template of the type
+var defaultTemplates = map[string]string{
+	models.TransferTemplateType: `ENDORSEMENT LETTER
+
+Date: {{date}}
+
+This is to record that the booking {{booking_reference}} of unit {{unit_number}} in {{project_name}}
+has been transferred with our consent from {{transferors}} to {{transferees}}.
+
+All dues up to the date of transfer have been settled and the transfer fee of Rs. {{transfer_fee}}
+plus GST of Rs. {{transfer_fee_gst}} has been received vide {{fee_reference}}.
+
+The transferees shall hold the unit as {{ownership_type}} under co-ownership agreement {{agreement_reference}},
+and all rights and obligations under the booking now rest with them.
+
+Authorised Signatory`,
+}
+
+// RenderTemplate fills the tenant's active template of the given type, or the
+// built-in default, with the supplied {{field}} values
+func (s *DocumentService) RenderTemplate(tenantID, templateType string, fields map[string]string) (string, error) {
+	var content string
+	err := s.DB.QueryRow(`SELECT COALESCE(template_content, '') FROM document_templates
+		WHERE tenant_id = ? AND template_type = ? AND is_active = TRUE AND deleted_at IS NULL
+		ORDER BY updated_at DESC LIMIT 1`, tenantID, templateType).Scan(&content)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to fetch document template: %w", err)
+	}
+	if content == "" {
+		content = defaultTemplates[templateType]
+	}
+	if content == "" {
+		return "", fmt.Errorf("no %s template configured", templateType)
+	}
+	return renderTemplate(content, fields), nil
+}
+
+// renderTemplate replaces each {{field}} placeholder with its value
+func renderTemplate(content string, fields map[string]string) string {
+	pairs := make([]string, 0, len(fields)*2)
+	for k, v := range fields {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(content)
+}
+
+// ============================================================================
+// ENTITY ATTACHMENTS
+// ============================================================================
+
+// RequireAttachments checks that every document is attached to the entity and
+// has not been deleted, rejected or allowed to expire
+func (s *DocumentService) RequireAttachments(tenantID, entityType, entityID string, documentIDs ...string) error {
+	for _, id := range documentIDs {
+		if id == "" {
+			return fmt.Errorf("document id is required")
+		}
+		var status string
+		err := s.DB.QueryRow(`SELECT document_status FROM documents
+			WHERE tenant_id = ? AND id = ? AND entity_type = ? AND entity_id = ? AND deleted_at IS NULL`,
+			tenantID, id, entityType, entityID).Scan(&status)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("document %s is not attached to %s %s", id, entityType, entityID)
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch document %s: %w", id, err)
+		}
+		if status == "rejected" || status == "expired" {
+			return fmt.Errorf("document %s is %s", id, status)
+		}
+	}
+	return nil
+}
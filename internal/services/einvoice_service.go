@@ -0,0 +1,870 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// GST E-INVOICE AND E-WAY BILL SERVICE
+// ============================================================================
+// Builds INV-01 documents from sales invoices and e-way bills from inventory
+// transfers, and registers them through the tenant's configured IRP client.
+
+// IRNs and e-way bills can only be cancelled within 24 hours of generation
+const eDocumentCancelWindow = 24 * time.Hour
+
+// irpRequestTimeout bounds a single call to the IRP / e-way bill system
+const irpRequestTimeout = 30 * time.Second
+
+type EInvoiceService struct {
+	DB *sql.DB
+	// NewClient resolves the IRP client for a tenant's settings; defaults to NewIRPClient
+	NewClient func(settings *models.EInvoiceSettings) (IRPClient, error)
+}
+
+func NewEInvoiceService(db *sql.DB) *EInvoiceService {
+	return &EInvoiceService{DB: db, NewClient: NewIRPClient}
+}
+
+// gstStateCodes maps state / UT names (lower case) to GST state codes
+var gstStateCodes = map[string]string{
+	"jammu and kashmir": "01", "himachal pradesh": "02", "punjab": "03", "chandigarh": "04",
+	"uttarakhand": "05", "haryana": "06", "delhi": "07", "rajasthan": "08", "uttar pradesh": "09",
+	"bihar": "10", "sikkim": "11", "arunachal pradesh": "12", "nagaland": "13", "manipur": "14",
+	"mizoram": "15", "tripura": "16", "meghalaya": "17", "assam": "18", "west bengal": "19",
+	"jharkhand": "20", "odisha": "21", "chhattisgarh": "22", "madhya pradesh": "23", "gujarat": "24",
+	"dadra and nagar haveli and daman and diu": "26", "maharashtra": "27", "karnataka": "29",
+	"goa": "30", "lakshadweep": "31", "kerala": "32", "tamil nadu": "33", "puducherry": "34",
+	"andaman and nicobar islands": "35", "telangana": "36", "andhra pradesh": "37", "ladakh": "38",
+}
+
+// gstStateCodeForName resolves a state name or an existing 2-digit code
+func gstStateCodeForName(state string) string {
+	state = strings.TrimSpace(state)
+	if len(state) == 2 && state[0] >= '0' && state[0] <= '9' {
+		return state
+	}
+	return gstStateCodes[strings.ToLower(state)]
+}
+
+// withinCancellationWindow reports whether a document generated at the given time can still be cancelled
+func withinCancellationWindow(generatedAt, now time.Time) bool {
+	return now.Sub(generatedAt) <= eDocumentCancelWindow
+}
+
+// ============================================================================
+// SETTINGS
+// ============================================================================
+
+// GetSettings returns the tenant's e-invoice settings
+func (s *EInvoiceService) GetSettings(tenantID string) (*models.EInvoiceSettings, error) {
+	var st models.EInvoiceSettings
+	err := s.DB.QueryRow(`SELECT id, tenant_id, provider, COALESCE(api_base_url, ''), COALESCE(client_id, ''),
+		COALESCE(client_secret, ''), COALESCE(username, ''), COALESCE(password, ''), seller_gstin, legal_name,
+		COALESCE(trade_name, ''), address1, COALESCE(address2, ''), location, pincode, state_code,
+		is_active, created_at, updated_at
+		FROM einvoice_settings WHERE tenant_id = ?`, tenantID).Scan(
+		&st.ID, &st.TenantID, &st.Provider, &st.APIBaseURL, &st.ClientID,
+		&st.ClientSecret, &st.Username, &st.Password, &st.SellerGSTIN, &st.LegalName,
+		&st.TradeName, &st.Address1, &st.Address2, &st.Location, &st.Pincode, &st.StateCode,
+		&st.IsActive, &st.CreatedAt, &st.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("e-invoice settings not configured")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get e-invoice settings: %w", err)
+	}
+	return &st, nil
+}
+
+// UpsertSettings sets the seller profile and IRP connection for a tenant
+func (s *EInvoiceService) UpsertSettings(tenantID string, req *models.UpsertEInvoiceSettingsRequest) (*models.EInvoiceSettings, error) {
+	gstin := strings.ToUpper(strings.TrimSpace(req.SellerGSTIN))
+	if !gstinPattern.MatchString(gstin) {
+		return nil, fmt.Errorf("seller_gstin %s is not a valid GSTIN", req.SellerGSTIN)
+	}
+	if req.Provider != models.IRPProviderStub && req.Provider != models.IRPProviderGSP {
+		return nil, fmt.Errorf("unsupported IRP provider: %s", req.Provider)
+	}
+	if req.Provider == models.IRPProviderGSP && req.APIBaseURL == "" {
+		return nil, fmt.Errorf("api_base_url is required for provider %s", req.Provider)
+	}
+	if req.Pincode < 100000 || req.Pincode > 999999 {
+		return nil, fmt.Errorf("pincode must be 6 digits")
+	}
+
+	st := &models.EInvoiceSettings{
+		TenantID:     tenantID,
+		Provider:     req.Provider,
+		APIBaseURL:   strings.TrimRight(req.APIBaseURL, "/"),
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		Username:     req.Username,
+		Password:     req.Password,
+		SellerGSTIN:  gstin,
+		LegalName:    req.LegalName,
+		TradeName:    req.TradeName,
+		Address1:     req.Address1,
+		Address2:     req.Address2,
+		Location:     req.Location,
+		Pincode:      req.Pincode,
+		StateCode:    gstStateCode(gstin),
+		IsActive:     true,
+		UpdatedAt:    time.Now(),
+	}
+
+	existing, err := s.GetSettings(tenantID)
+	if err == nil {
+		st.ID = existing.ID
+		st.CreatedAt = existing.CreatedAt
+		// Secrets are write-only; keep the stored ones when not resent
+		if st.ClientSecret == "" {
+			st.ClientSecret = existing.ClientSecret
+		}
+		if st.Password == "" {
+			st.Password = existing.Password
+		}
+		_, err = s.DB.Exec(`UPDATE einvoice_settings SET provider = ?, api_base_url = ?, client_id = ?,
+			client_secret = ?, username = ?, password = ?, seller_gstin = ?, legal_name = ?, trade_name = ?,
+			address1 = ?, address2 = ?, location = ?, pincode = ?, state_code = ?, is_active = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`,
+			st.Provider, st.APIBaseURL, st.ClientID, st.ClientSecret, st.Username, st.Password,
+			st.SellerGSTIN, st.LegalName, st.TradeName, st.Address1, st.Address2, st.Location,
+			st.Pincode, st.StateCode, st.IsActive, st.UpdatedAt, st.ID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update e-invoice settings: %w", err)
+		}
+		return st, nil
+	}
+
+	st.ID = uuid.New().String()
+	st.CreatedAt = st.UpdatedAt
+	_, err = s.DB.Exec(`INSERT INTO einvoice_settings
+		(id, tenant_id, provider, api_base_url, client_id, client_secret, username, password,
+		 seller_gstin, legal_name, trade_name, address1, address2, location, pincode, state_code,
+		 is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		st.ID, tenantID, st.Provider, st.APIBaseURL, st.ClientID, st.ClientSecret, st.Username, st.Password,
+		st.SellerGSTIN, st.LegalName, st.TradeName, st.Address1, st.Address2, st.Location, st.Pincode,
+		st.StateCode, st.IsActive, st.CreatedAt, st.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create e-invoice settings: %w", err)
+	}
+	return st, nil
+}
+
+func (s *EInvoiceService) client(tenantID string) (*models.EInvoiceSettings, IRPClient, error) {
+	settings, err := s.GetSettings(tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !settings.IsActive {
+		return nil, nil, fmt.Errorf("e-invoicing is disabled for this tenant")
+	}
+	client, err := s.NewClient(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return settings, client, nil
+}
+
+// ============================================================================
+// E-INVOICE (IRN)
+// ============================================================================
+
+// einvoiceBuyer is the customer detail INV-01 needs
+type einvoiceBuyer struct {
+	Name          string
+	TradeName     string
+	GSTIN         string
+	Address       string
+	City          string
+	State         string
+	Pincode       string
+	ShippingState string
+}
+
+// BuildEInvoicePayload returns the INV-01 document for a sales invoice without registering it
+func (s *EInvoiceService) BuildEInvoicePayload(tenantID, invoiceID string) (*models.EInvoicePayload, error) {
+	settings, err := s.GetSettings(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.getSalesInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	buyer, err := s.getBuyer(tenantID, invoice.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEInvoicePayload(settings, invoice, buyer)
+}
+
+// GenerateIRN registers a B2B sales invoice with the IRP and stores the IRN, ack and signed QR
+func (s *EInvoiceService) GenerateIRN(tenantID, invoiceID, userID string) (*models.SalesEInvoice, error) {
+	existing, err := s.GetEInvoice(tenantID, invoiceID)
+	if err == nil && existing.Status == models.EDocumentStatusGenerated {
+		return nil, fmt.Errorf("IRN already generated for invoice %s", existing.InvoiceNumber)
+	}
+
+	_, client, err := s.client(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.BuildEInvoicePayload(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	requestJSON, _ := json.Marshal(payload)
+
+	now := time.Now()
+	einv := &models.SalesEInvoice{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		InvoiceID:     invoiceID,
+		InvoiceNumber: payload.DocDtls.No,
+		Status:        models.EDocumentStatusGenerated,
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), irpRequestTimeout)
+	defer cancel()
+	resp, irpErr := client.GenerateIRN(ctx, payload)
+	if irpErr != nil {
+		einv.Status = models.EDocumentStatusFailed
+		einv.ErrorMessage = irpErr.Error()
+	} else {
+		einv.IRN = resp.Irn
+		einv.AckNo = strconv.FormatInt(resp.AckNo, 10)
+		einv.SignedInvoice = resp.SignedInvoice
+		einv.SignedQRCode = resp.SignedQRCode
+		if ackDate, err := time.ParseInLocation(irpDateTimeLayout, resp.AckDt, time.Local); err == nil {
+			einv.AckDate = &ackDate
+		} else {
+			einv.AckDate = &now
+		}
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO sales_einvoices
+		(id, tenant_id, invoice_id, invoice_number, irn, ack_no, ack_date, signed_invoice, signed_qr_code,
+		 status, error_message, request_payload, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		einv.ID, tenantID, invoiceID, einv.InvoiceNumber, nullIfEmpty(einv.IRN), nullIfEmpty(einv.AckNo),
+		einv.AckDate, nullIfEmpty(einv.SignedInvoice), nullIfEmpty(einv.SignedQRCode), einv.Status,
+		nullIfEmpty(einv.ErrorMessage), string(requestJSON), userID, einv.CreatedAt, einv.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save e-invoice: %w", err)
+	}
+
+	if irpErr != nil {
+		return nil, fmt.Errorf("failed to generate IRN: %w", irpErr)
+	}
+	return einv, nil
+}
+
+// GetEInvoice returns the latest e-invoice record for a sales invoice
+func (s *EInvoiceService) GetEInvoice(tenantID, invoiceID string) (*models.SalesEInvoice, error) {
+	var e models.SalesEInvoice
+	var ackDate, cancelledAt sql.NullTime
+	err := s.DB.QueryRow(`SELECT id, tenant_id, invoice_id, invoice_number, COALESCE(irn, ''), COALESCE(ack_no, ''),
+		ack_date, COALESCE(signed_invoice, ''), COALESCE(signed_qr_code, ''), status, COALESCE(error_message, ''),
+		COALESCE(cancel_reason_code, ''), COALESCE(cancel_remarks, ''), cancelled_at, created_by, created_at, updated_at
+		FROM sales_einvoices WHERE tenant_id = ? AND invoice_id = ?
+		ORDER BY created_at DESC LIMIT 1`, tenantID, invoiceID).Scan(
+		&e.ID, &e.TenantID, &e.InvoiceID, &e.InvoiceNumber, &e.IRN, &e.AckNo,
+		&ackDate, &e.SignedInvoice, &e.SignedQRCode, &e.Status, &e.ErrorMessage,
+		&e.CancelReasonCode, &e.CancelRemarks, &cancelledAt, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("e-invoice not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get e-invoice: %w", err)
+	}
+	if ackDate.Valid {
+		e.AckDate = &ackDate.Time
+	}
+	if cancelledAt.Valid {
+		e.CancelledAt = &cancelledAt.Time
+	}
+	return &e, nil
+}
+
+// CancelIRN cancels a registered IRN; the IRP only allows this within 24 hours of acknowledgement
+func (s *EInvoiceService) CancelIRN(tenantID, invoiceID, userID string, req *models.CancelIRNRequest) (*models.SalesEInvoice, error) {
+	switch req.ReasonCode {
+	case models.IRNCancelDuplicate, models.IRNCancelDataEntry, models.IRNCancelOrderCancelled, models.IRNCancelOthers:
+	default:
+		return nil, fmt.Errorf("invalid cancellation reason code %q", req.ReasonCode)
+	}
+	if strings.TrimSpace(req.Remarks) == "" {
+		return nil, fmt.Errorf("cancellation remarks are required")
+	}
+
+	einv, err := s.GetEInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if einv.Status != models.EDocumentStatusGenerated {
+		return nil, fmt.Errorf("e-invoice is %s and cannot be cancelled", einv.Status)
+	}
+	now := time.Now()
+	if einv.AckDate == nil || !withinCancellationWindow(*einv.AckDate, now) {
+		return nil, fmt.Errorf("IRN can only be cancelled within 24 hours of generation; issue a credit note instead")
+	}
+
+	_, client, err := s.client(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), irpRequestTimeout)
+	defer cancel()
+	if _, err := client.CancelIRN(ctx, einv.IRN, req.ReasonCode, req.Remarks); err != nil {
+		return nil, fmt.Errorf("failed to cancel IRN: %w", err)
+	}
+
+	_, err = s.DB.Exec(`UPDATE sales_einvoices SET status = ?, cancel_reason_code = ?, cancel_remarks = ?,
+		cancelled_at = ?, cancelled_by = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		models.EDocumentStatusCancelled, req.ReasonCode, req.Remarks, now, userID, now, einv.ID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update e-invoice: %w", err)
+	}
+
+	einv.Status = models.EDocumentStatusCancelled
+	einv.CancelReasonCode = req.ReasonCode
+	einv.CancelRemarks = req.Remarks
+	einv.CancelledAt = &now
+	einv.UpdatedAt = now
+	return einv, nil
+}
+
+func (s *EInvoiceService) getSalesInvoice(tenantID, invoiceID string) (*models.SalesInvoice, error) {
+	var inv models.SalesInvoice
+	err := s.DB.QueryRow(`SELECT id, tenant_id, invoice_number, customer_id, invoice_date,
+		subtotal_amount, discount_amount, cgst_amount, sgst_amount, igst_amount, total_tax, total_amount,
+		document_status
+		FROM sales_invoices WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`,
+		invoiceID, tenantID).Scan(
+		&inv.ID, &inv.TenantID, &inv.InvoiceNumber, &inv.CustomerID, &inv.InvoiceDate,
+		&inv.SubtotalAmount, &inv.DiscountAmount, &inv.CGSTAmount, &inv.SGSTAmount, &inv.IGSTAmount,
+		&inv.TotalTax, &inv.TotalAmount, &inv.DocumentStatus)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	rows, err := s.DB.Query(`SELECT id, line_number, COALESCE(description, ''), COALESCE(hsn_code, ''), quantity,
+		unit_price, line_total, COALESCE(discount_amount, 0), COALESCE(cgst_rate, 0), COALESCE(cgst_amount, 0),
+		COALESCE(sgst_rate, 0), COALESCE(sgst_amount, 0), COALESCE(igst_rate, 0), COALESCE(igst_amount, 0)
+		FROM sales_invoice_items WHERE invoice_id = ? AND tenant_id = ? ORDER BY line_number`,
+		invoiceID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.SalesInvoiceItem
+		if err := rows.Scan(&item.ID, &item.LineNumber, &item.Description, &item.HSNCode, &item.Quantity,
+			&item.UnitPrice, &item.LineTotal, &item.DiscountAmount, &item.CGSTRate, &item.CGSTAmount,
+			&item.SGSTRate, &item.SGSTAmount, &item.IGSTRate, &item.IGSTAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice item: %w", err)
+		}
+		inv.Items = append(inv.Items, item)
+	}
+	return &inv, rows.Err()
+}
+
+func (s *EInvoiceService) getBuyer(tenantID, customerID string) (*einvoiceBuyer, error) {
+	var b einvoiceBuyer
+	err := s.DB.QueryRow(`SELECT customer_name, COALESCE(business_name, ''), COALESCE(gst_number, ''),
+		COALESCE(billing_address, ''), COALESCE(billing_city, ''), COALESCE(billing_state, ''),
+		COALESCE(billing_zip, ''), COALESCE(shipping_state, '')
+		FROM sales_customers WHERE id = ? AND tenant_id = ?`, customerID, tenantID).Scan(
+		&b.Name, &b.TradeName, &b.GSTIN, &b.Address, &b.City, &b.State, &b.Pincode, &b.ShippingState)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("customer not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	return &b, nil
+}
+
+// buildEInvoicePayload maps a sales invoice to INV-01 and validates the fields the IRP rejects on
+func buildEInvoicePayload(settings *models.EInvoiceSettings, invoice *models.SalesInvoice, buyer *einvoiceBuyer) (*models.EInvoicePayload, error) {
+	if invoice.DocumentStatus == "cancelled" {
+		return nil, fmt.Errorf("invoice %s is cancelled", invoice.InvoiceNumber)
+	}
+	buyerGSTIN := strings.ToUpper(strings.TrimSpace(buyer.GSTIN))
+	if buyerGSTIN == "" {
+		return nil, fmt.Errorf("customer GSTIN is required for a B2B e-invoice")
+	}
+	if !gstinPattern.MatchString(buyerGSTIN) {
+		return nil, fmt.Errorf("customer GSTIN %s is not a valid GSTIN", buyerGSTIN)
+	}
+	buyerPin, err := strconv.Atoi(strings.TrimSpace(buyer.Pincode))
+	if err != nil || buyerPin < 100000 || buyerPin > 999999 {
+		return nil, fmt.Errorf("customer billing pincode %q is not a valid 6-digit pincode", buyer.Pincode)
+	}
+	if len(invoice.Items) == 0 {
+		return nil, fmt.Errorf("invoice %s has no line items", invoice.InvoiceNumber)
+	}
+
+	pos := gstStateCodeForName(buyer.ShippingState)
+	if pos == "" {
+		pos = gstStateCode(buyerGSTIN)
+	}
+
+	payload := &models.EInvoicePayload{
+		Version:  "1.1",
+		TranDtls: models.EInvoiceTranDetails{TaxSch: "GST", SupTyp: "B2B", RegRev: "N", IgstOnIntra: "N"},
+		DocDtls: models.EInvoiceDocDetails{
+			Typ: models.GSTDocumentInvoice,
+			No:  invoice.InvoiceNumber,
+			Dt:  invoice.InvoiceDate.Format("02/01/2006"),
+		},
+		SellerDtls: models.EInvoiceParty{
+			Gstin: settings.SellerGSTIN,
+			LglNm: settings.LegalName,
+			TrdNm: settings.TradeName,
+			Addr1: settings.Address1,
+			Addr2: settings.Address2,
+			Loc:   settings.Location,
+			Pin:   settings.Pincode,
+			Stcd:  settings.StateCode,
+		},
+		BuyerDtls: models.EInvoiceParty{
+			Gstin: buyerGSTIN,
+			LglNm: buyer.Name,
+			TrdNm: buyer.TradeName,
+			Pos:   pos,
+			Addr1: buyer.Address,
+			Loc:   buyer.City,
+			Pin:   buyerPin,
+			Stcd:  gstStateCode(buyerGSTIN),
+		},
+	}
+
+	val := &payload.ValDtls
+	for i, item := range invoice.Items {
+		if item.HSNCode == "" {
+			return nil, fmt.Errorf("line %d has no HSN/SAC code", i+1)
+		}
+		isService := "N"
+		if strings.HasPrefix(item.HSNCode, "99") {
+			isService = "Y"
+		}
+		total := roundTo2(item.Quantity * item.UnitPrice)
+		assessable := roundTo2(total - item.DiscountAmount)
+		rate := item.IGSTRate
+		if item.IGSTAmount == 0 {
+			rate = item.CGSTRate + item.SGSTRate
+		}
+
+		line := models.EInvoiceItem{
+			SlNo:       strconv.Itoa(i + 1),
+			PrdDesc:    item.Description,
+			IsServc:    isService,
+			HsnCd:      item.HSNCode,
+			Qty:        item.Quantity,
+			Unit:       "NOS",
+			UnitPrice:  item.UnitPrice,
+			TotAmt:     total,
+			Discount:   roundTo2(item.DiscountAmount),
+			AssAmt:     assessable,
+			GstRt:      rate,
+			IgstAmt:    roundTo2(item.IGSTAmount),
+			CgstAmt:    roundTo2(item.CGSTAmount),
+			SgstAmt:    roundTo2(item.SGSTAmount),
+			TotItemVal: roundTo2(assessable + item.IGSTAmount + item.CGSTAmount + item.SGSTAmount),
+		}
+		if isService == "Y" {
+			line.Unit = "OTH"
+		}
+		payload.ItemList = append(payload.ItemList, line)
+
+		val.AssVal = roundTo2(val.AssVal + line.AssAmt)
+		val.IgstVal = roundTo2(val.IgstVal + line.IgstAmt)
+		val.CgstVal = roundTo2(val.CgstVal + line.CgstAmt)
+		val.SgstVal = roundTo2(val.SgstVal + line.SgstAmt)
+	}
+
+	// Invoice-level discount sits outside the line items
+	val.Discount = roundTo2(invoice.DiscountAmount)
+	val.TotInvVal = roundTo2(val.AssVal + val.IgstVal + val.CgstVal + val.SgstVal - val.Discount)
+
+	return payload, nil
+}
+
+// ============================================================================
+// E-WAY BILL
+// ============================================================================
+
+// ewbSite is a warehouse / site end of a stock movement
+type ewbSite struct {
+	Name    string
+	Address string
+	City    string
+	State   string
+	Pincode string
+}
+
+// ewbLine is a line of a stock movement
+type ewbLine struct {
+	Name     string
+	Desc     string
+	HSNCode  string
+	Unit     string
+	Quantity float64
+	UnitCost float64
+}
+
+// GenerateTransferEWayBill raises an e-way bill for moving material between sites
+func (s *EInvoiceService) GenerateTransferEWayBill(tenantID, transferID, userID string, req *models.GenerateTransferEWayBillRequest) (*models.EWayBill, error) {
+	var existing int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM eway_bills WHERE tenant_id = ? AND source_type = 'inventory_transfer'
+		AND source_id = ? AND status = ?`, tenantID, transferID, models.EDocumentStatusGenerated).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to check e-way bills: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("an active e-way bill already exists for this transfer")
+	}
+
+	settings, client, err := s.client(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transferNumber, status, fromID, toID string
+	var transferDate time.Time
+	err = s.DB.QueryRow(`SELECT transfer_number, transfer_date, COALESCE(transfer_status, 'draft'), from_warehouse_id, to_warehouse_id
+		FROM inventory_transfer WHERE id = ? AND tenant_id = ?`, transferID, tenantID).Scan(
+		&transferNumber, &transferDate, &status, &fromID, &toID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transfer not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	if status == "cancelled" {
+		return nil, fmt.Errorf("transfer %s is cancelled", transferNumber)
+	}
+
+	from, err := s.getSite(tenantID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getSite(tenantID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(`SELECT i.item_name, COALESCE(i.item_description, ''), COALESCE(i.hsn_code, ''),
+		COALESCE(i.unit_of_measure, ''), COALESCE(l.quantity_transferred, 0), COALESCE(l.unit_cost, 0)
+		FROM inventory_transfer_line l JOIN inventory_item i ON i.id = l.inventory_item_id AND i.tenant_id = l.tenant_id
+		WHERE l.transfer_id = ? AND l.tenant_id = ? ORDER BY l.line_number`, transferID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer lines: %w", err)
+	}
+	defer rows.Close()
+	var lines []ewbLine
+	for rows.Next() {
+		var l ewbLine
+		if err := rows.Scan(&l.Name, &l.Desc, &l.HSNCode, &l.Unit, &l.Quantity, &l.UnitCost); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer line: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	payload, err := buildTransferEWayBillPayload(settings, transferNumber, transferDate, from, to, lines, req)
+	if err != nil {
+		return nil, err
+	}
+	requestJSON, _ := json.Marshal(payload)
+
+	now := time.Now()
+	ewb := &models.EWayBill{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		SourceType:     "inventory_transfer",
+		SourceID:       transferID,
+		DocumentNumber: transferNumber,
+		VehicleNo:      payload.VehicleNo,
+		TransportMode:  payload.TransMode,
+		DistanceKm:     req.DistanceKm,
+		TotalValue:     payload.TotInvValue,
+		Status:         models.EDocumentStatusGenerated,
+		CreatedBy:      userID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), irpRequestTimeout)
+	defer cancel()
+	resp, ewbErr := client.GenerateEWayBill(ctx, payload)
+	if ewbErr != nil {
+		ewb.Status = models.EDocumentStatusFailed
+		ewb.ErrorMessage = ewbErr.Error()
+	} else {
+		ewb.EWayBillNo = strconv.FormatInt(resp.EWayBillNo, 10)
+		if d, err := time.ParseInLocation(ewbDateTimeLayout, resp.EWayBillDate, time.Local); err == nil {
+			ewb.EWayBillDate = &d
+		} else {
+			ewb.EWayBillDate = &now
+		}
+		if d, err := time.ParseInLocation(ewbDateTimeLayout, resp.ValidUpto, time.Local); err == nil {
+			ewb.ValidUpto = &d
+		}
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO eway_bills
+		(id, tenant_id, source_type, source_id, document_number, eway_bill_no, eway_bill_date, valid_upto,
+		 vehicle_no, transport_mode, distance_km, total_value, status, error_message, request_payload,
+		 created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ewb.ID, tenantID, ewb.SourceType, ewb.SourceID, ewb.DocumentNumber, nullIfEmpty(ewb.EWayBillNo),
+		ewb.EWayBillDate, ewb.ValidUpto, nullIfEmpty(ewb.VehicleNo), ewb.TransportMode, ewb.DistanceKm,
+		ewb.TotalValue, ewb.Status, nullIfEmpty(ewb.ErrorMessage), string(requestJSON),
+		userID, ewb.CreatedAt, ewb.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save e-way bill: %w", err)
+	}
+
+	if ewbErr != nil {
+		return nil, fmt.Errorf("failed to generate e-way bill: %w", ewbErr)
+	}
+	return ewb, nil
+}
+
+// GetEWayBill returns an e-way bill
+func (s *EInvoiceService) GetEWayBill(tenantID, id string) (*models.EWayBill, error) {
+	var e models.EWayBill
+	var ewbDate, validUpto, cancelledAt sql.NullTime
+	err := s.DB.QueryRow(`SELECT id, tenant_id, source_type, source_id, document_number, COALESCE(eway_bill_no, ''),
+		eway_bill_date, valid_upto, COALESCE(vehicle_no, ''), transport_mode, distance_km, total_value, status,
+		COALESCE(error_message, ''), COALESCE(cancel_reason, ''), cancelled_at, created_by, created_at, updated_at
+		FROM eway_bills WHERE id = ? AND tenant_id = ?`, id, tenantID).Scan(
+		&e.ID, &e.TenantID, &e.SourceType, &e.SourceID, &e.DocumentNumber, &e.EWayBillNo,
+		&ewbDate, &validUpto, &e.VehicleNo, &e.TransportMode, &e.DistanceKm, &e.TotalValue, &e.Status,
+		&e.ErrorMessage, &e.CancelReason, &cancelledAt, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("e-way bill not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get e-way bill: %w", err)
+	}
+	if ewbDate.Valid {
+		e.EWayBillDate = &ewbDate.Time
+	}
+	if validUpto.Valid {
+		e.ValidUpto = &validUpto.Time
+	}
+	if cancelledAt.Valid {
+		e.CancelledAt = &cancelledAt.Time
+	}
+	return &e, nil
+}
+
+// CancelEWayBill cancels an e-way bill within 24 hours of generation
+func (s *EInvoiceService) CancelEWayBill(tenantID, id, userID string, req *models.CancelEWayBillRequest) (*models.EWayBill, error) {
+	if req.ReasonCode < 1 || req.ReasonCode > 4 {
+		return nil, fmt.Errorf("invalid cancellation reason code %d", req.ReasonCode)
+	}
+	if strings.TrimSpace(req.Remarks) == "" {
+		return nil, fmt.Errorf("cancellation remarks are required")
+	}
+
+	ewb, err := s.GetEWayBill(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if ewb.Status != models.EDocumentStatusGenerated {
+		return nil, fmt.Errorf("e-way bill is %s and cannot be cancelled", ewb.Status)
+	}
+	now := time.Now()
+	if ewb.EWayBillDate == nil || !withinCancellationWindow(*ewb.EWayBillDate, now) {
+		return nil, fmt.Errorf("e-way bill can only be cancelled within 24 hours of generation")
+	}
+
+	ewbNo, err := strconv.ParseInt(ewb.EWayBillNo, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e-way bill number %s", ewb.EWayBillNo)
+	}
+
+	_, client, err := s.client(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), irpRequestTimeout)
+	defer cancel()
+	if _, err := client.CancelEWayBill(ctx, ewbNo, req.ReasonCode, req.Remarks); err != nil {
+		return nil, fmt.Errorf("failed to cancel e-way bill: %w", err)
+	}
+
+	reason := fmt.Sprintf("%d: %s", req.ReasonCode, req.Remarks)
+	_, err = s.DB.Exec(`UPDATE eway_bills SET status = ?, cancel_reason = ?, cancelled_at = ?, cancelled_by = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`,
+		models.EDocumentStatusCancelled, reason, now, userID, now, id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update e-way bill: %w", err)
+	}
+
+	ewb.Status = models.EDocumentStatusCancelled
+	ewb.CancelReason = reason
+	ewb.CancelledAt = &now
+	ewb.UpdatedAt = now
+	return ewb, nil
+}
+
+func (s *EInvoiceService) getSite(tenantID, warehouseID string) (*ewbSite, error) {
+	var site ewbSite
+	err := s.DB.QueryRow(`SELECT warehouse_name, COALESCE(address, ''), COALESCE(city, ''), COALESCE(state, ''),
+		COALESCE(postal_code, '') FROM warehouse WHERE id = ? AND tenant_id = ?`, warehouseID, tenantID).Scan(
+		&site.Name, &site.Address, &site.City, &site.State, &site.Pincode)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("warehouse not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	return &site, nil
+}
+
+// buildTransferEWayBillPayload maps a stock transfer between sites to an e-way bill (delivery challan, own use)
+func buildTransferEWayBillPayload(settings *models.EInvoiceSettings, transferNumber string, transferDate time.Time, from, to *ewbSite, lines []ewbLine, req *models.GenerateTransferEWayBillRequest) (*models.EWayBillPayload, error) {
+	if req.DistanceKm <= 0 || req.DistanceKm > 4000 {
+		return nil, fmt.Errorf("distance_km must be between 1 and 4000")
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("transfer %s has no lines", transferNumber)
+	}
+
+	fromState := gstStateCodeForName(from.State)
+	toState := gstStateCodeForName(to.State)
+	if fromState == "" || toState == "" {
+		return nil, fmt.Errorf("state is missing or unknown for %s or %s", from.Name, to.Name)
+	}
+	if fromState != settings.StateCode {
+		return nil, fmt.Errorf("source site %s is outside the state of GSTIN %s", from.Name, settings.SellerGSTIN)
+	}
+	fromPin, err := strconv.Atoi(strings.TrimSpace(from.Pincode))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pincode for %s", from.Name)
+	}
+	toPin, err := strconv.Atoi(strings.TrimSpace(to.Pincode))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pincode for %s", to.Name)
+	}
+
+	toGSTIN := strings.ToUpper(strings.TrimSpace(req.ToGSTIN))
+	if toGSTIN == "" {
+		if toState != fromState {
+			return nil, fmt.Errorf("to_gstin is required for an inter-state transfer")
+		}
+		toGSTIN = settings.SellerGSTIN
+	}
+	if !gstinPattern.MatchString(toGSTIN) {
+		return nil, fmt.Errorf("to_gstin %s is not a valid GSTIN", toGSTIN)
+	}
+
+	mode := req.TransportMode
+	if mode == "" {
+		mode = "1"
+	}
+	if mode == "1" && req.VehicleNo == "" && req.TransporterID == "" {
+		return nil, fmt.Errorf("vehicle_no or transporter_id is required for road transport")
+	}
+
+	fromStateCode, _ := strconv.Atoi(fromState)
+	toStateCode, _ := strconv.Atoi(toState)
+	interState := fromState != toState
+
+	payload := &models.EWayBillPayload{
+		SupplyType:       "O",
+		SubSupplyType:    "5",
+		DocType:          "CHL",
+		DocNo:            transferNumber,
+		DocDate:          transferDate.Format("02/01/2006"),
+		FromGSTIN:        settings.SellerGSTIN,
+		FromTradeName:    from.Name,
+		FromAddr1:        from.Address,
+		FromPlace:        from.City,
+		FromPincode:      fromPin,
+		ActFromStateCode: fromStateCode,
+		FromStateCode:    fromStateCode,
+		ToGSTIN:          toGSTIN,
+		ToTradeName:      to.Name,
+		ToAddr1:          to.Address,
+		ToPlace:          to.City,
+		ToPincode:        toPin,
+		ActToStateCode:   toStateCode,
+		ToStateCode:      toStateCode,
+		TransactionType:  1,
+		TransporterID:    req.TransporterID,
+		TransporterName:  req.TransporterName,
+		TransMode:        mode,
+		TransDistance:    strconv.Itoa(req.DistanceKm),
+		VehicleNo:        strings.ToUpper(strings.ReplaceAll(req.VehicleNo, " ", "")),
+	}
+	if payload.VehicleNo != "" {
+		payload.VehicleType = "R"
+	}
+
+	for _, l := range lines {
+		if l.HSNCode == "" {
+			return nil, fmt.Errorf("item %s has no HSN code", l.Name)
+		}
+		taxable := roundTo2(l.Quantity * l.UnitCost)
+		item := models.EWayBillItem{
+			ProductName:   l.Name,
+			ProductDesc:   l.Desc,
+			HSNCode:       l.HSNCode,
+			Quantity:      l.Quantity,
+			QtyUnit:       strings.ToUpper(l.Unit),
+			TaxableAmount: taxable,
+		}
+		if item.QtyUnit == "" {
+			item.QtyUnit = "OTH"
+		}
+		if req.GSTRate > 0 {
+			if interState {
+				item.IGSTRate = req.GSTRate
+				payload.IGSTValue = roundTo2(payload.IGSTValue + taxable*req.GSTRate/100)
+			} else {
+				item.CGSTRate = req.GSTRate / 2
+				item.SGSTRate = req.GSTRate / 2
+				payload.CGSTValue = roundTo2(payload.CGSTValue + taxable*req.GSTRate/200)
+				payload.SGSTValue = roundTo2(payload.SGSTValue + taxable*req.GSTRate/200)
+			}
+		}
+		payload.ItemList = append(payload.ItemList, item)
+		payload.TotalValue = roundTo2(payload.TotalValue + taxable)
+	}
+	payload.TotInvValue = roundTo2(payload.TotalValue + payload.IGSTValue + payload.CGSTValue + payload.SGSTValue)
+
+	return payload, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEInvoiceSettings() *models.EInvoiceSettings {
+	return &models.EInvoiceSettings{
+		Provider:    models.IRPProviderStub,
+		SellerGSTIN: "27AAACV1234F1Z5",
+		LegalName:   "Vyom Developers Pvt Ltd",
+		Address1:    "Baner Road",
+		Location:    "Pune",
+		Pincode:     411045,
+		StateCode:   "27",
+	}
+}
+
+// TestBuildEInvoicePayload tests INV-01 mapping and totals
+func TestBuildEInvoicePayload(t *testing.T) {
+	invoice := &models.SalesInvoice{
+		InvoiceNumber:  "INV-20250710-1",
+		InvoiceDate:    time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC),
+		DiscountAmount: 0,
+		Items: []models.SalesInvoiceItem{
+			{Description: "Commercial unit C-101", HSNCode: "995411", Quantity: 1, UnitPrice: 5000000,
+				DiscountAmount: 100000, CGSTRate: 9, CGSTAmount: 441000, SGSTRate: 9, SGSTAmount: 441000},
+			{Description: "TMT steel resale", HSNCode: "7214", Quantity: 2, UnitPrice: 50000,
+				CGSTRate: 9, CGSTAmount: 9000, SGSTRate: 9, SGSTAmount: 9000},
+		},
+	}
+	buyer := &einvoiceBuyer{Name: "Acme Retail LLP", GSTIN: "27AABCB5678K1Z2", Address: "MG Road", City: "Pune", Pincode: "411001"}
+
+	payload, err := buildEInvoicePayload(testEInvoiceSettings(), invoice, buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, "B2B", payload.TranDtls.SupTyp)
+	assert.Equal(t, "10/07/2025", payload.DocDtls.Dt)
+	assert.Equal(t, "27", payload.BuyerDtls.Pos)
+	assert.Equal(t, 411001, payload.BuyerDtls.Pin)
+
+	assert.Len(t, payload.ItemList, 2)
+	assert.Equal(t, "Y", payload.ItemList[0].IsServc)
+	assert.Equal(t, "OTH", payload.ItemList[0].Unit)
+	assert.Equal(t, 4900000.0, payload.ItemList[0].AssAmt)
+	assert.Equal(t, 18.0, payload.ItemList[0].GstRt)
+	assert.Equal(t, 5782000.0, payload.ItemList[0].TotItemVal)
+	assert.Equal(t, "N", payload.ItemList[1].IsServc)
+
+	assert.Equal(t, 5000000.0, payload.ValDtls.AssVal)
+	assert.Equal(t, 450000.0, payload.ValDtls.CgstVal)
+	assert.Equal(t, 5900000.0, payload.ValDtls.TotInvVal)
+
+	// B2B needs a registered buyer
+	buyer.GSTIN = ""
+	_, err = buildEInvoicePayload(testEInvoiceSettings(), invoice, buyer)
+	assert.Error(t, err)
+}
+
+// TestStubIRPClient tests IRN derivation and cancellation window
+func TestStubIRPClient(t *testing.T) {
+	client := NewStubIRPClient()
+	payload := &models.EInvoicePayload{
+		DocDtls:    models.EInvoiceDocDetails{Typ: "INV", No: "INV-1", Dt: "10/02/2026"},
+		SellerDtls: models.EInvoiceParty{Gstin: "27AAACV1234F1Z5"},
+	}
+
+	resp, err := client.GenerateIRN(context.Background(), payload)
+	assert.NoError(t, err)
+	assert.Equal(t, computeIRN("27AAACV1234F1Z5", "2025-26", "INV", "INV-1"), resp.Irn)
+	assert.Len(t, resp.Irn, 64)
+	assert.NotEmpty(t, resp.SignedQRCode)
+
+	generated := time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC)
+	assert.True(t, withinCancellationWindow(generated, generated.Add(23*time.Hour)))
+	assert.False(t, withinCancellationWindow(generated, generated.Add(25*time.Hour)))
+}
+
+// TestGSTFinancialYear tests April-March financial years
+func TestGSTFinancialYear(t *testing.T) {
+	assert.Equal(t, "2025-26", gstFinancialYear(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-25", gstFinancialYear(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2099-00", gstFinancialYear(time.Date(2099, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestBuildTransferEWayBillPayload tests site-to-site e-way bills
+func TestBuildTransferEWayBillPayload(t *testing.T) {
+	from := &ewbSite{Name: "Central Store", Address: "Hinjewadi", City: "Pune", State: "Maharashtra", Pincode: "411057"}
+	to := &ewbSite{Name: "Site B", Address: "Thane West", City: "Thane", State: "maharashtra", Pincode: "400601"}
+	lines := []ewbLine{{Name: "Cement OPC 53", HSNCode: "2523", Unit: "bag", Quantity: 200, UnitCost: 380}}
+	date := time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)
+
+	payload, err := buildTransferEWayBillPayload(testEInvoiceSettings(), "TRF-001", date, from, to, lines,
+		&models.GenerateTransferEWayBillRequest{DistanceKm: 160, VehicleNo: "MH 12 AB 1234"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CHL", payload.DocType)
+	assert.Equal(t, "27AAACV1234F1Z5", payload.ToGSTIN)
+	assert.Equal(t, 27, payload.ToStateCode)
+	assert.Equal(t, "MH12AB1234", payload.VehicleNo)
+	assert.Equal(t, 76000.0, payload.TotInvValue)
+	assert.Equal(t, "BAG", payload.ItemList[0].QtyUnit)
+
+	// Inter-state movement needs the receiving registration
+	to.State = "Karnataka"
+	_, err = buildTransferEWayBillPayload(testEInvoiceSettings(), "TRF-002", date, from, to, lines,
+		&models.GenerateTransferEWayBillRequest{DistanceKm: 840, VehicleNo: "MH12AB1234"})
+	assert.Error(t, err)
+
+	payload, err = buildTransferEWayBillPayload(testEInvoiceSettings(), "TRF-002", date, from, to, lines,
+		&models.GenerateTransferEWayBillRequest{DistanceKm: 840, VehicleNo: "MH12AB1234", ToGSTIN: "29AAACV1234F1Z3", GSTRate: 28})
+	assert.NoError(t, err)
+	assert.Equal(t, 21280.0, payload.IGSTValue)
+	assert.Equal(t, 5, ewbValidityDays(840))
+}
@@ -117,8 +117,20 @@ func (s *GLService) ListAccounts(tenantID string, accountType string) ([]models.
 // JOURNAL ENTRY MANAGEMENT
 // ============================================================================
 
+// glExecer is satisfied by *sql.DB and *sql.Tx, so entries can be posted inside
+// the transaction that saves the document they belong to
+type glExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 // CreateJournalEntry creates a new journal entry
 func (s *GLService) CreateJournalEntry(tenantID string, entry *models.JournalEntry) error {
+	return createJournalEntry(s.DB, tenantID, entry)
+}
+
+func createJournalEntry(db glExecer, tenantID string, entry *models.JournalEntry) error {
 	entry.TenantID = tenantID
 	entry.EntryStatus = "Draft"
 	entry.CreatedAt = time.Now()
@@ -129,7 +141,7 @@ func (s *GLService) CreateJournalEntry(tenantID string, entry *models.JournalEnt
 		description, amount, narration, entry_status, created_at, updated_at
 	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := s.DB.Exec(query,
+	_, err := db.Exec(query,
 		entry.ID, entry.TenantID, entry.EntryDate, entry.ReferenceNumber, entry.ReferenceType,
 		entry.ReferenceID, entry.Description, entry.Amount, entry.Narration, entry.EntryStatus,
 		entry.CreatedAt, entry.UpdatedAt,
@@ -140,12 +152,16 @@ func (s *GLService) CreateJournalEntry(tenantID string, entry *models.JournalEnt
 
 // AddJournalEntryDetail adds a debit/credit line to an entry
 func (s *GLService) AddJournalEntryDetail(detail *models.JournalEntryDetail) error {
+	return addJournalEntryDetail(s.DB, detail)
+}
+
+func addJournalEntryDetail(db glExecer, detail *models.JournalEntryDetail) error {
 	query := `INSERT INTO journal_entry_details (
 		id, tenant_id, journal_entry_id, account_id, account_code, debit_amount, credit_amount,
 		description, line_number, created_at, updated_at
 	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := s.DB.Exec(query,
+	_, err := db.Exec(query,
 		detail.ID, detail.TenantID, detail.JournalEntryID, detail.AccountID, detail.AccountCode,
 		detail.DebitAmount, detail.CreditAmount, detail.Description, detail.LineNumber,
 		detail.CreatedAt, detail.UpdatedAt,
@@ -156,13 +172,17 @@ func (s *GLService) AddJournalEntryDetail(detail *models.JournalEntryDetail) err
 
 // PostJournalEntry posts a draft entry (moves from Draft to Posted)
 func (s *GLService) PostJournalEntry(tenantID, entryID, postedBy string) error {
+	return postJournalEntry(s.DB, tenantID, entryID, postedBy)
+}
+
+func postJournalEntry(db glExecer, tenantID, entryID, postedBy string) error {
 	// Validate debit/credit balance
 	var totalDebit, totalCredit float64
 
 	query := `SELECT SUM(debit_amount) as debit, SUM(credit_amount) as credit
 		FROM journal_entry_details WHERE journal_entry_id = ? AND tenant_id = ?`
 
-	err := s.DB.QueryRow(query, entryID, tenantID).Scan(&totalDebit, &totalCredit)
+	err := db.QueryRow(query, entryID, tenantID).Scan(&totalDebit, &totalCredit)
 	if err != nil {
 		return fmt.Errorf("failed to calculate totals: %v", err)
 	}
@@ -175,7 +195,7 @@ func (s *GLService) PostJournalEntry(tenantID, entryID, postedBy string) error {
 	updateQuery := `UPDATE journal_entries SET entry_status = 'Posted', posted_by = ?, posted_at = NOW(), updated_at = NOW()
 		WHERE id = ? AND tenant_id = ?`
 
-	_, err = s.DB.Exec(updateQuery, postedBy, entryID, tenantID)
+	_, err = db.Exec(updateQuery, postedBy, entryID, tenantID)
 	if err != nil {
 		return err
 	}
@@ -184,30 +204,78 @@ func (s *GLService) PostJournalEntry(tenantID, entryID, postedBy string) error {
 	detailsQuery := `SELECT account_id, debit_amount, credit_amount FROM journal_entry_details
 		WHERE journal_entry_id = ? AND tenant_id = ?`
 
-	rows, err := s.DB.Query(detailsQuery, entryID, tenantID)
+	rows, err := db.Query(detailsQuery, entryID, tenantID)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
+	// Read every line before updating balances, as a transaction cannot run
+	// statements while a result set is still open
+	type balanceChange struct {
+		accountID     string
+		debit, credit float64
+	}
+	var changes []balanceChange
 	for rows.Next() {
-		var accountID string
-		var debit, credit float64
-
-		if err := rows.Scan(&accountID, &debit, &credit); err != nil {
+		var c balanceChange
+		if err := rows.Scan(&c.accountID, &c.debit, &c.credit); err != nil {
 			return err
 		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
 
+	for _, c := range changes {
 		// Update current balance
 		balanceQuery := `UPDATE chart_of_accounts SET current_balance = current_balance + ? - ?
 			WHERE id = ? AND tenant_id = ?`
-		_, err = s.DB.Exec(balanceQuery, debit, credit, accountID, tenantID)
+		_, err = db.Exec(balanceQuery, c.debit, c.credit, c.accountID, tenantID)
 		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// PostBalancedEntry creates a journal entry with its lines and posts it in one call.
+// Line IDs and numbers are assigned from the entry ID when not set.
+func (s *GLService) PostBalancedEntry(tenantID string, entry *models.JournalEntry, lines []models.JournalEntryDetail, postedBy string) error {
+	return postBalancedEntry(s.DB, tenantID, entry, lines, postedBy)
+}
+
+// PostBalancedEntryTx is PostBalancedEntry inside the caller's transaction
+func (s *GLService) PostBalancedEntryTx(tx *sql.Tx, tenantID string, entry *models.JournalEntry, lines []models.JournalEntryDetail, postedBy string) error {
+	return postBalancedEntry(tx, tenantID, entry, lines, postedBy)
+}
+
+func postBalancedEntry(db glExecer, tenantID string, entry *models.JournalEntry, lines []models.JournalEntryDetail, postedBy string) error {
+	if err := createJournalEntry(db, tenantID, entry); err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		if line.ID == "" {
+			line.ID = fmt.Sprintf("JED-%s-%d", entry.ID, i+1)
+		}
+		line.TenantID = tenantID
+		line.JournalEntryID = entry.ID
+		line.LineNumber = i + 1
+		line.CreatedAt = time.Now()
+		line.UpdatedAt = time.Now()
+		if err := addJournalEntryDetail(db, line); err != nil {
+			return fmt.Errorf("failed to add journal entry line %d: %w", i+1, err)
+		}
+	}
 
-	return rows.Err()
+	if err := postJournalEntry(db, tenantID, entry.ID, postedBy); err != nil {
+		return fmt.Errorf("failed to post journal entry: %w", err)
+	}
+	return nil
 }
 
 // GetJournalEntry retrieves an entry with its details
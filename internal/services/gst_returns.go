@@ -0,0 +1,914 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+// ============================================================================
+// GST RETURN PREPARATION (GSTR-1 / GSTR-3B)
+// ============================================================================
+// Builds the monthly returns from gst_invoice_tracking (outward) and
+// gst_input_credit (inward) in the GSTN upload schema.
+
+// b2clThreshold is the invoice value above which an inter-state supply to an
+// unregistered person is reported invoice-wise (B2CL) instead of in B2CS
+const b2clThreshold = 100000.0
+
+const gstReturnDateLayout = "02-01-2006"
+
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+// ParseGSTReturnPeriod parses an MMYYYY return period into its month range [from, to)
+func ParseGSTReturnPeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.Parse("012006", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid return period %q, expected MMYYYY", period)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// ============================================================================
+// DOCUMENT RECORDING
+// ============================================================================
+
+// RecordGSTOutwardDocument records a sales invoice, credit note or debit note with the detail GSTR-1 needs
+func (s *TaxComplianceService) RecordGSTOutwardDocument(tenantID string, req *models.RecordGSTOutwardDocumentRequest) (*models.GSTInvoiceTracking, error) {
+	invoiceDate, err := time.Parse("2006-01-02", req.InvoiceDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invoice_date: %w", err)
+	}
+
+	docType := strings.ToUpper(req.DocumentType)
+	if docType == "" {
+		docType = models.GSTDocumentInvoice
+	}
+	if docType != models.GSTDocumentInvoice && docType != models.GSTDocumentCreditNote && docType != models.GSTDocumentDebitNote {
+		return nil, fmt.Errorf("invalid document_type %q", req.DocumentType)
+	}
+
+	var originalDate *time.Time
+	if req.OriginalInvoiceDate != "" {
+		d, err := time.Parse("2006-01-02", req.OriginalInvoiceDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid original_invoice_date: %w", err)
+		}
+		originalDate = &d
+	}
+
+	gstAmount := req.IGSTAmount + req.CGSTAmount + req.SGSTAmount
+	doc := &models.GSTInvoiceTracking{
+		ID:                    uuid.New().String(),
+		TenantID:              tenantID,
+		InvoiceID:             req.InvoiceID,
+		InvoiceNumber:         req.InvoiceNumber,
+		InvoiceDate:           &invoiceDate,
+		InvoiceAmount:         req.InvoiceAmount,
+		CustomerID:            req.CustomerID,
+		CustomerGSTIN:         strings.ToUpper(strings.TrimSpace(req.CustomerGSTIN)),
+		CustomerName:          req.CustomerName,
+		DocumentType:          docType,
+		PlaceOfSupply:         req.PlaceOfSupply,
+		ReverseCharge:         req.ReverseCharge,
+		HSNCode:               req.HSNCode,
+		HSNDescription:        req.HSNDescription,
+		UQC:                   req.UQC,
+		Quantity:              req.Quantity,
+		OriginalInvoiceNumber: req.OriginalInvoiceNumber,
+		OriginalInvoiceDate:   originalDate,
+		GSTRate:               req.GSTRate,
+		GSTAmount:             gstAmount,
+		TaxableValue:          req.TaxableValue,
+		IGSTAmount:            req.IGSTAmount,
+		CGSTAmount:            req.CGSTAmount,
+		SGSTAmount:            req.SGSTAmount,
+		CessAmount:            req.CessAmount,
+		InvoiceRaisedDate:     &invoiceDate,
+		ITCEligible:           true,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO gst_invoice_tracking
+		(id, tenant_id, invoice_id, invoice_number, invoice_date, invoice_amount,
+		 customer_id, customer_gstin, customer_name, document_type, place_of_supply,
+		 reverse_charge, hsn_code, hsn_description, uqc, quantity,
+		 original_invoice_number, original_invoice_date, gst_rate, gst_amount,
+		 taxable_value, igst_amount, cgst_amount, sgst_amount, cess_amount,
+		 invoice_raised_date, itc_eligible, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		doc.ID, doc.TenantID, doc.InvoiceID, doc.InvoiceNumber, doc.InvoiceDate, doc.InvoiceAmount,
+		nullIfEmpty(doc.CustomerID), nullIfEmpty(doc.CustomerGSTIN), nullIfEmpty(doc.CustomerName),
+		doc.DocumentType, nullIfEmpty(doc.PlaceOfSupply), doc.ReverseCharge,
+		nullIfEmpty(doc.HSNCode), nullIfEmpty(doc.HSNDescription), nullIfEmpty(doc.UQC), doc.Quantity,
+		nullIfEmpty(doc.OriginalInvoiceNumber), doc.OriginalInvoiceDate, doc.GSTRate, doc.GSTAmount,
+		doc.TaxableValue, doc.IGSTAmount, doc.CGSTAmount, doc.SGSTAmount, doc.CessAmount,
+		doc.InvoiceRaisedDate, doc.ITCEligible, doc.CreatedAt, doc.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record GST outward document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// RecordGSTInwardDocument records a purchase invoice with the tax break-up GSTR-3B needs
+func (s *TaxComplianceService) RecordGSTInwardDocument(tenantID string, req *models.RecordGSTInwardDocumentRequest) (*models.GSTInputCredit, error) {
+	invoiceDate, err := time.Parse("2006-01-02", req.InvoiceDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invoice_date: %w", err)
+	}
+
+	gstAmount := req.IGSTAmount + req.CGSTAmount + req.SGSTAmount
+	credit := &models.GSTInputCredit{
+		ID:                  uuid.New().String(),
+		TenantID:            tenantID,
+		PurchaseInvoiceID:   req.PurchaseInvoiceID,
+		VendorID:            req.VendorID,
+		VendorGSTIN:         strings.ToUpper(strings.TrimSpace(req.VendorGSTIN)),
+		InvoiceNumber:       req.InvoiceNumber,
+		InvoiceDate:         &invoiceDate,
+		InvoiceAmount:       req.InvoiceAmount,
+		GSTRate:             req.GSTRate,
+		GSTAmount:           gstAmount,
+		TaxableValue:        req.TaxableValue,
+		IGSTAmount:          req.IGSTAmount,
+		CGSTAmount:          req.CGSTAmount,
+		SGSTAmount:          req.SGSTAmount,
+		CessAmount:          req.CessAmount,
+		PlaceOfSupply:       req.PlaceOfSupply,
+		ReverseCharge:       req.ReverseCharge,
+		ITCEligible:         req.ITCEligible,
+		ITCIneligibleReason: req.ITCIneligibleReason,
+		BlockedPercentage:   req.BlockedPercentage,
+		BlockedAmount:       roundTo2(gstAmount * req.BlockedPercentage / 100),
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO gst_input_credit
+		(id, tenant_id, purchase_invoice_id, vendor_id, vendor_gstin, invoice_number,
+		 invoice_date, invoice_amount, gst_rate, gst_amount, taxable_value, igst_amount,
+		 cgst_amount, sgst_amount, cess_amount, place_of_supply, reverse_charge,
+		 itc_eligible, itc_ineligible_reason, blocked_percentage, blocked_amount,
+		 created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		credit.ID, credit.TenantID, credit.PurchaseInvoiceID, nullIfEmpty(credit.VendorID),
+		nullIfEmpty(credit.VendorGSTIN), credit.InvoiceNumber, credit.InvoiceDate, credit.InvoiceAmount,
+		credit.GSTRate, credit.GSTAmount, credit.TaxableValue, credit.IGSTAmount,
+		credit.CGSTAmount, credit.SGSTAmount, credit.CessAmount, nullIfEmpty(credit.PlaceOfSupply),
+		credit.ReverseCharge, credit.ITCEligible, nullIfEmpty(credit.ITCIneligibleReason),
+		credit.BlockedPercentage, credit.BlockedAmount, credit.CreatedAt, credit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record GST inward document: %w", err)
+	}
+
+	return credit, nil
+}
+
+// ============================================================================
+// RETURN GENERATION
+// ============================================================================
+
+// GenerateGSTR1 builds GSTR-1 for an MMYYYY period from the tracked outward documents
+func (s *TaxComplianceService) GenerateGSTR1(tenantID, period string) (*models.GSTR1Result, error) {
+	from, to, err := ParseGSTReturnPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	supplierGSTIN, err := s.getSupplierGSTIN(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.getOutwardDocuments(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, issues := buildGSTR1(supplierGSTIN, period, docs)
+	return &models.GSTR1Result{
+		Period:        period,
+		DocumentCount: len(docs),
+		Valid:         !hasGSTErrors(issues),
+		Issues:        issues,
+		Return:        ret,
+	}, nil
+}
+
+// GenerateGSTR3B builds the GSTR-3B summary tables for an MMYYYY period
+func (s *TaxComplianceService) GenerateGSTR3B(tenantID, period string) (*models.GSTR3BResult, error) {
+	from, to, err := ParseGSTReturnPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	supplierGSTIN, err := s.getSupplierGSTIN(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	outward, err := s.getOutwardDocuments(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	inward, err := s.getInwardDocuments(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, issues := buildGSTR3B(supplierGSTIN, period, outward, inward)
+	return &models.GSTR3BResult{
+		Period:           period,
+		OutwardDocuments: len(outward),
+		InwardDocuments:  len(inward),
+		Valid:            !hasGSTErrors(issues),
+		Issues:           issues,
+		Return:           ret,
+	}, nil
+}
+
+func (s *TaxComplianceService) getSupplierGSTIN(tenantID string) (string, error) {
+	var gstin sql.NullString
+	err := s.DB.QueryRow(`SELECT gst_registration_number FROM tax_configuration
+		WHERE tenant_id = ? AND is_active = TRUE AND deleted_at IS NULL
+		ORDER BY created_at DESC LIMIT 1`, tenantID).Scan(&gstin)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get GST registration: %w", err)
+	}
+	return strings.ToUpper(strings.TrimSpace(gstin.String)), nil
+}
+
+func (s *TaxComplianceService) getOutwardDocuments(tenantID string, from, to time.Time) ([]models.GSTInvoiceTracking, error) {
+	rows, err := s.DB.Query(`SELECT id, invoice_id, invoice_number, invoice_date, invoice_amount,
+		COALESCE(customer_id, ''), COALESCE(customer_gstin, ''), COALESCE(customer_name, ''),
+		COALESCE(document_type, 'INV'), COALESCE(place_of_supply, ''), COALESCE(reverse_charge, FALSE),
+		COALESCE(hsn_code, ''), COALESCE(hsn_description, ''), COALESCE(uqc, ''), COALESCE(quantity, 0),
+		COALESCE(original_invoice_number, ''), original_invoice_date,
+		COALESCE(gst_rate, 0), COALESCE(gst_amount, 0), COALESCE(taxable_value, 0),
+		COALESCE(igst_amount, 0), COALESCE(cgst_amount, 0), COALESCE(sgst_amount, 0), COALESCE(cess_amount, 0)
+		FROM gst_invoice_tracking
+		WHERE tenant_id = ? AND invoice_date >= ? AND invoice_date < ?
+		AND COALESCE(invoice_cancelled, FALSE) = FALSE AND deleted_at IS NULL
+		ORDER BY invoice_date, invoice_number`,
+		tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outward GST documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []models.GSTInvoiceTracking
+	for rows.Next() {
+		var d models.GSTInvoiceTracking
+		var invoiceDate time.Time
+		var originalDate sql.NullTime
+		if err := rows.Scan(&d.ID, &d.InvoiceID, &d.InvoiceNumber, &invoiceDate, &d.InvoiceAmount,
+			&d.CustomerID, &d.CustomerGSTIN, &d.CustomerName,
+			&d.DocumentType, &d.PlaceOfSupply, &d.ReverseCharge,
+			&d.HSNCode, &d.HSNDescription, &d.UQC, &d.Quantity,
+			&d.OriginalInvoiceNumber, &originalDate,
+			&d.GSTRate, &d.GSTAmount, &d.TaxableValue,
+			&d.IGSTAmount, &d.CGSTAmount, &d.SGSTAmount, &d.CessAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan outward GST document: %w", err)
+		}
+		d.TenantID = tenantID
+		d.InvoiceDate = &invoiceDate
+		if originalDate.Valid {
+			d.OriginalInvoiceDate = &originalDate.Time
+		}
+		docs = append(docs, d)
+	}
+
+	return docs, rows.Err()
+}
+
+func (s *TaxComplianceService) getInwardDocuments(tenantID string, from, to time.Time) ([]models.GSTInputCredit, error) {
+	rows, err := s.DB.Query(`SELECT id, purchase_invoice_id, COALESCE(vendor_id, ''), COALESCE(vendor_gstin, ''),
+		invoice_number, invoice_date, invoice_amount, COALESCE(gst_rate, 0), COALESCE(gst_amount, 0),
+		COALESCE(taxable_value, 0), COALESCE(igst_amount, 0), COALESCE(cgst_amount, 0),
+		COALESCE(sgst_amount, 0), COALESCE(cess_amount, 0), COALESCE(place_of_supply, ''),
+		COALESCE(reverse_charge, FALSE), COALESCE(itc_eligible, FALSE), COALESCE(itc_ineligible_reason, ''),
+		COALESCE(blocked_percentage, 0), COALESCE(blocked_amount, 0)
+		FROM gst_input_credit
+		WHERE tenant_id = ? AND invoice_date >= ? AND invoice_date < ? AND deleted_at IS NULL
+		ORDER BY invoice_date, invoice_number`,
+		tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inward GST documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []models.GSTInputCredit
+	for rows.Next() {
+		var c models.GSTInputCredit
+		var invoiceDate time.Time
+		if err := rows.Scan(&c.ID, &c.PurchaseInvoiceID, &c.VendorID, &c.VendorGSTIN,
+			&c.InvoiceNumber, &invoiceDate, &c.InvoiceAmount, &c.GSTRate, &c.GSTAmount,
+			&c.TaxableValue, &c.IGSTAmount, &c.CGSTAmount,
+			&c.SGSTAmount, &c.CessAmount, &c.PlaceOfSupply,
+			&c.ReverseCharge, &c.ITCEligible, &c.ITCIneligibleReason,
+			&c.BlockedPercentage, &c.BlockedAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan inward GST document: %w", err)
+		}
+		c.TenantID = tenantID
+		c.InvoiceDate = &invoiceDate
+		docs = append(docs, c)
+	}
+
+	return docs, rows.Err()
+}
+
+// ============================================================================
+// RETURN BUILDERS
+// ============================================================================
+
+// gstStateCode returns the state code embedded in a GSTIN
+func gstStateCode(gstin string) string {
+	if len(gstin) < 2 {
+		return ""
+	}
+	return gstin[:2]
+}
+
+// taxBreakup completes a recorded tax split. Legacy rows only carry the total
+// GST amount, so taxable value is derived from the invoice amount and the tax
+// is allotted to IGST for inter-state supplies or halved into CGST/SGST.
+func taxBreakup(recorded models.GSTItemDetail, invoiceAmount, gstAmount float64, interState bool) models.GSTItemDetail {
+	d := recorded
+	if d.IGSTAmount == 0 && d.CGSTAmount == 0 && d.SGSTAmount == 0 && gstAmount != 0 {
+		if interState {
+			d.IGSTAmount = roundTo2(gstAmount)
+		} else {
+			d.CGSTAmount = roundTo2(gstAmount / 2)
+			d.SGSTAmount = roundTo2(gstAmount - d.CGSTAmount)
+		}
+	}
+	if d.TaxableValue == 0 {
+		d.TaxableValue = roundTo2(invoiceAmount - d.IGSTAmount - d.CGSTAmount - d.SGSTAmount - d.CessAmount)
+	}
+	return d
+}
+
+func outwardDetail(doc *models.GSTInvoiceTracking, interState bool) models.GSTItemDetail {
+	return taxBreakup(models.GSTItemDetail{
+		TaxableValue: doc.TaxableValue,
+		Rate:         doc.GSTRate,
+		IGSTAmount:   doc.IGSTAmount,
+		CGSTAmount:   doc.CGSTAmount,
+		SGSTAmount:   doc.SGSTAmount,
+		CessAmount:   doc.CessAmount,
+	}, doc.InvoiceAmount, doc.GSTAmount, interState)
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "Y"
+	}
+	return "N"
+}
+
+func hasGSTErrors(issues []models.GSTReturnIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == models.GSTIssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGSTR1 sorts outward documents into the GSTR-1 sections and validates them
+func buildGSTR1(supplierGSTIN, period string, docs []models.GSTInvoiceTracking) (*models.GSTR1Return, []models.GSTReturnIssue) {
+	ret := &models.GSTR1Return{
+		GSTIN:        supplierGSTIN,
+		FilingPeriod: period,
+		B2B:          []models.GSTR1B2B{},
+		B2CL:         []models.GSTR1B2CL{},
+		B2CS:         []models.GSTR1B2CS{},
+		CDNR:         []models.GSTR1CDNR{},
+		HSN:          models.GSTR1HSN{Data: []models.GSTR1HSNRow{}},
+	}
+	issues := []models.GSTReturnIssue{}
+	addIssue := func(severity, docNumber, field, message string) {
+		issues = append(issues, models.GSTReturnIssue{Severity: severity, DocumentNumber: docNumber, Field: field, Message: message})
+	}
+
+	if supplierGSTIN == "" {
+		addIssue(models.GSTIssueError, "", "gstin", "GST registration number is not configured for this organization")
+	} else if !gstinPattern.MatchString(supplierGSTIN) {
+		addIssue(models.GSTIssueError, "", "gstin", fmt.Sprintf("GST registration number %s is not a valid GSTIN", supplierGSTIN))
+	}
+	supplierState := gstStateCode(supplierGSTIN)
+
+	b2bIndex := map[string]int{}
+	b2clIndex := map[string]int{}
+	b2csIndex := map[string]int{}
+	cdnrIndex := map[string]int{}
+	hsnIndex := map[string]int{}
+
+	for i := range docs {
+		doc := &docs[i]
+		docType := doc.DocumentType
+		if docType == "" {
+			docType = models.GSTDocumentInvoice
+		}
+
+		registered := doc.CustomerGSTIN != ""
+		if registered && !gstinPattern.MatchString(doc.CustomerGSTIN) {
+			addIssue(models.GSTIssueError, doc.InvoiceNumber, "customer_gstin",
+				fmt.Sprintf("Customer GSTIN %s is not a valid GSTIN", doc.CustomerGSTIN))
+			continue
+		}
+
+		pos := doc.PlaceOfSupply
+		if pos == "" {
+			if !registered {
+				addIssue(models.GSTIssueError, doc.InvoiceNumber, "place_of_supply",
+					"Place of supply is missing for a supply to an unregistered customer")
+				continue
+			}
+			pos = gstStateCode(doc.CustomerGSTIN)
+			addIssue(models.GSTIssueWarning, doc.InvoiceNumber, "place_of_supply",
+				fmt.Sprintf("Place of supply is missing; state %s taken from customer GSTIN", pos))
+		}
+
+		interState := supplierState != "" && pos != supplierState
+		detail := outwardDetail(doc, interState)
+		item := models.GSTItem{Num: 1, Detail: detail}
+		invoiceDate := doc.InvoiceDate.Format(gstReturnDateLayout)
+
+		// HSN summary nets credit notes off against invoices
+		sign := 1.0
+		if docType == models.GSTDocumentCreditNote {
+			sign = -1
+		}
+		if doc.HSNCode == "" {
+			addIssue(models.GSTIssueError, doc.InvoiceNumber, "hsn_code", "HSN/SAC code is missing")
+		} else {
+			uqc := doc.UQC
+			if uqc == "" {
+				uqc = "NA"
+			}
+			key := fmt.Sprintf("%s|%s|%.2f", doc.HSNCode, uqc, detail.Rate)
+			idx, ok := hsnIndex[key]
+			if !ok {
+				idx = len(ret.HSN.Data)
+				hsnIndex[key] = idx
+				ret.HSN.Data = append(ret.HSN.Data, models.GSTR1HSNRow{
+					Num:         idx + 1,
+					HSNCode:     doc.HSNCode,
+					Description: doc.HSNDescription,
+					UQC:         uqc,
+					Rate:        detail.Rate,
+				})
+			}
+			row := &ret.HSN.Data[idx]
+			row.Quantity = roundTo2(row.Quantity + sign*doc.Quantity)
+			row.TaxableValue = roundTo2(row.TaxableValue + sign*detail.TaxableValue)
+			row.IGSTAmount = roundTo2(row.IGSTAmount + sign*detail.IGSTAmount)
+			row.CGSTAmount = roundTo2(row.CGSTAmount + sign*detail.CGSTAmount)
+			row.SGSTAmount = roundTo2(row.SGSTAmount + sign*detail.SGSTAmount)
+			row.CessAmount = roundTo2(row.CessAmount + sign*detail.CessAmount)
+		}
+
+		switch {
+		case docType == models.GSTDocumentCreditNote || docType == models.GSTDocumentDebitNote:
+			if !registered {
+				addIssue(models.GSTIssueWarning, doc.InvoiceNumber, "customer_gstin",
+					"Note issued to an unregistered customer is not included; report it under CDNUR")
+				continue
+			}
+			if doc.OriginalInvoiceNumber == "" {
+				addIssue(models.GSTIssueWarning, doc.InvoiceNumber, "original_invoice_number",
+					"Original invoice reference is missing on the note")
+			}
+			noteType := "C"
+			if docType == models.GSTDocumentDebitNote {
+				noteType = "D"
+			}
+			idx, ok := cdnrIndex[doc.CustomerGSTIN]
+			if !ok {
+				idx = len(ret.CDNR)
+				cdnrIndex[doc.CustomerGSTIN] = idx
+				ret.CDNR = append(ret.CDNR, models.GSTR1CDNR{CounterpartyGSTIN: doc.CustomerGSTIN})
+			}
+			ret.CDNR[idx].Notes = append(ret.CDNR[idx].Notes, models.GSTR1Note{
+				NoteType:      noteType,
+				NoteNumber:    doc.InvoiceNumber,
+				NoteDate:      invoiceDate,
+				Value:         roundTo2(doc.InvoiceAmount),
+				PlaceOfSupply: pos,
+				ReverseCharge: yesNo(doc.ReverseCharge),
+				InvoiceType:   "R",
+				Items:         []models.GSTItem{item},
+			})
+
+		case registered:
+			idx, ok := b2bIndex[doc.CustomerGSTIN]
+			if !ok {
+				idx = len(ret.B2B)
+				b2bIndex[doc.CustomerGSTIN] = idx
+				ret.B2B = append(ret.B2B, models.GSTR1B2B{CounterpartyGSTIN: doc.CustomerGSTIN})
+			}
+			ret.B2B[idx].Invoices = append(ret.B2B[idx].Invoices, models.GSTR1Invoice{
+				InvoiceNumber: doc.InvoiceNumber,
+				InvoiceDate:   invoiceDate,
+				Value:         roundTo2(doc.InvoiceAmount),
+				PlaceOfSupply: pos,
+				ReverseCharge: yesNo(doc.ReverseCharge),
+				InvoiceType:   "R",
+				Items:         []models.GSTItem{item},
+			})
+
+		case interState && doc.InvoiceAmount > b2clThreshold:
+			idx, ok := b2clIndex[pos]
+			if !ok {
+				idx = len(ret.B2CL)
+				b2clIndex[pos] = idx
+				ret.B2CL = append(ret.B2CL, models.GSTR1B2CL{PlaceOfSupply: pos})
+			}
+			ret.B2CL[idx].Invoices = append(ret.B2CL[idx].Invoices, models.GSTR1Invoice{
+				InvoiceNumber: doc.InvoiceNumber,
+				InvoiceDate:   invoiceDate,
+				Value:         roundTo2(doc.InvoiceAmount),
+				Items:         []models.GSTItem{item},
+			})
+
+		default:
+			supplyType := "INTRA"
+			if interState {
+				supplyType = "INTER"
+			}
+			key := fmt.Sprintf("%s|%s|%.2f", supplyType, pos, detail.Rate)
+			idx, ok := b2csIndex[key]
+			if !ok {
+				idx = len(ret.B2CS)
+				b2csIndex[key] = idx
+				ret.B2CS = append(ret.B2CS, models.GSTR1B2CS{SupplyType: supplyType, Rate: detail.Rate, Type: "OE", PlaceOfSupply: pos})
+			}
+			row := &ret.B2CS[idx]
+			row.TaxableValue = roundTo2(row.TaxableValue + detail.TaxableValue)
+			row.IGSTAmount = roundTo2(row.IGSTAmount + detail.IGSTAmount)
+			row.CGSTAmount = roundTo2(row.CGSTAmount + detail.CGSTAmount)
+			row.SGSTAmount = roundTo2(row.SGSTAmount + detail.SGSTAmount)
+			row.CessAmount = roundTo2(row.CessAmount + detail.CessAmount)
+		}
+	}
+
+	return ret, issues
+}
+
+func addGSTAmounts(total *models.GSTR3BAmounts, d models.GSTItemDetail, factor float64) {
+	total.TaxableValue = roundTo2(total.TaxableValue + factor*d.TaxableValue)
+	total.IGSTAmount = roundTo2(total.IGSTAmount + factor*d.IGSTAmount)
+	total.CGSTAmount = roundTo2(total.CGSTAmount + factor*d.CGSTAmount)
+	total.SGSTAmount = roundTo2(total.SGSTAmount + factor*d.SGSTAmount)
+	total.CessAmount = roundTo2(total.CessAmount + factor*d.CessAmount)
+}
+
+func addITCRow(rows []models.GSTR3BITCRow, ty string, d models.GSTItemDetail, factor float64) {
+	for i := range rows {
+		if rows[i].Type == ty {
+			rows[i].IGSTAmount = roundTo2(rows[i].IGSTAmount + factor*d.IGSTAmount)
+			rows[i].CGSTAmount = roundTo2(rows[i].CGSTAmount + factor*d.CGSTAmount)
+			rows[i].SGSTAmount = roundTo2(rows[i].SGSTAmount + factor*d.SGSTAmount)
+			rows[i].CessAmount = roundTo2(rows[i].CessAmount + factor*d.CessAmount)
+			return
+		}
+	}
+}
+
+func sumITCRows(rows []models.GSTR3BITCRow) models.GSTR3BAmounts {
+	var total models.GSTR3BAmounts
+	for _, row := range rows {
+		total.IGSTAmount += row.IGSTAmount
+		total.CGSTAmount += row.CGSTAmount
+		total.SGSTAmount += row.SGSTAmount
+		total.CessAmount += row.CessAmount
+	}
+	return total
+}
+
+func newITCRows(types ...string) []models.GSTR3BITCRow {
+	rows := make([]models.GSTR3BITCRow, len(types))
+	for i, ty := range types {
+		rows[i].Type = ty
+	}
+	return rows
+}
+
+// buildGSTR3B computes tables 3.1, 3.2 and 4 of GSTR-3B
+func buildGSTR3B(supplierGSTIN, period string, outward []models.GSTInvoiceTracking, inward []models.GSTInputCredit) (*models.GSTR3BReturn, []models.GSTReturnIssue) {
+	ret := &models.GSTR3BReturn{
+		GSTIN:        supplierGSTIN,
+		ReturnPeriod: period,
+		InterStateSupp: models.GSTR3BInterState{
+			Unregistered: []models.GSTR3BInterStateRow{},
+			Composition:  []models.GSTR3BInterStateRow{},
+			UINHolders:   []models.GSTR3BInterStateRow{},
+		},
+		ITCEligibility: models.GSTR3BITC{
+			Available:  newITCRows("IMPG", "IMPS", "ISRC", "ISD", "OTH"),
+			Reversed:   newITCRows("RUL", "OTH"),
+			Ineligible: newITCRows("RUL", "OTH"),
+		},
+	}
+	issues := []models.GSTReturnIssue{}
+	addIssue := func(severity, docNumber, field, message string) {
+		issues = append(issues, models.GSTReturnIssue{Severity: severity, DocumentNumber: docNumber, Field: field, Message: message})
+	}
+
+	if supplierGSTIN == "" {
+		addIssue(models.GSTIssueError, "", "gstin", "GST registration number is not configured for this organization")
+	} else if !gstinPattern.MatchString(supplierGSTIN) {
+		addIssue(models.GSTIssueError, "", "gstin", fmt.Sprintf("GST registration number %s is not a valid GSTIN", supplierGSTIN))
+	}
+	supplierState := gstStateCode(supplierGSTIN)
+
+	// 3.1 / 3.2 outward supplies
+	unregIndex := map[string]int{}
+	sup := &ret.SupplyDetails
+	for i := range outward {
+		doc := &outward[i]
+		pos := doc.PlaceOfSupply
+		if pos == "" {
+			pos = gstStateCode(doc.CustomerGSTIN)
+		}
+		if pos == "" {
+			addIssue(models.GSTIssueError, doc.InvoiceNumber, "place_of_supply", "Place of supply is missing")
+			continue
+		}
+
+		interState := supplierState != "" && pos != supplierState
+		detail := outwardDetail(doc, interState)
+		sign := 1.0
+		if doc.DocumentType == models.GSTDocumentCreditNote {
+			sign = -1
+		}
+
+		switch {
+		case doc.ReverseCharge:
+			// Tax on outward supplies under reverse charge is paid by the recipient
+			continue
+		case detail.Rate == 0 && doc.GSTAmount == 0:
+			sup.OutwardNilExemp.TaxableValue = roundTo2(sup.OutwardNilExemp.TaxableValue + sign*detail.TaxableValue)
+			continue
+		default:
+			addGSTAmounts(&sup.OutwardTaxable, detail, sign)
+		}
+
+		if interState && doc.CustomerGSTIN == "" {
+			idx, ok := unregIndex[pos]
+			if !ok {
+				idx = len(ret.InterStateSupp.Unregistered)
+				unregIndex[pos] = idx
+				ret.InterStateSupp.Unregistered = append(ret.InterStateSupp.Unregistered, models.GSTR3BInterStateRow{PlaceOfSupply: pos})
+			}
+			row := &ret.InterStateSupp.Unregistered[idx]
+			row.TaxableValue = roundTo2(row.TaxableValue + sign*detail.TaxableValue)
+			row.IGSTAmount = roundTo2(row.IGSTAmount + sign*detail.IGSTAmount)
+		}
+	}
+
+	// 3.1(d) and 4 inward supplies
+	itc := &ret.ITCEligibility
+	for i := range inward {
+		credit := &inward[i]
+		vendorState := gstStateCode(credit.VendorGSTIN)
+		receivedIn := credit.PlaceOfSupply
+		if receivedIn == "" {
+			receivedIn = supplierState
+		}
+		interState := vendorState != "" && receivedIn != "" && vendorState != receivedIn
+
+		detail := taxBreakup(models.GSTItemDetail{
+			TaxableValue: credit.TaxableValue,
+			Rate:         credit.GSTRate,
+			IGSTAmount:   credit.IGSTAmount,
+			CGSTAmount:   credit.CGSTAmount,
+			SGSTAmount:   credit.SGSTAmount,
+			CessAmount:   credit.CessAmount,
+		}, credit.InvoiceAmount, credit.GSTAmount, interState)
+
+		if credit.ReverseCharge {
+			addGSTAmounts(&sup.InwardReverse, detail, 1)
+		}
+
+		if !credit.ITCEligible {
+			addITCRow(itc.Ineligible, "RUL", detail, 1)
+			continue
+		}
+
+		if credit.VendorGSTIN == "" && !credit.ReverseCharge {
+			addIssue(models.GSTIssueError, credit.InvoiceNumber, "vendor_gstin",
+				"Vendor GSTIN is missing; input tax credit cannot be claimed")
+			continue
+		}
+		if credit.VendorGSTIN != "" && !gstinPattern.MatchString(credit.VendorGSTIN) {
+			addIssue(models.GSTIssueError, credit.InvoiceNumber, "vendor_gstin",
+				fmt.Sprintf("Vendor GSTIN %s is not a valid GSTIN", credit.VendorGSTIN))
+			continue
+		}
+
+		availableType := "OTH"
+		if credit.ReverseCharge {
+			availableType = "ISRC"
+		}
+		addITCRow(itc.Available, availableType, detail, 1)
+
+		// Common credit blocked under rules 42/43 is reversed proportionately
+		blocked := credit.BlockedPercentage / 100
+		if credit.BlockedAmount > 0 && credit.GSTAmount > 0 {
+			blocked = credit.BlockedAmount / credit.GSTAmount
+		}
+		if blocked > 0 {
+			addITCRow(itc.Reversed, "RUL", detail, blocked)
+		}
+	}
+
+	available := sumITCRows(itc.Available)
+	reversed := sumITCRows(itc.Reversed)
+	itc.Net = models.GSTR3BAmounts{
+		IGSTAmount: roundTo2(available.IGSTAmount - reversed.IGSTAmount),
+		CGSTAmount: roundTo2(available.CGSTAmount - reversed.CGSTAmount),
+		SGSTAmount: roundTo2(available.SGSTAmount - reversed.SGSTAmount),
+		CessAmount: roundTo2(available.CessAmount - reversed.CessAmount),
+	}
+
+	return ret, issues
+}
+
+// ============================================================================
+// EXCEL EXPORT
+// ============================================================================
+
+func writeGSTSheet(f *excelize.File, sheet string, headers []string, rows [][]interface{}) error {
+	if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+	for col, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+		f.SetCellValue(sheet, cell, header)
+	}
+	for r, row := range rows {
+		for col, val := range row {
+			cell, _ := excelize.CoordinatesToCellName(col+1, r+2)
+			f.SetCellValue(sheet, cell, val)
+		}
+	}
+	return nil
+}
+
+func issueRows(issues []models.GSTReturnIssue) [][]interface{} {
+	rows := make([][]interface{}, 0, len(issues))
+	for _, issue := range issues {
+		rows = append(rows, []interface{}{issue.Severity, issue.DocumentNumber, issue.Field, issue.Message})
+	}
+	return rows
+}
+
+func newGSTWorkbook(firstSheet string) *excelize.File {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", firstSheet)
+	return f
+}
+
+// ExportGSTR1Excel renders a generated GSTR-1 as a workbook with one sheet per section
+func (s *TaxComplianceService) ExportGSTR1Excel(result *models.GSTR1Result) ([]byte, error) {
+	ret := result.Return
+	f := newGSTWorkbook("b2b")
+	defer f.Close()
+
+	var b2b, b2cl, b2cs, cdnr, hsn [][]interface{}
+	for _, party := range ret.B2B {
+		for _, inv := range party.Invoices {
+			for _, it := range inv.Items {
+				b2b = append(b2b, []interface{}{party.CounterpartyGSTIN, inv.InvoiceNumber, inv.InvoiceDate, inv.Value,
+					inv.PlaceOfSupply, inv.ReverseCharge, inv.InvoiceType, it.Detail.Rate, it.Detail.TaxableValue,
+					it.Detail.IGSTAmount, it.Detail.CGSTAmount, it.Detail.SGSTAmount, it.Detail.CessAmount})
+			}
+		}
+	}
+	for _, group := range ret.B2CL {
+		for _, inv := range group.Invoices {
+			for _, it := range inv.Items {
+				b2cl = append(b2cl, []interface{}{inv.InvoiceNumber, inv.InvoiceDate, inv.Value, group.PlaceOfSupply,
+					it.Detail.Rate, it.Detail.TaxableValue, it.Detail.IGSTAmount, it.Detail.CessAmount})
+			}
+		}
+	}
+	for _, row := range ret.B2CS {
+		b2cs = append(b2cs, []interface{}{row.SupplyType, row.PlaceOfSupply, row.Rate, row.TaxableValue,
+			row.IGSTAmount, row.CGSTAmount, row.SGSTAmount, row.CessAmount})
+	}
+	for _, party := range ret.CDNR {
+		for _, note := range party.Notes {
+			for _, it := range note.Items {
+				cdnr = append(cdnr, []interface{}{party.CounterpartyGSTIN, note.NoteNumber, note.NoteDate, note.NoteType,
+					note.PlaceOfSupply, note.ReverseCharge, note.Value, it.Detail.Rate, it.Detail.TaxableValue,
+					it.Detail.IGSTAmount, it.Detail.CGSTAmount, it.Detail.SGSTAmount, it.Detail.CessAmount})
+			}
+		}
+	}
+	for _, row := range ret.HSN.Data {
+		hsn = append(hsn, []interface{}{row.HSNCode, row.Description, row.UQC, row.Quantity, row.Rate,
+			row.TaxableValue, row.IGSTAmount, row.CGSTAmount, row.SGSTAmount, row.CessAmount})
+	}
+
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"b2b", []string{"GSTIN/UIN of Recipient", "Invoice Number", "Invoice Date", "Invoice Value", "Place Of Supply", "Reverse Charge", "Invoice Type", "Rate", "Taxable Value", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess Amount"}, b2b},
+		{"b2cl", []string{"Invoice Number", "Invoice Date", "Invoice Value", "Place Of Supply", "Rate", "Taxable Value", "Integrated Tax", "Cess Amount"}, b2cl},
+		{"b2cs", []string{"Supply Type", "Place Of Supply", "Rate", "Taxable Value", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess Amount"}, b2cs},
+		{"cdnr", []string{"GSTIN/UIN of Recipient", "Note Number", "Note Date", "Note Type", "Place Of Supply", "Reverse Charge", "Note Value", "Rate", "Taxable Value", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess Amount"}, cdnr},
+		{"hsn", []string{"HSN", "Description", "UQC", "Total Quantity", "Rate", "Taxable Value", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess Amount"}, hsn},
+		{"validation", []string{"Severity", "Document", "Field", "Message"}, issueRows(result.Issues)},
+	}
+	for _, sheet := range sheets {
+		if err := writeGSTSheet(f, sheet.name, sheet.headers, sheet.rows); err != nil {
+			return nil, fmt.Errorf("failed to write %s sheet: %w", sheet.name, err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write GSTR-1 workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportGSTR3BExcel renders a generated GSTR-3B summary as a workbook
+func (s *TaxComplianceService) ExportGSTR3BExcel(result *models.GSTR3BResult) ([]byte, error) {
+	ret := result.Return
+	f := newGSTWorkbook("3.1 Outward")
+	defer f.Close()
+
+	amountRow := func(label string, a models.GSTR3BAmounts) []interface{} {
+		return []interface{}{label, a.TaxableValue, a.IGSTAmount, a.CGSTAmount, a.SGSTAmount, a.CessAmount}
+	}
+	sup := ret.SupplyDetails
+	outward := [][]interface{}{
+		amountRow("(a) Outward taxable supplies (other than zero rated, nil rated and exempted)", sup.OutwardTaxable),
+		amountRow("(b) Outward taxable supplies (zero rated)", sup.OutwardZero),
+		amountRow("(c) Other outward supplies (nil rated, exempted)", sup.OutwardNilExemp),
+		amountRow("(d) Inward supplies (liable to reverse charge)", sup.InwardReverse),
+		amountRow("(e) Non-GST outward supplies", sup.OutwardNonGST),
+	}
+
+	var interState [][]interface{}
+	for _, row := range ret.InterStateSupp.Unregistered {
+		interState = append(interState, []interface{}{"Unregistered Persons", row.PlaceOfSupply, row.TaxableValue, row.IGSTAmount})
+	}
+
+	var itc [][]interface{}
+	itcRows := func(section string, rows []models.GSTR3BITCRow) {
+		for _, row := range rows {
+			itc = append(itc, []interface{}{section, row.Type, row.IGSTAmount, row.CGSTAmount, row.SGSTAmount, row.CessAmount})
+		}
+	}
+	itcRows("(A) ITC Available", ret.ITCEligibility.Available)
+	itcRows("(B) ITC Reversed", ret.ITCEligibility.Reversed)
+	net := ret.ITCEligibility.Net
+	itc = append(itc, []interface{}{"(C) Net ITC Available", "", net.IGSTAmount, net.CGSTAmount, net.SGSTAmount, net.CessAmount})
+	itcRows("(D) Ineligible ITC", ret.ITCEligibility.Ineligible)
+
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"3.1 Outward", []string{"Nature of Supplies", "Total Taxable Value", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess"}, outward},
+		{"3.2 Inter-State", []string{"Supplies made to", "Place of Supply", "Total Taxable Value", "Integrated Tax"}, interState},
+		{"4 ITC", []string{"Details", "Type", "Integrated Tax", "Central Tax", "State/UT Tax", "Cess"}, itc},
+		{"validation", []string{"Severity", "Document", "Field", "Message"}, issueRows(result.Issues)},
+	}
+	for _, sheet := range sheets {
+		if err := writeGSTSheet(f, sheet.name, sheet.headers, sheet.rows); err != nil {
+			return nil, fmt.Errorf("failed to write %s sheet: %w", sheet.name, err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write GSTR-3B workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSupplierGSTIN = "27AAACV1234F1Z5" // Maharashtra
+
+// TestParseGSTReturnPeriod tests MMYYYY period parsing
+func TestParseGSTReturnPeriod(t *testing.T) {
+	from, to, err := ParseGSTReturnPeriod("072025")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = ParseGSTReturnPeriod("2025-07")
+	assert.Error(t, err)
+}
+
+// TestTaxBreakup tests splitting legacy rows that only carry total GST
+func TestTaxBreakup(t *testing.T) {
+	intra := taxBreakup(models.GSTItemDetail{Rate: 18}, 118000, 18000, false)
+	assert.Equal(t, 100000.0, intra.TaxableValue)
+	assert.Equal(t, 9000.0, intra.CGSTAmount)
+	assert.Equal(t, 9000.0, intra.SGSTAmount)
+	assert.Equal(t, 0.0, intra.IGSTAmount)
+
+	inter := taxBreakup(models.GSTItemDetail{Rate: 18}, 118000, 18000, true)
+	assert.Equal(t, 18000.0, inter.IGSTAmount)
+
+	// Recorded split is kept
+	recorded := taxBreakup(models.GSTItemDetail{TaxableValue: 50000, IGSTAmount: 2500, Rate: 5}, 52500, 2500, false)
+	assert.Equal(t, 2500.0, recorded.IGSTAmount)
+	assert.Equal(t, 0.0, recorded.CGSTAmount)
+}
+
+// TestBuildGSTR1 tests classification into B2B, B2CL, B2CS, CDNR and the HSN summary
+func TestBuildGSTR1(t *testing.T) {
+	date := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	docs := []models.GSTInvoiceTracking{
+		{InvoiceNumber: "INV-1", InvoiceDate: &date, InvoiceAmount: 1180000, CustomerGSTIN: "27AABCB5678K1Z2",
+			PlaceOfSupply: "27", HSNCode: "995411", UQC: "SQM", Quantity: 100, GSTRate: 18, GSTAmount: 180000},
+		{InvoiceNumber: "INV-2", InvoiceDate: &date, InvoiceAmount: 525000, PlaceOfSupply: "29",
+			HSNCode: "995411", UQC: "SQM", Quantity: 50, GSTRate: 5, GSTAmount: 25000},
+		{InvoiceNumber: "INV-3", InvoiceDate: &date, InvoiceAmount: 52500, PlaceOfSupply: "27",
+			HSNCode: "995411", UQC: "SQM", Quantity: 5, GSTRate: 5, GSTAmount: 2500},
+		{InvoiceNumber: "CN-1", DocumentType: models.GSTDocumentCreditNote, InvoiceDate: &date, InvoiceAmount: 118000,
+			CustomerGSTIN: "27AABCB5678K1Z2", PlaceOfSupply: "27", HSNCode: "995411", UQC: "SQM", Quantity: 10,
+			GSTRate: 18, GSTAmount: 18000},
+		{InvoiceNumber: "INV-4", InvoiceDate: &date, InvoiceAmount: 10000, HSNCode: "995411", GSTRate: 18, GSTAmount: 1525},
+	}
+
+	ret, issues := buildGSTR1(testSupplierGSTIN, "072025", docs)
+
+	assert.Equal(t, "072025", ret.FilingPeriod)
+	assert.Len(t, ret.B2B, 1)
+	assert.Equal(t, "10-07-2025", ret.B2B[0].Invoices[0].InvoiceDate)
+	assert.Equal(t, 90000.0, ret.B2B[0].Invoices[0].Items[0].Detail.CGSTAmount)
+
+	assert.Len(t, ret.B2CL, 1)
+	assert.Equal(t, "29", ret.B2CL[0].PlaceOfSupply)
+	assert.Equal(t, 25000.0, ret.B2CL[0].Invoices[0].Items[0].Detail.IGSTAmount)
+
+	assert.Len(t, ret.B2CS, 1)
+	assert.Equal(t, "INTRA", ret.B2CS[0].SupplyType)
+	assert.Equal(t, 50000.0, ret.B2CS[0].TaxableValue)
+
+	assert.Len(t, ret.CDNR, 1)
+	assert.Equal(t, "C", ret.CDNR[0].Notes[0].NoteType)
+
+	// 18% line nets the credit note; 5% line combines B2CL and B2CS
+	assert.Len(t, ret.HSN.Data, 2)
+	assert.Equal(t, 900000.0, ret.HSN.Data[0].TaxableValue)
+	assert.Equal(t, 90.0, ret.HSN.Data[0].Quantity)
+	assert.Equal(t, 550000.0, ret.HSN.Data[1].TaxableValue)
+
+	// INV-4 has no place of supply and no GSTIN; CN-1 has no original invoice
+	assert.True(t, hasGSTErrors(issues))
+	fields := map[string]string{}
+	for _, issue := range issues {
+		fields[issue.DocumentNumber+":"+issue.Field] = issue.Severity
+	}
+	assert.Equal(t, models.GSTIssueError, fields["INV-4:place_of_supply"])
+	assert.Equal(t, models.GSTIssueWarning, fields["CN-1:original_invoice_number"])
+}
+
+// TestBuildGSTR1Validation tests supplier and customer GSTIN checks
+func TestBuildGSTR1Validation(t *testing.T) {
+	date := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	docs := []models.GSTInvoiceTracking{
+		{InvoiceNumber: "INV-1", InvoiceDate: &date, InvoiceAmount: 1180, CustomerGSTIN: "BADGSTIN",
+			PlaceOfSupply: "27", HSNCode: "995411", GSTRate: 18, GSTAmount: 180},
+		{InvoiceNumber: "INV-2", InvoiceDate: &date, InvoiceAmount: 1180, CustomerGSTIN: "29AABCB5678K1Z2",
+			HSNCode: "995411", GSTRate: 18, GSTAmount: 180},
+	}
+
+	ret, issues := buildGSTR1("", "072025", docs)
+	assert.Len(t, ret.B2B, 1)
+	assert.Equal(t, "29", ret.B2B[0].Invoices[0].PlaceOfSupply)
+	assert.Equal(t, "gstin", issues[0].Field)
+	assert.Equal(t, "customer_gstin", issues[1].Field)
+	assert.Equal(t, models.GSTIssueWarning, issues[2].Severity)
+}
+
+// TestBuildGSTR3B tests outward summaries and ITC tables
+func TestBuildGSTR3B(t *testing.T) {
+	date := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	outward := []models.GSTInvoiceTracking{
+		{InvoiceNumber: "INV-1", InvoiceDate: &date, InvoiceAmount: 118000, CustomerGSTIN: "27AABCB5678K1Z2", GSTRate: 18, GSTAmount: 18000},
+		{InvoiceNumber: "INV-2", InvoiceDate: &date, InvoiceAmount: 105000, PlaceOfSupply: "29", GSTRate: 5, GSTAmount: 5000},
+		{InvoiceNumber: "CN-1", DocumentType: models.GSTDocumentCreditNote, InvoiceDate: &date, InvoiceAmount: 11800,
+			CustomerGSTIN: "27AABCB5678K1Z2", GSTRate: 18, GSTAmount: 1800},
+		{InvoiceNumber: "INV-3", InvoiceDate: &date, InvoiceAmount: 20000, PlaceOfSupply: "27"},
+	}
+	inward := []models.GSTInputCredit{
+		{InvoiceNumber: "P-1", VendorGSTIN: "27AAACS1111A1Z1", InvoiceAmount: 59000, GSTAmount: 9000, ITCEligible: true,
+			BlockedPercentage: 10},
+		{InvoiceNumber: "P-2", VendorGSTIN: "29AAACS2222B1Z3", InvoiceAmount: 11200, GSTAmount: 1200, ITCEligible: true},
+		{InvoiceNumber: "P-3", InvoiceAmount: 10500, GSTAmount: 500, ReverseCharge: true, ITCEligible: true},
+		{InvoiceNumber: "P-4", VendorGSTIN: "27AAACS1111A1Z1", InvoiceAmount: 1180, GSTAmount: 180, ITCEligible: false},
+		{InvoiceNumber: "P-5", InvoiceAmount: 1180, GSTAmount: 180, ITCEligible: true},
+	}
+
+	ret, issues := buildGSTR3B(testSupplierGSTIN, "072025", outward, inward)
+
+	sup := ret.SupplyDetails
+	assert.Equal(t, 190000.0, sup.OutwardTaxable.TaxableValue)
+	assert.Equal(t, 5000.0, sup.OutwardTaxable.IGSTAmount)
+	assert.Equal(t, 8100.0, sup.OutwardTaxable.CGSTAmount)
+	assert.Equal(t, 20000.0, sup.OutwardNilExemp.TaxableValue)
+	assert.Equal(t, 10000.0, sup.InwardReverse.TaxableValue)
+
+	assert.Len(t, ret.InterStateSupp.Unregistered, 1)
+	assert.Equal(t, "29", ret.InterStateSupp.Unregistered[0].PlaceOfSupply)
+	assert.Equal(t, 100000.0, ret.InterStateSupp.Unregistered[0].TaxableValue)
+
+	itc := ret.ITCEligibility
+	assert.Equal(t, "ISRC", itc.Available[2].Type)
+	assert.Equal(t, 250.0, itc.Available[2].CGSTAmount)
+	assert.Equal(t, "OTH", itc.Available[4].Type)
+	assert.Equal(t, 1200.0, itc.Available[4].IGSTAmount)
+	assert.Equal(t, 4500.0, itc.Available[4].CGSTAmount)
+	assert.Equal(t, 450.0, itc.Reversed[0].CGSTAmount)
+	assert.Equal(t, 4300.0, itc.Net.CGSTAmount)
+	assert.Equal(t, 90.0, itc.Ineligible[0].CGSTAmount)
+
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "P-5", issues[0].DocumentNumber)
+	assert.Equal(t, "vendor_gstin", issues[0].Field)
+}
@@ -0,0 +1,708 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// GSTR-2B IMPORT AND ITC RECONCILIATION
+// ============================================================================
+// Checks the input tax credit in our books (vendor invoices) against what
+// suppliers actually reported, as auto-drafted in the month's GSTR-2B.
+
+// itcMatchTolerance is the rupee difference ignored when comparing tax and taxable values
+const itcMatchTolerance = 1.0
+
+// booksExcludedInvoiceStatuses are vendor invoices that never carry ITC
+var booksExcludedInvoiceStatuses = []string{"Draft", "Rejected"}
+
+var (
+	invoiceNumberRunPattern = regexp.MustCompile(`[A-Z]+|[0-9]+`)
+	financialYearPattern    = regexp.MustCompile(`(?:^|[^0-9A-Z])(?:FY)?((?:20)?[0-9]{2})[-/]((?:20)?[0-9]{2})(?:$|[^0-9A-Z])`)
+)
+
+// ============================================================================
+// IMPORT
+// ============================================================================
+
+// ImportGSTR2B stores a GSTR-2B statement downloaded from the portal.
+// Re-importing a period replaces that period's documents.
+func (s *TaxComplianceService) ImportGSTR2B(tenantID, userID string, file *models.GSTR2BFile) (*models.GSTR2BImport, error) {
+	period := file.Data.ReturnPeriod
+	if _, _, err := ParseGSTReturnPeriod(period); err != nil {
+		return nil, err
+	}
+
+	imp := &models.GSTR2BImport{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		GSTIN:         strings.ToUpper(file.Data.GSTIN),
+		ReturnPeriod:  period,
+		GeneratedDate: file.Data.GeneratedDate,
+		ImportedBy:    userID,
+		CreatedAt:     time.Now(),
+	}
+
+	docs, err := flattenGSTR2B(file)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].ID = uuid.New().String()
+		docs[i].TenantID = tenantID
+		docs[i].ImportID = imp.ID
+		imp.TotalTaxable += docs[i].TaxableValue
+		imp.TotalTax += docs[i].TotalTax()
+	}
+	imp.DocumentCount = len(docs)
+	imp.TotalTaxable = roundTo2(imp.TotalTaxable)
+	imp.TotalTax = roundTo2(imp.TotalTax)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM gstr2b_documents WHERE tenant_id = ? AND return_period = ?`, tenantID, period); err != nil {
+		return nil, fmt.Errorf("failed to clear previous GSTR-2B documents: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO gstr2b_imports (
+		id, tenant_id, gstin, return_period, generated_date, document_count, total_taxable, total_tax, imported_by, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		imp.ID, imp.TenantID, imp.GSTIN, imp.ReturnPeriod, imp.GeneratedDate, imp.DocumentCount,
+		imp.TotalTaxable, imp.TotalTax, nullIfEmpty(imp.ImportedBy), imp.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record GSTR-2B import: %w", err)
+	}
+
+	for _, doc := range docs {
+		_, err = tx.Exec(`INSERT INTO gstr2b_documents (
+			id, tenant_id, import_id, return_period, supplier_gstin, supplier_name, document_type, document_number,
+			normalized_number, document_date, document_value, taxable_value, igst_amount, cgst_amount, sgst_amount,
+			cess_amount, itc_available, reverse_charge, reason, supplier_filed_on, supplier_filing_period
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			doc.ID, doc.TenantID, doc.ImportID, doc.ReturnPeriod, doc.SupplierGSTIN, doc.SupplierName, doc.DocumentType,
+			doc.DocumentNumber, normalizeInvoiceNumber(doc.DocumentNumber), doc.DocumentDate, doc.DocumentValue,
+			doc.TaxableValue, doc.IGSTAmount, doc.CGSTAmount, doc.SGSTAmount, doc.CessAmount, doc.ITCAvailable,
+			doc.ReverseCharge, doc.Reason, doc.SupplierFiledOn, doc.SupplierFilingPd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store GSTR-2B document %s: %w", doc.DocumentNumber, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit GSTR-2B import: %w", err)
+	}
+	return imp, nil
+}
+
+// ListGSTR2BImports lists uploaded statements, optionally for one period
+func (s *TaxComplianceService) ListGSTR2BImports(tenantID, period string) ([]models.GSTR2BImport, error) {
+	query := `SELECT id, tenant_id, gstin, return_period, COALESCE(generated_date, ''), document_count,
+		total_taxable, total_tax, COALESCE(imported_by, ''), created_at
+		FROM gstr2b_imports WHERE tenant_id = ?`
+	args := []interface{}{tenantID}
+	if period != "" {
+		query += " AND return_period = ?"
+		args = append(args, period)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GSTR-2B imports: %w", err)
+	}
+	defer rows.Close()
+
+	var imports []models.GSTR2BImport
+	for rows.Next() {
+		var imp models.GSTR2BImport
+		if err := rows.Scan(&imp.ID, &imp.TenantID, &imp.GSTIN, &imp.ReturnPeriod, &imp.GeneratedDate,
+			&imp.DocumentCount, &imp.TotalTaxable, &imp.TotalTax, &imp.ImportedBy, &imp.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan GSTR-2B import: %w", err)
+		}
+		imports = append(imports, imp)
+	}
+	return imports, rows.Err()
+}
+
+// flattenGSTR2B turns the supplier-wise B2B and CDNR sections into documents
+func flattenGSTR2B(file *models.GSTR2BFile) ([]models.GSTR2BDocument, error) {
+	period := file.Data.ReturnPeriod
+	var docs []models.GSTR2BDocument
+
+	newDoc := func(sup models.GSTR2BSupplier, docType, number, date string, value float64, rev, itcavl, reason string, items []models.GSTR2BItem) (models.GSTR2BDocument, error) {
+		doc := models.GSTR2BDocument{
+			ReturnPeriod:     period,
+			SupplierGSTIN:    strings.ToUpper(sup.SupplierGSTIN),
+			SupplierName:     sup.TradeName,
+			DocumentType:     docType,
+			DocumentNumber:   number,
+			DocumentValue:    value,
+			ITCAvailable:     !strings.EqualFold(itcavl, "N"),
+			ReverseCharge:    strings.EqualFold(rev, "Y"),
+			Reason:           reason,
+			SupplierFiledOn:  sup.FilingDate,
+			SupplierFilingPd: sup.FilingPeriod,
+		}
+		if date != "" {
+			d, err := time.Parse(gstReturnDateLayout, date)
+			if err != nil {
+				return doc, fmt.Errorf("invalid date %q on %s from %s", date, number, sup.SupplierGSTIN)
+			}
+			doc.DocumentDate = &d
+		}
+		for _, item := range items {
+			doc.TaxableValue += item.TaxableValue
+			doc.IGSTAmount += item.IGSTAmount
+			doc.CGSTAmount += item.CGSTAmount
+			doc.SGSTAmount += item.SGSTAmount
+			doc.CessAmount += item.CessAmount
+		}
+		return doc, nil
+	}
+
+	for _, sup := range file.Data.DocData.B2B {
+		for _, inv := range sup.Invoices {
+			doc, err := newDoc(sup, models.GSTDocumentInvoice, inv.InvoiceNumber, inv.Date, inv.Value,
+				inv.ReverseCharge, inv.ITCAvailable, inv.Reason, inv.Items)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+	}
+	for _, sup := range file.Data.DocData.CDNR {
+		for _, note := range sup.Notes {
+			docType := models.GSTDocumentDebitNote
+			if strings.EqualFold(note.Type, "C") {
+				docType = models.GSTDocumentCreditNote
+			}
+			doc, err := newDoc(sup, docType, note.NoteNumber, note.Date, note.Value,
+				note.ReverseCharge, note.ITCAvailable, note.Reason, note.Items)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// ============================================================================
+// RECONCILIATION
+// ============================================================================
+
+// ReconcileITC matches the period's GSTR-2B against vendor invoices in the books
+// and stores the outcome. Books invoices matched in another period are skipped;
+// unmatched ones stay open until the ITC claim window for their year closes.
+func (s *TaxComplianceService) ReconcileITC(tenantID, period string) (*models.ITCReconciliationResult, error) {
+	from, to, err := ParseGSTReturnPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.getGSTR2BDocuments(tenantID, period)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no GSTR-2B imported for period %s", period)
+	}
+
+	books, err := s.getITCBooksInvoices(tenantID, period, itcClaimWindowStart(from), to)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := reconcileITC(books, docs)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM itc_reconciliation_lines WHERE tenant_id = ? AND return_period = ?`, tenantID, period); err != nil {
+		return nil, fmt.Errorf("failed to clear previous reconciliation: %w", err)
+	}
+
+	now := time.Now()
+	for _, line := range lines {
+		_, err = tx.Exec(`INSERT INTO itc_reconciliation_lines (
+			id, tenant_id, return_period, status, match_type, supplier_gstin, vendor_id, vendor_name,
+			vendor_invoice_id, books_invoice_number, books_invoice_date, books_taxable, books_tax,
+			gstr2b_document_id, gstr2b_number, gstr2b_date, gstr2b_taxable, gstr2b_tax,
+			tax_difference, itc_at_risk, reasons, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), tenantID, period, line.Status, nullIfEmpty(line.MatchType), line.SupplierGSTIN,
+			nullIfEmpty(line.VendorID), line.VendorName, nullIfEmpty(line.BooksInvoiceID), nullIfEmpty(line.BooksInvoiceNumber),
+			line.BooksInvoiceDate, line.BooksTaxable, line.BooksTax, nullIfEmpty(line.GSTR2BDocumentID),
+			nullIfEmpty(line.GSTR2BNumber), line.GSTR2BDate, line.GSTR2BTaxable, line.GSTR2BTax,
+			line.TaxDifference, line.ITCAtRisk, strings.Join(line.Reasons, ","), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store reconciliation line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reconciliation: %w", err)
+	}
+	return buildITCReconciliationResult(period, lines), nil
+}
+
+// GetITCAtRiskReport summarises the ITC exposure from the period's last reconciliation
+func (s *TaxComplianceService) GetITCAtRiskReport(tenantID, period string) (*models.ITCAtRiskReport, error) {
+	if _, _, err := ParseGSTReturnPeriod(period); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(`SELECT status, COALESCE(match_type, ''), supplier_gstin, COALESCE(vendor_id, ''),
+		COALESCE(vendor_name, ''), COALESCE(vendor_invoice_id, ''), COALESCE(books_invoice_number, ''),
+		books_invoice_date, books_taxable, books_tax, COALESCE(gstr2b_document_id, ''), COALESCE(gstr2b_number, ''),
+		gstr2b_date, gstr2b_taxable, gstr2b_tax, tax_difference, itc_at_risk, COALESCE(reasons, ''), created_at
+		FROM itc_reconciliation_lines WHERE tenant_id = ? AND return_period = ?`, tenantID, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reconciliation lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []models.ITCReconciliationLine
+	var reconciledAt *time.Time
+	for rows.Next() {
+		var line models.ITCReconciliationLine
+		var booksDate, docDate sql.NullTime
+		var reasons string
+		var createdAt time.Time
+		if err := rows.Scan(&line.Status, &line.MatchType, &line.SupplierGSTIN, &line.VendorID, &line.VendorName,
+			&line.BooksInvoiceID, &line.BooksInvoiceNumber, &booksDate, &line.BooksTaxable, &line.BooksTax,
+			&line.GSTR2BDocumentID, &line.GSTR2BNumber, &docDate, &line.GSTR2BTaxable, &line.GSTR2BTax,
+			&line.TaxDifference, &line.ITCAtRisk, &reasons, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reconciliation line: %w", err)
+		}
+		if booksDate.Valid {
+			line.BooksInvoiceDate = &booksDate.Time
+		}
+		if docDate.Valid {
+			line.GSTR2BDate = &docDate.Time
+		}
+		if reasons != "" {
+			line.Reasons = strings.Split(reasons, ",")
+		}
+		if reconciledAt == nil {
+			reconciledAt = &createdAt
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("ITC has not been reconciled for period %s", period)
+	}
+
+	report := buildITCAtRiskReport(period, lines)
+	report.ReconciledAt = reconciledAt
+	return report, nil
+}
+
+func (s *TaxComplianceService) getGSTR2BDocuments(tenantID, period string) ([]models.GSTR2BDocument, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, import_id, return_period, supplier_gstin, COALESCE(supplier_name, ''),
+		document_type, document_number, document_date, document_value, taxable_value, igst_amount, cgst_amount,
+		sgst_amount, cess_amount, itc_available, reverse_charge, COALESCE(reason, ''),
+		COALESCE(supplier_filed_on, ''), COALESCE(supplier_filing_period, '')
+		FROM gstr2b_documents WHERE tenant_id = ? AND return_period = ?
+		ORDER BY supplier_gstin, document_number`, tenantID, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GSTR-2B documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []models.GSTR2BDocument
+	for rows.Next() {
+		var doc models.GSTR2BDocument
+		var docDate sql.NullTime
+		if err := rows.Scan(&doc.ID, &doc.TenantID, &doc.ImportID, &doc.ReturnPeriod, &doc.SupplierGSTIN,
+			&doc.SupplierName, &doc.DocumentType, &doc.DocumentNumber, &docDate, &doc.DocumentValue,
+			&doc.TaxableValue, &doc.IGSTAmount, &doc.CGSTAmount, &doc.SGSTAmount, &doc.CessAmount,
+			&doc.ITCAvailable, &doc.ReverseCharge, &doc.Reason, &doc.SupplierFiledOn, &doc.SupplierFilingPd); err != nil {
+			return nil, fmt.Errorf("failed to scan GSTR-2B document: %w", err)
+		}
+		if docDate.Valid {
+			doc.DocumentDate = &docDate.Time
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// getITCBooksInvoices loads vendor invoices in [from, to) that were not matched in another period
+func (s *TaxComplianceService) getITCBooksInvoices(tenantID, period string, from, to time.Time) ([]models.ITCBooksInvoice, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(booksExcludedInvoiceStatuses)), ", ")
+	query := `SELECT vi.id, vi.invoice_number, vi.invoice_date, vi.vendor_id, COALESCE(v.name, ''),
+		UPPER(COALESCE(v.tax_id, '')), vi.invoice_amount - vi.discount_amount, vi.tax_amount
+		FROM vendor_invoices vi
+		LEFT JOIN vendors v ON v.id = vi.vendor_id
+		WHERE vi.tenant_id = ? AND vi.invoice_date >= ? AND vi.invoice_date < ?
+		AND vi.tax_amount > 0 AND vi.status NOT IN (` + placeholders + `)
+		AND NOT EXISTS (SELECT 1 FROM itc_reconciliation_lines l
+			WHERE l.tenant_id = vi.tenant_id AND l.vendor_invoice_id = vi.id
+			AND l.status = ? AND l.return_period <> ?)
+		ORDER BY vi.invoice_date`
+	args := []interface{}{tenantID, from, to}
+	for _, status := range booksExcludedInvoiceStatuses {
+		args = append(args, status)
+	}
+	args = append(args, models.ITCReconMatched, period)
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendor invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []models.ITCBooksInvoice
+	for rows.Next() {
+		var inv models.ITCBooksInvoice
+		if err := rows.Scan(&inv.InvoiceID, &inv.InvoiceNumber, &inv.InvoiceDate, &inv.VendorID, &inv.VendorName,
+			&inv.VendorGSTIN, &inv.TaxableValue, &inv.TaxAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan vendor invoice: %w", err)
+		}
+		invoices = append(invoices, inv)
+	}
+	return invoices, rows.Err()
+}
+
+// ============================================================================
+// MATCHING
+// ============================================================================
+
+// itcClaimWindowStart is the earliest invoice date whose ITC can still be claimed in
+// a return period: a financial year's ITC lapses after 30 November of the next year
+func itcClaimWindowStart(periodStart time.Time) time.Time {
+	year := periodStart.Year()
+	if periodStart.Month() <= time.November {
+		year--
+	}
+	return time.Date(year, time.April, 1, 0, 0, 0, 0, periodStart.Location())
+}
+
+// normalizeInvoiceNumber reduces an invoice number to its letters and numbers so that
+// "INV/001/25-26", "inv-1 (2025-26)" and "INV1" compare equal. Leading zeros are
+// dropped from numeric runs and a financial year suffix or prefix is ignored.
+func normalizeInvoiceNumber(number string) string {
+	runs := invoiceNumberRunPattern.FindAllString(stripFinancialYear(strings.ToUpper(number)), -1)
+	for i, run := range runs {
+		if run[0] >= '0' && run[0] <= '9' {
+			runs[i] = strings.TrimLeft(run, "0")
+			if runs[i] == "" {
+				runs[i] = "0"
+			}
+		}
+	}
+	return strings.Join(runs, "")
+}
+
+// invoiceSerial is the last numeric run of a normalised invoice number, used as a
+// fallback key when suppliers and clerks disagree on prefixes
+func invoiceSerial(number string) string {
+	runs := invoiceNumberRunPattern.FindAllString(stripFinancialYear(strings.ToUpper(number)), -1)
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i][0] >= '0' && runs[i][0] <= '9' {
+			serial := strings.TrimLeft(runs[i], "0")
+			if serial == "" {
+				serial = "0"
+			}
+			return serial
+		}
+	}
+	return ""
+}
+
+// stripFinancialYear removes a "2025-26" / "FY25-26" / "2025/2026" segment when the
+// number has other digits left to identify it
+func stripFinancialYear(number string) string {
+	for _, m := range financialYearPattern.FindAllStringSubmatchIndex(number, -1) {
+		first, _ := strconv.Atoi(number[m[2]:m[3]])
+		second, _ := strconv.Atoi(number[m[4]:m[5]])
+		if (first+1)%100 != second%100 {
+			continue
+		}
+		stripped := number[:m[0]] + " " + number[m[1]:]
+		if strings.ContainsAny(stripped, "0123456789") {
+			return stripped
+		}
+	}
+	return number
+}
+
+// reconcileITC pairs books invoices with 2B documents and buckets every document.
+// Pairs are found first on GSTIN + normalised number, then on GSTIN + serial
+// number where the tax amount also agrees.
+func reconcileITC(books []models.ITCBooksInvoice, docs []models.GSTR2BDocument) []models.ITCReconciliationLine {
+	type docKey struct{ gstin, number string }
+	used := make([]bool, len(docs))
+	exact := make(map[docKey][]int)
+	for i, doc := range docs {
+		if doc.DocumentType == models.GSTDocumentCreditNote {
+			continue
+		}
+		key := docKey{doc.SupplierGSTIN, normalizeInvoiceNumber(doc.DocumentNumber)}
+		exact[key] = append(exact[key], i)
+	}
+
+	take := func(candidates []int, accept func(models.GSTR2BDocument) bool) int {
+		for _, i := range candidates {
+			if !used[i] && accept(docs[i]) {
+				used[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	var lines []models.ITCReconciliationLine
+	var unmatched []models.ITCBooksInvoice
+	for _, inv := range books {
+		if inv.VendorGSTIN == "" {
+			lines = append(lines, missingIn2BLine(inv, models.ITCReasonNoVendorGSTIN))
+			continue
+		}
+		key := docKey{inv.VendorGSTIN, normalizeInvoiceNumber(inv.InvoiceNumber)}
+		if i := take(exact[key], func(models.GSTR2BDocument) bool { return true }); i >= 0 {
+			lines = append(lines, compareITC(inv, docs[i], models.ITCMatchExact))
+			continue
+		}
+		unmatched = append(unmatched, inv)
+	}
+
+	for _, inv := range unmatched {
+		serial := invoiceSerial(inv.InvoiceNumber)
+		var candidates []int
+		for i, doc := range docs {
+			if doc.SupplierGSTIN == inv.VendorGSTIN && doc.DocumentType != models.GSTDocumentCreditNote &&
+				serial != "" && invoiceSerial(doc.DocumentNumber) == serial {
+				candidates = append(candidates, i)
+			}
+		}
+		i := take(candidates, func(doc models.GSTR2BDocument) bool {
+			return math.Abs(inv.TaxAmount-doc.TotalTax()) <= itcMatchTolerance
+		})
+		if i >= 0 {
+			lines = append(lines, compareITC(inv, docs[i], models.ITCMatchFuzzy))
+			continue
+		}
+		lines = append(lines, missingIn2BLine(inv))
+	}
+
+	vendorNames := make(map[string]string)
+	for _, inv := range books {
+		vendorNames[inv.VendorGSTIN] = inv.VendorName
+	}
+	for i, doc := range docs {
+		if used[i] {
+			continue
+		}
+		factor := 1.0
+		if doc.DocumentType == models.GSTDocumentCreditNote {
+			factor = -1
+		}
+		name := vendorNames[doc.SupplierGSTIN]
+		if name == "" {
+			name = doc.SupplierName
+		}
+		lines = append(lines, models.ITCReconciliationLine{
+			Status:           models.ITCReconMissingInBooks,
+			SupplierGSTIN:    doc.SupplierGSTIN,
+			VendorName:       name,
+			GSTR2BDocumentID: doc.ID,
+			GSTR2BNumber:     doc.DocumentNumber,
+			GSTR2BDate:       doc.DocumentDate,
+			GSTR2BTaxable:    roundTo2(factor * doc.TaxableValue),
+			GSTR2BTax:        roundTo2(factor * doc.TotalTax()),
+			TaxDifference:    roundTo2(-factor * doc.TotalTax()),
+			Reasons:          []string{},
+		})
+	}
+	return lines
+}
+
+func missingIn2BLine(inv models.ITCBooksInvoice, reasons ...string) models.ITCReconciliationLine {
+	date := inv.InvoiceDate
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return models.ITCReconciliationLine{
+		Status:             models.ITCReconMissingIn2B,
+		SupplierGSTIN:      inv.VendorGSTIN,
+		VendorID:           inv.VendorID,
+		VendorName:         inv.VendorName,
+		BooksInvoiceID:     inv.InvoiceID,
+		BooksInvoiceNumber: inv.InvoiceNumber,
+		BooksInvoiceDate:   &date,
+		BooksTaxable:       roundTo2(inv.TaxableValue),
+		BooksTax:           roundTo2(inv.TaxAmount),
+		TaxDifference:      roundTo2(inv.TaxAmount),
+		ITCAtRisk:          roundTo2(inv.TaxAmount),
+		Reasons:            reasons,
+	}
+}
+
+// compareITC checks a paired invoice's date and amounts. ITC above what the supplier
+// reported, or reported as not available, is at risk.
+func compareITC(inv models.ITCBooksInvoice, doc models.GSTR2BDocument, matchType string) models.ITCReconciliationLine {
+	date := inv.InvoiceDate
+	line := models.ITCReconciliationLine{
+		Status:             models.ITCReconMatched,
+		MatchType:          matchType,
+		SupplierGSTIN:      inv.VendorGSTIN,
+		VendorID:           inv.VendorID,
+		VendorName:         inv.VendorName,
+		BooksInvoiceID:     inv.InvoiceID,
+		BooksInvoiceNumber: inv.InvoiceNumber,
+		BooksInvoiceDate:   &date,
+		BooksTaxable:       roundTo2(inv.TaxableValue),
+		BooksTax:           roundTo2(inv.TaxAmount),
+		GSTR2BDocumentID:   doc.ID,
+		GSTR2BNumber:       doc.DocumentNumber,
+		GSTR2BDate:         doc.DocumentDate,
+		GSTR2BTaxable:      roundTo2(doc.TaxableValue),
+		GSTR2BTax:          roundTo2(doc.TotalTax()),
+		Reasons:            []string{},
+	}
+	line.TaxDifference = roundTo2(line.BooksTax - line.GSTR2BTax)
+
+	if math.Abs(line.TaxDifference) > itcMatchTolerance {
+		line.Reasons = append(line.Reasons, models.ITCReasonTaxDifference)
+		if line.TaxDifference > 0 {
+			line.ITCAtRisk = line.TaxDifference
+		}
+	}
+	if math.Abs(line.BooksTaxable-line.GSTR2BTaxable) > itcMatchTolerance {
+		line.Reasons = append(line.Reasons, models.ITCReasonTaxableDifference)
+	}
+	if doc.DocumentDate != nil && !sameDay(inv.InvoiceDate, *doc.DocumentDate) {
+		line.Reasons = append(line.Reasons, models.ITCReasonDateDifference)
+	}
+	if !doc.ITCAvailable {
+		line.Reasons = append(line.Reasons, models.ITCReasonNotAvailable)
+		line.ITCAtRisk = line.BooksTax
+	}
+	if len(line.Reasons) > 0 {
+		line.Status = models.ITCReconMismatched
+	}
+	return line
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// ============================================================================
+// SUMMARIES
+// ============================================================================
+
+func buildITCReconciliationResult(period string, lines []models.ITCReconciliationLine) *models.ITCReconciliationResult {
+	result := &models.ITCReconciliationResult{
+		Period:         period,
+		Matched:        []models.ITCReconciliationLine{},
+		Mismatched:     []models.ITCReconciliationLine{},
+		MissingIn2B:    []models.ITCReconciliationLine{},
+		MissingInBooks: []models.ITCReconciliationLine{},
+	}
+	sum := &result.Summary
+	for _, line := range lines {
+		sum.BooksITC += line.BooksTax
+		sum.GSTR2BITC += line.GSTR2BTax
+		sum.ITCAtRisk += line.ITCAtRisk
+		switch line.Status {
+		case models.ITCReconMatched:
+			result.Matched = append(result.Matched, line)
+			sum.MatchedITC += line.BooksTax
+		case models.ITCReconMismatched:
+			result.Mismatched = append(result.Mismatched, line)
+			sum.MatchedITC += line.BooksTax - line.ITCAtRisk
+		case models.ITCReconMissingIn2B:
+			result.MissingIn2B = append(result.MissingIn2B, line)
+		case models.ITCReconMissingInBooks:
+			result.MissingInBooks = append(result.MissingInBooks, line)
+			sum.UnbookedITC += line.GSTR2BTax
+		}
+	}
+	sum.MatchedCount = len(result.Matched)
+	sum.MismatchedCount = len(result.Mismatched)
+	sum.MissingIn2BCount = len(result.MissingIn2B)
+	sum.MissingInBooksCount = len(result.MissingInBooks)
+	sum.BooksITC = roundTo2(sum.BooksITC)
+	sum.GSTR2BITC = roundTo2(sum.GSTR2BITC)
+	sum.MatchedITC = roundTo2(sum.MatchedITC)
+	sum.ITCAtRisk = roundTo2(sum.ITCAtRisk)
+	sum.UnbookedITC = roundTo2(sum.UnbookedITC)
+	return result
+}
+
+// buildITCAtRiskReport totals the month's exposure and ranks vendors by ITC at risk
+func buildITCAtRiskReport(period string, lines []models.ITCReconciliationLine) *models.ITCAtRiskReport {
+	summary := buildITCReconciliationResult(period, lines).Summary
+	report := &models.ITCAtRiskReport{
+		Period:         period,
+		BooksITC:       summary.BooksITC,
+		MatchedITC:     summary.MatchedITC,
+		TotalITCAtRisk: summary.ITCAtRisk,
+		UnbookedITC:    summary.UnbookedITC,
+		Vendors:        []models.ITCAtRiskVendor{},
+	}
+
+	byVendor := make(map[string]*models.ITCAtRiskVendor)
+	for _, line := range lines {
+		switch line.Status {
+		case models.ITCReconMissingIn2B:
+			report.MissingIn2BITC += line.ITCAtRisk
+		case models.ITCReconMismatched:
+			report.MismatchITC += line.ITCAtRisk
+		}
+		if line.ITCAtRisk <= 0 {
+			continue
+		}
+		key := line.SupplierGSTIN
+		if key == "" {
+			key = "vendor:" + line.VendorID
+		}
+		v, ok := byVendor[key]
+		if !ok {
+			v = &models.ITCAtRiskVendor{SupplierGSTIN: line.SupplierGSTIN, VendorName: line.VendorName}
+			byVendor[key] = v
+		}
+		v.DocumentCount++
+		v.ITCAtRisk += line.ITCAtRisk
+	}
+	report.MissingIn2BITC = roundTo2(report.MissingIn2BITC)
+	report.MismatchITC = roundTo2(report.MismatchITC)
+
+	for _, v := range byVendor {
+		v.ITCAtRisk = roundTo2(v.ITCAtRisk)
+		report.Vendors = append(report.Vendors, *v)
+	}
+	sort.Slice(report.Vendors, func(i, j int) bool {
+		if report.Vendors[i].ITCAtRisk != report.Vendors[j].ITCAtRisk {
+			return report.Vendors[i].ITCAtRisk > report.Vendors[j].ITCAtRisk
+		}
+		return report.Vendors[i].SupplierGSTIN < report.Vendors[j].SupplierGSTIN
+	})
+	return report
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeInvoiceNumber tests fuzzy invoice number normalisation
+func TestNormalizeInvoiceNumber(t *testing.T) {
+	assert.Equal(t, "INV1", normalizeInvoiceNumber("INV/001/25-26"))
+	assert.Equal(t, "INV1", normalizeInvoiceNumber("inv-1 (2025-26)"))
+	assert.Equal(t, "INV1", normalizeInvoiceNumber("INV0001"))
+	assert.Equal(t, "SB45", normalizeInvoiceNumber("FY24-25/SB/045"))
+	// Non-consecutive years and bare year ranges are kept
+	assert.Equal(t, "INV72325", normalizeInvoiceNumber("INV/7/23-25"))
+	assert.Equal(t, "2425", normalizeInvoiceNumber("24-25"))
+	assert.Equal(t, "A1020", normalizeInvoiceNumber("A/10-20"))
+
+	assert.Equal(t, "45", invoiceSerial("SB/045/2024-25"))
+	assert.Equal(t, "", invoiceSerial("ABC"))
+}
+
+// TestITCClaimWindowStart tests the section 16(4) cut-off for older invoices
+func TestITCClaimWindowStart(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), itcClaimWindowStart(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), itcClaimWindowStart(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), itcClaimWindowStart(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestReconcileITC tests bucketing of books invoices against GSTR-2B
+func TestReconcileITC(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	dayPtr := func(d int) *time.Time { v := day(d); return &v }
+	const steel, cement = "27AAACS1111A1Z5", "27AAACC2222B1Z6"
+
+	books := []models.ITCBooksInvoice{
+		{InvoiceID: "b1", InvoiceNumber: "INV-001/25-26", InvoiceDate: day(3), VendorGSTIN: steel, VendorName: "Steel Co", TaxableValue: 100000, TaxAmount: 18000},
+		{InvoiceID: "b2", InvoiceNumber: "SC/77", InvoiceDate: day(5), VendorGSTIN: cement, VendorName: "Cement Co", TaxableValue: 50000, TaxAmount: 9000},
+		{InvoiceID: "b3", InvoiceNumber: "C-12", InvoiceDate: day(8), VendorGSTIN: cement, VendorName: "Cement Co", TaxableValue: 20000, TaxAmount: 3600},
+		{InvoiceID: "b4", InvoiceNumber: "ST-9", InvoiceDate: day(9), VendorGSTIN: steel, VendorName: "Steel Co", TaxableValue: 10000, TaxAmount: 1800},
+		{InvoiceID: "b5", InvoiceNumber: "X1", InvoiceDate: day(9), VendorName: "Unregistered", TaxableValue: 1000, TaxAmount: 180},
+	}
+	docs := []models.GSTR2BDocument{
+		{ID: "d1", SupplierGSTIN: steel, DocumentType: "INV", DocumentNumber: "INV1", DocumentDate: dayPtr(3), TaxableValue: 100000, IGSTAmount: 18000, ITCAvailable: true},
+		{ID: "d2", SupplierGSTIN: cement, DocumentType: "INV", DocumentNumber: "SC77", DocumentDate: dayPtr(6), TaxableValue: 40000, CGSTAmount: 3600, SGSTAmount: 3600, ITCAvailable: true},
+		{ID: "d3", SupplierGSTIN: cement, DocumentType: "INV", DocumentNumber: "CEM/0012", DocumentDate: dayPtr(8), TaxableValue: 20000, CGSTAmount: 1800, SGSTAmount: 1800, ITCAvailable: true},
+		{ID: "d4", SupplierGSTIN: cement, DocumentType: "INV", DocumentNumber: "SC/80", DocumentDate: dayPtr(20), TaxableValue: 5000, IGSTAmount: 900, ITCAvailable: true},
+		{ID: "d5", SupplierGSTIN: steel, DocumentType: "CRN", DocumentNumber: "CN-1", DocumentDate: dayPtr(25), TaxableValue: 2000, IGSTAmount: 360, ITCAvailable: true},
+	}
+
+	result := buildITCReconciliationResult("072025", reconcileITC(books, docs))
+
+	assert.Len(t, result.Matched, 2)
+	assert.Equal(t, models.ITCMatchExact, result.Matched[0].MatchType)
+	assert.Equal(t, models.ITCMatchFuzzy, result.Matched[1].MatchType)
+	assert.Equal(t, "d3", result.Matched[1].GSTR2BDocumentID)
+
+	assert.Len(t, result.Mismatched, 1)
+	assert.Equal(t, 1800.0, result.Mismatched[0].ITCAtRisk)
+	assert.Equal(t, []string{models.ITCReasonTaxDifference, models.ITCReasonTaxableDifference, models.ITCReasonDateDifference},
+		result.Mismatched[0].Reasons)
+
+	assert.Len(t, result.MissingIn2B, 2)
+	assert.Equal(t, []string{models.ITCReasonNoVendorGSTIN}, result.MissingIn2B[0].Reasons)
+
+	assert.Len(t, result.MissingInBooks, 2)
+	assert.Equal(t, -360.0, result.MissingInBooks[1].GSTR2BTax)
+
+	assert.Equal(t, 32580.0, result.Summary.BooksITC)
+	assert.Equal(t, 1800.0+1800+180, result.Summary.ITCAtRisk)
+	assert.Equal(t, 540.0, result.Summary.UnbookedITC)
+
+	report := buildITCAtRiskReport("072025", reconcileITC(books, docs))
+	assert.Equal(t, 1980.0, report.MissingIn2BITC)
+	assert.Equal(t, 1800.0, report.MismatchITC)
+	assert.Len(t, report.Vendors, 3)
+}
+
+// TestCompareITCNotAvailable tests that ITC blocked in 2B is fully at risk
+func TestCompareITCNotAvailable(t *testing.T) {
+	date := time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC)
+	inv := models.ITCBooksInvoice{InvoiceNumber: "1", InvoiceDate: date, VendorGSTIN: "27AAACS1111A1Z5", TaxableValue: 1000, TaxAmount: 180}
+	doc := models.GSTR2BDocument{DocumentNumber: "1", DocumentDate: &date, TaxableValue: 1000, IGSTAmount: 180.5, Reason: "P"}
+
+	line := compareITC(inv, doc, models.ITCMatchExact)
+	assert.Equal(t, models.ITCReconMismatched, line.Status)
+	assert.Equal(t, []string{models.ITCReasonNotAvailable}, line.Reasons)
+	assert.Equal(t, 180.0, line.ITCAtRisk)
+}
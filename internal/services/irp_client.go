@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"vyomtech-backend/internal/models"
+)
+
+// IRPClient is implemented by invoice registration portal / e-way bill providers
+type IRPClient interface {
+	GenerateIRN(ctx context.Context, payload *models.EInvoicePayload) (*models.IRNResponse, error)
+	CancelIRN(ctx context.Context, irn, reasonCode, remarks string) (*models.IRNCancelResponse, error)
+	GenerateEWayBill(ctx context.Context, payload *models.EWayBillPayload) (*models.EWayBillResponse, error)
+	CancelEWayBill(ctx context.Context, ewbNo int64, reasonCode int, remarks string) (*models.EWayBillCancelResponse, error)
+}
+
+// NewIRPClient returns the client configured in the tenant's e-invoice settings
+func NewIRPClient(settings *models.EInvoiceSettings) (IRPClient, error) {
+	switch settings.Provider {
+	case models.IRPProviderStub:
+		return NewStubIRPClient(), nil
+	case models.IRPProviderGSP:
+		if settings.APIBaseURL == "" {
+			return nil, fmt.Errorf("api_base_url is required for provider %s", settings.Provider)
+		}
+		return NewGSPIRPClient(settings), nil
+	default:
+		return nil, fmt.Errorf("unsupported IRP provider: %s", settings.Provider)
+	}
+}
+
+// irpDateTimeLayout is the AckDt / CancelDate layout used by the IRP
+const irpDateTimeLayout = "2006-01-02 15:04:05"
+
+// ewbDateTimeLayout is the ewayBillDate / validUpto layout used by the e-way bill system
+const ewbDateTimeLayout = "02/01/2006 03:04:05 PM"
+
+// computeIRN derives the invoice reference number the way the IRP does:
+// SHA-256 of supplier GSTIN, financial year, document type and document number
+func computeIRN(sellerGSTIN, financialYear, docType, docNumber string) string {
+	sum := sha256.Sum256([]byte(sellerGSTIN + financialYear + docType + docNumber))
+	return hex.EncodeToString(sum[:])
+}
+
+// gstFinancialYear returns the financial year (e.g. 2025-26) a document date falls in
+func gstFinancialYear(d time.Time) string {
+	start := d.Year()
+	if d.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// ewbValidityDays is one day per 200 km (or part) for regular cargo
+func ewbValidityDays(distanceKm int) int {
+	if distanceKm <= 0 {
+		return 1
+	}
+	return int(math.Ceil(float64(distanceKm) / 200))
+}
+
+// ============================================================================
+// STUB CLIENT
+// ============================================================================
+
+// StubIRPClient registers documents locally without contacting the IRP.
+// IRNs are computed exactly as the IRP would; signatures are placeholders.
+type StubIRPClient struct {
+	sequence int64
+	now      func() time.Time
+}
+
+// NewStubIRPClient creates a local IRP stub
+func NewStubIRPClient() *StubIRPClient {
+	return &StubIRPClient{sequence: time.Now().Unix() % 1000000, now: time.Now}
+}
+
+func (c *StubIRPClient) next() int64 {
+	return atomic.AddInt64(&c.sequence, 1)
+}
+
+// GenerateIRN returns an IRN and a stub-signed invoice and QR code
+func (c *StubIRPClient) GenerateIRN(ctx context.Context, payload *models.EInvoicePayload) (*models.IRNResponse, error) {
+	docDate, err := time.Parse("02/01/2006", payload.DocDtls.Dt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document date: %w", err)
+	}
+
+	irn := computeIRN(payload.SellerDtls.Gstin, gstFinancialYear(docDate), payload.DocDtls.Typ, payload.DocDtls.No)
+	now := c.now()
+
+	mainHSN := ""
+	if len(payload.ItemList) > 0 {
+		mainHSN = payload.ItemList[0].HsnCd
+	}
+	qr := map[string]interface{}{
+		"SellerGstin": payload.SellerDtls.Gstin,
+		"BuyerGstin":  payload.BuyerDtls.Gstin,
+		"DocNo":       payload.DocDtls.No,
+		"DocTyp":      payload.DocDtls.Typ,
+		"DocDt":       payload.DocDtls.Dt,
+		"TotInvVal":   payload.ValDtls.TotInvVal,
+		"ItemCnt":     len(payload.ItemList),
+		"MainHsnCode": mainHSN,
+		"Irn":         irn,
+		"IrnDt":       now.Format(irpDateTimeLayout),
+	}
+	signedQR, err := stubSign(map[string]interface{}{"data": qr})
+	if err != nil {
+		return nil, err
+	}
+	signedInvoice, err := stubSign(map[string]interface{}{"data": payload})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IRNResponse{
+		AckNo:         int64(now.Year()%100)*1e13 + c.next(),
+		AckDt:         now.Format(irpDateTimeLayout),
+		Irn:           irn,
+		SignedInvoice: signedInvoice,
+		SignedQRCode:  signedQR,
+		Status:        "ACT",
+	}, nil
+}
+
+// CancelIRN acknowledges the cancellation
+func (c *StubIRPClient) CancelIRN(ctx context.Context, irn, reasonCode, remarks string) (*models.IRNCancelResponse, error) {
+	return &models.IRNCancelResponse{Irn: irn, CancelDate: c.now().Format(irpDateTimeLayout)}, nil
+}
+
+// GenerateEWayBill returns an e-way bill number valid for the transport distance
+func (c *StubIRPClient) GenerateEWayBill(ctx context.Context, payload *models.EWayBillPayload) (*models.EWayBillResponse, error) {
+	distance, _ := strconv.Atoi(payload.TransDistance)
+	now := c.now()
+	validUpto := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, now.Location()).AddDate(0, 0, ewbValidityDays(distance))
+
+	return &models.EWayBillResponse{
+		EWayBillNo:   1e11 + c.next(),
+		EWayBillDate: now.Format(ewbDateTimeLayout),
+		ValidUpto:    validUpto.Format(ewbDateTimeLayout),
+	}, nil
+}
+
+// CancelEWayBill acknowledges the cancellation
+func (c *StubIRPClient) CancelEWayBill(ctx context.Context, ewbNo int64, reasonCode int, remarks string) (*models.EWayBillCancelResponse, error) {
+	return &models.EWayBillCancelResponse{EWayBillNo: ewbNo, CancelDate: c.now().Format(ewbDateTimeLayout)}, nil
+}
+
+// stubSign produces a JWS-shaped string with an unsigned placeholder signature
+func stubSign(claims interface{}) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode signed content: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(header) + "." + enc.EncodeToString(body) + ".STUB", nil
+}
+
+// ============================================================================
+// GSP CLIENT
+// ============================================================================
+
+// GSPIRPClient calls a GST Suvidha Provider's plain-JSON e-invoice / e-way bill API.
+// The GSP handles session keys and payload encryption towards the NIC portals.
+type GSPIRPClient struct {
+	settings *models.EInvoiceSettings
+	client   *http.Client
+}
+
+// NewGSPIRPClient creates a GSP-backed IRP client
+func NewGSPIRPClient(settings *models.EInvoiceSettings) *GSPIRPClient {
+	return &GSPIRPClient{
+		settings: settings,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// gspResponse is the GSP envelope: Status 1 carries Data, 0 carries ErrorDetails
+type gspResponse struct {
+	Status       int             `json:"Status"`
+	Data         json.RawMessage `json:"Data"`
+	ErrorDetails []struct {
+		ErrorCode    string `json:"ErrorCode"`
+		ErrorMessage string `json:"ErrorMessage"`
+	} `json:"ErrorDetails"`
+}
+
+func (c *GSPIRPClient) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.settings.APIBaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("client_id", c.settings.ClientID)
+	req.Header.Set("client_secret", c.settings.ClientSecret)
+	req.Header.Set("user_name", c.settings.Username)
+	req.Header.Set("password", c.settings.Password)
+	req.Header.Set("gstin", c.settings.SellerGSTIN)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GSP error: %d %s", resp.StatusCode, string(raw))
+	}
+
+	var envelope gspResponse
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if envelope.Status != 1 {
+		if len(envelope.ErrorDetails) > 0 {
+			return fmt.Errorf("IRP error %s: %s", envelope.ErrorDetails[0].ErrorCode, envelope.ErrorDetails[0].ErrorMessage)
+		}
+		return fmt.Errorf("IRP request was rejected")
+	}
+
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
+
+// GenerateIRN submits an INV-01 document for registration
+func (c *GSPIRPClient) GenerateIRN(ctx context.Context, payload *models.EInvoicePayload) (*models.IRNResponse, error) {
+	var out models.IRNResponse
+	if err := c.post(ctx, "/eicore/v1.03/Invoice", payload, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelIRN cancels a registered IRN
+func (c *GSPIRPClient) CancelIRN(ctx context.Context, irn, reasonCode, remarks string) (*models.IRNCancelResponse, error) {
+	var out models.IRNCancelResponse
+	body := map[string]string{"Irn": irn, "CnlRsn": reasonCode, "CnlRem": remarks}
+	if err := c.post(ctx, "/eicore/v1.03/Invoice/Cancel", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GenerateEWayBill submits an e-way bill
+func (c *GSPIRPClient) GenerateEWayBill(ctx context.Context, payload *models.EWayBillPayload) (*models.EWayBillResponse, error) {
+	var out models.EWayBillResponse
+	if err := c.post(ctx, "/ewaybillapi/v1.03/ewayapi/GENEWAYBILL", payload, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelEWayBill cancels an e-way bill
+func (c *GSPIRPClient) CancelEWayBill(ctx context.Context, ewbNo int64, reasonCode int, remarks string) (*models.EWayBillCancelResponse, error) {
+	var out models.EWayBillCancelResponse
+	body := map[string]interface{}{"ewbNo": ewbNo, "cancelRsnCode": reasonCode, "cancelRmrk": remarks}
+	if err := c.post(ctx, "/ewaybillapi/v1.03/ewayapi/CANEWB", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
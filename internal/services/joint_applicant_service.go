@@ -2,7 +2,12 @@ package services
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // JointApplicantService provides joint applicant management functionality
@@ -152,6 +157,146 @@ func (s *JointApplicantService) GetJointApplicantSummary(tenantID, bookingID int
 	}, nil
 }
 
+// ============================================================================
+// BOOKING APPLICANTS
+// ============================================================================
+
+// BookingApplicants returns the applicants on a booking, falling back to the
+// primary applicant in the booking's customer details when none are recorded
+func (s *JointApplicantService) BookingApplicants(tenantID, bookingID string) ([]models.TransferParty, error) {
+	rows, err := s.DB.Query(`SELECT applicant_name, COALESCE(email, ''), COALESCE(phone_number, ''),
+		COALESCE(id_type, ''), COALESCE(id_number, ''), COALESCE(address_line1, ''),
+		COALESCE(ownership_share_percentage, 0), COALESCE(is_primary_applicant, FALSE)
+		FROM joint_applicant WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+		ORDER BY is_primary_applicant DESC, created_at`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch joint applicants: %w", err)
+	}
+	defer rows.Close()
+
+	parties := []models.TransferParty{}
+	for rows.Next() {
+		p := models.TransferParty{Role: models.TransferPartyTransferor}
+		if err := rows.Scan(&p.Name, &p.Email, &p.Phone, &p.IDType, &p.IDNumber, &p.Address,
+			&p.SharePercent, &p.IsPrimary); err != nil {
+			return nil, fmt.Errorf("failed to scan joint applicant: %w", err)
+		}
+		if p.IDType == "pan" {
+			p.PAN = p.IDNumber
+		}
+		parties = append(parties, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(parties) > 0 {
+		return parties, nil
+	}
+
+	p := models.TransferParty{Role: models.TransferPartyTransferor, SharePercent: 100, IsPrimary: true}
+	err = s.DB.QueryRow(`SELECT COALESCE(primary_name, ''), COALESCE(primary_email, ''), COALESCE(primary_phone, ''),
+		COALESCE(primary_pan_no, '') FROM customer_details
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL LIMIT 1`,
+		tenantID, bookingID).Scan(&p.Name, &p.Email, &p.Phone, &p.PAN)
+	if err == sql.ErrNoRows {
+		return parties, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch customer details: %w", err)
+	}
+	return append(parties, p), nil
+}
+
+// ReplaceApplicants endorses a booking to new applicants within the caller's
+// transaction: the current applicants are removed and the new ones added with an
+// audit trail, and the co-ownership agreement is superseded by a new draft.
+// It returns the new agreement's reference number.
+func (s *JointApplicantService) ReplaceApplicants(tx *sql.Tx, tenantID, bookingID, reason string, parties []models.TransferParty, ownershipType string, agreementValue float64, userID string) (string, error) {
+	now := time.Now()
+
+	rows, err := tx.Query(`SELECT id, applicant_name FROM joint_applicant
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL`, tenantID, bookingID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch joint applicants: %w", err)
+	}
+	type applicant struct{ id, name string }
+	removed := []applicant{}
+	for rows.Next() {
+		var a applicant
+		if err := rows.Scan(&a.id, &a.name); err != nil {
+			rows.Close()
+			return "", fmt.Errorf("failed to scan joint applicant: %w", err)
+		}
+		removed = append(removed, a)
+	}
+	rows.Close()
+
+	for _, a := range removed {
+		if _, err := tx.Exec(`UPDATE joint_applicant SET deleted_at = ?, updated_by = ? WHERE id = ? AND tenant_id = ?`,
+			now, userID, a.id, tenantID); err != nil {
+			return "", fmt.Errorf("failed to remove joint applicant: %w", err)
+		}
+		if err := insertApplicantAudit(tx, tenantID, a.id, "transferred_out", reason, a.name, "", userID); err != nil {
+			return "", err
+		}
+	}
+
+	for _, p := range parties {
+		idType, idNumber := p.IDType, p.IDNumber
+		if idType == "" && p.PAN != "" {
+			idType, idNumber = "pan", p.PAN
+		}
+		id := newApplicantRecordID()
+		if _, err := tx.Exec(`INSERT INTO joint_applicant
+			(id, tenant_id, booking_id, is_primary_applicant, applicant_name, email, phone_number, address_line1,
+			 id_type, id_number, ownership_share_percentage, ownership_type, kyc_status, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'pending', ?, ?)`,
+			id, tenantID, bookingID, p.IsPrimary, p.Name, nullIfEmpty(p.Email), nullIfEmpty(p.Phone),
+			nullIfEmpty(p.Address), nullIfEmpty(idType), nullIfEmpty(idNumber), p.SharePercent, ownershipType,
+			userID, now); err != nil {
+			return "", fmt.Errorf("failed to add joint applicant: %w", err)
+		}
+		if err := insertApplicantAudit(tx, tenantID, id, "transferred_in", reason, "", p.Name, userID); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE co_ownership_agreement SET agreement_status = 'cancelled', updated_by = ?
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL AND agreement_status <> 'cancelled'`,
+		userID, tenantID, bookingID); err != nil {
+		return "", fmt.Errorf("failed to supersede co-ownership agreement: %w", err)
+	}
+
+	reference := interestNoteNumber("COA")
+	if _, err := tx.Exec(`INSERT INTO co_ownership_agreement
+		(id, tenant_id, booking_id, agreement_reference_no, agreement_date, agreement_type, property_description,
+		 agreed_purchase_price, total_share_percentage, agreement_status, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 100, 'draft', ?, ?)`,
+		newApplicantRecordID(), tenantID, bookingID, reference, now, ownershipType, reason,
+		agreementValue, userID, now); err != nil {
+		return "", fmt.Errorf("failed to create co-ownership agreement: %w", err)
+	}
+	return reference, nil
+}
+
+// insertApplicantAudit logs an applicant change. The log's user_id column is too
+// narrow for user IDs, so the acting user goes in the description.
+func insertApplicantAudit(tx *sql.Tx, tenantID, applicantID, action, description, oldValue, newValue, userID string) error {
+	if _, err := tx.Exec(`INSERT INTO joint_applicant_audit_log
+		(id, tenant_id, joint_applicant_id, action, action_description, field_changed, old_value, new_value, created_at)
+		VALUES (?, ?, ?, ?, ?, 'applicant_name', ?, ?, ?)`,
+		newApplicantRecordID(), tenantID, applicantID, action, fmt.Sprintf("%s (by %s)", description, userID),
+		nullIfEmpty(oldValue), nullIfEmpty(newValue), time.Now()); err != nil {
+		return fmt.Errorf("failed to write applicant audit log: %w", err)
+	}
+	return nil
+}
+
+// newApplicantRecordID returns a 26 character ID for the joint applicant tables
+func newApplicantRecordID() string {
+	return strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", ""))[:26]
+}
+
 // Helper function
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,1131 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// LAND BANK & JDA SERVICE
+// ============================================================================
+
+// LandBankService tracks land ahead of a project: parcels with their zoning and
+// FSI, acquisition costs and payments posted to the GL, and joint development
+// agreements. Under a JDA landowners are allotted project units, which are
+// blocked from sales inventory, and are paid their share of collections.
+type LandBankService struct {
+	DB           *sql.DB
+	GL           *GLService
+	Availability *UnitAvailabilityService
+}
+
+// NewLandBankService creates a new land bank service
+func NewLandBankService(db *sql.DB, gl *GLService, availability *UnitAvailabilityService) *LandBankService {
+	return &LandBankService{DB: db, GL: gl, Availability: availability}
+}
+
+var landParcelStatuses = map[string]bool{
+	models.LandParcelIdentified:       true,
+	models.LandParcelUnderNegotiation: true,
+	models.LandParcelAcquired:         true,
+	models.LandParcelUnderJDA:         true,
+	models.LandParcelDropped:          true,
+}
+
+var landCostTypes = map[string]bool{
+	models.LandCostLandPrice:    true,
+	models.LandCostStampDuty:    true,
+	models.LandCostRegistration: true,
+	models.LandCostBrokerage:    true,
+	models.LandCostConversion:   true,
+	models.LandCostLegal:        true,
+	models.LandCostJDADeposit:   true,
+	models.LandCostOther:        true,
+}
+
+// ============================================================================
+// PARCELS
+// ============================================================================
+
+// CreateParcel adds a parcel to the land bank
+func (s *LandBankService) CreateParcel(tenantID, userID string, req *models.CreateLandParcelRequest) (*models.LandParcel, error) {
+	if strings.TrimSpace(req.SurveyNumber) == "" || strings.TrimSpace(req.Village) == "" {
+		return nil, fmt.Errorf("survey_number and village are required")
+	}
+	if req.AreaAcres <= 0 {
+		return nil, fmt.Errorf("area_acres must be positive")
+	}
+	if req.PermissibleFSI < 0 {
+		return nil, fmt.Errorf("permissible_fsi cannot be negative")
+	}
+	mode := req.AcquisitionMode
+	if mode == "" {
+		mode = models.LandAcquisitionOutright
+	}
+	if mode != models.LandAcquisitionOutright && mode != models.LandAcquisitionJDA {
+		return nil, fmt.Errorf("acquisition_mode must be outright or jda")
+	}
+
+	now := time.Now()
+	p := &models.LandParcel{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		ParcelCode:      interestNoteNumber("LND"),
+		SurveyNumber:    strings.TrimSpace(req.SurveyNumber),
+		SubDivision:     req.SubDivision,
+		Village:         strings.TrimSpace(req.Village),
+		Taluk:           req.Taluk,
+		District:        req.District,
+		State:           req.State,
+		AreaAcres:       req.AreaAcres,
+		Zoning:          req.Zoning,
+		PermissibleFSI:  req.PermissibleFSI,
+		BuildableArea:   buildableArea(req.AreaAcres, req.PermissibleFSI),
+		AcquisitionMode: mode,
+		Status:          models.LandParcelIdentified,
+		TitleParcelID:   req.TitleParcelID,
+		Notes:           req.Notes,
+		CreatedBy:       optionalString(userID),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if _, err := s.DB.Exec(`INSERT INTO land_parcels
+		(id, tenant_id, parcel_code, survey_number, sub_division, village, taluk, district, state, area_acres,
+		 zoning, permissible_fsi, acquisition_mode, status, title_parcel_id, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, tenantID, p.ParcelCode, p.SurveyNumber, nullIfEmpty(p.SubDivision), p.Village, nullIfEmpty(p.Taluk),
+		nullIfEmpty(p.District), nullIfEmpty(p.State), p.AreaAcres, nullIfEmpty(p.Zoning), p.PermissibleFSI, mode,
+		p.Status, p.TitleParcelID, nullIfEmpty(p.Notes), p.CreatedBy, now, now); err != nil {
+		return nil, fmt.Errorf("failed to create land parcel: %w", err)
+	}
+	return p, nil
+}
+
+// UpdateParcel updates a parcel's zoning, FSI, status or project
+func (s *LandBankService) UpdateParcel(tenantID, parcelID string, req *models.UpdateLandParcelRequest) (*models.LandParcel, error) {
+	p, err := s.GetParcel(tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Zoning != nil {
+		p.Zoning = *req.Zoning
+	}
+	if req.PermissibleFSI != nil {
+		if *req.PermissibleFSI < 0 {
+			return nil, fmt.Errorf("permissible_fsi cannot be negative")
+		}
+		p.PermissibleFSI = *req.PermissibleFSI
+	}
+	if req.Status != nil {
+		if !landParcelStatuses[*req.Status] {
+			return nil, fmt.Errorf("invalid parcel status %q", *req.Status)
+		}
+		if p.Status == models.LandParcelUnderJDA && *req.Status != models.LandParcelUnderJDA {
+			return nil, fmt.Errorf("parcel is under an active JDA")
+		}
+		p.Status = *req.Status
+	}
+	if req.ProjectID != nil {
+		p.ProjectID = optionalString(*req.ProjectID)
+	}
+	if req.Notes != nil {
+		p.Notes = *req.Notes
+	}
+	p.UpdatedAt = time.Now()
+
+	if _, err := s.DB.Exec(`UPDATE land_parcels SET zoning = ?, permissible_fsi = ?, status = ?, project_id = ?,
+		notes = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		nullIfEmpty(p.Zoning), p.PermissibleFSI, p.Status, p.ProjectID, nullIfEmpty(p.Notes), p.UpdatedAt,
+		parcelID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update land parcel: %w", err)
+	}
+	p.BuildableArea = buildableArea(p.AreaAcres, p.PermissibleFSI)
+	return p, nil
+}
+
+// GetParcel returns a parcel with its acquisition costs and payments
+func (s *LandBankService) GetParcel(tenantID, parcelID string) (*models.LandParcel, error) {
+	parcels, err := s.getParcels(tenantID, "AND lp.id = ?", parcelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(parcels) == 0 {
+		return nil, fmt.Errorf("land parcel not found")
+	}
+	p := &parcels[0]
+	if p.Costs, err = s.getCosts(tenantID, parcelID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ListParcels lists the land bank, optionally by status or village
+func (s *LandBankService) ListParcels(tenantID, status, village string) ([]models.LandParcel, error) {
+	where := ""
+	args := []interface{}{}
+	if status != "" {
+		where += " AND lp.status = ?"
+		args = append(args, status)
+	}
+	if village != "" {
+		where += " AND lp.village = ?"
+		args = append(args, village)
+	}
+	return s.getParcels(tenantID, where, args...)
+}
+
+func (s *LandBankService) getParcels(tenantID, where string, args ...interface{}) ([]models.LandParcel, error) {
+	rows, err := s.DB.Query(`SELECT lp.id, lp.tenant_id, lp.parcel_code, lp.survey_number, COALESCE(lp.sub_division, ''),
+		lp.village, COALESCE(lp.taluk, ''), COALESCE(lp.district, ''), COALESCE(lp.state, ''), lp.area_acres,
+		COALESCE(lp.zoning, ''), lp.permissible_fsi, lp.acquisition_mode, lp.status, lp.project_id, lp.title_parcel_id,
+		COALESCE((SELECT SUM(c.amount) FROM land_acquisition_costs c WHERE c.parcel_id = lp.id AND c.tenant_id = lp.tenant_id), 0),
+		COALESCE((SELECT SUM(c.amount_paid) FROM land_acquisition_costs c WHERE c.parcel_id = lp.id AND c.tenant_id = lp.tenant_id), 0),
+		COALESCE(lp.notes, ''), lp.created_by, lp.created_at, lp.updated_at
+		FROM land_parcels lp WHERE lp.tenant_id = ? AND lp.deleted_at IS NULL`+where+`
+		ORDER BY lp.village, lp.survey_number`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch land parcels: %w", err)
+	}
+	defer rows.Close()
+
+	parcels := []models.LandParcel{}
+	for rows.Next() {
+		var p models.LandParcel
+		var projectID, createdBy sql.NullString
+		var titleParcelID sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.ParcelCode, &p.SurveyNumber, &p.SubDivision, &p.Village, &p.Taluk,
+			&p.District, &p.State, &p.AreaAcres, &p.Zoning, &p.PermissibleFSI, &p.AcquisitionMode, &p.Status,
+			&projectID, &titleParcelID, &p.AcquisitionCost, &p.AmountPaid, &p.Notes, &createdBy, &p.CreatedAt,
+			&p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan land parcel: %w", err)
+		}
+		p.ProjectID = nullStringPtr(projectID)
+		p.CreatedBy = nullStringPtr(createdBy)
+		if titleParcelID.Valid {
+			p.TitleParcelID = &titleParcelID.Int64
+		}
+		p.BuildableArea = buildableArea(p.AreaAcres, p.PermissibleFSI)
+		p.Outstanding = roundTo2(p.AcquisitionCost - p.AmountPaid)
+		parcels = append(parcels, p)
+	}
+	return parcels, rows.Err()
+}
+
+// ============================================================================
+// ACQUISITION COSTS & PAYMENTS
+// ============================================================================
+
+// RecordCost accrues an acquisition cost on a parcel. The cost is capitalised to
+// the land bank against the payee: Dr Land Bank, Cr Land Acquisition Payable.
+func (s *LandBankService) RecordCost(tenantID, userID, parcelID string, req *models.RecordLandCostRequest) (*models.LandCost, error) {
+	if !landCostTypes[req.CostType] {
+		return nil, fmt.Errorf("invalid cost_type %q", req.CostType)
+	}
+	amount := roundTo2(req.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if strings.TrimSpace(req.Payee) == "" {
+		return nil, fmt.Errorf("payee is required")
+	}
+	parcel, err := s.GetParcel(tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	if parcel.Status == models.LandParcelDropped {
+		return nil, fmt.Errorf("parcel has been dropped from the land bank")
+	}
+
+	c := &models.LandCost{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		ParcelID:    parcelID,
+		CostType:    req.CostType,
+		Payee:       strings.TrimSpace(req.Payee),
+		Description: req.Description,
+		CostDate:    time.Now(),
+		Amount:      amount,
+		Outstanding: amount,
+		CreatedBy:   optionalString(userID),
+		CreatedAt:   time.Now(),
+	}
+	if req.CostDate != nil {
+		c.CostDate = *req.CostDate
+	}
+	entryID := fmt.Sprintf("JE-LAND-COST-%s", c.ID)
+	c.JournalEntryID = &entryID
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO land_acquisition_costs
+		(id, tenant_id, parcel_id, cost_type, payee, description, cost_date, amount, amount_paid, journal_entry_id,
+		 created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?)`,
+		c.ID, tenantID, parcelID, c.CostType, c.Payee, nullIfEmpty(c.Description), c.CostDate, amount, entryID,
+		c.CreatedBy, c.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record land acquisition cost: %w", err)
+	}
+
+	reference := parcel.ParcelCode
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       c.CostDate,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Land_Acquisition_Cost",
+		ReferenceID:     &c.ID,
+		Description:     fmt.Sprintf("Land %s cost - Sy. No. %s, %s", c.CostType, parcel.SurveyNumber, parcel.Village),
+		Amount:          amount,
+		Narration:       fmt.Sprintf("Payable to %s", c.Payee),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-LAND-BANK", DebitAmount: amount, Description: fmt.Sprintf("%s %s", parcel.ParcelCode, c.CostType)},
+		{AccountID: "ACC-LAND-PAYABLE", CreditAmount: amount, Description: fmt.Sprintf("Payable to %s", c.Payee)},
+	}
+	if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit land acquisition cost: %w", err)
+	}
+	return c, nil
+}
+
+// RecordPayment pays an acquisition cost, up to what is outstanding on it:
+// Dr Land Acquisition Payable, Cr Bank.
+func (s *LandBankService) RecordPayment(tenantID, userID, costID string, req *models.RecordLandPaymentRequest) (*models.LandPayment, error) {
+	amount := roundTo2(req.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if req.PaymentMode == "" {
+		return nil, fmt.Errorf("payment_mode is required")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var parcelID, payee, costType string
+	var costAmount, paid float64
+	err = tx.QueryRow(`SELECT parcel_id, payee, cost_type, amount, amount_paid FROM land_acquisition_costs
+		WHERE id = ? AND tenant_id = ? FOR UPDATE`, costID, tenantID).Scan(&parcelID, &payee, &costType, &costAmount, &paid)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("land acquisition cost not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get land acquisition cost: %w", err)
+	}
+	if outstanding := roundTo2(costAmount - paid); amount > outstanding+0.005 {
+		return nil, fmt.Errorf("amount %.2f exceeds the outstanding %.2f on this cost", amount, outstanding)
+	}
+
+	p := &models.LandPayment{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		ParcelID:    parcelID,
+		CostID:      costID,
+		PaymentDate: time.Now(),
+		Amount:      amount,
+		PaymentMode: req.PaymentMode,
+		Reference:   req.Reference,
+		CreatedBy:   optionalString(userID),
+		CreatedAt:   time.Now(),
+	}
+	if req.PaymentDate != nil {
+		p.PaymentDate = *req.PaymentDate
+	}
+	entryID := fmt.Sprintf("JE-LAND-PAY-%s", p.ID)
+	p.JournalEntryID = &entryID
+
+	if _, err := tx.Exec(`INSERT INTO land_payments
+		(id, tenant_id, parcel_id, cost_id, payment_date, amount, payment_mode, reference, journal_entry_id,
+		 created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, tenantID, parcelID, costID, p.PaymentDate, amount, p.PaymentMode, nullIfEmpty(p.Reference), entryID,
+		p.CreatedBy, p.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record land payment: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE land_acquisition_costs SET amount_paid = amount_paid + ? WHERE id = ? AND tenant_id = ?`,
+		amount, costID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update land acquisition cost: %w", err)
+	}
+
+	reference := req.Reference
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       p.PaymentDate,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Land_Payment",
+		ReferenceID:     &p.ID,
+		Description:     fmt.Sprintf("Land %s paid to %s", costType, payee),
+		Amount:          amount,
+		Narration:       fmt.Sprintf("%s %s", req.PaymentMode, req.Reference),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-LAND-PAYABLE", DebitAmount: amount, Description: fmt.Sprintf("Paid to %s", payee)},
+		{AccountID: "ACC-BANK-CASH", CreditAmount: amount, Description: fmt.Sprintf("Land payment %s", req.Reference)},
+	}
+	if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit land payment: %w", err)
+	}
+	return p, nil
+}
+
+func (s *LandBankService) getCosts(tenantID, parcelID string) ([]models.LandCost, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, parcel_id, cost_type, payee, COALESCE(description, ''), cost_date,
+		amount, amount_paid, journal_entry_id, created_by, created_at
+		FROM land_acquisition_costs WHERE tenant_id = ? AND parcel_id = ? ORDER BY cost_date, created_at`,
+		tenantID, parcelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch land acquisition costs: %w", err)
+	}
+	costs := []models.LandCost{}
+	index := map[string]int{}
+	for rows.Next() {
+		var c models.LandCost
+		var entryID, createdBy sql.NullString
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.ParcelID, &c.CostType, &c.Payee, &c.Description, &c.CostDate,
+			&c.Amount, &c.AmountPaid, &entryID, &createdBy, &c.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan land acquisition cost: %w", err)
+		}
+		c.JournalEntryID = nullStringPtr(entryID)
+		c.CreatedBy = nullStringPtr(createdBy)
+		c.Outstanding = roundTo2(c.Amount - c.AmountPaid)
+		c.Payments = []models.LandPayment{}
+		index[c.ID] = len(costs)
+		costs = append(costs, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.DB.Query(`SELECT id, tenant_id, parcel_id, cost_id, payment_date, amount, payment_mode,
+		COALESCE(reference, ''), journal_entry_id, created_by, created_at
+		FROM land_payments WHERE tenant_id = ? AND parcel_id = ? ORDER BY payment_date, created_at`, tenantID, parcelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch land payments: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.LandPayment
+		var entryID, createdBy sql.NullString
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.ParcelID, &p.CostID, &p.PaymentDate, &p.Amount, &p.PaymentMode,
+			&p.Reference, &entryID, &createdBy, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan land payment: %w", err)
+		}
+		p.JournalEntryID = nullStringPtr(entryID)
+		p.CreatedBy = nullStringPtr(createdBy)
+		if i, ok := index[p.CostID]; ok {
+			costs[i].Payments = append(costs[i].Payments, p)
+		}
+	}
+	return costs, rows.Err()
+}
+
+// ============================================================================
+// JOINT DEVELOPMENT AGREEMENTS
+// ============================================================================
+
+// CreateJDA records a joint development agreement on a parcel and ties the
+// parcel to the project being developed on it
+func (s *LandBankService) CreateJDA(tenantID, userID string, req *models.CreateJDARequest) (*models.JointDevelopmentAgreement, error) {
+	if req.ParcelID == "" || req.ProjectID == "" {
+		return nil, fmt.Errorf("parcel_id and project_id are required")
+	}
+	if req.AgreementDate.IsZero() {
+		return nil, fmt.Errorf("agreement_date is required")
+	}
+	if err := validateJDAShares(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	jda := &models.JointDevelopmentAgreement{
+		ID:                 uuid.New().String(),
+		TenantID:           tenantID,
+		AgreementNumber:    interestNoteNumber("JDA"),
+		ParcelID:           req.ParcelID,
+		ProjectID:          req.ProjectID,
+		AgreementDate:      req.AgreementDate,
+		RegistrationNumber: req.RegistrationNumber,
+		ShareType:          req.ShareType,
+		AreaSharePercent:   req.AreaSharePercent,
+		RevenueSharePct:    req.RevenueSharePct,
+		Status:             models.JDAStatusActive,
+		CreatedBy:          optionalString(userID),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM land_parcels WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL FOR UPDATE`,
+		req.ParcelID, tenantID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("land parcel not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get land parcel: %w", err)
+	}
+	if status == models.LandParcelUnderJDA || status == models.LandParcelDropped {
+		return nil, fmt.Errorf("a JDA cannot be recorded on a parcel that is %s", status)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO joint_development_agreements
+		(id, tenant_id, agreement_number, parcel_id, project_id, agreement_date, registration_number, share_type,
+		 area_share_percent, revenue_share_percent, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		jda.ID, tenantID, jda.AgreementNumber, jda.ParcelID, jda.ProjectID, jda.AgreementDate,
+		nullIfEmpty(jda.RegistrationNumber), jda.ShareType, jda.AreaSharePercent, jda.RevenueSharePct, jda.Status,
+		jda.CreatedBy, now, now); err != nil {
+		return nil, fmt.Errorf("failed to create JDA: %w", err)
+	}
+	for _, in := range req.Landowners {
+		lo := models.JDALandowner{
+			ID:           uuid.New().String(),
+			TenantID:     tenantID,
+			JDAID:        jda.ID,
+			Name:         strings.TrimSpace(in.Name),
+			PAN:          strings.ToUpper(strings.TrimSpace(in.PAN)),
+			SharePercent: in.SharePercent,
+			BankAccount:  in.BankAccount,
+		}
+		if _, err := tx.Exec(`INSERT INTO jda_landowners (id, tenant_id, jda_id, name, pan, share_percent, bank_account)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, lo.ID, tenantID, jda.ID, lo.Name, nullIfEmpty(lo.PAN), lo.SharePercent,
+			nullIfEmpty(lo.BankAccount)); err != nil {
+			return nil, fmt.Errorf("failed to add JDA landowner: %w", err)
+		}
+		jda.Landowners = append(jda.Landowners, lo)
+	}
+	if _, err := tx.Exec(`UPDATE land_parcels SET status = ?, acquisition_mode = ?, project_id = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, models.LandParcelUnderJDA, models.LandAcquisitionJDA, req.ProjectID, now,
+		req.ParcelID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update land parcel: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit JDA: %w", err)
+	}
+	return jda, nil
+}
+
+// GetJDA returns a JDA with its landowners and unit allocations
+func (s *LandBankService) GetJDA(tenantID, jdaID string) (*models.JointDevelopmentAgreement, error) {
+	jdas, err := s.getJDAs(tenantID, "AND id = ?", jdaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jdas) == 0 {
+		return nil, fmt.Errorf("JDA not found")
+	}
+	jda := &jdas[0]
+	if jda.Landowners, err = s.getLandowners(tenantID, jdaID); err != nil {
+		return nil, err
+	}
+	if jda.Allocations, err = s.ListAllocations(tenantID, jdaID, ""); err != nil {
+		return nil, err
+	}
+	return jda, nil
+}
+
+// ListJDAs lists JDAs, optionally for a project
+func (s *LandBankService) ListJDAs(tenantID, projectID string) ([]models.JointDevelopmentAgreement, error) {
+	if projectID != "" {
+		return s.getJDAs(tenantID, "AND project_id = ?", projectID)
+	}
+	return s.getJDAs(tenantID, "")
+}
+
+func (s *LandBankService) getJDAs(tenantID, where string, args ...interface{}) ([]models.JointDevelopmentAgreement, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, agreement_number, parcel_id, project_id, agreement_date,
+		COALESCE(registration_number, ''), share_type, area_share_percent, revenue_share_percent, status, created_by,
+		created_at, updated_at
+		FROM joint_development_agreements WHERE tenant_id = ? `+where+` ORDER BY agreement_date DESC`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JDAs: %w", err)
+	}
+	defer rows.Close()
+
+	jdas := []models.JointDevelopmentAgreement{}
+	for rows.Next() {
+		var j models.JointDevelopmentAgreement
+		var createdBy sql.NullString
+		if err := rows.Scan(&j.ID, &j.TenantID, &j.AgreementNumber, &j.ParcelID, &j.ProjectID, &j.AgreementDate,
+			&j.RegistrationNumber, &j.ShareType, &j.AreaSharePercent, &j.RevenueSharePct, &j.Status, &createdBy,
+			&j.CreatedAt, &j.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan JDA: %w", err)
+		}
+		j.CreatedBy = nullStringPtr(createdBy)
+		jdas = append(jdas, j)
+	}
+	return jdas, rows.Err()
+}
+
+func (s *LandBankService) getLandowners(tenantID, jdaID string) ([]models.JDALandowner, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, jda_id, name, COALESCE(pan, ''), share_percent, COALESCE(bank_account, '')
+		FROM jda_landowners WHERE tenant_id = ? AND jda_id = ? ORDER BY name`, tenantID, jdaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JDA landowners: %w", err)
+	}
+	defer rows.Close()
+
+	landowners := []models.JDALandowner{}
+	for rows.Next() {
+		var lo models.JDALandowner
+		if err := rows.Scan(&lo.ID, &lo.TenantID, &lo.JDAID, &lo.Name, &lo.PAN, &lo.SharePercent,
+			&lo.BankAccount); err != nil {
+			return nil, fmt.Errorf("failed to scan JDA landowner: %w", err)
+		}
+		landowners = append(landowners, lo)
+	}
+	return landowners, rows.Err()
+}
+
+// ============================================================================
+// LANDOWNER UNIT ALLOCATION
+// ============================================================================
+
+// AllotUnit allots a project unit to a landowner against the JDA's area share.
+// The unit is blocked so it drops out of sales inventory, and the landowner
+// cannot be allotted more saleable area than their entitlement.
+func (s *LandBankService) AllotUnit(tenantID, userID, jdaID string, req *models.AllotLandownerUnitRequest) (*models.JDAUnitAllocation, error) {
+	jda, err := s.GetJDA(tenantID, jdaID)
+	if err != nil {
+		return nil, err
+	}
+	if jda.Status != models.JDAStatusActive {
+		return nil, fmt.Errorf("JDA is %s", jda.Status)
+	}
+	if jda.AreaSharePercent <= 0 {
+		return nil, fmt.Errorf("this JDA has no area share for landowners")
+	}
+	var landowner *models.JDALandowner
+	for i := range jda.Landowners {
+		if jda.Landowners[i].ID == req.LandownerID {
+			landowner = &jda.Landowners[i]
+		}
+	}
+	if landowner == nil {
+		return nil, fmt.Errorf("landowner is not a party to this JDA")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var projectID, unitNumber, rawStatus string
+	var sbua float64
+	var booked bool
+	err = tx.QueryRow(`SELECT COALESCE(u.project_id, ''), u.unit_number, COALESCE(u.sbua, 0), COALESCE(u.status, ''),
+		EXISTS (SELECT 1 FROM customer_bookings b WHERE b.unit_id = u.id AND b.tenant_id = u.tenant_id
+			AND b.booking_status = 'active' AND b.deleted_at IS NULL)
+		FROM property_units u WHERE u.id = ? AND u.tenant_id = ? AND u.deleted_at IS NULL FOR UPDATE`,
+		req.UnitID, tenantID).Scan(&projectID, &unitNumber, &sbua, &rawStatus, &booked)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unit not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit: %w", err)
+	}
+	if projectID != jda.ProjectID {
+		return nil, fmt.Errorf("unit is not in the JDA's project")
+	}
+	from := matrixUnitStatus(rawStatus, booked)
+	if from == models.UnitStatusBooked || from == models.UnitStatusRegistered {
+		return nil, fmt.Errorf("unit %s is already %s", unitNumber, from)
+	}
+	if allotted, err := isLandownerUnit(tx, tenantID, req.UnitID); err != nil {
+		return nil, err
+	} else if allotted {
+		return nil, fmt.Errorf("unit %s is already allotted to a landowner", unitNumber)
+	}
+
+	var projectSBUA, allottedSBUA float64
+	if err := tx.QueryRow(`SELECT COALESCE(SUM(sbua), 0) FROM property_units
+		WHERE tenant_id = ? AND project_id = ? AND deleted_at IS NULL`, tenantID, jda.ProjectID).Scan(&projectSBUA); err != nil {
+		return nil, fmt.Errorf("failed to fetch project area: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT COALESCE(SUM(sbua), 0) FROM jda_unit_allocations
+		WHERE tenant_id = ? AND jda_id = ? AND landowner_id = ? AND status = ?`,
+		tenantID, jdaID, landowner.ID, models.JDAAllocationAllotted).Scan(&allottedSBUA); err != nil {
+		return nil, fmt.Errorf("failed to fetch allotted area: %w", err)
+	}
+	entitled := landownerEntitlement(projectSBUA, jda.AreaSharePercent, landowner.SharePercent)
+	if allottedSBUA+sbua > entitled+0.005 {
+		return nil, fmt.Errorf("unit %s (%.2f sqft) exceeds %s's balance entitlement of %.2f sqft",
+			unitNumber, sbua, landowner.Name, math.Max(0, roundTo2(entitled-allottedSBUA)))
+	}
+
+	now := time.Now()
+	a := &models.JDAUnitAllocation{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		JDAID:         jdaID,
+		LandownerID:   landowner.ID,
+		LandownerName: landowner.Name,
+		UnitID:        req.UnitID,
+		UnitNumber:    unitNumber,
+		SBUA:          sbua,
+		Status:        models.JDAAllocationAllotted,
+		AllottedAt:    now,
+		CreatedBy:     optionalString(userID),
+	}
+	if _, err := tx.Exec(`INSERT INTO jda_unit_allocations
+		(id, tenant_id, jda_id, landowner_id, unit_id, sbua, status, allotted_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, tenantID, jdaID, landowner.ID, req.UnitID, sbua, a.Status, now, a.CreatedBy); err != nil {
+		return nil, fmt.Errorf("failed to allot unit: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE property_units SET status = ?, alloted_to = ?, allotment_date = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, models.UnitStatusBlocked, landowner.Name, now, now, req.UnitID,
+		tenantID); err != nil {
+		return nil, fmt.Errorf("failed to block unit: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit unit allotment: %w", err)
+	}
+
+	if s.Availability != nil && from != models.UnitStatusBlocked {
+		if _, err := s.Availability.UnitStatusChanged(tenantID, userID, req.UnitID, from, models.UnitStatusBlocked,
+			fmt.Sprintf("Allotted to landowner %s under JDA %s", landowner.Name, jda.AgreementNumber)); err != nil {
+			return nil, fmt.Errorf("unit allotted but %w", err)
+		}
+	}
+	return a, nil
+}
+
+// ReleaseUnit cancels a landowner allotment and returns the unit to sales inventory
+func (s *LandBankService) ReleaseUnit(tenantID, userID, allocationID string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var unitID string
+	err = tx.QueryRow(`SELECT unit_id FROM jda_unit_allocations WHERE id = ? AND tenant_id = ? AND status = ? FOR UPDATE`,
+		allocationID, tenantID, models.JDAAllocationAllotted).Scan(&unitID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("allotted unit allocation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get unit allocation: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE jda_unit_allocations SET status = ?, released_at = ? WHERE id = ? AND tenant_id = ?`,
+		models.JDAAllocationReleased, now, allocationID, tenantID); err != nil {
+		return fmt.Errorf("failed to release unit allocation: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE property_units SET status = ?, alloted_to = NULL, allotment_date = NULL, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, models.UnitStatusAvailable, now, unitID, tenantID); err != nil {
+		return fmt.Errorf("failed to release unit: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit unit release: %w", err)
+	}
+
+	if s.Availability != nil {
+		if _, err := s.Availability.UnitStatusChanged(tenantID, userID, unitID, models.UnitStatusBlocked,
+			models.UnitStatusAvailable, "Released from landowner allotment"); err != nil {
+			return fmt.Errorf("unit released but %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAllocations lists a JDA's unit allocations, optionally by status
+func (s *LandBankService) ListAllocations(tenantID, jdaID, status string) ([]models.JDAUnitAllocation, error) {
+	query := `SELECT a.id, a.tenant_id, a.jda_id, a.landowner_id, lo.name, a.unit_id, COALESCE(u.unit_number, ''),
+		a.sbua, a.status, a.allotted_at, a.released_at, a.created_by
+		FROM jda_unit_allocations a
+		JOIN jda_landowners lo ON lo.id = a.landowner_id AND lo.tenant_id = a.tenant_id
+		LEFT JOIN property_units u ON u.id = a.unit_id AND u.tenant_id = a.tenant_id
+		WHERE a.tenant_id = ? AND a.jda_id = ?`
+	args := []interface{}{tenantID, jdaID}
+	if status != "" {
+		query += " AND a.status = ?"
+		args = append(args, status)
+	}
+	rows, err := s.DB.Query(query+" ORDER BY lo.name, u.unit_number", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unit allocations: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := []models.JDAUnitAllocation{}
+	for rows.Next() {
+		var a models.JDAUnitAllocation
+		var releasedAt sql.NullTime
+		var createdBy sql.NullString
+		if err := rows.Scan(&a.ID, &a.TenantID, &a.JDAID, &a.LandownerID, &a.LandownerName, &a.UnitID, &a.UnitNumber,
+			&a.SBUA, &a.Status, &a.AllottedAt, &releasedAt, &createdBy); err != nil {
+			return nil, fmt.Errorf("failed to scan unit allocation: %w", err)
+		}
+		if releasedAt.Valid {
+			a.ReleasedAt = &releasedAt.Time
+		}
+		a.CreatedBy = nullStringPtr(createdBy)
+		allocations = append(allocations, a)
+	}
+	return allocations, rows.Err()
+}
+
+// GetAreaPosition returns each landowner's area entitlement against the units allotted
+func (s *LandBankService) GetAreaPosition(tenantID, jdaID string) (*models.JDAAreaPosition, error) {
+	jda, err := s.GetJDA(tenantID, jdaID)
+	if err != nil {
+		return nil, err
+	}
+	var projectSBUA float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(sbua), 0) FROM property_units
+		WHERE tenant_id = ? AND project_id = ? AND deleted_at IS NULL`, tenantID, jda.ProjectID).Scan(&projectSBUA); err != nil {
+		return nil, fmt.Errorf("failed to fetch project area: %w", err)
+	}
+	return buildAreaPosition(jda, projectSBUA), nil
+}
+
+// IsLandownerUnit reports whether a unit is allotted to a landowner under a JDA.
+// Such units are not sales inventory and cannot be booked.
+func (s *LandBankService) IsLandownerUnit(tenantID, unitID string) (bool, error) {
+	return isLandownerUnit(s.DB, tenantID, unitID)
+}
+
+func isLandownerUnit(db sqlRowQuerier, tenantID, unitID string) (bool, error) {
+	var allotted bool
+	if err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM jda_unit_allocations
+		WHERE tenant_id = ? AND unit_id = ? AND status = ?)`,
+		tenantID, unitID, models.JDAAllocationAllotted).Scan(&allotted); err != nil {
+		return false, fmt.Errorf("failed to check landowner allotment: %w", err)
+	}
+	return allotted, nil
+}
+
+// ============================================================================
+// REVENUE SHARE PAYOUTS
+// ============================================================================
+
+// PreviewPayouts computes each landowner's revenue share of the project's
+// collections for a period. Collections are cleared customer payments, with TDS
+// deducted by buyers, on active bookings of units not allotted to landowners.
+func (s *LandBankService) PreviewPayouts(tenantID, jdaID string, req *models.JDAPayoutRequest) ([]models.JDARevenuePayout, error) {
+	jda, err := s.GetJDA(tenantID, jdaID)
+	if err != nil {
+		return nil, err
+	}
+	if jda.RevenueSharePct <= 0 {
+		return nil, fmt.Errorf("this JDA has no revenue share for landowners")
+	}
+	if !req.PeriodTo.After(req.PeriodFrom) {
+		return nil, fmt.Errorf("period_to must be after period_from")
+	}
+
+	var collections float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		JOIN customer_bookings b ON b.id = p.booking_id AND b.tenant_id = p.tenant_id
+		JOIN property_units u ON u.id = b.unit_id AND u.tenant_id = b.tenant_id
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND u.project_id = ? AND p.status = 'cleared' AND p.deleted_at IS NULL
+		AND b.booking_status <> 'cancelled' AND b.deleted_at IS NULL
+		AND p.payment_date >= ? AND p.payment_date < ?
+		AND NOT EXISTS (SELECT 1 FROM jda_unit_allocations a WHERE a.tenant_id = u.tenant_id AND a.unit_id = u.id
+			AND a.status = ?)`,
+		tenantID, jda.ProjectID, req.PeriodFrom, req.PeriodTo, models.JDAAllocationAllotted).Scan(&collections); err != nil {
+		return nil, fmt.Errorf("failed to fetch project collections: %w", err)
+	}
+	return splitRevenueShare(jda, roundTo2(collections), req.PeriodFrom, req.PeriodTo), nil
+}
+
+// RecordPayouts pays the landowners their revenue share for a period:
+// Dr JDA Revenue Share, Cr Bank. A period cannot overlap one already paid.
+func (s *LandBankService) RecordPayouts(tenantID, userID, jdaID string, req *models.JDAPayoutRequest) ([]models.JDARevenuePayout, error) {
+	if req.PaymentMode == "" {
+		return nil, fmt.Errorf("payment_mode is required")
+	}
+	payouts, err := s.PreviewPayouts(tenantID, jdaID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the JDA so two payouts for the same period cannot both pass the overlap check
+	var locked string
+	if err := tx.QueryRow(`SELECT id FROM joint_development_agreements WHERE id = ? AND tenant_id = ? FOR UPDATE`,
+		jdaID, tenantID).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("failed to lock JDA: %w", err)
+	}
+	rows, err := tx.Query(`SELECT DISTINCT period_from, period_to FROM jda_revenue_payouts WHERE tenant_id = ? AND jda_id = ?`,
+		tenantID, jdaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch paid periods: %w", err)
+	}
+	for rows.Next() {
+		var from, to time.Time
+		if err := rows.Scan(&from, &to); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan paid period: %w", err)
+		}
+		if periodsOverlap(req.PeriodFrom, req.PeriodTo, from, to) {
+			rows.Close()
+			return nil, fmt.Errorf("revenue share for %s to %s has already been paid",
+				from.Format("2006-01-02"), to.Format("2006-01-02"))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range payouts {
+		p := &payouts[i]
+		p.ID = uuid.New().String()
+		p.PayoutNumber = interestNoteNumber("JDP")
+		p.PaymentMode = req.PaymentMode
+		p.Reference = req.Reference
+		p.PaidAt = &now
+		p.CreatedBy = optionalString(userID)
+		p.CreatedAt = now
+		if p.Amount > 0 {
+			entryID := fmt.Sprintf("JE-JDA-PAYOUT-%s", p.ID)
+			p.JournalEntryID = &entryID
+		}
+		if _, err := tx.Exec(`INSERT INTO jda_revenue_payouts
+			(id, tenant_id, payout_number, jda_id, landowner_id, period_from, period_to, collections,
+			 revenue_share_percent, landowner_share_percent, amount, payment_mode, reference, paid_at,
+			 journal_entry_id, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			p.ID, tenantID, p.PayoutNumber, jdaID, p.LandownerID, p.PeriodFrom, p.PeriodTo, p.Collections,
+			p.RevenueShare, p.LandownerShare, p.Amount, p.PaymentMode, nullIfEmpty(p.Reference), now,
+			p.JournalEntryID, p.CreatedBy, now); err != nil {
+			return nil, fmt.Errorf("failed to record revenue share payout: %w", err)
+		}
+	}
+
+	for _, p := range payouts {
+		if p.JournalEntryID == nil {
+			continue
+		}
+		reference := p.PayoutNumber
+		entry := &models.JournalEntry{
+			ID:              *p.JournalEntryID,
+			TenantID:        tenantID,
+			EntryDate:       now,
+			ReferenceNumber: &reference,
+			ReferenceType:   "JDA_Revenue_Share",
+			ReferenceID:     &p.ID,
+			Description: fmt.Sprintf("Revenue share to %s for %s to %s", p.LandownerName,
+				p.PeriodFrom.Format("2006-01-02"), p.PeriodTo.Format("2006-01-02")),
+			Amount:      p.Amount,
+			Narration:   fmt.Sprintf("%s %s", req.PaymentMode, req.Reference),
+			EntryStatus: "Draft",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		lines := []models.JournalEntryDetail{
+			{AccountID: "ACC-JDA-REVENUE-SHARE", DebitAmount: p.Amount, Description: fmt.Sprintf("Landowner share %s", p.LandownerName)},
+			{AccountID: "ACC-BANK-CASH", CreditAmount: p.Amount, Description: fmt.Sprintf("Paid to %s", p.LandownerName)},
+		}
+		if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit revenue share payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+// ListPayouts lists a JDA's revenue share payouts, latest period first
+func (s *LandBankService) ListPayouts(tenantID, jdaID string) ([]models.JDARevenuePayout, error) {
+	rows, err := s.DB.Query(`SELECT p.id, p.tenant_id, p.payout_number, p.jda_id, p.landowner_id, lo.name,
+		p.period_from, p.period_to, p.collections, p.revenue_share_percent, p.landowner_share_percent, p.amount,
+		p.payment_mode, COALESCE(p.reference, ''), p.paid_at, p.journal_entry_id, p.created_by, p.created_at
+		FROM jda_revenue_payouts p
+		JOIN jda_landowners lo ON lo.id = p.landowner_id AND lo.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.jda_id = ? ORDER BY p.period_from DESC, lo.name`, tenantID, jdaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revenue share payouts: %w", err)
+	}
+	defer rows.Close()
+
+	payouts := []models.JDARevenuePayout{}
+	for rows.Next() {
+		var p models.JDARevenuePayout
+		var paidAt sql.NullTime
+		var entryID, createdBy sql.NullString
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.PayoutNumber, &p.JDAID, &p.LandownerID, &p.LandownerName,
+			&p.PeriodFrom, &p.PeriodTo, &p.Collections, &p.RevenueShare, &p.LandownerShare, &p.Amount,
+			&p.PaymentMode, &p.Reference, &paidAt, &entryID, &createdBy, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revenue share payout: %w", err)
+		}
+		if paidAt.Valid {
+			p.PaidAt = &paidAt.Time
+		}
+		p.JournalEntryID = nullStringPtr(entryID)
+		p.CreatedBy = nullStringPtr(createdBy)
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// buildableArea is the floor area a parcel supports in sqft: land area x FSI
+func buildableArea(areaAcres, fsi float64) float64 {
+	return roundTo2(areaAcres * models.SqftPerAcre * fsi)
+}
+
+// landownerEntitlement is the saleable area due to one landowner: their share of
+// the landowners' area share of the project
+func landownerEntitlement(projectSBUA, areaSharePercent, landownerSharePercent float64) float64 {
+	return roundTo2(projectSBUA * areaSharePercent / 100 * landownerSharePercent / 100)
+}
+
+// validateJDAShares checks the share type carries the shares it needs and that
+// the landowners' shares add up to 100
+func validateJDAShares(req *models.CreateJDARequest) error {
+	switch req.ShareType {
+	case models.JDAShareArea:
+		if req.AreaSharePercent <= 0 {
+			return fmt.Errorf("area_share_percent is required for an area share JDA")
+		}
+	case models.JDAShareRevenue:
+		if req.RevenueSharePct <= 0 {
+			return fmt.Errorf("revenue_share_percent is required for a revenue share JDA")
+		}
+	case models.JDAShareHybrid:
+		if req.AreaSharePercent <= 0 || req.RevenueSharePct <= 0 {
+			return fmt.Errorf("area_share_percent and revenue_share_percent are required for a hybrid JDA")
+		}
+	default:
+		return fmt.Errorf("share_type must be area, revenue or hybrid")
+	}
+	if req.AreaSharePercent < 0 || req.AreaSharePercent >= 100 || req.RevenueSharePct < 0 || req.RevenueSharePct >= 100 {
+		return fmt.Errorf("shares must be between 0 and 100 percent")
+	}
+	if len(req.Landowners) == 0 {
+		return fmt.Errorf("at least one landowner is required")
+	}
+	total := 0.0
+	for _, lo := range req.Landowners {
+		if strings.TrimSpace(lo.Name) == "" {
+			return fmt.Errorf("landowner name is required")
+		}
+		if lo.SharePercent <= 0 {
+			return fmt.Errorf("share_percent for %s must be positive", lo.Name)
+		}
+		total += lo.SharePercent
+	}
+	if math.Abs(total-100) > 0.01 {
+		return fmt.Errorf("landowner shares add up to %.2f%%, not 100%%", total)
+	}
+	return nil
+}
+
+// buildAreaPosition splits the landowners' area share between them against the
+// area allotted to each so far
+func buildAreaPosition(jda *models.JointDevelopmentAgreement, projectSBUA float64) *models.JDAAreaPosition {
+	pos := &models.JDAAreaPosition{
+		JDAID:            jda.ID,
+		ProjectSBUA:      roundTo2(projectSBUA),
+		AreaSharePercent: jda.AreaSharePercent,
+		LandownerSBUA:    roundTo2(projectSBUA * jda.AreaSharePercent / 100),
+		Entitlements:     []models.JDAEntitlement{},
+	}
+	for _, lo := range jda.Landowners {
+		e := models.JDAEntitlement{
+			LandownerID:   lo.ID,
+			LandownerName: lo.Name,
+			SharePercent:  lo.SharePercent,
+			EntitledSBUA:  landownerEntitlement(projectSBUA, jda.AreaSharePercent, lo.SharePercent),
+		}
+		for _, a := range jda.Allocations {
+			if a.LandownerID == lo.ID && a.Status == models.JDAAllocationAllotted {
+				e.AllottedSBUA += a.SBUA
+				e.UnitsAllotted++
+			}
+		}
+		e.AllottedSBUA = roundTo2(e.AllottedSBUA)
+		e.BalanceSBUA = roundTo2(e.EntitledSBUA - e.AllottedSBUA)
+		pos.AllottedSBUA += e.AllottedSBUA
+		pos.Entitlements = append(pos.Entitlements, e)
+	}
+	pos.AllottedSBUA = roundTo2(pos.AllottedSBUA)
+	return pos
+}
+
+// splitRevenueShare divides the landowners' revenue share of collections between
+// them. The last landowner takes the rounding difference so the payouts add up
+// to the landowners' share exactly.
+func splitRevenueShare(jda *models.JointDevelopmentAgreement, collections float64, from, to time.Time) []models.JDARevenuePayout {
+	pool := roundTo2(collections * jda.RevenueSharePct / 100)
+	payouts := make([]models.JDARevenuePayout, 0, len(jda.Landowners))
+	remaining := pool
+	for i, lo := range jda.Landowners {
+		amount := roundTo2(pool * lo.SharePercent / 100)
+		if i == len(jda.Landowners)-1 {
+			amount = roundTo2(remaining)
+		}
+		remaining -= amount
+		payouts = append(payouts, models.JDARevenuePayout{
+			TenantID:       jda.TenantID,
+			JDAID:          jda.ID,
+			LandownerID:    lo.ID,
+			LandownerName:  lo.Name,
+			PeriodFrom:     from,
+			PeriodTo:       to,
+			Collections:    collections,
+			RevenueShare:   jda.RevenueSharePct,
+			LandownerShare: lo.SharePercent,
+			Amount:         amount,
+		})
+	}
+	return payouts
+}
+
+// periodsOverlap reports whether two half-open periods [from, to) overlap
+func periodsOverlap(aFrom, aTo, bFrom, bTo time.Time) bool {
+	return aFrom.Before(bTo) && bFrom.Before(aTo)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLandEntitlements tests buildable area and each landowner's share of the saleable area
+func TestLandEntitlements(t *testing.T) {
+	assert.Equal(t, 87120.0, buildableArea(1, 2))
+	assert.Equal(t, 0.0, buildableArea(2.5, 0))
+
+	// 40% of 100,000 sqft to landowners, split 60/40
+	assert.Equal(t, 24000.0, landownerEntitlement(100000, 40, 60))
+	assert.Equal(t, 16000.0, landownerEntitlement(100000, 40, 40))
+
+	jda := &models.JointDevelopmentAgreement{
+		ID:               "jda-1",
+		AreaSharePercent: 40,
+		Landowners: []models.JDALandowner{
+			{ID: "lo-1", Name: "Ramaiah", SharePercent: 60},
+			{ID: "lo-2", Name: "Lakshmamma", SharePercent: 40},
+		},
+		Allocations: []models.JDAUnitAllocation{
+			{LandownerID: "lo-1", SBUA: 1450, Status: models.JDAAllocationAllotted},
+			{LandownerID: "lo-1", SBUA: 1200, Status: models.JDAAllocationReleased},
+			{LandownerID: "lo-2", SBUA: 1650, Status: models.JDAAllocationAllotted},
+		},
+	}
+	pos := buildAreaPosition(jda, 100000)
+	assert.Equal(t, 40000.0, pos.LandownerSBUA)
+	assert.Equal(t, 3100.0, pos.AllottedSBUA)
+	assert.Equal(t, 1, pos.Entitlements[0].UnitsAllotted)
+	assert.Equal(t, 22550.0, pos.Entitlements[0].BalanceSBUA)
+	assert.Equal(t, 14350.0, pos.Entitlements[1].BalanceSBUA)
+}
+
+// TestValidateJDAShares tests share types and that landowner shares add up to 100
+func TestValidateJDAShares(t *testing.T) {
+	req := &models.CreateJDARequest{
+		ShareType:        models.JDAShareArea,
+		AreaSharePercent: 40,
+		Landowners:       []models.JDALandownerInput{{Name: "A", SharePercent: 50}, {Name: "B", SharePercent: 50}},
+	}
+	assert.NoError(t, validateJDAShares(req))
+
+	req.Landowners[1].SharePercent = 40
+	assert.Error(t, validateJDAShares(req))
+
+	req.Landowners[1].SharePercent = 50
+	req.ShareType = models.JDAShareHybrid
+	assert.Error(t, validateJDAShares(req)) // hybrid needs a revenue share too
+	req.RevenueSharePct = 10
+	assert.NoError(t, validateJDAShares(req))
+
+	req.ShareType = "barter"
+	assert.Error(t, validateJDAShares(req))
+}
+
+// TestSplitRevenueShare tests the payout split and that rounding goes to the last landowner
+func TestSplitRevenueShare(t *testing.T) {
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	jda := &models.JointDevelopmentAgreement{
+		ID:              "jda-1",
+		RevenueSharePct: 35,
+		Landowners: []models.JDALandowner{
+			{ID: "lo-1", SharePercent: 33.33},
+			{ID: "lo-2", SharePercent: 33.33},
+			{ID: "lo-3", SharePercent: 33.34},
+		},
+	}
+	payouts := splitRevenueShare(jda, 1000000.01, from, to)
+	assert.Len(t, payouts, 3)
+	total := 0.0
+	for _, p := range payouts {
+		total += p.Amount
+		assert.Equal(t, from, p.PeriodFrom)
+	}
+	assert.InDelta(t, 350000.0, total, 0.001)
+	assert.Equal(t, 116655.0, payouts[0].Amount)
+
+	assert.True(t, periodsOverlap(from, to, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, periodsOverlap(from, to, to, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,977 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// HOME LOAN DISBURSEMENT REQUEST SERVICE
+// ============================================================================
+
+// LoanDisbursementService raises disbursement requests to the customer's lender
+// for construction-linked demands, in the lender's own format, tracks consent,
+// documents and submission, and matches the lender's credits back to the
+// request, the booking's BankDisbursement and the demanded payment stage
+type LoanDisbursementService struct {
+	DB        *sql.DB
+	Financing *BankFinancingService
+	Escrow    *RERAComplianceService
+}
+
+// NewLoanDisbursementService creates a new loan disbursement service
+func NewLoanDisbursementService(db *sql.DB, financing *BankFinancingService, escrow *RERAComplianceService) *LoanDisbursementService {
+	return &LoanDisbursementService{DB: db, Financing: financing, Escrow: escrow}
+}
+
+// defaultLenderFormat is used for banks that have not given us their own format
+var defaultLenderFormat = models.LenderRequestFormat{
+	FormatName:  "Standard",
+	AddressedTo: "The Branch Manager, {{bank_name}}, {{branch_name}}",
+	SubjectTemplate: "Request for disbursement - loan account {{loan_account}} - {{customer_name}} - " +
+		"unit {{unit_number}}, {{project_name}}",
+	BodyTemplate: "Dear Sir/Madam,\n\nOur customer {{customer_name}} (loan account {{loan_account}}) has booked unit " +
+		"{{unit_number}} in {{project_name}} under booking {{booking_reference}}. The instalment \"{{stage_name}}\" of " +
+		"Rs. {{demand_amount}} has fallen due under demand letter {{demand_letter}} dated {{demand_date}}.\n\n" +
+		"We request you to disburse Rs. {{requested_amount}} by {{due_date}}. The architect's certificate " +
+		"{{architect_certificate}} and the customer's consent are enclosed.\n\nThanking you.",
+}
+
+// loanRequestOpenStatuses are the statuses whose requested amount still counts against the sanction
+var loanRequestOpenStatuses = []string{models.LoanRequestPendingDocuments, models.LoanRequestReady,
+	models.LoanRequestSubmitted, models.LoanRequestPartiallyDisbursed}
+
+// ============================================================================
+// LENDER FORMATS
+// ============================================================================
+
+// SetFormat creates or updates a bank's disbursement request format
+func (s *LoanDisbursementService) SetFormat(tenantID string, req *models.SetLenderRequestFormatRequest) (*models.LenderRequestFormat, error) {
+	if req.BankID == "" || strings.TrimSpace(req.SubjectTemplate) == "" || strings.TrimSpace(req.BodyTemplate) == "" {
+		return nil, fmt.Errorf("bank_id, subject_template and body_template are required")
+	}
+	for _, doc := range req.RequiredDocuments {
+		if strings.TrimSpace(doc) == "" {
+			return nil, fmt.Errorf("required document types cannot be blank")
+		}
+	}
+	now := time.Now()
+	f := &models.LenderRequestFormat{
+		ID:                uuid.New().String(),
+		TenantID:          tenantID,
+		BankID:            req.BankID,
+		FormatName:        req.FormatName,
+		AddressedTo:       req.AddressedTo,
+		SubjectTemplate:   req.SubjectTemplate,
+		BodyTemplate:      req.BodyTemplate,
+		RequiredDocuments: req.RequiredDocuments,
+		SubmissionEmail:   req.SubmissionEmail,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if f.RequiredDocuments == nil {
+		f.RequiredDocuments = []string{}
+	}
+	docs, err := json.Marshal(f.RequiredDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode required documents: %w", err)
+	}
+	if _, err := s.DB.Exec(`INSERT INTO lender_request_formats
+		(id, tenant_id, bank_id, format_name, addressed_to, subject_template, body_template, required_documents,
+		 submission_email, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE format_name = VALUES(format_name), addressed_to = VALUES(addressed_to),
+		subject_template = VALUES(subject_template), body_template = VALUES(body_template),
+		required_documents = VALUES(required_documents), submission_email = VALUES(submission_email),
+		updated_at = VALUES(updated_at)`,
+		f.ID, tenantID, f.BankID, nullIfEmpty(f.FormatName), nullIfEmpty(f.AddressedTo), f.SubjectTemplate,
+		f.BodyTemplate, docs, nullIfEmpty(f.SubmissionEmail), now, now); err != nil {
+		return nil, fmt.Errorf("failed to save lender format: %w", err)
+	}
+	return s.GetFormat(tenantID, req.BankID)
+}
+
+// GetFormat returns a bank's request format, or the standard format when it has none
+func (s *LoanDisbursementService) GetFormat(tenantID, bankID string) (*models.LenderRequestFormat, error) {
+	f := models.LenderRequestFormat{}
+	var docs []byte
+	err := s.DB.QueryRow(`SELECT id, tenant_id, bank_id, COALESCE(format_name, ''), COALESCE(addressed_to, ''),
+		subject_template, body_template, required_documents, COALESCE(submission_email, ''), created_at, updated_at
+		FROM lender_request_formats WHERE tenant_id = ? AND bank_id = ?`, tenantID, bankID).Scan(
+		&f.ID, &f.TenantID, &f.BankID, &f.FormatName, &f.AddressedTo, &f.SubjectTemplate, &f.BodyTemplate, &docs,
+		&f.SubmissionEmail, &f.CreatedAt, &f.UpdatedAt)
+	if err == sql.ErrNoRows {
+		f = defaultLenderFormat
+		f.TenantID = tenantID
+		f.BankID = bankID
+		f.RequiredDocuments = []string{}
+		return &f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lender format: %w", err)
+	}
+	if err := json.Unmarshal(docs, &f.RequiredDocuments); err != nil {
+		return nil, fmt.Errorf("failed to decode required documents: %w", err)
+	}
+	return &f, nil
+}
+
+// ============================================================================
+// DISBURSEMENT REQUESTS
+// ============================================================================
+
+// RequestForDemandLetter raises a disbursement request for a demand letter when
+// the booking has a sanctioned loan with undrawn sanction. It returns nil when
+// there is no loan to draw on or a request already exists for the letter.
+func (s *LoanDisbursementService) RequestForDemandLetter(tenantID, userID, letterID string) (*models.LoanDisbursementRequest, error) {
+	var letter models.DemandLetter
+	var milestoneID sql.NullString
+	err := s.DB.QueryRow(`SELECT id, letter_number, booking_id, COALESCE(booking_reference, ''), plan_stage_id,
+		milestone_id, schedule_id, COALESCE(project_name, ''), COALESCE(unit_number, ''), COALESCE(customer_name, ''),
+		stage_name, stage_amount, demand_date, due_date
+		FROM demand_letters WHERE id = ? AND tenant_id = ?`, letterID, tenantID).Scan(
+		&letter.ID, &letter.LetterNumber, &letter.BookingID, &letter.BookingReference, &letter.PlanStageID,
+		&milestoneID, &letter.ScheduleID, &letter.ProjectName, &letter.UnitNumber, &letter.CustomerName,
+		&letter.StageName, &letter.StageAmount, &letter.DemandDate, &letter.DueDate)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("demand letter not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get demand letter: %w", err)
+	}
+	letter.MilestoneID = nullStringPtr(milestoneID)
+
+	var exists bool
+	if err := s.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM loan_disbursement_requests
+		WHERE tenant_id = ? AND demand_letter_id = ?)`, tenantID, letterID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check existing request: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+
+	financing, err := s.Financing.GetActiveFinancingForBooking(context.Background(), tenantID, letter.BookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking financing: %w", err)
+	}
+	if financing == nil || financing.BankID == nil || financing.SanctionedAmount <= 0 ||
+		(financing.Status != "sanctioned" && financing.Status != "disbursing") {
+		return nil, nil
+	}
+
+	var pending float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(requested_amount - disbursed_amount), 0)
+		FROM loan_disbursement_requests WHERE tenant_id = ? AND financing_id = ? AND status IN (?, ?, ?, ?)`,
+		tenantID, financing.ID, loanRequestOpenStatuses[0], loanRequestOpenStatuses[1], loanRequestOpenStatuses[2],
+		loanRequestOpenStatuses[3]).Scan(&pending); err != nil {
+		return nil, fmt.Errorf("failed to fetch pending requests: %w", err)
+	}
+	amount := loanRequestAmount(letter.StageAmount, financing.SanctionedAmount, financing.DisbursedAmount, pending)
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	format, err := s.GetFormat(tenantID, *financing.BankID)
+	if err != nil {
+		return nil, err
+	}
+	var bankName, branchName, loanAccount, architectCert string
+	if err := s.DB.QueryRow(`SELECT COALESCE(bank_name, ''), COALESCE(branch_name, '') FROM bank
+		WHERE id = ? AND tenant_id = ?`, *financing.BankID, tenantID).Scan(&bankName, &branchName); err != nil &&
+		err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get bank: %w", err)
+	}
+	if err := s.DB.QueryRow(`SELECT COALESCE(application_ref_no, '') FROM bank_financing WHERE id = ? AND tenant_id = ?`,
+		financing.ID, tenantID).Scan(&loanAccount); err != nil {
+		return nil, fmt.Errorf("failed to get loan account: %w", err)
+	}
+	if letter.MilestoneID != nil {
+		if err := s.DB.QueryRow(`SELECT COALESCE(architect_certificate_ref, '') FROM tower_milestones
+			WHERE id = ? AND tenant_id = ?`, *letter.MilestoneID, tenantID).Scan(&architectCert); err != nil &&
+			err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get milestone: %w", err)
+		}
+	}
+
+	now := time.Now()
+	r := &models.LoanDisbursementRequest{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		RequestNumber:   interestNoteNumber("LDR"),
+		FinancingID:     financing.ID,
+		BookingID:       letter.BookingID,
+		BankID:          *financing.BankID,
+		LoanAccountRef:  loanAccount,
+		DemandLetterID:  letter.ID,
+		PlanStageID:     letter.PlanStageID,
+		ScheduleID:      letter.ScheduleID,
+		MilestoneID:     letter.MilestoneID,
+		StageName:       letter.StageName,
+		DemandAmount:    letter.StageAmount,
+		RequestedAmount: amount,
+		Outstanding:     amount,
+		DueDate:         letter.DueDate,
+		Documents:       initialLoanDocuments(letter.LetterNumber, architectCert, format.RequiredDocuments),
+		CreatedBy:       optionalString(userID),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	r.Status = loanRequestReadiness(r.Documents)
+	fields := map[string]string{
+		"bank_name":             bankName,
+		"branch_name":           branchName,
+		"loan_account":          loanAccount,
+		"customer_name":         letter.CustomerName,
+		"unit_number":           letter.UnitNumber,
+		"project_name":          letter.ProjectName,
+		"booking_reference":     letter.BookingReference,
+		"stage_name":            letter.StageName,
+		"demand_amount":         fmt.Sprintf("%.2f", letter.StageAmount),
+		"requested_amount":      fmt.Sprintf("%.2f", amount),
+		"demand_letter":         letter.LetterNumber,
+		"demand_date":           letter.DemandDate.Format("02 Jan 2006"),
+		"due_date":              letter.DueDate.Format("02 Jan 2006"),
+		"architect_certificate": architectCert,
+	}
+	r.AddressedTo = renderLenderTemplate(format.AddressedTo, fields)
+	r.Subject = renderLenderTemplate(format.SubjectTemplate, fields)
+	r.Body = renderLenderTemplate(format.BodyTemplate, fields)
+
+	docs, err := json.Marshal(r.Documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request documents: %w", err)
+	}
+	if _, err := s.DB.Exec(`INSERT INTO loan_disbursement_requests
+		(id, tenant_id, request_number, financing_id, booking_id, bank_id, loan_account_ref, demand_letter_id,
+		 plan_stage_id, schedule_id, milestone_id, stage_name, demand_amount, requested_amount, disbursed_amount,
+		 due_date, addressed_to, subject, body, documents, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, tenantID, r.RequestNumber, r.FinancingID, r.BookingID, r.BankID, nullIfEmpty(r.LoanAccountRef),
+		r.DemandLetterID, r.PlanStageID, nullIfEmpty(r.ScheduleID), r.MilestoneID, r.StageName, r.DemandAmount,
+		r.RequestedAmount, r.DueDate, nullIfEmpty(r.AddressedTo), r.Subject, r.Body, docs, r.Status, r.CreatedBy,
+		now, now); err != nil {
+		return nil, fmt.Errorf("failed to create disbursement request: %w", err)
+	}
+	return r, nil
+}
+
+// GetRequest returns a disbursement request
+func (s *LoanDisbursementService) GetRequest(tenantID, requestID string) (*models.LoanDisbursementRequest, error) {
+	requests, err := s.getRequests(s.DB, tenantID, "AND id = ?", requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("disbursement request not found")
+	}
+	return &requests[0], nil
+}
+
+// ListRequests lists disbursement requests, optionally by status, booking or bank
+func (s *LoanDisbursementService) ListRequests(tenantID, status, bookingID, bankID string) ([]models.LoanDisbursementRequest, error) {
+	where := ""
+	args := []interface{}{}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	if bankID != "" {
+		where += " AND bank_id = ?"
+		args = append(args, bankID)
+	}
+	return s.getRequests(s.DB, tenantID, where, args...)
+}
+
+// RecordConsent records the customer's consent to a disbursement request. bookingID
+// is set when the customer consents on the portal and must be the request's booking.
+func (s *LoanDisbursementService) RecordConsent(tenantID, bookingID, requestID string, req *models.LoanConsentRequest) (*models.LoanDisbursementRequest, error) {
+	reference := strings.TrimSpace(req.ConsentReference)
+	if reference == "" {
+		reference = "portal"
+	}
+	return s.updateDocuments(tenantID, requestID, func(r *models.LoanDisbursementRequest) error {
+		if bookingID != "" && r.BookingID != bookingID {
+			return fmt.Errorf("disbursement request not found")
+		}
+		if r.ConsentAt != nil {
+			return fmt.Errorf("consent has already been recorded")
+		}
+		now := time.Now()
+		r.ConsentAt = &now
+		r.ConsentReference = reference
+		attachLoanDocument(r.Documents, models.LoanDocCustomerConsent, reference, now)
+		return nil
+	})
+}
+
+// AttachDocument attaches a document the lender requires to a disbursement request
+func (s *LoanDisbursementService) AttachDocument(tenantID, requestID string, req *models.AttachLoanDocumentRequest) (*models.LoanDisbursementRequest, error) {
+	if req.DocumentType == "" || strings.TrimSpace(req.Reference) == "" {
+		return nil, fmt.Errorf("document_type and reference are required")
+	}
+	if req.DocumentType == models.LoanDocCustomerConsent {
+		return nil, fmt.Errorf("customer consent is recorded through the consent endpoint")
+	}
+	return s.updateDocuments(tenantID, requestID, func(r *models.LoanDisbursementRequest) error {
+		if !attachLoanDocument(r.Documents, req.DocumentType, strings.TrimSpace(req.Reference), time.Now()) {
+			return fmt.Errorf("%s is not a document on this request", req.DocumentType)
+		}
+		return nil
+	})
+}
+
+// updateDocuments applies a change to a request's documents and recomputes whether it is ready to submit
+func (s *LoanDisbursementService) updateDocuments(tenantID, requestID string, change func(r *models.LoanDisbursementRequest) error) (*models.LoanDisbursementRequest, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	requests, err := s.getRequests(tx, tenantID, "AND id = ? FOR UPDATE", requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("disbursement request not found")
+	}
+	r := &requests[0]
+	if r.Status != models.LoanRequestPendingDocuments && r.Status != models.LoanRequestReady {
+		return nil, fmt.Errorf("disbursement request is %s", r.Status)
+	}
+	if err := change(r); err != nil {
+		return nil, err
+	}
+	r.Status = loanRequestReadiness(r.Documents)
+	r.UpdatedAt = time.Now()
+
+	docs, err := json.Marshal(r.Documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request documents: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE loan_disbursement_requests SET documents = ?, consent_at = ?, consent_reference = ?,
+		status = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		docs, r.ConsentAt, nullIfEmpty(r.ConsentReference), r.Status, r.UpdatedAt, requestID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update disbursement request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit disbursement request: %w", err)
+	}
+	return r, nil
+}
+
+// SubmitRequest marks a ready request as sent to the lender and opens the
+// booking's BankDisbursement that the lender's credit will be matched to
+func (s *LoanDisbursementService) SubmitRequest(tenantID, userID, requestID string) (*models.LoanDisbursementRequest, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	requests, err := s.getRequests(tx, tenantID, "AND id = ? FOR UPDATE", requestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("disbursement request not found")
+	}
+	r := &requests[0]
+	if r.Status != models.LoanRequestReady {
+		if r.Status == models.LoanRequestPendingDocuments {
+			return nil, fmt.Errorf("missing documents: %s", strings.Join(missingLoanDocuments(r.Documents), ", "))
+		}
+		return nil, fmt.Errorf("disbursement request is %s", r.Status)
+	}
+
+	var number int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(disbursement_number), 0) + 1 FROM bank_disbursement
+		WHERE tenant_id = ? AND financing_id = ?`, tenantID, r.FinancingID).Scan(&number); err != nil {
+		return nil, fmt.Errorf("failed to number disbursement: %w", err)
+	}
+	now := time.Now()
+	disbursementID := uuid.New().String()
+	if _, err := tx.Exec(`INSERT INTO bank_disbursement
+		(id, tenant_id, financing_id, disbursement_number, scheduled_amount, milestone_id, status, scheduled_date,
+		 created_by, created_at, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		disbursementID, tenantID, r.FinancingID, number, r.RequestedAmount, r.MilestoneID, "pending", r.DueDate,
+		nullIfEmpty(userID), now, nullIfEmpty(userID), now); err != nil {
+		return nil, fmt.Errorf("failed to create bank disbursement: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE loan_disbursement_requests SET status = ?, disbursement_id = ?, submitted_at = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		models.LoanRequestSubmitted, disbursementID, now, now, requestID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to submit disbursement request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit disbursement request: %w", err)
+	}
+
+	r.Status = models.LoanRequestSubmitted
+	r.DisbursementID = &disbursementID
+	r.SubmittedAt = &now
+	r.UpdatedAt = now
+	return r, nil
+}
+
+// RejectRequest records that the lender declined a request. The demand stays
+// open for the customer to pay.
+func (s *LoanDisbursementService) RejectRequest(tenantID, requestID string, req *models.RejectLoanRequestRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return fmt.Errorf("reason is required")
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var disbursementID sql.NullString
+	err = tx.QueryRow(`SELECT disbursement_id FROM loan_disbursement_requests
+		WHERE id = ? AND tenant_id = ? AND status IN (?, ?, ?) AND disbursed_amount = 0 FOR UPDATE`,
+		requestID, tenantID, models.LoanRequestPendingDocuments, models.LoanRequestReady,
+		models.LoanRequestSubmitted).Scan(&disbursementID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("disbursement request is not open or has already received funds")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get disbursement request: %w", err)
+	}
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE loan_disbursement_requests SET status = ?, rejection_reason = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, models.LoanRequestRejected, req.Reason, now, requestID, tenantID); err != nil {
+		return fmt.Errorf("failed to reject disbursement request: %w", err)
+	}
+	if disbursementID.Valid {
+		if _, err := tx.Exec(`UPDATE bank_disbursement SET status = 'cancelled', updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, now, disbursementID.String, tenantID); err != nil {
+			return fmt.Errorf("failed to cancel bank disbursement: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rejection: %w", err)
+	}
+	return nil
+}
+
+func (s *LoanDisbursementService) getRequests(db interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, tenantID, where string, args ...interface{}) ([]models.LoanDisbursementRequest, error) {
+	rows, err := db.Query(`SELECT id, tenant_id, request_number, financing_id, booking_id, bank_id,
+		COALESCE(loan_account_ref, ''), demand_letter_id, plan_stage_id, COALESCE(schedule_id, ''), milestone_id,
+		stage_name, demand_amount, requested_amount, disbursed_amount, due_date, COALESCE(addressed_to, ''), subject,
+		body, documents, consent_at, COALESCE(consent_reference, ''), status, disbursement_id, submitted_at,
+		COALESCE(rejection_reason, ''), created_by, created_at, updated_at
+		FROM loan_disbursement_requests WHERE tenant_id = ? `+where,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch disbursement requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.LoanDisbursementRequest{}
+	for rows.Next() {
+		var r models.LoanDisbursementRequest
+		var milestoneID, disbursementID, createdBy sql.NullString
+		var consentAt, submittedAt sql.NullTime
+		var docs []byte
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.RequestNumber, &r.FinancingID, &r.BookingID, &r.BankID,
+			&r.LoanAccountRef, &r.DemandLetterID, &r.PlanStageID, &r.ScheduleID, &milestoneID, &r.StageName,
+			&r.DemandAmount, &r.RequestedAmount, &r.DisbursedAmount, &r.DueDate, &r.AddressedTo, &r.Subject, &r.Body,
+			&docs, &consentAt, &r.ConsentReference, &r.Status, &disbursementID, &submittedAt, &r.RejectionReason,
+			&createdBy, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan disbursement request: %w", err)
+		}
+		if err := json.Unmarshal(docs, &r.Documents); err != nil {
+			return nil, fmt.Errorf("failed to decode request documents: %w", err)
+		}
+		r.MilestoneID = nullStringPtr(milestoneID)
+		r.DisbursementID = nullStringPtr(disbursementID)
+		r.CreatedBy = nullStringPtr(createdBy)
+		if consentAt.Valid {
+			r.ConsentAt = &consentAt.Time
+		}
+		if submittedAt.Valid {
+			r.SubmittedAt = &submittedAt.Time
+		}
+		r.Outstanding = roundTo2(math.Max(r.RequestedAmount-r.DisbursedAmount, 0))
+		requests = append(requests, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].CreatedAt.After(requests[j].CreatedAt) })
+	return requests, nil
+}
+
+// ============================================================================
+// INCOMING DISBURSEMENTS
+// ============================================================================
+
+// RecordIncoming records a credit from a lender and matches it to a submitted
+// request of that bank. A credit that cannot be matched unambiguously is kept
+// as unmatched for the accounts team to match by hand.
+func (s *LoanDisbursementService) RecordIncoming(tenantID, userID string, req *models.RecordIncomingDisbursementRequest) (*models.IncomingLoanDisbursement, error) {
+	amount := roundTo2(req.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if req.BankID == "" || strings.TrimSpace(req.BankReference) == "" {
+		return nil, fmt.Errorf("bank_id and bank_reference are required")
+	}
+	in := &models.IncomingLoanDisbursement{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		BankID:         req.BankID,
+		Amount:         amount,
+		CreditedOn:     time.Now(),
+		BankReference:  strings.TrimSpace(req.BankReference),
+		LoanAccountRef: strings.TrimSpace(req.LoanAccountRef),
+		BookingID:      optionalString(req.BookingID),
+		MatchStatus:    models.LoanCreditUnmatched,
+		CreatedBy:      optionalString(userID),
+		CreatedAt:      time.Now(),
+	}
+	if req.CreditedOn != nil {
+		in.CreditedOn = *req.CreditedOn
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var duplicate bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM incoming_loan_disbursements
+		WHERE tenant_id = ? AND bank_id = ? AND bank_reference = ?)`, tenantID, in.BankID, in.BankReference).Scan(
+		&duplicate); err != nil {
+		return nil, fmt.Errorf("failed to check bank reference: %w", err)
+	}
+	if duplicate {
+		return nil, fmt.Errorf("credit %s from this bank has already been recorded", in.BankReference)
+	}
+
+	candidates, err := s.getRequests(tx, tenantID, "AND bank_id = ? AND status IN (?, ?) FOR UPDATE",
+		in.BankID, models.LoanRequestSubmitted, models.LoanRequestPartiallyDisbursed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`INSERT INTO incoming_loan_disbursements
+		(id, tenant_id, bank_id, amount, credited_on, bank_reference, loan_account_ref, booking_id, match_status,
+		 created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		in.ID, tenantID, in.BankID, amount, in.CreditedOn, in.BankReference, nullIfEmpty(in.LoanAccountRef),
+		in.BookingID, in.MatchStatus, in.CreatedBy, in.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record incoming disbursement: %w", err)
+	}
+
+	match := matchLoanDisbursement(in, candidates)
+	if match != nil {
+		if err := s.applyDisbursement(tx, userID, in, match, "auto"); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit incoming disbursement: %w", err)
+	}
+	return in, nil
+}
+
+// MatchIncoming matches an unmatched credit to a submitted request by hand
+func (s *LoanDisbursementService) MatchIncoming(tenantID, userID, incomingID string, req *models.MatchIncomingDisbursementRequest) (*models.IncomingLoanDisbursement, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	incoming, err := s.getIncoming(tx, tenantID, "AND id = ? FOR UPDATE", incomingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(incoming) == 0 {
+		return nil, fmt.Errorf("incoming disbursement not found")
+	}
+	in := &incoming[0]
+	if in.MatchStatus != models.LoanCreditUnmatched {
+		return nil, fmt.Errorf("incoming disbursement is already matched")
+	}
+	requests, err := s.getRequests(tx, tenantID, "AND id = ? FOR UPDATE", req.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("disbursement request not found")
+	}
+	r := &requests[0]
+	if r.BankID != in.BankID {
+		return nil, fmt.Errorf("the request is with a different bank")
+	}
+	if r.Status != models.LoanRequestSubmitted && r.Status != models.LoanRequestPartiallyDisbursed {
+		return nil, fmt.Errorf("disbursement request is %s", r.Status)
+	}
+	if in.Amount > r.Outstanding+0.005 {
+		return nil, fmt.Errorf("credit of %.2f exceeds the %.2f outstanding on the request", in.Amount, r.Outstanding)
+	}
+
+	if err := s.applyDisbursement(tx, userID, in, r, "manual"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit match: %w", err)
+	}
+	return in, nil
+}
+
+// ListIncoming lists credits from lenders, optionally by match status
+func (s *LoanDisbursementService) ListIncoming(tenantID, matchStatus string) ([]models.IncomingLoanDisbursement, error) {
+	if matchStatus != "" {
+		return s.getIncoming(s.DB, tenantID, "AND match_status = ? ORDER BY credited_on DESC", matchStatus)
+	}
+	return s.getIncoming(s.DB, tenantID, "ORDER BY credited_on DESC")
+}
+
+// applyDisbursement posts a matched credit: the request, the booking's
+// BankDisbursement and loan, the demanded stage's payment schedule, a cleared
+// booking payment and the customer ledger
+func (s *LoanDisbursementService) applyDisbursement(tx *sql.Tx, userID string, in *models.IncomingLoanDisbursement, r *models.LoanDisbursementRequest, matchedBy string) error {
+	tenantID := in.TenantID
+	now := time.Now()
+
+	var bankName, customerID string
+	if err := tx.QueryRow(`SELECT COALESCE((SELECT bank_name FROM bank WHERE id = ? AND tenant_id = ?), ''),
+		COALESCE((SELECT customer_id FROM customer_bookings WHERE id = ? AND tenant_id = ?), '')`,
+		r.BankID, tenantID, r.BookingID, tenantID).Scan(&bankName, &customerID); err != nil {
+		return fmt.Errorf("failed to get bank and customer: %w", err)
+	}
+
+	paymentID := uuid.New().String()
+	receiptNumber := interestNoteNumber("LRC")
+	remarks := fmt.Sprintf("Loan disbursement against %s", r.RequestNumber)
+	if _, err := tx.Exec(`INSERT INTO booking_payments
+		(id, tenant_id, booking_id, payment_date, payment_mode, paid_by, receipt_number, towards, amount, bank_name,
+		 transaction_id, status, remarks, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		paymentID, tenantID, r.BookingID, in.CreditedOn, "bank_disbursement", bankName, receiptNumber, r.StageName,
+		in.Amount, bankName, in.BankReference, "cleared", remarks, now, now); err != nil {
+		return fmt.Errorf("failed to record booking payment: %w", err)
+	}
+	// Route the RERA share of the credit to the project's designated account
+	if s.Escrow != nil {
+		if _, err := s.Escrow.SplitBookingReceiptTx(tx, tenantID, r.BookingID, paymentID, in.Amount); err != nil {
+			return err
+		}
+	}
+	if _, err := insertCustomerLedgerEntry(tx, tenantID, r.BookingID, customerID, "credit",
+		fmt.Sprintf("Loan disbursement received - %s", r.StageName), in.Amount, receiptNumber); err != nil {
+		return err
+	}
+
+	disbursed := roundTo2(r.DisbursedAmount + in.Amount)
+	status := models.LoanRequestPartiallyDisbursed
+	if disbursed >= r.RequestedAmount-0.005 {
+		status = models.LoanRequestDisbursed
+	}
+	if _, err := tx.Exec(`UPDATE loan_disbursement_requests SET disbursed_amount = ?, status = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, disbursed, status, now, r.ID, tenantID); err != nil {
+		return fmt.Errorf("failed to update disbursement request: %w", err)
+	}
+	if r.DisbursementID != nil {
+		if _, err := tx.Exec(`UPDATE bank_disbursement SET actual_amount = COALESCE(actual_amount, 0) + ?,
+			actual_date = ?, bank_reference_no = ?, status = 'credited', updated_by = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, in.Amount, in.CreditedOn, in.BankReference, nullIfEmpty(userID), now,
+			*r.DisbursementID, tenantID); err != nil {
+			return fmt.Errorf("failed to update bank disbursement: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE bank_financing SET disbursed_amount = disbursed_amount + ?,
+		status = IF(disbursed_amount >= sanctioned_amount - 0.005, 'completed', 'disbursing'),
+		updated_by = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		in.Amount, nullIfEmpty(userID), now, r.FinancingID, tenantID); err != nil {
+		return fmt.Errorf("failed to update bank financing: %w", err)
+	}
+	if r.ScheduleID != "" {
+		if _, err := tx.Exec(`UPDATE payment_schedules SET amount_paid = amount_paid + ?,
+			outstanding = GREATEST(payment_amount - amount_paid, 0),
+			status = IF(amount_paid >= payment_amount - 0.005, 'paid', 'partially_paid'), updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, in.Amount, now, r.ScheduleID, tenantID); err != nil {
+			return fmt.Errorf("failed to update payment schedule: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE incoming_loan_disbursements SET request_id = ?, booking_id = ?, payment_id = ?,
+		match_status = ?, matched_by = ? WHERE id = ? AND tenant_id = ?`,
+		r.ID, r.BookingID, paymentID, models.LoanCreditMatched, matchedBy, in.ID, tenantID); err != nil {
+		return fmt.Errorf("failed to match incoming disbursement: %w", err)
+	}
+
+	in.RequestID = &r.ID
+	in.BookingID = &r.BookingID
+	in.PaymentID = &paymentID
+	in.MatchStatus = models.LoanCreditMatched
+	in.MatchedBy = matchedBy
+	return nil
+}
+
+func (s *LoanDisbursementService) getIncoming(db interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, tenantID, where string, args ...interface{}) ([]models.IncomingLoanDisbursement, error) {
+	rows, err := db.Query(`SELECT id, tenant_id, bank_id, amount, credited_on, bank_reference,
+		COALESCE(loan_account_ref, ''), booking_id, request_id, payment_id, match_status, COALESCE(matched_by, ''),
+		created_by, created_at
+		FROM incoming_loan_disbursements WHERE tenant_id = ? `+where, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch incoming disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	incoming := []models.IncomingLoanDisbursement{}
+	for rows.Next() {
+		var in models.IncomingLoanDisbursement
+		var bookingID, requestID, paymentID, createdBy sql.NullString
+		if err := rows.Scan(&in.ID, &in.TenantID, &in.BankID, &in.Amount, &in.CreditedOn, &in.BankReference,
+			&in.LoanAccountRef, &bookingID, &requestID, &paymentID, &in.MatchStatus, &in.MatchedBy, &createdBy,
+			&in.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incoming disbursement: %w", err)
+		}
+		in.BookingID = nullStringPtr(bookingID)
+		in.RequestID = nullStringPtr(requestID)
+		in.PaymentID = nullStringPtr(paymentID)
+		in.CreatedBy = nullStringPtr(createdBy)
+		incoming = append(incoming, in)
+	}
+	return incoming, rows.Err()
+}
+
+// ============================================================================
+// CUSTOMER VIEW
+// ============================================================================
+
+// GetFundingSplit returns how a booking's instalments split between the loan and
+// the customer's own contribution
+func (s *LoanDisbursementService) GetFundingSplit(tenantID, bookingID string) (*models.LoanFundingSplit, error) {
+	rows, err := s.DB.Query(`SELECT `+bookingPlanStageColumns+` FROM booking_plan_stages bps
+		WHERE bps.tenant_id = ? AND bps.booking_id = ? ORDER BY bps.stage_number`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment plan: %w", err)
+	}
+	stages := []models.BookingPlanStage{}
+	for rows.Next() {
+		st, err := scanBookingPlanStage(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan plan stage: %w", err)
+		}
+		stages = append(stages, *st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("booking has no payment plan")
+	}
+
+	requests, err := s.getRequests(s.DB, tenantID, "AND booking_id = ? AND status NOT IN (?, ?)", bookingID,
+		models.LoanRequestRejected, models.LoanRequestCancelled)
+	if err != nil {
+		return nil, err
+	}
+	sanctioned := 0.0
+	financing, err := s.Financing.GetActiveFinancingForBooking(context.Background(), tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking financing: %w", err)
+	}
+	if financing != nil {
+		sanctioned = financing.SanctionedAmount
+	}
+	var received float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.booking_id = ? AND p.status = 'cleared' AND p.deleted_at IS NULL`,
+		tenantID, bookingID).Scan(&received); err != nil {
+		return nil, fmt.Errorf("failed to fetch booking receipts: %w", err)
+	}
+	return buildLoanFundingSplit(bookingID, stages, requests, sanctioned, received), nil
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// loanRequestAmount is what to ask the lender for on a demand: the demand, up
+// to the sanction not yet disbursed or already requested
+func loanRequestAmount(demand, sanctioned, disbursed, pending float64) float64 {
+	return roundTo2(math.Max(math.Min(demand, sanctioned-disbursed-pending), 0))
+}
+
+// initialLoanDocuments lists the documents a request needs. The demand letter is
+// attached from the start and the architect certificate when the milestone has one.
+func initialLoanDocuments(letterNumber, architectCert string, required []string) []models.LoanRequestDocument {
+	now := time.Now()
+	docs := []models.LoanRequestDocument{
+		{DocumentType: models.LoanDocDemandLetter, Reference: letterNumber, Attached: true, AttachedAt: &now},
+		{DocumentType: models.LoanDocArchitectCertificate},
+		{DocumentType: models.LoanDocCustomerConsent},
+	}
+	if architectCert != "" {
+		attachLoanDocument(docs, models.LoanDocArchitectCertificate, architectCert, now)
+	}
+	seen := map[string]bool{}
+	for _, d := range docs {
+		seen[d.DocumentType] = true
+	}
+	for _, t := range required {
+		if !seen[t] {
+			docs = append(docs, models.LoanRequestDocument{DocumentType: t})
+			seen[t] = true
+		}
+	}
+	return docs
+}
+
+// attachLoanDocument marks a document attached; false when the request has no such document
+func attachLoanDocument(docs []models.LoanRequestDocument, docType, reference string, at time.Time) bool {
+	for i := range docs {
+		if docs[i].DocumentType == docType {
+			docs[i].Reference = reference
+			docs[i].Attached = true
+			docs[i].AttachedAt = &at
+			return true
+		}
+	}
+	return false
+}
+
+func missingLoanDocuments(docs []models.LoanRequestDocument) []string {
+	missing := []string{}
+	for _, d := range docs {
+		if !d.Attached {
+			missing = append(missing, d.DocumentType)
+		}
+	}
+	return missing
+}
+
+// loanRequestReadiness is ready once every document, consent included, is attached
+func loanRequestReadiness(docs []models.LoanRequestDocument) string {
+	if len(missingLoanDocuments(docs)) > 0 {
+		return models.LoanRequestPendingDocuments
+	}
+	return models.LoanRequestReady
+}
+
+// renderLenderTemplate fills {{placeholders}} in a lender format
+func renderLenderTemplate(tpl string, fields map[string]string) string {
+	pairs := make([]string, 0, len(fields)*2)
+	for k, v := range fields {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tpl)
+}
+
+// matchLoanDisbursement finds the request a lender's credit pays. Candidates are
+// narrowed to the booking or loan account when the credit names one; a credit
+// for exactly one request's outstanding amount matches it, and a part payment
+// matches the oldest request only when the credit narrows to a single booking.
+func matchLoanDisbursement(in *models.IncomingLoanDisbursement, candidates []models.LoanDisbursementRequest) *models.LoanDisbursementRequest {
+	filtered := []*models.LoanDisbursementRequest{}
+	for i := range candidates {
+		c := &candidates[i]
+		if c.BankID != in.BankID || c.Outstanding <= 0 {
+			continue
+		}
+		if in.BookingID != nil && c.BookingID != *in.BookingID {
+			continue
+		}
+		if in.LoanAccountRef != "" && !strings.EqualFold(c.LoanAccountRef, in.LoanAccountRef) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].CreatedAt.Before(filtered[j].CreatedAt) })
+
+	var exact []*models.LoanDisbursementRequest
+	bookings := map[string]bool{}
+	for _, c := range filtered {
+		bookings[c.BookingID] = true
+		if math.Abs(c.Outstanding-in.Amount) < 0.01 {
+			exact = append(exact, c)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0]
+	}
+	if len(exact) > 1 {
+		if len(bookings) == 1 {
+			return exact[0]
+		}
+		return nil
+	}
+	if len(bookings) == 1 {
+		for _, c := range filtered {
+			if in.Amount <= c.Outstanding+0.005 {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// buildLoanFundingSplit splits each stage into the loan-funded part (what was
+// requested from the lender) and the customer's own contribution
+func buildLoanFundingSplit(bookingID string, stages []models.BookingPlanStage, requests []models.LoanDisbursementRequest, sanctioned, received float64) *models.LoanFundingSplit {
+	byStage := map[string]models.LoanDisbursementRequest{}
+	for _, r := range requests {
+		byStage[r.PlanStageID] = r
+	}
+
+	split := &models.LoanFundingSplit{BookingID: bookingID, SanctionedAmount: sanctioned, Stages: []models.LoanFundingStage{}}
+	ownDemanded := 0.0
+	for _, st := range stages {
+		fs := models.LoanFundingStage{
+			PlanStageID:     st.ID,
+			StageName:       st.StageName,
+			Amount:          st.Amount,
+			Status:          st.Status,
+			OwnContribution: st.Amount,
+		}
+		if r, ok := byStage[st.ID]; ok {
+			fs.LoanRequested = r.RequestedAmount
+			fs.LoanDisbursed = r.DisbursedAmount
+			fs.OwnContribution = roundTo2(st.Amount - r.RequestedAmount)
+			fs.RequestStatus = r.Status
+			split.LoanDisbursed += r.DisbursedAmount
+			split.LoanPending += r.RequestedAmount - r.DisbursedAmount
+		}
+		split.AgreementValue += st.Amount
+		split.OwnContribution += fs.OwnContribution
+		if st.Status == models.PlanStageStatusDemanded || st.Status == models.PlanStageStatusScheduled {
+			ownDemanded += fs.OwnContribution
+		}
+		split.Stages = append(split.Stages, fs)
+	}
+	split.AgreementValue = roundTo2(split.AgreementValue)
+	split.LoanDisbursed = roundTo2(split.LoanDisbursed)
+	split.LoanPending = roundTo2(split.LoanPending)
+	split.OwnContribution = roundTo2(split.OwnContribution)
+	split.OwnContributionPaid = roundTo2(math.Max(received-split.LoanDisbursed, 0))
+	split.OwnContributionDue = roundTo2(math.Max(ownDemanded-split.OwnContributionPaid, 0))
+	return split
+}
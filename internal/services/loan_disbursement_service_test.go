@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoanRequestDrafting tests the requested amount, required documents and the lender template
+func TestLoanRequestDrafting(t *testing.T) {
+	// 10L demand against a 50L sanction with 30L drawn and 15L already requested
+	assert.Equal(t, 500000.0, loanRequestAmount(1000000, 5000000, 3000000, 1500000))
+	assert.Equal(t, 1000000.0, loanRequestAmount(1000000, 5000000, 3000000, 0))
+	assert.Equal(t, 0.0, loanRequestAmount(1000000, 5000000, 4000000, 1500000))
+
+	docs := initialLoanDocuments("DL-001", "", []string{"noc", models.LoanDocDemandLetter})
+	assert.Len(t, docs, 4)
+	assert.Equal(t, models.LoanRequestPendingDocuments, loanRequestReadiness(docs))
+	assert.Equal(t, []string{models.LoanDocArchitectCertificate, models.LoanDocCustomerConsent, "noc"}, missingLoanDocuments(docs))
+
+	now := time.Now()
+	assert.True(t, attachLoanDocument(docs, models.LoanDocArchitectCertificate, "ARCH-7", now))
+	assert.True(t, attachLoanDocument(docs, models.LoanDocCustomerConsent, "OTP-1234", now))
+	assert.False(t, attachLoanDocument(docs, "sale_deed", "x", now))
+	assert.Equal(t, models.LoanRequestPendingDocuments, loanRequestReadiness(docs))
+	assert.True(t, attachLoanDocument(docs, "noc", "NOC-3", now))
+	assert.Equal(t, models.LoanRequestReady, loanRequestReadiness(docs))
+
+	subject := renderLenderTemplate("Disbursement - {{loan_account}} - {{customer_name}} - {{unknown}}",
+		map[string]string{"loan_account": "HL-991", "customer_name": "Anita Rao"})
+	assert.Equal(t, "Disbursement - HL-991 - Anita Rao - {{unknown}}", subject)
+}
+
+// TestMatchLoanDisbursement tests matching lender credits to submitted requests
+func TestMatchLoanDisbursement(t *testing.T) {
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	requests := []models.LoanDisbursementRequest{
+		{ID: "r1", BankID: "hdfc", BookingID: "b1", LoanAccountRef: "HL-1", Outstanding: 500000, CreatedAt: base},
+		{ID: "r2", BankID: "hdfc", BookingID: "b1", LoanAccountRef: "HL-1", Outstanding: 750000, CreatedAt: base.AddDate(0, 1, 0)},
+		{ID: "r3", BankID: "hdfc", BookingID: "b2", LoanAccountRef: "HL-2", Outstanding: 500000, CreatedAt: base},
+		{ID: "r4", BankID: "sbi", BookingID: "b3", LoanAccountRef: "SB-3", Outstanding: 750000, CreatedAt: base},
+	}
+	b1 := "b1"
+
+	// Unique exact amount for the bank
+	m := matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 750000}, requests)
+	assert.Equal(t, "r2", m.ID)
+
+	// Same amount on two bookings is ambiguous until the loan account narrows it
+	assert.Nil(t, matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 500000}, requests))
+	m = matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 500000, LoanAccountRef: "hl-2"}, requests)
+	assert.Equal(t, "r3", m.ID)
+
+	// A part payment on one booking goes to its oldest request that can take it
+	m = matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 300000, BookingID: &b1}, requests)
+	assert.Equal(t, "r1", m.ID)
+	m = matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 600000, BookingID: &b1}, requests)
+	assert.Equal(t, "r2", m.ID)
+
+	// A part payment across bookings, or more than anything outstanding, stays unmatched
+	assert.Nil(t, matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 300000}, requests))
+	assert.Nil(t, matchLoanDisbursement(&models.IncomingLoanDisbursement{BankID: "hdfc", Amount: 900000, BookingID: &b1}, requests))
+}
+
+// TestBuildLoanFundingSplit tests the loan-funded versus own-contribution split of a booking
+func TestBuildLoanFundingSplit(t *testing.T) {
+	stages := []models.BookingPlanStage{
+		{ID: "s1", StageName: "Booking", Amount: 1000000, Status: models.PlanStageStatusDemanded},
+		{ID: "s2", StageName: "Plinth", Amount: 2000000, Status: models.PlanStageStatusDemanded},
+		{ID: "s3", StageName: "Slab 5", Amount: 2000000, Status: models.PlanStageStatusDemanded},
+		{ID: "s4", StageName: "Possession", Amount: 1000000, Status: models.PlanStageStatusPending},
+	}
+	requests := []models.LoanDisbursementRequest{
+		{PlanStageID: "s2", RequestedAmount: 2000000, DisbursedAmount: 2000000, Status: models.LoanRequestDisbursed},
+		{PlanStageID: "s3", RequestedAmount: 1500000, DisbursedAmount: 0, Status: models.LoanRequestSubmitted},
+	}
+	// Booking amount paid by the customer plus the plinth disbursement
+	split := buildLoanFundingSplit("b1", stages, requests, 3500000, 3000000)
+
+	assert.Equal(t, 6000000.0, split.AgreementValue)
+	assert.Equal(t, 2000000.0, split.LoanDisbursed)
+	assert.Equal(t, 1500000.0, split.LoanPending)
+	assert.Equal(t, 2500000.0, split.OwnContribution)
+	assert.Equal(t, 1000000.0, split.OwnContributionPaid)
+	assert.Equal(t, 500000.0, split.OwnContributionDue) // the part of Slab 5 the loan does not cover
+	assert.Equal(t, 500000.0, split.Stages[2].OwnContribution)
+	assert.Equal(t, models.LoanRequestSubmitted, split.Stages[2].RequestStatus)
+}
@@ -0,0 +1,1050 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// MAINTENANCE BILLING SERVICE
+// ============================================================================
+
+// MaintenanceService bills maintenance on handed-over units until the residents'
+// association takes over: a one-time deposit (corpus) on enrolment, periodic CAM
+// and sinking fund invoices with GST above the exempt limit, receipts allocated
+// oldest invoice first, arrears, and the closing statement and corpus transfer
+// at association handover. Every invoice and receipt goes to the booking's
+// customer ledger and the GL.
+type MaintenanceService struct {
+	DB *sql.DB
+	GL *GLService
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(db *sql.DB, gl *GLService) *MaintenanceService {
+	return &MaintenanceService{DB: db, GL: gl}
+}
+
+// maintenanceBillingMonths is the months covered by one bill of each frequency
+var maintenanceBillingMonths = map[string]int{
+	models.MaintenanceMonthly:    1,
+	models.MaintenanceQuarterly:  3,
+	models.MaintenanceHalfYearly: 6,
+	models.MaintenanceAnnual:     12,
+}
+
+// maintenancePeriod is one billing period; Months is fractional for the first, part-month period
+type maintenancePeriod struct {
+	From   time.Time
+	To     time.Time // exclusive
+	Months float64
+}
+
+// ============================================================================
+// MAINTENANCE PLANS
+// ============================================================================
+
+// SetPlan creates or updates a project's maintenance tariff. New rates apply to
+// invoices generated from now on.
+func (s *MaintenanceService) SetPlan(tenantID string, req *models.SetMaintenancePlanRequest) (*models.MaintenancePlan, error) {
+	if req.CAMRatePerSqft < 0 || req.SinkingFundPerSqft < 0 || req.DepositPerSqft < 0 {
+		return nil, fmt.Errorf("rates cannot be negative")
+	}
+	if req.CAMRatePerSqft == 0 && req.SinkingFundPerSqft == 0 {
+		return nil, fmt.Errorf("cam_rate_per_sqft or sinking_fund_per_sqft is required")
+	}
+	frequency := req.BillingFrequency
+	if frequency == "" {
+		frequency = models.MaintenanceMonthly
+	}
+	if _, ok := maintenanceBillingMonths[frequency]; !ok {
+		return nil, fmt.Errorf("billing_frequency must be monthly, quarterly, half_yearly or annual")
+	}
+	if existing, err := s.GetPlan(tenantID, req.ProjectID); err == nil && !existing.IsActive {
+		return nil, fmt.Errorf("maintenance for this project has been handed over to the association")
+	}
+
+	now := time.Now()
+	plan := &models.MaintenancePlan{
+		ID:                 uuid.New().String(),
+		TenantID:           tenantID,
+		ProjectID:          req.ProjectID,
+		CAMRatePerSqft:     req.CAMRatePerSqft,
+		SinkingFundPerSqft: req.SinkingFundPerSqft,
+		DepositPerSqft:     req.DepositPerSqft,
+		BillingFrequency:   frequency,
+		GSTRate:            models.DefaultMaintenanceGSTRate,
+		GSTExemptLimit:     models.DefaultMaintenanceGSTExempt,
+		DueDays:            req.DueDays,
+		IsActive:           true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if req.GSTRate != nil {
+		plan.GSTRate = *req.GSTRate
+	}
+	if req.GSTExemptLimit != nil {
+		plan.GSTExemptLimit = *req.GSTExemptLimit
+	}
+	if plan.GSTRate < 0 || plan.GSTExemptLimit < 0 {
+		return nil, fmt.Errorf("gst_rate and gst_exempt_limit cannot be negative")
+	}
+	if plan.DueDays <= 0 {
+		plan.DueDays = models.DefaultMaintenanceDueDays
+	}
+
+	if _, err := s.DB.Exec(`INSERT INTO maintenance_plans
+		(id, tenant_id, project_id, cam_rate_per_sqft, sinking_fund_per_sqft, deposit_per_sqft, billing_frequency,
+		 gst_rate, gst_exempt_limit, due_days, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE cam_rate_per_sqft = VALUES(cam_rate_per_sqft),
+			sinking_fund_per_sqft = VALUES(sinking_fund_per_sqft), deposit_per_sqft = VALUES(deposit_per_sqft),
+			billing_frequency = VALUES(billing_frequency), gst_rate = VALUES(gst_rate),
+			gst_exempt_limit = VALUES(gst_exempt_limit), due_days = VALUES(due_days), updated_at = VALUES(updated_at)`,
+		plan.ID, tenantID, plan.ProjectID, plan.CAMRatePerSqft, plan.SinkingFundPerSqft, plan.DepositPerSqft,
+		plan.BillingFrequency, plan.GSTRate, plan.GSTExemptLimit, plan.DueDays, plan.IsActive,
+		plan.CreatedAt, plan.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to set maintenance plan: %w", err)
+	}
+	return s.GetPlan(tenantID, req.ProjectID)
+}
+
+// GetPlan returns a project's maintenance tariff
+func (s *MaintenanceService) GetPlan(tenantID, projectID string) (*models.MaintenancePlan, error) {
+	var p models.MaintenancePlan
+	err := s.DB.QueryRow(`SELECT id, tenant_id, project_id, cam_rate_per_sqft, sinking_fund_per_sqft, deposit_per_sqft,
+		billing_frequency, gst_rate, gst_exempt_limit, due_days, is_active, created_at, updated_at
+		FROM maintenance_plans WHERE tenant_id = ? AND project_id = ?`, tenantID, projectID).Scan(
+		&p.ID, &p.TenantID, &p.ProjectID, &p.CAMRatePerSqft, &p.SinkingFundPerSqft, &p.DepositPerSqft,
+		&p.BillingFrequency, &p.GSTRate, &p.GSTExemptLimit, &p.DueDays, &p.IsActive, &p.CreatedAt, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("maintenance plan not found for project")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance plan: %w", err)
+	}
+	return &p, nil
+}
+
+func (s *MaintenanceService) activePlan(tenantID, projectID string) (*models.MaintenancePlan, error) {
+	plan, err := s.GetPlan(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive {
+		return nil, fmt.Errorf("maintenance for this project has been handed over to the association")
+	}
+	return plan, nil
+}
+
+// ============================================================================
+// MAINTENANCE ACCOUNTS
+// ============================================================================
+
+// EnrollUnits opens maintenance accounts for every unit of the project whose
+// possession is completed and raises the maintenance deposit on each. Billing
+// starts from the possession date.
+func (s *MaintenanceService) EnrollUnits(tenantID, userID, projectID string) ([]models.MaintenanceAccount, error) {
+	plan, err := s.activePlan(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT b.id, b.unit_id, COALESCE(b.customer_id, ''), COALESCE(u.unit_number, ''),
+		COALESCE(u.sbua, 0), ps.possession_date
+		FROM possession_statuses ps
+		JOIN customer_bookings b ON b.id = ps.booking_id AND b.tenant_id = ps.tenant_id AND b.deleted_at IS NULL
+		JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN maintenance_accounts ma ON ma.tenant_id = b.tenant_id AND ma.unit_id = b.unit_id
+		WHERE ps.tenant_id = ? AND ps.status = 'completed' AND ps.possession_date IS NOT NULL
+			AND ps.deleted_at IS NULL AND u.project_id = ? AND ma.id IS NULL
+		ORDER BY u.unit_number`, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch possessed units: %w", err)
+	}
+	now := time.Now()
+	accounts := []models.MaintenanceAccount{}
+	for rows.Next() {
+		a := models.MaintenanceAccount{
+			ID:        uuid.New().String(),
+			TenantID:  tenantID,
+			ProjectID: projectID,
+			Status:    models.MaintenanceAccountActive,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := rows.Scan(&a.BookingID, &a.UnitID, &a.CustomerID, &a.UnitNumber, &a.AreaSqft, &a.BillingStartDate); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan possessed unit: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var invoices []models.MaintenanceInvoice
+	for i := range accounts {
+		a := &accounts[i]
+		if a.AreaSqft <= 0 {
+			return nil, fmt.Errorf("unit %s has no super built-up area to bill on", a.UnitNumber)
+		}
+		if _, err := tx.Exec(`INSERT INTO maintenance_accounts
+			(id, tenant_id, project_id, unit_id, booking_id, customer_id, area_sqft, billing_start_date, status,
+			 created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			a.ID, tenantID, projectID, a.UnitID, a.BookingID, nullIfEmpty(a.CustomerID), a.AreaSqft,
+			a.BillingStartDate, a.Status, a.CreatedAt, a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to open maintenance account for unit %s: %w", a.UnitNumber, err)
+		}
+
+		deposit := roundTo2(a.AreaSqft * plan.DepositPerSqft)
+		if deposit <= 0 {
+			continue
+		}
+		inv := models.MaintenanceInvoice{
+			InvoiceType:   models.MaintenanceInvoiceDeposit,
+			InvoiceDate:   now,
+			DepositAmount: deposit,
+			DueDate:       now.AddDate(0, 0, plan.DueDays),
+		}
+		if err := raiseMaintenanceInvoice(tx, tenantID, userID, a, &inv); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, inv)
+	}
+
+	if err := s.postInvoices(tx, tenantID, userID, invoices); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit enrolment: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetAccount returns a unit's maintenance account with its invoices and receipts
+func (s *MaintenanceService) GetAccount(tenantID, accountID string) (*models.MaintenanceAccount, error) {
+	return s.getAccount(tenantID, "ma.id = ?", accountID)
+}
+
+// GetBookingAccount returns the maintenance account of a booking's unit
+func (s *MaintenanceService) GetBookingAccount(tenantID, bookingID string) (*models.MaintenanceAccount, error) {
+	return s.getAccount(tenantID, "ma.booking_id = ?", bookingID)
+}
+
+// ListAccounts lists maintenance accounts of a project, optionally by status
+func (s *MaintenanceService) ListAccounts(tenantID, projectID, status string) ([]models.MaintenanceAccount, error) {
+	where, args := "1 = 1", []interface{}{}
+	if projectID != "" {
+		where += " AND ma.project_id = ?"
+		args = append(args, projectID)
+	}
+	if status != "" {
+		where += " AND ma.status = ?"
+		args = append(args, status)
+	}
+	return s.getAccounts(tenantID, where, args...)
+}
+
+func (s *MaintenanceService) getAccount(tenantID, where string, args ...interface{}) (*models.MaintenanceAccount, error) {
+	list, err := s.getAccounts(tenantID, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("maintenance account not found")
+	}
+	a := &list[0]
+
+	if a.Invoices, err = s.getInvoices(tenantID, "mi.account_id = ?", a.ID); err != nil {
+		return nil, err
+	}
+	if a.Receipts, err = s.getReceipts(tenantID, a.ID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *MaintenanceService) getAccounts(tenantID, where string, args ...interface{}) ([]models.MaintenanceAccount, error) {
+	rows, err := s.DB.Query(`SELECT ma.id, ma.tenant_id, ma.project_id, ma.unit_id, COALESCE(u.unit_number, ''),
+		ma.booking_id, COALESCE(ma.customer_id, ''), ma.area_sqft, ma.billing_start_date, ma.billed_up_to, ma.status,
+		COALESCE((SELECT SUM(total_amount) FROM maintenance_invoices WHERE tenant_id = ma.tenant_id AND account_id = ma.id), 0),
+		COALESCE((SELECT SUM(amount) FROM maintenance_receipts WHERE tenant_id = ma.tenant_id AND account_id = ma.id), 0),
+		ma.created_at, ma.updated_at
+		FROM maintenance_accounts ma
+		LEFT JOIN property_units u ON u.id = ma.unit_id
+		WHERE ma.tenant_id = ? AND `+where+` ORDER BY u.unit_number`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance accounts: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.MaintenanceAccount{}
+	for rows.Next() {
+		var a models.MaintenanceAccount
+		var billedUpTo sql.NullTime
+		if err := rows.Scan(&a.ID, &a.TenantID, &a.ProjectID, &a.UnitID, &a.UnitNumber, &a.BookingID, &a.CustomerID,
+			&a.AreaSqft, &a.BillingStartDate, &billedUpTo, &a.Status, &a.TotalBilled, &a.TotalReceived,
+			&a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance account: %w", err)
+		}
+		if billedUpTo.Valid {
+			a.BilledUpTo = &billedUpTo.Time
+		}
+		a.Outstanding = roundTo2(a.TotalBilled - a.TotalReceived)
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// ============================================================================
+// MAINTENANCE INVOICES
+// ============================================================================
+
+// GenerateInvoices bills every active account of the project for each period
+// starting on or before AsOf that has not been billed yet
+func (s *MaintenanceService) GenerateInvoices(tenantID, userID string, req *models.GenerateMaintenanceInvoicesRequest) ([]models.MaintenanceInvoice, error) {
+	plan, err := s.activePlan(tenantID, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	asOf := now
+	if req.AsOf != nil {
+		asOf = *req.AsOf
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, unit_id, booking_id, COALESCE(customer_id, ''), area_sqft, billing_start_date, billed_up_to
+		FROM maintenance_accounts WHERE tenant_id = ? AND project_id = ? AND status = ? FOR UPDATE`,
+		tenantID, req.ProjectID, models.MaintenanceAccountActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance accounts: %w", err)
+	}
+	var accounts []models.MaintenanceAccount
+	for rows.Next() {
+		a := models.MaintenanceAccount{TenantID: tenantID, ProjectID: req.ProjectID}
+		var billedUpTo sql.NullTime
+		if err := rows.Scan(&a.ID, &a.UnitID, &a.BookingID, &a.CustomerID, &a.AreaSqft, &a.BillingStartDate, &billedUpTo); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan maintenance account: %w", err)
+		}
+		if billedUpTo.Valid {
+			a.BilledUpTo = &billedUpTo.Time
+		}
+		accounts = append(accounts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	months := maintenanceBillingMonths[plan.BillingFrequency]
+	invoices := []models.MaintenanceInvoice{}
+	for i := range accounts {
+		a := &accounts[i]
+		periods := maintenanceBillingPeriods(a.BillingStartDate, a.BilledUpTo, months, asOf)
+		if len(periods) == 0 {
+			continue
+		}
+		for _, p := range periods {
+			from, to := p.From, p.To
+			inv := maintenancePeriodInvoice(plan, a.AreaSqft, p)
+			inv.InvoiceDate = now
+			inv.PeriodFrom = &from
+			inv.PeriodTo = &to
+			inv.DueDate = laterOf(now, from).AddDate(0, 0, plan.DueDays)
+			if err := raiseMaintenanceInvoice(tx, tenantID, userID, a, &inv); err != nil {
+				return nil, err
+			}
+			invoices = append(invoices, inv)
+		}
+		if _, err := tx.Exec(`UPDATE maintenance_accounts SET billed_up_to = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+			periods[len(periods)-1].To, now, a.ID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update billed period: %w", err)
+		}
+	}
+
+	if err := s.postInvoices(tx, tenantID, userID, invoices); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit maintenance invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// ListInvoices lists maintenance invoices of a project or account, optionally by status
+func (s *MaintenanceService) ListInvoices(tenantID, projectID, accountID, status string) ([]models.MaintenanceInvoice, error) {
+	where, args := "1 = 1", []interface{}{}
+	if projectID != "" {
+		where += " AND mi.project_id = ?"
+		args = append(args, projectID)
+	}
+	if accountID != "" {
+		where += " AND mi.account_id = ?"
+		args = append(args, accountID)
+	}
+	if status != "" {
+		where += " AND mi.status = ?"
+		args = append(args, status)
+	}
+	return s.getInvoices(tenantID, where, args...)
+}
+
+func (s *MaintenanceService) getInvoices(tenantID, where string, args ...interface{}) ([]models.MaintenanceInvoice, error) {
+	rows, err := s.DB.Query(`SELECT mi.id, mi.tenant_id, mi.invoice_number, mi.account_id, mi.project_id, mi.unit_id,
+		mi.booking_id, mi.invoice_type, mi.invoice_date, mi.period_from, mi.period_to, mi.cam_amount,
+		mi.sinking_fund_amount, mi.deposit_amount, mi.taxable_amount, mi.gst_rate, mi.gst_amount, mi.total_amount,
+		mi.amount_paid, mi.due_date, mi.status, mi.journal_entry_id, mi.created_by, mi.created_at
+		FROM maintenance_invoices mi
+		WHERE mi.tenant_id = ? AND `+where+` ORDER BY mi.due_date, mi.created_at`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance invoices: %w", err)
+	}
+	defer rows.Close()
+	return scanMaintenanceInvoices(rows)
+}
+
+func scanMaintenanceInvoices(rows *sql.Rows) ([]models.MaintenanceInvoice, error) {
+	list := []models.MaintenanceInvoice{}
+	for rows.Next() {
+		var inv models.MaintenanceInvoice
+		var periodFrom, periodTo sql.NullTime
+		var journalID, createdBy sql.NullString
+		if err := rows.Scan(&inv.ID, &inv.TenantID, &inv.InvoiceNumber, &inv.AccountID, &inv.ProjectID, &inv.UnitID,
+			&inv.BookingID, &inv.InvoiceType, &inv.InvoiceDate, &periodFrom, &periodTo, &inv.CAMAmount,
+			&inv.SinkingFundAmount, &inv.DepositAmount, &inv.TaxableAmount, &inv.GSTRate, &inv.GSTAmount, &inv.TotalAmount,
+			&inv.AmountPaid, &inv.DueDate, &inv.Status, &journalID, &createdBy, &inv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance invoice: %w", err)
+		}
+		if periodFrom.Valid {
+			inv.PeriodFrom = &periodFrom.Time
+		}
+		if periodTo.Valid {
+			inv.PeriodTo = &periodTo.Time
+		}
+		inv.JournalEntryID = nullStringPtr(journalID)
+		inv.CreatedBy = nullStringPtr(createdBy)
+		inv.Balance = roundTo2(inv.TotalAmount - inv.AmountPaid)
+		list = append(list, inv)
+	}
+	return list, rows.Err()
+}
+
+// raiseMaintenanceInvoice saves an invoice for the account and debits the booking's customer ledger.
+// The charge amounts, dates and type must be set; totals are derived here.
+func raiseMaintenanceInvoice(tx *sql.Tx, tenantID, userID string, a *models.MaintenanceAccount, inv *models.MaintenanceInvoice) error {
+	inv.ID = uuid.New().String()
+	inv.TenantID = tenantID
+	inv.InvoiceNumber = interestNoteNumber("MNT")
+	inv.AccountID = a.ID
+	inv.ProjectID = a.ProjectID
+	inv.UnitID = a.UnitID
+	inv.BookingID = a.BookingID
+	inv.TotalAmount = roundTo2(inv.CAMAmount + inv.SinkingFundAmount + inv.DepositAmount + inv.GSTAmount)
+	inv.Balance = inv.TotalAmount
+	inv.Status = models.MaintenanceInvoiceUnpaid
+	inv.CreatedBy = optionalString(userID)
+	inv.CreatedAt = time.Now()
+	entryID := fmt.Sprintf("JE-MNT-INV-%s", inv.ID)
+	inv.JournalEntryID = &entryID
+
+	if _, err := tx.Exec(`INSERT INTO maintenance_invoices
+		(id, tenant_id, invoice_number, account_id, project_id, unit_id, booking_id, invoice_type, invoice_date,
+		 period_from, period_to, cam_amount, sinking_fund_amount, deposit_amount, taxable_amount, gst_rate, gst_amount,
+		 total_amount, amount_paid, due_date, status, journal_entry_id, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)`,
+		inv.ID, tenantID, inv.InvoiceNumber, inv.AccountID, inv.ProjectID, inv.UnitID, inv.BookingID, inv.InvoiceType,
+		inv.InvoiceDate, inv.PeriodFrom, inv.PeriodTo, inv.CAMAmount, inv.SinkingFundAmount, inv.DepositAmount,
+		inv.TaxableAmount, inv.GSTRate, inv.GSTAmount, inv.TotalAmount, inv.DueDate, inv.Status, entryID,
+		inv.CreatedBy, inv.CreatedAt); err != nil {
+		return fmt.Errorf("failed to raise maintenance invoice: %w", err)
+	}
+
+	description := "Maintenance deposit"
+	if inv.InvoiceType == models.MaintenanceInvoicePeriodic {
+		description = fmt.Sprintf("Maintenance %s to %s", inv.PeriodFrom.Format("02-Jan-2006"),
+			inv.PeriodTo.AddDate(0, 0, -1).Format("02-Jan-2006"))
+	}
+	if _, err := insertCustomerLedgerEntry(tx, tenantID, a.BookingID, a.CustomerID, "debit",
+		fmt.Sprintf("%s - %s", description, inv.InvoiceNumber), inv.TotalAmount, inv.InvoiceNumber); err != nil {
+		return err
+	}
+	return nil
+}
+
+// postInvoices books raised invoices to receivables in the GL, in the transaction
+// that raises them
+func (s *MaintenanceService) postInvoices(tx *sql.Tx, tenantID, userID string, invoices []models.MaintenanceInvoice) error {
+	for i := range invoices {
+		inv := &invoices[i]
+		reference := inv.InvoiceNumber
+		entry := &models.JournalEntry{
+			ID:              *inv.JournalEntryID,
+			TenantID:        tenantID,
+			EntryDate:       inv.InvoiceDate,
+			ReferenceNumber: &reference,
+			ReferenceType:   "Maintenance_Invoice",
+			ReferenceID:     &inv.ID,
+			Description:     fmt.Sprintf("Maintenance %s invoice %s", inv.InvoiceType, inv.InvoiceNumber),
+			Amount:          inv.TotalAmount,
+			Narration:       fmt.Sprintf("Booking %s", inv.BookingID),
+			EntryStatus:     "Draft",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, maintenanceInvoiceLines(inv), userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// MAINTENANCE RECEIPTS & ARREARS
+// ============================================================================
+
+// RecordReceipt records a maintenance payment and applies it to the account's
+// open invoices, oldest due first
+func (s *MaintenanceService) RecordReceipt(tenantID, userID, accountID string, req *models.RecordMaintenanceReceiptRequest) (*models.MaintenanceReceipt, error) {
+	amount := roundTo2(req.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if req.PaymentMode == "" {
+		return nil, fmt.Errorf("payment_mode is required")
+	}
+	receiptDate := time.Now()
+	if req.ReceiptDate != nil {
+		receiptDate = *req.ReceiptDate
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var bookingID, customerID, status string
+	err = tx.QueryRow(`SELECT booking_id, COALESCE(customer_id, ''), status FROM maintenance_accounts
+		WHERE id = ? AND tenant_id = ? FOR UPDATE`, accountID, tenantID).Scan(&bookingID, &customerID, &status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("maintenance account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance account: %w", err)
+	}
+	if status != models.MaintenanceAccountActive {
+		return nil, fmt.Errorf("maintenance has been handed over; arrears are collected by the association")
+	}
+
+	rows, err := tx.Query(`SELECT mi.id, mi.tenant_id, mi.invoice_number, mi.account_id, mi.project_id, mi.unit_id,
+		mi.booking_id, mi.invoice_type, mi.invoice_date, mi.period_from, mi.period_to, mi.cam_amount,
+		mi.sinking_fund_amount, mi.deposit_amount, mi.taxable_amount, mi.gst_rate, mi.gst_amount, mi.total_amount,
+		mi.amount_paid, mi.due_date, mi.status, mi.journal_entry_id, mi.created_by, mi.created_at
+		FROM maintenance_invoices mi
+		WHERE mi.tenant_id = ? AND mi.account_id = ? AND mi.status <> ?
+		ORDER BY mi.due_date, mi.created_at FOR UPDATE`, tenantID, accountID, models.MaintenanceInvoicePaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open invoices: %w", err)
+	}
+	open, err := scanMaintenanceInvoices(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	outstanding := 0.0
+	for _, inv := range open {
+		outstanding += inv.Balance
+	}
+	if amount > roundTo2(outstanding)+0.005 {
+		return nil, fmt.Errorf("amount %.2f exceeds the outstanding maintenance of %.2f", amount, roundTo2(outstanding))
+	}
+
+	receipt := &models.MaintenanceReceipt{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		ReceiptNumber: interestNoteNumber("MRC"),
+		AccountID:     accountID,
+		BookingID:     bookingID,
+		ReceiptDate:   receiptDate,
+		Amount:        amount,
+		PaymentMode:   req.PaymentMode,
+		Reference:     req.Reference,
+		Allocations:   allocateMaintenanceReceipt(amount, open),
+		CreatedBy:     optionalString(userID),
+		CreatedAt:     time.Now(),
+	}
+	entryID := fmt.Sprintf("JE-MNT-RCPT-%s", receipt.ID)
+	receipt.JournalEntryID = &entryID
+
+	if _, err := tx.Exec(`INSERT INTO maintenance_receipts
+		(id, tenant_id, receipt_number, account_id, booking_id, receipt_date, amount, payment_mode, reference,
+		 journal_entry_id, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		receipt.ID, tenantID, receipt.ReceiptNumber, accountID, bookingID, receiptDate, amount, req.PaymentMode,
+		nullIfEmpty(req.Reference), entryID, receipt.CreatedBy, receipt.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record maintenance receipt: %w", err)
+	}
+	for _, alloc := range receipt.Allocations {
+		if _, err := tx.Exec(`INSERT INTO maintenance_receipt_allocations (id, tenant_id, receipt_id, invoice_id, amount)
+			VALUES (?, ?, ?, ?, ?)`, uuid.New().String(), tenantID, receipt.ID, alloc.InvoiceID, alloc.Amount); err != nil {
+			return nil, fmt.Errorf("failed to allocate maintenance receipt: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE maintenance_invoices SET amount_paid = amount_paid + ?,
+			status = IF(amount_paid >= total_amount - 0.005, ?, ?) WHERE id = ? AND tenant_id = ?`,
+			alloc.Amount, models.MaintenanceInvoicePaid, models.MaintenanceInvoicePartiallyPaid,
+			alloc.InvoiceID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update maintenance invoice: %w", err)
+		}
+	}
+	if _, err := insertCustomerLedgerEntry(tx, tenantID, bookingID, customerID, "credit",
+		fmt.Sprintf("Maintenance received - %s", receipt.ReceiptNumber), amount, receipt.ReceiptNumber); err != nil {
+		return nil, err
+	}
+
+	reference := receipt.ReceiptNumber
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       receiptDate,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Maintenance_Receipt",
+		ReferenceID:     &receipt.ID,
+		Description:     fmt.Sprintf("Maintenance receipt %s", receipt.ReceiptNumber),
+		Amount:          amount,
+		Narration:       fmt.Sprintf("%s %s", req.PaymentMode, req.Reference),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-BANK-CASH", DebitAmount: amount, Description: fmt.Sprintf("Maintenance received %s", req.Reference)},
+		{AccountID: "ACC-ACCOUNTS-RECEIVABLE", CreditAmount: amount, Description: "Maintenance receivable"},
+	}
+	if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit maintenance receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+func (s *MaintenanceService) getReceipts(tenantID, accountID string) ([]models.MaintenanceReceipt, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, receipt_number, account_id, booking_id, receipt_date, amount,
+		payment_mode, COALESCE(reference, ''), journal_entry_id, created_by, created_at
+		FROM maintenance_receipts WHERE tenant_id = ? AND account_id = ? ORDER BY receipt_date, created_at`,
+		tenantID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance receipts: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.MaintenanceReceipt{}
+	byID := map[string]int{}
+	for rows.Next() {
+		var r models.MaintenanceReceipt
+		var journalID, createdBy sql.NullString
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.ReceiptNumber, &r.AccountID, &r.BookingID, &r.ReceiptDate,
+			&r.Amount, &r.PaymentMode, &r.Reference, &journalID, &createdBy, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance receipt: %w", err)
+		}
+		r.JournalEntryID = nullStringPtr(journalID)
+		r.CreatedBy = nullStringPtr(createdBy)
+		byID[r.ID] = len(list)
+		list = append(list, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	allocs, err := s.DB.Query(`SELECT a.receipt_id, a.invoice_id, COALESCE(mi.invoice_number, ''), a.amount
+		FROM maintenance_receipt_allocations a
+		JOIN maintenance_receipts r ON r.id = a.receipt_id
+		LEFT JOIN maintenance_invoices mi ON mi.id = a.invoice_id
+		WHERE a.tenant_id = ? AND r.account_id = ?`, tenantID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipt allocations: %w", err)
+	}
+	defer allocs.Close()
+	for allocs.Next() {
+		var receiptID string
+		var a models.MaintenanceAllocation
+		if err := allocs.Scan(&receiptID, &a.InvoiceID, &a.InvoiceNumber, &a.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan receipt allocation: %w", err)
+		}
+		if i, ok := byID[receiptID]; ok {
+			list[i].Allocations = append(list[i].Allocations, a)
+		}
+	}
+	return list, allocs.Err()
+}
+
+// GetArrears returns each unit's overdue maintenance as of a date, with its ageing bucket
+func (s *MaintenanceService) GetArrears(tenantID, projectID string, asOf time.Time) ([]models.MaintenanceArrearsRow, error) {
+	invoices, err := s.getInvoices(tenantID, "mi.project_id = ? AND mi.status <> ?", projectID, models.MaintenanceInvoicePaid)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.unitNumbers(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return buildMaintenanceArrears(invoices, units, asOf, true), nil
+}
+
+// unitNumbers maps the project's maintenance account IDs to their unit numbers
+func (s *MaintenanceService) unitNumbers(tenantID, projectID string) (map[string]string, error) {
+	accounts, err := s.getAccounts(tenantID, "ma.project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	units := make(map[string]string, len(accounts))
+	for _, a := range accounts {
+		units[a.ID] = a.UnitNumber
+	}
+	return units, nil
+}
+
+// ============================================================================
+// ASSOCIATION HANDOVER
+// ============================================================================
+
+// GetClosingStatement returns the project's maintenance position as it would be handed over today
+func (s *MaintenanceService) GetClosingStatement(tenantID, projectID string) (*models.MaintenanceClosingStatement, error) {
+	invoices, err := s.getInvoices(tenantID, "mi.project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.unitNumbers(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	st := buildClosingStatement(projectID, invoices, units, time.Now())
+	return &st, nil
+}
+
+// HandOverToAssociation closes the project's maintenance accounts with a closing
+// statement and transfers the corpus (deposits and sinking fund collected) to the
+// association. Billing and receipts stop; the association collects the arrears.
+func (s *MaintenanceService) HandOverToAssociation(tenantID, userID, projectID string, req *models.AssociationHandoverRequest) (*models.AssociationHandover, error) {
+	if req.AssociationName == "" || req.BankAccount == "" || req.TransferReference == "" {
+		return nil, fmt.Errorf("association_name, bank_account and transfer_reference are required")
+	}
+	if _, err := s.activePlan(tenantID, projectID); err != nil {
+		return nil, err
+	}
+	statement, err := s.GetClosingStatement(tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	h := &models.AssociationHandover{
+		ID:                 uuid.New().String(),
+		TenantID:           tenantID,
+		HandoverNumber:     interestNoteNumber("AHO"),
+		ProjectID:          projectID,
+		AssociationName:    req.AssociationName,
+		RegistrationNumber: req.RegistrationNumber,
+		BankAccount:        req.BankAccount,
+		HandoverDate:       now,
+		CorpusTransferred:  statement.CorpusTransfer,
+		TransferReference:  req.TransferReference,
+		Statement:          *statement,
+		CreatedBy:          optionalString(userID),
+		CreatedAt:          now,
+	}
+	if req.HandoverDate != nil {
+		h.HandoverDate = *req.HandoverDate
+	}
+	if h.CorpusTransferred > 0 {
+		entryID := fmt.Sprintf("JE-MNT-HO-%s", h.ID)
+		h.JournalEntryID = &entryID
+	}
+	statementJSON, err := json.Marshal(h.Statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode closing statement: %w", err)
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE maintenance_plans SET is_active = FALSE, updated_at = ?
+		WHERE tenant_id = ? AND project_id = ? AND is_active = TRUE`, now, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close maintenance plan: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("maintenance for this project has already been handed over")
+	}
+	if _, err := tx.Exec(`UPDATE maintenance_accounts SET status = ?, updated_at = ?
+		WHERE tenant_id = ? AND project_id = ? AND status = ?`,
+		models.MaintenanceAccountHandedOver, now, tenantID, projectID, models.MaintenanceAccountActive); err != nil {
+		return nil, fmt.Errorf("failed to close maintenance accounts: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO association_handovers
+		(id, tenant_id, handover_number, project_id, association_name, registration_number, bank_account,
+		 handover_date, corpus_transferred, transfer_reference, statement, journal_entry_id, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		h.ID, tenantID, h.HandoverNumber, projectID, h.AssociationName, nullIfEmpty(h.RegistrationNumber),
+		h.BankAccount, h.HandoverDate, h.CorpusTransferred, h.TransferReference, statementJSON,
+		h.JournalEntryID, h.CreatedBy, h.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record association handover: %w", err)
+	}
+
+	if h.JournalEntryID != nil {
+		reference := req.TransferReference
+		entry := &models.JournalEntry{
+			ID:              *h.JournalEntryID,
+			TenantID:        tenantID,
+			EntryDate:       h.HandoverDate,
+			ReferenceNumber: &reference,
+			ReferenceType:   "Maintenance_Handover",
+			ReferenceID:     &h.ID,
+			Description:     fmt.Sprintf("Corpus transferred to %s", h.AssociationName),
+			Amount:          h.CorpusTransferred,
+			Narration:       fmt.Sprintf("Association handover %s", h.HandoverNumber),
+			EntryStatus:     "Draft",
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		var lines []models.JournalEntryDetail
+		if statement.DepositCollected > 0 {
+			lines = append(lines, models.JournalEntryDetail{AccountID: "ACC-MAINTENANCE-CORPUS",
+				DebitAmount: statement.DepositCollected, Description: "Maintenance deposits transferred"})
+		}
+		if statement.SinkingFundCollected > 0 {
+			lines = append(lines, models.JournalEntryDetail{AccountID: "ACC-SINKING-FUND",
+				DebitAmount: statement.SinkingFundCollected, Description: "Sinking fund transferred"})
+		}
+		lines = append(lines, models.JournalEntryDetail{AccountID: "ACC-BANK-CASH", CreditAmount: h.CorpusTransferred,
+			Description: fmt.Sprintf("Transfer to %s", h.BankAccount)})
+		if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit association handover: %w", err)
+	}
+	return h, nil
+}
+
+// GetHandover returns the project's association handover with its closing statement
+func (s *MaintenanceService) GetHandover(tenantID, projectID string) (*models.AssociationHandover, error) {
+	var h models.AssociationHandover
+	var registration sql.NullString
+	var journalID, createdBy sql.NullString
+	var statement []byte
+	err := s.DB.QueryRow(`SELECT id, tenant_id, handover_number, project_id, association_name, registration_number,
+		bank_account, handover_date, corpus_transferred, transfer_reference, statement, journal_entry_id,
+		created_by, created_at
+		FROM association_handovers WHERE tenant_id = ? AND project_id = ?`, tenantID, projectID).Scan(
+		&h.ID, &h.TenantID, &h.HandoverNumber, &h.ProjectID, &h.AssociationName, &registration,
+		&h.BankAccount, &h.HandoverDate, &h.CorpusTransferred, &h.TransferReference, &statement, &journalID,
+		&createdBy, &h.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("maintenance has not been handed over for this project")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get association handover: %w", err)
+	}
+	h.RegistrationNumber = registration.String
+	h.JournalEntryID = nullStringPtr(journalID)
+	h.CreatedBy = nullStringPtr(createdBy)
+	if err := json.Unmarshal(statement, &h.Statement); err != nil {
+		return nil, fmt.Errorf("failed to decode closing statement: %w", err)
+	}
+	return &h, nil
+}
+
+// ============================================================================
+// MAINTENANCE CALCULATIONS
+// ============================================================================
+
+// maintenanceBillingPeriods returns the unbilled periods starting on or before asOf.
+// A period that starts mid-month runs to the 1st of the next month and is billed
+// pro rata; later periods run whole months from the 1st.
+func maintenanceBillingPeriods(start time.Time, billedUpTo *time.Time, months int, asOf time.Time) []maintenancePeriod {
+	from := start
+	if billedUpTo != nil {
+		from = *billedUpTo
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	var periods []maintenancePeriod
+	for !from.After(asOf) {
+		p := maintenancePeriod{From: from}
+		if from.Day() == 1 {
+			p.To = from.AddDate(0, months, 0)
+			p.Months = float64(months)
+		} else {
+			p.To = time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, from.Location())
+			daysInMonth := p.To.AddDate(0, 0, -1).Day()
+			p.Months = float64(p.To.Sub(from).Hours()/24) / float64(daysInMonth)
+		}
+		periods = append(periods, p)
+		from = p.To
+	}
+	return periods
+}
+
+// maintenancePeriodInvoice prices one period for a unit. GST applies on the whole
+// contribution when its monthly equivalent exceeds the plan's exempt limit.
+func maintenancePeriodInvoice(plan *models.MaintenancePlan, areaSqft float64, p maintenancePeriod) models.MaintenanceInvoice {
+	inv := models.MaintenanceInvoice{
+		InvoiceType:       models.MaintenanceInvoicePeriodic,
+		CAMAmount:         roundTo2(areaSqft * plan.CAMRatePerSqft * p.Months),
+		SinkingFundAmount: roundTo2(areaSqft * plan.SinkingFundPerSqft * p.Months),
+	}
+	inv.TaxableAmount = roundTo2(inv.CAMAmount + inv.SinkingFundAmount)
+	monthly := areaSqft * (plan.CAMRatePerSqft + plan.SinkingFundPerSqft)
+	if monthly > plan.GSTExemptLimit {
+		inv.GSTRate = plan.GSTRate
+		inv.GSTAmount = roundTo2(inv.TaxableAmount * plan.GSTRate / 100)
+	}
+	return inv
+}
+
+// maintenanceInvoiceLines books an invoice: receivable against CAM income, the
+// sinking fund and corpus held for the association, and output GST
+func maintenanceInvoiceLines(inv *models.MaintenanceInvoice) []models.JournalEntryDetail {
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-ACCOUNTS-RECEIVABLE", DebitAmount: inv.TotalAmount, Description: fmt.Sprintf("Maintenance %s", inv.InvoiceNumber)},
+	}
+	credit := func(account string, amount float64, description string) {
+		if amount > 0 {
+			lines = append(lines, models.JournalEntryDetail{AccountID: account, CreditAmount: amount, Description: description})
+		}
+	}
+	credit("ACC-MAINTENANCE-INCOME", inv.CAMAmount, "Common area maintenance")
+	credit("ACC-SINKING-FUND", inv.SinkingFundAmount, "Sinking fund contribution")
+	credit("ACC-MAINTENANCE-CORPUS", inv.DepositAmount, "Maintenance deposit")
+	credit("ACC-OUTPUT-TAX", inv.GSTAmount, "GST on maintenance")
+	return lines
+}
+
+// allocateMaintenanceReceipt applies a receipt to open invoices in the order given
+func allocateMaintenanceReceipt(amount float64, open []models.MaintenanceInvoice) []models.MaintenanceAllocation {
+	var allocations []models.MaintenanceAllocation
+	remaining := roundTo2(amount)
+	for _, inv := range open {
+		if remaining <= 0 {
+			break
+		}
+		if inv.Balance <= 0 {
+			continue
+		}
+		applied := inv.Balance
+		if applied > remaining {
+			applied = remaining
+		}
+		applied = roundTo2(applied)
+		allocations = append(allocations, models.MaintenanceAllocation{InvoiceID: inv.ID, InvoiceNumber: inv.InvoiceNumber, Amount: applied})
+		remaining = roundTo2(remaining - applied)
+	}
+	return allocations
+}
+
+// buildMaintenanceArrears groups unpaid invoices by account; with overdueOnly
+// invoices not yet due are left out
+func buildMaintenanceArrears(invoices []models.MaintenanceInvoice, units map[string]string, asOf time.Time, overdueOnly bool) []models.MaintenanceArrearsRow {
+	var rows []models.MaintenanceArrearsRow
+	index := map[string]int{}
+	for _, inv := range invoices {
+		if inv.Balance <= 0.005 || (overdueOnly && !inv.DueDate.Before(asOf)) {
+			continue
+		}
+		i, ok := index[inv.AccountID]
+		if !ok {
+			i = len(rows)
+			index[inv.AccountID] = i
+			rows = append(rows, models.MaintenanceArrearsRow{
+				AccountID:     inv.AccountID,
+				UnitID:        inv.UnitID,
+				UnitNumber:    units[inv.AccountID],
+				BookingID:     inv.BookingID,
+				OldestDueDate: inv.DueDate,
+			})
+		}
+		row := &rows[i]
+		row.InvoiceCount++
+		row.Outstanding = roundTo2(row.Outstanding + inv.Balance)
+		if inv.DueDate.Before(row.OldestDueDate) {
+			row.OldestDueDate = inv.DueDate
+		}
+	}
+	for i := range rows {
+		rows[i].DaysOverdue = daysPastDue(rows[i].OldestDueDate, asOf)
+		if rows[i].DaysOverdue < 0 {
+			rows[i].DaysOverdue = 0
+		}
+		rows[i].Bucket = ageingBucket(rows[i].DaysOverdue)
+	}
+	return rows
+}
+
+// buildClosingStatement totals billing and collections by component. A part-paid
+// invoice's collection is spread over its components in proportion.
+func buildClosingStatement(projectID string, invoices []models.MaintenanceInvoice, units map[string]string, asOf time.Time) models.MaintenanceClosingStatement {
+	st := models.MaintenanceClosingStatement{ProjectID: projectID, AsOf: asOf, UnitsEnrolled: len(units)}
+	for _, inv := range invoices {
+		share := 0.0
+		if inv.TotalAmount > 0 {
+			share = inv.AmountPaid / inv.TotalAmount
+		}
+		st.DepositBilled += inv.DepositAmount
+		st.DepositCollected += inv.DepositAmount * share
+		st.SinkingFundBilled += inv.SinkingFundAmount
+		st.SinkingFundCollected += inv.SinkingFundAmount * share
+		st.CAMBilled += inv.CAMAmount
+		st.CAMCollected += inv.CAMAmount * share
+		st.GSTBilled += inv.GSTAmount
+		st.TotalBilled += inv.TotalAmount
+		st.TotalCollected += inv.AmountPaid
+	}
+	for _, v := range []*float64{&st.DepositBilled, &st.DepositCollected, &st.SinkingFundBilled, &st.SinkingFundCollected,
+		&st.CAMBilled, &st.CAMCollected, &st.GSTBilled, &st.TotalBilled, &st.TotalCollected} {
+		*v = roundTo2(*v)
+	}
+	st.Arrears = roundTo2(st.TotalBilled - st.TotalCollected)
+	st.CorpusTransfer = roundTo2(st.DepositCollected + st.SinkingFundCollected)
+	st.ArrearsByUnit = buildMaintenanceArrears(invoices, units, asOf, false)
+	if st.ArrearsByUnit == nil {
+		st.ArrearsByUnit = []models.MaintenanceArrearsRow{}
+	}
+	return st
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMaintenanceBillingPeriods tests the pro-rata first period and whole periods from the 1st
+func TestMaintenanceBillingPeriods(t *testing.T) {
+	possession := time.Date(2026, 6, 16, 0, 0, 0, 0, time.UTC)
+	periods := maintenanceBillingPeriods(possession, nil, 3, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, periods, 3)
+	assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), periods[0].To)
+	assert.InDelta(t, 0.5, periods[0].Months, 0.0001) // 15 of 30 days
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), periods[1].To)
+	assert.Equal(t, 3.0, periods[1].Months)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), periods[2].From)
+
+	billed := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Empty(t, maintenanceBillingPeriods(possession, &billed, 3, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Len(t, maintenanceBillingPeriods(possession, &billed, 1, billed), 1)
+}
+
+// TestMaintenancePeriodInvoice tests CAM, sinking fund and the GST exempt limit
+func TestMaintenancePeriodInvoice(t *testing.T) {
+	plan := &models.MaintenancePlan{CAMRatePerSqft: 3, SinkingFundPerSqft: 0.5, GSTRate: 18, GSTExemptLimit: 7500}
+
+	small := maintenancePeriodInvoice(plan, 1200, maintenancePeriod{Months: 3})
+	assert.Equal(t, 10800.0, small.CAMAmount)
+	assert.Equal(t, 1800.0, small.SinkingFundAmount)
+	assert.Equal(t, 12600.0, small.TaxableAmount)
+	assert.Equal(t, 0.0, small.GSTAmount) // 4,200 a month is under the limit
+
+	large := maintenancePeriodInvoice(plan, 2500, maintenancePeriod{Months: 1})
+	assert.Equal(t, 8750.0, large.TaxableAmount)
+	assert.Equal(t, 18.0, large.GSTRate)
+	assert.Equal(t, 1575.0, large.GSTAmount)
+
+	large.InvoiceNumber = "MNT-1"
+	large.TotalAmount = large.TaxableAmount + large.GSTAmount
+	debit, credit := 0.0, 0.0
+	for _, l := range maintenanceInvoiceLines(&large) {
+		debit += l.DebitAmount
+		credit += l.CreditAmount
+	}
+	assert.Equal(t, debit, credit)
+	assert.Len(t, maintenanceInvoiceLines(&large), 4)
+}
+
+// TestAllocateMaintenanceReceipt tests oldest-first allocation of receipts
+func TestAllocateMaintenanceReceipt(t *testing.T) {
+	open := []models.MaintenanceInvoice{
+		{ID: "dep", Balance: 60000},
+		{ID: "q1", Balance: 12600},
+		{ID: "q2", Balance: 12600},
+	}
+	allocs := allocateMaintenanceReceipt(70000, open)
+	assert.Len(t, allocs, 2)
+	assert.Equal(t, 60000.0, allocs[0].Amount)
+	assert.Equal(t, "q1", allocs[1].InvoiceID)
+	assert.Equal(t, 10000.0, allocs[1].Amount)
+
+	assert.Len(t, allocateMaintenanceReceipt(85200, open), 3)
+}
+
+// TestBuildClosingStatement tests collections by component, arrears and the corpus transfer
+func TestBuildClosingStatement(t *testing.T) {
+	asOf := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	invoices := []models.MaintenanceInvoice{
+		{AccountID: "a1", DepositAmount: 60000, TotalAmount: 60000, AmountPaid: 60000, DueDate: asOf.AddDate(0, -6, 0)},
+		{AccountID: "a1", CAMAmount: 10000, SinkingFundAmount: 2000, TotalAmount: 12000, AmountPaid: 6000, Balance: 6000, DueDate: asOf.AddDate(0, 0, -45)},
+		{AccountID: "a2", CAMAmount: 10000, SinkingFundAmount: 2000, TotalAmount: 12000, Balance: 12000, DueDate: asOf.AddDate(0, 0, 10)},
+	}
+	st := buildClosingStatement("p1", invoices, map[string]string{"a1": "A-101", "a2": "A-102"}, asOf)
+	assert.Equal(t, 2, st.UnitsEnrolled)
+	assert.Equal(t, 60000.0, st.DepositCollected)
+	assert.Equal(t, 4000.0, st.SinkingFundBilled)
+	assert.Equal(t, 1000.0, st.SinkingFundCollected)
+	assert.Equal(t, 5000.0, st.CAMCollected)
+	assert.Equal(t, 18000.0, st.Arrears)
+	assert.Equal(t, 61000.0, st.CorpusTransfer)
+	assert.Len(t, st.ArrearsByUnit, 2)
+	assert.Equal(t, "A-101", st.ArrearsByUnit[0].UnitNumber)
+	assert.Equal(t, 45, st.ArrearsByUnit[0].DaysOverdue)
+
+	overdue := buildMaintenanceArrears(invoices, nil, asOf, true)
+	assert.Len(t, overdue, 1)
+	assert.Equal(t, 6000.0, overdue[0].Outstanding)
+}
@@ -9,30 +9,32 @@ import (
 	"time"
 
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // PartnerLeadService handles partner lead submissions and referrals
 type PartnerLeadService interface {
 	// Lead Submission
 	SubmitPartnerLead(ctx context.Context, tenantID string, lead *models.PartnerLead) (*models.PartnerLead, error)
-	GetPartnerLead(ctx context.Context, tenantID string, leadID int64) (*models.PartnerLead, error)
+	GetPartnerLead(ctx context.Context, tenantID string, leadID string) (*models.PartnerLead, error)
 	GetPartnerLeads(ctx context.Context, tenantID string, filter *models.PartnerLeadFilter) ([]models.PartnerLead, int64, error)
-	UpdatePartnerLeadStatus(ctx context.Context, tenantID string, leadID int64, status string, notes string) error
+	UpdatePartnerLeadStatus(ctx context.Context, tenantID string, leadID string, status string, notes string) error
 
 	// Lead Review & Approval
-	ApprovePartnerLead(ctx context.Context, tenantID string, leadID int64, approvedBy int64, actualLeadID int64) error
-	RejectPartnerLead(ctx context.Context, tenantID string, leadID int64, rejectionReason string, rejectedBy int64) error
+	ApprovePartnerLead(ctx context.Context, tenantID string, leadID string, approvedBy string, actualLeadID string) error
+	RejectPartnerLead(ctx context.Context, tenantID string, leadID string, rejectionReason string, rejectedBy string) error
 	GetPendingLeadsForReview(ctx context.Context, tenantID string, limit int, offset int) ([]models.PartnerLead, error)
 
 	// Lead Credit Management
-	GetLeadCredits(ctx context.Context, tenantID string, partnerID int64) ([]models.PartnerLeadCredit, error)
+	GetLeadCredits(ctx context.Context, tenantID string, partnerID string) ([]models.PartnerLeadCredit, error)
 	SubmitLeadCreditApprovalRequest(ctx context.Context, tenantID string, credit *models.PartnerLeadCredit) (*models.PartnerLeadCredit, error)
-	ApproveLeadCredit(ctx context.Context, tenantID string, creditID int64, approvedBy int64) error
-	RejectLeadCredit(ctx context.Context, tenantID string, creditID int64, rejectionReason string) error
+	ApproveLeadCredit(ctx context.Context, tenantID string, creditID string, approvedBy string) error
+	RejectLeadCredit(ctx context.Context, tenantID string, creditID string, rejectionReason string) error
 
 	// Activity Tracking
 	LogPartnerActivity(ctx context.Context, tenantID string, activity *models.PartnerActivity) error
-	GetPartnerActivity(ctx context.Context, tenantID string, partnerID int64, limit int, offset int) ([]models.PartnerActivity, error)
+	GetPartnerActivity(ctx context.Context, tenantID string, partnerID string, limit int, offset int) ([]models.PartnerActivity, error)
 }
 
 type partnerLeadService struct {
@@ -50,10 +52,11 @@ func NewPartnerLeadService(db *sql.DB, partnerService PartnerService) PartnerLea
 
 // SubmitPartnerLead submits a lead from a partner
 func (s *partnerLeadService) SubmitPartnerLead(ctx context.Context, tenantID string, lead *models.PartnerLead) (*models.PartnerLead, error) {
-	if lead.PartnerID == 0 || lead.SubmittedBy == 0 {
+	if lead.PartnerID == "" || lead.SubmittedBy == "" {
 		return nil, errors.New("partner_id and submitted_by are required")
 	}
 
+	lead.ID = uuid.New().String()
 	lead.TenantID = tenantID
 	lead.Status = "submitted"
 	lead.CreatedAt = time.Now()
@@ -65,13 +68,13 @@ func (s *partnerLeadService) SubmitPartnerLead(ctx context.Context, tenantID str
 
 	query := `
 		INSERT INTO partner_leads (
-			tenant_id, partner_id, submission_type, status, lead_data, quality_score,
+			id, tenant_id, partner_id, submission_type, status, lead_data, quality_score,
 			submitted_by, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		lead.TenantID, lead.PartnerID, lead.SubmissionType, lead.Status, lead.LeadData, lead.QualityScore,
+	_, err := s.db.ExecContext(ctx, query,
+		lead.ID, lead.TenantID, lead.PartnerID, lead.SubmissionType, lead.Status, lead.LeadData, lead.QualityScore,
 		lead.SubmittedBy, lead.CreatedAt, lead.UpdatedAt,
 	)
 
@@ -79,20 +82,13 @@ func (s *partnerLeadService) SubmitPartnerLead(ctx context.Context, tenantID str
 		return nil, fmt.Errorf("failed to submit partner lead: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get lead ID: %w", err)
-	}
-
-	lead.ID = id
-
 	// Log activity
 	s.LogPartnerActivity(ctx, tenantID, &models.PartnerActivity{
 		PartnerID:  lead.PartnerID,
 		UserID:     &lead.SubmittedBy,
 		Action:     "lead_submitted",
 		Resource:   "lead",
-		ResourceID: id,
+		ResourceID: lead.ID,
 		TenantID:   tenantID,
 		CreatedAt:  time.Now(),
 	})
@@ -101,7 +97,7 @@ func (s *partnerLeadService) SubmitPartnerLead(ctx context.Context, tenantID str
 }
 
 // GetPartnerLead retrieves a partner lead
-func (s *partnerLeadService) GetPartnerLead(ctx context.Context, tenantID string, leadID int64) (*models.PartnerLead, error) {
+func (s *partnerLeadService) GetPartnerLead(ctx context.Context, tenantID string, leadID string) (*models.PartnerLead, error) {
 	lead := &models.PartnerLead{}
 
 	query := `
@@ -153,7 +149,7 @@ func (s *partnerLeadService) GetPartnerLeads(ctx context.Context, tenantID strin
 
 	args := []interface{}{tenantID}
 
-	if filter.PartnerID != 0 {
+	if filter.PartnerID != "" {
 		query += " AND partner_id = ?"
 		args = append(args, filter.PartnerID)
 	}
@@ -175,7 +171,7 @@ func (s *partnerLeadService) GetPartnerLeads(ctx context.Context, tenantID strin
 	}
 
 	// Get count
-	countQuery := strings.Replace(query, "SELECT id, tenant_id, partner_id, lead_id, submission_type, status, lead_data, quality_score, rejection_reason, reviewed_by, reviewed_at, submitted_by, conversion_date, credit_amount, credit_status, created_at, updated_at", "SELECT COUNT(*)", 1)
+	countQuery := "SELECT COUNT(*) FROM partner_leads" + query[strings.Index(query, "\n\t\tWHERE"):]
 	var total int64
 	s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 
@@ -206,7 +202,7 @@ func (s *partnerLeadService) GetPartnerLeads(ctx context.Context, tenantID strin
 }
 
 // UpdatePartnerLeadStatus updates a partner lead status
-func (s *partnerLeadService) UpdatePartnerLeadStatus(ctx context.Context, tenantID string, leadID int64, status string, notes string) error {
+func (s *partnerLeadService) UpdatePartnerLeadStatus(ctx context.Context, tenantID string, leadID string, status string, notes string) error {
 	query := `
 		UPDATE partner_leads SET status = ?, updated_at = ? WHERE id = ? AND tenant_id = ?
 	`
@@ -216,7 +212,7 @@ func (s *partnerLeadService) UpdatePartnerLeadStatus(ctx context.Context, tenant
 }
 
 // ApprovePartnerLead approves a partner lead
-func (s *partnerLeadService) ApprovePartnerLead(ctx context.Context, tenantID string, leadID int64, approvedBy int64, actualLeadID int64) error {
+func (s *partnerLeadService) ApprovePartnerLead(ctx context.Context, tenantID string, leadID string, approvedBy string, actualLeadID string) error {
 	now := time.Now()
 	query := `
 		UPDATE partner_leads SET
@@ -245,7 +241,7 @@ func (s *partnerLeadService) ApprovePartnerLead(ctx context.Context, tenantID st
 }
 
 // RejectPartnerLead rejects a partner lead
-func (s *partnerLeadService) RejectPartnerLead(ctx context.Context, tenantID string, leadID int64, rejectionReason string, rejectedBy int64) error {
+func (s *partnerLeadService) RejectPartnerLead(ctx context.Context, tenantID string, leadID string, rejectionReason string, rejectedBy string) error {
 	now := time.Now()
 	query := `
 		UPDATE partner_leads SET
@@ -315,7 +311,7 @@ func (s *partnerLeadService) GetPendingLeadsForReview(ctx context.Context, tenan
 }
 
 // GetLeadCredits retrieves pending lead credits for a partner
-func (s *partnerLeadService) GetLeadCredits(ctx context.Context, tenantID string, partnerID int64) ([]models.PartnerLeadCredit, error) {
+func (s *partnerLeadService) GetLeadCredits(ctx context.Context, tenantID string, partnerID string) ([]models.PartnerLeadCredit, error) {
 	credits := []models.PartnerLeadCredit{}
 
 	query := `
@@ -351,10 +347,11 @@ func (s *partnerLeadService) GetLeadCredits(ctx context.Context, tenantID string
 
 // SubmitLeadCreditApprovalRequest submits a lead credit for approval
 func (s *partnerLeadService) SubmitLeadCreditApprovalRequest(ctx context.Context, tenantID string, credit *models.PartnerLeadCredit) (*models.PartnerLeadCredit, error) {
-	if credit.PartnerLeadID == 0 || credit.PartnerID == 0 {
+	if credit.PartnerLeadID == "" || credit.PartnerID == "" {
 		return nil, errors.New("partner_lead_id and partner_id are required")
 	}
 
+	credit.ID = uuid.New().String()
 	credit.TenantID = tenantID
 	credit.Status = "pending_approval"
 	credit.CreatedAt = time.Now()
@@ -362,31 +359,24 @@ func (s *partnerLeadService) SubmitLeadCreditApprovalRequest(ctx context.Context
 
 	query := `
 		INSERT INTO partner_lead_credits (
-			tenant_id, partner_lead_id, partner_id, credit_amount, calculation_type,
+			id, tenant_id, partner_lead_id, partner_id, credit_amount, calculation_type,
 			status, notes, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		credit.TenantID, credit.PartnerLeadID, credit.PartnerID, credit.CreditAmount, credit.CalculationType,
+	_, err := s.db.ExecContext(ctx, query,
+		credit.ID, credit.TenantID, credit.PartnerLeadID, credit.PartnerID, credit.CreditAmount, credit.CalculationType,
 		credit.Status, credit.Notes, credit.CreatedAt, credit.UpdatedAt,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit lead credit: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get credit ID: %w", err)
-	}
-
-	credit.ID = id
 	return credit, nil
 }
 
 // ApproveLeadCredit approves a lead credit request
-func (s *partnerLeadService) ApproveLeadCredit(ctx context.Context, tenantID string, creditID int64, approvedBy int64) error {
+func (s *partnerLeadService) ApproveLeadCredit(ctx context.Context, tenantID string, creditID string, approvedBy string) error {
 	now := time.Now()
 	query := `
 		UPDATE partner_lead_credits SET
@@ -426,7 +416,7 @@ func (s *partnerLeadService) ApproveLeadCredit(ctx context.Context, tenantID str
 }
 
 // RejectLeadCredit rejects a lead credit request
-func (s *partnerLeadService) RejectLeadCredit(ctx context.Context, tenantID string, creditID int64, rejectionReason string) error {
+func (s *partnerLeadService) RejectLeadCredit(ctx context.Context, tenantID string, creditID string, rejectionReason string) error {
 	query := `
 		UPDATE partner_lead_credits SET
 			status = 'rejected', rejection_reason = ?, updated_at = ?
@@ -443,17 +433,18 @@ func (s *partnerLeadService) RejectLeadCredit(ctx context.Context, tenantID stri
 
 // LogPartnerActivity logs a partner activity
 func (s *partnerLeadService) LogPartnerActivity(ctx context.Context, tenantID string, activity *models.PartnerActivity) error {
+	activity.ID = uuid.New().String()
 	activity.TenantID = tenantID
 	activity.CreatedAt = time.Now()
 
 	query := `
 		INSERT INTO partner_activities (
-			tenant_id, partner_id, user_id, action, resource, resource_id, details, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, tenant_id, partner_id, user_id, action, resource, resource_id, details, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := s.db.ExecContext(ctx, query,
-		activity.TenantID, activity.PartnerID, activity.UserID, activity.Action,
+		activity.ID, activity.TenantID, activity.PartnerID, activity.UserID, activity.Action,
 		activity.Resource, activity.ResourceID, activity.Details, activity.CreatedAt,
 	)
 
@@ -461,7 +452,7 @@ func (s *partnerLeadService) LogPartnerActivity(ctx context.Context, tenantID st
 }
 
 // GetPartnerActivity retrieves partner activities
-func (s *partnerLeadService) GetPartnerActivity(ctx context.Context, tenantID string, partnerID int64, limit int, offset int) ([]models.PartnerActivity, error) {
+func (s *partnerLeadService) GetPartnerActivity(ctx context.Context, tenantID string, partnerID string, limit int, offset int) ([]models.PartnerActivity, error) {
 	if limit == 0 {
 		limit = 50
 	}
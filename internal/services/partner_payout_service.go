@@ -8,31 +8,33 @@ import (
 	"time"
 
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // PartnerPayoutService handles partner payout approvals and management
 type PartnerPayoutService interface {
 	// Payout Generation
-	GeneratePayoutPeriod(ctx context.Context, tenantID string, partnerID int64, periodStart time.Time, periodEnd time.Time) (*models.PartnerPayout, error)
+	GeneratePayoutPeriod(ctx context.Context, tenantID string, partnerID string, periodStart time.Time, periodEnd time.Time) (*models.PartnerPayout, error)
 	CreatePayout(ctx context.Context, tenantID string, payout *models.PartnerPayout) (*models.PartnerPayout, error)
-	GetPayout(ctx context.Context, tenantID string, payoutID int64) (*models.PartnerPayout, error)
-	GetPayouts(ctx context.Context, tenantID string, partnerID int64, limit int, offset int) ([]models.PartnerPayout, error)
+	GetPayout(ctx context.Context, tenantID string, payoutID string) (*models.PartnerPayout, error)
+	GetPayouts(ctx context.Context, tenantID string, partnerID string, limit int, offset int) ([]models.PartnerPayout, error)
 	GetPendingPayouts(ctx context.Context, tenantID string, limit int, offset int) ([]models.PartnerPayout, error)
 
 	// Payout Review & Approval
-	ApprovePayout(ctx context.Context, tenantID string, payoutID int64, approvedAmount float64, approvedBy int64) error
-	RejectPayout(ctx context.Context, tenantID string, payoutID int64, rejectionNotes string) error
-	PartiallyApprovePayout(ctx context.Context, tenantID string, payoutID int64, approvedAmount float64, approvedBy int64) error
+	ApprovePayout(ctx context.Context, tenantID string, payoutID string, approvedAmount float64, approvedBy string) error
+	RejectPayout(ctx context.Context, tenantID string, payoutID string, rejectionNotes string) error
+	PartiallyApprovePayout(ctx context.Context, tenantID string, payoutID string, approvedAmount float64, approvedBy string) error
 
 	// Payout Details
-	GetPayoutDetails(ctx context.Context, tenantID string, payoutID int64, limit int, offset int) ([]models.PartnerPayoutDetail, error)
+	GetPayoutDetails(ctx context.Context, tenantID string, payoutID string, limit int, offset int) ([]models.PartnerPayoutDetail, error)
 	AddPayoutDetail(ctx context.Context, tenantID string, detail *models.PartnerPayoutDetail) (*models.PartnerPayoutDetail, error)
-	ApprovePayoutDetail(ctx context.Context, tenantID string, detailID int64) error
-	RejectPayoutDetail(ctx context.Context, tenantID string, detailID int64, notes string) error
+	ApprovePayoutDetail(ctx context.Context, tenantID string, detailID string) error
+	RejectPayoutDetail(ctx context.Context, tenantID string, detailID string, notes string) error
 
 	// Payout Processing
-	MarkPayoutAsPaid(ctx context.Context, tenantID string, payoutID int64, paymentDate time.Time, referenceNumber string) error
-	GetPayoutStats(ctx context.Context, tenantID string, partnerID int64) (*PayoutStats, error)
+	MarkPayoutAsPaid(ctx context.Context, tenantID string, payoutID string, paymentDate time.Time, referenceNumber string) error
+	GetPayoutStats(ctx context.Context, tenantID string, partnerID string) (*PayoutStats, error)
 }
 
 type partnerPayoutService struct {
@@ -64,7 +66,7 @@ func NewPartnerPayoutService(db *sql.DB, partnerService PartnerService, leadServ
 }
 
 // GeneratePayoutPeriod generates a payout for a period
-func (s *partnerPayoutService) GeneratePayoutPeriod(ctx context.Context, tenantID string, partnerID int64, periodStart time.Time, periodEnd time.Time) (*models.PartnerPayout, error) {
+func (s *partnerPayoutService) GeneratePayoutPeriod(ctx context.Context, tenantID string, partnerID string, periodStart time.Time, periodEnd time.Time) (*models.PartnerPayout, error) {
 	// Get all approved leads for the period
 	query := `
 		SELECT COUNT(*), SUM(credit_amount)
@@ -112,10 +114,11 @@ func (s *partnerPayoutService) GeneratePayoutPeriod(ctx context.Context, tenantI
 
 // CreatePayout creates a new payout record
 func (s *partnerPayoutService) CreatePayout(ctx context.Context, tenantID string, payout *models.PartnerPayout) (*models.PartnerPayout, error) {
-	if payout.PartnerID == 0 {
+	if payout.PartnerID == "" {
 		return nil, errors.New("partner_id is required")
 	}
 
+	payout.ID = uuid.New().String()
 	payout.TenantID = tenantID
 	payout.CreatedAt = time.Now()
 	payout.UpdatedAt = time.Now()
@@ -126,14 +129,14 @@ func (s *partnerPayoutService) CreatePayout(ctx context.Context, tenantID string
 
 	query := `
 		INSERT INTO partner_payouts (
-			tenant_id, partner_id, period_start, period_end, total_leads_count,
+			id, tenant_id, partner_id, period_start, period_end, total_leads_count,
 			approved_leads, converted_leads, total_amount, status,
 			notes, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		payout.TenantID, payout.PartnerID, payout.PeriodStart, payout.PeriodEnd,
+	_, err := s.db.ExecContext(ctx, query,
+		payout.ID, payout.TenantID, payout.PartnerID, payout.PeriodStart, payout.PeriodEnd,
 		payout.TotalLeadsCount, payout.ApprovedLeads, payout.ConvertedLeads, payout.TotalAmount,
 		payout.Status, payout.Notes, payout.CreatedAt, payout.UpdatedAt,
 	)
@@ -141,24 +144,17 @@ func (s *partnerPayoutService) CreatePayout(ctx context.Context, tenantID string
 	if err != nil {
 		return nil, fmt.Errorf("failed to create payout: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get payout ID: %w", err)
-	}
-
-	payout.ID = id
 	return payout, nil
 }
 
 // GetPayout retrieves a payout
-func (s *partnerPayoutService) GetPayout(ctx context.Context, tenantID string, payoutID int64) (*models.PartnerPayout, error) {
+func (s *partnerPayoutService) GetPayout(ctx context.Context, tenantID string, payoutID string) (*models.PartnerPayout, error) {
 	payout := &models.PartnerPayout{}
 
 	query := `
 		SELECT id, tenant_id, partner_id, period_start, period_end, total_leads_count,
 		approved_leads, converted_leads, total_amount, approved_amount, rejected_amount,
-		status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
+		tds_amount, net_amount, status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
 		approved_at, rejection_notes, notes, created_at, updated_at
 		FROM partner_payouts
 		WHERE id = ? AND tenant_id = ?
@@ -167,7 +163,7 @@ func (s *partnerPayoutService) GetPayout(ctx context.Context, tenantID string, p
 	err := s.db.QueryRowContext(ctx, query, payoutID, tenantID).Scan(
 		&payout.ID, &payout.TenantID, &payout.PartnerID, &payout.PeriodStart, &payout.PeriodEnd, &payout.TotalLeadsCount,
 		&payout.ApprovedLeads, &payout.ConvertedLeads, &payout.TotalAmount, &payout.ApprovedAmount, &payout.RejectedAmount,
-		&payout.Status, &payout.PaymentMethod, &payout.PaymentDate, &payout.ReferenceNumber, &payout.ReviewedBy, &payout.ApprovedBy,
+		&payout.TDSAmount, &payout.NetAmount, &payout.Status, &payout.PaymentMethod, &payout.PaymentDate, &payout.ReferenceNumber, &payout.ReviewedBy, &payout.ApprovedBy,
 		&payout.ApprovedAt, &payout.RejectionNotes, &payout.Notes, &payout.CreatedAt, &payout.UpdatedAt,
 	)
 
@@ -182,7 +178,7 @@ func (s *partnerPayoutService) GetPayout(ctx context.Context, tenantID string, p
 }
 
 // GetPayouts retrieves payouts for a partner
-func (s *partnerPayoutService) GetPayouts(ctx context.Context, tenantID string, partnerID int64, limit int, offset int) ([]models.PartnerPayout, error) {
+func (s *partnerPayoutService) GetPayouts(ctx context.Context, tenantID string, partnerID string, limit int, offset int) ([]models.PartnerPayout, error) {
 	if limit == 0 {
 		limit = 50
 	}
@@ -195,7 +191,7 @@ func (s *partnerPayoutService) GetPayouts(ctx context.Context, tenantID string,
 	query := `
 		SELECT id, tenant_id, partner_id, period_start, period_end, total_leads_count,
 		approved_leads, converted_leads, total_amount, approved_amount, rejected_amount,
-		status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
+		tds_amount, net_amount, status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
 		approved_at, rejection_notes, notes, created_at, updated_at
 		FROM partner_payouts
 		WHERE tenant_id = ? AND partner_id = ?
@@ -214,7 +210,7 @@ func (s *partnerPayoutService) GetPayouts(ctx context.Context, tenantID string,
 		err := rows.Scan(
 			&p.ID, &p.TenantID, &p.PartnerID, &p.PeriodStart, &p.PeriodEnd, &p.TotalLeadsCount,
 			&p.ApprovedLeads, &p.ConvertedLeads, &p.TotalAmount, &p.ApprovedAmount, &p.RejectedAmount,
-			&p.Status, &p.PaymentMethod, &p.PaymentDate, &p.ReferenceNumber, &p.ReviewedBy, &p.ApprovedBy,
+			&p.TDSAmount, &p.NetAmount, &p.Status, &p.PaymentMethod, &p.PaymentDate, &p.ReferenceNumber, &p.ReviewedBy, &p.ApprovedBy,
 			&p.ApprovedAt, &p.RejectionNotes, &p.Notes, &p.CreatedAt, &p.UpdatedAt,
 		)
 		if err != nil {
@@ -240,7 +236,7 @@ func (s *partnerPayoutService) GetPendingPayouts(ctx context.Context, tenantID s
 	query := `
 		SELECT id, tenant_id, partner_id, period_start, period_end, total_leads_count,
 		approved_leads, converted_leads, total_amount, approved_amount, rejected_amount,
-		status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
+		tds_amount, net_amount, status, payment_method, payment_date, reference_number, reviewed_by, approved_by,
 		approved_at, rejection_notes, notes, created_at, updated_at
 		FROM partner_payouts
 		WHERE tenant_id = ? AND status = 'pending'
@@ -259,7 +255,7 @@ func (s *partnerPayoutService) GetPendingPayouts(ctx context.Context, tenantID s
 		err := rows.Scan(
 			&p.ID, &p.TenantID, &p.PartnerID, &p.PeriodStart, &p.PeriodEnd, &p.TotalLeadsCount,
 			&p.ApprovedLeads, &p.ConvertedLeads, &p.TotalAmount, &p.ApprovedAmount, &p.RejectedAmount,
-			&p.Status, &p.PaymentMethod, &p.PaymentDate, &p.ReferenceNumber, &p.ReviewedBy, &p.ApprovedBy,
+			&p.TDSAmount, &p.NetAmount, &p.Status, &p.PaymentMethod, &p.PaymentDate, &p.ReferenceNumber, &p.ReviewedBy, &p.ApprovedBy,
 			&p.ApprovedAt, &p.RejectionNotes, &p.Notes, &p.CreatedAt, &p.UpdatedAt,
 		)
 		if err != nil {
@@ -272,7 +268,7 @@ func (s *partnerPayoutService) GetPendingPayouts(ctx context.Context, tenantID s
 }
 
 // ApprovePayout approves a payout
-func (s *partnerPayoutService) ApprovePayout(ctx context.Context, tenantID string, payoutID int64, approvedAmount float64, approvedBy int64) error {
+func (s *partnerPayoutService) ApprovePayout(ctx context.Context, tenantID string, payoutID string, approvedAmount float64, approvedBy string) error {
 	now := time.Now()
 	query := `
 		UPDATE partner_payouts SET
@@ -302,7 +298,7 @@ func (s *partnerPayoutService) ApprovePayout(ctx context.Context, tenantID strin
 }
 
 // RejectPayout rejects a payout
-func (s *partnerPayoutService) RejectPayout(ctx context.Context, tenantID string, payoutID int64, rejectionNotes string) error {
+func (s *partnerPayoutService) RejectPayout(ctx context.Context, tenantID string, payoutID string, rejectionNotes string) error {
 	query := `
 		UPDATE partner_payouts SET
 			status = 'rejected', rejection_notes = ?, updated_at = ?
@@ -318,7 +314,7 @@ func (s *partnerPayoutService) RejectPayout(ctx context.Context, tenantID string
 }
 
 // PartiallyApprovePayout partially approves a payout
-func (s *partnerPayoutService) PartiallyApprovePayout(ctx context.Context, tenantID string, payoutID int64, approvedAmount float64, approvedBy int64) error {
+func (s *partnerPayoutService) PartiallyApprovePayout(ctx context.Context, tenantID string, payoutID string, approvedAmount float64, approvedBy string) error {
 	now := time.Now()
 	query := `
 		UPDATE partner_payouts SET
@@ -337,7 +333,7 @@ func (s *partnerPayoutService) PartiallyApprovePayout(ctx context.Context, tenan
 }
 
 // GetPayoutDetails retrieves payout line items
-func (s *partnerPayoutService) GetPayoutDetails(ctx context.Context, tenantID string, payoutID int64, limit int, offset int) ([]models.PartnerPayoutDetail, error) {
+func (s *partnerPayoutService) GetPayoutDetails(ctx context.Context, tenantID string, payoutID string, limit int, offset int) ([]models.PartnerPayoutDetail, error) {
 	if limit == 0 {
 		limit = 50
 	}
@@ -374,63 +370,101 @@ func (s *partnerPayoutService) GetPayoutDetails(ctx context.Context, tenantID st
 
 // AddPayoutDetail adds a line item to a payout
 func (s *partnerPayoutService) AddPayoutDetail(ctx context.Context, tenantID string, detail *models.PartnerPayoutDetail) (*models.PartnerPayoutDetail, error) {
+	detail.ID = uuid.New().String()
 	detail.CreatedAt = time.Now()
 
 	query := `
 		INSERT INTO partner_payout_details (
-			payout_id, partner_lead_id, lead_submission_id, amount, status, created_at
-		) VALUES (?, ?, ?, ?, ?, ?)
+			id, payout_id, partner_lead_id, lead_submission_id, amount, status, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		detail.PayoutID, detail.PartnerLeadID, detail.LeadSubmissionID, detail.Amount, detail.Status, detail.CreatedAt,
+	_, err := s.db.ExecContext(ctx, query,
+		detail.ID, detail.PayoutID, detail.PartnerLeadID, detail.LeadSubmissionID, detail.Amount, detail.Status, detail.CreatedAt,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to add payout detail: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get detail ID: %w", err)
-	}
-
-	detail.ID = id
 	return detail, nil
 }
 
 // ApprovePayoutDetail approves a payout line item
-func (s *partnerPayoutService) ApprovePayoutDetail(ctx context.Context, tenantID string, detailID int64) error {
+func (s *partnerPayoutService) ApprovePayoutDetail(ctx context.Context, tenantID string, detailID string) error {
 	query := `UPDATE partner_payout_details SET status = 'approved' WHERE id = ?`
 	_, err := s.db.ExecContext(ctx, query, detailID)
 	return err
 }
 
 // RejectPayoutDetail rejects a payout line item
-func (s *partnerPayoutService) RejectPayoutDetail(ctx context.Context, tenantID string, detailID int64, notes string) error {
+func (s *partnerPayoutService) RejectPayoutDetail(ctx context.Context, tenantID string, detailID string, notes string) error {
 	query := `UPDATE partner_payout_details SET status = 'rejected', approval_notes = ? WHERE id = ?`
 	_, err := s.db.ExecContext(ctx, query, notes, detailID)
 	return err
 }
 
-// MarkPayoutAsPaid marks a payout as paid
-func (s *partnerPayoutService) MarkPayoutAsPaid(ctx context.Context, tenantID string, payoutID int64, paymentDate time.Time, referenceNumber string) error {
+// MarkPayoutAsPaid marks a payout as paid, deducting TDS under section 194H on the
+// approved commission. The TDS and net amount paid are saved with the status.
+func (s *partnerPayoutService) MarkPayoutAsPaid(ctx context.Context, tenantID string, payoutID string, paymentDate time.Time, referenceNumber string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var partnerID, partnerName string
+	var pan sql.NullString
+	var amount float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT pp.partner_id, p.organization_name, p.tax_id, COALESCE(NULLIF(pp.approved_amount, 0), pp.total_amount)
+		FROM partner_payouts pp
+		JOIN partners p ON p.id = pp.partner_id
+		WHERE pp.id = ? AND pp.tenant_id = ?
+	`, payoutID, tenantID).Scan(&partnerID, &partnerName, &pan, &amount)
+	if err == sql.ErrNoRows {
+		return errors.New("payout not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get payout: %w", err)
+	}
+
+	tds := 0.0
+	if amount > 0 {
+		entry, err := NewTDSService(s.db).DeductTx(tx, tenantID, &models.TDSDeductionInput{
+			SourceType:      models.TDSSourcePartnerPayout,
+			SourceID:        payoutID,
+			PayeeType:       models.TDSPayeePartner,
+			PayeeID:         partnerID,
+			PayeeName:       partnerName,
+			PAN:             pan.String,
+			Section:         models.TDSSection194H,
+			Amount:          amount,
+			TransactionDate: paymentDate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to deduct tds: %w", err)
+		}
+		tds = entry.TDSAmount
+	}
+
 	query := `
 		UPDATE partner_payouts SET
-			status = 'paid', payment_date = ?, reference_number = ?, updated_at = ?
+			status = 'paid', payment_date = ?, reference_number = ?, tds_amount = ?, net_amount = ?, updated_at = ?
 		WHERE id = ? AND tenant_id = ?
 	`
 
-	_, err := s.db.ExecContext(ctx, query,
-		paymentDate, referenceNumber, time.Now(),
+	if _, err := tx.ExecContext(ctx, query,
+		paymentDate, referenceNumber, tds, roundTo2(amount-tds), time.Now(),
 		payoutID, tenantID,
-	)
+	); err != nil {
+		return fmt.Errorf("failed to mark payout paid: %w", err)
+	}
 
-	return err
+	return tx.Commit()
 }
 
 // GetPayoutStats retrieves payout statistics
-func (s *partnerPayoutService) GetPayoutStats(ctx context.Context, tenantID string, partnerID int64) (*PayoutStats, error) {
+func (s *partnerPayoutService) GetPayoutStats(ctx context.Context, tenantID string, partnerID string) (*PayoutStats, error) {
 	stats := &PayoutStats{}
 
 	query := `
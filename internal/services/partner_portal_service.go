@@ -0,0 +1,653 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+	"vyomtech-backend/pkg/auth"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ============================================================================
+// CHANNEL PARTNER PORTAL SERVICE
+// ============================================================================
+// Partner users sign in with partner tokens issued by a JWT manager that uses
+// its own secret, so a partner token is never accepted on staff endpoints.
+// Every method takes the partner from the token and only reads or writes that
+// partner's leads, payouts and invoices.
+
+const (
+	partnerMaxFailedLogins = 5
+	partnerLockoutDuration = 15 * time.Minute
+)
+
+// ErrPartnerInvalidCredentials is returned for an unknown email or a wrong password
+var ErrPartnerInvalidCredentials = errors.New("invalid email or password")
+
+type PartnerPortalService struct {
+	DB        *sql.DB
+	JWT       *auth.JWTManager
+	Partners  PartnerService
+	Leads     PartnerLeadService
+	Payouts   PartnerPayoutService
+	Documents *DocumentService
+}
+
+func NewPartnerPortalService(db *sql.DB, jwt *auth.JWTManager, partners PartnerService, leads PartnerLeadService, payouts PartnerPayoutService, documents *DocumentService) *PartnerPortalService {
+	return &PartnerPortalService{DB: db, JWT: jwt, Partners: partners, Leads: leads, Payouts: payouts, Documents: documents}
+}
+
+// ============================================================================
+// AUTHENTICATION
+// ============================================================================
+
+// Login checks a partner user's password under their partner code and issues a
+// partner token. Repeated failures lock the user out for a while.
+func (s *PartnerPortalService) Login(ctx context.Context, req *models.PartnerLoginRequest) (*models.PartnerLoginResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	code := strings.TrimSpace(req.PartnerCode)
+	if code == "" || email == "" || req.Password == "" {
+		return nil, ErrPartnerInvalidCredentials
+	}
+
+	var userID, partnerID, tenantID, hash, role, firstName, lastName string
+	var partnerCode, partnerName, partnerStatus string
+	var active bool
+	var failed int
+	var lockedUntil sql.NullTime
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT u.id, u.partner_id, u.tenant_id, u.password_hash, u.role, u.first_name, u.last_name,
+		u.is_active, COALESCE(u.failed_login_attempts, 0), u.locked_until,
+		p.partner_code, p.organization_name, p.status
+		FROM partner_users u
+		JOIN partners p ON p.id = u.partner_id AND p.tenant_id = u.tenant_id
+		WHERE u.email = ? AND p.partner_code = ? AND u.deleted_at IS NULL AND p.deleted_at IS NULL
+	`, email, code).Scan(&userID, &partnerID, &tenantID, &hash, &role, &firstName, &lastName,
+		&active, &failed, &lockedUntil, &partnerCode, &partnerName, &partnerStatus)
+	if err == sql.ErrNoRows {
+		return nil, ErrPartnerInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner user: %w", err)
+	}
+
+	now := time.Now()
+	// A locked account answers like a wrong password, so the lock does not confirm the email
+	if lockedUntil.Valid && lockedUntil.Time.After(now) {
+		return nil, ErrPartnerInvalidCredentials
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)) != nil {
+		attempts, lockUntil := partnerLoginFailure(failed, now)
+		if _, err := s.DB.ExecContext(ctx, `UPDATE partner_users SET failed_login_attempts = ?, locked_until = ? WHERE id = ?`,
+			attempts, lockUntil, userID); err != nil {
+			return nil, fmt.Errorf("failed to record failed sign-in: %w", err)
+		}
+		return nil, ErrPartnerInvalidCredentials
+	}
+
+	if !active || partnerStatus != string(models.PartnerStatusActive) {
+		return nil, errors.New("partner account is not active")
+	}
+
+	scopes := partnerScopes(role)
+	token, expiresAt, err := s.JWT.GeneratePartnerToken(&auth.PartnerClaims{
+		PartnerUserID: userID,
+		PartnerID:     partnerID,
+		TenantID:      tenantID,
+		Role:          role,
+		Scopes:        scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if _, err := s.DB.ExecContext(ctx, `UPDATE partner_users SET failed_login_attempts = 0, locked_until = NULL, last_login = ? WHERE id = ?`,
+		now, userID); err != nil {
+		return nil, fmt.Errorf("failed to record sign-in: %w", err)
+	}
+	s.Leads.LogPartnerActivity(ctx, tenantID, &models.PartnerActivity{
+		PartnerID:  partnerID,
+		UserID:     &userID,
+		Action:     "portal_login",
+		Resource:   "partner_user",
+		ResourceID: userID,
+	})
+
+	return &models.PartnerLoginResponse{
+		Token:       token,
+		ExpiresAt:   expiresAt,
+		UserID:      userID,
+		Name:        strings.TrimSpace(firstName + " " + lastName),
+		Role:        role,
+		Scopes:      scopes,
+		PartnerID:   partnerID,
+		PartnerCode: partnerCode,
+		PartnerName: partnerName,
+	}, nil
+}
+
+// Authenticate validates a partner token and checks the user and partner are still
+// active, so suspending a partner cuts off its portal before the token expires
+func (s *PartnerPortalService) Authenticate(ctx context.Context, token string) (*auth.PartnerClaims, error) {
+	claims, err := s.JWT.ValidatePartnerToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var active bool
+	var partnerStatus string
+	err = s.DB.QueryRowContext(ctx, `SELECT u.is_active, p.status
+		FROM partner_users u
+		JOIN partners p ON p.id = u.partner_id AND p.tenant_id = u.tenant_id
+		WHERE u.id = ? AND u.partner_id = ? AND u.tenant_id = ? AND u.deleted_at IS NULL AND p.deleted_at IS NULL`,
+		claims.PartnerUserID, claims.PartnerID, claims.TenantID).Scan(&active, &partnerStatus)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("partner user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner user: %w", err)
+	}
+	if !active || partnerStatus != string(models.PartnerStatusActive) {
+		return nil, errors.New("partner account is not active")
+	}
+
+	return claims, nil
+}
+
+// partnerLoginFailure counts a failed sign-in, locking the user out and
+// resetting the count once the limit is reached
+func partnerLoginFailure(failed int, now time.Time) (int, *time.Time) {
+	failed++
+	if failed < partnerMaxFailedLogins {
+		return failed, nil
+	}
+	until := now.Add(partnerLockoutDuration)
+	return 0, &until
+}
+
+// partnerScopes returns the portal scopes a partner user's role grants
+func partnerScopes(role string) []string {
+	switch role {
+	case "admin", "manager", "lead_manager":
+		return []string{models.PartnerScopeLeadsRead, models.PartnerScopeLeadsWrite, models.PartnerScopePayoutsRead, models.PartnerScopeInvoicesWrite}
+	case "user":
+		return []string{models.PartnerScopeLeadsRead, models.PartnerScopeLeadsWrite}
+	case "viewer":
+		return []string{models.PartnerScopeLeadsRead}
+	}
+	return []string{}
+}
+
+// GetProfile returns the signed-in partner with its lead and payout figures
+func (s *PartnerPortalService) GetProfile(ctx context.Context, tenantID, partnerID, userID string, scopes []string) (*models.PartnerPortalProfile, error) {
+	partner, err := s.portalPartner(ctx, tenantID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.Partners.GetPartnerStats(ctx, tenantID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PartnerPortalProfile{
+		PartnerID:   partner.ID,
+		PartnerCode: partner.PartnerCode,
+		PartnerName: partner.OrganizationName,
+		UserID:      userID,
+		Scopes:      scopes,
+		Stats:       stats,
+	}, nil
+}
+
+// portalPartner loads the partner fields the portal shows and checks against
+func (s *PartnerPortalService) portalPartner(ctx context.Context, tenantID, partnerID string) (*models.Partner, error) {
+	partner := &models.Partner{ID: partnerID, TenantID: tenantID}
+	var taxID sql.NullString
+	var quota sql.NullInt64
+	err := s.DB.QueryRowContext(ctx, `SELECT partner_code, organization_name, status, tax_id, monthly_quota
+		FROM partners WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`, partnerID, tenantID).Scan(
+		&partner.PartnerCode, &partner.OrganizationName, &partner.Status, &taxID, &quota)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("partner not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+	partner.TaxID = taxID.String
+	partner.MonthlyQuota = int(quota.Int64)
+	return partner, nil
+}
+
+// ============================================================================
+// LEADS
+// ============================================================================
+
+// SubmitLead submits a lead for the signed-in partner within its monthly quota
+func (s *PartnerPortalService) SubmitLead(ctx context.Context, tenantID, partnerID, userID string, req *models.SubmitPortalLeadRequest) (*models.PartnerLead, error) {
+	data := req.LeadData
+	if strings.TrimSpace(data.FirstName) == "" {
+		return nil, errors.New("lead_data.first_name is required")
+	}
+	if strings.TrimSpace(data.Phone) == "" && strings.TrimSpace(data.Email) == "" {
+		return nil, errors.New("lead_data.phone or lead_data.email is required")
+	}
+	submissionType := req.SubmissionType
+	if submissionType == "" {
+		submissionType = "new_lead"
+	}
+	if submissionType != "new_lead" && submissionType != "referral" {
+		return nil, errors.New("submission_type must be new_lead or referral")
+	}
+
+	partner, err := s.portalPartner(ctx, tenantID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if partner.MonthlyQuota > 0 {
+		now := time.Now()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		var submitted int
+		if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM partner_leads
+			WHERE tenant_id = ? AND partner_id = ? AND created_at >= ?`,
+			tenantID, partnerID, monthStart).Scan(&submitted); err != nil {
+			return nil, fmt.Errorf("failed to count partner leads: %w", err)
+		}
+		if submitted >= partner.MonthlyQuota {
+			return nil, fmt.Errorf("monthly lead quota of %d reached", partner.MonthlyQuota)
+		}
+	}
+
+	return s.Leads.SubmitPartnerLead(ctx, tenantID, &models.PartnerLead{
+		PartnerID:      partnerID,
+		SubmittedBy:    userID,
+		SubmissionType: submissionType,
+		LeadData:       data,
+	})
+}
+
+// ListLeads lists the partner's leads with the status of any bookings made on them
+func (s *PartnerPortalService) ListLeads(ctx context.Context, tenantID, partnerID, status string, limit, offset int) ([]models.PartnerPortalLead, int64, error) {
+	leads, total, err := s.Leads.GetPartnerLeads(ctx, tenantID, &models.PartnerLeadFilter{
+		PartnerID: partnerID,
+		Status:    status,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bookings, err := s.leadBookings(ctx, tenantID, leads)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]models.PartnerPortalLead, 0, len(leads))
+	for _, lead := range leads {
+		out = append(out, portalLead(lead, bookings))
+	}
+	return out, total, nil
+}
+
+// GetLead returns one of the partner's leads with its booking status
+func (s *PartnerPortalService) GetLead(ctx context.Context, tenantID, partnerID, leadID string) (*models.PartnerPortalLead, error) {
+	lead, err := s.Leads.GetPartnerLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.PartnerID != partnerID {
+		return nil, errors.New("lead not found")
+	}
+
+	bookings, err := s.leadBookings(ctx, tenantID, []models.PartnerLead{*lead})
+	if err != nil {
+		return nil, err
+	}
+	out := portalLead(*lead, bookings)
+	return &out, nil
+}
+
+// leadBookings loads bookings made on the sales leads the partner leads were approved into
+func (s *PartnerPortalService) leadBookings(ctx context.Context, tenantID string, leads []models.PartnerLead) (map[string][]models.PartnerPortalBooking, error) {
+	bookings := map[string][]models.PartnerPortalBooking{}
+	var ids []interface{}
+	for _, lead := range leads {
+		if lead.LeadID != nil && *lead.LeadID != "" {
+			ids = append(ids, *lead.LeadID)
+		}
+	}
+	if len(ids) == 0 {
+		return bookings, nil
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `SELECT lead_id, id, booking_reference, booking_status, booking_date, agreement_date
+		FROM customer_bookings
+		WHERE tenant_id = ? AND lead_id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)
+		ORDER BY booking_date`, append([]interface{}{tenantID}, ids...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead bookings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var leadID string
+		var b models.PartnerPortalBooking
+		if err := rows.Scan(&leadID, &b.BookingID, &b.BookingReference, &b.BookingStatus, &b.BookingDate, &b.AgreementDate); err != nil {
+			return nil, fmt.Errorf("failed to scan lead booking: %w", err)
+		}
+		bookings[leadID] = append(bookings[leadID], b)
+	}
+	return bookings, rows.Err()
+}
+
+// portalLead attaches a lead's bookings and works out the stage shown to the partner
+func portalLead(lead models.PartnerLead, bookings map[string][]models.PartnerPortalBooking) models.PartnerPortalLead {
+	out := models.PartnerPortalLead{PartnerLead: lead, Bookings: []models.PartnerPortalBooking{}}
+	if lead.LeadID != nil {
+		if b, ok := bookings[*lead.LeadID]; ok {
+			out.Bookings = b
+		}
+	}
+
+	switch lead.Status {
+	case "submitted":
+		out.Stage = models.PartnerLeadStageSubmitted
+	case "under_review":
+		out.Stage = models.PartnerLeadStageUnderReview
+	case "rejected":
+		out.Stage = models.PartnerLeadStageRejected
+	default:
+		out.Stage = models.PartnerLeadStageAccepted
+	}
+
+	// The furthest any live booking has got wins; cancelled shows only when every booking was
+	cancelled := 0
+	for _, b := range out.Bookings {
+		if b.BookingStatus == "cancelled" {
+			cancelled++
+			continue
+		}
+		if b.AgreementDate != nil {
+			out.Stage = models.PartnerLeadStageAgreementSigned
+		} else if out.Stage != models.PartnerLeadStageAgreementSigned {
+			out.Stage = models.PartnerLeadStageBooked
+		}
+	}
+	if len(out.Bookings) > 0 && cancelled == len(out.Bookings) {
+		out.Stage = models.PartnerLeadStageCancelled
+	}
+
+	// Partners see the outcome of a review, not who reviewed it
+	out.ReviewedBy = nil
+	return out
+}
+
+// ============================================================================
+// PAYOUTS AND STATEMENTS
+// ============================================================================
+
+// ListPayouts lists the partner's payouts
+func (s *PartnerPortalService) ListPayouts(ctx context.Context, tenantID, partnerID string, limit, offset int) ([]models.PartnerPayout, error) {
+	payouts, err := s.Payouts.GetPayouts(ctx, tenantID, partnerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range payouts {
+		hidePayoutReviewers(&payouts[i])
+	}
+	return payouts, nil
+}
+
+// GetPayoutStatement returns a payout's lead lines, TDS deducted and invoices raised against it
+func (s *PartnerPortalService) GetPayoutStatement(ctx context.Context, tenantID, partnerID, payoutID string) (*models.PartnerPayoutStatement, error) {
+	payout, err := s.partnerPayout(ctx, tenantID, partnerID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := s.portalPartner(ctx, tenantID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.Payouts.GetPayoutDetails(ctx, tenantID, payoutID, 1000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var tds float64
+	err = s.DB.QueryRowContext(ctx, `SELECT COALESCE(SUM(tds_amount), 0) FROM tds_ledger
+		WHERE tenant_id = ? AND source_type = ? AND source_id = ?`,
+		tenantID, models.TDSSourcePartnerPayout, payoutID).Scan(&tds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout tds: %w", err)
+	}
+
+	invoices, err := s.ListInvoices(ctx, tenantID, partnerID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &models.PartnerPayoutStatement{
+		PartnerID:   partner.ID,
+		PartnerCode: partner.PartnerCode,
+		PartnerName: partner.OrganizationName,
+		PAN:         partner.TaxID,
+		Payout:      *payout,
+		Lines:       lines,
+		Invoices:    invoices,
+	}
+	settlePayoutStatement(st, tds)
+	return st, nil
+}
+
+// settlePayoutStatement works out the gross payable, which is the approved amount once the
+// payout has been reviewed, and the net after TDS
+func settlePayoutStatement(st *models.PartnerPayoutStatement, tds float64) {
+	st.GrossAmount = st.Payout.TotalAmount
+	switch st.Payout.Status {
+	case "approved", "partially_approved", "paid":
+		if st.Payout.ApprovedAmount > 0 {
+			st.GrossAmount = st.Payout.ApprovedAmount
+		}
+	case "rejected":
+		st.GrossAmount = 0
+	}
+	st.TDSAmount = roundTo2(tds)
+	st.NetAmount = roundTo2(st.GrossAmount - st.TDSAmount)
+}
+
+// ExportPayoutStatementCSV renders a payout statement for download
+func (s *PartnerPortalService) ExportPayoutStatementCSV(st *models.PartnerPayoutStatement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	amount := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	records := [][]string{
+		{"Partner", st.PartnerName},
+		{"Partner Code", st.PartnerCode},
+		{"PAN", st.PAN},
+		{"Payout", st.Payout.ID},
+		{"Period", st.Payout.PeriodStart.Format("2006-01-02") + " to " + st.Payout.PeriodEnd.Format("2006-01-02")},
+		{"Status", st.Payout.Status},
+		{},
+		{"Partner Lead", "Amount", "Status", "Notes"},
+	}
+	for _, line := range st.Lines {
+		records = append(records, []string{line.PartnerLeadID, amount(line.Amount), line.Status, line.ApprovalNotes})
+	}
+	records = append(records,
+		[]string{},
+		[]string{"Gross Amount", amount(st.GrossAmount)},
+		[]string{"TDS (194H)", amount(st.TDSAmount)},
+		[]string{"Net Amount", amount(st.NetAmount)},
+	)
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write statement: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// partnerPayout loads a payout, treating another partner's payout as not found
+func (s *PartnerPortalService) partnerPayout(ctx context.Context, tenantID, partnerID, payoutID string) (*models.PartnerPayout, error) {
+	payout, err := s.Payouts.GetPayout(ctx, tenantID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if payout.PartnerID != partnerID {
+		return nil, errors.New("payout not found")
+	}
+	hidePayoutReviewers(payout)
+	return payout, nil
+}
+
+func hidePayoutReviewers(p *models.PartnerPayout) {
+	p.ReviewedBy = nil
+	p.ApprovedBy = nil
+}
+
+// ============================================================================
+// INVOICES
+// ============================================================================
+
+// UploadInvoice records an invoice the partner raised against one of its reviewed payouts.
+// The invoice file is uploaded as a document attached to the payout, which the invoice
+// refers to by id.
+func (s *PartnerPortalService) UploadInvoice(ctx context.Context, tenantID, partnerID, userID string, req *models.UploadPartnerInvoiceRequest) (*models.PartnerInvoice, error) {
+	if req.PayoutID == "" || strings.TrimSpace(req.InvoiceNumber) == "" || req.DocumentID == "" {
+		return nil, errors.New("payout_id, invoice_number and document_id are required")
+	}
+	if req.GSTAmount < 0 {
+		return nil, errors.New("gst_amount cannot be negative")
+	}
+
+	payout, err := s.partnerPayout(ctx, tenantID, partnerID, req.PayoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoiced float64
+	if err := s.DB.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM partner_invoices
+		WHERE tenant_id = ? AND payout_id = ? AND status != ?`,
+		tenantID, req.PayoutID, models.PartnerInvoiceRejected).Scan(&invoiced); err != nil {
+		return nil, fmt.Errorf("failed to get invoiced amount: %w", err)
+	}
+	if err := checkPartnerInvoice(payout, invoiced, req.Amount); err != nil {
+		return nil, err
+	}
+	if err := s.Documents.RequireAttachments(tenantID, models.PartnerInvoiceDocumentEntity, req.PayoutID, req.DocumentID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoiceDate := now
+	if req.InvoiceDate != nil {
+		invoiceDate = *req.InvoiceDate
+	}
+	inv := &models.PartnerInvoice{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		PartnerID:     partnerID,
+		PayoutID:      req.PayoutID,
+		InvoiceNumber: strings.TrimSpace(req.InvoiceNumber),
+		InvoiceDate:   invoiceDate,
+		Amount:        roundTo2(req.Amount),
+		GSTAmount:     roundTo2(req.GSTAmount),
+		TotalAmount:   roundTo2(req.Amount + req.GSTAmount),
+		DocumentID:    req.DocumentID,
+		Status:        models.PartnerInvoiceSubmitted,
+		UploadedBy:    userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	var exists int
+	if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM partner_invoices WHERE tenant_id = ? AND partner_id = ? AND invoice_number = ?`,
+		tenantID, partnerID, inv.InvoiceNumber).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check invoice number: %w", err)
+	}
+	if exists > 0 {
+		return nil, fmt.Errorf("invoice %s has already been uploaded", inv.InvoiceNumber)
+	}
+
+	if _, err := s.DB.ExecContext(ctx, `INSERT INTO partner_invoices (
+			id, tenant_id, partner_id, payout_id, invoice_number, invoice_date, amount, gst_amount,
+			total_amount, document_id, status, uploaded_by, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		inv.ID, inv.TenantID, inv.PartnerID, inv.PayoutID, inv.InvoiceNumber, inv.InvoiceDate, inv.Amount, inv.GSTAmount,
+		inv.TotalAmount, inv.DocumentID, inv.Status, inv.UploadedBy, inv.CreatedAt, inv.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to upload invoice: %w", err)
+	}
+
+	s.Leads.LogPartnerActivity(ctx, tenantID, &models.PartnerActivity{
+		PartnerID:  partnerID,
+		UserID:     &userID,
+		Action:     "invoice_uploaded",
+		Resource:   "invoice",
+		ResourceID: inv.ID,
+	})
+
+	return inv, nil
+}
+
+// checkPartnerInvoice allows invoices only on reviewed payouts and only up to the approved amount
+func checkPartnerInvoice(payout *models.PartnerPayout, invoiced, amount float64) error {
+	if amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	switch payout.Status {
+	case "approved", "partially_approved", "paid":
+	default:
+		return fmt.Errorf("cannot invoice a %s payout", payout.Status)
+	}
+
+	approved := payout.ApprovedAmount
+	if approved == 0 {
+		approved = payout.TotalAmount
+	}
+	if remaining := roundTo2(approved - invoiced); roundTo2(amount) > remaining {
+		return fmt.Errorf("amount exceeds the %.2f left to invoice on this payout", remaining)
+	}
+	return nil
+}
+
+// ListInvoices lists the partner's invoices, optionally for one payout
+func (s *PartnerPortalService) ListInvoices(ctx context.Context, tenantID, partnerID, payoutID string) ([]models.PartnerInvoice, error) {
+	query := `SELECT id, tenant_id, partner_id, payout_id, invoice_number, invoice_date, amount, gst_amount,
+		total_amount, COALESCE(document_id, ''), status, uploaded_by, created_at, updated_at
+		FROM partner_invoices WHERE tenant_id = ? AND partner_id = ?`
+	args := []interface{}{tenantID, partnerID}
+	if payoutID != "" {
+		query += " AND payout_id = ?"
+		args = append(args, payoutID)
+	}
+	query += " ORDER BY invoice_date DESC, created_at DESC"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []models.PartnerInvoice{}
+	for rows.Next() {
+		var inv models.PartnerInvoice
+		if err := rows.Scan(&inv.ID, &inv.TenantID, &inv.PartnerID, &inv.PayoutID, &inv.InvoiceNumber, &inv.InvoiceDate,
+			&inv.Amount, &inv.GSTAmount, &inv.TotalAmount, &inv.DocumentID, &inv.Status, &inv.UploadedBy,
+			&inv.CreatedAt, &inv.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, inv)
+	}
+	return invoices, rows.Err()
+}
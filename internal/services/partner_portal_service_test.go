@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPartnerScopesAndLockout tests role scopes and locking out after repeated failed sign-ins
+func TestPartnerScopesAndLockout(t *testing.T) {
+	assert.Contains(t, partnerScopes("admin"), models.PartnerScopeInvoicesWrite)
+	assert.Equal(t, []string{models.PartnerScopeLeadsRead, models.PartnerScopeLeadsWrite}, partnerScopes("user"))
+	assert.Equal(t, []string{models.PartnerScopeLeadsRead}, partnerScopes("viewer"))
+	assert.Empty(t, partnerScopes("unknown"))
+
+	now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	attempts, until := partnerLoginFailure(3, now)
+	assert.Equal(t, 4, attempts)
+	assert.Nil(t, until)
+
+	attempts, until = partnerLoginFailure(4, now)
+	assert.Equal(t, 0, attempts)
+	if assert.NotNil(t, until) {
+		assert.Equal(t, now.Add(15*time.Minute), *until)
+	}
+}
+
+// TestPortalLeadStage tests the pipeline stage a partner sees for a lead and its bookings
+func TestPortalLeadStage(t *testing.T) {
+	salesLead, reviewer := "sl1", "u1"
+	signed := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, models.PartnerLeadStageSubmitted, portalLead(models.PartnerLead{Status: "submitted"}, nil).Stage)
+	assert.Equal(t, models.PartnerLeadStageRejected, portalLead(models.PartnerLead{Status: "rejected"}, nil).Stage)
+
+	approved := models.PartnerLead{Status: "approved", LeadID: &salesLead, ReviewedBy: &reviewer}
+	out := portalLead(approved, map[string][]models.PartnerPortalBooking{})
+	assert.Equal(t, models.PartnerLeadStageAccepted, out.Stage)
+	assert.NotNil(t, out.Bookings)
+	assert.Nil(t, out.ReviewedBy)
+
+	bookings := map[string][]models.PartnerPortalBooking{"sl1": {
+		{BookingID: "b1", BookingStatus: "cancelled"},
+		{BookingID: "b2", BookingStatus: "active"},
+	}}
+	assert.Equal(t, models.PartnerLeadStageBooked, portalLead(approved, bookings).Stage)
+
+	bookings["sl1"][1].AgreementDate = &signed
+	assert.Equal(t, models.PartnerLeadStageAgreementSigned, portalLead(approved, bookings).Stage)
+
+	bookings["sl1"][1].BookingStatus = "cancelled"
+	out = portalLead(approved, bookings)
+	assert.Equal(t, models.PartnerLeadStageCancelled, out.Stage)
+	assert.Len(t, out.Bookings, 2)
+}
+
+// TestPartnerPayoutStatementAndInvoice tests statement totals and invoice limits on a payout
+func TestPartnerPayoutStatementAndInvoice(t *testing.T) {
+	st := &models.PartnerPayoutStatement{Payout: models.PartnerPayout{Status: "pending", TotalAmount: 50000}}
+	settlePayoutStatement(st, 0)
+	assert.Equal(t, 50000.0, st.GrossAmount)
+
+	st.Payout.Status, st.Payout.ApprovedAmount = "paid", 40000
+	settlePayoutStatement(st, 2000)
+	assert.Equal(t, 40000.0, st.GrossAmount)
+	assert.Equal(t, 2000.0, st.TDSAmount)
+	assert.Equal(t, 38000.0, st.NetAmount)
+
+	st.Payout.Status = "rejected"
+	settlePayoutStatement(st, 0)
+	assert.Equal(t, 0.0, st.NetAmount)
+
+	pending := &models.PartnerPayout{Status: "pending", TotalAmount: 50000}
+	assert.Error(t, checkPartnerInvoice(pending, 0, 1000))
+
+	approved := &models.PartnerPayout{Status: "approved", TotalAmount: 50000, ApprovedAmount: 40000}
+	assert.NoError(t, checkPartnerInvoice(approved, 0, 40000))
+	assert.NoError(t, checkPartnerInvoice(approved, 25000, 15000))
+	assert.Error(t, checkPartnerInvoice(approved, 25000, 15000.01))
+	assert.Error(t, checkPartnerInvoice(approved, 0, 0))
+}
@@ -10,32 +10,34 @@ import (
 	"time"
 
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // PartnerService handles partner operations
 type PartnerService interface {
 	// Partner Management
 	CreatePartner(ctx context.Context, tenantID string, partner *models.Partner) (*models.Partner, error)
-	GetPartner(ctx context.Context, tenantID string, partnerID int64) (*models.Partner, error)
+	GetPartner(ctx context.Context, tenantID string, partnerID string) (*models.Partner, error)
 	GetPartnerByCode(ctx context.Context, tenantID string, partnerCode string) (*models.Partner, error)
 	GetPartners(ctx context.Context, tenantID string, filter *models.PartnerFilter) ([]models.Partner, int64, error)
 	UpdatePartner(ctx context.Context, tenantID string, partner *models.Partner) (*models.Partner, error)
-	UpdatePartnerStatus(ctx context.Context, tenantID string, partnerID int64, status models.PartnerStatus, reason string, approvedBy int64) error
-	DeactivatePartner(ctx context.Context, tenantID string, partnerID int64, reason string) error
-	SuspendPartner(ctx context.Context, tenantID string, partnerID int64, reason string) error
+	UpdatePartnerStatus(ctx context.Context, tenantID string, partnerID string, status models.PartnerStatus, reason string, approvedBy string) error
+	DeactivatePartner(ctx context.Context, tenantID string, partnerID string, reason string) error
+	SuspendPartner(ctx context.Context, tenantID string, partnerID string, reason string) error
 
 	// Partner Users
 	CreatePartnerUser(ctx context.Context, tenantID string, user *models.PartnerUser) (*models.PartnerUser, error)
-	GetPartnerUser(ctx context.Context, tenantID string, userID int64) (*models.PartnerUser, error)
+	GetPartnerUser(ctx context.Context, tenantID string, userID string) (*models.PartnerUser, error)
 	GetPartnerUserByEmail(ctx context.Context, tenantID string, email string) (*models.PartnerUser, error)
-	GetPartnerUsers(ctx context.Context, tenantID string, partnerID int64) ([]models.PartnerUser, error)
+	GetPartnerUsers(ctx context.Context, tenantID string, partnerID string) ([]models.PartnerUser, error)
 	UpdatePartnerUser(ctx context.Context, tenantID string, user *models.PartnerUser) (*models.PartnerUser, error)
-	UpdatePartnerUserPassword(ctx context.Context, tenantID string, userID int64, newPasswordHash string) error
-	DeactivatePartnerUser(ctx context.Context, tenantID string, userID int64) error
+	UpdatePartnerUserPassword(ctx context.Context, tenantID string, userID string, newPasswordHash string) error
+	DeactivatePartnerUser(ctx context.Context, tenantID string, userID string) error
 
 	// Partner Statistics
-	GetPartnerStats(ctx context.Context, tenantID string, partnerID int64) (*models.PartnerStats, error)
-	GetPartnerMonthlyStats(ctx context.Context, tenantID string, partnerID int64, year int, month int) (*models.PartnerStats, error)
+	GetPartnerStats(ctx context.Context, tenantID string, partnerID string) (*models.PartnerStats, error)
+	GetPartnerMonthlyStats(ctx context.Context, tenantID string, partnerID string, year int, month int) (*models.PartnerStats, error)
 
 	// Quality Scoring
 	CalculateLeadQualityScore(ctx context.Context, leadData *models.LeadData) float64
@@ -57,8 +59,9 @@ func (s *partnerService) CreatePartner(ctx context.Context, tenantID string, par
 	}
 
 	// Generate partner code if not provided
+	partner.ID = uuid.New().String()
 	if partner.PartnerCode == "" {
-		partner.PartnerCode = fmt.Sprintf("PARTNER_%d_%d", time.Now().Unix(), int64(partner.ID))
+		partner.PartnerCode = fmt.Sprintf("PARTNER_%d_%s", time.Now().Unix(), strings.ToUpper(partner.ID[:8]))
 	}
 
 	// Set defaults
@@ -71,16 +74,16 @@ func (s *partnerService) CreatePartner(ctx context.Context, tenantID string, par
 
 	query := `
 		INSERT INTO partners (
-			tenant_id, partner_code, organization_name, partner_type, status,
+			id, tenant_id, partner_code, organization_name, partner_type, status,
 			contact_email, contact_phone, contact_person, website, description,
 			address, city, state, country, zip_code, tax_id,
 			banking_details, commission_percentage, lead_price, monthly_quota,
 			document_urls, created_by, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		partner.TenantID, partner.PartnerCode, partner.OrganizationName, partner.PartnerType, partner.Status,
+	_, err := s.db.ExecContext(ctx, query,
+		partner.ID, partner.TenantID, partner.PartnerCode, partner.OrganizationName, partner.PartnerType, partner.Status,
 		partner.ContactEmail, partner.ContactPhone, partner.ContactPerson, partner.Website, partner.Description,
 		partner.Address, partner.City, partner.State, partner.Country, partner.ZipCode, partner.TaxID,
 		partner.BankingDetails, partner.CommissionPercentage, partner.LeadPrice, partner.MonthlyQuota,
@@ -90,18 +93,11 @@ func (s *partnerService) CreatePartner(ctx context.Context, tenantID string, par
 	if err != nil {
 		return nil, fmt.Errorf("failed to create partner: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get partner ID: %w", err)
-	}
-
-	partner.ID = id
 	return partner, nil
 }
 
 // GetPartner retrieves a partner by ID
-func (s *partnerService) GetPartner(ctx context.Context, tenantID string, partnerID int64) (*models.Partner, error) {
+func (s *partnerService) GetPartner(ctx context.Context, tenantID string, partnerID string) (*models.Partner, error) {
 	partner := &models.Partner{}
 
 	query := `
@@ -285,7 +281,7 @@ func (s *partnerService) UpdatePartner(ctx context.Context, tenantID string, par
 }
 
 // UpdatePartnerStatus updates partner status
-func (s *partnerService) UpdatePartnerStatus(ctx context.Context, tenantID string, partnerID int64, status models.PartnerStatus, reason string, approvedBy int64) error {
+func (s *partnerService) UpdatePartnerStatus(ctx context.Context, tenantID string, partnerID string, status models.PartnerStatus, reason string, approvedBy string) error {
 	query := `
 		UPDATE partners SET
 			status = ?, rejection_reason = ?, approved_by = ?, approved_at = ?, updated_at = ?
@@ -294,7 +290,7 @@ func (s *partnerService) UpdatePartnerStatus(ctx context.Context, tenantID strin
 
 	now := time.Now()
 	_, err := s.db.ExecContext(ctx, query,
-		status, reason, approvedBy, now, now,
+		status, reason, nullIfEmpty(approvedBy), now, now,
 		partnerID, tenantID,
 	)
 
@@ -306,12 +302,12 @@ func (s *partnerService) UpdatePartnerStatus(ctx context.Context, tenantID strin
 }
 
 // DeactivatePartner deactivates a partner
-func (s *partnerService) DeactivatePartner(ctx context.Context, tenantID string, partnerID int64, reason string) error {
-	return s.UpdatePartnerStatus(ctx, tenantID, partnerID, models.PartnerStatusInactive, reason, 0)
+func (s *partnerService) DeactivatePartner(ctx context.Context, tenantID string, partnerID string, reason string) error {
+	return s.UpdatePartnerStatus(ctx, tenantID, partnerID, models.PartnerStatusInactive, reason, "")
 }
 
 // SuspendPartner suspends a partner
-func (s *partnerService) SuspendPartner(ctx context.Context, tenantID string, partnerID int64, reason string) error {
+func (s *partnerService) SuspendPartner(ctx context.Context, tenantID string, partnerID string, reason string) error {
 	query := `
 		UPDATE partners SET
 			status = ?, suspension_reason = ?, suspended_at = ?, updated_at = ?
@@ -329,10 +325,11 @@ func (s *partnerService) SuspendPartner(ctx context.Context, tenantID string, pa
 
 // CreatePartnerUser creates a new partner user
 func (s *partnerService) CreatePartnerUser(ctx context.Context, tenantID string, user *models.PartnerUser) (*models.PartnerUser, error) {
-	if user.Email == "" || user.PartnerID == 0 {
+	if user.Email == "" || user.PartnerID == "" {
 		return nil, errors.New("email and partner_id are required")
 	}
 
+	user.ID = uuid.New().String()
 	user.TenantID = tenantID
 	user.IsActive = true
 	user.CreatedAt = time.Now()
@@ -340,31 +337,24 @@ func (s *partnerService) CreatePartnerUser(ctx context.Context, tenantID string,
 
 	query := `
 		INSERT INTO partner_users (
-			partner_id, tenant_id, email, first_name, last_name, phone,
+			id, partner_id, tenant_id, email, first_name, last_name, phone,
 			password_hash, role, is_active, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := s.db.ExecContext(ctx, query,
-		user.PartnerID, user.TenantID, user.Email, user.FirstName, user.LastName, user.Phone,
+	_, err := s.db.ExecContext(ctx, query,
+		user.ID, user.PartnerID, user.TenantID, user.Email, user.FirstName, user.LastName, user.Phone,
 		user.PasswordHash, user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create partner user: %w", err)
 	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user ID: %w", err)
-	}
-
-	user.ID = id
 	return user, nil
 }
 
 // GetPartnerUser retrieves a partner user by ID
-func (s *partnerService) GetPartnerUser(ctx context.Context, tenantID string, userID int64) (*models.PartnerUser, error) {
+func (s *partnerService) GetPartnerUser(ctx context.Context, tenantID string, userID string) (*models.PartnerUser, error) {
 	user := &models.PartnerUser{}
 
 	query := `
@@ -416,7 +406,7 @@ func (s *partnerService) GetPartnerUserByEmail(ctx context.Context, tenantID str
 }
 
 // GetPartnerUsers retrieves all users for a partner
-func (s *partnerService) GetPartnerUsers(ctx context.Context, tenantID string, partnerID int64) ([]models.PartnerUser, error) {
+func (s *partnerService) GetPartnerUsers(ctx context.Context, tenantID string, partnerID string) ([]models.PartnerUser, error) {
 	users := []models.PartnerUser{}
 
 	query := `
@@ -473,7 +463,7 @@ func (s *partnerService) UpdatePartnerUser(ctx context.Context, tenantID string,
 }
 
 // UpdatePartnerUserPassword updates a partner user password
-func (s *partnerService) UpdatePartnerUserPassword(ctx context.Context, tenantID string, userID int64, newPasswordHash string) error {
+func (s *partnerService) UpdatePartnerUserPassword(ctx context.Context, tenantID string, userID string, newPasswordHash string) error {
 	query := `
 		UPDATE partner_users SET
 			password_hash = ?, updated_at = ?
@@ -489,7 +479,7 @@ func (s *partnerService) UpdatePartnerUserPassword(ctx context.Context, tenantID
 }
 
 // DeactivatePartnerUser deactivates a partner user
-func (s *partnerService) DeactivatePartnerUser(ctx context.Context, tenantID string, userID int64) error {
+func (s *partnerService) DeactivatePartnerUser(ctx context.Context, tenantID string, userID string) error {
 	query := `
 		UPDATE partner_users SET
 			is_active = ?, deleted_at = ?, updated_at = ?
@@ -506,7 +496,7 @@ func (s *partnerService) DeactivatePartnerUser(ctx context.Context, tenantID str
 }
 
 // GetPartnerStats retrieves statistics for a partner
-func (s *partnerService) GetPartnerStats(ctx context.Context, tenantID string, partnerID int64) (*models.PartnerStats, error) {
+func (s *partnerService) GetPartnerStats(ctx context.Context, tenantID string, partnerID string) (*models.PartnerStats, error) {
 	stats := &models.PartnerStats{}
 
 	query := `
@@ -531,6 +521,8 @@ func (s *partnerService) GetPartnerStats(ctx context.Context, tenantID string, p
 	// Calculate rates
 	if stats.TotalLeadsSubmitted > 0 {
 		stats.ApprovalRate = (float64(stats.ApprovedLeads) / float64(stats.TotalLeadsSubmitted)) * 100
+	}
+	if stats.ApprovedLeads > 0 {
 		stats.ConversionRate = (float64(stats.ConvertedLeads) / float64(stats.ApprovedLeads)) * 100
 	}
 
@@ -545,7 +537,7 @@ func (s *partnerService) GetPartnerStats(ctx context.Context, tenantID string, p
 }
 
 // GetPartnerMonthlyStats retrieves monthly statistics for a partner
-func (s *partnerService) GetPartnerMonthlyStats(ctx context.Context, tenantID string, partnerID int64, year int, month int) (*models.PartnerStats, error) {
+func (s *partnerService) GetPartnerMonthlyStats(ctx context.Context, tenantID string, partnerID string, year int, month int) (*models.PartnerStats, error) {
 	stats := &models.PartnerStats{}
 
 	startDate := fmt.Sprintf("%04d-%02d-01", year, month)
@@ -573,6 +565,8 @@ func (s *partnerService) GetPartnerMonthlyStats(ctx context.Context, tenantID st
 
 	if stats.TotalLeadsSubmitted > 0 {
 		stats.ApprovalRate = (float64(stats.ApprovedLeads) / float64(stats.TotalLeadsSubmitted)) * 100
+	}
+	if stats.ApprovedLeads > 0 {
 		stats.ConversionRate = (float64(stats.ConvertedLeads) / float64(stats.ApprovedLeads)) * 100
 	}
 
@@ -0,0 +1,981 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// PAYMENT PLAN SERVICE
+// ============================================================================
+// Reusable payment plan templates per project. Applying a plan to a booking
+// splits the agreement value into stages; construction-linked stages wait for
+// their tower milestone, and completing the milestone raises demand letters,
+// payment schedules and customer notifications for every booking in the tower.
+
+type PaymentPlanService struct {
+	DB                *sql.DB
+	Communication     *CommunicationService
+	LoanDisbursements *LoanDisbursementService
+}
+
+func NewPaymentPlanService(db *sql.DB, communication *CommunicationService, loanDisbursements *LoanDisbursementService) *PaymentPlanService {
+	return &PaymentPlanService{DB: db, Communication: communication, LoanDisbursements: loanDisbursements}
+}
+
+// defaultStageDueDays is the time allowed to pay a demand when the stage does not say
+const defaultStageDueDays = 15
+
+// ============================================================================
+// TEMPLATES
+// ============================================================================
+
+// CreateTemplate creates a payment plan template with its stages
+func (s *PaymentPlanService) CreateTemplate(tenantID, userID string, req *models.CreatePaymentPlanTemplateRequest) (*models.PaymentPlanTemplate, error) {
+	if err := validatePaymentPlan(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tpl := &models.PaymentPlanTemplate{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		PlanType:    req.PlanType,
+		Description: req.Description,
+		IsActive:    true,
+		Stages:      req.Stages,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO payment_plan_templates
+		(id, tenant_id, project_id, name, plan_type, description, is_active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		tpl.ID, tpl.TenantID, tpl.ProjectID, tpl.Name, tpl.PlanType, tpl.Description, tpl.IsActive,
+		tpl.CreatedBy, tpl.CreatedAt, tpl.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create payment plan template: %w", err)
+	}
+	for i := range tpl.Stages {
+		st := &tpl.Stages[i]
+		st.ID = uuid.New().String()
+		st.TemplateID = tpl.ID
+		if _, err := tx.Exec(`INSERT INTO payment_plan_stages
+			(id, template_id, stage_number, stage_name, percentage, trigger_type, offset_days, milestone_code, due_days, payer)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			st.ID, st.TemplateID, st.StageNumber, st.StageName, st.Percentage, st.TriggerType, st.OffsetDays,
+			nullIfEmpty(st.MilestoneCode), st.DueDays, st.Payer); err != nil {
+			return nil, fmt.Errorf("failed to create payment plan stage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit payment plan template: %w", err)
+	}
+	return tpl, nil
+}
+
+// ListTemplates lists a project's active payment plan templates
+func (s *PaymentPlanService) ListTemplates(tenantID, projectID string) ([]models.PaymentPlanTemplate, error) {
+	query := `SELECT id FROM payment_plan_templates WHERE tenant_id = ? AND is_active = TRUE`
+	args := []interface{}{tenantID}
+	if projectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY project_id, name"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment plan templates: %w", err)
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan payment plan template: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read payment plan templates: %w", err)
+	}
+
+	templates := []models.PaymentPlanTemplate{}
+	for _, id := range ids {
+		tpl, err := s.GetTemplate(tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tpl)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns a payment plan template with its stages
+func (s *PaymentPlanService) GetTemplate(tenantID, templateID string) (*models.PaymentPlanTemplate, error) {
+	tpl := &models.PaymentPlanTemplate{}
+	err := s.DB.QueryRow(`SELECT id, tenant_id, project_id, name, plan_type, COALESCE(description, ''), is_active,
+		COALESCE(created_by, ''), created_at, updated_at
+		FROM payment_plan_templates WHERE id = ? AND tenant_id = ?`, templateID, tenantID).Scan(
+		&tpl.ID, &tpl.TenantID, &tpl.ProjectID, &tpl.Name, &tpl.PlanType, &tpl.Description, &tpl.IsActive,
+		&tpl.CreatedBy, &tpl.CreatedAt, &tpl.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("payment plan template not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment plan template: %w", err)
+	}
+
+	rows, err := s.DB.Query(`SELECT id, template_id, stage_number, stage_name, percentage, trigger_type, offset_days,
+		COALESCE(milestone_code, ''), due_days, payer
+		FROM payment_plan_stages WHERE template_id = ? ORDER BY stage_number`, tpl.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment plan stages: %w", err)
+	}
+	defer rows.Close()
+
+	tpl.Stages = []models.PaymentPlanStage{}
+	for rows.Next() {
+		var st models.PaymentPlanStage
+		if err := rows.Scan(&st.ID, &st.TemplateID, &st.StageNumber, &st.StageName, &st.Percentage, &st.TriggerType,
+			&st.OffsetDays, &st.MilestoneCode, &st.DueDays, &st.Payer); err != nil {
+			return nil, fmt.Errorf("failed to scan payment plan stage: %w", err)
+		}
+		tpl.Stages = append(tpl.Stages, st)
+	}
+	return tpl, rows.Err()
+}
+
+// ============================================================================
+// BOOKING PLANS
+// ============================================================================
+
+// AssignPlan applies a template to a booking. Stages due on booking, and
+// milestone stages whose milestone the tower has already reached, are demanded
+// straight away; time-linked stages get their payment schedule with a fixed due date.
+func (s *PaymentPlanService) AssignPlan(ctx context.Context, tenantID, userID, bookingID string, req *models.AssignPaymentPlanRequest) (*models.BookingPaymentPlan, error) {
+	tpl, err := s.GetTemplate(tenantID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookingDate time.Time
+	var projectID, blockID string
+	var costSheetTotal float64
+	// The price locked on the booking wins over the unit's legacy cost sheet
+	err = s.DB.QueryRow(`SELECT b.booking_date, COALESCE(u.project_id, ''), COALESCE(u.block_id, ''),
+		COALESCE(bpl.total_payable, ucs.grand_total, 0)
+		FROM customer_bookings b
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN booking_price_locks bpl ON bpl.booking_id = b.id AND bpl.tenant_id = b.tenant_id
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = b.unit_id AND ucs.tenant_id = b.tenant_id
+		WHERE b.id = ? AND b.tenant_id = ? AND b.deleted_at IS NULL AND b.booking_status = 'active'`,
+		bookingID, tenantID).Scan(&bookingDate, &projectID, &blockID, &costSheetTotal)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("active booking not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if projectID != tpl.ProjectID {
+		return nil, fmt.Errorf("payment plan belongs to a different project")
+	}
+
+	var existing int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM booking_plan_stages WHERE tenant_id = ? AND booking_id = ?`,
+		tenantID, bookingID).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to check booking plan: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("booking already has a payment plan")
+	}
+
+	agreementValue := req.AgreementValue
+	if agreementValue <= 0 {
+		agreementValue = costSheetTotal
+	}
+	if agreementValue <= 0 {
+		return nil, fmt.Errorf("agreement_value is required when the booking has no locked price or cost sheet")
+	}
+
+	completed, err := s.completedMilestones(tenantID, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stages := buildBookingPlanStages(tenantID, bookingID, tpl, agreementValue, now)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range stages {
+		st := &stages[i]
+		if st.TriggerType == models.PlanTriggerTime {
+			due := bookingDate.AddDate(0, 0, st.OffsetDays)
+			scheduleID, err := insertPaymentSchedule(tx, st, due)
+			if err != nil {
+				return nil, err
+			}
+			st.Status = models.PlanStageStatusScheduled
+			st.DueDate = &due
+			st.ScheduleID = &scheduleID
+		}
+		if _, err := tx.Exec(`INSERT INTO booking_plan_stages
+			(id, tenant_id, booking_id, template_id, stage_id, stage_number, stage_name, percentage, amount,
+			 trigger_type, offset_days, milestone_code, due_days, payer, status, due_date, schedule_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			st.ID, st.TenantID, st.BookingID, st.TemplateID, st.StageID, st.StageNumber, st.StageName,
+			st.Percentage, st.Amount, st.TriggerType, st.OffsetDays, nullIfEmpty(st.MilestoneCode), st.DueDays,
+			st.Payer, st.Status, st.DueDate, st.ScheduleID, st.CreatedAt, st.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to save booking plan stage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit booking plan: %w", err)
+	}
+
+	for _, st := range stages {
+		var milestoneID *string
+		switch st.TriggerType {
+		case models.PlanTriggerOnBooking:
+		case models.PlanTriggerMilestone:
+			m, ok := completed[st.MilestoneCode]
+			if !ok {
+				continue
+			}
+			milestoneID = &m.ID
+		default:
+			continue
+		}
+		targets, err := s.getDemandTargets(tenantID, "bps.id = ?", st.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range targets {
+			if _, err := s.raiseDemand(ctx, tenantID, userID, t, milestoneID, string(ProviderTypeEmail)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.GetBookingPlan(tenantID, bookingID)
+}
+
+// GetBookingPlan returns a booking's plan stages and the demand letters raised so far
+func (s *PaymentPlanService) GetBookingPlan(tenantID, bookingID string) (*models.BookingPaymentPlan, error) {
+	rows, err := s.DB.Query(`SELECT `+bookingPlanStageColumns+` FROM booking_plan_stages bps
+		WHERE bps.tenant_id = ? AND bps.booking_id = ? ORDER BY bps.stage_number`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking plan: %w", err)
+	}
+	defer rows.Close()
+
+	plan := &models.BookingPaymentPlan{BookingID: bookingID, Stages: []models.BookingPlanStage{}}
+	for rows.Next() {
+		st, err := scanBookingPlanStage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking plan stage: %w", err)
+		}
+		plan.TemplateID = st.TemplateID
+		plan.AgreementValue += st.Amount
+		if st.Status == models.PlanStageStatusPending {
+			plan.PendingAmount += st.Amount
+		} else {
+			plan.DemandedAmount += st.Amount
+		}
+		plan.Stages = append(plan.Stages, *st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read booking plan: %w", err)
+	}
+	if len(plan.Stages) == 0 {
+		return nil, fmt.Errorf("booking has no payment plan")
+	}
+	plan.AgreementValue = roundTo2(plan.AgreementValue)
+	plan.PendingAmount = roundTo2(plan.PendingAmount)
+	plan.DemandedAmount = roundTo2(plan.DemandedAmount)
+
+	if err := s.DB.QueryRow(`SELECT name, plan_type FROM payment_plan_templates WHERE id = ? AND tenant_id = ?`,
+		plan.TemplateID, tenantID).Scan(&plan.PlanName, &plan.PlanType); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get payment plan template: %w", err)
+	}
+	if plan.DemandLetters, err = s.ListDemandLetters(tenantID, bookingID, ""); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ============================================================================
+// TOWER MILESTONES
+// ============================================================================
+
+// CreateTowerMilestones adds construction milestones to a tower
+func (s *PaymentPlanService) CreateTowerMilestones(tenantID string, req *models.CreateTowerMilestonesRequest) ([]models.TowerMilestone, error) {
+	if len(req.Milestones) == 0 {
+		return nil, fmt.Errorf("at least one milestone is required")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	milestones := []models.TowerMilestone{}
+	for i, in := range req.Milestones {
+		m := models.TowerMilestone{
+			ID:            uuid.New().String(),
+			TenantID:      tenantID,
+			ProjectID:     req.ProjectID,
+			BlockID:       req.BlockID,
+			MilestoneCode: normalizeMilestoneCode(in.MilestoneCode),
+			MilestoneName: in.MilestoneName,
+			Sequence:      in.Sequence,
+			Status:        models.MilestoneStatusPending,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		if m.MilestoneCode == "" || m.MilestoneName == "" {
+			return nil, fmt.Errorf("milestone %d: milestone_code and milestone_name are required", i+1)
+		}
+		if m.Sequence == 0 {
+			m.Sequence = i + 1
+		}
+		if in.PlannedDate != "" {
+			d, err := time.Parse("2006-01-02", in.PlannedDate)
+			if err != nil {
+				return nil, fmt.Errorf("milestone %d: invalid planned_date", i+1)
+			}
+			m.PlannedDate = &d
+		}
+		if _, err := tx.Exec(`INSERT INTO tower_milestones
+			(id, tenant_id, project_id, block_id, milestone_code, milestone_name, sequence, planned_date, status,
+			 created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, m.TenantID, m.ProjectID, m.BlockID, m.MilestoneCode, m.MilestoneName, m.Sequence, m.PlannedDate,
+			m.Status, m.CreatedAt, m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to create milestone %s: %w", m.MilestoneCode, err)
+		}
+		milestones = append(milestones, m)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit milestones: %w", err)
+	}
+	return milestones, nil
+}
+
+// ListTowerMilestones lists milestones of a project, optionally for one tower
+func (s *PaymentPlanService) ListTowerMilestones(tenantID, projectID, blockID string) ([]models.TowerMilestone, error) {
+	where := "project_id = ?"
+	args := []interface{}{projectID}
+	if blockID != "" {
+		where += " AND block_id = ?"
+		args = append(args, blockID)
+	}
+	return s.getTowerMilestones(tenantID, where, args...)
+}
+
+// CompleteMilestone marks a tower milestone complete and raises the demand for the
+// matching stage on every active booking in the tower
+func (s *PaymentPlanService) CompleteMilestone(ctx context.Context, tenantID, userID, milestoneID string, req *models.CompleteMilestoneRequest) (*models.MilestoneDemandRun, error) {
+	milestones, err := s.getTowerMilestones(tenantID, "id = ?", milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if len(milestones) == 0 {
+		return nil, fmt.Errorf("milestone not found")
+	}
+	m := milestones[0]
+	if m.Status == models.MilestoneStatusCompleted {
+		return nil, fmt.Errorf("milestone already completed")
+	}
+
+	completedOn := time.Now()
+	if req.CompletedOn != "" {
+		if completedOn, err = time.Parse("2006-01-02", req.CompletedOn); err != nil {
+			return nil, fmt.Errorf("invalid completed_on: %w", err)
+		}
+	}
+	m.Status = models.MilestoneStatusCompleted
+	m.CompletedOn = &completedOn
+	if req.ProgressTrackingID != "" {
+		m.ProgressTrackingID = &req.ProgressTrackingID
+	}
+	m.ArchitectCertRef = req.ArchitectCertRef
+	m.CompletedBy = &userID
+	m.UpdatedAt = time.Now()
+
+	res, err := s.DB.Exec(`UPDATE tower_milestones SET status = ?, completed_on = ?, progress_tracking_id = ?,
+		architect_certificate_ref = ?, completed_by = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`,
+		m.Status, m.CompletedOn, m.ProgressTrackingID, nullIfEmpty(m.ArchitectCertRef), m.CompletedBy, m.UpdatedAt,
+		m.ID, tenantID, models.MilestoneStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete milestone: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("milestone already completed")
+	}
+
+	return s.raiseMilestoneDemands(ctx, tenantID, userID, m, req.Channel)
+}
+
+// RaiseMilestoneDemands raises the demands still pending on a completed milestone,
+// such as those that failed when it was completed
+func (s *PaymentPlanService) RaiseMilestoneDemands(ctx context.Context, tenantID, userID, milestoneID string, req *models.RaiseMilestoneDemandsRequest) (*models.MilestoneDemandRun, error) {
+	milestones, err := s.getTowerMilestones(tenantID, "id = ?", milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if len(milestones) == 0 {
+		return nil, fmt.Errorf("milestone not found")
+	}
+	if milestones[0].Status != models.MilestoneStatusCompleted {
+		return nil, fmt.Errorf("milestone is not completed")
+	}
+	return s.raiseMilestoneDemands(ctx, tenantID, userID, milestones[0], req.Channel)
+}
+
+// raiseMilestoneDemands raises the demand for the milestone's stage on every active
+// booking in the tower. A booking that fails is reported on the run and left pending.
+func (s *PaymentPlanService) raiseMilestoneDemands(ctx context.Context, tenantID, userID string, m models.TowerMilestone, channel string) (*models.MilestoneDemandRun, error) {
+	if channel == "" {
+		channel = string(ProviderTypeEmail)
+	}
+	targets, err := s.getDemandTargets(tenantID,
+		"bps.status = ? AND bps.trigger_type = ? AND bps.milestone_code = ? AND u.block_id = ?",
+		models.PlanStageStatusPending, models.PlanTriggerMilestone, m.MilestoneCode, m.BlockID)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.MilestoneDemandRun{Milestone: m, DemandLetters: []models.DemandLetter{}, Failures: []models.DemandFailure{}}
+	for _, t := range targets {
+		letter, err := s.raiseDemand(ctx, tenantID, userID, t, &m.ID, channel)
+		if err != nil {
+			run.DemandsFailed++
+			run.Failures = append(run.Failures, models.DemandFailure{
+				BookingID:        t.Stage.BookingID,
+				BookingReference: t.BookingReference,
+				PlanStageID:      t.Stage.ID,
+				StageName:        t.Stage.StageName,
+				Error:            err.Error(),
+			})
+			continue
+		}
+		run.DemandsRaised++
+		run.TotalDemanded += letter.StageAmount
+		if letter.NotifyStatus != "sent" {
+			run.NotifyFailed++
+		}
+		if letter.LoanRequestID != "" {
+			run.LoanRequestsRaised++
+		}
+		run.DemandLetters = append(run.DemandLetters, *letter)
+	}
+	run.TotalDemanded = roundTo2(run.TotalDemanded)
+	return run, nil
+}
+
+// ============================================================================
+// DEMAND LETTERS
+// ============================================================================
+
+// ListDemandLetters lists demand letters for a booking or a milestone, newest first
+func (s *PaymentPlanService) ListDemandLetters(tenantID, bookingID, milestoneID string) ([]models.DemandLetter, error) {
+	query := `SELECT id, tenant_id, letter_number, booking_id, COALESCE(booking_reference, ''), plan_stage_id,
+		milestone_id, schedule_id, COALESCE(project_name, ''), COALESCE(unit_number, ''), COALESCE(customer_name, ''),
+		COALESCE(customer_email, ''), COALESCE(customer_phone, ''), stage_name, percentage, stage_amount,
+		previous_outstanding, total_due, demand_date, due_date, subject, body, notify_channel, notify_status,
+		COALESCE(notify_error, ''), notified_at, COALESCE(created_by, ''), created_at
+		FROM demand_letters WHERE tenant_id = ?`
+	args := []interface{}{tenantID}
+	if bookingID != "" {
+		query += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	if milestoneID != "" {
+		query += " AND milestone_id = ?"
+		args = append(args, milestoneID)
+	}
+	query += " ORDER BY demand_date DESC, created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch demand letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []models.DemandLetter{}
+	for rows.Next() {
+		var l models.DemandLetter
+		var milestone sql.NullString
+		if err := rows.Scan(&l.ID, &l.TenantID, &l.LetterNumber, &l.BookingID, &l.BookingReference, &l.PlanStageID,
+			&milestone, &l.ScheduleID, &l.ProjectName, &l.UnitNumber, &l.CustomerName, &l.CustomerEmail,
+			&l.CustomerPhone, &l.StageName, &l.Percentage, &l.StageAmount, &l.PreviousOutstanding, &l.TotalDue,
+			&l.DemandDate, &l.DueDate, &l.Subject, &l.Body, &l.NotifyChannel, &l.NotifyStatus, &l.NotifyError,
+			&l.NotifiedAt, &l.CreatedBy, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan demand letter: %w", err)
+		}
+		if milestone.Valid {
+			l.MilestoneID = &milestone.String
+		}
+		letters = append(letters, l)
+	}
+	return letters, rows.Err()
+}
+
+// demandTarget is a booking plan stage ready to be demanded, with the booking
+// details the letter needs
+type demandTarget struct {
+	Stage            models.BookingPlanStage
+	BookingReference string
+	ProjectName      string
+	UnitNumber       string
+	CustomerName     string
+	CustomerEmail    string
+	CustomerPhone    string
+	MilestoneName    string
+}
+
+// raiseDemand creates the payment schedule and demand letter for a stage, marks it
+// demanded and notifies the customer. The customer is only notified once the
+// demand is committed, and the outcome is recorded on the letter afterwards.
+func (s *PaymentPlanService) raiseDemand(ctx context.Context, tenantID, userID string, t demandTarget, milestoneID *string, channel string) (*models.DemandLetter, error) {
+	demandDate := time.Now().Truncate(24 * time.Hour)
+	dueDate := demandDate.AddDate(0, 0, t.Stage.DueDays)
+
+	previous, err := s.bookingOutstanding(tenantID, t.Stage.BookingID, demandDate)
+	if err != nil {
+		return nil, err
+	}
+	letter := buildDemandLetter(tenantID, t, demandDate, dueDate, previous, channel)
+	letter.MilestoneID = milestoneID
+	letter.CreatedBy = userID
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if letter.ScheduleID, err = insertPaymentSchedule(tx, &t.Stage, dueDate); err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(`UPDATE booking_plan_stages SET status = ?, due_date = ?, schedule_id = ?, demand_letter_id = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.PlanStageStatusDemanded, dueDate, letter.ScheduleID, letter.ID, time.Now(), t.Stage.ID, tenantID,
+		models.PlanStageStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update booking plan stage: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("stage %s was already demanded", t.Stage.StageName)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO demand_letters
+		(id, tenant_id, letter_number, booking_id, booking_reference, plan_stage_id, milestone_id, schedule_id,
+		 project_name, unit_number, customer_name, customer_email, customer_phone, stage_name, percentage,
+		 stage_amount, previous_outstanding, total_due, demand_date, due_date, subject, body, notify_channel,
+		 notify_status, notify_error, notified_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		letter.ID, letter.TenantID, letter.LetterNumber, letter.BookingID, letter.BookingReference, letter.PlanStageID,
+		letter.MilestoneID, letter.ScheduleID, letter.ProjectName, letter.UnitNumber, letter.CustomerName,
+		letter.CustomerEmail, letter.CustomerPhone, letter.StageName, letter.Percentage, letter.StageAmount,
+		letter.PreviousOutstanding, letter.TotalDue, letter.DemandDate, letter.DueDate, letter.Subject, letter.Body,
+		letter.NotifyChannel, letter.NotifyStatus, nullIfEmpty(letter.NotifyError), letter.NotifiedAt,
+		letter.CreatedBy, letter.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to save demand letter: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit demand letter: %w", err)
+	}
+
+	s.sendDemandLetter(ctx, letter)
+	if _, err := s.DB.Exec(`UPDATE demand_letters SET notify_status = ?, notify_error = ?, notified_at = ?
+		WHERE id = ? AND tenant_id = ?`,
+		letter.NotifyStatus, nullIfEmpty(letter.NotifyError), letter.NotifiedAt, letter.ID, tenantID); err != nil {
+		log.Printf("Error recording notification for demand letter %s: %v", letter.LetterNumber, err)
+	}
+
+	// Construction-linked demands on a booking with a sanctioned loan go to the lender as well.
+	// The demand stands on its own, so a failed request is reported on the letter for a retry.
+	if milestoneID != nil && s.LoanDisbursements != nil {
+		req, err := s.LoanDisbursements.RequestForDemandLetter(tenantID, userID, letter.ID)
+		if err != nil {
+			letter.LoanRequestError = err.Error()
+		} else if req != nil {
+			letter.LoanRequestID = req.ID
+		}
+	}
+	return letter, nil
+}
+
+// sendDemandLetter sends the letter through the communication service and records the outcome
+func (s *PaymentPlanService) sendDemandLetter(ctx context.Context, letter *models.DemandLetter) {
+	recipient := letter.CustomerEmail
+	if letter.NotifyChannel != string(ProviderTypeEmail) {
+		recipient = letter.CustomerPhone
+	}
+	if recipient == "" {
+		letter.NotifyStatus = "failed"
+		letter.NotifyError = "no recipient on customer details"
+		return
+	}
+	if s.Communication == nil {
+		letter.NotifyStatus = "failed"
+		letter.NotifyError = "communication service not configured"
+		return
+	}
+
+	msg := &Message{
+		TenantID:     letter.TenantID,
+		Recipient:    recipient,
+		ProviderType: CommunicationProviderType(letter.NotifyChannel),
+		Subject:      letter.Subject,
+		Body:         letter.Body,
+	}
+	if err := s.Communication.SendMessage(ctx, msg); err != nil {
+		letter.NotifyStatus = "failed"
+		letter.NotifyError = err.Error()
+		return
+	}
+
+	now := time.Now()
+	letter.NotifyStatus = "sent"
+	letter.NotifiedAt = &now
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+const bookingPlanStageColumns = `bps.id, bps.tenant_id, bps.booking_id, bps.template_id, bps.stage_id, bps.stage_number,
+	bps.stage_name, bps.percentage, bps.amount, bps.trigger_type, bps.offset_days, COALESCE(bps.milestone_code, ''),
+	bps.due_days, bps.payer, bps.status, bps.due_date, bps.schedule_id, bps.demand_letter_id, bps.created_at,
+	bps.updated_at`
+
+func scanBookingPlanStage(scanner interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.BookingPlanStage, error) {
+	var st models.BookingPlanStage
+	var dueDate sql.NullTime
+	var scheduleID, letterID sql.NullString
+	dest := []interface{}{&st.ID, &st.TenantID, &st.BookingID, &st.TemplateID, &st.StageID, &st.StageNumber,
+		&st.StageName, &st.Percentage, &st.Amount, &st.TriggerType, &st.OffsetDays, &st.MilestoneCode, &st.DueDays,
+		&st.Payer, &st.Status, &dueDate, &scheduleID, &letterID, &st.CreatedAt, &st.UpdatedAt}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if dueDate.Valid {
+		st.DueDate = &dueDate.Time
+	}
+	if scheduleID.Valid {
+		st.ScheduleID = &scheduleID.String
+	}
+	if letterID.Valid {
+		st.DemandLetterID = &letterID.String
+	}
+	return &st, nil
+}
+
+// getDemandTargets loads booking plan stages of active bookings along with the
+// booking, unit and customer details for the demand letter
+func (s *PaymentPlanService) getDemandTargets(tenantID, where string, args ...interface{}) ([]demandTarget, error) {
+	rows, err := s.DB.Query(`SELECT `+bookingPlanStageColumns+`, COALESCE(b.booking_reference, ''),
+		COALESCE(p.project_name, ''), COALESCE(u.unit_number, ''), COALESCE(cd.primary_name, ''),
+		COALESCE(cd.primary_email, ''), COALESCE(cd.primary_phone, ''), COALESCE(m.milestone_name, '')
+		FROM booking_plan_stages bps
+		JOIN customer_bookings b ON b.id = bps.booking_id AND b.tenant_id = bps.tenant_id
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN property_projects p ON p.id = u.project_id
+		LEFT JOIN customer_details cd ON cd.booking_id = b.id AND cd.deleted_at IS NULL
+		LEFT JOIN tower_milestones m ON m.tenant_id = bps.tenant_id AND m.block_id = u.block_id
+			AND m.milestone_code = bps.milestone_code
+		WHERE bps.tenant_id = ? AND b.booking_status = 'active' AND b.deleted_at IS NULL AND `+where+`
+		ORDER BY u.unit_number, bps.stage_number`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stages to demand: %w", err)
+	}
+	defer rows.Close()
+
+	targets := []demandTarget{}
+	for rows.Next() {
+		var t demandTarget
+		st, err := scanBookingPlanStage(rows, &t.BookingReference, &t.ProjectName, &t.UnitNumber,
+			&t.CustomerName, &t.CustomerEmail, &t.CustomerPhone, &t.MilestoneName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stage to demand: %w", err)
+		}
+		t.Stage = *st
+		targets = append(targets, t)
+	}
+	return targets, rows.Err()
+}
+
+func (s *PaymentPlanService) getTowerMilestones(tenantID, where string, args ...interface{}) ([]models.TowerMilestone, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, block_id, milestone_code, milestone_name, sequence,
+		planned_date, status, completed_on, progress_tracking_id, COALESCE(architect_certificate_ref, ''), completed_by,
+		created_at, updated_at
+		FROM tower_milestones WHERE tenant_id = ? AND `+where+` ORDER BY block_id, sequence`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tower milestones: %w", err)
+	}
+	defer rows.Close()
+
+	milestones := []models.TowerMilestone{}
+	for rows.Next() {
+		var m models.TowerMilestone
+		var planned, completedOn sql.NullTime
+		var progressID, completedBy sql.NullString
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.ProjectID, &m.BlockID, &m.MilestoneCode, &m.MilestoneName,
+			&m.Sequence, &planned, &m.Status, &completedOn, &progressID, &m.ArchitectCertRef, &completedBy,
+			&m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tower milestone: %w", err)
+		}
+		if planned.Valid {
+			m.PlannedDate = &planned.Time
+		}
+		if completedOn.Valid {
+			m.CompletedOn = &completedOn.Time
+		}
+		if progressID.Valid {
+			m.ProgressTrackingID = &progressID.String
+		}
+		if completedBy.Valid {
+			m.CompletedBy = &completedBy.String
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+// completedMilestones returns a tower's completed milestones by code
+func (s *PaymentPlanService) completedMilestones(tenantID, blockID string) (map[string]models.TowerMilestone, error) {
+	completed := map[string]models.TowerMilestone{}
+	if blockID == "" {
+		return completed, nil
+	}
+	milestones, err := s.getTowerMilestones(tenantID, "block_id = ? AND status = ?", blockID, models.MilestoneStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range milestones {
+		completed[m.MilestoneCode] = m
+	}
+	return completed, nil
+}
+
+// bookingOutstanding is what a booking owes on schedules already due, net of
+// cleared receipts and buyer TDS
+func (s *PaymentPlanService) bookingOutstanding(tenantID, bookingID string, asOf time.Time) (float64, error) {
+	var due, received float64
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(payment_amount), 0) FROM payment_schedules
+		WHERE tenant_id = ? AND booking_id = ? AND due_date <= ? AND deleted_at IS NULL`,
+		tenantID, bookingID, asOf).Scan(&due); err != nil {
+		return 0, fmt.Errorf("failed to fetch scheduled dues: %w", err)
+	}
+	if err := s.DB.QueryRow(`SELECT COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.booking_id = ? AND p.status = 'cleared' AND p.payment_date <= ?
+		AND p.deleted_at IS NULL`, tenantID, bookingID, asOf).Scan(&received); err != nil {
+		return 0, fmt.Errorf("failed to fetch booking receipts: %w", err)
+	}
+	return roundTo2(math.Max(due-received, 0)), nil
+}
+
+// insertPaymentSchedule adds the stage to the booking's payment schedule, which
+// drives receivables ageing and dunning
+func insertPaymentSchedule(tx *sql.Tx, st *models.BookingPlanStage, dueDate time.Time) (string, error) {
+	id := uuid.New().String()
+	_, err := tx.Exec(`INSERT INTO payment_schedules
+		(id, tenant_id, booking_id, schedule_name, payment_stage, payment_percentage, payment_amount, due_date,
+		 amount_paid, outstanding, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, st.TenantID, st.BookingID, st.StageName, st.TriggerType, st.Percentage, st.Amount, dueDate,
+		0, st.Amount, "pending", time.Now(), time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to create payment schedule: %w", err)
+	}
+	return id, nil
+}
+
+// validatePaymentPlan checks a template's stages add up to 100% and suit the plan type
+func validatePaymentPlan(req *models.CreatePaymentPlanTemplateRequest) error {
+	switch req.PlanType {
+	case models.PaymentPlanConstructionLinked, models.PaymentPlanTimeLinked,
+		models.PaymentPlanDownPayment, models.PaymentPlanSubvention:
+	default:
+		return fmt.Errorf("invalid plan_type %s", req.PlanType)
+	}
+	if len(req.Stages) == 0 {
+		return fmt.Errorf("at least one stage is required")
+	}
+
+	total := 0.0
+	hasMilestone, hasLender := false, false
+	for i := range req.Stages {
+		st := &req.Stages[i]
+		if st.StageNumber == 0 {
+			st.StageNumber = i + 1
+		}
+		if st.StageName == "" {
+			return fmt.Errorf("stage %d: stage_name is required", st.StageNumber)
+		}
+		if st.Percentage <= 0 {
+			return fmt.Errorf("stage %d: percentage must be positive", st.StageNumber)
+		}
+		if st.DueDays <= 0 {
+			st.DueDays = defaultStageDueDays
+		}
+		if st.Payer == "" {
+			st.Payer = models.PlanPayerCustomer
+		}
+		if st.Payer != models.PlanPayerCustomer && st.Payer != models.PlanPayerLender {
+			return fmt.Errorf("stage %d: invalid payer %s", st.StageNumber, st.Payer)
+		}
+		switch st.TriggerType {
+		case models.PlanTriggerOnBooking:
+		case models.PlanTriggerTime:
+			if st.OffsetDays < 0 {
+				return fmt.Errorf("stage %d: offset_days cannot be negative", st.StageNumber)
+			}
+		case models.PlanTriggerMilestone:
+			st.MilestoneCode = normalizeMilestoneCode(st.MilestoneCode)
+			if st.MilestoneCode == "" {
+				return fmt.Errorf("stage %d: milestone_code is required for a milestone stage", st.StageNumber)
+			}
+			hasMilestone = true
+		default:
+			return fmt.Errorf("stage %d: invalid trigger_type %s", st.StageNumber, st.TriggerType)
+		}
+		hasLender = hasLender || st.Payer == models.PlanPayerLender
+		total += st.Percentage
+	}
+
+	if math.Abs(total-100) > 0.001 {
+		return fmt.Errorf("stage percentages add up to %.2f%%, expected 100%%", total)
+	}
+	if req.PlanType == models.PaymentPlanConstructionLinked && !hasMilestone {
+		return fmt.Errorf("a construction-linked plan needs at least one milestone stage")
+	}
+	if req.PlanType == models.PaymentPlanTimeLinked && hasMilestone {
+		return fmt.Errorf("a time-linked plan cannot have milestone stages")
+	}
+	if req.PlanType == models.PaymentPlanSubvention && !hasLender {
+		return fmt.Errorf("a subvention plan needs at least one lender-paid stage")
+	}
+	return nil
+}
+
+// buildBookingPlanStages splits the agreement value across the template's stages;
+// the last stage takes the rounding difference so the stages add up exactly
+func buildBookingPlanStages(tenantID, bookingID string, tpl *models.PaymentPlanTemplate, agreementValue float64, now time.Time) []models.BookingPlanStage {
+	stages := make([]models.BookingPlanStage, 0, len(tpl.Stages))
+	allocated := 0.0
+	for i, st := range tpl.Stages {
+		amount := roundTo2(agreementValue * st.Percentage / 100)
+		if i == len(tpl.Stages)-1 {
+			amount = roundTo2(agreementValue - allocated)
+		}
+		allocated += amount
+		stages = append(stages, models.BookingPlanStage{
+			ID:            uuid.New().String(),
+			TenantID:      tenantID,
+			BookingID:     bookingID,
+			TemplateID:    tpl.ID,
+			StageID:       st.ID,
+			StageNumber:   st.StageNumber,
+			StageName:     st.StageName,
+			Percentage:    st.Percentage,
+			Amount:        amount,
+			TriggerType:   st.TriggerType,
+			OffsetDays:    st.OffsetDays,
+			MilestoneCode: st.MilestoneCode,
+			DueDays:       st.DueDays,
+			Payer:         st.Payer,
+			Status:        models.PlanStageStatusPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return stages
+}
+
+func buildDemandLetter(tenantID string, t demandTarget, demandDate, dueDate time.Time, previousOutstanding float64, channel string) *models.DemandLetter {
+	st := t.Stage
+	reason := "is now due"
+	if t.MilestoneName != "" && st.TriggerType == models.PlanTriggerMilestone {
+		reason = fmt.Sprintf("has fallen due on completion of %s", t.MilestoneName)
+	}
+	payer := ""
+	if st.Payer == models.PlanPayerLender {
+		payer = " Please instruct your lender to disburse this instalment."
+	}
+	totalDue := roundTo2(st.Amount + previousOutstanding)
+
+	body := fmt.Sprintf("Dear %s, the instalment \"%s\" (%.2f%% of the agreement value) for unit %s in %s, "+
+		"booking %s, %s. Amount: Rs. %.2f.", t.CustomerName, st.StageName, st.Percentage, t.UnitNumber,
+		t.ProjectName, t.BookingReference, reason, st.Amount)
+	if previousOutstanding > 0 {
+		body += fmt.Sprintf(" Previous outstanding: Rs. %.2f. Total payable: Rs. %.2f.", previousOutstanding, totalDue)
+	}
+	body += fmt.Sprintf(" Kindly pay by %s to avoid interest on delayed payment.%s", dueDate.Format("02 Jan 2006"), payer)
+
+	return &models.DemandLetter{
+		ID:                  uuid.New().String(),
+		TenantID:            tenantID,
+		LetterNumber:        interestNoteNumber("DL"),
+		BookingID:           st.BookingID,
+		BookingReference:    t.BookingReference,
+		PlanStageID:         st.ID,
+		ProjectName:         t.ProjectName,
+		UnitNumber:          t.UnitNumber,
+		CustomerName:        t.CustomerName,
+		CustomerEmail:       t.CustomerEmail,
+		CustomerPhone:       t.CustomerPhone,
+		StageName:           st.StageName,
+		Percentage:          st.Percentage,
+		StageAmount:         st.Amount,
+		PreviousOutstanding: previousOutstanding,
+		TotalDue:            totalDue,
+		DemandDate:          demandDate,
+		DueDate:             dueDate,
+		Subject:             fmt.Sprintf("Demand letter: %s for unit %s", st.StageName, t.UnitNumber),
+		Body:                body,
+		NotifyChannel:       channel,
+		NotifyStatus:        "pending",
+		CreatedAt:           time.Now(),
+	}
+}
+
+func normalizeMilestoneCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), "_"))
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidatePaymentPlan tests stage totals, triggers and plan type rules
+func TestValidatePaymentPlan(t *testing.T) {
+	clp := func() *models.CreatePaymentPlanTemplateRequest {
+		return &models.CreatePaymentPlanTemplateRequest{
+			ProjectID: "p1",
+			Name:      "CLP 10:90",
+			PlanType:  models.PaymentPlanConstructionLinked,
+			Stages: []models.PaymentPlanStage{
+				{StageName: "Booking", Percentage: 10, TriggerType: models.PlanTriggerOnBooking},
+				{StageName: "Agreement", Percentage: 20, TriggerType: models.PlanTriggerTime, OffsetDays: 30},
+				{StageName: "5th slab", Percentage: 35, TriggerType: models.PlanTriggerMilestone, MilestoneCode: "slab 5"},
+				{StageName: "Possession", Percentage: 35, TriggerType: models.PlanTriggerMilestone, MilestoneCode: "POSSESSION", DueDays: 30},
+			},
+		}
+	}
+
+	req := clp()
+	assert.NoError(t, validatePaymentPlan(req))
+	assert.Equal(t, 1, req.Stages[0].StageNumber)
+	assert.Equal(t, 4, req.Stages[3].StageNumber)
+	assert.Equal(t, defaultStageDueDays, req.Stages[0].DueDays)
+	assert.Equal(t, 30, req.Stages[3].DueDays)
+	assert.Equal(t, models.PlanPayerCustomer, req.Stages[0].Payer)
+	assert.Equal(t, "SLAB_5", req.Stages[2].MilestoneCode)
+
+	req = clp()
+	req.Stages[3].Percentage = 30
+	assert.ErrorContains(t, validatePaymentPlan(req), "95.00%")
+
+	req = clp()
+	req.Stages[2].MilestoneCode = ""
+	assert.ErrorContains(t, validatePaymentPlan(req), "milestone_code is required")
+
+	req = clp()
+	req.Stages[1].TriggerType = "monthly"
+	assert.ErrorContains(t, validatePaymentPlan(req), "invalid trigger_type")
+
+	req = clp()
+	req.PlanType = models.PaymentPlanTimeLinked
+	assert.ErrorContains(t, validatePaymentPlan(req), "cannot have milestone stages")
+
+	req = clp()
+	req.PlanType = models.PaymentPlanSubvention
+	assert.ErrorContains(t, validatePaymentPlan(req), "lender-paid stage")
+	req.Stages[3].Payer = models.PlanPayerLender
+	assert.NoError(t, validatePaymentPlan(req))
+
+	req = clp()
+	req.Stages = []models.PaymentPlanStage{{StageName: "Full", Percentage: 100, TriggerType: models.PlanTriggerOnBooking}}
+	assert.ErrorContains(t, validatePaymentPlan(req), "at least one milestone stage")
+	req.PlanType = models.PaymentPlanDownPayment
+	assert.NoError(t, validatePaymentPlan(req))
+}
+
+// TestBuildBookingPlanStages tests that stage amounts add up to the agreement value
+func TestBuildBookingPlanStages(t *testing.T) {
+	tpl := &models.PaymentPlanTemplate{
+		ID: "t1",
+		Stages: []models.PaymentPlanStage{
+			{ID: "s1", StageNumber: 1, Percentage: 33.333, TriggerType: models.PlanTriggerOnBooking},
+			{ID: "s2", StageNumber: 2, Percentage: 33.333, TriggerType: models.PlanTriggerMilestone, MilestoneCode: "PLINTH"},
+			{ID: "s3", StageNumber: 3, Percentage: 33.334, TriggerType: models.PlanTriggerMilestone, MilestoneCode: "ROOF"},
+		},
+	}
+
+	stages := buildBookingPlanStages("tenant", "b1", tpl, 1000000.01, time.Now())
+	assert.Len(t, stages, 3)
+	assert.Equal(t, 333330.0, stages[0].Amount)
+	assert.Equal(t, 333330.0, stages[1].Amount)
+	assert.Equal(t, 333340.01, stages[2].Amount)
+	assert.Equal(t, "s2", stages[1].StageID)
+	assert.Equal(t, "PLINTH", stages[1].MilestoneCode)
+	for _, st := range stages {
+		assert.Equal(t, models.PlanStageStatusPending, st.Status)
+		assert.Equal(t, "b1", st.BookingID)
+	}
+}
+
+// TestBuildDemandLetter tests the letter totals and wording
+func TestBuildDemandLetter(t *testing.T) {
+	target := demandTarget{
+		Stage: models.BookingPlanStage{
+			ID: "bps1", BookingID: "b1", StageName: "5th slab", Percentage: 35, Amount: 2100000,
+			TriggerType: models.PlanTriggerMilestone, Payer: models.PlanPayerCustomer,
+		},
+		BookingReference: "BK-001",
+		ProjectName:      "Skyline",
+		UnitNumber:       "A-502",
+		CustomerName:     "R. Sharma",
+		CustomerEmail:    "r@example.com",
+		MilestoneName:    "Casting of 5th floor slab",
+	}
+	demandDate := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	dueDate := demandDate.AddDate(0, 0, 15)
+
+	letter := buildDemandLetter("tenant", target, demandDate, dueDate, 50000, "email")
+	assert.Equal(t, 2150000.0, letter.TotalDue)
+	assert.Equal(t, 50000.0, letter.PreviousOutstanding)
+	assert.True(t, strings.HasPrefix(letter.LetterNumber, "DL-"))
+	assert.Equal(t, "bps1", letter.PlanStageID)
+	assert.Equal(t, "Demand letter: 5th slab for unit A-502", letter.Subject)
+	assert.Contains(t, letter.Body, "completion of Casting of 5th floor slab")
+	assert.Contains(t, letter.Body, "Total payable: Rs. 2150000.00")
+	assert.Contains(t, letter.Body, "16 Sep 2025")
+	assert.NotContains(t, letter.Body, "lender")
+
+	target.Stage.Payer = models.PlanPayerLender
+	target.Stage.TriggerType = models.PlanTriggerOnBooking
+	letter = buildDemandLetter("tenant", target, demandDate, dueDate, 0, "sms")
+	assert.Equal(t, 2100000.0, letter.TotalDue)
+	assert.Contains(t, letter.Body, "is now due")
+	assert.NotContains(t, letter.Body, "Previous outstanding")
+	assert.Contains(t, letter.Body, "lender")
+	assert.Equal(t, "sms", letter.NotifyChannel)
+}
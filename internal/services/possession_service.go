@@ -3,17 +3,20 @@ package services
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"time"
 	"vyomtech-backend/internal/models"
 )
 
 // PossessionService handles possession management operations
 type PossessionService struct {
-	db *sql.DB
+	db         *sql.DB
+	Subvention *SubventionService
 }
 
 // NewPossessionService creates a new possession service
-func NewPossessionService(db *sql.DB) *PossessionService {
-	return &PossessionService{db: db}
+func NewPossessionService(db *sql.DB, subvention *SubventionService) *PossessionService {
+	return &PossessionService{db: db, Subvention: subvention}
 }
 
 // CreatePossessionStatus creates a new possession status
@@ -32,7 +35,11 @@ func (s *PossessionService) CreatePossessionStatus(tenantID, bookingID int64, st
 		return nil, err
 	}
 
-	return s.GetPossessionStatus(id)
+	ps, err := s.GetPossessionStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	return ps, s.possessionCompleted(ps)
 }
 
 // GetPossessionStatus retrieves a possession status by ID
@@ -98,7 +105,28 @@ func (s *PossessionService) UpdatePossessionStatus(id int64, updates map[string]
 		return nil, err
 	}
 
-	return s.GetPossessionStatus(id)
+	ps, err := s.GetPossessionStatus(id)
+	if err != nil {
+		return nil, err
+	}
+	return ps, s.possessionCompleted(ps)
+}
+
+// possessionCompleted stops the booking's pre-EMI subvention once possession is
+// handed over, as of the possession date
+func (s *PossessionService) possessionCompleted(ps *models.PossessionStatus) error {
+	if s.Subvention == nil || ps.Status != "completed" {
+		return nil
+	}
+	on := time.Now()
+	if ps.PossessionDate != nil {
+		on = *ps.PossessionDate
+	}
+	if err := s.Subvention.StopForBooking(fmt.Sprint(ps.TenantID), fmt.Sprint(ps.BookingID),
+		models.SubventionStopPossession, on); err != nil {
+		return fmt.Errorf("possession completed but failed to stop pre-EMI subvention: %w", err)
+	}
+	return nil
 }
 
 // DeletePossessionStatus soft deletes a possession status
@@ -0,0 +1,689 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// PRICE LIST SERVICE
+// ============================================================================
+// Versioned, rule-based price lists per project and tower. A published version
+// is immutable and applies from its effective date until a newer one takes
+// over, so the cost sheet of any unit can be rebuilt as of any date. Bookings
+// freeze the cost sheet they were sold at.
+
+type PriceListService struct {
+	DB *sql.DB
+}
+
+// ErrNoPriceList is returned when no published price list covers a unit
+var ErrNoPriceList = errors.New("no published price list")
+
+func NewPriceListService(db *sql.DB) *PriceListService {
+	return &PriceListService{DB: db}
+}
+
+// ============================================================================
+// PRICE LISTS
+// ============================================================================
+
+// CreatePriceList creates the next draft version of a project's (or tower's) price list
+func (s *PriceListService) CreatePriceList(tenantID, userID string, req *models.CreatePriceListRequest) (*models.PriceList, error) {
+	if err := validatePriceList(req); err != nil {
+		return nil, err
+	}
+
+	var version int
+	if err := s.DB.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM price_lists
+		WHERE tenant_id = ? AND project_id = ? AND COALESCE(block_id, '') = ?`,
+		tenantID, req.ProjectID, req.BlockID).Scan(&version); err != nil {
+		return nil, fmt.Errorf("failed to get price list version: %w", err)
+	}
+
+	now := time.Now()
+	pl := &models.PriceList{
+		ID:               uuid.New().String(),
+		TenantID:         tenantID,
+		ProjectID:        req.ProjectID,
+		Version:          version + 1,
+		Name:             req.Name,
+		BaseRatePerSqft:  req.BaseRatePerSqft,
+		BaseGSTRate:      req.BaseGSTRate,
+		StampDutyRate:    req.StampDutyRate,
+		RegistrationRate: req.RegistrationRate,
+		RegistrationCap:  req.RegistrationCap,
+		Status:           models.PriceListStatusDraft,
+		Notes:            req.Notes,
+		Rules:            req.Rules,
+		CreatedBy:        userID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if req.BlockID != "" {
+		pl.BlockID = &req.BlockID
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO price_lists
+		(id, tenant_id, project_id, block_id, version, name, base_rate_per_sqft, base_gst_rate, stamp_duty_rate,
+		 registration_rate, registration_cap, status, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		pl.ID, pl.TenantID, pl.ProjectID, pl.BlockID, pl.Version, pl.Name, pl.BaseRatePerSqft, pl.BaseGSTRate,
+		pl.StampDutyRate, pl.RegistrationRate, pl.RegistrationCap, pl.Status, nullIfEmpty(pl.Notes), pl.CreatedBy,
+		pl.CreatedAt, pl.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create price list: %w", err)
+	}
+	for i := range pl.Rules {
+		rule := &pl.Rules[i]
+		rule.ID = uuid.New().String()
+		rule.PriceListID = pl.ID
+		if _, err := tx.Exec(`INSERT INTO price_list_rules
+			(id, price_list_id, category, code, name, charge_type, amount, gst_rate, from_floor, to_floor,
+			 attribute, match_value, parking_type, unit_types, is_optional, display_order)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rule.ID, rule.PriceListID, rule.Category, rule.Code, rule.Name, rule.ChargeType, rule.Amount,
+			rule.GSTRate, rule.FromFloor, rule.ToFloor, nullIfEmpty(rule.Attribute), nullIfEmpty(rule.MatchValue),
+			nullIfEmpty(rule.ParkingType), nullIfEmpty(rule.UnitTypes), rule.IsOptional, rule.DisplayOrder); err != nil {
+			return nil, fmt.Errorf("failed to create price list rule %s: %w", rule.Code, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit price list: %w", err)
+	}
+	return pl, nil
+}
+
+// PublishPriceList publishes a draft; it applies from its effective date onwards
+func (s *PriceListService) PublishPriceList(tenantID, userID, priceListID string, req *models.PublishPriceListRequest) (*models.PriceList, error) {
+	effectiveFrom := time.Now().Truncate(24 * time.Hour)
+	if req.EffectiveFrom != "" {
+		d, err := time.Parse("2006-01-02", req.EffectiveFrom)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effective_from: %w", err)
+		}
+		effectiveFrom = d
+	}
+
+	res, err := s.DB.Exec(`UPDATE price_lists SET status = ?, effective_from = ?, published_by = ?, published_at = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.PriceListStatusPublished, effectiveFrom, userID, time.Now(), time.Now(), priceListID, tenantID,
+		models.PriceListStatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish price list: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("draft price list not found")
+	}
+	return s.GetPriceList(tenantID, priceListID)
+}
+
+// ListPriceLists lists a project's price list versions, newest first
+func (s *PriceListService) ListPriceLists(tenantID, projectID, blockID string) ([]models.PriceList, error) {
+	where := "project_id = ?"
+	args := []interface{}{projectID}
+	if blockID != "" {
+		where += " AND block_id = ?"
+		args = append(args, blockID)
+	}
+	lists, err := s.getPriceLists(tenantID, where+" ORDER BY block_id, version DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		if lists[i].Rules, err = s.getPriceListRules(lists[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+// GetPriceList returns a price list version with its rules
+func (s *PriceListService) GetPriceList(tenantID, priceListID string) (*models.PriceList, error) {
+	lists, err := s.getPriceLists(tenantID, "id = ?", priceListID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return nil, fmt.Errorf("price list not found")
+	}
+	pl := &lists[0]
+	if pl.Rules, err = s.getPriceListRules(pl.ID); err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// ResolvePriceList finds the version in force for a tower on a date. A tower's
+// own list wins over the project-wide one; among those, the latest effective
+// date and then the highest version.
+func (s *PriceListService) ResolvePriceList(tenantID, projectID, blockID string, asOf time.Time) (*models.PriceList, error) {
+	lists, err := s.getPriceLists(tenantID, `project_id = ? AND status = ? AND effective_from <= ?
+		AND (block_id IS NULL OR block_id = ?)
+		ORDER BY block_id IS NULL, effective_from DESC, version DESC LIMIT 1`,
+		projectID, models.PriceListStatusPublished, asOf, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return nil, fmt.Errorf("%w for the unit as of %s", ErrNoPriceList, asOf.Format("2006-01-02"))
+	}
+	pl := &lists[0]
+	if pl.Rules, err = s.getPriceListRules(pl.ID); err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// ============================================================================
+// COST SHEETS
+// ============================================================================
+
+// GetCostSheet prices a unit under the price list in force on asOf
+func (s *PriceListService) GetCostSheet(tenantID, unitID string, asOf time.Time, opts *models.CostSheetOptions) (*models.CostSheet, error) {
+	sheet, err := s.getUnitForPricing(tenantID, unitID)
+	if err != nil {
+		return nil, err
+	}
+	pl, err := s.ResolvePriceList(tenantID, sheet.ProjectID, sheet.BlockID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	sheet.AsOf = asOf
+	if err := buildCostSheet(sheet, pl, opts); err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
+
+// UpdateUnitPricingAttributes sets the view and corner flags PLC rules use
+func (s *PriceListService) UpdateUnitPricingAttributes(tenantID, unitID string, req *models.UpdateUnitPricingAttributesRequest) (*models.UnitPricingAttributes, error) {
+	attrs := &models.UnitPricingAttributes{
+		UnitID:    unitID,
+		View:      strings.ToLower(strings.TrimSpace(req.View)),
+		IsCorner:  req.IsCorner,
+		UpdatedAt: time.Now(),
+	}
+	if _, err := s.DB.Exec(`INSERT INTO unit_pricing_attributes (tenant_id, unit_id, view_type, is_corner, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE view_type = VALUES(view_type), is_corner = VALUES(is_corner), updated_at = VALUES(updated_at)`,
+		tenantID, unitID, nullIfEmpty(attrs.View), attrs.IsCorner, attrs.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update unit pricing attributes: %w", err)
+	}
+	return attrs, nil
+}
+
+// ============================================================================
+// BOOKING PRICE LOCKS
+// ============================================================================
+
+// LockBookingPrice freezes the cost sheet a booking was sold at, as of the booking
+// date unless told otherwise, less any approved discount. A booking's price can
+// only be locked once.
+func (s *PriceListService) LockBookingPrice(tenantID, userID, bookingID string, req *models.LockBookingPriceRequest) (*models.BookingPriceLock, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	lock, err := s.LockBookingPriceTx(tx, tenantID, userID, bookingID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit booking price lock: %w", err)
+	}
+	return lock, nil
+}
+
+// LockBookingPriceTx locks a booking's price inside the caller's transaction, so a
+// new booking, its price lock and the discount it consumes are created together. A
+// booking rate below the cost sheet needs an approved discount that covers it.
+func (s *PriceListService) LockBookingPriceTx(tx *sql.Tx, tenantID, userID, bookingID string, req *models.LockBookingPriceRequest) (*models.BookingPriceLock, error) {
+	var unitID string
+	var bookingDate time.Time
+	var ratePerSqft float64
+	err := tx.QueryRow(`SELECT unit_id, booking_date, COALESCE(rate_per_sqft, 0) FROM customer_bookings
+		WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL`, bookingID, tenantID).Scan(&unitID, &bookingDate, &ratePerSqft)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	var existing int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM booking_price_locks WHERE tenant_id = ? AND booking_id = ?`,
+		tenantID, bookingID).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to check booking price lock: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("booking price is already locked")
+	}
+
+	asOf := bookingDate
+	if req.AsOf != "" {
+		if asOf, err = time.Parse("2006-01-02", req.AsOf); err != nil {
+			return nil, fmt.Errorf("invalid as_of: %w", err)
+		}
+	}
+	opts := req.CostSheetOptions
+	opts.DiscountAmount = 0
+	if req.DiscountRequestID != "" {
+		discount, err := NewDiscountApprovalService(s.DB).GetDiscountRequest(tenantID, req.DiscountRequestID)
+		if err != nil {
+			return nil, err
+		}
+		if discount.Status != models.DiscountStatusApproved {
+			return nil, fmt.Errorf("discount request is %s, only an approved discount can be applied", discount.Status)
+		}
+		if discount.UnitID != unitID {
+			return nil, fmt.Errorf("discount request was raised for a different unit")
+		}
+		opts.DiscountAmount = discount.DiscountAmount
+	}
+	sheet, err := s.GetCostSheet(tenantID, unitID, asOf, &opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingRate(sheet, ratePerSqft); err != nil {
+		return nil, err
+	}
+	sheet.DiscountRequestID = req.DiscountRequestID
+	snapshot, err := json.Marshal(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cost sheet: %w", err)
+	}
+
+	lock := &models.BookingPriceLock{
+		ID:               uuid.New().String(),
+		TenantID:         tenantID,
+		BookingID:        bookingID,
+		UnitID:           unitID,
+		PriceListID:      sheet.PriceListID,
+		PriceListVersion: sheet.PriceListVersion,
+		AgreementValue:   sheet.AgreementValue,
+		TotalGST:         sheet.TotalGST,
+		TotalPayable:     sheet.TotalPayable,
+		GrandTotal:       sheet.GrandTotal,
+		CostSheet:        *sheet,
+		LockedBy:         userID,
+		LockedAt:         time.Now(),
+	}
+
+	if _, err := tx.Exec(`INSERT INTO booking_price_locks
+		(id, tenant_id, booking_id, unit_id, price_list_id, price_list_version, agreement_value, total_gst,
+		 total_payable, grand_total, cost_sheet, locked_by, locked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		lock.ID, lock.TenantID, lock.BookingID, lock.UnitID, lock.PriceListID, lock.PriceListVersion,
+		lock.AgreementValue, lock.TotalGST, lock.TotalPayable, lock.GrandTotal, string(snapshot), lock.LockedBy,
+		lock.LockedAt); err != nil {
+		return nil, fmt.Errorf("failed to lock booking price: %w", err)
+	}
+	if req.DiscountRequestID != "" {
+		if err := markDiscountUsed(tx, tenantID, req.DiscountRequestID, bookingID, ""); err != nil {
+			return nil, err
+		}
+	}
+	return lock, nil
+}
+
+// GetBookingPriceLock returns the cost sheet frozen on a booking
+func (s *PriceListService) GetBookingPriceLock(tenantID, bookingID string) (*models.BookingPriceLock, error) {
+	lock := &models.BookingPriceLock{}
+	var snapshot string
+	err := s.DB.QueryRow(`SELECT id, tenant_id, booking_id, unit_id, price_list_id, price_list_version,
+		agreement_value, total_gst, total_payable, grand_total, cost_sheet, COALESCE(locked_by, ''), locked_at
+		FROM booking_price_locks WHERE tenant_id = ? AND booking_id = ?`, tenantID, bookingID).Scan(
+		&lock.ID, &lock.TenantID, &lock.BookingID, &lock.UnitID, &lock.PriceListID, &lock.PriceListVersion,
+		&lock.AgreementValue, &lock.TotalGST, &lock.TotalPayable, &lock.GrandTotal, &snapshot, &lock.LockedBy,
+		&lock.LockedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking price is not locked")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking price lock: %w", err)
+	}
+	if err := json.Unmarshal([]byte(snapshot), &lock.CostSheet); err != nil {
+		return nil, fmt.Errorf("failed to decode locked cost sheet: %w", err)
+	}
+	return lock, nil
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+func (s *PriceListService) getPriceLists(tenantID, where string, args ...interface{}) ([]models.PriceList, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, block_id, version, name, base_rate_per_sqft,
+		base_gst_rate, stamp_duty_rate, registration_rate, registration_cap, status, effective_from, published_by,
+		published_at, COALESCE(notes, ''), COALESCE(created_by, ''), created_at, updated_at
+		FROM price_lists WHERE tenant_id = ? AND `+where, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []models.PriceList{}
+	for rows.Next() {
+		var pl models.PriceList
+		var blockID, publishedBy sql.NullString
+		var effectiveFrom, publishedAt sql.NullTime
+		if err := rows.Scan(&pl.ID, &pl.TenantID, &pl.ProjectID, &blockID, &pl.Version, &pl.Name,
+			&pl.BaseRatePerSqft, &pl.BaseGSTRate, &pl.StampDutyRate, &pl.RegistrationRate, &pl.RegistrationCap,
+			&pl.Status, &effectiveFrom, &publishedBy, &publishedAt, &pl.Notes, &pl.CreatedBy, &pl.CreatedAt,
+			&pl.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price list: %w", err)
+		}
+		if blockID.Valid {
+			pl.BlockID = &blockID.String
+		}
+		if effectiveFrom.Valid {
+			pl.EffectiveFrom = &effectiveFrom.Time
+		}
+		if publishedBy.Valid {
+			pl.PublishedBy = &publishedBy.String
+		}
+		if publishedAt.Valid {
+			pl.PublishedAt = &publishedAt.Time
+		}
+		lists = append(lists, pl)
+	}
+	return lists, rows.Err()
+}
+
+func (s *PriceListService) getPriceListRules(priceListID string) ([]models.PriceListRule, error) {
+	rows, err := s.DB.Query(`SELECT id, price_list_id, category, code, name, charge_type, amount, gst_rate,
+		from_floor, to_floor, COALESCE(attribute, ''), COALESCE(match_value, ''), COALESCE(parking_type, ''),
+		COALESCE(unit_types, ''), is_optional, display_order
+		FROM price_list_rules WHERE price_list_id = ? ORDER BY display_order, category, code`, priceListID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price list rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.PriceListRule{}
+	for rows.Next() {
+		var r models.PriceListRule
+		if err := rows.Scan(&r.ID, &r.PriceListID, &r.Category, &r.Code, &r.Name, &r.ChargeType, &r.Amount,
+			&r.GSTRate, &r.FromFloor, &r.ToFloor, &r.Attribute, &r.MatchValue, &r.ParkingType, &r.UnitTypes,
+			&r.IsOptional, &r.DisplayOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan price list rule: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// getUnitForPricing starts a cost sheet with the unit's pricing attributes
+func (s *PriceListService) getUnitForPricing(tenantID, unitID string) (*models.CostSheet, error) {
+	sheet := &models.CostSheet{UnitID: unitID}
+	err := s.DB.QueryRow(`SELECT u.unit_number, u.project_id, COALESCE(u.block_id, ''), COALESCE(u.floor, 0),
+		COALESCE(u.unit_type, ''), COALESCE(u.facing, ''), COALESCE(u.sbua, 0), COALESCE(a.view_type, ''),
+		COALESCE(a.is_corner, FALSE)
+		FROM property_units u
+		LEFT JOIN unit_pricing_attributes a ON a.unit_id = u.id AND a.tenant_id = u.tenant_id
+		WHERE u.id = ? AND u.tenant_id = ? AND u.deleted_at IS NULL`, unitID, tenantID).Scan(
+		&sheet.UnitNumber, &sheet.ProjectID, &sheet.BlockID, &sheet.Floor, &sheet.UnitType, &sheet.Facing,
+		&sheet.SBUA, &sheet.View, &sheet.IsCorner)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unit not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit: %w", err)
+	}
+	if sheet.SBUA <= 0 {
+		return nil, fmt.Errorf("unit %s has no SBUA to price", sheet.UnitNumber)
+	}
+	return sheet, nil
+}
+
+// validatePriceList checks rates and that every rule can be applied
+func validatePriceList(req *models.CreatePriceListRequest) error {
+	if req.BaseRatePerSqft <= 0 {
+		return fmt.Errorf("base_rate_per_sqft must be positive")
+	}
+	for _, rate := range []float64{req.BaseGSTRate, req.StampDutyRate, req.RegistrationRate} {
+		if rate < 0 || rate > 100 {
+			return fmt.Errorf("rates must be between 0 and 100")
+		}
+	}
+
+	codes := map[string]bool{}
+	for i := range req.Rules {
+		r := &req.Rules[i]
+		r.Code = strings.ToUpper(strings.TrimSpace(r.Code))
+		if r.Code == "" || r.Name == "" {
+			return fmt.Errorf("rule %d: code and name are required", i+1)
+		}
+		if codes[r.Code] {
+			return fmt.Errorf("rule %s: duplicate code", r.Code)
+		}
+		codes[r.Code] = true
+		if r.Amount < 0 || r.GSTRate < 0 || r.GSTRate > 100 {
+			return fmt.Errorf("rule %s: invalid amount or gst_rate", r.Code)
+		}
+		switch r.ChargeType {
+		case models.PriceChargePerSqft, models.PriceChargeLumpsum, models.PriceChargeBasicPct:
+		default:
+			return fmt.Errorf("rule %s: invalid charge_type %s", r.Code, r.ChargeType)
+		}
+		r.Attribute = strings.ToLower(r.Attribute)
+		r.MatchValue = strings.ToLower(strings.TrimSpace(r.MatchValue))
+		r.ParkingType = strings.ToLower(strings.TrimSpace(r.ParkingType))
+
+		switch r.Category {
+		case models.PriceRuleFloorRise:
+			if r.FromFloor < 0 || (r.ToFloor != 0 && r.ToFloor < r.FromFloor) {
+				return fmt.Errorf("rule %s: invalid floor range", r.Code)
+			}
+		case models.PriceRulePLC:
+			switch r.Attribute {
+			case models.PLCAttributeCorner:
+			case models.PLCAttributeFacing, models.PLCAttributeView:
+				if r.MatchValue == "" {
+					return fmt.Errorf("rule %s: match_value is required for a %s PLC", r.Code, r.Attribute)
+				}
+			default:
+				return fmt.Errorf("rule %s: PLC attribute must be facing, view or corner", r.Code)
+			}
+		case models.PriceRuleParking:
+			if r.ParkingType == "" {
+				return fmt.Errorf("rule %s: parking_type is required for a parking tier", r.Code)
+			}
+		case models.PriceRuleClub, models.PriceRuleStatutory, models.PriceRuleOther:
+		default:
+			return fmt.Errorf("rule %s: invalid category %s", r.Code, r.Category)
+		}
+	}
+	return nil
+}
+
+// buildCostSheet applies a price list's rules to a unit. Floor rise takes the
+// first matching slab, PLCs stack, parking charges the chosen tier per slot,
+// and optional rules apply only when asked for.
+func buildCostSheet(sheet *models.CostSheet, pl *models.PriceList, opts *models.CostSheetOptions) error {
+	if opts == nil {
+		opts = &models.CostSheetOptions{}
+	}
+	sheet.PriceListID = pl.ID
+	sheet.PriceListVersion = pl.Version
+	sheet.PriceListName = pl.Name
+	sheet.ParkingType = strings.ToLower(strings.TrimSpace(opts.ParkingType))
+	sheet.ParkingSlots = 0
+	if sheet.ParkingType != "" {
+		sheet.ParkingSlots = opts.ParkingSlots
+		if sheet.ParkingSlots <= 0 {
+			sheet.ParkingSlots = 1
+		}
+	}
+	optional := map[string]bool{}
+	for _, code := range opts.OptionalCharges {
+		optional[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	basic := roundTo2(sheet.SBUA * pl.BaseRatePerSqft)
+	sheet.Lines = []models.CostSheetLine{
+		costSheetLine("basic", "BASIC", "Basic cost", models.PriceChargePerSqft, sheet.SBUA, pl.BaseRatePerSqft, basic, pl.BaseGSTRate),
+	}
+
+	rules := append([]models.PriceListRule(nil), pl.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].DisplayOrder < rules[j].DisplayOrder })
+
+	floorRiseApplied, parkingApplied := false, false
+	for _, r := range rules {
+		if !ruleAppliesToUnitType(r, sheet.UnitType) || (r.IsOptional && !optional[r.Code]) {
+			continue
+		}
+		qty := 1.0
+		switch r.Category {
+		case models.PriceRuleFloorRise:
+			if floorRiseApplied || sheet.Floor < r.FromFloor || (r.ToFloor != 0 && sheet.Floor > r.ToFloor) {
+				continue
+			}
+			floorRiseApplied = true
+		case models.PriceRulePLC:
+			if !plcMatches(r, sheet) {
+				continue
+			}
+		case models.PriceRuleParking:
+			if r.ParkingType != sheet.ParkingType || parkingApplied {
+				continue
+			}
+			parkingApplied = true
+			qty = float64(sheet.ParkingSlots)
+		}
+
+		var rate, amount float64
+		switch r.ChargeType {
+		case models.PriceChargePerSqft:
+			qty, rate = sheet.SBUA, r.Amount
+			amount = sheet.SBUA * r.Amount
+		case models.PriceChargeBasicPct:
+			rate = r.Amount
+			amount = basic * r.Amount / 100
+		default:
+			rate = r.Amount
+			amount = qty * r.Amount
+		}
+		sheet.Lines = append(sheet.Lines, costSheetLine(r.Category, r.Code, r.Name, r.ChargeType, qty, rate, roundTo2(amount), r.GSTRate))
+	}
+	if sheet.ParkingType != "" && !parkingApplied {
+		return fmt.Errorf("price list has no %s parking tier", sheet.ParkingType)
+	}
+
+	// An approved discount comes off the consideration, and the GST on it at the basic rate
+	sheet.Discount = roundTo2(opts.DiscountAmount)
+	if sheet.Discount > 0 {
+		if sheet.Discount >= basic {
+			return fmt.Errorf("discount cannot exceed the basic cost")
+		}
+		sheet.Lines = append(sheet.Lines, costSheetLine("discount", "DISCOUNT", "Approved discount", models.PriceChargeLumpsum, 1, -sheet.Discount, -sheet.Discount, pl.BaseGSTRate))
+	}
+
+	sheet.AgreementValue, sheet.StatutoryCharges, sheet.TotalGST = 0, 0, 0
+	for _, l := range sheet.Lines {
+		if l.Category == models.PriceRuleStatutory {
+			sheet.StatutoryCharges += l.Amount
+		} else {
+			sheet.AgreementValue += l.Amount
+		}
+		sheet.TotalGST += l.GSTAmount
+	}
+	sheet.AgreementValue = roundTo2(sheet.AgreementValue)
+	sheet.StatutoryCharges = roundTo2(sheet.StatutoryCharges)
+	sheet.TotalGST = roundTo2(sheet.TotalGST)
+	sheet.TotalPayable = roundTo2(sheet.AgreementValue + sheet.StatutoryCharges + sheet.TotalGST)
+
+	sheet.GuidelineValue = opts.GuidelineValue
+	sheet.StampDutyBasis = math.Max(sheet.AgreementValue, opts.GuidelineValue)
+	sheet.StampDutyRate = pl.StampDutyRate
+	sheet.StampDuty = roundTo2(sheet.StampDutyBasis * pl.StampDutyRate / 100)
+	sheet.RegistrationRate = pl.RegistrationRate
+	sheet.RegistrationFee = roundTo2(sheet.StampDutyBasis * pl.RegistrationRate / 100)
+	if pl.RegistrationCap > 0 && sheet.RegistrationFee > pl.RegistrationCap {
+		sheet.RegistrationFee = pl.RegistrationCap
+	}
+	sheet.GrandTotal = roundTo2(sheet.TotalPayable + sheet.StampDuty + sheet.RegistrationFee)
+	return nil
+}
+
+// checkBookingRate refuses a booking rate whose basic cost comes in below the cost
+// sheet's, net of the approved discount applied to it. A booking without a rate is
+// sold at the cost sheet.
+func checkBookingRate(sheet *models.CostSheet, ratePerSqft float64) error {
+	if ratePerSqft <= 0 || sheet.SBUA <= 0 {
+		return nil
+	}
+	var basic models.CostSheetLine
+	for _, l := range sheet.Lines {
+		if l.Code == "BASIC" {
+			basic = l
+			break
+		}
+	}
+	floor := roundTo2(basic.Amount - sheet.Discount)
+	if roundTo2(ratePerSqft*sheet.SBUA) < floor-0.005 {
+		return fmt.Errorf("booking rate of %.2f per sqft is below the %.2f per sqft on the cost sheet; an approved discount request for the unit must cover it",
+			ratePerSqft, roundTo2(floor/sheet.SBUA))
+	}
+	return nil
+}
+
+func costSheetLine(category, code, name, chargeType string, qty, rate, amount, gstRate float64) models.CostSheetLine {
+	gst := roundTo2(amount * gstRate / 100)
+	return models.CostSheetLine{
+		Category:   category,
+		Code:       code,
+		Name:       name,
+		ChargeType: chargeType,
+		Quantity:   qty,
+		Rate:       rate,
+		Amount:     amount,
+		GSTRate:    gstRate,
+		GSTAmount:  gst,
+		Total:      roundTo2(amount + gst),
+	}
+}
+
+func ruleAppliesToUnitType(r models.PriceListRule, unitType string) bool {
+	if strings.TrimSpace(r.UnitTypes) == "" {
+		return true
+	}
+	for _, t := range strings.Split(r.UnitTypes, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), unitType) {
+			return true
+		}
+	}
+	return false
+}
+
+func plcMatches(r models.PriceListRule, sheet *models.CostSheet) bool {
+	switch r.Attribute {
+	case models.PLCAttributeCorner:
+		// Older units record corner placement in facing
+		return sheet.IsCorner || strings.EqualFold(sheet.Facing, "corner")
+	case models.PLCAttributeFacing:
+		return strings.EqualFold(sheet.Facing, r.MatchValue)
+	case models.PLCAttributeView:
+		return strings.EqualFold(sheet.View, r.MatchValue)
+	}
+	return false
+}
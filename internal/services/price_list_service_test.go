@@ -0,0 +1,139 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPriceList() *models.PriceList {
+	return &models.PriceList{
+		ID:               "pl1",
+		Version:          3,
+		Name:             "Tower A launch",
+		BaseRatePerSqft:  6000,
+		BaseGSTRate:      5,
+		StampDutyRate:    7,
+		RegistrationRate: 1,
+		RegistrationCap:  30000,
+		Rules: []models.PriceListRule{
+			{Category: models.PriceRuleFloorRise, Code: "FR1", Name: "Floor rise 1-5", ChargeType: models.PriceChargePerSqft, Amount: 0, FromFloor: 1, ToFloor: 5, GSTRate: 5},
+			{Category: models.PriceRuleFloorRise, Code: "FR2", Name: "Floor rise 6+", ChargeType: models.PriceChargePerSqft, Amount: 50, FromFloor: 6, GSTRate: 5},
+			{Category: models.PriceRulePLC, Code: "EAST", Name: "East facing", ChargeType: models.PriceChargePerSqft, Amount: 100, Attribute: "facing", MatchValue: "east", GSTRate: 5},
+			{Category: models.PriceRulePLC, Code: "PARK", Name: "Park view", ChargeType: models.PriceChargeBasicPct, Amount: 2, Attribute: "view", MatchValue: "park", GSTRate: 5},
+			{Category: models.PriceRulePLC, Code: "CORNER", Name: "Corner", ChargeType: models.PriceChargeLumpsum, Amount: 150000, Attribute: "corner", GSTRate: 5},
+			{Category: models.PriceRuleParking, Code: "COV", Name: "Covered parking", ChargeType: models.PriceChargeLumpsum, Amount: 300000, ParkingType: "covered", GSTRate: 5},
+			{Category: models.PriceRuleParking, Code: "OPEN", Name: "Open parking", ChargeType: models.PriceChargeLumpsum, Amount: 150000, ParkingType: "open", GSTRate: 5},
+			{Category: models.PriceRuleClub, Code: "CLUB", Name: "Club membership", ChargeType: models.PriceChargeLumpsum, Amount: 100000, GSTRate: 18},
+			{Category: models.PriceRuleStatutory, Code: "CORPUS", Name: "Corpus fund", ChargeType: models.PriceChargePerSqft, Amount: 50},
+			{Category: models.PriceRuleOther, Code: "PENT", Name: "Penthouse terrace", ChargeType: models.PriceChargeLumpsum, Amount: 500000, UnitTypes: "4BHK", GSTRate: 5},
+			{Category: models.PriceRuleOther, Code: "MODKIT", Name: "Modular kitchen", ChargeType: models.PriceChargeLumpsum, Amount: 200000, IsOptional: true, GSTRate: 18},
+		},
+	}
+}
+
+// TestBuildCostSheet tests rule matching, GST per component and stamp duty estimates
+func TestBuildCostSheet(t *testing.T) {
+	sheet := &models.CostSheet{UnitID: "u1", Floor: 8, UnitType: "3BHK", Facing: "East", View: "park", SBUA: 1000}
+	opts := &models.CostSheetOptions{ParkingType: "Covered", ParkingSlots: 2, GuidelineValue: 9000000}
+
+	assert.NoError(t, buildCostSheet(sheet, testPriceList(), opts))
+
+	codes := []string{}
+	for _, l := range sheet.Lines {
+		codes = append(codes, l.Code)
+	}
+	// Only the 6+ slab, no corner, no 4BHK-only or optional charges
+	assert.Equal(t, []string{"BASIC", "FR2", "EAST", "PARK", "COV", "CLUB", "CORPUS"}, codes)
+
+	assert.Equal(t, 6000000.0, sheet.Lines[0].Amount)
+	assert.Equal(t, 300000.0, sheet.Lines[0].GSTAmount)
+	assert.Equal(t, 50000.0, sheet.Lines[1].Amount)
+	assert.Equal(t, 120000.0, sheet.Lines[3].Amount) // 2% of basic
+	assert.Equal(t, 600000.0, sheet.Lines[4].Amount) // two covered slots
+	assert.Equal(t, 2.0, sheet.Lines[4].Quantity)
+	assert.Equal(t, 18000.0, sheet.Lines[5].GSTAmount)
+	assert.Equal(t, 0.0, sheet.Lines[6].GSTAmount)
+
+	// 60L + 0.5L + 1L + 1.2L + 6L + 1L
+	assert.Equal(t, 6970000.0, sheet.AgreementValue)
+	assert.Equal(t, 50000.0, sheet.StatutoryCharges)
+	assert.Equal(t, 343500.0+18000, sheet.TotalGST)
+	assert.Equal(t, 6970000.0+50000+361500, sheet.TotalPayable)
+
+	// Guideline value is higher, so stamp duty follows it; registration is capped
+	assert.Equal(t, 9000000.0, sheet.StampDutyBasis)
+	assert.Equal(t, 630000.0, sheet.StampDuty)
+	assert.Equal(t, 30000.0, sheet.RegistrationFee)
+	assert.Equal(t, sheet.TotalPayable+630000+30000, sheet.GrandTotal)
+	assert.Equal(t, 3, sheet.PriceListVersion)
+}
+
+// TestBuildCostSheetOptions tests optional charges, unit type filters and parking tiers
+func TestBuildCostSheetOptions(t *testing.T) {
+	sheet := &models.CostSheet{UnitID: "u2", Floor: 2, UnitType: "4bhk", Facing: "corner", SBUA: 2000}
+	assert.NoError(t, buildCostSheet(sheet, testPriceList(), &models.CostSheetOptions{OptionalCharges: []string{"modkit"}}))
+
+	codes := map[string]bool{}
+	for _, l := range sheet.Lines {
+		codes[l.Code] = true
+	}
+	assert.True(t, codes["FR1"])
+	assert.True(t, codes["CORNER"])
+	assert.True(t, codes["PENT"])
+	assert.True(t, codes["MODKIT"])
+	assert.False(t, codes["COV"])
+	assert.Equal(t, 0, sheet.ParkingSlots)
+	// Agreement value is the stamp duty basis when there is no guideline value
+	assert.Equal(t, sheet.AgreementValue, sheet.StampDutyBasis)
+
+	sheet = &models.CostSheet{UnitID: "u3", Floor: 1, SBUA: 900}
+	assert.ErrorContains(t, buildCostSheet(sheet, testPriceList(), &models.CostSheetOptions{ParkingType: "stilt"}), "no stilt parking tier")
+}
+
+// TestCheckBookingRate tests that a booking below the cost sheet needs an approved discount
+func TestCheckBookingRate(t *testing.T) {
+	sheet := &models.CostSheet{UnitID: "u1", Floor: 2, SBUA: 1000}
+	assert.NoError(t, buildCostSheet(sheet, testPriceList(), nil))
+
+	assert.NoError(t, checkBookingRate(sheet, 0))
+	assert.NoError(t, checkBookingRate(sheet, 6000))
+	assert.NoError(t, checkBookingRate(sheet, 6200))
+	assert.ErrorContains(t, checkBookingRate(sheet, 5900), "below the 6000.00 per sqft")
+
+	// An approved discount of 1L lets the booking go down to 5900 but no further
+	sheet = &models.CostSheet{UnitID: "u1", Floor: 2, SBUA: 1000}
+	assert.NoError(t, buildCostSheet(sheet, testPriceList(), &models.CostSheetOptions{DiscountAmount: 100000}))
+	assert.NoError(t, checkBookingRate(sheet, 5900))
+	assert.ErrorContains(t, checkBookingRate(sheet, 5899), "below the 5900.00 per sqft")
+}
+
+// TestValidatePriceList tests rule validation
+func TestValidatePriceList(t *testing.T) {
+	req := &models.CreatePriceListRequest{ProjectID: "p1", Name: "v1", BaseRatePerSqft: 5000, BaseGSTRate: 5}
+	req.Rules = []models.PriceListRule{
+		{Category: models.PriceRulePLC, Code: " sea ", Name: "Sea view", ChargeType: models.PriceChargePerSqft, Amount: 200, Attribute: "View", MatchValue: "Sea"},
+	}
+	assert.NoError(t, validatePriceList(req))
+	assert.Equal(t, "SEA", req.Rules[0].Code)
+	assert.Equal(t, "view", req.Rules[0].Attribute)
+	assert.Equal(t, "sea", req.Rules[0].MatchValue)
+
+	req.Rules = append(req.Rules, models.PriceListRule{Category: models.PriceRuleClub, Code: "SEA", Name: "Club", ChargeType: models.PriceChargeLumpsum})
+	assert.ErrorContains(t, validatePriceList(req), "duplicate code")
+
+	req.Rules = []models.PriceListRule{{Category: models.PriceRulePLC, Code: "X", Name: "X", ChargeType: models.PriceChargeLumpsum, Attribute: "facing"}}
+	assert.ErrorContains(t, validatePriceList(req), "match_value is required")
+
+	req.Rules = []models.PriceListRule{{Category: models.PriceRuleFloorRise, Code: "F", Name: "F", ChargeType: models.PriceChargePerSqft, FromFloor: 10, ToFloor: 5}}
+	assert.ErrorContains(t, validatePriceList(req), "invalid floor range")
+
+	req.Rules = []models.PriceListRule{{Category: models.PriceRuleParking, Code: "P", Name: "P", ChargeType: models.PriceChargeLumpsum}}
+	assert.ErrorContains(t, validatePriceList(req), "parking_type is required")
+
+	req.Rules = nil
+	req.BaseRatePerSqft = 0
+	assert.ErrorContains(t, validatePriceList(req), "base_rate_per_sqft")
+}
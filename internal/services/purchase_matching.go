@@ -0,0 +1,621 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// THREE-WAY MATCH
+// ============================================================================
+// Goods receipts against PO lines, and vendor invoice lines matched against
+// PO price and accepted quantity. Mismatched invoices go on hold until their
+// exceptions are resolved; only matched invoices can post to GL.
+
+// Default tolerances used until a tenant configures its own
+const (
+	defaultPriceTolerancePct    = 2.0
+	defaultQuantityTolerancePct = 0.0
+	defaultAmountTolerance      = 1.0
+)
+
+// ============================================================================
+// GOODS RECEIPTS
+// ============================================================================
+
+// CreateGoodsReceipt records a goods receipt note against the lines of a purchase order
+func (s *PurchaseService) CreateGoodsReceipt(tenantID string, grn *models.GoodsReceipt) error {
+	po, err := s.GetPurchaseOrder(tenantID, grn.POID)
+	if err != nil {
+		return err
+	}
+	poLines, err := s.getPOLineItems(tenantID, po.ID)
+	if err != nil {
+		return err
+	}
+	if len(grn.LineItems) == 0 {
+		return fmt.Errorf("goods receipt must have at least one line")
+	}
+
+	grn.ID = uuid.New().String()
+	grn.TenantID = tenantID
+	grn.GRNNumber = fmt.Sprintf("GRN-%s-%s", time.Now().Format("20060102"), grn.ID[:8])
+	if grn.ReceiptDate.IsZero() {
+		grn.ReceiptDate = time.Now()
+	}
+	if grn.Status == "" {
+		grn.Status = "Received"
+	}
+	if grn.QCStatus == "" {
+		grn.QCStatus = "Pending"
+	}
+	grn.CreatedAt = time.Now()
+	grn.UpdatedAt = time.Now()
+	grn.TotalQuantityReceived, grn.TotalQuantityAccepted, grn.TotalQuantityRejected = 0, 0, 0
+
+	for i := range grn.LineItems {
+		line := &grn.LineItems[i]
+		if line.POLineItemID == nil {
+			return fmt.Errorf("goods receipt line %d has no PO line", i+1)
+		}
+		poLine, ok := poLines[*line.POLineItemID]
+		if !ok {
+			return fmt.Errorf("goods receipt line %d does not belong to purchase order %s", i+1, po.PONumber)
+		}
+		if err := settleGRNLineQuantities(line); err != nil {
+			return fmt.Errorf("goods receipt line %d %w", i+1, err)
+		}
+
+		line.ID = uuid.New().String()
+		line.TenantID = tenantID
+		line.GRNID = grn.ID
+		line.LineNumber = i + 1
+		line.POQuantity = poLine.Quantity
+		if line.ProductCode == "" {
+			line.ProductCode = poLine.ProductCode
+		}
+		if line.Description == "" {
+			line.Description = poLine.Description
+		}
+		if line.Unit == "" {
+			line.Unit = poLine.Unit
+		}
+		line.CreatedAt = grn.CreatedAt
+
+		grn.TotalQuantityReceived += line.ReceivedQuantity
+		grn.TotalQuantityAccepted += line.AcceptedQuantity
+		grn.TotalQuantityRejected += line.RejectedQuantity
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO goods_receipts (
+		id, tenant_id, grn_number, po_id, received_date, received_by, total_quantity_received,
+		total_quantity_accepted, total_quantity_rejected, delivery_note_number, vehicle_number,
+		driver_name, driver_phone, remarks, status, qc_status, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		grn.ID, grn.TenantID, grn.GRNNumber, grn.POID, grn.ReceiptDate, grn.ReceivedBy, grn.TotalQuantityReceived,
+		grn.TotalQuantityAccepted, grn.TotalQuantityRejected, grn.DeliveryNoteNumber, grn.VehicleNumber,
+		grn.DriverName, grn.DriverPhone, grn.Remarks, grn.Status, grn.QCStatus, grn.CreatedAt, grn.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create goods receipt: %w", err)
+	}
+
+	for _, line := range grn.LineItems {
+		_, err = tx.Exec(`INSERT INTO grn_line_items (
+			id, tenant_id, grn_id, po_line_item_id, line_number, product_code, description, po_quantity,
+			received_quantity, accepted_quantity, rejected_quantity, unit, rejection_reason, batch_number,
+			expiry_date, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			line.ID, line.TenantID, line.GRNID, line.POLineItemID, line.LineNumber, line.ProductCode, line.Description,
+			line.POQuantity, line.ReceivedQuantity, line.AcceptedQuantity, line.RejectedQuantity, line.Unit,
+			line.RejectionReason, line.BatchNumber, line.ExpiryDate, line.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add goods receipt line %d: %w", line.LineNumber, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit goods receipt: %w", err)
+	}
+	return nil
+}
+
+// settleGRNLineQuantities checks a receipt line's quantities and defaults the
+// accepted quantity to what was received less what was rejected
+func settleGRNLineQuantities(line *models.GRNLineItem) error {
+	if line.ReceivedQuantity <= 0 || line.RejectedQuantity < 0 || line.RejectedQuantity > line.ReceivedQuantity {
+		return fmt.Errorf("has invalid quantities")
+	}
+	usable := line.ReceivedQuantity - line.RejectedQuantity
+	if line.AcceptedQuantity == 0 {
+		line.AcceptedQuantity = usable
+	}
+	if line.AcceptedQuantity < 0 || line.AcceptedQuantity > usable {
+		return fmt.Errorf("accepts %.2f but only %.2f was received and not rejected", line.AcceptedQuantity, usable)
+	}
+	return nil
+}
+
+// ============================================================================
+// TOLERANCES
+// ============================================================================
+
+// GetMatchTolerance returns the tenant's match tolerances, or the defaults
+func (s *PurchaseService) GetMatchTolerance(tenantID string) (*models.PurchaseMatchTolerance, error) {
+	var t models.PurchaseMatchTolerance
+	err := s.DB.QueryRow(`SELECT id, tenant_id, price_tolerance_pct, quantity_tolerance_pct, amount_tolerance,
+		created_at, updated_at FROM purchase_match_tolerances WHERE tenant_id = ?`, tenantID).Scan(
+		&t.ID, &t.TenantID, &t.PriceTolerancePct, &t.QuantityTolerancePct, &t.AmountTolerance,
+		&t.CreatedAt, &t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &models.PurchaseMatchTolerance{
+			TenantID:             tenantID,
+			PriceTolerancePct:    defaultPriceTolerancePct,
+			QuantityTolerancePct: defaultQuantityTolerancePct,
+			AmountTolerance:      defaultAmountTolerance,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch match tolerance: %w", err)
+	}
+	return &t, nil
+}
+
+// UpsertMatchTolerance sets the tenant's match tolerances
+func (s *PurchaseService) UpsertMatchTolerance(tenantID string, req *models.UpsertMatchToleranceRequest) (*models.PurchaseMatchTolerance, error) {
+	if req.PriceTolerancePct < 0 || req.QuantityTolerancePct < 0 || req.AmountTolerance < 0 {
+		return nil, fmt.Errorf("tolerances cannot be negative")
+	}
+
+	existing, err := s.GetMatchTolerance(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.PurchaseMatchTolerance{
+		ID:                   existing.ID,
+		TenantID:             tenantID,
+		PriceTolerancePct:    req.PriceTolerancePct,
+		QuantityTolerancePct: req.QuantityTolerancePct,
+		AmountTolerance:      req.AmountTolerance,
+		CreatedAt:            existing.CreatedAt,
+		UpdatedAt:            time.Now(),
+	}
+	if t.ID != "" {
+		_, err = s.DB.Exec(`UPDATE purchase_match_tolerances SET price_tolerance_pct = ?, quantity_tolerance_pct = ?,
+			amount_tolerance = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+			t.PriceTolerancePct, t.QuantityTolerancePct, t.AmountTolerance, t.UpdatedAt, t.ID, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update match tolerance: %w", err)
+		}
+		return t, nil
+	}
+
+	t.ID = uuid.New().String()
+	t.CreatedAt = t.UpdatedAt
+	_, err = s.DB.Exec(`INSERT INTO purchase_match_tolerances
+		(id, tenant_id, price_tolerance_pct, quantity_tolerance_pct, amount_tolerance, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, tenantID, t.PriceTolerancePct, t.QuantityTolerancePct, t.AmountTolerance, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create match tolerance: %w", err)
+	}
+	return t, nil
+}
+
+// ============================================================================
+// INVOICE MATCHING
+// ============================================================================
+
+// MatchVendorInvoice matches an invoice line by line against PO price and accepted
+// quantity. A clean match approves the invoice; any exception puts it on hold.
+func (s *PurchaseService) MatchVendorInvoice(tenantID, invoiceID string) (*models.InvoiceMatchResult, error) {
+	invoice, err := s.GetVendorInvoice(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == "Rejected" || invoice.Status == "Paid" || invoice.Status == "posted_to_gl" {
+		return nil, fmt.Errorf("invoice is %s and cannot be re-matched", invoice.Status)
+	}
+
+	lines, err := s.getInvoiceLineItems(tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	tolerance, err := s.GetMatchTolerance(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var exceptions []models.InvoiceMatchException
+	if invoice.POID == nil || *invoice.POID == "" {
+		exceptions = append(exceptions, models.InvoiceMatchException{
+			ExceptionType: models.MatchExceptionNoPurchaseOrder,
+			Message:       "Invoice does not reference a purchase order",
+		})
+	} else {
+		po, err := s.GetPurchaseOrder(tenantID, *invoice.POID)
+		if err != nil {
+			return nil, err
+		}
+		if po.VendorID != invoice.VendorID {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				ExceptionType: models.MatchExceptionVendorMismatch,
+				Message:       fmt.Sprintf("Invoice vendor differs from vendor on %s", po.PONumber),
+			})
+		}
+
+		poLines, err := s.getPOLineItems(tenantID, po.ID)
+		if err != nil {
+			return nil, err
+		}
+		received, err := s.getAcceptedQuantities(tenantID, po.ID)
+		if err != nil {
+			return nil, err
+		}
+		invoiced, err := s.getInvoicedQuantities(tenantID, po.ID, invoiceID)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, matchInvoiceLines(invoice, lines, poLines, received, invoiced, tolerance)...)
+	}
+
+	result := &models.InvoiceMatchResult{InvoiceID: invoiceID, Exceptions: exceptions}
+	if len(exceptions) == 0 {
+		result.Matched = true
+		result.MatchedStatus = models.MatchStatusThreeWayMatch
+		result.InvoiceStatus = "Approved"
+	} else {
+		result.MatchedStatus = models.MatchStatusMismatch
+		result.InvoiceStatus = "On_Hold"
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A re-match replaces whatever was still open from the previous attempt
+	if _, err := tx.Exec(`UPDATE invoice_match_exceptions SET status = 'superseded'
+		WHERE tenant_id = ? AND invoice_id = ? AND status = 'open'`, tenantID, invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to supersede match exceptions: %w", err)
+	}
+
+	for i := range result.Exceptions {
+		e := &result.Exceptions[i]
+		e.ID = uuid.New().String()
+		e.TenantID = tenantID
+		e.InvoiceID = invoiceID
+		e.InvoiceNumber = invoice.InvoiceNumber
+		e.VendorID = invoice.VendorID
+		e.Status = "open"
+		e.CreatedAt = time.Now()
+		if _, err := tx.Exec(`INSERT INTO invoice_match_exceptions (
+			id, tenant_id, invoice_id, invoice_line_id, po_line_item_id, exception_type, expected_value,
+			actual_value, variance_pct, message, status, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.ID, e.TenantID, e.InvoiceID, e.InvoiceLineID, e.POLineItemID, e.ExceptionType, e.ExpectedValue,
+			e.ActualValue, e.VariancePct, e.Message, e.Status, e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to record match exception: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE vendor_invoices SET matched_status = ?, three_way_match = ?, status = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`,
+		result.MatchedStatus, result.Matched, result.InvoiceStatus, time.Now(), invoiceID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to update invoice match status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit invoice match: %w", err)
+	}
+	return result, nil
+}
+
+// matchInvoiceLines compares invoice lines with their PO lines. Quantities are checked
+// cumulatively with earlier invoices on the same PO line; under-billing is never an exception.
+func matchInvoiceLines(invoice *models.VendorInvoice, lines []models.PurchaseInvoiceLineItem, poLines map[string]models.POLineItem,
+	accepted, previouslyInvoiced map[string]float64, tolerance *models.PurchaseMatchTolerance) []models.InvoiceMatchException {
+	var exceptions []models.InvoiceMatchException
+	qtyFactor := 1 + tolerance.QuantityTolerancePct/100
+	billed := map[string]float64{}
+	linesTotal := 0.0
+
+	for _, line := range lines {
+		linesTotal += line.LineTotal
+
+		if line.POLineItemID == nil || *line.POLineItemID == "" {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				InvoiceLineID: line.ID,
+				ExceptionType: models.MatchExceptionUnmatchedLine,
+				ActualValue:   line.LineTotal,
+				Message:       fmt.Sprintf("Line %d (%s) has no PO line", line.LineNumber, line.Description),
+			})
+			continue
+		}
+		poLine, ok := poLines[*line.POLineItemID]
+		if !ok {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				InvoiceLineID: line.ID,
+				POLineItemID:  *line.POLineItemID,
+				ExceptionType: models.MatchExceptionUnmatchedLine,
+				ActualValue:   line.LineTotal,
+				Message:       fmt.Sprintf("Line %d references a PO line not on the purchase order", line.LineNumber),
+			})
+			continue
+		}
+
+		if poLine.UnitPrice > 0 {
+			variance := (line.UnitPrice - poLine.UnitPrice) / poLine.UnitPrice * 100
+			if variance > tolerance.PriceTolerancePct+1e-9 {
+				exceptions = append(exceptions, models.InvoiceMatchException{
+					InvoiceLineID: line.ID,
+					POLineItemID:  poLine.ID,
+					ExceptionType: models.MatchExceptionPriceVariance,
+					ExpectedValue: poLine.UnitPrice,
+					ActualValue:   line.UnitPrice,
+					VariancePct:   roundTo2(variance),
+					Message:       fmt.Sprintf("Line %d unit price %.2f exceeds PO price %.2f", line.LineNumber, line.UnitPrice, poLine.UnitPrice),
+				})
+			}
+		}
+
+		billed[poLine.ID] += line.Quantity
+		cumulative := previouslyInvoiced[poLine.ID] + billed[poLine.ID]
+		if cumulative > poLine.Quantity*qtyFactor+1e-9 {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				InvoiceLineID: line.ID,
+				POLineItemID:  poLine.ID,
+				ExceptionType: models.MatchExceptionQuantityOrdered,
+				ExpectedValue: poLine.Quantity,
+				ActualValue:   cumulative,
+				VariancePct:   variancePct(poLine.Quantity, cumulative),
+				Message:       fmt.Sprintf("Line %d bills %.3f %s against %.3f ordered", line.LineNumber, cumulative, poLine.Unit, poLine.Quantity),
+			})
+		}
+		if cumulative > accepted[poLine.ID]*qtyFactor+1e-9 {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				InvoiceLineID: line.ID,
+				POLineItemID:  poLine.ID,
+				ExceptionType: models.MatchExceptionQuantityReceived,
+				ExpectedValue: accepted[poLine.ID],
+				ActualValue:   cumulative,
+				VariancePct:   variancePct(accepted[poLine.ID], cumulative),
+				Message:       fmt.Sprintf("Line %d bills %.3f %s against %.3f received and accepted", line.LineNumber, cumulative, poLine.Unit, accepted[poLine.ID]),
+			})
+		}
+	}
+
+	if len(lines) > 0 {
+		invoiceNet := invoice.InvoiceAmount
+		if math.Abs(invoiceNet-linesTotal) > tolerance.AmountTolerance+1e-9 {
+			exceptions = append(exceptions, models.InvoiceMatchException{
+				ExceptionType: models.MatchExceptionInvoiceTotal,
+				ExpectedValue: roundTo2(linesTotal),
+				ActualValue:   invoiceNet,
+				VariancePct:   variancePct(linesTotal, invoiceNet),
+				Message:       fmt.Sprintf("Invoice amount %.2f differs from sum of lines %.2f", invoiceNet, linesTotal),
+			})
+		}
+	} else {
+		exceptions = append(exceptions, models.InvoiceMatchException{
+			ExceptionType: models.MatchExceptionUnmatchedLine,
+			ActualValue:   invoice.InvoiceAmount,
+			Message:       "Invoice has no lines to match",
+		})
+	}
+
+	return exceptions
+}
+
+func variancePct(expected, actual float64) float64 {
+	if expected == 0 {
+		return 100
+	}
+	return roundTo2((actual - expected) / expected * 100)
+}
+
+// ============================================================================
+// EXCEPTIONS QUEUE
+// ============================================================================
+
+// ListMatchExceptions returns match exceptions, open ones by default
+func (s *PurchaseService) ListMatchExceptions(tenantID, status string) ([]models.InvoiceMatchException, error) {
+	if status == "" {
+		status = "open"
+	}
+	rows, err := s.DB.Query(`SELECT e.id, e.tenant_id, e.invoice_id, COALESCE(vi.invoice_number, ''), COALESCE(vi.vendor_id, ''),
+		COALESCE(e.invoice_line_id, ''), COALESCE(e.po_line_item_id, ''), e.exception_type, e.expected_value,
+		e.actual_value, e.variance_pct, e.message, e.status, e.resolved_by, e.resolved_at,
+		COALESCE(e.resolution_notes, ''), e.created_at
+		FROM invoice_match_exceptions e
+		LEFT JOIN vendor_invoices vi ON vi.id = e.invoice_id
+		WHERE e.tenant_id = ? AND e.status = ?
+		ORDER BY e.created_at`, tenantID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch match exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	var exceptions []models.InvoiceMatchException
+	for rows.Next() {
+		var e models.InvoiceMatchException
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.InvoiceID, &e.InvoiceNumber, &e.VendorID,
+			&e.InvoiceLineID, &e.POLineItemID, &e.ExceptionType, &e.ExpectedValue,
+			&e.ActualValue, &e.VariancePct, &e.Message, &e.Status, &e.ResolvedBy, &e.ResolvedAt,
+			&e.ResolutionNotes, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan match exception: %w", err)
+		}
+		exceptions = append(exceptions, e)
+	}
+	return exceptions, rows.Err()
+}
+
+// ResolveMatchException accepts or rejects an open exception. Rejecting rejects the
+// invoice; once every exception is accepted the invoice is released as an override.
+func (s *PurchaseService) ResolveMatchException(tenantID, exceptionID, userID string, req *models.ResolveMatchExceptionRequest) (*models.InvoiceMatchResult, error) {
+	var invoiceID, status string
+	err := s.DB.QueryRow(`SELECT invoice_id, status FROM invoice_match_exceptions WHERE id = ? AND tenant_id = ?`,
+		exceptionID, tenantID).Scan(&invoiceID, &status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("match exception not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get match exception: %w", err)
+	}
+	if status != "open" {
+		return nil, fmt.Errorf("match exception is already %s", status)
+	}
+
+	resolution := "rejected"
+	if req.Accept {
+		resolution = "accepted"
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE invoice_match_exceptions SET status = ?, resolved_by = ?, resolved_at = ?, resolution_notes = ?
+		WHERE id = ? AND tenant_id = ?`, resolution, userID, now, req.Notes, exceptionID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to resolve match exception: %w", err)
+	}
+
+	result := &models.InvoiceMatchResult{InvoiceID: invoiceID, MatchedStatus: models.MatchStatusMismatch, InvoiceStatus: "On_Hold"}
+	if !req.Accept {
+		if _, err := tx.Exec(`UPDATE invoice_match_exceptions SET status = 'superseded'
+			WHERE tenant_id = ? AND invoice_id = ? AND status = 'open'`, tenantID, invoiceID); err != nil {
+			return nil, fmt.Errorf("failed to supersede match exceptions: %w", err)
+		}
+		result.InvoiceStatus = "Rejected"
+		if _, err := tx.Exec(`UPDATE vendor_invoices SET status = 'Rejected', rejection_reason = ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, req.Notes, now, invoiceID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to reject invoice: %w", err)
+		}
+	} else {
+		var open int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM invoice_match_exceptions WHERE tenant_id = ? AND invoice_id = ? AND status = 'open'`,
+			tenantID, invoiceID).Scan(&open); err != nil {
+			return nil, fmt.Errorf("failed to count open match exceptions: %w", err)
+		}
+		if open == 0 {
+			result.Matched = true
+			result.MatchedStatus = models.MatchStatusOverrideApproved
+			result.InvoiceStatus = "Approved"
+			if _, err := tx.Exec(`UPDATE vendor_invoices SET matched_status = ?, three_way_match = TRUE, status = 'Approved',
+				approved_by = ?, approved_at = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+				result.MatchedStatus, userID, now, now, invoiceID, tenantID); err != nil {
+				return nil, fmt.Errorf("failed to release invoice: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit match exception: %w", err)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// LOOKUPS
+// ============================================================================
+
+func (s *PurchaseService) getPOLineItems(tenantID, poID string) (map[string]models.POLineItem, error) {
+	rows, err := s.DB.Query(`SELECT id, po_id, line_number, COALESCE(product_code, ''), COALESCE(description, ''),
+		quantity, COALESCE(unit, ''), unit_price, line_total
+		FROM po_line_items WHERE po_id = ? AND tenant_id = ?`, poID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PO lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := map[string]models.POLineItem{}
+	for rows.Next() {
+		var line models.POLineItem
+		if err := rows.Scan(&line.ID, &line.POID, &line.LineNumber, &line.ProductCode, &line.Description,
+			&line.Quantity, &line.Unit, &line.UnitPrice, &line.LineTotal); err != nil {
+			return nil, fmt.Errorf("failed to scan PO line: %w", err)
+		}
+		line.TenantID = tenantID
+		lines[line.ID] = line
+	}
+	return lines, rows.Err()
+}
+
+func (s *PurchaseService) getInvoiceLineItems(tenantID, invoiceID string) ([]models.PurchaseInvoiceLineItem, error) {
+	rows, err := s.DB.Query(`SELECT id, invoice_id, po_line_item_id, line_number, COALESCE(description, ''),
+		quantity, unit_price, line_total, tax_amount
+		FROM purchase_invoice_line_items WHERE invoice_id = ? AND tenant_id = ? ORDER BY line_number`, invoiceID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoice lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []models.PurchaseInvoiceLineItem
+	for rows.Next() {
+		var line models.PurchaseInvoiceLineItem
+		if err := rows.Scan(&line.ID, &line.InvoiceID, &line.POLineItemID, &line.LineNumber, &line.Description,
+			&line.Quantity, &line.UnitPrice, &line.LineTotal, &line.TaxAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice line: %w", err)
+		}
+		line.TenantID = tenantID
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// getAcceptedQuantities sums accepted quantities per PO line across the PO's goods receipts
+func (s *PurchaseService) getAcceptedQuantities(tenantID, poID string) (map[string]float64, error) {
+	rows, err := s.DB.Query(`SELECT gl.po_line_item_id, SUM(gl.accepted_quantity)
+		FROM grn_line_items gl
+		JOIN goods_receipts g ON g.id = gl.grn_id
+		WHERE g.tenant_id = ? AND g.po_id = ? AND g.status <> 'Rejected' AND g.deleted_at IS NULL
+		AND gl.po_line_item_id IS NOT NULL
+		GROUP BY gl.po_line_item_id`, tenantID, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch received quantities: %w", err)
+	}
+	return scanQuantityMap(rows)
+}
+
+// getInvoicedQuantities sums quantities billed per PO line on the PO's other live invoices
+func (s *PurchaseService) getInvoicedQuantities(tenantID, poID, excludeInvoiceID string) (map[string]float64, error) {
+	rows, err := s.DB.Query(`SELECT il.po_line_item_id, SUM(il.quantity)
+		FROM purchase_invoice_line_items il
+		JOIN vendor_invoices vi ON vi.id = il.invoice_id
+		WHERE vi.tenant_id = ? AND vi.po_id = ? AND vi.id <> ? AND vi.status <> 'Rejected'
+		AND il.po_line_item_id IS NOT NULL
+		GROUP BY il.po_line_item_id`, tenantID, poID, excludeInvoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoiced quantities: %w", err)
+	}
+	return scanQuantityMap(rows)
+}
+
+func scanQuantityMap(rows *sql.Rows) (map[string]float64, error) {
+	defer rows.Close()
+	quantities := map[string]float64{}
+	for rows.Next() {
+		var id string
+		var qty float64
+		if err := rows.Scan(&id, &qty); err != nil {
+			return nil, fmt.Errorf("failed to scan quantity: %w", err)
+		}
+		quantities[id] = qty
+	}
+	return quantities, rows.Err()
+}
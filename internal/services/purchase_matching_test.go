@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func matchFixture() (map[string]models.POLineItem, *models.PurchaseMatchTolerance) {
+	poLines := map[string]models.POLineItem{
+		"pl1": {ID: "pl1", Quantity: 100, UnitPrice: 350, Unit: "bags"},
+		"pl2": {ID: "pl2", Quantity: 10, UnitPrice: 60000, Unit: "MT"},
+	}
+	tolerance := &models.PurchaseMatchTolerance{PriceTolerancePct: 2, QuantityTolerancePct: 0, AmountTolerance: 1}
+	return poLines, tolerance
+}
+
+// TestMatchInvoiceLinesClean tests an invoice within PO price and received quantity
+func TestMatchInvoiceLinesClean(t *testing.T) {
+	poLines, tolerance := matchFixture()
+	lines := []models.PurchaseInvoiceLineItem{
+		{ID: "l1", LineNumber: 1, POLineItemID: stringPtr("pl1"), Quantity: 50, UnitPrice: 355, LineTotal: 17750},
+		{ID: "l2", LineNumber: 2, POLineItemID: stringPtr("pl2"), Quantity: 4, UnitPrice: 60000, LineTotal: 240000},
+	}
+	invoice := &models.VendorInvoice{InvoiceAmount: 257750}
+	accepted := map[string]float64{"pl1": 60, "pl2": 4}
+
+	exceptions := matchInvoiceLines(invoice, lines, poLines, accepted, map[string]float64{}, tolerance)
+
+	assert.Empty(t, exceptions)
+}
+
+// TestMatchInvoiceLinesExceptions tests price, quantity and total mismatches
+func TestMatchInvoiceLinesExceptions(t *testing.T) {
+	poLines, tolerance := matchFixture()
+	lines := []models.PurchaseInvoiceLineItem{
+		// 3% over PO price
+		{ID: "l1", LineNumber: 1, POLineItemID: stringPtr("pl1"), Quantity: 10, UnitPrice: 360.5, LineTotal: 3605},
+		// 6 MT billed, 2 already invoiced, 7 accepted
+		{ID: "l2", LineNumber: 2, POLineItemID: stringPtr("pl2"), Quantity: 6, UnitPrice: 60000, LineTotal: 360000},
+		{ID: "l3", LineNumber: 3, Quantity: 1, UnitPrice: 500, LineTotal: 500},
+	}
+	invoice := &models.VendorInvoice{InvoiceAmount: 370000}
+	accepted := map[string]float64{"pl1": 10, "pl2": 7}
+	invoiced := map[string]float64{"pl2": 2}
+
+	exceptions := matchInvoiceLines(invoice, lines, poLines, accepted, invoiced, tolerance)
+
+	types := []string{}
+	for _, e := range exceptions {
+		types = append(types, e.ExceptionType)
+	}
+	assert.Equal(t, []string{
+		models.MatchExceptionPriceVariance,
+		models.MatchExceptionQuantityReceived,
+		models.MatchExceptionUnmatchedLine,
+		models.MatchExceptionInvoiceTotal,
+	}, types)
+	assert.Equal(t, 3.0, exceptions[0].VariancePct)
+	assert.Equal(t, 8.0, exceptions[1].ActualValue)
+}
+
+// TestMatchInvoiceLinesOverOrdered tests cumulative billing beyond the ordered quantity
+func TestMatchInvoiceLinesOverOrdered(t *testing.T) {
+	poLines, tolerance := matchFixture()
+	lines := []models.PurchaseInvoiceLineItem{
+		{ID: "l1", LineNumber: 1, POLineItemID: stringPtr("pl1"), Quantity: 60, UnitPrice: 350, LineTotal: 21000},
+		{ID: "l2", LineNumber: 2, POLineItemID: stringPtr("pl1"), Quantity: 50, UnitPrice: 350, LineTotal: 17500},
+	}
+	invoice := &models.VendorInvoice{InvoiceAmount: 38500}
+	accepted := map[string]float64{"pl1": 110}
+
+	exceptions := matchInvoiceLines(invoice, lines, poLines, accepted, map[string]float64{}, tolerance)
+
+	assert.Len(t, exceptions, 1)
+	assert.Equal(t, models.MatchExceptionQuantityOrdered, exceptions[0].ExceptionType)
+	assert.Equal(t, "l2", exceptions[0].InvoiceLineID)
+
+	// A 10% quantity tolerance lets the over-delivery through
+	tolerance.QuantityTolerancePct = 10
+	assert.Empty(t, matchInvoiceLines(invoice, lines, poLines, accepted, map[string]float64{}, tolerance))
+}
+
+// TestSettleGRNLineQuantities tests accepted quantity defaults and limits on a receipt line
+func TestSettleGRNLineQuantities(t *testing.T) {
+	line := &models.GRNLineItem{ReceivedQuantity: 100, RejectedQuantity: 5}
+	assert.NoError(t, settleGRNLineQuantities(line))
+	assert.Equal(t, 95.0, line.AcceptedQuantity)
+
+	assert.NoError(t, settleGRNLineQuantities(&models.GRNLineItem{ReceivedQuantity: 100, RejectedQuantity: 5, AcceptedQuantity: 90}))
+	assert.Error(t, settleGRNLineQuantities(&models.GRNLineItem{ReceivedQuantity: 100, RejectedQuantity: 5, AcceptedQuantity: 96}))
+	assert.Error(t, settleGRNLineQuantities(&models.GRNLineItem{ReceivedQuantity: 100, AcceptedQuantity: -1}))
+	assert.Error(t, settleGRNLineQuantities(&models.GRNLineItem{ReceivedQuantity: 10, RejectedQuantity: 11}))
+}
@@ -0,0 +1,1285 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// ACCOUNTS PAYABLE
+// ============================================================================
+// AP ageing, vendor payment runs with maker-checker approval, bank bulk
+// payment files and TDS deducted on vendor payments
+
+// Vendor invoice statuses that carry an open payable
+var payableInvoiceStatuses = []string{"posted_to_gl", "Partially_Paid"}
+
+// defaultPaymentTermDays is used when neither the invoice nor the vendor carries terms
+const defaultPaymentTermDays = 30
+
+var paymentTermDaysPattern = regexp.MustCompile(`\d+`)
+
+// ============================================================================
+// AP AGEING
+// ============================================================================
+
+// GetOpenPayables returns vendor invoices with an unpaid balance as of a date.
+// Payments, TDS already deducted and applied back-charges all reduce the balance.
+func (s *PurchaseService) GetOpenPayables(tenantID, vendorID, projectID string, asOf time.Time) ([]models.PayableInvoice, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(payableInvoiceStatuses)), ", ")
+	query := `SELECT vi.id, vi.invoice_number, vi.vendor_id, COALESCE(v.name, ''), COALESCE(v.payment_terms, ''),
+		COALESCE(vi.project_id, ''), COALESCE(p.project_name, ''), vi.invoice_date, vi.due_date,
+		vi.invoice_amount - vi.discount_amount, vi.total_payable, vi.status,
+		COALESCE(paid.amount, 0) + COALESCE(tds.amount, 0), COALESCE(bc.amount, 0)
+		FROM vendor_invoices vi
+		LEFT JOIN vendors v ON v.id = vi.vendor_id
+		LEFT JOIN property_projects p ON p.id = vi.project_id
+		LEFT JOIN (SELECT invoice_id, SUM(payment_amount) AS amount FROM purchase_payments
+			WHERE tenant_id = ? AND payment_date <= ? GROUP BY invoice_id) paid ON paid.invoice_id = vi.id
+		LEFT JOIN (SELECT invoice_id, SUM(tds_amount) AS amount FROM tds_deductions
+			WHERE tenant_id = ? AND deduction_date <= ? GROUP BY invoice_id) tds ON tds.invoice_id = vi.id
+		LEFT JOIN (SELECT invoice_id, SUM(amount) AS amount FROM vendor_back_charges
+			WHERE tenant_id = ? AND status = ? AND created_at <= ? GROUP BY invoice_id) bc ON bc.invoice_id = vi.id
+		WHERE vi.tenant_id = ? AND vi.invoice_date <= ? AND vi.status IN (` + placeholders + `)`
+	args := []interface{}{tenantID, asOf, tenantID, asOf, tenantID, models.VendorBackChargeApplied, asOf, tenantID, asOf}
+	for _, status := range payableInvoiceStatuses {
+		args = append(args, status)
+	}
+	if vendorID != "" {
+		query += " AND vi.vendor_id = ?"
+		args = append(args, vendorID)
+	}
+	if projectID != "" {
+		query += " AND vi.project_id = ?"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY vi.vendor_id, vi.invoice_date"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open payables: %w", err)
+	}
+	defer rows.Close()
+
+	var payables []models.PayableInvoice
+	for rows.Next() {
+		var item models.PayableInvoice
+		var paymentTerms string
+		var dueDate sql.NullTime
+		if err := rows.Scan(&item.InvoiceID, &item.InvoiceNumber, &item.VendorID, &item.VendorName, &paymentTerms,
+			&item.ProjectID, &item.ProjectName, &item.InvoiceDate, &dueDate,
+			&item.TaxableAmount, &item.TotalPayable, &item.Status, &item.AmountPaid, &item.BackCharged); err != nil {
+			return nil, fmt.Errorf("failed to scan payable: %w", err)
+		}
+
+		item.Outstanding = roundTo2(item.TotalPayable - item.AmountPaid - item.BackCharged)
+		if item.Outstanding <= 0 {
+			continue
+		}
+		var due *time.Time
+		if dueDate.Valid {
+			due = &dueDate.Time
+		}
+		item.DueDate = payableDueDate(item.InvoiceDate, due, paymentTerms)
+		item.DaysOverdue = daysPastDue(item.DueDate, asOf)
+		item.Bucket = ageingBucket(item.DaysOverdue)
+		payables = append(payables, item)
+	}
+
+	return payables, rows.Err()
+}
+
+// GetAPAgeingReport buckets open payables by days past due, grouped by vendor or project
+func (s *PurchaseService) GetAPAgeingReport(tenantID, projectID, groupBy string, asOf time.Time) (*models.APAgeingReport, error) {
+	if groupBy != "project" {
+		groupBy = "vendor"
+	}
+
+	payables, err := s.GetOpenPayables(tenantID, "", projectID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.APAgeingReport{
+		AsOfDate: asOf,
+		GroupBy:  groupBy,
+		Rows:     buildAPAgeingRows(payables, groupBy),
+	}
+	report.Totals.GroupKey = "total"
+	report.Totals.GroupName = "Total"
+	for _, row := range report.Rows {
+		report.Totals.Current += row.Current
+		report.Totals.Bucket0To30 += row.Bucket0To30
+		report.Totals.Bucket31To60 += row.Bucket31To60
+		report.Totals.Bucket61To90 += row.Bucket61To90
+		report.Totals.Bucket90Plus += row.Bucket90Plus
+		report.Totals.TotalOverdue += row.TotalOverdue
+		report.Totals.TotalOutstanding += row.TotalOutstanding
+		report.Totals.InvoiceCount += row.InvoiceCount
+	}
+
+	return report, nil
+}
+
+// payableDueDate returns the invoice due date, falling back to the vendor's
+// payment terms ("Net 45", "30 days") and then the default term
+func payableDueDate(invoiceDate time.Time, dueDate *time.Time, paymentTerms string) time.Time {
+	if dueDate != nil && !dueDate.IsZero() {
+		return *dueDate
+	}
+	days := defaultPaymentTermDays
+	if match := paymentTermDaysPattern.FindString(paymentTerms); match != "" {
+		if n, err := strconv.Atoi(match); err == nil {
+			days = n
+		}
+	}
+	return invoiceDate.AddDate(0, 0, days)
+}
+
+// buildAPAgeingRows aggregates open payables into ageing rows
+func buildAPAgeingRows(payables []models.PayableInvoice, groupBy string) []models.APAgeingRow {
+	rows := map[string]*models.APAgeingRow{}
+	order := []string{}
+
+	for _, item := range payables {
+		key, name := item.VendorID, item.VendorName
+		if groupBy == "project" {
+			key, name = item.ProjectID, item.ProjectName
+			if key == "" {
+				name = "Unallocated"
+			}
+		}
+
+		row, ok := rows[key]
+		if !ok {
+			row = &models.APAgeingRow{GroupKey: key, GroupName: name}
+			rows[key] = row
+			order = append(order, key)
+		}
+
+		switch item.Bucket {
+		case models.AgeingBucketCurrent:
+			row.Current += item.Outstanding
+		case models.AgeingBucket0To30:
+			row.Bucket0To30 += item.Outstanding
+		case models.AgeingBucket31To60:
+			row.Bucket31To60 += item.Outstanding
+		case models.AgeingBucket61To90:
+			row.Bucket61To90 += item.Outstanding
+		default:
+			row.Bucket90Plus += item.Outstanding
+		}
+		if item.Bucket != models.AgeingBucketCurrent {
+			row.TotalOverdue += item.Outstanding
+		}
+		row.TotalOutstanding += item.Outstanding
+		row.InvoiceCount++
+	}
+
+	result := make([]models.APAgeingRow, 0, len(order))
+	for _, key := range order {
+		result = append(result, *rows[key])
+	}
+	return result
+}
+
+// ============================================================================
+// VENDOR BANK ACCOUNTS & TDS SECTIONS
+// ============================================================================
+
+// UpsertVendorBankAccount sets the vendor's primary bank account used in payment files
+func (s *PurchaseService) UpsertVendorBankAccount(tenantID, vendorID string, req *models.UpsertVendorBankAccountRequest) (*models.VendorBankAccount, error) {
+	account := &models.VendorBankAccount{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		VendorID:      vendorID,
+		AccountName:   req.AccountName,
+		AccountNumber: req.AccountNumber,
+		IFSCCode:      strings.ToUpper(req.IFSCCode),
+		BankName:      req.BankName,
+		IsPrimary:     true,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE vendor_bank_accounts SET is_primary = FALSE, updated_at = ?
+		WHERE tenant_id = ? AND vendor_id = ? AND is_primary = TRUE`, time.Now(), tenantID, vendorID); err != nil {
+		return nil, fmt.Errorf("failed to clear primary bank account: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO vendor_bank_accounts (
+		id, tenant_id, vendor_id, account_name, account_number, ifsc_code, bank_name, is_primary, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account.ID, account.TenantID, account.VendorID, account.AccountName, account.AccountNumber,
+		account.IFSCCode, account.BankName, account.IsPrimary, account.CreatedAt, account.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vendor bank account: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit vendor bank account: %w", err)
+	}
+	return account, nil
+}
+
+// SetVendorTDSSection sets the TDS section and rate deducted on the vendor's payments
+func (s *PurchaseService) SetVendorTDSSection(tenantID, vendorID string, req *models.SetVendorTDSSectionRequest) (*models.VendorTDSSection, error) {
+	if req.Rate <= 0 || req.Rate > 100 {
+		return nil, fmt.Errorf("tds rate must be between 0 and 100")
+	}
+
+	section := &models.VendorTDSSection{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		VendorID:    vendorID,
+		SectionCode: strings.ToUpper(req.SectionCode),
+		Rate:        req.Rate,
+		PAN:         strings.ToUpper(req.PAN),
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE vendor_tds_sections SET is_active = FALSE, updated_at = ?
+		WHERE tenant_id = ? AND vendor_id = ? AND is_active = TRUE`, time.Now(), tenantID, vendorID); err != nil {
+		return nil, fmt.Errorf("failed to deactivate tds section: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO vendor_tds_sections (
+		id, tenant_id, vendor_id, section_code, rate, pan, is_active, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		section.ID, section.TenantID, section.VendorID, section.SectionCode, section.Rate,
+		section.PAN, section.IsActive, section.CreatedAt, section.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tds section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tds section: %w", err)
+	}
+	return section, nil
+}
+
+func (s *PurchaseService) getVendorBankAccounts(tenantID string) (map[string]models.VendorBankAccount, error) {
+	rows, err := s.DB.Query(`SELECT id, vendor_id, account_name, account_number, ifsc_code, COALESCE(bank_name, '')
+		FROM vendor_bank_accounts WHERE tenant_id = ? AND is_primary = TRUE`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendor bank accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := map[string]models.VendorBankAccount{}
+	for rows.Next() {
+		var a models.VendorBankAccount
+		if err := rows.Scan(&a.ID, &a.VendorID, &a.AccountName, &a.AccountNumber, &a.IFSCCode, &a.BankName); err != nil {
+			return nil, fmt.Errorf("failed to scan vendor bank account: %w", err)
+		}
+		accounts[a.VendorID] = a
+	}
+	return accounts, rows.Err()
+}
+
+func (s *PurchaseService) getVendorTDSSections(tenantID string) (map[string]models.VendorTDSSection, error) {
+	rows, err := s.DB.Query(`SELECT id, vendor_id, section_code, rate, COALESCE(pan, '')
+		FROM vendor_tds_sections WHERE tenant_id = ? AND is_active = TRUE`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendor tds sections: %w", err)
+	}
+	defer rows.Close()
+
+	sections := map[string]models.VendorTDSSection{}
+	for rows.Next() {
+		var sec models.VendorTDSSection
+		if err := rows.Scan(&sec.ID, &sec.VendorID, &sec.SectionCode, &sec.Rate, &sec.PAN); err != nil {
+			return nil, fmt.Errorf("failed to scan vendor tds section: %w", err)
+		}
+		sections[sec.VendorID] = sec
+	}
+	return sections, rows.Err()
+}
+
+// ============================================================================
+// BANK PAYMENT FORMATS
+// ============================================================================
+
+// CreateBankPaymentFormat configures a bank's bulk payment file layout
+func (s *PurchaseService) CreateBankPaymentFormat(tenantID string, req *models.CreateBankPaymentFormatRequest) (*models.BankPaymentFormat, error) {
+	format := &models.BankPaymentFormat{
+		ID:                 uuid.New().String(),
+		TenantID:           tenantID,
+		BankCode:           strings.ToUpper(req.BankCode),
+		FormatName:         req.FormatName,
+		FileType:           strings.ToLower(req.FileType),
+		Delimiter:          req.Delimiter,
+		IncludeHeader:      req.IncludeHeader,
+		Columns:            req.Columns,
+		DateFormat:         req.DateFormat,
+		DebitAccountNumber: req.DebitAccountNumber,
+		IsActive:           true,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	applyBankFormatDefaults(format)
+	if err := validateBankFormat(format); err != nil {
+		return nil, err
+	}
+
+	columns, err := json.Marshal(format.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode columns: %w", err)
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO bank_payment_formats (
+		id, tenant_id, bank_code, format_name, file_type, delimiter, include_header, columns,
+		date_format, debit_account_number, is_active, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		format.ID, format.TenantID, format.BankCode, format.FormatName, format.FileType, format.Delimiter,
+		format.IncludeHeader, string(columns), format.DateFormat, format.DebitAccountNumber,
+		format.IsActive, format.CreatedAt, format.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bank payment format: %w", err)
+	}
+	return format, nil
+}
+
+// ListBankPaymentFormats returns the active bank payment formats
+func (s *PurchaseService) ListBankPaymentFormats(tenantID string) ([]models.BankPaymentFormat, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, bank_code, format_name, file_type, delimiter, include_header,
+		columns, date_format, debit_account_number, is_active, created_at, updated_at
+		FROM bank_payment_formats WHERE tenant_id = ? AND is_active = TRUE ORDER BY bank_code, format_name`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bank payment formats: %w", err)
+	}
+	defer rows.Close()
+
+	var formats []models.BankPaymentFormat
+	for rows.Next() {
+		format, err := scanBankPaymentFormat(rows)
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, *format)
+	}
+	return formats, rows.Err()
+}
+
+func (s *PurchaseService) getBankPaymentFormat(tenantID, formatID string) (*models.BankPaymentFormat, error) {
+	row := s.DB.QueryRow(`SELECT id, tenant_id, bank_code, format_name, file_type, delimiter, include_header,
+		columns, date_format, debit_account_number, is_active, created_at, updated_at
+		FROM bank_payment_formats WHERE id = ? AND tenant_id = ?`, formatID, tenantID)
+	format, err := scanBankPaymentFormat(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bank payment format not found")
+	}
+	return format, err
+}
+
+func scanBankPaymentFormat(scanner interface{ Scan(...interface{}) error }) (*models.BankPaymentFormat, error) {
+	var format models.BankPaymentFormat
+	var columns string
+	if err := scanner.Scan(&format.ID, &format.TenantID, &format.BankCode, &format.FormatName, &format.FileType,
+		&format.Delimiter, &format.IncludeHeader, &columns, &format.DateFormat, &format.DebitAccountNumber,
+		&format.IsActive, &format.CreatedAt, &format.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan bank payment format: %w", err)
+	}
+	if err := json.Unmarshal([]byte(columns), &format.Columns); err != nil {
+		return nil, fmt.Errorf("failed to decode bank format columns: %w", err)
+	}
+	return &format, nil
+}
+
+// applyBankFormatDefaults fills the delimiter and date format for the file type
+func applyBankFormatDefaults(format *models.BankPaymentFormat) {
+	if format.FileType == "" {
+		format.FileType = "csv"
+	}
+	if format.Delimiter == "" {
+		format.Delimiter = ","
+		if format.FileType == "neft" {
+			format.Delimiter = "|"
+		}
+	}
+	if format.DateFormat == "" {
+		format.DateFormat = "DD/MM/YYYY"
+	}
+}
+
+func validateBankFormat(format *models.BankPaymentFormat) error {
+	if format.FileType != "csv" && format.FileType != "neft" {
+		return fmt.Errorf("file_type must be csv or neft")
+	}
+	if len([]rune(format.Delimiter)) != 1 {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+	if len(format.Columns) == 0 {
+		return fmt.Errorf("at least one column is required")
+	}
+	for _, column := range format.Columns {
+		if _, ok := bankFileColumnHeaders[column]; !ok {
+			return fmt.Errorf("unknown bank file column: %s", column)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// PAYMENT RUNS
+// ============================================================================
+
+// CreatePaymentProposal selects open invoices by due date, vendor or project into
+// a proposed payment run, computing the TDS to deduct on each
+func (s *PurchaseService) CreatePaymentProposal(tenantID, userID string, req *models.CreatePaymentProposalRequest) (*models.VendorPaymentRun, error) {
+	format, err := s.getBankPaymentFormat(tenantID, req.BankFormatID)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentDate := time.Now()
+	if req.PaymentDate != nil {
+		paymentDate = *req.PaymentDate
+	}
+	method := req.PaymentMethod
+	if method == "" {
+		method = "NEFT"
+	}
+
+	payables, err := s.GetOpenPayables(tenantID, req.VendorID, req.ProjectID, paymentDate)
+	if err != nil {
+		return nil, err
+	}
+	inOpenRuns, err := s.getInvoicesInOpenRuns(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	bankAccounts, err := s.getVendorBankAccounts(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tdsSections, err := s.getVendorTDSSections(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tdsDeducted, err := s.getTDSDeductedByInvoice(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tdsAssessed, err := s.getTDSAssessedInvoices(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.VendorPaymentRun{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		RunNumber:     fmt.Sprintf("PRUN-%s-%s", time.Now().Format("20060102"), strings.ToUpper(uuid.New().String()[:8])),
+		Status:        "proposed",
+		DueOnOrBefore: req.DueOnOrBefore,
+		VendorID:      req.VendorID,
+		ProjectID:     req.ProjectID,
+		PaymentDate:   paymentDate,
+		PaymentMethod: method,
+		BankFormatID:  format.ID,
+		CreatedBy:     userID,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	for _, item := range payables {
+		if req.DueOnOrBefore != nil && item.DueDate.After(*req.DueOnOrBefore) {
+			continue
+		}
+		if inOpenRuns[item.InvoiceID] {
+			run.Skipped = append(run.Skipped, item.InvoiceNumber+": already in an open payment run")
+			continue
+		}
+		if _, ok := bankAccounts[item.VendorID]; !ok {
+			run.Skipped = append(run.Skipped, item.InvoiceNumber+": vendor has no bank account")
+			continue
+		}
+
+		line := models.VendorPaymentRunLine{
+			ID:            uuid.New().String(),
+			TenantID:      tenantID,
+			RunID:         run.ID,
+			InvoiceID:     item.InvoiceID,
+			InvoiceNumber: item.InvoiceNumber,
+			VendorID:      item.VendorID,
+			VendorName:    item.VendorName,
+			DueDate:       item.DueDate,
+			GrossAmount:   item.Outstanding,
+			Status:        "proposed",
+			CreatedAt:     time.Now(),
+		}
+		if section, ok := tdsSections[item.VendorID]; ok && !tdsAssessed[item.InvoiceID] {
+			line.TDSSection = section.SectionCode
+			line.TDSRate = section.Rate
+			line.TDSAmount = vendorTDSDue(item.TaxableAmount, section.Rate, tdsDeducted[item.InvoiceID], item.Outstanding)
+		}
+		line.NetAmount = roundTo2(line.GrossAmount - line.TDSAmount)
+
+		run.Lines = append(run.Lines, line)
+		run.TotalGross += line.GrossAmount
+		run.TotalTDS += line.TDSAmount
+		run.TotalNet += line.NetAmount
+	}
+	if len(run.Lines) == 0 {
+		return nil, fmt.Errorf("no payable invoices match the proposal criteria")
+	}
+	run.LineCount = len(run.Lines)
+	run.TotalGross = roundTo2(run.TotalGross)
+	run.TotalTDS = roundTo2(run.TotalTDS)
+	run.TotalNet = roundTo2(run.TotalNet)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO vendor_payment_runs (
+		id, tenant_id, run_number, status, due_on_or_before, vendor_id, project_id, payment_date, payment_method,
+		bank_format_id, total_gross, total_tds, total_net, line_count, created_by, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.TenantID, run.RunNumber, run.Status, run.DueOnOrBefore, nullIfEmpty(run.VendorID), nullIfEmpty(run.ProjectID),
+		run.PaymentDate, run.PaymentMethod, run.BankFormatID, run.TotalGross, run.TotalTDS, run.TotalNet,
+		run.LineCount, run.CreatedBy, run.CreatedAt, run.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment run: %w", err)
+	}
+
+	for _, line := range run.Lines {
+		_, err = tx.Exec(`INSERT INTO vendor_payment_run_lines (
+			id, tenant_id, run_id, invoice_id, invoice_number, vendor_id, vendor_name, due_date,
+			gross_amount, tds_section, tds_rate, tds_amount, net_amount, status, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			line.ID, line.TenantID, line.RunID, line.InvoiceID, line.InvoiceNumber, line.VendorID, line.VendorName,
+			line.DueDate, line.GrossAmount, line.TDSSection, line.TDSRate, line.TDSAmount, line.NetAmount,
+			line.Status, line.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payment run line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit payment run: %w", err)
+	}
+	return run, nil
+}
+
+// ApprovePaymentRun approves or rejects a proposed run. The approver must not be the user who proposed it.
+func (s *PurchaseService) ApprovePaymentRun(tenantID, runID, userID string, req *models.ApprovePaymentRunRequest) (*models.VendorPaymentRun, error) {
+	run, err := s.GetPaymentRun(tenantID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != "proposed" {
+		return nil, fmt.Errorf("payment run is %s, only proposed runs can be approved", run.Status)
+	}
+	if run.CreatedBy == userID {
+		return nil, fmt.Errorf("payment run cannot be approved by the user who proposed it")
+	}
+
+	status := "rejected"
+	if req.Approve {
+		status = "approved"
+	}
+	now := time.Now()
+	_, err = s.DB.Exec(`UPDATE vendor_payment_runs SET status = ?, approved_by = ?, approved_at = ?, approval_comment = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = 'proposed'`,
+		status, userID, now, req.Comment, now, runID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment run: %w", err)
+	}
+
+	run.Status = status
+	run.ApprovedBy = &userID
+	run.ApprovedAt = &now
+	run.ApprovalComment = req.Comment
+	return run, nil
+}
+
+// ProcessPaymentRun pays an approved run: records each payment net of TDS, posts it
+// through PostPaymentToGL, books the TDS deduction and generates the bank file.
+// A run in which no line could be paid is marked failed and gets no bank file. When
+// the bank file cannot be produced after lines were paid, the run is left
+// bank_file_failed with the error; processing it again pays any lines still
+// proposed and builds the file from every paid line.
+func (s *PurchaseService) ProcessPaymentRun(tenantID, runID, userID string) (*models.VendorPaymentRun, error) {
+	run, err := s.GetPaymentRun(tenantID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != "approved" && run.Status != "bank_file_failed" {
+		return nil, fmt.Errorf("payment run is %s, only approved runs can be processed", run.Status)
+	}
+
+	format, err := s.getBankPaymentFormat(tenantID, run.BankFormatID)
+	if err != nil {
+		return nil, err
+	}
+	bankAccounts, err := s.getVendorBankAccounts(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	vendors, err := s.getVendorsByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the run so that concurrent requests cannot pay it twice
+	res, err := s.DB.Exec(`UPDATE vendor_payment_runs SET status = 'processing', failure_reason = NULL, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status IN ('approved', 'bank_file_failed')`, time.Now(), runID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim payment run: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, fmt.Errorf("payment run is already being processed")
+	}
+
+	var fileRows []bankFileRow
+	for i := range run.Lines {
+		line := &run.Lines[i]
+		account, ok := bankAccounts[line.VendorID]
+		switch line.Status {
+		case "paid":
+			// Paid by an earlier attempt whose bank file failed
+			if !ok {
+				return nil, s.failPaymentRunFile(run, fmt.Errorf("vendor %s of paid invoice %s has no bank account",
+					line.VendorName, line.InvoiceNumber))
+			}
+		case "proposed":
+			if !ok {
+				s.failRunLine(line, "vendor has no bank account")
+				continue
+			}
+			if err := s.payRunLine(tenantID, run, i+1, line, userID); err != nil {
+				s.failRunLine(line, err.Error())
+				continue
+			}
+		default:
+			continue
+		}
+
+		vendor := vendors[line.VendorID]
+		fileRows = append(fileRows, bankFileRow{
+			PaymentMode:   run.PaymentMethod,
+			Beneficiary:   account.AccountName,
+			AccountNumber: account.AccountNumber,
+			IFSC:          account.IFSCCode,
+			BankName:      account.BankName,
+			Amount:        line.NetAmount,
+			PaymentDate:   run.PaymentDate,
+			Reference:     line.InvoiceNumber,
+			VendorCode:    vendor.VendorCode,
+			Email:         vendor.Email,
+		})
+	}
+
+	if len(fileRows) == 0 {
+		if _, err := s.DB.Exec(`UPDATE vendor_payment_runs SET status = 'failed', updated_at = ? WHERE id = ? AND tenant_id = ?`,
+			time.Now(), runID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update payment run: %w", err)
+		}
+		run.Status = "failed"
+		return run, nil
+	}
+
+	content, err := buildBankFile(format, fileRows)
+	if err != nil {
+		return nil, s.failPaymentRunFile(run, err)
+	}
+	extension := "csv"
+	if format.FileType == "neft" {
+		extension = "txt"
+	}
+	fileName := fmt.Sprintf("%s_%s.%s", format.BankCode, run.RunNumber, extension)
+
+	now := time.Now()
+	_, err = s.DB.Exec(`UPDATE vendor_payment_runs SET status = 'processed', processed_at = ?, bank_file_name = ?,
+		bank_file_content = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		now, fileName, content, now, runID, tenantID)
+	if err != nil {
+		return nil, s.failPaymentRunFile(run, fmt.Errorf("failed to update payment run: %w", err))
+	}
+
+	run.Status = "processed"
+	run.ProcessedAt = &now
+	run.BankFileName = fileName
+	return run, nil
+}
+
+// payRunLine records and posts the payment and TDS deduction for one run line in a
+// single transaction, so a line that fails leaves no payment or GL entry behind
+func (s *PurchaseService) payRunLine(tenantID string, run *models.VendorPaymentRun, seq int, line *models.VendorPaymentRunLine, userID string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	paymentID := uuid.New().String()
+	paymentNumber := fmt.Sprintf("%s-%03d", run.RunNumber, seq)
+	if _, err := tx.Exec(`INSERT INTO purchase_payments (
+		id, tenant_id, invoice_id, payment_number, payment_date, payment_amount, payment_method,
+		reference_number, payment_status, paid_by, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 'Processed', ?, ?, ?)`,
+		paymentID, tenantID, line.InvoiceID, paymentNumber, run.PaymentDate, line.NetAmount, run.PaymentMethod,
+		run.RunNumber, userID, time.Now(), time.Now()); err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	journalEntryID, err := s.postPaymentToGL(tx, tenantID, paymentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if line.TDSAmount > 0 {
+		base := roundTo2(line.TDSAmount * 100 / line.TDSRate)
+		deductionID, err := s.recordTDSDeduction(tx, tenantID, paymentID, line, base, run.PaymentDate, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordPaymentTDSInLedger(tx, tenantID, paymentID, deductionID, line, base, run.PaymentDate, userID); err != nil {
+			return err
+		}
+	}
+
+	status := "Paid"
+	if invoiceBalance(tx, tenantID, line.InvoiceID) > 0.005 {
+		status = "Partially_Paid"
+	}
+	if _, err := tx.Exec(`UPDATE vendor_invoices SET status = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		status, time.Now(), line.InvoiceID, tenantID); err != nil {
+		return fmt.Errorf("failed to update invoice status: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE vendor_payment_run_lines SET status = 'paid', payment_id = ?, journal_entry_id = ?
+		WHERE id = ? AND tenant_id = ?`, paymentID, journalEntryID, line.ID, tenantID); err != nil {
+		return fmt.Errorf("failed to update payment run line: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payment: %w", err)
+	}
+
+	line.Status = "paid"
+	line.PaymentID = &paymentID
+	line.JournalEntryID = &journalEntryID
+	return nil
+}
+
+// recordTDSDeduction saves the deduction and moves it from AP to TDS payable in the GL.
+// paymentID is empty when TDS is deducted on crediting the invoice.
+func (s *PurchaseService) recordTDSDeduction(db glExecer, tenantID, paymentID string, line *models.VendorPaymentRunLine, baseAmount float64, deductionDate time.Time, userID string) (string, error) {
+	deduction := &models.TDSDeduction{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		VendorID:      line.VendorID,
+		InvoiceID:     line.InvoiceID,
+		PaymentID:     paymentID,
+		SectionCode:   line.TDSSection,
+		Rate:          line.TDSRate,
+		BaseAmount:    baseAmount,
+		TDSAmount:     line.TDSAmount,
+		DeductionDate: deductionDate,
+		Status:        "pending_deposit",
+		CreatedAt:     time.Now(),
+	}
+	if _, err := db.Exec(`INSERT INTO tds_deductions (
+		id, tenant_id, vendor_id, invoice_id, payment_id, section_code, rate, base_amount, tds_amount,
+		deduction_date, status, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		deduction.ID, deduction.TenantID, deduction.VendorID, deduction.InvoiceID, deduction.PaymentID,
+		deduction.SectionCode, deduction.Rate, deduction.BaseAmount, deduction.TDSAmount,
+		deduction.DeductionDate, deduction.Status, deduction.CreatedAt); err != nil {
+		return "", fmt.Errorf("failed to record tds deduction: %w", err)
+	}
+
+	entryID := fmt.Sprintf("JE-PO-TDS-%s", paymentID)
+	description := fmt.Sprintf("TDS u/s %s on payment to %s", line.TDSSection, line.VendorName)
+	if paymentID == "" {
+		entryID = fmt.Sprintf("JE-PO-TDS-INV-%s", line.InvoiceID)
+		description = fmt.Sprintf("TDS u/s %s on invoice from %s", line.TDSSection, line.VendorName)
+	}
+	reference := line.InvoiceNumber
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       deductionDate,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Purchase_TDS",
+		ReferenceID:     &deduction.ID,
+		Description:     description,
+		Amount:          deduction.TDSAmount,
+		Narration:       fmt.Sprintf("TDS @ %.2f%% on invoice %s", line.TDSRate, line.InvoiceNumber),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-ACCOUNTS-PAYABLE", DebitAmount: deduction.TDSAmount, Description: fmt.Sprintf("TDS deducted - %s", line.VendorName)},
+		{AccountID: "ACC-TDS-PAYABLE", CreditAmount: deduction.TDSAmount, Description: fmt.Sprintf("TDS payable u/s %s", line.TDSSection)},
+	}
+	if err := postBalancedEntry(db, tenantID, entry, lines, userID); err != nil {
+		return "", err
+	}
+	return deduction.ID, nil
+}
+
+// applyInvoiceTDS runs a posted invoice through the TDS engine under the vendor's
+// section. Any TDS is booked against the invoice straight away, so payment runs
+// pay it net and do not deduct again. It runs in the transaction that posts the invoice.
+func (s *PurchaseService) applyInvoiceTDS(tx *sql.Tx, tenantID string, invoice *models.VendorInvoice, vendor *models.Vendor, userID string) error {
+	sections, err := s.getVendorTDSSections(tenantID)
+	if err != nil {
+		return err
+	}
+	section, ok := sections[vendor.ID]
+	if !ok {
+		return nil
+	}
+
+	pan := section.PAN
+	if pan == "" {
+		pan = panFromGSTIN(vendor.TaxID)
+	}
+	entry, err := NewTDSService(s.DB).DeductTx(tx, tenantID, &models.TDSDeductionInput{
+		SourceType:      models.TDSSourceVendorInvoice,
+		SourceID:        invoice.ID,
+		PayeeType:       models.TDSPayeeVendor,
+		PayeeID:         vendor.ID,
+		PayeeName:       vendor.Name,
+		PAN:             pan,
+		NonResident:     isNonResidentVendor(vendor),
+		Section:         section.SectionCode,
+		RateOverride:    section.Rate,
+		Amount:          invoice.InvoiceAmount - invoice.DiscountAmount,
+		TransactionDate: invoice.InvoiceDate,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deduct tds: %w", err)
+	}
+	if entry.TDSAmount <= 0 || entry.DeductionID != nil {
+		return nil
+	}
+
+	line := &models.VendorPaymentRunLine{
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		VendorID:      vendor.ID,
+		VendorName:    vendor.Name,
+		TDSSection:    entry.SectionCode,
+		TDSRate:       entry.Rate,
+		TDSAmount:     entry.TDSAmount,
+	}
+	deductionID, err := s.recordTDSDeduction(tx, tenantID, "", line, entry.BaseAmount, invoice.InvoiceDate, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE tds_ledger SET deduction_id = ? WHERE id = ? AND tenant_id = ?`,
+		deductionID, entry.ID, tenantID); err != nil {
+		return fmt.Errorf("failed to link tds deduction: %w", err)
+	}
+	return nil
+}
+
+// recordPaymentTDSInLedger adds TDS deducted by a payment run on an invoice the
+// engine never assessed (posted before it existed) to the TDS ledger
+func (s *PurchaseService) recordPaymentTDSInLedger(tx *sql.Tx, tenantID, paymentID, deductionID string, line *models.VendorPaymentRunLine, base float64, paymentDate time.Time, userID string) error {
+	vendor, err := s.GetVendor(tenantID, line.VendorID)
+	if err != nil {
+		return fmt.Errorf("failed to get vendor: %w", err)
+	}
+	pan := ""
+	if sections, err := s.getVendorTDSSections(tenantID); err == nil {
+		pan = sections[line.VendorID].PAN
+	}
+	if pan == "" {
+		pan = panFromGSTIN(vendor.TaxID)
+	}
+
+	_, err = NewTDSService(s.DB).RecordDeductionTx(tx, tenantID, &models.TDSDeductionInput{
+		SourceType:      models.TDSSourceVendorPayment,
+		SourceID:        paymentID,
+		PayeeType:       models.TDSPayeeVendor,
+		PayeeID:         line.VendorID,
+		PayeeName:       line.VendorName,
+		PAN:             pan,
+		NonResident:     isNonResidentVendor(vendor),
+		Section:         line.TDSSection,
+		Amount:          base,
+		TransactionDate: paymentDate,
+		DeductionID:     deductionID,
+		CreatedBy:       userID,
+	}, models.TDSComputation{
+		Section:    line.TDSSection,
+		Rate:       line.TDSRate,
+		BaseAmount: base,
+		TDSAmount:  line.TDSAmount,
+		PANMissing: !panPattern.MatchString(strings.ToUpper(pan)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record tds in ledger: %w", err)
+	}
+	return nil
+}
+
+func isNonResidentVendor(vendor *models.Vendor) bool {
+	country := strings.TrimSpace(vendor.Country)
+	return country != "" && !strings.EqualFold(country, "India") && !strings.EqualFold(country, "IN")
+}
+
+// failPaymentRunFile leaves a run whose lines were paid but whose bank file could not
+// be saved as bank_file_failed with the error, so it can be processed again
+func (s *PurchaseService) failPaymentRunFile(run *models.VendorPaymentRun, cause error) error {
+	run.Status = "bank_file_failed"
+	run.FailureReason = cause.Error()
+	if _, err := s.DB.Exec(`UPDATE vendor_payment_runs SET status = ?, failure_reason = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, run.Status, run.FailureReason, time.Now(), run.ID, run.TenantID); err != nil {
+		return fmt.Errorf("%v; failed to mark payment run: %w", cause, err)
+	}
+	return cause
+}
+
+func (s *PurchaseService) failRunLine(line *models.VendorPaymentRunLine, reason string) {
+	line.Status = "failed"
+	line.FailureReason = reason
+	s.DB.Exec(`UPDATE vendor_payment_run_lines SET status = 'failed', failure_reason = ? WHERE id = ? AND tenant_id = ?`,
+		reason, line.ID, line.TenantID)
+}
+
+// GetPaymentRun returns a payment run with its lines
+func (s *PurchaseService) GetPaymentRun(tenantID, runID string) (*models.VendorPaymentRun, error) {
+	var run models.VendorPaymentRun
+	var dueOnOrBefore, approvedAt, processedAt sql.NullTime
+	var approvedBy sql.NullString
+	err := s.DB.QueryRow(`SELECT id, tenant_id, run_number, status, due_on_or_before, COALESCE(vendor_id, ''),
+		COALESCE(project_id, ''), payment_date, payment_method, bank_format_id, total_gross, total_tds, total_net,
+		line_count, created_by, approved_by, approved_at, COALESCE(approval_comment, ''), processed_at,
+		COALESCE(bank_file_name, ''), COALESCE(failure_reason, ''), created_at, updated_at
+		FROM vendor_payment_runs WHERE id = ? AND tenant_id = ?`, runID, tenantID).Scan(
+		&run.ID, &run.TenantID, &run.RunNumber, &run.Status, &dueOnOrBefore, &run.VendorID,
+		&run.ProjectID, &run.PaymentDate, &run.PaymentMethod, &run.BankFormatID, &run.TotalGross, &run.TotalTDS, &run.TotalNet,
+		&run.LineCount, &run.CreatedBy, &approvedBy, &approvedAt, &run.ApprovalComment, &processedAt,
+		&run.BankFileName, &run.FailureReason, &run.CreatedAt, &run.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("payment run not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get payment run: %w", err)
+	}
+	if dueOnOrBefore.Valid {
+		run.DueOnOrBefore = &dueOnOrBefore.Time
+	}
+	if approvedBy.Valid {
+		run.ApprovedBy = &approvedBy.String
+	}
+	if approvedAt.Valid {
+		run.ApprovedAt = &approvedAt.Time
+	}
+	if processedAt.Valid {
+		run.ProcessedAt = &processedAt.Time
+	}
+
+	rows, err := s.DB.Query(`SELECT id, tenant_id, run_id, invoice_id, invoice_number, vendor_id, vendor_name, due_date,
+		gross_amount, COALESCE(tds_section, ''), tds_rate, tds_amount, net_amount, payment_id, journal_entry_id,
+		status, COALESCE(failure_reason, ''), created_at
+		FROM vendor_payment_run_lines WHERE run_id = ? AND tenant_id = ? ORDER BY vendor_name, due_date`, runID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment run lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.VendorPaymentRunLine
+		if err := rows.Scan(&line.ID, &line.TenantID, &line.RunID, &line.InvoiceID, &line.InvoiceNumber, &line.VendorID,
+			&line.VendorName, &line.DueDate, &line.GrossAmount, &line.TDSSection, &line.TDSRate, &line.TDSAmount,
+			&line.NetAmount, &line.PaymentID, &line.JournalEntryID, &line.Status, &line.FailureReason,
+			&line.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment run line: %w", err)
+		}
+		run.Lines = append(run.Lines, line)
+	}
+
+	return &run, rows.Err()
+}
+
+// ListPaymentRuns lists payment runs, optionally filtered by status
+func (s *PurchaseService) ListPaymentRuns(tenantID, status string) ([]models.VendorPaymentRun, error) {
+	query := `SELECT id, run_number, status, payment_date, payment_method, total_gross, total_tds, total_net,
+		line_count, created_by, COALESCE(bank_file_name, ''), created_at
+		FROM vendor_payment_runs WHERE tenant_id = ?`
+	args := []interface{}{tenantID}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.VendorPaymentRun
+	for rows.Next() {
+		run := models.VendorPaymentRun{TenantID: tenantID}
+		if err := rows.Scan(&run.ID, &run.RunNumber, &run.Status, &run.PaymentDate, &run.PaymentMethod,
+			&run.TotalGross, &run.TotalTDS, &run.TotalNet, &run.LineCount, &run.CreatedBy,
+			&run.BankFileName, &run.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// GetPaymentRunBankFile returns the generated bank file of a processed run
+func (s *PurchaseService) GetPaymentRunBankFile(tenantID, runID string) (string, string, error) {
+	var fileName, content sql.NullString
+	err := s.DB.QueryRow(`SELECT bank_file_name, bank_file_content FROM vendor_payment_runs WHERE id = ? AND tenant_id = ?`,
+		runID, tenantID).Scan(&fileName, &content)
+	if err == sql.ErrNoRows {
+		return "", "", fmt.Errorf("payment run not found")
+	} else if err != nil {
+		return "", "", fmt.Errorf("failed to get bank file: %w", err)
+	}
+	if !content.Valid {
+		return "", "", fmt.Errorf("bank file not generated, payment run is not processed")
+	}
+	return fileName.String, content.String, nil
+}
+
+func (s *PurchaseService) getInvoicesInOpenRuns(tenantID string) (map[string]bool, error) {
+	rows, err := s.DB.Query(`SELECT l.invoice_id FROM vendor_payment_run_lines l
+		JOIN vendor_payment_runs r ON r.id = l.run_id
+		WHERE l.tenant_id = ? AND r.status IN ('proposed', 'approved') AND l.status = 'proposed'`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open payment runs: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := map[string]bool{}
+	for rows.Next() {
+		var invoiceID string
+		if err := rows.Scan(&invoiceID); err != nil {
+			return nil, fmt.Errorf("failed to scan payment run invoice: %w", err)
+		}
+		invoices[invoiceID] = true
+	}
+	return invoices, rows.Err()
+}
+
+func (s *PurchaseService) getTDSDeductedByInvoice(tenantID string) (map[string]float64, error) {
+	rows, err := s.DB.Query(`SELECT invoice_id, SUM(tds_amount) FROM tds_deductions WHERE tenant_id = ? GROUP BY invoice_id`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tds deductions: %w", err)
+	}
+	defer rows.Close()
+
+	deducted := map[string]float64{}
+	for rows.Next() {
+		var invoiceID string
+		var amount float64
+		if err := rows.Scan(&invoiceID, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan tds deduction: %w", err)
+		}
+		deducted[invoiceID] = amount
+	}
+	return deducted, rows.Err()
+}
+
+// getTDSAssessedInvoices returns invoices the TDS engine assessed when they were posted
+func (s *PurchaseService) getTDSAssessedInvoices(tenantID string) (map[string]bool, error) {
+	rows, err := s.DB.Query(`SELECT source_id FROM tds_ledger WHERE tenant_id = ? AND source_type = ?`,
+		tenantID, models.TDSSourceVendorInvoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tds ledger: %w", err)
+	}
+	defer rows.Close()
+
+	assessed := map[string]bool{}
+	for rows.Next() {
+		var invoiceID string
+		if err := rows.Scan(&invoiceID); err != nil {
+			return nil, fmt.Errorf("failed to scan tds ledger: %w", err)
+		}
+		assessed[invoiceID] = true
+	}
+	return assessed, rows.Err()
+}
+
+func (s *PurchaseService) getVendorsByID(tenantID string) (map[string]models.Vendor, error) {
+	vendors, err := s.ListVendors(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendors: %w", err)
+	}
+	byID := make(map[string]models.Vendor, len(vendors))
+	for _, v := range vendors {
+		byID[v.ID] = v
+	}
+	return byID, nil
+}
+
+// vendorTDSDue is the TDS still to deduct on an invoice: rate on the taxable
+// value (GST excluded) less TDS already deducted, never more than the balance
+func vendorTDSDue(taxableAmount, rate, alreadyDeducted, outstanding float64) float64 {
+	tds := roundTo2(taxableAmount*rate/100) - alreadyDeducted
+	if tds > outstanding {
+		tds = outstanding
+	}
+	if tds < 0 {
+		return 0
+	}
+	return roundTo2(tds)
+}
+
+func nullIfEmpty(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+// ============================================================================
+// BANK FILE GENERATION
+// ============================================================================
+
+// bankFileRow is one beneficiary payment in a bank bulk upload
+type bankFileRow struct {
+	PaymentMode   string
+	DebitAccount  string
+	Beneficiary   string
+	AccountNumber string
+	IFSC          string
+	BankName      string
+	Amount        float64
+	PaymentDate   time.Time
+	Reference     string
+	VendorCode    string
+	Email         string
+}
+
+var bankFileColumnHeaders = map[string]string{
+	models.BankFileColumnPaymentMode:   "Payment Mode",
+	models.BankFileColumnDebitAccount:  "Debit Account No",
+	models.BankFileColumnBeneficiary:   "Beneficiary Name",
+	models.BankFileColumnAccountNumber: "Beneficiary Account No",
+	models.BankFileColumnIFSC:          "IFSC Code",
+	models.BankFileColumnBankName:      "Beneficiary Bank",
+	models.BankFileColumnAmount:        "Amount",
+	models.BankFileColumnPaymentDate:   "Value Date",
+	models.BankFileColumnReference:     "Payment Reference",
+	models.BankFileColumnVendorCode:    "Beneficiary Code",
+	models.BankFileColumnEmail:         "Beneficiary Email",
+}
+
+// buildBankFile renders payment rows in the bank's configured layout. CSV files are
+// quoted as needed; NEFT files are plain delimited records with the delimiter stripped from values.
+func buildBankFile(format *models.BankPaymentFormat, rows []bankFileRow) (string, error) {
+	if err := validateBankFormat(format); err != nil {
+		return "", err
+	}
+	delimiter := []rune(format.Delimiter)[0]
+	layout := bankDateLayout(format.DateFormat)
+
+	records := [][]string{}
+	if format.IncludeHeader {
+		header := make([]string, len(format.Columns))
+		for i, column := range format.Columns {
+			header[i] = bankFileColumnHeaders[column]
+		}
+		records = append(records, header)
+	}
+	for _, row := range rows {
+		if row.DebitAccount == "" {
+			row.DebitAccount = format.DebitAccountNumber
+		}
+		record := make([]string, len(format.Columns))
+		for i, column := range format.Columns {
+			record[i] = bankFileValue(column, row, layout)
+		}
+		records = append(records, record)
+	}
+
+	if format.FileType == "neft" {
+		var b strings.Builder
+		for _, record := range records {
+			for i, value := range record {
+				record[i] = strings.ReplaceAll(value, string(delimiter), " ")
+			}
+			b.WriteString(strings.Join(record, string(delimiter)))
+			b.WriteString("\r\n")
+		}
+		return b.String(), nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = delimiter
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("failed to write bank file: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func bankFileValue(column string, row bankFileRow, dateLayout string) string {
+	switch column {
+	case models.BankFileColumnPaymentMode:
+		return row.PaymentMode
+	case models.BankFileColumnDebitAccount:
+		return row.DebitAccount
+	case models.BankFileColumnBeneficiary:
+		return row.Beneficiary
+	case models.BankFileColumnAccountNumber:
+		return row.AccountNumber
+	case models.BankFileColumnIFSC:
+		return row.IFSC
+	case models.BankFileColumnBankName:
+		return row.BankName
+	case models.BankFileColumnAmount:
+		return strconv.FormatFloat(row.Amount, 'f', 2, 64)
+	case models.BankFileColumnPaymentDate:
+		return row.PaymentDate.Format(dateLayout)
+	case models.BankFileColumnReference:
+		return row.Reference
+	case models.BankFileColumnVendorCode:
+		return row.VendorCode
+	case models.BankFileColumnEmail:
+		return row.Email
+	}
+	return ""
+}
+
+// bankDateLayout converts a bank date pattern such as DD/MM/YYYY or YYYYMMDD to a Go layout
+func bankDateLayout(pattern string) string {
+	return strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").Replace(strings.ToUpper(pattern))
+}
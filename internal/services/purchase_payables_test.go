@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPayableDueDate tests due date fallback to vendor payment terms
+func TestPayableDueDate(t *testing.T) {
+	invoiceDate := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	explicit := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, explicit, payableDueDate(invoiceDate, &explicit, "Net 45"))
+	assert.Equal(t, invoiceDate.AddDate(0, 0, 45), payableDueDate(invoiceDate, nil, "Net 45"))
+	assert.Equal(t, invoiceDate.AddDate(0, 0, 15), payableDueDate(invoiceDate, nil, "15 days"))
+	assert.Equal(t, invoiceDate.AddDate(0, 0, defaultPaymentTermDays), payableDueDate(invoiceDate, nil, "Immediate"))
+}
+
+// TestBuildAPAgeingRows tests aggregation by vendor and by project
+func TestBuildAPAgeingRows(t *testing.T) {
+	payables := []models.PayableInvoice{
+		{VendorID: "v1", VendorName: "Cement Co", ProjectID: "p1", ProjectName: "Skyline", Outstanding: 1000, Bucket: models.AgeingBucketCurrent},
+		{VendorID: "v1", VendorName: "Cement Co", ProjectID: "p2", ProjectName: "Riverside", Outstanding: 500, Bucket: models.AgeingBucket31To60},
+		{VendorID: "v2", VendorName: "Steel Ltd", ProjectID: "", Outstanding: 200, Bucket: models.AgeingBucket90Plus},
+	}
+
+	byVendor := buildAPAgeingRows(payables, "vendor")
+	assert.Len(t, byVendor, 2)
+	assert.Equal(t, 1500.0, byVendor[0].TotalOutstanding)
+	assert.Equal(t, 500.0, byVendor[0].TotalOverdue)
+	assert.Equal(t, 2, byVendor[0].InvoiceCount)
+
+	byProject := buildAPAgeingRows(payables, "project")
+	assert.Len(t, byProject, 3)
+	assert.Equal(t, "Unallocated", byProject[2].GroupName)
+	assert.Equal(t, 200.0, byProject[2].Bucket90Plus)
+}
+
+// TestVendorTDSDue tests TDS on taxable value net of earlier deductions
+func TestVendorTDSDue(t *testing.T) {
+	// 194C at 2% on 100000 taxable, invoice 118000 incl. GST
+	assert.Equal(t, 2000.0, vendorTDSDue(100000, 2, 0, 118000))
+	// Part already deducted on an earlier payment
+	assert.Equal(t, 1200.0, vendorTDSDue(100000, 2, 800, 50000))
+	// Fully deducted
+	assert.Equal(t, 0.0, vendorTDSDue(100000, 2, 2000, 10000))
+	// Never more than the balance
+	assert.Equal(t, 500.0, vendorTDSDue(100000, 10, 0, 500))
+}
+
+// TestBankDateLayout tests bank date pattern conversion
+func TestBankDateLayout(t *testing.T) {
+	d := time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "05/07/2025", d.Format(bankDateLayout("DD/MM/YYYY")))
+	assert.Equal(t, "20250705", d.Format(bankDateLayout("yyyymmdd")))
+	assert.Equal(t, "05-07-25", d.Format(bankDateLayout("DD-MM-YY")))
+}
+
+// TestBuildBankFile tests CSV and NEFT layouts
+func TestBuildBankFile(t *testing.T) {
+	rows := []bankFileRow{{
+		PaymentMode:   "NEFT",
+		Beneficiary:   "Cement Co, Pune",
+		AccountNumber: "123456789",
+		IFSC:          "HDFC0000123",
+		Amount:        98000,
+		PaymentDate:   time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC),
+		Reference:     "INV-001",
+	}}
+
+	csvFormat := &models.BankPaymentFormat{
+		FileType:           "csv",
+		Delimiter:          ",",
+		IncludeHeader:      true,
+		Columns:            []string{"debit_account", "beneficiary_name", "account_number", "ifsc", "amount", "payment_date"},
+		DateFormat:         "DD/MM/YYYY",
+		DebitAccountNumber: "999000111",
+	}
+	content, err := buildBankFile(csvFormat, rows)
+	assert.NoError(t, err)
+	assert.Equal(t, "Debit Account No,Beneficiary Name,Beneficiary Account No,IFSC Code,Amount,Value Date\n"+
+		"999000111,\"Cement Co, Pune\",123456789,HDFC0000123,98000.00,05/07/2025\n", content)
+
+	neftFormat := &models.BankPaymentFormat{
+		FileType:   "neft",
+		Delimiter:  "|",
+		Columns:    []string{"payment_mode", "ifsc", "account_number", "amount", "reference"},
+		DateFormat: "YYYYMMDD",
+	}
+	content, err = buildBankFile(neftFormat, rows)
+	assert.NoError(t, err)
+	assert.Equal(t, "NEFT|HDFC0000123|123456789|98000.00|INV-001\r\n", content)
+
+	_, err = buildBankFile(&models.BankPaymentFormat{FileType: "csv", Delimiter: ",", Columns: []string{"swift"}}, rows)
+	assert.Error(t, err)
+}
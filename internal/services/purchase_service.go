@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // PurchaseService handles Purchase Management and GL Integration
@@ -119,8 +121,37 @@ func (s *PurchaseService) CreatePurchaseOrder(tenantID string, po *models.Purcha
 		po.PaymentTerms, po.DeliveryLocation, po.SpecialInstructions, po.Status,
 		po.CreatedAt, po.UpdatedAt, po.CreatedBy,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Line items are the basis for matching goods receipts and invoices
+	lineQuery := `INSERT INTO po_line_items (
+		id, tenant_id, po_id, line_number, product_code, description, quantity, unit,
+		unit_price, line_total, hsn_code, tax_rate, tax_amount, specification, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	for i := range po.LineItems {
+		line := &po.LineItems[i]
+		if line.ID == "" {
+			line.ID = uuid.New().String()
+		}
+		line.TenantID = tenantID
+		line.POID = po.ID
+		line.LineNumber = i + 1
+		line.CreatedAt = po.CreatedAt
+		if line.LineTotal == 0 {
+			line.LineTotal = line.Quantity * line.UnitPrice
+		}
+		if _, err := s.DB.Exec(lineQuery,
+			line.ID, line.TenantID, line.POID, line.LineNumber, line.ProductCode, line.Description,
+			line.Quantity, line.Unit, line.UnitPrice, line.LineTotal, line.HSNCode, line.TaxRate,
+			line.TaxAmount, line.Specification, line.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to add PO line %d: %w", line.LineNumber, err)
+		}
+	}
+
+	return nil
 }
 
 // GetPurchaseOrder retrieves a PO by ID
@@ -155,28 +186,60 @@ func (s *PurchaseService) CreateVendorInvoice(tenantID string, invoice *models.V
 	invoice.TenantID = tenantID
 	invoice.CreatedAt = time.Now()
 	invoice.UpdatedAt = time.Now()
+	if invoice.MatchedStatus == "" {
+		invoice.MatchedStatus = models.MatchStatusNotMatched
+	}
+	// Matching is decided by MatchVendorInvoice, never by the caller
+	invoice.ThreeWayMatch = false
 
 	query := `INSERT INTO vendor_invoices (
-		id, tenant_id, invoice_number, vendor_id, po_id, grn_id, invoice_date,
+		id, tenant_id, invoice_number, vendor_id, po_id, grn_id, project_id, invoice_date,
 		due_date, invoice_amount, tax_amount, discount_amount, total_payable,
 		status, matched_status, three_way_match, created_at, updated_at, created_by
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := s.DB.Exec(query,
 		invoice.ID, invoice.TenantID, invoice.InvoiceNumber, invoice.VendorID,
-		invoice.POID, invoice.GRNID, invoice.InvoiceDate, invoice.DueDate,
+		invoice.POID, invoice.GRNID, invoice.ProjectID, invoice.InvoiceDate, invoice.DueDate,
 		invoice.InvoiceAmount, invoice.TaxAmount, invoice.DiscountAmount,
 		invoice.TotalPayable, invoice.Status, invoice.MatchedStatus, invoice.ThreeWayMatch,
 		invoice.CreatedAt, invoice.UpdatedAt, invoice.CreatedBy,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	lineQuery := `INSERT INTO purchase_invoice_line_items (
+		id, tenant_id, invoice_id, po_line_item_id, line_number, description, quantity,
+		unit_price, line_total, hsn_code, tax_rate, tax_amount, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	for i := range invoice.LineItems {
+		line := &invoice.LineItems[i]
+		if line.ID == "" {
+			line.ID = uuid.New().String()
+		}
+		line.TenantID = tenantID
+		line.InvoiceID = invoice.ID
+		line.LineNumber = i + 1
+		line.CreatedAt = invoice.CreatedAt
+		if line.LineTotal == 0 {
+			line.LineTotal = line.Quantity * line.UnitPrice
+		}
+		if _, err := s.DB.Exec(lineQuery,
+			line.ID, line.TenantID, line.InvoiceID, line.POLineItemID, line.LineNumber, line.Description,
+			line.Quantity, line.UnitPrice, line.LineTotal, line.HSNCode, line.TaxRate, line.TaxAmount, line.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to add invoice line %d: %w", line.LineNumber, err)
+		}
+	}
+
+	return nil
 }
 
 // GetVendorInvoice retrieves an invoice by ID
 func (s *PurchaseService) GetVendorInvoice(tenantID, invoiceID string) (*models.VendorInvoice, error) {
 	var invoice models.VendorInvoice
-	query := `SELECT id, tenant_id, invoice_number, vendor_id, po_id, grn_id,
+	query := `SELECT id, tenant_id, invoice_number, vendor_id, po_id, grn_id, project_id,
 		invoice_date, due_date, invoice_amount, tax_amount, discount_amount,
 		total_payable, status, matched_status, three_way_match, approved_at,
 		approved_by, rejection_reason, created_at, updated_at
@@ -184,7 +247,7 @@ func (s *PurchaseService) GetVendorInvoice(tenantID, invoiceID string) (*models.
 
 	err := s.DB.QueryRow(query, invoiceID, tenantID).Scan(
 		&invoice.ID, &invoice.TenantID, &invoice.InvoiceNumber, &invoice.VendorID,
-		&invoice.POID, &invoice.GRNID, &invoice.InvoiceDate, &invoice.DueDate,
+		&invoice.POID, &invoice.GRNID, &invoice.ProjectID, &invoice.InvoiceDate, &invoice.DueDate,
 		&invoice.InvoiceAmount, &invoice.TaxAmount, &invoice.DiscountAmount,
 		&invoice.TotalPayable, &invoice.Status, &invoice.MatchedStatus, &invoice.ThreeWayMatch,
 		&invoice.ApprovedAt, &invoice.ApprovedBy, &invoice.RejectionReason,
@@ -213,6 +276,9 @@ func (s *PurchaseService) PostInvoiceToGL(tenantID, invoiceID string, glService
 	if err != nil {
 		return "", fmt.Errorf("failed to get invoice: %w", err)
 	}
+	if !invoice.ThreeWayMatch {
+		return "", fmt.Errorf("invoice %s is not matched to its purchase order and goods receipt (%s)", invoice.InvoiceNumber, invoice.MatchedStatus)
+	}
 
 	// Get vendor details for reference
 	vendor, err := s.GetVendor(tenantID, invoice.VendorID)
@@ -236,90 +302,68 @@ func (s *PurchaseService) PostInvoiceToGL(tenantID, invoiceID string, glService
 		UpdatedAt:       time.Now(),
 	}
 
-	// Create journal entry in GL service
-	if err := glService.CreateJournalEntry(tenantID, journalEntry); err != nil {
-		return "", fmt.Errorf("failed to create journal entry: %w", err)
-	}
-
-	// Add debit line: Expense/Asset account (based on PO)
+	// Debit line: Expense/Asset account (based on PO)
 	// For purchase of materials, debit inventory/asset account
 	// For purchase of services, debit expense account
 	lineAmount := invoice.InvoiceAmount - invoice.DiscountAmount
-	expenseDetail := &models.JournalEntryDetail{
-		ID:             fmt.Sprintf("JED-%s-EXP", invoiceID),
-		TenantID:       tenantID,
-		JournalEntryID: journalEntry.ID,
-		AccountID:      "ACC-PURCHASE-EXPENSE", // Should be configured per tenant
-		DebitAmount:    lineAmount,
-		CreditAmount:   0,
-		Description:    "Purchase expense/inventory",
-		LineNumber:     1,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-	}
-
-	if err := glService.AddJournalEntryDetail(expenseDetail); err != nil {
-		return "", fmt.Errorf("failed to add expense detail: %w", err)
-	}
-
-	lineNum := 2
-
-	// Add debit line for Input Tax (if applicable - for GST input credit, etc.)
+	lines := []models.JournalEntryDetail{{
+		ID:          fmt.Sprintf("JED-%s-EXP", invoiceID),
+		AccountID:   "ACC-PURCHASE-EXPENSE", // Should be configured per tenant
+		DebitAmount: lineAmount,
+		Description: "Purchase expense/inventory",
+	}}
+
+	// Debit line for Input Tax (if applicable - for GST input credit, etc.)
 	// DR: Input Tax Receivable (GST can be claimed as credit)
 	// This reduces the amount payable to government
 	if invoice.TaxAmount > 0 {
-		taxDetail := &models.JournalEntryDetail{
-			ID:             fmt.Sprintf("JED-%s-TAX", invoiceID),
-			TenantID:       tenantID,
-			JournalEntryID: journalEntry.ID,
-			AccountID:      "ACC-INPUT-TAX", // GST Input Tax Receivable
-			DebitAmount:    invoice.TaxAmount,
-			CreditAmount:   0,
-			Description:    "Input GST/Tax (recoverable)",
-			LineNumber:     lineNum,
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		}
-
-		if err := glService.AddJournalEntryDetail(taxDetail); err != nil {
-			return "", fmt.Errorf("failed to add tax detail: %w", err)
-		}
-		lineNum++
+		lines = append(lines, models.JournalEntryDetail{
+			ID:          fmt.Sprintf("JED-%s-TAX", invoiceID),
+			AccountID:   "ACC-INPUT-TAX", // GST Input Tax Receivable
+			DebitAmount: invoice.TaxAmount,
+			Description: "Input GST/Tax (recoverable)",
+		})
 	}
 
-	// Add credit line: Accounts Payable (main liability)
+	// Credit line: Accounts Payable (main liability)
 	// CR: AP = Purchase amount + Tax amount (vendor owes this total)
 	totalPayable := lineAmount + invoice.TaxAmount
-	apDetail := &models.JournalEntryDetail{
-		ID:             fmt.Sprintf("JED-%s-AP", invoiceID),
-		TenantID:       tenantID,
-		JournalEntryID: journalEntry.ID,
-		AccountID:      "ACC-ACCOUNTS-PAYABLE", // Should be configured per tenant
-		DebitAmount:    0,
-		CreditAmount:   totalPayable,
-		Description:    fmt.Sprintf("Accounts payable - %s", vendor.Name),
-		LineNumber:     lineNum,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-	}
-
-	if err := glService.AddJournalEntryDetail(apDetail); err != nil {
-		return "", fmt.Errorf("failed to add AP detail: %w", err)
+	lines = append(lines, models.JournalEntryDetail{
+		ID:           fmt.Sprintf("JED-%s-AP", invoiceID),
+		AccountID:    "ACC-ACCOUNTS-PAYABLE", // Should be configured per tenant
+		CreditAmount: totalPayable,
+		Description:  fmt.Sprintf("Accounts payable - %s", vendor.Name),
+	})
+
+	// The invoice entry, its status and any TDS deducted on it are written together
+	tx, err := glService.DB.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
 	// Post the journal entry (validates debit=credit balance)
-	if err := glService.PostJournalEntry(tenantID, journalEntry.ID, postedBy); err != nil {
-		return "", fmt.Errorf("failed to post journal entry: %w", err)
+	if err := glService.PostBalancedEntryTx(tx, tenantID, journalEntry, lines, postedBy); err != nil {
+		return "", err
 	}
 
 	// Update invoice status to indicate GL posting
 	updateQuery := `UPDATE vendor_invoices SET status = 'posted_to_gl', updated_at = ? 
 		WHERE id = ? AND tenant_id = ?`
-	_, err = s.DB.Exec(updateQuery, time.Now(), invoiceID, tenantID)
+	_, err = tx.Exec(updateQuery, time.Now(), invoiceID, tenantID)
 	if err != nil {
 		return "", fmt.Errorf("failed to update invoice status: %w", err)
 	}
 
+	// TDS is deducted when the invoice is credited to the vendor
+	if err := s.applyInvoiceTDS(tx, tenantID, invoice, vendor, postedBy); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit invoice posting: %w", err)
+	}
+
 	return journalEntry.ID, nil
 }
 
@@ -332,6 +376,12 @@ func (s *PurchaseService) PostInvoiceToGL(tenantID, invoiceID string, glService
 // - DR: Accounts Payable (reduction in liability)
 // - CR: Cash/Bank (cash outflow)
 func (s *PurchaseService) PostPaymentToGL(tenantID, paymentID string, glService *GLService, postedBy string) (string, error) {
+	return s.postPaymentToGL(glService.DB, tenantID, paymentID, postedBy)
+}
+
+// postPaymentToGL posts the payment through db, which is the caller's transaction
+// when the payment is recorded in the same unit of work
+func (s *PurchaseService) postPaymentToGL(db glExecer, tenantID, paymentID, postedBy string) (string, error) {
 	// Get payment details from database
 	var invoiceID, paymentNumber string
 	var paymentAmount float64
@@ -340,8 +390,8 @@ func (s *PurchaseService) PostPaymentToGL(tenantID, paymentID string, glService
 	query := `SELECT id, invoice_id, payment_number, payment_amount, payment_date 
 		FROM purchase_payments WHERE id = ? AND tenant_id = ?`
 
-	err := s.DB.QueryRow(query, paymentID, tenantID).Scan(
-		&invoiceID, &paymentNumber, &paymentAmount, &paymentDate,
+	err := db.QueryRow(query, paymentID, tenantID).Scan(
+		&paymentID, &invoiceID, &paymentNumber, &paymentAmount, &paymentDate,
 	)
 
 	if err == sql.ErrNoRows {
@@ -378,7 +428,7 @@ func (s *PurchaseService) PostPaymentToGL(tenantID, paymentID string, glService
 	}
 
 	// Create journal entry in GL service
-	if err := glService.CreateJournalEntry(tenantID, journalEntry); err != nil {
+	if err := createJournalEntry(db, tenantID, journalEntry); err != nil {
 		return "", fmt.Errorf("failed to create journal entry: %w", err)
 	}
 
@@ -397,7 +447,7 @@ func (s *PurchaseService) PostPaymentToGL(tenantID, paymentID string, glService
 		UpdatedAt:      time.Now(),
 	}
 
-	if err := glService.AddJournalEntryDetail(apDetail); err != nil {
+	if err := addJournalEntryDetail(db, apDetail); err != nil {
 		return "", fmt.Errorf("failed to add AP detail: %w", err)
 	}
 
@@ -416,20 +466,20 @@ func (s *PurchaseService) PostPaymentToGL(tenantID, paymentID string, glService
 		UpdatedAt:      time.Now(),
 	}
 
-	if err := glService.AddJournalEntryDetail(cashDetail); err != nil {
+	if err := addJournalEntryDetail(db, cashDetail); err != nil {
 		return "", fmt.Errorf("failed to add cash detail: %w", err)
 	}
 
 	// Post the journal entry (validates debit=credit balance)
 	// Validates: DR AP = CR Cash
-	if err := glService.PostJournalEntry(tenantID, journalEntry.ID, postedBy); err != nil {
+	if err := postJournalEntry(db, tenantID, journalEntry.ID, postedBy); err != nil {
 		return "", fmt.Errorf("failed to post journal entry: %w", err)
 	}
 
 	// Update payment status to indicate GL posting
 	updateQuery := `UPDATE purchase_payments SET payment_status = 'posted_to_gl', updated_at = ? 
 		WHERE id = ? AND tenant_id = ?`
-	_, err = s.DB.Exec(updateQuery, time.Now(), paymentID, tenantID)
+	_, err = db.Exec(updateQuery, time.Now(), paymentID, tenantID)
 	if err != nil {
 		return "", fmt.Errorf("failed to update payment status: %w", err)
 	}
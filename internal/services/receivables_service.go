@@ -0,0 +1,593 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// RECEIVABLES SERVICE
+// ============================================================================
+// AR ageing on customer installments and staged dunning of overdue bookings
+
+type ReceivablesService struct {
+	DB            *sql.DB
+	Communication *CommunicationService
+}
+
+func NewReceivablesService(db *sql.DB, communication *CommunicationService) *ReceivablesService {
+	return &ReceivablesService{DB: db, Communication: communication}
+}
+
+// Default dunning thresholds (days past due date)
+const (
+	defaultReminderAfterDays = 1
+	defaultDemandAfterDays   = 31
+	defaultFinalAfterDays    = 61
+	defaultDunningGapDays    = 7
+)
+
+// ============================================================================
+// AGEING
+// ============================================================================
+
+// GetOutstandingItems returns open installments as of a date, with cleared
+// booking payments applied to the oldest installments first
+func (s *ReceivablesService) GetOutstandingItems(tenantID, projectID string, asOf time.Time) ([]models.ReceivableItem, error) {
+	query := `SELECT ps.id, ps.booking_id, COALESCE(b.booking_reference, ''), COALESCE(ps.schedule_name, ps.payment_stage, ''),
+		COALESCE(u.project_id, ''), COALESCE(p.project_name, ''), COALESCE(u.block_id, ''), COALESCE(blk.block_name, ''),
+		COALESCE(u.unit_number, ''), COALESCE(cd.primary_name, ''), COALESCE(cd.primary_phone, ''), COALESCE(cd.primary_email, ''),
+		ps.due_date, ps.payment_amount
+		FROM payment_schedules ps
+		JOIN customer_bookings b ON b.id = ps.booking_id AND b.tenant_id = ps.tenant_id
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN property_projects p ON p.id = u.project_id
+		LEFT JOIN property_blocks blk ON blk.id = u.block_id
+		LEFT JOIN customer_details cd ON cd.booking_id = b.id AND cd.deleted_at IS NULL
+		WHERE ps.tenant_id = ? AND ps.deleted_at IS NULL AND b.deleted_at IS NULL
+		AND b.booking_status = 'active'`
+	args := []interface{}{tenantID}
+	if projectID != "" {
+		query += " AND u.project_id = ?"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY ps.booking_id, ps.due_date"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment schedules: %w", err)
+	}
+	defer rows.Close()
+
+	byBooking := map[string][]models.ReceivableItem{}
+	bookingOrder := []string{}
+	for rows.Next() {
+		var item models.ReceivableItem
+		if err := rows.Scan(&item.ScheduleID, &item.BookingID, &item.BookingReference, &item.ScheduleName,
+			&item.ProjectID, &item.ProjectName, &item.BlockID, &item.BlockName,
+			&item.UnitNumber, &item.CustomerName, &item.CustomerPhone, &item.CustomerEmail,
+			&item.DueDate, &item.AmountDue); err != nil {
+			return nil, fmt.Errorf("failed to scan payment schedule: %w", err)
+		}
+		if _, ok := byBooking[item.BookingID]; !ok {
+			bookingOrder = append(bookingOrder, item.BookingID)
+		}
+		byBooking[item.BookingID] = append(byBooking[item.BookingID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read payment schedules: %w", err)
+	}
+
+	received, err := s.getClearedReceipts(tenantID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []models.ReceivableItem{}
+	for _, bookingID := range bookingOrder {
+		for _, item := range applyReceipts(byBooking[bookingID], received[bookingID]) {
+			if item.Outstanding <= 0 {
+				continue
+			}
+			item.DaysOverdue = daysPastDue(item.DueDate, asOf)
+			item.Bucket = ageingBucket(item.DaysOverdue)
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// getClearedReceipts totals cleared booking payments per booking up to a date. TDS
+// the buyer deducted from a payment counts as received along with it.
+func (s *ReceivablesService) getClearedReceipts(tenantID string, asOf time.Time) (map[string]float64, error) {
+	rows, err := s.DB.Query(`SELECT p.booking_id, COALESCE(SUM(p.amount + COALESCE(t.tds_amount, 0)), 0)
+		FROM booking_payments p
+		LEFT JOIN booking_payment_tds t ON t.payment_id = p.id AND t.tenant_id = p.tenant_id
+		WHERE p.tenant_id = ? AND p.status = 'cleared' AND p.payment_date <= ? AND p.deleted_at IS NULL
+		GROUP BY p.booking_id`, tenantID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking payments: %w", err)
+	}
+	defer rows.Close()
+
+	received := map[string]float64{}
+	for rows.Next() {
+		var bookingID string
+		var amount float64
+		if err := rows.Scan(&bookingID, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan booking payment: %w", err)
+		}
+		received[bookingID] = amount
+	}
+	return received, rows.Err()
+}
+
+// GetAgeingReport builds the AR ageing report grouped by project, tower or customer
+func (s *ReceivablesService) GetAgeingReport(tenantID, projectID, groupBy string, asOf time.Time) (*models.ARAgeingReport, error) {
+	switch groupBy {
+	case "", "project":
+		groupBy = "project"
+	case "tower", "customer":
+	default:
+		return nil, fmt.Errorf("invalid group_by: %s", groupBy)
+	}
+
+	items, err := s.GetOutstandingItems(tenantID, projectID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ARAgeingReport{
+		AsOfDate: asOf,
+		GroupBy:  groupBy,
+		Rows:     buildAgeingRows(items, groupBy),
+	}
+	report.Totals.GroupKey = "total"
+	report.Totals.GroupName = "Total"
+	for _, row := range report.Rows {
+		addAgeingRow(&report.Totals, row)
+	}
+
+	return report, nil
+}
+
+// applyReceipts allocates the amount received against installments in due date order
+func applyReceipts(schedules []models.ReceivableItem, received float64) []models.ReceivableItem {
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].DueDate.Before(schedules[j].DueDate)
+	})
+	for i := range schedules {
+		applied := schedules[i].AmountDue
+		if received < applied {
+			applied = received
+		}
+		if applied < 0 {
+			applied = 0
+		}
+		schedules[i].AmountReceived = applied
+		schedules[i].Outstanding = schedules[i].AmountDue - applied
+		received -= applied
+	}
+	return schedules
+}
+
+// daysPastDue returns whole days between the due date and asOf (negative if not yet due)
+func daysPastDue(dueDate, asOf time.Time) int {
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	ref := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ref.Sub(due).Hours() / 24)
+}
+
+// ageingBucket maps days overdue to its ageing bucket
+func ageingBucket(daysOverdue int) string {
+	switch {
+	case daysOverdue <= 0:
+		return models.AgeingBucketCurrent
+	case daysOverdue <= 30:
+		return models.AgeingBucket0To30
+	case daysOverdue <= 60:
+		return models.AgeingBucket31To60
+	case daysOverdue <= 90:
+		return models.AgeingBucket61To90
+	default:
+		return models.AgeingBucket90Plus
+	}
+}
+
+// buildAgeingRows aggregates receivable items into ageing rows
+func buildAgeingRows(items []models.ReceivableItem, groupBy string) []models.ARAgeingRow {
+	rows := map[string]*models.ARAgeingRow{}
+	bookings := map[string]map[string]bool{}
+	order := []string{}
+
+	for _, item := range items {
+		key, name := item.ProjectID, item.ProjectName
+		switch groupBy {
+		case "tower":
+			key, name = item.BlockID, item.ProjectName+" / "+item.BlockName
+		case "customer":
+			key, name = item.BookingID, item.CustomerName+" ("+item.UnitNumber+")"
+		}
+
+		row, ok := rows[key]
+		if !ok {
+			row = &models.ARAgeingRow{GroupKey: key, GroupName: name}
+			rows[key] = row
+			bookings[key] = map[string]bool{}
+			order = append(order, key)
+		}
+
+		switch item.Bucket {
+		case models.AgeingBucketCurrent:
+			row.Current += item.Outstanding
+		case models.AgeingBucket0To30:
+			row.Bucket0To30 += item.Outstanding
+		case models.AgeingBucket31To60:
+			row.Bucket31To60 += item.Outstanding
+		case models.AgeingBucket61To90:
+			row.Bucket61To90 += item.Outstanding
+		default:
+			row.Bucket90Plus += item.Outstanding
+		}
+		if item.Bucket != models.AgeingBucketCurrent {
+			row.TotalOverdue += item.Outstanding
+		}
+		row.TotalOutstanding += item.Outstanding
+		bookings[key][item.BookingID] = true
+	}
+
+	result := make([]models.ARAgeingRow, 0, len(order))
+	for _, key := range order {
+		rows[key].BookingCount = len(bookings[key])
+		result = append(result, *rows[key])
+	}
+	return result
+}
+
+func addAgeingRow(total *models.ARAgeingRow, row models.ARAgeingRow) {
+	total.Current += row.Current
+	total.Bucket0To30 += row.Bucket0To30
+	total.Bucket31To60 += row.Bucket31To60
+	total.Bucket61To90 += row.Bucket61To90
+	total.Bucket90Plus += row.Bucket90Plus
+	total.TotalOverdue += row.TotalOverdue
+	total.TotalOutstanding += row.TotalOutstanding
+	total.BookingCount += row.BookingCount
+}
+
+// ============================================================================
+// DUNNING
+// ============================================================================
+
+// RunDunning escalates overdue bookings through reminder, demand notice and
+// final notice, sends each notice and logs it on the customer portal timeline
+func (s *ReceivablesService) RunDunning(ctx context.Context, tenantID, userID string, req *models.DunningRunRequest) (*models.DunningRun, error) {
+	asOf := time.Now()
+	if req.AsOfDate != nil {
+		asOf = *req.AsOfDate
+	}
+	if req.Channel == "" {
+		req.Channel = string(ProviderTypeEmail)
+	}
+	if req.ReminderAfterDays <= 0 {
+		req.ReminderAfterDays = defaultReminderAfterDays
+	}
+	if req.DemandAfterDays <= 0 {
+		req.DemandAfterDays = defaultDemandAfterDays
+	}
+	if req.FinalAfterDays <= 0 {
+		req.FinalAfterDays = defaultFinalAfterDays
+	}
+	if req.MinGapDays <= 0 {
+		req.MinGapDays = defaultDunningGapDays
+	}
+
+	items, err := s.GetOutstandingItems(tenantID, req.ProjectID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.DunningRun{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		ProjectID: req.ProjectID,
+		AsOfDate:  asOf,
+		Channel:   req.Channel,
+		Status:    "completed",
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if req.DryRun {
+		run.Status = "dry_run"
+	} else {
+		// The run is recorded up front so its notices always have a run to point at
+		run.Status = "running"
+		_, err = s.DB.Exec(`INSERT INTO dunning_runs
+			(id, tenant_id, project_id, as_of_date, channel, bookings_seen, notices_sent, notices_failed,
+			 total_overdue, status, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			run.ID, run.TenantID, run.ProjectID, run.AsOfDate, run.Channel, 0, 0, 0, 0, run.Status,
+			run.CreatedBy, run.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record dunning run: %w", err)
+		}
+	}
+
+	// Collapse overdue installments to one line per booking
+	type overdueBooking struct {
+		item        models.ReceivableItem
+		amount      float64
+		daysOverdue int
+		oldestDue   time.Time
+	}
+	overdue := map[string]*overdueBooking{}
+	order := []string{}
+	for _, item := range items {
+		if item.DaysOverdue <= 0 {
+			continue
+		}
+		ob, ok := overdue[item.BookingID]
+		if !ok {
+			ob = &overdueBooking{item: item, oldestDue: item.DueDate}
+			overdue[item.BookingID] = ob
+			order = append(order, item.BookingID)
+		}
+		if item.DueDate.Before(ob.oldestDue) {
+			ob.oldestDue = item.DueDate
+		}
+		ob.amount += item.Outstanding
+		if item.DaysOverdue > ob.daysOverdue {
+			ob.daysOverdue = item.DaysOverdue
+		}
+	}
+	run.BookingsSeen = len(order)
+
+	for _, bookingID := range order {
+		ob := overdue[bookingID]
+		run.TotalOverdue += ob.amount
+
+		// Only notices sent since the oldest installment still overdue count, so a booking
+		// that was brought current starts again from a reminder when it falls behind
+		lastStage, lastSentAt, err := s.getLastDunningNotice(tenantID, bookingID, ob.oldestDue)
+		if err != nil {
+			s.finishDunningRun(run, "failed")
+			return nil, err
+		}
+		if lastSentAt != nil && daysPastDue(*lastSentAt, asOf) < req.MinGapDays {
+			continue
+		}
+
+		stage := nextDunningStage(lastStage, ob.daysOverdue, req)
+		if stage == "" {
+			continue
+		}
+
+		notice := buildDunningNotice(tenantID, run.ID, req.Channel, stage, ob.item, ob.amount, ob.daysOverdue)
+		if req.DryRun {
+			notice.Status = "skipped"
+			run.Notices = append(run.Notices, *notice)
+			continue
+		}
+
+		s.sendDunningNotice(ctx, notice)
+		if notice.Status == "sent" {
+			run.NoticesSent++
+		} else {
+			run.NoticesFailed++
+		}
+
+		if err := s.saveDunningNotice(notice); err != nil {
+			s.finishDunningRun(run, "failed")
+			return nil, err
+		}
+		// The notice has gone out; a missing timeline entry must not fail the run
+		if notice.Status == "sent" {
+			if err := s.logDunningActivity(tenantID, bookingID, notice); err != nil {
+				log.Printf("Error logging dunning activity for booking %s: %v", bookingID, err)
+			}
+		}
+		run.Notices = append(run.Notices, *notice)
+	}
+
+	if !req.DryRun {
+		if err := s.finishDunningRun(run, "completed"); err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
+
+// finishDunningRun saves the run's totals with its final status
+func (s *ReceivablesService) finishDunningRun(run *models.DunningRun, status string) error {
+	run.Status = status
+	_, err := s.DB.Exec(`UPDATE dunning_runs SET bookings_seen = ?, notices_sent = ?, notices_failed = ?,
+		total_overdue = ?, status = ? WHERE id = ? AND tenant_id = ?`,
+		run.BookingsSeen, run.NoticesSent, run.NoticesFailed, run.TotalOverdue, run.Status, run.ID, run.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to record dunning run: %w", err)
+	}
+	return nil
+}
+
+// nextDunningStage returns the next stage for a booking, or "" when nothing is due.
+// Stages never skip: a booking moves one step per run even if it is long overdue.
+func nextDunningStage(lastStage string, daysOverdue int, req *models.DunningRunRequest) string {
+	switch lastStage {
+	case "":
+		if daysOverdue >= req.ReminderAfterDays {
+			return models.DunningStageReminder
+		}
+	case models.DunningStageReminder:
+		if daysOverdue >= req.DemandAfterDays {
+			return models.DunningStageDemandNotice
+		}
+	case models.DunningStageDemandNotice:
+		if daysOverdue >= req.FinalAfterDays {
+			return models.DunningStageFinalNotice
+		}
+	}
+	return ""
+}
+
+func buildDunningNotice(tenantID, runID, channel, stage string, item models.ReceivableItem, amount float64, daysOverdue int) *models.DunningNotice {
+	recipient := item.CustomerEmail
+	if channel != string(ProviderTypeEmail) {
+		recipient = item.CustomerPhone
+	}
+
+	subject := fmt.Sprintf("Payment reminder for unit %s", item.UnitNumber)
+	switch stage {
+	case models.DunningStageDemandNotice:
+		subject = fmt.Sprintf("Demand notice for unit %s", item.UnitNumber)
+	case models.DunningStageFinalNotice:
+		subject = fmt.Sprintf("Final notice for unit %s", item.UnitNumber)
+	}
+	body := fmt.Sprintf("Dear %s, an amount of Rs. %.2f against booking %s (%s, unit %s) is overdue by %d days. "+
+		"Please arrange payment at the earliest to avoid interest and further action.",
+		item.CustomerName, amount, item.BookingReference, item.ProjectName, item.UnitNumber, daysOverdue)
+
+	now := time.Now()
+	return &models.DunningNotice{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		DunningRunID:  runID,
+		BookingID:     item.BookingID,
+		ProjectID:     item.ProjectID,
+		CustomerName:  item.CustomerName,
+		Stage:         stage,
+		Channel:       channel,
+		Recipient:     recipient,
+		Subject:       subject,
+		Body:          body,
+		AmountOverdue: amount,
+		DaysOverdue:   daysOverdue,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// sendDunningNotice sends the notice through the communication service and
+// records the outcome on the notice
+func (s *ReceivablesService) sendDunningNotice(ctx context.Context, notice *models.DunningNotice) {
+	if notice.Recipient == "" {
+		notice.Status = "failed"
+		notice.FailureReason = "no recipient on customer details"
+		return
+	}
+	if s.Communication == nil {
+		notice.Status = "failed"
+		notice.FailureReason = "communication service not configured"
+		return
+	}
+
+	msg := &Message{
+		TenantID:     notice.TenantID,
+		Recipient:    notice.Recipient,
+		ProviderType: CommunicationProviderType(notice.Channel),
+		Subject:      notice.Subject,
+		Body:         notice.Body,
+	}
+	if err := s.Communication.SendMessage(ctx, msg); err != nil {
+		notice.Status = "failed"
+		notice.FailureReason = err.Error()
+		return
+	}
+
+	now := time.Now()
+	notice.Status = "sent"
+	notice.SentAt = &now
+}
+
+func (s *ReceivablesService) saveDunningNotice(notice *models.DunningNotice) error {
+	_, err := s.DB.Exec(`INSERT INTO dunning_notices
+		(id, tenant_id, dunning_run_id, booking_id, project_id, customer_name, stage, channel, recipient,
+		 subject, body, amount_overdue, days_overdue, status, failure_reason, sent_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		notice.ID, notice.TenantID, notice.DunningRunID, notice.BookingID, notice.ProjectID,
+		notice.CustomerName, notice.Stage, notice.Channel, notice.Recipient, notice.Subject,
+		notice.Body, notice.AmountOverdue, notice.DaysOverdue, notice.Status, notice.FailureReason,
+		notice.SentAt, notice.CreatedAt, notice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save dunning notice: %w", err)
+	}
+	return nil
+}
+
+// getLastDunningNotice returns the latest stage successfully sent for a booking since
+// the given due date
+func (s *ReceivablesService) getLastDunningNotice(tenantID, bookingID string, since time.Time) (string, *time.Time, error) {
+	var stage string
+	var sentAt time.Time
+	err := s.DB.QueryRow(`SELECT stage, sent_at FROM dunning_notices
+		WHERE tenant_id = ? AND booking_id = ? AND status = 'sent' AND sent_at >= ?
+		ORDER BY sent_at DESC LIMIT 1`, tenantID, bookingID, since).Scan(&stage, &sentAt)
+	if err == sql.ErrNoRows {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch last dunning notice: %w", err)
+	}
+	return stage, &sentAt, nil
+}
+
+// logDunningActivity adds the notice to the portal activity timeline of the booking's
+// customer. Customers without a portal profile have no timeline and are skipped.
+func (s *ReceivablesService) logDunningActivity(tenantID, bookingID string, notice *models.DunningNotice) error {
+	var portalUserID string
+	err := s.DB.QueryRow(`SELECT user_id FROM customer_profiles
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL LIMIT 1`,
+		tenantID, bookingID).Scan(&portalUserID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch portal user: %w", err)
+	}
+
+	// customer_activity_log keys are 26 characters
+	activityID := strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", ""))[:26]
+	_, err = s.DB.Exec(`INSERT INTO customer_activity_log
+		(id, tenant_id, user_id, activity_type, activity_description, entity_type, entity_id, action_taken, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		activityID, tenantID, portalUserID, "dunning_"+notice.Stage, notice.Subject,
+		"booking", bookingID, notice.Channel, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to log dunning activity: %w", err)
+	}
+	return nil
+}
+
+// ListDunningNotices lists notices sent for a booking, newest first
+func (s *ReceivablesService) ListDunningNotices(tenantID, bookingID string) ([]models.DunningNotice, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, dunning_run_id, booking_id, project_id, customer_name, stage,
+		channel, recipient, subject, body, amount_overdue, days_overdue, status, COALESCE(failure_reason, ''),
+		sent_at, created_at, updated_at
+		FROM dunning_notices WHERE tenant_id = ? AND booking_id = ? ORDER BY created_at DESC`, tenantID, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dunning notices: %w", err)
+	}
+	defer rows.Close()
+
+	notices := []models.DunningNotice{}
+	for rows.Next() {
+		var n models.DunningNotice
+		if err := rows.Scan(&n.ID, &n.TenantID, &n.DunningRunID, &n.BookingID, &n.ProjectID, &n.CustomerName,
+			&n.Stage, &n.Channel, &n.Recipient, &n.Subject, &n.Body, &n.AmountOverdue, &n.DaysOverdue,
+			&n.Status, &n.FailureReason, &n.SentAt, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dunning notice: %w", err)
+		}
+		notices = append(notices, n)
+	}
+	return notices, rows.Err()
+}
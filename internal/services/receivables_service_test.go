@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAgeingBucket tests bucket boundaries
+func TestAgeingBucket(t *testing.T) {
+	assert.Equal(t, models.AgeingBucketCurrent, ageingBucket(-5))
+	assert.Equal(t, models.AgeingBucketCurrent, ageingBucket(0))
+	assert.Equal(t, models.AgeingBucket0To30, ageingBucket(1))
+	assert.Equal(t, models.AgeingBucket0To30, ageingBucket(30))
+	assert.Equal(t, models.AgeingBucket31To60, ageingBucket(31))
+	assert.Equal(t, models.AgeingBucket61To90, ageingBucket(90))
+	assert.Equal(t, models.AgeingBucket90Plus, ageingBucket(91))
+}
+
+// TestApplyReceipts tests that receipts settle the oldest installments first
+func TestApplyReceipts(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	schedules := []models.ReceivableItem{
+		{ScheduleID: "s2", DueDate: jan.AddDate(0, 2, 0), AmountDue: 100000},
+		{ScheduleID: "s1", DueDate: jan, AmountDue: 100000},
+	}
+
+	result := applyReceipts(schedules, 150000)
+
+	assert.Equal(t, "s1", result[0].ScheduleID)
+	assert.Equal(t, 0.0, result[0].Outstanding)
+	assert.Equal(t, 50000.0, result[1].Outstanding)
+	assert.Equal(t, 50000.0, result[1].AmountReceived)
+}
+
+// TestBuildAgeingRows tests aggregation by tower
+func TestBuildAgeingRows(t *testing.T) {
+	items := []models.ReceivableItem{
+		{BookingID: "b1", BlockID: "t1", Outstanding: 1000, Bucket: models.AgeingBucket0To30},
+		{BookingID: "b1", BlockID: "t1", Outstanding: 500, Bucket: models.AgeingBucketCurrent},
+		{BookingID: "b2", BlockID: "t1", Outstanding: 2000, Bucket: models.AgeingBucket90Plus},
+		{BookingID: "b3", BlockID: "t2", Outstanding: 300, Bucket: models.AgeingBucket31To60},
+	}
+
+	rows := buildAgeingRows(items, "tower")
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 3000.0, rows[0].TotalOverdue)
+	assert.Equal(t, 3500.0, rows[0].TotalOutstanding)
+	assert.Equal(t, 2, rows[0].BookingCount)
+	assert.Equal(t, 300.0, rows[1].Bucket31To60)
+}
+
+// TestNextDunningStage tests one-step escalation
+func TestNextDunningStage(t *testing.T) {
+	req := &models.DunningRunRequest{ReminderAfterDays: 1, DemandAfterDays: 31, FinalAfterDays: 61}
+
+	assert.Equal(t, models.DunningStageReminder, nextDunningStage("", 100, req))
+	assert.Equal(t, "", nextDunningStage(models.DunningStageReminder, 20, req))
+	assert.Equal(t, models.DunningStageDemandNotice, nextDunningStage(models.DunningStageReminder, 45, req))
+	assert.Equal(t, models.DunningStageFinalNotice, nextDunningStage(models.DunningStageDemandNotice, 61, req))
+	assert.Equal(t, "", nextDunningStage(models.DunningStageFinalNotice, 200, req))
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // ============================================================================
@@ -14,11 +16,12 @@ import (
 // Manages project-specific collection accounts as per RERA regulations
 
 type RERAComplianceService struct {
-	DB *sql.DB
+	DB        *sql.DB
+	Documents *DocumentService
 }
 
-func NewRERAComplianceService(db *sql.DB) *RERAComplianceService {
-	return &RERAComplianceService{DB: db}
+func NewRERAComplianceService(db *sql.DB, documents *DocumentService) *RERAComplianceService {
+	return &RERAComplianceService{DB: db, Documents: documents}
 }
 
 // CreateProjectCollectionAccount creates a segregated collection account for a project
@@ -115,12 +118,18 @@ func (s *RERAComplianceService) RecordCollection(
 		UpdatedAt:           time.Now(),
 	}
 
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO project_collection_ledger 
 		(id, tenant_id, project_id, collection_account_id, collection_date, collection_number, 
 		 booking_id, unit_id, payment_mode, amount_collected, paid_by, status, created_at, updated_at) 
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = s.DB.Exec(query,
+	_, err = tx.Exec(query,
 		collection.ID, collection.TenantID, collection.ProjectID, collection.CollectionAccountID,
 		collection.CollectionDate, collection.CollectionNumber, collection.BookingID,
 		collection.UnitID, collection.PaymentMode, collection.AmountCollected,
@@ -131,16 +140,33 @@ func (s *RERAComplianceService) RecordCollection(
 		return nil, fmt.Errorf("failed to record collection: %w", err)
 	}
 
-	// Update collection account balance
-	newBalance := account.CurrentBalance + amountCollected
-	updateQuery := `UPDATE project_collection_accounts 
-		SET current_balance = ?, updated_at = ? WHERE id = ?`
-
-	_, err = s.DB.Exec(updateQuery, newBalance, time.Now(), collectionAccountID)
+	// Projects with designated accounts split the receipt instead of crediting one account.
+	// A receipt against a booking is split when the payment is recorded on the booking,
+	// which is the authoritative source, so its collection entry moves no money again.
+	policy, err := s.getEscrowPolicy(tx, tenantID, "project_id = ?", projectID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update account balance: %w", err)
+		return nil, err
+	}
+	if policy != nil && bookingID == "" {
+		if _, err := s.splitReceipt(tx, tenantID, projectID, models.RERAReceiptSourceCollection, collection.ID, bookingID, amountCollected); err != nil {
+			return nil, err
+		}
+	}
+	if policy == nil {
+		// Update collection account balance
+		newBalance := account.CurrentBalance + amountCollected
+		updateQuery := `UPDATE project_collection_accounts 
+			SET current_balance = ?, updated_at = ? WHERE id = ?`
+
+		_, err = tx.Exec(updateQuery, newBalance, time.Now(), collectionAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update account balance: %w", err)
+		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit collection: %w", err)
+	}
 	return collection, nil
 }
 
@@ -153,6 +179,27 @@ func (s *RERAComplianceService) RecordFundUtilization(
 	billNumber string,
 ) (*models.ProjectFundUtilization, error) {
 
+	// Spending from a designated RERA account is a withdrawal and is capped by certified completion
+	policy, err := s.getEscrowPolicy(s.DB, tenantID, "rera_account_id = ?", collectionAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		withdrawal, utilization, err := s.withdraw(tenantID, "", policy, &models.RERAWithdrawalRequest{
+			Amount:          amountUtilized,
+			UtilizationType: utilizationType,
+			Description:     description,
+			BillNumber:      billNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if withdrawal.Status == models.RERAWithdrawalBlocked {
+			return nil, fmt.Errorf("utilisation blocked: %s", *withdrawal.BlockReason)
+		}
+		return utilization, nil
+	}
+
 	utilization := &models.ProjectFundUtilization{
 		ID:                  fmt.Sprintf("PFU-%d", time.Now().UnixNano()),
 		TenantID:            tenantID,
@@ -167,20 +214,8 @@ func (s *RERAComplianceService) RecordFundUtilization(
 		UpdatedAt:           time.Now(),
 	}
 
-	query := `INSERT INTO project_fund_utilization 
-		(id, tenant_id, project_id, collection_account_id, utilization_date, utilization_type, 
-		 description, amount_utilized, bill_number, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_, err := s.DB.Exec(query,
-		utilization.ID, utilization.TenantID, utilization.ProjectID,
-		utilization.CollectionAccountID, utilization.UtilizationDate, utilization.UtilizationType,
-		utilization.Description, utilization.AmountUtilized, utilization.BillNumber,
-		utilization.CreatedAt, utilization.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to record fund utilization: %w", err)
+	if err := insertFundUtilization(s.DB, utilization); err != nil {
+		return nil, err
 	}
 
 	// Update collection account balance
@@ -432,3 +467,510 @@ func (s *RERAComplianceService) GetRERAComplianceMetrics(tenantID string) (map[s
 
 	return metrics, nil
 }
+
+// ============================================================================
+// RERA ESCROW ENFORCEMENT
+// ============================================================================
+
+// SetEscrowPolicy designates the project's RERA and free accounts and the share of
+// every receipt that must land in the RERA account (at least 70%)
+func (s *RERAComplianceService) SetEscrowPolicy(tenantID, projectID string, req *models.SetRERAEscrowPolicyRequest) (*models.RERAEscrowPolicy, error) {
+	percent := req.EscrowPercent
+	if percent == 0 {
+		percent = models.MinRERAEscrowPercent
+	}
+	if percent < models.MinRERAEscrowPercent || percent > 100 {
+		return nil, fmt.Errorf("escrow percent must be between %.0f and 100", models.MinRERAEscrowPercent)
+	}
+	if req.RERAAccountID == "" || req.FreeAccountID == "" {
+		return nil, fmt.Errorf("rera_account_id and free_account_id are required")
+	}
+	if req.RERAAccountID == req.FreeAccountID {
+		return nil, fmt.Errorf("the RERA and free accounts must be different")
+	}
+	for _, accountID := range []string{req.RERAAccountID, req.FreeAccountID} {
+		account, err := s.GetCollectionAccount(tenantID, accountID)
+		if err != nil {
+			return nil, err
+		}
+		if account.ProjectID != projectID {
+			return nil, fmt.Errorf("collection account %s belongs to another project", accountID)
+		}
+	}
+
+	now := time.Now()
+	_, err := s.DB.Exec(`INSERT INTO rera_escrow_policies
+		(id, tenant_id, project_id, rera_account_id, free_account_id, escrow_percent, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE rera_account_id = VALUES(rera_account_id), free_account_id = VALUES(free_account_id),
+		escrow_percent = VALUES(escrow_percent), updated_at = VALUES(updated_at)`,
+		uuid.New().String(), tenantID, projectID, req.RERAAccountID, req.FreeAccountID, percent, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save escrow policy: %w", err)
+	}
+
+	return s.getEscrowPolicy(s.DB, tenantID, "project_id = ?", projectID)
+}
+
+// SplitBookingReceiptTx splits a customer payment recorded against a booking between
+// the project's designated accounts, inside the transaction that records the payment.
+// Projects without a policy are left alone.
+func (s *RERAComplianceService) SplitBookingReceiptTx(tx *sql.Tx, tenantID, bookingID, paymentID string, amount float64) (*models.RERAReceiptSplit, error) {
+	var projectID string
+	err := tx.QueryRow(`SELECT COALESCE(u.project_id, '') FROM customer_bookings b
+		JOIN property_units u ON u.id = b.unit_id
+		WHERE b.tenant_id = ? AND b.id = ?`, tenantID, bookingID).Scan(&projectID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking project: %w", err)
+	}
+
+	return s.splitReceipt(tx, tenantID, projectID, models.RERAReceiptSourceBookingPayment, paymentID, bookingID, amount)
+}
+
+// CertifyCompletion records a certified percentage of completion. The engineer's,
+// architect's and CA's certificates must already be attached to the project.
+func (s *RERAComplianceService) CertifyCompletion(tenantID, userID, projectID string, req *models.CertifyCompletionRequest) (*models.RERACompletionCertificate, error) {
+	previous, err := s.latestCertificate(s.DB, tenantID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCompletionCertificate(req, previous); err != nil {
+		return nil, err
+	}
+	if err := s.Documents.RequireAttachments(tenantID, "project", projectID,
+		req.EngineerDocumentID, req.ArchitectDocumentID, req.CADocumentID); err != nil {
+		return nil, err
+	}
+
+	cert := &models.RERACompletionCertificate{
+		ID:                  uuid.New().String(),
+		TenantID:            tenantID,
+		ProjectID:           projectID,
+		CompletionPercent:   req.CompletionPercent,
+		EngineerDocumentID:  req.EngineerDocumentID,
+		ArchitectDocumentID: req.ArchitectDocumentID,
+		CADocumentID:        req.CADocumentID,
+		CertifiedOn:         time.Now(),
+		Remarks:             optionalString(req.Remarks),
+		CreatedBy:           optionalString(userID),
+		CreatedAt:           time.Now(),
+	}
+	if req.CertifiedOn != nil {
+		cert.CertifiedOn = *req.CertifiedOn
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO rera_completion_certificates
+		(id, tenant_id, project_id, completion_percent, engineer_document_id, architect_document_id,
+		 ca_document_id, certified_on, remarks, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		cert.ID, tenantID, projectID, cert.CompletionPercent, cert.EngineerDocumentID, cert.ArchitectDocumentID,
+		cert.CADocumentID, cert.CertifiedOn, cert.Remarks, cert.CreatedBy, cert.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record completion certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+// ListCertificates lists a project's completion certificates, latest first
+func (s *RERAComplianceService) ListCertificates(tenantID, projectID string) ([]models.RERACompletionCertificate, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, completion_percent, engineer_document_id,
+		architect_document_id, ca_document_id, certified_on, remarks, created_by, created_at
+		FROM rera_completion_certificates WHERE tenant_id = ? AND project_id = ?
+		ORDER BY certified_on DESC, created_at DESC`, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completion certificates: %w", err)
+	}
+	defer rows.Close()
+
+	certs := []models.RERACompletionCertificate{}
+	for rows.Next() {
+		cert, err := scanCompletionCertificate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan completion certificate: %w", err)
+		}
+		certs = append(certs, *cert)
+	}
+	return certs, rows.Err()
+}
+
+// RequestWithdrawal withdraws from the project's RERA account when the amount is within
+// the limit allowed by certified completion. Every request is entered in the withdrawal
+// register; one over the limit is recorded as blocked and no funds move.
+func (s *RERAComplianceService) RequestWithdrawal(tenantID, userID, projectID string, req *models.RERAWithdrawalRequest) (*models.RERAWithdrawal, error) {
+	policy, err := s.getEscrowPolicy(s.DB, tenantID, "project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("project has no designated RERA account")
+	}
+
+	withdrawal, _, err := s.withdraw(tenantID, userID, policy, req)
+	return withdrawal, err
+}
+
+// ListWithdrawals returns the withdrawal register, optionally filtered by status
+func (s *RERAComplianceService) ListWithdrawals(tenantID, projectID, status string) ([]models.RERAWithdrawal, error) {
+	query := `SELECT id, tenant_id, project_id, withdrawal_number, rera_account_id, certificate_id,
+		utilization_type, COALESCE(description, ''), COALESCE(bill_number, ''), amount, rera_deposits,
+		completion_percent, allowable_limit, previously_withdrawn, available_before, status, block_reason,
+		utilization_id, requested_by, requested_at
+		FROM rera_withdrawals WHERE tenant_id = ? AND project_id = ?`
+	args := []interface{}{tenantID, projectID}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY requested_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+	defer rows.Close()
+
+	register := []models.RERAWithdrawal{}
+	for rows.Next() {
+		var w models.RERAWithdrawal
+		var certificateID, blockReason, utilizationID, requestedBy sql.NullString
+		if err := rows.Scan(&w.ID, &w.TenantID, &w.ProjectID, &w.WithdrawalNumber, &w.RERAAccountID, &certificateID,
+			&w.UtilizationType, &w.Description, &w.BillNumber, &w.Amount, &w.RERADeposits,
+			&w.CompletionPercent, &w.AllowableLimit, &w.PreviouslyWithdrawn, &w.AvailableBefore, &w.Status, &blockReason,
+			&utilizationID, &requestedBy, &w.RequestedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan withdrawal: %w", err)
+		}
+		w.CertificateID = nullStringPtr(certificateID)
+		w.BlockReason = nullStringPtr(blockReason)
+		w.UtilizationID = nullStringPtr(utilizationID)
+		w.RequestedBy = nullStringPtr(requestedBy)
+		register = append(register, w)
+	}
+	return register, rows.Err()
+}
+
+// ListReceiptSplits lists how a project's receipts were divided between its accounts
+func (s *RERAComplianceService) ListReceiptSplits(tenantID, projectID string) ([]models.RERAReceiptSplit, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, source_type, source_id, COALESCE(booking_id, ''),
+		receipt_amount, escrow_percent, rera_account_id, rera_amount, free_account_id, free_amount, created_at
+		FROM rera_receipt_splits WHERE tenant_id = ? AND project_id = ? ORDER BY created_at DESC`, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipt splits: %w", err)
+	}
+	defer rows.Close()
+
+	splits := []models.RERAReceiptSplit{}
+	for rows.Next() {
+		var sp models.RERAReceiptSplit
+		if err := rows.Scan(&sp.ID, &sp.TenantID, &sp.ProjectID, &sp.SourceType, &sp.SourceID, &sp.BookingID,
+			&sp.ReceiptAmount, &sp.EscrowPercent, &sp.RERAAccountID, &sp.RERAAmount, &sp.FreeAccountID,
+			&sp.FreeAmount, &sp.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan receipt split: %w", err)
+		}
+		splits = append(splits, sp)
+	}
+	return splits, rows.Err()
+}
+
+// GetEscrowPosition returns the project's designated account deposits, certified
+// completion and how much may still be withdrawn
+func (s *RERAComplianceService) GetEscrowPosition(tenantID, projectID string) (*models.RERAEscrowPosition, error) {
+	policy, err := s.getEscrowPolicy(s.DB, tenantID, "project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("project has no designated RERA account")
+	}
+	return s.escrowPosition(s.DB, tenantID, policy)
+}
+
+// splitReceipt credits the RERA and free accounts with their shares of a receipt
+func (s *RERAComplianceService) splitReceipt(tx *sql.Tx, tenantID, projectID, sourceType, sourceID, bookingID string, amount float64) (*models.RERAReceiptSplit, error) {
+	policy, err := s.getEscrowPolicy(tx, tenantID, "project_id = ?", projectID)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	reraAmount, freeAmount := splitEscrowReceipt(amount, policy.EscrowPercent)
+	split := &models.RERAReceiptSplit{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		ProjectID:     projectID,
+		SourceType:    sourceType,
+		SourceID:      sourceID,
+		BookingID:     bookingID,
+		ReceiptAmount: amount,
+		EscrowPercent: policy.EscrowPercent,
+		RERAAccountID: policy.RERAAccountID,
+		RERAAmount:    reraAmount,
+		FreeAccountID: policy.FreeAccountID,
+		FreeAmount:    freeAmount,
+		CreatedAt:     time.Now(),
+	}
+
+	_, err = tx.Exec(`INSERT INTO rera_receipt_splits
+		(id, tenant_id, project_id, source_type, source_id, booking_id, receipt_amount, escrow_percent,
+		 rera_account_id, rera_amount, free_account_id, free_amount, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		split.ID, tenantID, projectID, sourceType, sourceID, nullIfEmpty(bookingID), amount, split.EscrowPercent,
+		split.RERAAccountID, reraAmount, split.FreeAccountID, freeAmount, split.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record receipt split: %w", err)
+	}
+
+	for accountID, share := range map[string]float64{split.RERAAccountID: reraAmount, split.FreeAccountID: freeAmount} {
+		if _, err := tx.Exec(`UPDATE project_collection_accounts SET current_balance = current_balance + ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, share, split.CreatedAt, accountID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update account balance: %w", err)
+		}
+	}
+	return split, nil
+}
+
+// withdraw evaluates a withdrawal against the allowable limit and enters it in the
+// register, recording the fund utilization when it is approved
+func (s *RERAComplianceService) withdraw(tenantID, userID string, policy *models.RERAEscrowPolicy, req *models.RERAWithdrawalRequest) (*models.RERAWithdrawal, *models.ProjectFundUtilization, error) {
+	if req.Amount <= 0 {
+		return nil, nil, fmt.Errorf("withdrawal amount must be positive")
+	}
+	if req.UtilizationType == "" {
+		return nil, nil, fmt.Errorf("utilization_type is required")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the policy so concurrent withdrawals cannot both spend the same headroom
+	var balance float64
+	err = tx.QueryRow(`SELECT a.current_balance FROM rera_escrow_policies p
+		JOIN project_collection_accounts a ON a.id = p.rera_account_id
+		WHERE p.id = ? FOR UPDATE`, policy.ID).Scan(&balance)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock RERA account: %w", err)
+	}
+
+	pos, err := s.escrowPosition(tx, tenantID, policy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	w := &models.RERAWithdrawal{
+		ID:                  uuid.New().String(),
+		TenantID:            tenantID,
+		ProjectID:           policy.ProjectID,
+		WithdrawalNumber:    interestNoteNumber("RW"),
+		RERAAccountID:       policy.RERAAccountID,
+		UtilizationType:     req.UtilizationType,
+		Description:         req.Description,
+		BillNumber:          req.BillNumber,
+		Amount:              roundTo2(req.Amount),
+		RERADeposits:        pos.RERADeposits,
+		CompletionPercent:   pos.CompletionPercent,
+		AllowableLimit:      pos.AllowableLimit,
+		PreviouslyWithdrawn: pos.TotalWithdrawn,
+		AvailableBefore:     pos.AvailableToWithdraw,
+		Status:              models.RERAWithdrawalApproved,
+		RequestedBy:         optionalString(userID),
+		RequestedAt:         now,
+	}
+	if pos.Certificate != nil {
+		w.CertificateID = &pos.Certificate.ID
+	}
+
+	var utilization *models.ProjectFundUtilization
+	if reason := withdrawalBlockReason(w.Amount, pos, balance); reason != "" {
+		w.Status = models.RERAWithdrawalBlocked
+		w.BlockReason = &reason
+	} else {
+		utilization = &models.ProjectFundUtilization{
+			ID:                  fmt.Sprintf("PFU-%d", now.UnixNano()),
+			TenantID:            tenantID,
+			ProjectID:           policy.ProjectID,
+			CollectionAccountID: policy.RERAAccountID,
+			UtilizationDate:     now,
+			UtilizationType:     req.UtilizationType,
+			Description:         req.Description,
+			AmountUtilized:      w.Amount,
+			BillNumber:          req.BillNumber,
+			ApprovedBy:          optionalString(userID),
+			ApprovalDate:        &now,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if err := insertFundUtilization(tx, utilization); err != nil {
+			return nil, nil, err
+		}
+		if _, err := tx.Exec(`UPDATE project_collection_accounts SET current_balance = current_balance - ?, updated_at = ?
+			WHERE id = ? AND tenant_id = ?`, w.Amount, now, policy.RERAAccountID, tenantID); err != nil {
+			return nil, nil, fmt.Errorf("failed to update account balance: %w", err)
+		}
+		w.UtilizationID = &utilization.ID
+	}
+
+	_, err = tx.Exec(`INSERT INTO rera_withdrawals
+		(id, tenant_id, project_id, withdrawal_number, rera_account_id, certificate_id, utilization_type,
+		 description, bill_number, amount, rera_deposits, completion_percent, allowable_limit,
+		 previously_withdrawn, available_before, status, block_reason, utilization_id, requested_by, requested_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		w.ID, tenantID, w.ProjectID, w.WithdrawalNumber, w.RERAAccountID, w.CertificateID, w.UtilizationType,
+		nullIfEmpty(w.Description), nullIfEmpty(w.BillNumber), w.Amount, w.RERADeposits, w.CompletionPercent,
+		w.AllowableLimit, w.PreviouslyWithdrawn, w.AvailableBefore, w.Status, w.BlockReason, w.UtilizationID,
+		w.RequestedBy, w.RequestedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record withdrawal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit withdrawal: %w", err)
+	}
+	return w, utilization, nil
+}
+
+// escrowPosition totals deposits and approved withdrawals against the latest certificate
+func (s *RERAComplianceService) escrowPosition(db sqlRowQuerier, tenantID string, policy *models.RERAEscrowPolicy) (*models.RERAEscrowPosition, error) {
+	pos := &models.RERAEscrowPosition{ProjectID: policy.ProjectID, Policy: policy}
+
+	err := db.QueryRow(`SELECT COALESCE(SUM(receipt_amount), 0), COALESCE(SUM(rera_amount), 0), COALESCE(SUM(free_amount), 0)
+		FROM rera_receipt_splits WHERE tenant_id = ? AND project_id = ?`, tenantID, policy.ProjectID).Scan(
+		&pos.TotalReceipts, &pos.RERADeposits, &pos.FreeDeposits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total RERA deposits: %w", err)
+	}
+
+	err = db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM rera_withdrawals
+		WHERE tenant_id = ? AND project_id = ? AND status = ?`, tenantID, policy.ProjectID, models.RERAWithdrawalApproved).Scan(&pos.TotalWithdrawn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total RERA withdrawals: %w", err)
+	}
+
+	if pos.Certificate, err = s.latestCertificate(db, tenantID, policy.ProjectID); err != nil {
+		return nil, err
+	}
+	if pos.Certificate != nil {
+		pos.CompletionPercent = pos.Certificate.CompletionPercent
+	}
+
+	pos.AllowableLimit, pos.AvailableToWithdraw = escrowWithdrawalLimit(pos.RERADeposits, pos.CompletionPercent, pos.TotalWithdrawn)
+	return pos, nil
+}
+
+func (s *RERAComplianceService) latestCertificate(db sqlRowQuerier, tenantID, projectID string) (*models.RERACompletionCertificate, error) {
+	cert, err := scanCompletionCertificate(db.QueryRow(`SELECT id, tenant_id, project_id, completion_percent,
+		engineer_document_id, architect_document_id, ca_document_id, certified_on, remarks, created_by, created_at
+		FROM rera_completion_certificates WHERE tenant_id = ? AND project_id = ?
+		ORDER BY certified_on DESC, created_at DESC LIMIT 1`, tenantID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch completion certificate: %w", err)
+	}
+	return cert, nil
+}
+
+func (s *RERAComplianceService) getEscrowPolicy(db sqlRowQuerier, tenantID, where string, args ...interface{}) (*models.RERAEscrowPolicy, error) {
+	p := &models.RERAEscrowPolicy{}
+	err := db.QueryRow(`SELECT id, tenant_id, project_id, rera_account_id, free_account_id, escrow_percent, created_at, updated_at
+		FROM rera_escrow_policies WHERE tenant_id = ? AND `+where,
+		append([]interface{}{tenantID}, args...)...).Scan(
+		&p.ID, &p.TenantID, &p.ProjectID, &p.RERAAccountID, &p.FreeAccountID, &p.EscrowPercent, &p.CreatedAt, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch escrow policy: %w", err)
+	}
+	return p, nil
+}
+
+type sqlRowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func scanCompletionCertificate(scanner interface{ Scan(...interface{}) error }) (*models.RERACompletionCertificate, error) {
+	cert := &models.RERACompletionCertificate{}
+	var remarks, createdBy sql.NullString
+	if err := scanner.Scan(&cert.ID, &cert.TenantID, &cert.ProjectID, &cert.CompletionPercent, &cert.EngineerDocumentID,
+		&cert.ArchitectDocumentID, &cert.CADocumentID, &cert.CertifiedOn, &remarks, &createdBy, &cert.CreatedAt); err != nil {
+		return nil, err
+	}
+	cert.Remarks = nullStringPtr(remarks)
+	cert.CreatedBy = nullStringPtr(createdBy)
+	return cert, nil
+}
+
+func insertFundUtilization(db sqlExecer, u *models.ProjectFundUtilization) error {
+	_, err := db.Exec(`INSERT INTO project_fund_utilization 
+		(id, tenant_id, project_id, collection_account_id, utilization_date, utilization_type, 
+		 description, amount_utilized, bill_number, approved_by, approval_date, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		u.ID, u.TenantID, u.ProjectID, u.CollectionAccountID, u.UtilizationDate, u.UtilizationType,
+		u.Description, u.AmountUtilized, u.BillNumber, u.ApprovedBy, u.ApprovalDate, u.CreatedAt, u.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record fund utilization: %w", err)
+	}
+	return nil
+}
+
+// splitEscrowReceipt returns the RERA and free account shares of a receipt
+func splitEscrowReceipt(amount, escrowPercent float64) (float64, float64) {
+	rera := roundTo2(amount * escrowPercent / 100)
+	return rera, roundTo2(amount - rera)
+}
+
+// escrowWithdrawalLimit caps cumulative withdrawals at the certified share of deposits
+func escrowWithdrawalLimit(deposits, completionPercent, withdrawn float64) (float64, float64) {
+	limit := roundTo2(deposits * completionPercent / 100)
+	available := roundTo2(limit - withdrawn)
+	if available < 0 {
+		available = 0
+	}
+	return limit, available
+}
+
+// withdrawalBlockReason explains why a withdrawal cannot proceed, or returns ""
+func withdrawalBlockReason(amount float64, pos *models.RERAEscrowPosition, balance float64) string {
+	switch {
+	case pos.Certificate == nil:
+		return "no certified percentage of completion on record"
+	case amount > pos.AvailableToWithdraw:
+		return fmt.Sprintf("Rs. %.2f exceeds the Rs. %.2f still allowable at %.2f%% certified completion",
+			amount, pos.AvailableToWithdraw, pos.CompletionPercent)
+	case amount > balance:
+		return fmt.Sprintf("Rs. %.2f exceeds the RERA account balance of Rs. %.2f", amount, balance)
+	}
+	return ""
+}
+
+// validateCompletionCertificate checks a certificate carries all three documents and
+// does not certify less completion than the one before it
+func validateCompletionCertificate(req *models.CertifyCompletionRequest, previous *models.RERACompletionCertificate) error {
+	if req.CompletionPercent <= 0 || req.CompletionPercent > 100 {
+		return fmt.Errorf("completion percent must be between 0 and 100")
+	}
+	if req.EngineerDocumentID == "" || req.ArchitectDocumentID == "" || req.CADocumentID == "" {
+		return fmt.Errorf("engineer, architect and CA certificates are all required")
+	}
+	if req.EngineerDocumentID == req.ArchitectDocumentID || req.EngineerDocumentID == req.CADocumentID ||
+		req.ArchitectDocumentID == req.CADocumentID {
+		return fmt.Errorf("engineer, architect and CA certificates must be separate documents")
+	}
+	if previous != nil && req.CompletionPercent < previous.CompletionPercent {
+		return fmt.Errorf("completion cannot fall below the %.2f%% already certified", previous.CompletionPercent)
+	}
+	return nil
+}
+
+// optionalString returns nil for an empty string so it is stored as NULL
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSplitEscrowReceipt tests dividing a receipt between the RERA and free accounts
+func TestSplitEscrowReceipt(t *testing.T) {
+	rera, free := splitEscrowReceipt(1000000, 70)
+	assert.Equal(t, 700000.0, rera)
+	assert.Equal(t, 300000.0, free)
+
+	rera, free = splitEscrowReceipt(333333.33, 70)
+	assert.Equal(t, 233333.33, rera)
+	assert.Equal(t, 100000.0, free)
+	assert.InDelta(t, 333333.33, rera+free, 0.001)
+
+	rera, free = splitEscrowReceipt(500000, 100)
+	assert.Equal(t, 500000.0, rera)
+	assert.Equal(t, 0.0, free)
+}
+
+// TestEscrowWithdrawalLimit tests the withdrawal cap against certified completion
+func TestEscrowWithdrawalLimit(t *testing.T) {
+	limit, available := escrowWithdrawalLimit(10000000, 35, 2000000)
+	assert.Equal(t, 3500000.0, limit)
+	assert.Equal(t, 1500000.0, available)
+
+	// Withdrawn beyond the current limit leaves nothing available rather than a negative
+	_, available = escrowWithdrawalLimit(10000000, 10, 2000000)
+	assert.Equal(t, 0.0, available)
+
+	cert := &models.RERACompletionCertificate{ID: "c1", CompletionPercent: 35}
+	pos := &models.RERAEscrowPosition{Certificate: cert, CompletionPercent: 35, AvailableToWithdraw: 1500000}
+	assert.Equal(t, "", withdrawalBlockReason(1500000, pos, 5000000))
+	assert.Contains(t, withdrawalBlockReason(1500000.01, pos, 5000000), "still allowable at 35.00%")
+	assert.Contains(t, withdrawalBlockReason(1000000, pos, 800000), "account balance")
+	assert.Contains(t, withdrawalBlockReason(1, &models.RERAEscrowPosition{AvailableToWithdraw: 100}, 100), "no certified percentage")
+}
+
+// TestValidateCompletionCertificate tests the three-certificate and monotonic completion rules
+func TestValidateCompletionCertificate(t *testing.T) {
+	req := &models.CertifyCompletionRequest{
+		CompletionPercent:   40,
+		EngineerDocumentID:  "doc-eng",
+		ArchitectDocumentID: "doc-arch",
+		CADocumentID:        "doc-ca",
+	}
+	assert.NoError(t, validateCompletionCertificate(req, nil))
+	assert.NoError(t, validateCompletionCertificate(req, &models.RERACompletionCertificate{CompletionPercent: 40}))
+	assert.Error(t, validateCompletionCertificate(req, &models.RERACompletionCertificate{CompletionPercent: 45}))
+
+	missing := *req
+	missing.CADocumentID = ""
+	assert.Error(t, validateCompletionCertificate(&missing, nil))
+
+	reused := *req
+	reused.ArchitectDocumentID = req.EngineerDocumentID
+	assert.Error(t, validateCompletionCertificate(&reused, nil))
+
+	over := *req
+	over.CompletionPercent = 101
+	assert.Error(t, validateCompletionCertificate(&over, nil))
+}
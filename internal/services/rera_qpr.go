@@ -0,0 +1,1036 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+// ============================================================================
+// RERA QUARTERLY PROGRESS REPORT (QPR)
+// ============================================================================
+// Pre-fills the state portal's QPR from bookings, project_collection_ledger,
+// tower milestones (with their progress_tracking evidence), site permits and
+// project_fund_utilization. Figures are taken as at quarter end so a rebuilt
+// draft matches what the portal expects for that quarter.
+
+const qprDateLayout = "02-01-2006"
+
+// ParseQPRQuarter parses a YYYY-Qn quarter into its date range [from, to)
+func ParseQPRQuarter(quarter string) (time.Time, time.Time, error) {
+	var year, q int
+	if _, err := fmt.Sscanf(quarter, "%4d-Q%1d", &year, &q); err != nil || q < 1 || q > 4 ||
+		fmt.Sprintf("%04d-Q%d", year, q) != quarter {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid quarter %q, expected YYYY-Qn", quarter)
+	}
+	from := time.Date(year, time.Month((q-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 3, 0), nil
+}
+
+// previousQPRQuarter returns the quarter before a valid YYYY-Qn quarter
+func previousQPRQuarter(quarter string) string {
+	from, _, err := ParseQPRQuarter(quarter)
+	if err != nil {
+		return ""
+	}
+	prev := from.AddDate(0, -3, 0)
+	return fmt.Sprintf("%04d-Q%d", prev.Year(), (int(prev.Month())-1)/3+1)
+}
+
+// ============================================================================
+// REPORT GENERATION
+// ============================================================================
+
+// GenerateQPR builds the project's draft QPR for a quarter and validates it against
+// the previous quarter. A submitted QPR is locked and cannot be rebuilt.
+func (s *RERAComplianceService) GenerateQPR(tenantID, userID, projectID string, req *models.GenerateQPRRequest) (*models.QuarterlyProgressReport, error) {
+	from, to, err := ParseQPRQuarter(req.Quarter)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getQPR(tenantID, "project_id = ? AND quarter = ?", projectID, req.Quarter)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == models.QPRStatusSubmitted {
+		return nil, fmt.Errorf("QPR for %s was submitted and is locked", req.Quarter)
+	}
+
+	var projectName, state string
+	err = s.DB.QueryRow(`SELECT project_name, COALESCE(state, '') FROM property_projects
+		WHERE tenant_id = ? AND id = ? AND deleted_at IS NULL`, tenantID, projectID).Scan(&projectName, &state)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch project: %w", err)
+	}
+	stateCode := normalizeStateCode(req.StateCode)
+	if stateCode == "" {
+		stateCode = normalizeStateCode(state)
+	}
+
+	data, err := s.buildQPRData(tenantID, projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	prevQuarter := previousQPRQuarter(req.Quarter)
+	prior, err := s.getQPR(tenantID, "project_id = ? AND quarter = ?", projectID, prevQuarter)
+	if err != nil {
+		return nil, err
+	}
+	var priorData *models.QPRData
+	var priorStatus string
+	if prior != nil {
+		priorData, priorStatus = &prior.Data, prior.Status
+	}
+	issues := validateQPR(data, priorData, prevQuarter, priorStatus, to)
+
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QPR data: %w", err)
+	}
+	issuesJSON, err := json.Marshal(issues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QPR issues: %w", err)
+	}
+
+	// The IF guards keep a QPR submitted between the check above and this write intact
+	now := time.Now()
+	_, err = s.DB.Exec(`INSERT INTO rera_qpr_reports
+		(id, tenant_id, project_id, quarter, state_code, period_from, period_to, status, data, issues,
+		 generated_by, generated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+		 state_code = IF(status = 'draft', VALUES(state_code), state_code),
+		 data = IF(status = 'draft', VALUES(data), data),
+		 issues = IF(status = 'draft', VALUES(issues), issues),
+		 generated_by = IF(status = 'draft', VALUES(generated_by), generated_by),
+		 generated_at = IF(status = 'draft', VALUES(generated_at), generated_at)`,
+		uuid.New().String(), tenantID, projectID, req.Quarter, stateCode, from, to, models.QPRStatusDraft,
+		dataJSON, issuesJSON, optionalString(userID), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save QPR: %w", err)
+	}
+
+	report, err := s.getQPR(tenantID, "project_id = ? AND quarter = ?", projectID, req.Quarter)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status == models.QPRStatusSubmitted {
+		return nil, fmt.Errorf("QPR for %s was submitted and is locked", req.Quarter)
+	}
+	return report, nil
+}
+
+// GetQPR returns a QPR by ID
+func (s *RERAComplianceService) GetQPR(tenantID, reportID string) (*models.QuarterlyProgressReport, error) {
+	report, err := s.getQPR(tenantID, "id = ?", reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("QPR not found")
+	}
+	return report, nil
+}
+
+// ListQPRs lists a project's QPRs, latest quarter first
+func (s *RERAComplianceService) ListQPRs(tenantID, projectID string) ([]models.QuarterlyProgressReport, error) {
+	rows, err := s.DB.Query(qprSelect+` WHERE r.tenant_id = ? AND r.project_id = ? ORDER BY r.quarter DESC`, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list QPRs: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.QuarterlyProgressReport{}
+	for rows.Next() {
+		report, err := scanQPR(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan QPR: %w", err)
+		}
+		reports = append(reports, *report)
+	}
+	return reports, rows.Err()
+}
+
+// SubmitQPR locks a draft QPR once it has been filed on the portal. Drafts with
+// validation errors cannot be submitted.
+func (s *RERAComplianceService) SubmitQPR(tenantID, userID, reportID string, req *models.SubmitQPRRequest) (*models.QuarterlyProgressReport, error) {
+	report, err := s.GetQPR(tenantID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != models.QPRStatusDraft {
+		return nil, fmt.Errorf("QPR is already %s", report.Status)
+	}
+	if n := countQPRErrors(report.Issues); n > 0 {
+		return nil, fmt.Errorf("QPR has %d validation error(s) to resolve before submission", n)
+	}
+
+	res, err := s.DB.Exec(`UPDATE rera_qpr_reports SET status = ?, acknowledgement_number = ?, submitted_by = ?, submitted_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.QPRStatusSubmitted, nullIfEmpty(req.AcknowledgementNumber), optionalString(userID), time.Now(),
+		reportID, tenantID, models.QPRStatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit QPR: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("QPR is already submitted")
+	}
+
+	return s.GetQPR(tenantID, reportID)
+}
+
+// ExportQPR renders a QPR in its state's template as "excel" or "pdf", returning
+// the file and a suggested file name
+func (s *RERAComplianceService) ExportQPR(tenantID, reportID, format string) ([]byte, string, error) {
+	report, err := s.GetQPR(tenantID, reportID)
+	if err != nil {
+		return nil, "", err
+	}
+	tpl, err := s.GetQPRTemplate(tenantID, report.StateCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := fmt.Sprintf("QPR_%s_%s", report.StateCode, report.Quarter)
+	switch format {
+	case "excel":
+		data, err := renderQPRExcel(report, tpl)
+		return data, name + ".xlsx", err
+	case "pdf":
+		return renderTextPDF(renderQPRLines(report, tpl)), name + ".pdf", nil
+	}
+	return nil, "", fmt.Errorf("unsupported export format %q, expected excel or pdf", format)
+}
+
+// buildQPRData gathers the report figures as at the end of the quarter
+func (s *RERAComplianceService) buildQPRData(tenantID, projectID string, from, to time.Time) (models.QPRData, error) {
+	data := models.QPRData{}
+
+	// Units per tower, counting a unit as booked when its booking predates quarter end
+	// and was not cancelled before it
+	rows, err := s.DB.Query(`SELECT COALESCE(u.block_id, ''), COALESCE(blk.block_name, ''), COUNT(*),
+		SUM(CASE WHEN EXISTS (SELECT 1 FROM customer_bookings b
+			WHERE b.tenant_id = u.tenant_id AND b.unit_id = u.id AND b.booking_date < ?
+			AND NOT EXISTS (SELECT 1 FROM booking_cancellations c WHERE c.tenant_id = b.tenant_id AND c.booking_id = b.id
+				AND c.status IN ('approved', 'refunded') AND c.approved_at < ?)) THEN 1 ELSE 0 END)
+		FROM property_units u
+		LEFT JOIN property_blocks blk ON blk.id = u.block_id
+		WHERE u.tenant_id = ? AND u.project_id = ? AND u.deleted_at IS NULL
+		GROUP BY u.block_id, blk.block_name ORDER BY blk.block_name`, to, to, tenantID, projectID)
+	if err != nil {
+		return data, fmt.Errorf("failed to count project units: %w", err)
+	}
+	towers := []models.QPRTowerProgress{}
+	for rows.Next() {
+		var t models.QPRTowerProgress
+		if err := rows.Scan(&t.BlockID, &t.BlockName, &t.TotalUnits, &t.BookedUnits); err != nil {
+			rows.Close()
+			return data, fmt.Errorf("failed to scan unit counts: %w", err)
+		}
+		towers = append(towers, t)
+	}
+	rows.Close()
+
+	err = s.DB.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM customer_bookings b JOIN property_units u ON u.id = b.unit_id
+			WHERE b.tenant_id = ? AND u.project_id = ? AND b.booking_date >= ? AND b.booking_date < ?),
+		(SELECT COUNT(*) FROM booking_cancellations c JOIN property_units u ON u.id = c.unit_id
+			WHERE c.tenant_id = ? AND u.project_id = ? AND c.status IN ('approved', 'refunded')
+			AND c.approved_at >= ? AND c.approved_at < ?)`,
+		tenantID, projectID, from, to, tenantID, projectID, from, to).Scan(&data.Units.BookedInQuarter, &data.Units.CancelledInQuarter)
+	if err != nil {
+		return data, fmt.Errorf("failed to count quarter bookings: %w", err)
+	}
+
+	milestones, err := s.qprMilestones(tenantID, projectID, to)
+	if err != nil {
+		return data, err
+	}
+	data.Towers = buildTowerProgress(towers, milestones)
+	for _, t := range data.Towers {
+		data.Units.TotalUnits += t.TotalUnits
+		data.Units.BookedCumulative += t.BookedUnits
+	}
+	data.Units.UnsoldUnits = data.Units.TotalUnits - data.Units.BookedCumulative
+
+	c := &data.Collections
+	err = s.DB.QueryRow(`SELECT COALESCE(SUM(CASE WHEN collection_date >= ? THEN amount_collected ELSE 0 END), 0),
+		COALESCE(SUM(amount_collected), 0)
+		FROM project_collection_ledger WHERE tenant_id = ? AND project_id = ? AND collection_date < ?
+		AND status NOT IN ('Reversed', 'Cancelled') AND deleted_at IS NULL`,
+		from, tenantID, projectID, to).Scan(&c.Quarter, &c.Cumulative)
+	if err != nil {
+		return data, fmt.Errorf("failed to total collections: %w", err)
+	}
+	err = s.DB.QueryRow(`SELECT COALESCE(SUM(CASE WHEN created_at >= ? THEN rera_amount ELSE 0 END), 0),
+		COALESCE(SUM(rera_amount), 0)
+		FROM rera_receipt_splits WHERE tenant_id = ? AND project_id = ? AND created_at < ?`,
+		from, tenantID, projectID, to).Scan(&c.RERADepositedQuarter, &c.RERADepositedCumulative)
+	if err != nil {
+		return data, fmt.Errorf("failed to total RERA deposits: %w", err)
+	}
+	err = s.DB.QueryRow(`SELECT COALESCE(SUM(CASE WHEN requested_at >= ? THEN amount ELSE 0 END), 0),
+		COALESCE(SUM(amount), 0)
+		FROM rera_withdrawals WHERE tenant_id = ? AND project_id = ? AND status = ? AND requested_at < ?`,
+		from, tenantID, projectID, models.RERAWithdrawalApproved, to).Scan(&c.WithdrawnQuarter, &c.WithdrawnCumulative)
+	if err != nil {
+		return data, fmt.Errorf("failed to total RERA withdrawals: %w", err)
+	}
+
+	if data.Approvals, err = s.qprApprovals(tenantID, projectID, to); err != nil {
+		return data, err
+	}
+
+	rows, err = s.DB.Query(`SELECT utilization_type, COALESCE(SUM(CASE WHEN utilization_date >= ? THEN amount_utilized ELSE 0 END), 0),
+		COALESCE(SUM(amount_utilized), 0)
+		FROM project_fund_utilization WHERE tenant_id = ? AND project_id = ? AND utilization_date < ? AND deleted_at IS NULL
+		GROUP BY utilization_type ORDER BY utilization_type`, from, tenantID, projectID, to)
+	if err != nil {
+		return data, fmt.Errorf("failed to total fund utilization: %w", err)
+	}
+	defer rows.Close()
+	data.FundUsage = []models.QPRFundUsage{}
+	for rows.Next() {
+		var u models.QPRFundUsage
+		if err := rows.Scan(&u.UtilizationType, &u.Quarter, &u.Cumulative); err != nil {
+			return data, fmt.Errorf("failed to scan fund utilization: %w", err)
+		}
+		data.FundUsage = append(data.FundUsage, u)
+	}
+	return data, rows.Err()
+}
+
+// qprMilestone is a tower milestone as it stood at quarter end
+type qprMilestone struct {
+	BlockID         string
+	Name            string
+	Completed       bool
+	CompletedOn     *time.Time
+	ProgressPercent float64
+}
+
+func (s *RERAComplianceService) qprMilestones(tenantID, projectID string, to time.Time) ([]qprMilestone, error) {
+	rows, err := s.DB.Query(`SELECT m.block_id, m.milestone_name, m.status, m.completed_on, COALESCE(pt.percent_complete, 0)
+		FROM tower_milestones m
+		LEFT JOIN progress_tracking pt ON pt.id = m.progress_tracking_id
+		WHERE m.tenant_id = ? AND m.project_id = ?
+		ORDER BY m.block_id, m.sequence`, tenantID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tower milestones: %w", err)
+	}
+	defer rows.Close()
+
+	milestones := []qprMilestone{}
+	for rows.Next() {
+		var m qprMilestone
+		var status string
+		var completedOn sql.NullTime
+		if err := rows.Scan(&m.BlockID, &m.Name, &status, &completedOn, &m.ProgressPercent); err != nil {
+			return nil, fmt.Errorf("failed to scan tower milestone: %w", err)
+		}
+		if status == models.MilestoneStatusCompleted && completedOn.Valid && completedOn.Time.Before(to) {
+			m.Completed = true
+			m.CompletedOn = &completedOn.Time
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+func (s *RERAComplianceService) qprApprovals(tenantID, projectID string, to time.Time) ([]models.QPRApproval, error) {
+	rows, err := s.DB.Query(`SELECT p.permit_type, p.permit_number, COALESCE(p.issuing_authority, ''),
+		p.issued_date, p.expiry_date, p.status
+		FROM permits p JOIN sites st ON st.id = p.site_id
+		WHERE p.tenant_id = ? AND st.project_id = ? AND p.deleted_at IS NULL
+		AND (p.issued_date IS NULL OR p.issued_date < ?)
+		ORDER BY p.issued_date, p.permit_type`, tenantID, projectID, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []models.QPRApproval{}
+	for rows.Next() {
+		var a models.QPRApproval
+		var issued, expiry sql.NullTime
+		if err := rows.Scan(&a.PermitType, &a.PermitNumber, &a.IssuingAuthority, &issued, &expiry, &a.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		if issued.Valid {
+			a.IssuedDate = &issued.Time
+		}
+		if expiry.Valid {
+			a.ExpiryDate = &expiry.Time
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, rows.Err()
+}
+
+const qprSelect = `SELECT r.id, r.tenant_id, r.project_id, COALESCE(p.project_name, ''), r.state_code, r.quarter,
+	r.period_from, r.period_to, r.status, r.data, r.issues, r.acknowledgement_number, r.generated_by, r.generated_at,
+	r.submitted_by, r.submitted_at
+	FROM rera_qpr_reports r
+	LEFT JOIN property_projects p ON p.id = r.project_id`
+
+func (s *RERAComplianceService) getQPR(tenantID, where string, args ...interface{}) (*models.QuarterlyProgressReport, error) {
+	report, err := scanQPR(s.DB.QueryRow(qprSelect+` WHERE r.tenant_id = ? AND r.`+where,
+		append([]interface{}{tenantID}, args...)...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch QPR: %w", err)
+	}
+	return report, nil
+}
+
+func scanQPR(scanner interface{ Scan(...interface{}) error }) (*models.QuarterlyProgressReport, error) {
+	r := &models.QuarterlyProgressReport{}
+	var dataJSON, issuesJSON []byte
+	var ack, generatedBy, submittedBy sql.NullString
+	var submittedAt sql.NullTime
+	if err := scanner.Scan(&r.ID, &r.TenantID, &r.ProjectID, &r.ProjectName, &r.StateCode, &r.Quarter,
+		&r.PeriodFrom, &r.PeriodTo, &r.Status, &dataJSON, &issuesJSON, &ack, &generatedBy, &r.GeneratedAt,
+		&submittedBy, &submittedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dataJSON, &r.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode QPR data: %w", err)
+	}
+	r.Issues = []models.QPRIssue{}
+	if len(issuesJSON) > 0 {
+		if err := json.Unmarshal(issuesJSON, &r.Issues); err != nil {
+			return nil, fmt.Errorf("failed to decode QPR issues: %w", err)
+		}
+	}
+	r.AcknowledgementNumber = nullStringPtr(ack)
+	r.GeneratedBy = nullStringPtr(generatedBy)
+	r.SubmittedBy = nullStringPtr(submittedBy)
+	if submittedAt.Valid {
+		r.SubmittedAt = &submittedAt.Time
+	}
+	return r, nil
+}
+
+// buildTowerProgress attaches milestone progress to each tower. A tower's percentage
+// is the highest site progress evidenced by its completed milestones, falling back
+// to the share of milestones completed.
+func buildTowerProgress(towers []models.QPRTowerProgress, milestones []qprMilestone) []models.QPRTowerProgress {
+	index := map[string]int{}
+	for i, t := range towers {
+		index[t.BlockID] = i
+	}
+
+	evidenced := map[string]float64{}
+	for _, m := range milestones {
+		i, ok := index[m.BlockID]
+		if !ok {
+			towers = append(towers, models.QPRTowerProgress{BlockID: m.BlockID, BlockName: m.BlockID})
+			i = len(towers) - 1
+			index[m.BlockID] = i
+		}
+		t := &towers[i]
+		t.MilestonesTotal++
+		if m.Completed {
+			t.MilestonesCompleted++
+			t.LastMilestone = m.Name
+			t.LastCompletedOn = m.CompletedOn
+			evidenced[m.BlockID] = math.Max(evidenced[m.BlockID], m.ProgressPercent)
+		}
+	}
+
+	for i := range towers {
+		t := &towers[i]
+		switch {
+		case evidenced[t.BlockID] > 0:
+			t.PercentComplete = roundTo2(evidenced[t.BlockID])
+		case t.MilestonesTotal > 0:
+			t.PercentComplete = roundTo2(float64(t.MilestonesCompleted) / float64(t.MilestonesTotal) * 100)
+		}
+	}
+	return towers
+}
+
+// validateQPR checks a quarter's figures for internal consistency and against the
+// previous quarter's report. Cumulative figures that fall are errors; differences
+// explained by late entries or cancellations are warnings.
+func validateQPR(data models.QPRData, prior *models.QPRData, priorQuarter, priorStatus string, periodTo time.Time) []models.QPRIssue {
+	issues := []models.QPRIssue{}
+	add := func(severity, field, format string, args ...interface{}) {
+		issues = append(issues, models.QPRIssue{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if data.Units.TotalUnits == 0 {
+		add(models.QPRIssueError, "units.total", "project has no units")
+	}
+	c := data.Collections
+	if c.WithdrawnCumulative > c.RERADepositedCumulative+0.01 {
+		add(models.QPRIssueError, "collections.withdrawn_cumulative",
+			"withdrawals of Rs. %.2f exceed deposits of Rs. %.2f in the designated account", c.WithdrawnCumulative, c.RERADepositedCumulative)
+	}
+	for _, a := range data.Approvals {
+		if a.ExpiryDate != nil && a.ExpiryDate.Before(periodTo) && a.Status == "active" {
+			add(models.QPRIssueWarning, "approvals."+a.PermitNumber, "%s %s expired on %s",
+				a.PermitType, a.PermitNumber, a.ExpiryDate.Format(qprDateLayout))
+		}
+	}
+
+	if prior == nil {
+		add(models.QPRIssueWarning, "quarter", "no QPR on record for %s to compare against", priorQuarter)
+		return issues
+	}
+	if priorStatus != models.QPRStatusSubmitted {
+		add(models.QPRIssueWarning, "quarter", "QPR for %s has not been submitted", priorQuarter)
+	}
+
+	pc := prior.Collections
+	for _, f := range []struct {
+		field        string
+		now, before  float64
+		quarterDelta float64
+	}{
+		{"collections.cumulative", c.Cumulative, pc.Cumulative, c.Quarter},
+		{"collections.rera_deposited_cumulative", c.RERADepositedCumulative, pc.RERADepositedCumulative, c.RERADepositedQuarter},
+		{"collections.withdrawn_cumulative", c.WithdrawnCumulative, pc.WithdrawnCumulative, c.WithdrawnQuarter},
+	} {
+		if f.now < f.before-0.01 {
+			add(models.QPRIssueError, f.field, "fell from Rs. %.2f in %s to Rs. %.2f", f.before, priorQuarter, f.now)
+		} else if diff := roundTo2(f.now - f.before - f.quarterDelta); math.Abs(diff) > 0.01 {
+			add(models.QPRIssueWarning, f.field, "entries dated before this quarter changed the %s figure by Rs. %.2f", priorQuarter, diff)
+		}
+	}
+
+	u, pu := data.Units, prior.Units
+	if expected := pu.BookedCumulative + u.BookedInQuarter - u.CancelledInQuarter; u.BookedCumulative != expected {
+		add(models.QPRIssueWarning, "units.booked_cumulative",
+			"%d units booked at quarter end but %s's %d plus %d booked less %d cancelled gives %d",
+			u.BookedCumulative, priorQuarter, pu.BookedCumulative, u.BookedInQuarter, u.CancelledInQuarter, expected)
+	}
+
+	priorTowers := map[string]models.QPRTowerProgress{}
+	for _, t := range prior.Towers {
+		priorTowers[t.BlockID] = t
+	}
+	for _, t := range data.Towers {
+		pt, ok := priorTowers[t.BlockID]
+		if !ok {
+			continue
+		}
+		if t.PercentComplete < pt.PercentComplete {
+			add(models.QPRIssueError, "towers."+t.BlockName, "progress fell from %.2f%% in %s to %.2f%%",
+				pt.PercentComplete, priorQuarter, t.PercentComplete)
+		}
+		if t.MilestonesCompleted < pt.MilestonesCompleted {
+			add(models.QPRIssueError, "towers."+t.BlockName, "completed milestones fell from %d in %s to %d",
+				pt.MilestonesCompleted, priorQuarter, t.MilestonesCompleted)
+		}
+	}
+
+	current := map[string]float64{}
+	for _, f := range data.FundUsage {
+		current[f.UtilizationType] = f.Cumulative
+	}
+	for _, f := range prior.FundUsage {
+		if current[f.UtilizationType] < f.Cumulative-0.01 {
+			add(models.QPRIssueError, "fund_usage."+f.UtilizationType, "cumulative utilization fell from Rs. %.2f in %s to Rs. %.2f",
+				f.Cumulative, priorQuarter, current[f.UtilizationType])
+		}
+	}
+
+	return issues
+}
+
+func countQPRErrors(issues []models.QPRIssue) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity == models.QPRIssueError {
+			n++
+		}
+	}
+	return n
+}
+
+// qprStateCodes maps state names to the codes templates are keyed by
+var qprStateCodes = map[string]string{
+	"maharashtra": "MH",
+	"karnataka":   "KA",
+	"tamil nadu":  "TN",
+	"tamilnadu":   "TN",
+}
+
+// normalizeStateCode turns a state name or code into an upper-case state code
+func normalizeStateCode(state string) string {
+	state = strings.TrimSpace(state)
+	if code, ok := qprStateCodes[strings.ToLower(state)]; ok {
+		return code
+	}
+	return strings.ToUpper(state)
+}
+
+// ============================================================================
+// STATE TEMPLATES
+// ============================================================================
+
+// qprFieldKeys are the values a template field may reference
+var qprFieldKeys = []string{
+	"project_name", "state_code", "quarter", "period_from", "period_to", "status", "acknowledgement_number",
+	"units.total", "units.booked_cumulative", "units.booked_in_quarter", "units.cancelled_in_quarter", "units.unsold",
+	"collections.quarter", "collections.cumulative",
+	"collections.rera_deposited_quarter", "collections.rera_deposited_cumulative",
+	"collections.withdrawn_quarter", "collections.withdrawn_cumulative",
+}
+
+var qprProjectFields = []models.QPRTemplateField{
+	{Label: "Project Name", Key: "project_name"},
+	{Label: "Quarter", Key: "quarter"},
+	{Label: "Period From", Key: "period_from"},
+	{Label: "Period To", Key: "period_to"},
+}
+
+// defaultQPRTemplates are used for a state until the tenant configures its own layout
+var defaultQPRTemplates = map[string]models.QPRTemplate{
+	"MH": {StateCode: "MH", Name: "MahaRERA Quarterly Progress Report", Sections: []models.QPRTemplateSection{
+		{Title: "Project Details", Fields: qprProjectFields},
+		{Title: "Inventory of Apartments", Fields: []models.QPRTemplateField{
+			{Label: "Total number of apartments", Key: "units.total"},
+			{Label: "Apartments booked / allotted till date", Key: "units.booked_cumulative"},
+			{Label: "Apartments booked during the quarter", Key: "units.booked_in_quarter"},
+			{Label: "Bookings cancelled during the quarter", Key: "units.cancelled_in_quarter"},
+			{Label: "Unsold apartments", Key: "units.unsold"},
+		}},
+		{Title: "Building / Wing Wise Progress", Table: models.QPRTableTowers},
+		{Title: "Approvals and Sanctions", Table: models.QPRTableApprovals},
+		{Title: "Financial Progress", Fields: []models.QPRTemplateField{
+			{Label: "Amount collected during the quarter (Rs.)", Key: "collections.quarter"},
+			{Label: "Amount collected till date (Rs.)", Key: "collections.cumulative"},
+			{Label: "Deposited in designated account during the quarter (Rs.)", Key: "collections.rera_deposited_quarter"},
+			{Label: "Deposited in designated account till date (Rs.)", Key: "collections.rera_deposited_cumulative"},
+			{Label: "Withdrawn from designated account during the quarter (Rs.)", Key: "collections.withdrawn_quarter"},
+			{Label: "Withdrawn from designated account till date (Rs.)", Key: "collections.withdrawn_cumulative"},
+		}},
+		{Title: "Utilisation of Funds", Table: models.QPRTableFundUsage},
+	}},
+	"KA": {StateCode: "KA", Name: "K-RERA Quarterly Update", Sections: []models.QPRTemplateSection{
+		{Title: "Project Information", Fields: qprProjectFields},
+		{Title: "Block Wise Construction Status", Table: models.QPRTableTowers},
+		{Title: "Sales Status", Fields: []models.QPRTemplateField{
+			{Label: "Total units", Key: "units.total"},
+			{Label: "Units sold", Key: "units.booked_cumulative"},
+			{Label: "Units sold in the quarter", Key: "units.booked_in_quarter"},
+			{Label: "Cancellations in the quarter", Key: "units.cancelled_in_quarter"},
+			{Label: "Units available for sale", Key: "units.unsold"},
+		}},
+		{Title: "Receipts and Withdrawals", Fields: []models.QPRTemplateField{
+			{Label: "Receipts in the quarter (Rs.)", Key: "collections.quarter"},
+			{Label: "Total receipts (Rs.)", Key: "collections.cumulative"},
+			{Label: "Separate account deposits in the quarter (Rs.)", Key: "collections.rera_deposited_quarter"},
+			{Label: "Separate account withdrawals in the quarter (Rs.)", Key: "collections.withdrawn_quarter"},
+			{Label: "Separate account withdrawals till date (Rs.)", Key: "collections.withdrawn_cumulative"},
+		}},
+		{Title: "Expenditure", Table: models.QPRTableFundUsage},
+		{Title: "Approvals Obtained", Table: models.QPRTableApprovals},
+	}},
+	"TN": {StateCode: "TN", Name: "TNRERA Quarterly Progress Report", Sections: []models.QPRTemplateSection{
+		{Title: "Project", Fields: qprProjectFields},
+		{Title: "Apartment Booking Status", Fields: []models.QPRTemplateField{
+			{Label: "Total apartments", Key: "units.total"},
+			{Label: "Booked till end of quarter", Key: "units.booked_cumulative"},
+			{Label: "Booked in quarter", Key: "units.booked_in_quarter"},
+			{Label: "Cancelled in quarter", Key: "units.cancelled_in_quarter"},
+		}},
+		{Title: "Stage of Construction", Table: models.QPRTableTowers},
+		{Title: "Statutory Approvals", Table: models.QPRTableApprovals},
+		{Title: "Collection Account", Fields: []models.QPRTemplateField{
+			{Label: "Collected in quarter (Rs.)", Key: "collections.quarter"},
+			{Label: "Collected till date (Rs.)", Key: "collections.cumulative"},
+			{Label: "70% account balance deposited till date (Rs.)", Key: "collections.rera_deposited_cumulative"},
+			{Label: "Withdrawn till date (Rs.)", Key: "collections.withdrawn_cumulative"},
+		}},
+		{Title: "Fund Utilisation", Table: models.QPRTableFundUsage},
+	}},
+}
+
+// SetQPRTemplate configures the tenant's export layout for a state
+func (s *RERAComplianceService) SetQPRTemplate(tenantID, stateCode string, req *models.SetQPRTemplateRequest) (*models.QPRTemplate, error) {
+	stateCode = normalizeStateCode(stateCode)
+	if stateCode == "" || req.Name == "" {
+		return nil, fmt.Errorf("state code and template name are required")
+	}
+	if err := validateQPRTemplate(req.Sections); err != nil {
+		return nil, err
+	}
+
+	sections, err := json.Marshal(req.Sections)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template sections: %w", err)
+	}
+	_, err = s.DB.Exec(`INSERT INTO rera_qpr_templates (id, tenant_id, state_code, name, sections, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name), sections = VALUES(sections), updated_at = VALUES(updated_at)`,
+		uuid.New().String(), tenantID, stateCode, req.Name, sections, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save QPR template: %w", err)
+	}
+
+	return &models.QPRTemplate{StateCode: stateCode, Name: req.Name, Sections: req.Sections}, nil
+}
+
+// GetQPRTemplate returns the tenant's layout for a state, or the built-in default
+func (s *RERAComplianceService) GetQPRTemplate(tenantID, stateCode string) (*models.QPRTemplate, error) {
+	stateCode = normalizeStateCode(stateCode)
+	tpl := &models.QPRTemplate{StateCode: stateCode}
+	var sections []byte
+	err := s.DB.QueryRow(`SELECT name, sections FROM rera_qpr_templates WHERE tenant_id = ? AND state_code = ?`,
+		tenantID, stateCode).Scan(&tpl.Name, &sections)
+	if err == sql.ErrNoRows {
+		def, ok := defaultQPRTemplates[stateCode]
+		if !ok {
+			return nil, fmt.Errorf("no QPR template configured for state %q", stateCode)
+		}
+		def.IsDefault = true
+		return &def, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch QPR template: %w", err)
+	}
+	if err := json.Unmarshal(sections, &tpl.Sections); err != nil {
+		return nil, fmt.Errorf("failed to decode QPR template: %w", err)
+	}
+	return tpl, nil
+}
+
+// ListQPRTemplates lists the tenant's templates together with the built-in defaults
+// it has not overridden
+func (s *RERAComplianceService) ListQPRTemplates(tenantID string) ([]models.QPRTemplate, error) {
+	rows, err := s.DB.Query(`SELECT state_code FROM rera_qpr_templates WHERE tenant_id = ?`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list QPR templates: %w", err)
+	}
+	codes := map[string]bool{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan QPR template: %w", err)
+		}
+		codes[code] = true
+	}
+	rows.Close()
+	for code := range defaultQPRTemplates {
+		codes[code] = true
+	}
+
+	sorted := make([]string, 0, len(codes))
+	for code := range codes {
+		sorted = append(sorted, code)
+	}
+	sort.Strings(sorted)
+
+	templates := make([]models.QPRTemplate, 0, len(sorted))
+	for _, code := range sorted {
+		tpl, err := s.GetQPRTemplate(tenantID, code)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tpl)
+	}
+	return templates, nil
+}
+
+// validateQPRTemplate checks every section renders a known table or known fields
+func validateQPRTemplate(sections []models.QPRTemplateSection) error {
+	if len(sections) == 0 {
+		return fmt.Errorf("template needs at least one section")
+	}
+	known := map[string]bool{}
+	for _, key := range qprFieldKeys {
+		known[key] = true
+	}
+	for _, sec := range sections {
+		if sec.Title == "" {
+			return fmt.Errorf("every section needs a title")
+		}
+		switch sec.Table {
+		case "":
+			if len(sec.Fields) == 0 {
+				return fmt.Errorf("section %q has neither fields nor a table", sec.Title)
+			}
+		case models.QPRTableTowers, models.QPRTableApprovals, models.QPRTableFundUsage:
+		default:
+			return fmt.Errorf("section %q uses unknown table %q", sec.Title, sec.Table)
+		}
+		for _, f := range sec.Fields {
+			if !known[f.Key] {
+				return fmt.Errorf("section %q uses unknown field %q", sec.Title, f.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// EXPORT
+// ============================================================================
+
+// qprFieldValues flattens a report into the values template fields reference
+func qprFieldValues(r *models.QuarterlyProgressReport) map[string]interface{} {
+	u, c := r.Data.Units, r.Data.Collections
+	ack := ""
+	if r.AcknowledgementNumber != nil {
+		ack = *r.AcknowledgementNumber
+	}
+	return map[string]interface{}{
+		"project_name":                          r.ProjectName,
+		"state_code":                            r.StateCode,
+		"quarter":                               r.Quarter,
+		"period_from":                           r.PeriodFrom.Format(qprDateLayout),
+		"period_to":                             r.PeriodTo.AddDate(0, 0, -1).Format(qprDateLayout),
+		"status":                                r.Status,
+		"acknowledgement_number":                ack,
+		"units.total":                           u.TotalUnits,
+		"units.booked_cumulative":               u.BookedCumulative,
+		"units.booked_in_quarter":               u.BookedInQuarter,
+		"units.cancelled_in_quarter":            u.CancelledInQuarter,
+		"units.unsold":                          u.UnsoldUnits,
+		"collections.quarter":                   c.Quarter,
+		"collections.cumulative":                c.Cumulative,
+		"collections.rera_deposited_quarter":    c.RERADepositedQuarter,
+		"collections.rera_deposited_cumulative": c.RERADepositedCumulative,
+		"collections.withdrawn_quarter":         c.WithdrawnQuarter,
+		"collections.withdrawn_cumulative":      c.WithdrawnCumulative,
+	}
+}
+
+// qprTable returns the header and rows of one of the report tables
+func qprTable(r *models.QuarterlyProgressReport, table string) ([]string, [][]interface{}) {
+	formatDate := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(qprDateLayout)
+	}
+
+	rows := [][]interface{}{}
+	switch table {
+	case models.QPRTableTowers:
+		for _, t := range r.Data.Towers {
+			rows = append(rows, []interface{}{t.BlockName, t.TotalUnits, t.BookedUnits,
+				fmt.Sprintf("%d/%d", t.MilestonesCompleted, t.MilestonesTotal), t.LastMilestone,
+				formatDate(t.LastCompletedOn), t.PercentComplete})
+		}
+		return []string{"Tower", "Units", "Booked", "Milestones", "Last Milestone", "Completed On", "% Complete"}, rows
+	case models.QPRTableApprovals:
+		for _, a := range r.Data.Approvals {
+			rows = append(rows, []interface{}{a.PermitType, a.PermitNumber, a.IssuingAuthority,
+				formatDate(a.IssuedDate), formatDate(a.ExpiryDate), a.Status})
+		}
+		return []string{"Approval", "Number", "Authority", "Issued", "Valid Till", "Status"}, rows
+	case models.QPRTableFundUsage:
+		for _, f := range r.Data.FundUsage {
+			rows = append(rows, []interface{}{f.UtilizationType, f.Quarter, f.Cumulative})
+		}
+		return []string{"Head", "This Quarter (Rs.)", "Till Date (Rs.)"}, rows
+	}
+	return nil, rows
+}
+
+func formatQPRValue(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%.2f", f)
+	}
+	return fmt.Sprint(v)
+}
+
+// renderQPRExcel lays the template's sections out top to bottom on one sheet
+func renderQPRExcel(r *models.QuarterlyProgressReport, tpl *models.QPRTemplate) ([]byte, error) {
+	const sheet = "QPR"
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", sheet)
+
+	row := 1
+	set := func(col int, v interface{}) {
+		cell, _ := excelize.CoordinatesToCellName(col, row)
+		f.SetCellValue(sheet, cell, v)
+	}
+
+	set(1, tpl.Name)
+	row += 2
+	values := qprFieldValues(r)
+	for _, sec := range tpl.Sections {
+		set(1, sec.Title)
+		row++
+		for _, field := range sec.Fields {
+			set(1, field.Label)
+			set(2, values[field.Key])
+			row++
+		}
+		if sec.Table != "" {
+			headers, rows := qprTable(r, sec.Table)
+			for i, h := range headers {
+				set(i+1, h)
+			}
+			row++
+			for _, cells := range rows {
+				for i, v := range cells {
+					set(i+1, v)
+				}
+				row++
+			}
+		}
+		row++
+	}
+	f.SetColWidth(sheet, "A", "A", 55)
+	f.SetColWidth(sheet, "B", "G", 18)
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write QPR workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderQPRLines lays the template out as fixed-width text lines for the PDF
+func renderQPRLines(r *models.QuarterlyProgressReport, tpl *models.QPRTemplate) []string {
+	lines := []string{tpl.Name, ""}
+	if r.Status == models.QPRStatusDraft {
+		lines = append(lines, "DRAFT - not yet submitted", "")
+	}
+
+	values := qprFieldValues(r)
+	for _, sec := range tpl.Sections {
+		lines = append(lines, strings.ToUpper(sec.Title), strings.Repeat("-", len(sec.Title)))
+		for _, field := range sec.Fields {
+			lines = append(lines, fmt.Sprintf("%-60s %s", field.Label, formatQPRValue(values[field.Key])))
+		}
+		if sec.Table != "" {
+			headers, rows := qprTable(r, sec.Table)
+			lines = append(lines, formatQPRTable(headers, rows)...)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// formatQPRTable pads each column to its widest cell
+func formatQPRTable(headers []string, rows [][]interface{}) []string {
+	cells := [][]string{headers}
+	for _, row := range rows {
+		line := make([]string, len(row))
+		for i, v := range row {
+			line[i] = formatQPRValue(v)
+		}
+		cells = append(cells, line)
+	}
+
+	widths := make([]int, len(headers))
+	for _, line := range cells {
+		for i, cell := range line {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(cells))
+	for _, line := range cells {
+		parts := make([]string, len(line))
+		for i, cell := range line {
+			parts[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(parts, "  "), " "))
+	}
+	return lines
+}
+
+// ============================================================================
+// PDF RENDERING
+// ============================================================================
+
+const (
+	pdfLinesPerPage = 64
+	pdfLineWidth    = 100 // characters of 8pt Courier across an A4 page
+)
+
+// renderTextPDF writes lines of plain text as a paginated A4 PDF in Courier
+func renderTextPDF(lines []string) []byte {
+	wrapped := []string{}
+	for _, line := range lines {
+		for len(line) > pdfLineWidth {
+			wrapped = append(wrapped, line[:pdfLineWidth])
+			line = "    " + line[pdfLineWidth:]
+		}
+		wrapped = append(wrapped, line)
+	}
+
+	pages := [][]string{}
+	for len(wrapped) > pdfLinesPerPage {
+		pages = append(pages, wrapped[:pdfLinesPerPage])
+		wrapped = wrapped[pdfLinesPerPage:]
+	}
+	pages = append(pages, wrapped)
+
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+	for i, page := range pages {
+		var content strings.Builder
+		content.WriteString("BT /F1 8 Tf 12 TL 36 806 Td\n")
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", pdfEscape(line))
+		}
+		content.WriteString("ET")
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscape escapes a PDF string literal; characters outside ASCII become '?'
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseQPRQuarter tests quarter parsing and stepping back a quarter
+func TestParseQPRQuarter(t *testing.T) {
+	from, to, err := ParseQPRQuarter("2026-Q3")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), to)
+
+	for _, bad := range []string{"2026-Q5", "2026Q1", "26-Q1", "2026-Q1x"} {
+		_, _, err := ParseQPRQuarter(bad)
+		assert.Error(t, err, bad)
+	}
+
+	assert.Equal(t, "2026-Q2", previousQPRQuarter("2026-Q3"))
+	assert.Equal(t, "2025-Q4", previousQPRQuarter("2026-Q1"))
+	assert.Equal(t, "MH", normalizeStateCode("Maharashtra"))
+	assert.Equal(t, "TN", normalizeStateCode(" tamil nadu "))
+	assert.Equal(t, "KA", normalizeStateCode("ka"))
+}
+
+// TestBuildTowerProgress tests tower progress from milestones and their site evidence
+func TestBuildTowerProgress(t *testing.T) {
+	done := time.Date(2026, 8, 10, 0, 0, 0, 0, time.UTC)
+	towers := []models.QPRTowerProgress{
+		{BlockID: "b1", BlockName: "Tower A", TotalUnits: 40, BookedUnits: 25},
+		{BlockID: "b2", BlockName: "Tower B", TotalUnits: 40, BookedUnits: 10},
+	}
+	milestones := []qprMilestone{
+		{BlockID: "b1", Name: "Plinth", Completed: true, CompletedOn: &done, ProgressPercent: 15},
+		{BlockID: "b1", Name: "Slab 5", Completed: true, CompletedOn: &done, ProgressPercent: 42.5},
+		{BlockID: "b1", Name: "Slab 10"},
+		{BlockID: "b2", Name: "Plinth", Completed: true, CompletedOn: &done},
+		{BlockID: "b2", Name: "Slab 5"},
+		{BlockID: "b2", Name: "Slab 10"},
+		{BlockID: "b2", Name: "Finishing"},
+	}
+
+	got := buildTowerProgress(towers, milestones)
+	assert.Len(t, got, 2)
+	assert.Equal(t, 42.5, got[0].PercentComplete) // highest evidenced site progress
+	assert.Equal(t, "Slab 5", got[0].LastMilestone)
+	assert.Equal(t, 2, got[0].MilestonesCompleted)
+	assert.Equal(t, 3, got[0].MilestonesTotal)
+	assert.Equal(t, 25.0, got[1].PercentComplete) // 1 of 4 milestones without evidence
+}
+
+// TestValidateQPR tests validation against the previous quarter
+func TestValidateQPR(t *testing.T) {
+	periodTo := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	prior := &models.QPRData{
+		Units:       models.QPRUnitSummary{TotalUnits: 80, BookedCumulative: 30},
+		Collections: models.QPRCollections{Cumulative: 5000000, RERADepositedCumulative: 3500000, WithdrawnCumulative: 1000000},
+		Towers:      []models.QPRTowerProgress{{BlockID: "b1", BlockName: "Tower A", PercentComplete: 40, MilestonesCompleted: 2}},
+		FundUsage:   []models.QPRFundUsage{{UtilizationType: "Construction", Cumulative: 1000000}},
+	}
+	data := models.QPRData{
+		Units:       models.QPRUnitSummary{TotalUnits: 80, BookedCumulative: 34, BookedInQuarter: 5, CancelledInQuarter: 1},
+		Collections: models.QPRCollections{Quarter: 2000000, Cumulative: 7000000, RERADepositedQuarter: 1400000, RERADepositedCumulative: 4900000, WithdrawnCumulative: 1000000},
+		Towers:      []models.QPRTowerProgress{{BlockID: "b1", BlockName: "Tower A", PercentComplete: 55, MilestonesCompleted: 3}},
+		FundUsage:   []models.QPRFundUsage{{UtilizationType: "Construction", Quarter: 0, Cumulative: 1000000}},
+	}
+
+	issues := validateQPR(data, prior, "2026-Q2", models.QPRStatusSubmitted, periodTo)
+	assert.Empty(t, issues)
+
+	// Regressions against the submitted quarter are errors
+	regressed := data
+	regressed.Collections.Cumulative = 4000000
+	regressed.Towers = []models.QPRTowerProgress{{BlockID: "b1", BlockName: "Tower A", PercentComplete: 35, MilestonesCompleted: 2}}
+	regressed.FundUsage = nil
+	issues = validateQPR(regressed, prior, "2026-Q2", models.QPRStatusSubmitted, periodTo)
+	assert.Equal(t, 3, countQPRErrors(issues))
+
+	// Back-dated entries and unexplained booking movements are warnings
+	late := data
+	late.Collections.Cumulative = 7100000
+	late.Units.BookedCumulative = 36
+	issues = validateQPR(late, prior, "2026-Q2", models.QPRStatusSubmitted, periodTo)
+	assert.Equal(t, 0, countQPRErrors(issues))
+	assert.Len(t, issues, 2)
+
+	// First quarter has nothing to compare against
+	issues = validateQPR(data, nil, "2026-Q2", "", periodTo)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, models.QPRIssueWarning, issues[0].Severity)
+}
+
+// TestQPRTemplatesAndExport tests the default layouts and the rendered exports
+func TestQPRTemplatesAndExport(t *testing.T) {
+	for code, tpl := range defaultQPRTemplates {
+		assert.NoError(t, validateQPRTemplate(tpl.Sections), code)
+	}
+	assert.Error(t, validateQPRTemplate([]models.QPRTemplateSection{{Title: "Bad", Fields: []models.QPRTemplateField{{Label: "x", Key: "units.nope"}}}}))
+	assert.Error(t, validateQPRTemplate([]models.QPRTemplateSection{{Title: "Bad", Table: "inventory"}}))
+
+	from, to, _ := ParseQPRQuarter("2026-Q3")
+	report := &models.QuarterlyProgressReport{
+		ProjectName: "Skyline (Phase 1)",
+		StateCode:   "MH",
+		Quarter:     "2026-Q3",
+		PeriodFrom:  from,
+		PeriodTo:    to,
+		Status:      models.QPRStatusDraft,
+		Data: models.QPRData{
+			Units:     models.QPRUnitSummary{TotalUnits: 80, BookedCumulative: 34},
+			Towers:    []models.QPRTowerProgress{{BlockName: "Tower A", TotalUnits: 40, MilestonesCompleted: 3, MilestonesTotal: 8, PercentComplete: 55}},
+			FundUsage: []models.QPRFundUsage{{UtilizationType: "Construction", Quarter: 250000, Cumulative: 1250000}},
+		},
+	}
+	tpl := defaultQPRTemplates["MH"]
+
+	lines := renderQPRLines(report, &tpl)
+	assert.Equal(t, "MahaRERA Quarterly Progress Report", lines[0])
+	assert.Contains(t, lines, "Construction  250000.00           1250000.00")
+
+	pdf := renderTextPDF(lines)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), `Skyline \(Phase 1\)`)
+
+	xlsx, err := renderQPRExcel(report, &tpl)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(xlsx, []byte("PK")))
+}
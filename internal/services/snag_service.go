@@ -0,0 +1,814 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// SNAG SERVICE
+// ============================================================================
+
+// SnagService runs the post-possession snag list: the joint handover inspection,
+// the unit's defect-liability period, snags with photos assigned to contractors
+// under a severity SLA, and back-charging contractors through vendor payables.
+type SnagService struct {
+	DB       *sql.DB
+	GL       *GLService
+	Purchase *PurchaseService
+}
+
+// NewSnagService creates a new snag service
+func NewSnagService(db *sql.DB, gl *GLService, purchase *PurchaseService) *SnagService {
+	return &SnagService{DB: db, GL: gl, Purchase: purchase}
+}
+
+// snagSLADays is the days a contractor has to fix a snag once assigned
+var snagSLADays = map[string]int{
+	models.SnagSeverityCritical: 2,
+	models.SnagSeverityMajor:    7,
+	models.SnagSeverityMinor:    15,
+}
+
+// Internal snag actions; review actions are in models
+const (
+	snagActionAssign  = "assign"
+	snagActionResolve = "resolve"
+)
+
+// ============================================================================
+// HANDOVER INSPECTIONS
+// ============================================================================
+
+// CreateInspection records the joint inspection checklist for a booking. Every
+// item marked snag raises an open snag against the unit.
+func (s *SnagService) CreateInspection(tenantID, userID string, req *models.CreateHandoverInspectionRequest) (*models.HandoverInspection, error) {
+	if err := validateChecklist(req.Items); err != nil {
+		return nil, err
+	}
+
+	var unitID, projectID string
+	err := s.DB.QueryRow(`SELECT b.unit_id, COALESCE(u.project_id, '')
+		FROM customer_bookings b
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		WHERE b.id = ? AND b.tenant_id = ? AND b.deleted_at IS NULL`, req.BookingID, tenantID).Scan(&unitID, &projectID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	now := time.Now()
+	inspectionDate := now
+	if req.InspectionDate != nil {
+		inspectionDate = *req.InspectionDate
+	}
+	in := &models.HandoverInspection{
+		ID:                     uuid.New().String(),
+		TenantID:               tenantID,
+		BookingID:              req.BookingID,
+		UnitID:                 unitID,
+		ProjectID:              projectID,
+		InspectionDate:         inspectionDate,
+		CustomerRepresentative: req.CustomerRepresentative,
+		Status:                 models.InspectionStatusDraft,
+		InspectedBy:            optionalString(userID),
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, item := range req.Items {
+		line := models.HandoverChecklistItem{
+			ID:           uuid.New().String(),
+			InspectionID: in.ID,
+			Area:         item.Area,
+			Item:         item.Item,
+			Result:       item.Result,
+			Remarks:      item.Remarks,
+		}
+		if item.Result == models.ChecklistResultSnag {
+			snag := &models.Snag{
+				UnitID:       unitID,
+				BookingID:    req.BookingID,
+				ProjectID:    projectID,
+				InspectionID: &in.ID,
+				Source:       models.SnagSourceInspection,
+				Category:     item.Category,
+				Severity:     defaultSnagSeverity(item.Severity),
+				Location:     item.Area,
+				Description:  strings.TrimSpace(item.Item + " - " + item.Remarks),
+			}
+			if err := insertSnag(tx, tenantID, userID, snag, item.PhotoURLs); err != nil {
+				return nil, err
+			}
+			line.SnagID = &snag.ID
+			in.SnagCount++
+		}
+		if _, err := tx.Exec(`INSERT INTO handover_checklist_items
+			(id, tenant_id, inspection_id, line_number, area, item, result, remarks, snag_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			line.ID, tenantID, in.ID, i+1, line.Area, line.Item, line.Result, nullIfEmpty(line.Remarks), line.SnagID); err != nil {
+			return nil, fmt.Errorf("failed to add checklist item: %w", err)
+		}
+		in.Items = append(in.Items, line)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO handover_inspections
+		(id, tenant_id, booking_id, unit_id, project_id, inspection_date, customer_representative, status,
+		 snag_count, inspected_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		in.ID, tenantID, in.BookingID, in.UnitID, nullIfEmpty(in.ProjectID), in.InspectionDate,
+		nullIfEmpty(in.CustomerRepresentative), in.Status, in.SnagCount, in.InspectedBy, in.CreatedAt, in.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create handover inspection: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit handover inspection: %w", err)
+	}
+	return in, nil
+}
+
+// SignOffInspection completes the handover and starts the unit's defect-liability period
+func (s *SnagService) SignOffInspection(tenantID, userID, inspectionID string, req *models.SignOffHandoverRequest) (*models.HandoverInspection, error) {
+	in, err := s.GetInspection(tenantID, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Status != models.InspectionStatusDraft {
+		return nil, fmt.Errorf("inspection is already %s", in.Status)
+	}
+	handover := in.InspectionDate
+	if req.HandoverDate != nil {
+		handover = *req.HandoverDate
+	}
+	if handover.Before(in.InspectionDate.Truncate(24 * time.Hour)) {
+		return nil, fmt.Errorf("handover_date cannot be before the inspection date")
+	}
+	expiry := defectLiabilityExpiry(handover)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var existing time.Time
+	err = tx.QueryRow(`SELECT handover_date FROM unit_defect_liability WHERE tenant_id = ? AND unit_id = ? FOR UPDATE`,
+		tenantID, in.UnitID).Scan(&existing)
+	if err == nil {
+		return nil, fmt.Errorf("unit was already handed over on %s", existing.Format("2006-01-02"))
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check defect liability: %w", err)
+	}
+
+	now := time.Now()
+	res, err := tx.Exec(`UPDATE handover_inspections SET status = ?, handover_date = ?, dlp_expiry = ?, signed_off_by = ?,
+		signed_off_at = ?, updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.InspectionStatusSignedOff, handover, expiry, optionalString(userID), now, now,
+		inspectionID, tenantID, models.InspectionStatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign off inspection: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("inspection was already signed off")
+	}
+	if _, err := tx.Exec(`INSERT INTO unit_defect_liability
+		(id, tenant_id, unit_id, booking_id, project_id, inspection_id, handover_date, dlp_expiry, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		uuid.New().String(), tenantID, in.UnitID, in.BookingID, nullIfEmpty(in.ProjectID), in.ID, handover, expiry, now); err != nil {
+		return nil, fmt.Errorf("failed to start defect liability period: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit sign-off: %w", err)
+	}
+	return s.GetInspection(tenantID, inspectionID)
+}
+
+// GetInspection returns a handover inspection with its checklist
+func (s *SnagService) GetInspection(tenantID, inspectionID string) (*models.HandoverInspection, error) {
+	list, err := s.getInspections(tenantID, "id = ?", inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("handover inspection not found")
+	}
+	in := &list[0]
+
+	rows, err := s.DB.Query(`SELECT id, inspection_id, area, item, result, COALESCE(remarks, ''), snag_id
+		FROM handover_checklist_items WHERE tenant_id = ? AND inspection_id = ? ORDER BY line_number`, tenantID, inspectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checklist: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item models.HandoverChecklistItem
+		var snagID sql.NullString
+		if err := rows.Scan(&item.ID, &item.InspectionID, &item.Area, &item.Item, &item.Result, &item.Remarks, &snagID); err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		item.SnagID = nullStringPtr(snagID)
+		in.Items = append(in.Items, item)
+	}
+	return in, rows.Err()
+}
+
+// ListInspections lists handover inspections, optionally for one booking or project
+func (s *SnagService) ListInspections(tenantID, bookingID, projectID string) ([]models.HandoverInspection, error) {
+	where, args := "1 = 1", []interface{}{}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	if projectID != "" {
+		where += " AND project_id = ?"
+		args = append(args, projectID)
+	}
+	return s.getInspections(tenantID, where, args...)
+}
+
+func (s *SnagService) getInspections(tenantID, where string, args ...interface{}) ([]models.HandoverInspection, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, booking_id, unit_id, COALESCE(project_id, ''), inspection_date,
+		COALESCE(customer_representative, ''), status, handover_date, dlp_expiry, snag_count, inspected_by,
+		signed_off_by, signed_off_at, created_at, updated_at
+		FROM handover_inspections WHERE tenant_id = ? AND `+where+` ORDER BY inspection_date DESC`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch handover inspections: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.HandoverInspection{}
+	for rows.Next() {
+		var in models.HandoverInspection
+		var handover, expiry, signedOffAt sql.NullTime
+		var inspectedBy, signedOffBy sql.NullString
+		if err := rows.Scan(&in.ID, &in.TenantID, &in.BookingID, &in.UnitID, &in.ProjectID, &in.InspectionDate,
+			&in.CustomerRepresentative, &in.Status, &handover, &expiry, &in.SnagCount, &inspectedBy,
+			&signedOffBy, &signedOffAt, &in.CreatedAt, &in.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan handover inspection: %w", err)
+		}
+		if handover.Valid {
+			in.HandoverDate = &handover.Time
+		}
+		if expiry.Valid {
+			in.DLPExpiry = &expiry.Time
+		}
+		if signedOffAt.Valid {
+			in.SignedOffAt = &signedOffAt.Time
+		}
+		in.InspectedBy = nullStringPtr(inspectedBy)
+		in.SignedOffBy = nullStringPtr(signedOffBy)
+		list = append(list, in)
+	}
+	return list, rows.Err()
+}
+
+// ============================================================================
+// DEFECT LIABILITY PERIOD
+// ============================================================================
+
+// GetUnitDefectLiability returns a unit's defect-liability period
+func (s *SnagService) GetUnitDefectLiability(tenantID, unitID string) (*models.UnitDefectLiability, error) {
+	return s.getDefectLiability(tenantID, "d.unit_id = ?", unitID)
+}
+
+// GetBookingDefectLiability returns the defect-liability period of the booking's unit
+func (s *SnagService) GetBookingDefectLiability(tenantID, bookingID string) (*models.UnitDefectLiability, error) {
+	return s.getDefectLiability(tenantID,
+		"d.unit_id = (SELECT unit_id FROM customer_bookings WHERE id = ? AND tenant_id = d.tenant_id)", bookingID)
+}
+
+// ListDefectLiability lists units' defect-liability periods, optionally only those still running
+func (s *SnagService) ListDefectLiability(tenantID, projectID string, activeOnly bool) ([]models.UnitDefectLiability, error) {
+	where, args := "1 = 1", []interface{}{}
+	if projectID != "" {
+		where += " AND d.project_id = ?"
+		args = append(args, projectID)
+	}
+	if activeOnly {
+		where += " AND d.dlp_expiry >= ?"
+		args = append(args, time.Now())
+	}
+	return s.getDefectLiabilities(tenantID, where, args...)
+}
+
+func (s *SnagService) getDefectLiability(tenantID, where string, args ...interface{}) (*models.UnitDefectLiability, error) {
+	list, err := s.getDefectLiabilities(tenantID, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("unit has not been handed over")
+	}
+	return &list[0], nil
+}
+
+func (s *SnagService) getDefectLiabilities(tenantID, where string, args ...interface{}) ([]models.UnitDefectLiability, error) {
+	rows, err := s.DB.Query(`SELECT d.unit_id, COALESCE(u.unit_number, ''), d.booking_id, COALESCE(d.project_id, ''),
+		d.inspection_id, d.handover_date, d.dlp_expiry,
+		(SELECT COUNT(*) FROM snags sn WHERE sn.tenant_id = d.tenant_id AND sn.unit_id = d.unit_id AND sn.status IN (?, ?, ?))
+		FROM unit_defect_liability d
+		LEFT JOIN property_units u ON u.id = d.unit_id
+		WHERE d.tenant_id = ? AND `+where+` ORDER BY d.dlp_expiry`,
+		append([]interface{}{models.SnagStatusOpen, models.SnagStatusAssigned, models.SnagStatusResolved, tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch defect liability periods: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	list := []models.UnitDefectLiability{}
+	for rows.Next() {
+		var d models.UnitDefectLiability
+		if err := rows.Scan(&d.UnitID, &d.UnitNumber, &d.BookingID, &d.ProjectID, &d.InspectionID,
+			&d.HandoverDate, &d.DLPExpiry, &d.OpenSnags); err != nil {
+			return nil, fmt.Errorf("failed to scan defect liability period: %w", err)
+		}
+		d.DaysRemaining, d.IsActive = dlpStatus(d.DLPExpiry, now)
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// ============================================================================
+// SNAGS
+// ============================================================================
+
+// RaiseSnag reports a snag on a handed-over unit. Snags can only be raised
+// while the unit's defect-liability period is running.
+func (s *SnagService) RaiseSnag(tenantID, userID, source string, req *models.RaiseSnagRequest) (*models.Snag, error) {
+	if req.BookingID == "" {
+		return nil, fmt.Errorf("booking_id is required")
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		return nil, fmt.Errorf("description is required")
+	}
+	severity := defaultSnagSeverity(req.Severity)
+	if err := validateSnagClass(req.Category, severity); err != nil {
+		return nil, err
+	}
+
+	dlps, err := s.getDefectLiabilities(tenantID,
+		"d.unit_id = (SELECT unit_id FROM customer_bookings WHERE id = ? AND tenant_id = d.tenant_id)", req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dlps) == 0 {
+		return nil, fmt.Errorf("unit has not been handed over; snags before handover go on the handover inspection")
+	}
+	dlp := dlps[0]
+	if !dlp.IsActive {
+		return nil, fmt.Errorf("defect liability period for unit %s ended on %s", dlp.UnitNumber, dlp.DLPExpiry.Format("2006-01-02"))
+	}
+
+	snag := &models.Snag{
+		UnitID:      dlp.UnitID,
+		BookingID:   req.BookingID,
+		ProjectID:   dlp.ProjectID,
+		Source:      source,
+		Category:    req.Category,
+		Severity:    severity,
+		Location:    req.Location,
+		Description: req.Description,
+	}
+	if err := insertSnag(s.DB, tenantID, userID, snag, req.PhotoURLs); err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snag.ID)
+}
+
+// GetSnag returns a snag with its photos and back-charges
+func (s *SnagService) GetSnag(tenantID, snagID string) (*models.Snag, error) {
+	list, err := s.getSnags(tenantID, "sn.id = ?", snagID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("snag not found")
+	}
+	snag := &list[0]
+
+	rows, err := s.DB.Query(`SELECT id, snag_id, stage, photo_url, uploaded_by, created_at
+		FROM snag_photos WHERE tenant_id = ? AND snag_id = ? ORDER BY created_at`, tenantID, snagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snag photos: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.SnagPhoto
+		var uploadedBy sql.NullString
+		if err := rows.Scan(&p.ID, &p.SnagID, &p.Stage, &p.PhotoURL, &uploadedBy, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snag photo: %w", err)
+		}
+		p.UploadedBy = nullStringPtr(uploadedBy)
+		snag.Photos = append(snag.Photos, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if snag.BackCharged > 0 {
+		if snag.BackCharges, err = s.Purchase.ListVendorBackCharges(tenantID, "", "", snagID); err != nil {
+			return nil, err
+		}
+	}
+	return snag, nil
+}
+
+// ListSnags lists snags matching the filter
+func (s *SnagService) ListSnags(tenantID string, filter models.SnagFilter) ([]models.Snag, error) {
+	where, args := "1 = 1", []interface{}{}
+	for _, f := range []struct{ column, value string }{
+		{"sn.project_id", filter.ProjectID},
+		{"sn.unit_id", filter.UnitID},
+		{"sn.booking_id", filter.BookingID},
+		{"sn.contractor_id", filter.ContractorID},
+		{"sn.status", filter.Status},
+		{"sn.category", filter.Category},
+	} {
+		if f.value != "" {
+			where += " AND " + f.column + " = ?"
+			args = append(args, f.value)
+		}
+	}
+	if filter.OverdueOnly {
+		where += " AND sn.status = ? AND sn.sla_due_date < ?"
+		args = append(args, models.SnagStatusAssigned, time.Now())
+	}
+	return s.getSnags(tenantID, where, args...)
+}
+
+// AssignSnag assigns a snag to a contractor and starts its SLA. Reassigning restarts the SLA.
+func (s *SnagService) AssignSnag(tenantID, snagID string, req *models.AssignSnagRequest) (*models.Snag, error) {
+	snag, err := s.GetSnag(tenantID, snagID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := nextSnagStatus(snag.Status, snagActionAssign)
+	if err != nil {
+		return nil, err
+	}
+
+	var contractorName string
+	err = s.DB.QueryRow(`SELECT name FROM vendors WHERE id = ? AND tenant_id = ?`, req.ContractorID, tenantID).Scan(&contractorName)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("contractor not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contractor: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.moveSnag(tenantID, snag, next, `contractor_id = ?, assigned_at = ?, sla_due_date = ?`,
+		req.ContractorID, now, snagSLADueDate(snag.Severity, now)); err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snagID)
+}
+
+// ResolveSnag records the contractor's fix, with photos of the rectified work
+func (s *SnagService) ResolveSnag(tenantID, userID, snagID string, req *models.ResolveSnagRequest) (*models.Snag, error) {
+	if strings.TrimSpace(req.ResolutionNotes) == "" {
+		return nil, fmt.Errorf("resolution_notes is required")
+	}
+	snag, err := s.GetSnag(tenantID, snagID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := nextSnagStatus(snag.Status, snagActionResolve)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.moveSnag(tenantID, snag, next, `resolved_at = ?, resolution_notes = ?`, time.Now(), req.ResolutionNotes); err != nil {
+		return nil, err
+	}
+	if err := insertSnagPhotos(s.DB, tenantID, userID, snagID, models.SnagPhotoResolved, req.PhotoURLs); err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snagID)
+}
+
+// ReviewSnag closes or reopens a resolved snag, or rejects an open one. When
+// bookingID is set the review comes from the customer, who may only close or
+// reopen snags on their own booking.
+func (s *SnagService) ReviewSnag(tenantID, userID, bookingID, snagID string, req *models.ReviewSnagRequest) (*models.Snag, error) {
+	snag, err := s.GetSnag(tenantID, snagID)
+	if err != nil {
+		return nil, err
+	}
+	if bookingID != "" {
+		if snag.BookingID != bookingID {
+			return nil, fmt.Errorf("snag not found")
+		}
+		if req.Action == models.SnagActionReject {
+			return nil, fmt.Errorf("only the builder can reject a snag")
+		}
+	}
+	if req.Action == models.SnagActionReject && strings.TrimSpace(req.Remarks) == "" {
+		return nil, fmt.Errorf("remarks are required to reject a snag")
+	}
+	next, err := nextSnagStatus(snag.Status, req.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch req.Action {
+	case models.SnagActionReopen:
+		err = s.moveSnag(tenantID, snag, next, `resolved_at = NULL, review_remarks = ?, assigned_at = ?, sla_due_date = ?`,
+			nullIfEmpty(req.Remarks), now, snagSLADueDate(snag.Severity, now))
+	default:
+		err = s.moveSnag(tenantID, snag, next, `review_remarks = ?, closed_at = ?`, nullIfEmpty(req.Remarks), now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snagID)
+}
+
+// AddSnagPhotos attaches photos to a snag; bookingID scopes the call to the customer's booking
+func (s *SnagService) AddSnagPhotos(tenantID, userID, bookingID, snagID string, req *models.AddSnagPhotosRequest) (*models.Snag, error) {
+	if len(req.PhotoURLs) == 0 {
+		return nil, fmt.Errorf("photo_urls is required")
+	}
+	stage := req.Stage
+	if stage == "" {
+		stage = models.SnagPhotoReported
+	}
+	if stage != models.SnagPhotoReported && stage != models.SnagPhotoResolved {
+		return nil, fmt.Errorf("stage must be reported or resolved")
+	}
+	snag, err := s.GetSnag(tenantID, snagID)
+	if err != nil {
+		return nil, err
+	}
+	if bookingID != "" && snag.BookingID != bookingID {
+		return nil, fmt.Errorf("snag not found")
+	}
+	if err := insertSnagPhotos(s.DB, tenantID, userID, snagID, stage, req.PhotoURLs); err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snagID)
+}
+
+// BackChargeSnag recovers the cost of a snag from its contractor by setting it
+// off against one of the contractor's open invoices
+func (s *SnagService) BackChargeSnag(tenantID, userID, snagID string, req *models.BackChargeSnagRequest) (*models.Snag, error) {
+	snag, err := s.GetSnag(tenantID, snagID)
+	if err != nil {
+		return nil, err
+	}
+	if snag.ContractorID == nil {
+		return nil, fmt.Errorf("snag has not been assigned to a contractor")
+	}
+	if snag.Status == models.SnagStatusRejected {
+		return nil, fmt.Errorf("cannot back-charge a rejected snag")
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Rectification of snag %s (%s)", snag.SnagNumber, snag.Category)
+	}
+	charge := &models.VendorBackCharge{
+		VendorID:  *snag.ContractorID,
+		InvoiceID: req.InvoiceID,
+		SnagID:    &snag.ID,
+		Amount:    req.Amount,
+		Reason:    reason,
+	}
+	if err := s.Purchase.RecordVendorBackCharge(tenantID, charge, s.GL, userID); err != nil {
+		return nil, err
+	}
+	return s.GetSnag(tenantID, snagID)
+}
+
+// moveSnag moves a snag to the next status, setting the given columns, guarded
+// against a concurrent change of status
+func (s *SnagService) moveSnag(tenantID string, snag *models.Snag, next, set string, args ...interface{}) error {
+	args = append(args, next, time.Now(), snag.ID, tenantID, snag.Status)
+	res, err := s.DB.Exec(`UPDATE snags SET `+set+`, status = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update snag: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("snag was updated by someone else, reload and try again")
+	}
+	return nil
+}
+
+func (s *SnagService) getSnags(tenantID, where string, args ...interface{}) ([]models.Snag, error) {
+	rows, err := s.DB.Query(`SELECT sn.id, sn.tenant_id, sn.snag_number, sn.unit_id, sn.booking_id, COALESCE(sn.project_id, ''),
+		sn.inspection_id, sn.source, sn.category, sn.severity, COALESCE(sn.location, ''), sn.description, sn.status,
+		sn.contractor_id, COALESCE(v.name, ''), sn.assigned_at, sn.sla_due_date, sn.resolved_at,
+		COALESCE(sn.resolution_notes, ''), sn.closed_at, COALESCE(sn.review_remarks, ''),
+		COALESCE((SELECT SUM(bc.amount) FROM vendor_back_charges bc
+			WHERE bc.tenant_id = sn.tenant_id AND bc.snag_id = sn.id AND bc.status = ?), 0),
+		sn.raised_by, sn.created_at, sn.updated_at
+		FROM snags sn
+		LEFT JOIN vendors v ON v.id = sn.contractor_id
+		WHERE sn.tenant_id = ? AND `+where+` ORDER BY sn.created_at DESC`,
+		append([]interface{}{models.VendorBackChargeApplied, tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snags: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	list := []models.Snag{}
+	for rows.Next() {
+		var sn models.Snag
+		var inspectionID, contractorID, raisedBy sql.NullString
+		var assignedAt, slaDue, resolvedAt, closedAt sql.NullTime
+		if err := rows.Scan(&sn.ID, &sn.TenantID, &sn.SnagNumber, &sn.UnitID, &sn.BookingID, &sn.ProjectID,
+			&inspectionID, &sn.Source, &sn.Category, &sn.Severity, &sn.Location, &sn.Description, &sn.Status,
+			&contractorID, &sn.ContractorName, &assignedAt, &slaDue, &resolvedAt,
+			&sn.ResolutionNotes, &closedAt, &sn.ReviewRemarks, &sn.BackCharged,
+			&raisedBy, &sn.CreatedAt, &sn.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snag: %w", err)
+		}
+		sn.InspectionID = nullStringPtr(inspectionID)
+		sn.ContractorID = nullStringPtr(contractorID)
+		sn.RaisedBy = nullStringPtr(raisedBy)
+		if assignedAt.Valid {
+			sn.AssignedAt = &assignedAt.Time
+		}
+		if slaDue.Valid {
+			sn.SLADueDate = &slaDue.Time
+		}
+		if resolvedAt.Valid {
+			sn.ResolvedAt = &resolvedAt.Time
+		}
+		if closedAt.Valid {
+			sn.ClosedAt = &closedAt.Time
+		}
+		sn.SLABreached = snagSLABreached(sn.SLADueDate, sn.ResolvedAt, now)
+		list = append(list, sn)
+	}
+	return list, rows.Err()
+}
+
+// insertSnag saves a new open snag with its reported photos
+func insertSnag(db sqlExecer, tenantID, userID string, snag *models.Snag, photoURLs []string) error {
+	now := time.Now()
+	snag.ID = uuid.New().String()
+	snag.TenantID = tenantID
+	snag.SnagNumber = interestNoteNumber("SNAG")
+	snag.Status = models.SnagStatusOpen
+	snag.RaisedBy = optionalString(userID)
+	snag.CreatedAt = now
+	snag.UpdatedAt = now
+
+	if _, err := db.Exec(`INSERT INTO snags
+		(id, tenant_id, snag_number, unit_id, booking_id, project_id, inspection_id, source, category, severity,
+		 location, description, status, raised_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snag.ID, tenantID, snag.SnagNumber, snag.UnitID, snag.BookingID, nullIfEmpty(snag.ProjectID), snag.InspectionID,
+		snag.Source, snag.Category, snag.Severity, nullIfEmpty(snag.Location), snag.Description, snag.Status,
+		snag.RaisedBy, snag.CreatedAt, snag.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to raise snag: %w", err)
+	}
+	return insertSnagPhotos(db, tenantID, userID, snag.ID, models.SnagPhotoReported, photoURLs)
+}
+
+func insertSnagPhotos(db sqlExecer, tenantID, userID, snagID, stage string, photoURLs []string) error {
+	for _, url := range photoURLs {
+		if strings.TrimSpace(url) == "" {
+			continue
+		}
+		if _, err := db.Exec(`INSERT INTO snag_photos (id, tenant_id, snag_id, stage, photo_url, uploaded_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), tenantID, snagID, stage, url, optionalString(userID), time.Now()); err != nil {
+			return fmt.Errorf("failed to add snag photo: %w", err)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// SNAG RULES
+// ============================================================================
+
+// defectLiabilityExpiry is the last day the builder is liable for defects
+func defectLiabilityExpiry(handover time.Time) time.Time {
+	return handover.AddDate(models.DefectLiabilityYears, 0, 0)
+}
+
+// dlpStatus returns the whole days left in a defect-liability period and whether it is still running
+func dlpStatus(expiry, now time.Time) (int, bool) {
+	if now.After(expiry) {
+		return 0, false
+	}
+	return int(expiry.Sub(now).Hours() / 24), true
+}
+
+// snagSLADueDate is when a contractor must fix a snag of the given severity assigned at `from`
+func snagSLADueDate(severity string, from time.Time) time.Time {
+	days, ok := snagSLADays[severity]
+	if !ok {
+		days = snagSLADays[models.SnagSeverityMinor]
+	}
+	return from.AddDate(0, 0, days)
+}
+
+// snagSLABreached reports whether a snag was, or still is, unresolved past its SLA
+func snagSLABreached(due, resolvedAt *time.Time, now time.Time) bool {
+	if due == nil {
+		return false
+	}
+	if resolvedAt != nil {
+		return resolvedAt.After(*due)
+	}
+	return now.After(*due)
+}
+
+// nextSnagStatus returns the status a snag moves to on an action
+func nextSnagStatus(current, action string) (string, error) {
+	allowed := map[string][]string{
+		snagActionAssign:        {models.SnagStatusOpen, models.SnagStatusAssigned},
+		snagActionResolve:       {models.SnagStatusAssigned},
+		models.SnagActionClose:  {models.SnagStatusResolved},
+		models.SnagActionReopen: {models.SnagStatusResolved},
+		models.SnagActionReject: {models.SnagStatusOpen, models.SnagStatusAssigned},
+	}
+	next := map[string]string{
+		snagActionAssign:        models.SnagStatusAssigned,
+		snagActionResolve:       models.SnagStatusResolved,
+		models.SnagActionClose:  models.SnagStatusClosed,
+		models.SnagActionReopen: models.SnagStatusAssigned,
+		models.SnagActionReject: models.SnagStatusRejected,
+	}
+
+	from, ok := allowed[action]
+	if !ok {
+		return "", fmt.Errorf("action must be close, reopen or reject")
+	}
+	for _, status := range from {
+		if status == current {
+			return next[action], nil
+		}
+	}
+	return "", fmt.Errorf("cannot %s a snag that is %s", action, current)
+}
+
+func defaultSnagSeverity(severity string) string {
+	if severity == "" {
+		return models.SnagSeverityMinor
+	}
+	return severity
+}
+
+// validateSnagClass checks the category and severity are known
+func validateSnagClass(category, severity string) error {
+	known := false
+	for _, c := range models.SnagCategories {
+		if c == category {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("category must be one of %s", strings.Join(models.SnagCategories, ", "))
+	}
+	if _, ok := snagSLADays[severity]; !ok {
+		return fmt.Errorf("severity must be critical, major or minor")
+	}
+	return nil
+}
+
+// validateChecklist checks the handover checklist; snag lines need a valid category and severity
+func validateChecklist(items []models.HandoverChecklistInput) error {
+	if len(items) == 0 {
+		return fmt.Errorf("checklist must have at least one item")
+	}
+	for i, item := range items {
+		if strings.TrimSpace(item.Area) == "" || strings.TrimSpace(item.Item) == "" {
+			return fmt.Errorf("item %d: area and item are required", i+1)
+		}
+		switch item.Result {
+		case models.ChecklistResultOK, models.ChecklistResultNA:
+		case models.ChecklistResultSnag:
+			if err := validateSnagClass(item.Category, defaultSnagSeverity(item.Severity)); err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+		default:
+			return fmt.Errorf("item %d: result must be ok, snag or na", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDefectLiabilityPeriod tests the five-year DLP from handover
+func TestDefectLiabilityPeriod(t *testing.T) {
+	handover := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	expiry := defectLiabilityExpiry(handover)
+	assert.Equal(t, time.Date(2031, 3, 15, 0, 0, 0, 0, time.UTC), expiry)
+
+	days, active := dlpStatus(expiry, time.Date(2031, 3, 5, 0, 0, 0, 0, time.UTC))
+	assert.True(t, active)
+	assert.Equal(t, 10, days)
+
+	days, active = dlpStatus(expiry, time.Date(2031, 3, 16, 0, 0, 0, 0, time.UTC))
+	assert.False(t, active)
+	assert.Equal(t, 0, days)
+}
+
+// TestSnagSLA tests SLA due dates by severity and breach detection
+func TestSnagSLA(t *testing.T) {
+	assigned := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC), snagSLADueDate(models.SnagSeverityCritical, assigned))
+	assert.Equal(t, time.Date(2026, 10, 8, 10, 0, 0, 0, time.UTC), snagSLADueDate(models.SnagSeverityMajor, assigned))
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), snagSLADueDate(models.SnagSeverityMinor, assigned))
+
+	due := snagSLADueDate(models.SnagSeverityMajor, assigned)
+	early := due.Add(-time.Hour)
+	late := due.Add(time.Hour)
+	assert.False(t, snagSLABreached(nil, nil, late))
+	assert.False(t, snagSLABreached(&due, nil, early))
+	assert.True(t, snagSLABreached(&due, nil, late))
+	assert.False(t, snagSLABreached(&due, &early, late)) // fixed in time stays within SLA
+	assert.True(t, snagSLABreached(&due, &late, late))
+}
+
+// TestNextSnagStatus tests the snag workflow transitions
+func TestNextSnagStatus(t *testing.T) {
+	next, err := nextSnagStatus(models.SnagStatusOpen, snagActionAssign)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SnagStatusAssigned, next)
+
+	next, err = nextSnagStatus(models.SnagStatusAssigned, snagActionResolve)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SnagStatusResolved, next)
+
+	next, err = nextSnagStatus(models.SnagStatusResolved, models.SnagActionReopen)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SnagStatusAssigned, next)
+
+	next, err = nextSnagStatus(models.SnagStatusResolved, models.SnagActionClose)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SnagStatusClosed, next)
+
+	_, err = nextSnagStatus(models.SnagStatusOpen, snagActionResolve)
+	assert.Error(t, err)
+	_, err = nextSnagStatus(models.SnagStatusOpen, models.SnagActionClose)
+	assert.Error(t, err)
+	_, err = nextSnagStatus(models.SnagStatusResolved, models.SnagActionReject)
+	assert.Error(t, err)
+	_, err = nextSnagStatus(models.SnagStatusClosed, snagActionAssign)
+	assert.Error(t, err)
+	_, err = nextSnagStatus(models.SnagStatusOpen, "escalate")
+	assert.Error(t, err)
+}
+
+// TestValidateChecklistAndBackCharge tests checklist lines and the back-charge cap
+func TestValidateChecklistAndBackCharge(t *testing.T) {
+	items := []models.HandoverChecklistInput{
+		{Area: "Kitchen", Item: "Flooring", Result: models.ChecklistResultOK},
+		{Area: "Master Bath", Item: "Wall tiles", Result: models.ChecklistResultSnag, Category: "tiles"},
+		{Area: "Balcony", Item: "Railing", Result: models.ChecklistResultNA},
+	}
+	assert.NoError(t, validateChecklist(items))
+	assert.Error(t, validateChecklist(nil))
+
+	uncategorised := append([]models.HandoverChecklistInput{}, items...)
+	uncategorised[1].Category = ""
+	assert.Error(t, validateChecklist(uncategorised))
+
+	badSeverity := append([]models.HandoverChecklistInput{}, items...)
+	badSeverity[1].Severity = "urgent"
+	assert.Error(t, validateChecklist(badSeverity))
+
+	badResult := append([]models.HandoverChecklistInput{}, items...)
+	badResult[0].Result = "pass"
+	assert.Error(t, validateChecklist(badResult))
+
+	assert.NoError(t, validateBackCharge(25000, 100000))
+	assert.NoError(t, validateBackCharge(100000, 100000))
+	assert.Error(t, validateBackCharge(100000.01, 100000))
+	assert.Error(t, validateBackCharge(0, 100000))
+}
@@ -0,0 +1,870 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// SUBVENTION SERVICE
+// ============================================================================
+
+// SubventionService accounts for builder-paid pre-EMI interest: agreements on a
+// booking's BankFinancing, the monthly obligation schedule, GL provisioning as
+// interest accrues, payments to the lender and the liability by project. An
+// agreement stops on possession or cancellation of the booking.
+type SubventionService struct {
+	DB *sql.DB
+	GL *GLService
+}
+
+// NewSubventionService creates a new subvention service
+func NewSubventionService(db *sql.DB, gl *GLService) *SubventionService {
+	return &SubventionService{DB: db, GL: gl}
+}
+
+// subventionDueDays is when the lender debits a month's pre-EMI after the month ends
+const subventionDueDays = 5
+
+// subventionPeriod is one month of pre-EMI interest
+type subventionPeriod struct {
+	From time.Time
+	To   time.Time // exclusive
+}
+
+// loanDraw is a disbursement credited to the developer, on which interest runs
+type loanDraw struct {
+	Date   time.Time
+	Amount float64
+}
+
+// ============================================================================
+// AGREEMENTS
+// ============================================================================
+
+// CreateAgreement puts a booking's loan under a subvention scheme and schedules
+// the monthly obligations from the start date to the scheme end date
+func (s *SubventionService) CreateAgreement(tenantID, userID string, req *models.CreateSubventionAgreementRequest) (*models.SubventionAgreement, error) {
+	if req.InterestRate <= 0 || req.InterestRate > 30 {
+		return nil, fmt.Errorf("interest_rate must be between 0 and 30")
+	}
+	if req.MaxAmount < 0 {
+		return nil, fmt.Errorf("max_amount cannot be negative")
+	}
+	now := time.Now()
+	start := now.Truncate(24 * time.Hour)
+	if req.StartDate != nil {
+		start = req.StartDate.Truncate(24 * time.Hour)
+	}
+	end := req.EndDate.Truncate(24 * time.Hour)
+	if !end.After(start) {
+		return nil, fmt.Errorf("end_date must be after start_date")
+	}
+
+	a := &models.SubventionAgreement{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		AgreementNumber: interestNoteNumber("SUBV"),
+		FinancingID:     req.FinancingID,
+		SchemeName:      req.SchemeName,
+		InterestRate:    req.InterestRate,
+		StartDate:       start,
+		EndDate:         end,
+		MaxAmount:       roundTo2(req.MaxAmount),
+		Status:          models.SubventionActive,
+		CreatedBy:       optionalString(userID),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var bankID sql.NullString
+	var financingStatus, bookingStatus string
+	err = tx.QueryRow(`SELECT f.booking_id, f.bank_id, f.status, COALESCE(u.project_id, ''),
+		COALESCE(b.booking_status, '')
+		FROM bank_financing f
+		LEFT JOIN customer_bookings b ON b.id = f.booking_id AND b.tenant_id = f.tenant_id
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		WHERE f.id = ? AND f.tenant_id = ? AND f.deleted_at IS NULL`, req.FinancingID, tenantID).Scan(
+		&a.BookingID, &bankID, &financingStatus, &a.ProjectID, &bookingStatus)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bank financing not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank financing: %w", err)
+	}
+	if !bankID.Valid {
+		return nil, fmt.Errorf("bank financing has no lender")
+	}
+	a.BankID = bankID.String
+	if financingStatus == "rejected" || financingStatus == "cancelled" {
+		return nil, fmt.Errorf("bank financing is %s", financingStatus)
+	}
+	if bookingStatus == "cancelled" {
+		return nil, fmt.Errorf("booking is cancelled")
+	}
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM subvention_agreements
+		WHERE tenant_id = ? AND financing_id = ? AND status = ?)`,
+		tenantID, req.FinancingID, models.SubventionActive).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check existing agreement: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("the loan already has an active subvention agreement")
+	}
+
+	draws, err := s.loanDraws(tx, tenantID, a.FinancingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO subvention_agreements
+		(id, tenant_id, agreement_number, financing_id, booking_id, bank_id, project_id, scheme_name, interest_rate,
+		 start_date, end_date, max_amount, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, tenantID, a.AgreementNumber, a.FinancingID, a.BookingID, a.BankID, nullIfEmpty(a.ProjectID),
+		nullIfEmpty(a.SchemeName), a.InterestRate, a.StartDate, a.EndDate, a.MaxAmount, a.Status, a.CreatedBy,
+		now, now); err != nil {
+		return nil, fmt.Errorf("failed to create subvention agreement: %w", err)
+	}
+	for _, p := range subventionPeriods(start, end) {
+		o := models.SubventionObligation{
+			ID:          uuid.New().String(),
+			TenantID:    tenantID,
+			AgreementID: a.ID,
+			PeriodFrom:  p.From,
+			PeriodTo:    p.To,
+			DueDate:     p.To.AddDate(0, 0, subventionDueDays),
+			LoanBalance: loanBalanceAt(draws, p.To),
+			Amount:      subventionInterest(draws, a.InterestRate, p.From, p.To),
+			Status:      models.SubventionObligationScheduled,
+		}
+		if err := insertSubventionObligation(tx, &o); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit subvention agreement: %w", err)
+	}
+	return s.GetAgreement(tenantID, a.ID)
+}
+
+// GetAgreement returns an agreement with its obligation schedule
+func (s *SubventionService) GetAgreement(tenantID, agreementID string) (*models.SubventionAgreement, error) {
+	agreements, err := s.getAgreements(tenantID, "AND sa.id = ?", agreementID)
+	if err != nil {
+		return nil, err
+	}
+	if len(agreements) == 0 {
+		return nil, fmt.Errorf("subvention agreement not found")
+	}
+	a := &agreements[0]
+	if a.Obligations, err = s.getObligations(s.DB, tenantID, "AND agreement_id = ? ORDER BY period_from", a.ID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAgreements lists agreements, optionally by status, project or booking
+func (s *SubventionService) ListAgreements(tenantID, status, projectID, bookingID string) ([]models.SubventionAgreement, error) {
+	where := ""
+	args := []interface{}{}
+	if status != "" {
+		where += " AND sa.status = ?"
+		args = append(args, status)
+	}
+	if projectID != "" {
+		where += " AND sa.project_id = ?"
+		args = append(args, projectID)
+	}
+	if bookingID != "" {
+		where += " AND sa.booking_id = ?"
+		args = append(args, bookingID)
+	}
+	return s.getAgreements(tenantID, where, args...)
+}
+
+// StopAgreement ends an agreement before the scheme end date
+func (s *SubventionService) StopAgreement(tenantID, agreementID string, req *models.StopSubventionRequest) (*models.SubventionAgreement, error) {
+	switch req.Reason {
+	case models.SubventionStopPossession, models.SubventionStopCancellation, models.SubventionStopManual:
+	default:
+		return nil, fmt.Errorf("reason must be possession, cancellation or manual")
+	}
+	stopDate := time.Now().Truncate(24 * time.Hour)
+	if req.StopDate != nil {
+		stopDate = req.StopDate.Truncate(24 * time.Hour)
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var financingID, status string
+	var rate float64
+	err = tx.QueryRow(`SELECT financing_id, interest_rate, status FROM subvention_agreements
+		WHERE id = ? AND tenant_id = ? FOR UPDATE`, agreementID, tenantID).Scan(&financingID, &rate, &status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("subvention agreement not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subvention agreement: %w", err)
+	}
+	if status != models.SubventionActive {
+		return nil, fmt.Errorf("subvention agreement is %s", status)
+	}
+	if err := s.stop(tx, tenantID, agreementID, financingID, rate, req.Reason, stopDate); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit stop: %w", err)
+	}
+	return s.GetAgreement(tenantID, agreementID)
+}
+
+// StopForBooking stops the booking's active agreement, if any. It is called
+// when the booking is cancelled or handed over.
+func (s *SubventionService) StopForBooking(tenantID, bookingID, reason string, stopDate time.Time) error {
+	var agreementID string
+	err := s.DB.QueryRow(`SELECT id FROM subvention_agreements WHERE tenant_id = ? AND booking_id = ? AND status = ?`,
+		tenantID, bookingID, models.SubventionActive).Scan(&agreementID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get subvention agreement: %w", err)
+	}
+	_, err = s.StopAgreement(tenantID, agreementID, &models.StopSubventionRequest{Reason: reason, StopDate: &stopDate})
+	return err
+}
+
+// stop cancels the scheduled obligations after the stop date and cuts the month
+// it falls in short. Obligations already provisioned stay payable to the bank.
+func (s *SubventionService) stop(tx *sql.Tx, tenantID, agreementID, financingID string, rate float64, reason string, stopDate time.Time) error {
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE subvention_obligations SET status = ?
+		WHERE tenant_id = ? AND agreement_id = ? AND status = ? AND period_from >= ?`,
+		models.SubventionObligationCancelled, tenantID, agreementID, models.SubventionObligationScheduled,
+		stopDate); err != nil {
+		return fmt.Errorf("failed to cancel subvention obligations: %w", err)
+	}
+
+	var obligationID string
+	var from time.Time
+	err := tx.QueryRow(`SELECT id, period_from FROM subvention_obligations
+		WHERE tenant_id = ? AND agreement_id = ? AND status = ? AND period_from < ? AND period_to > ?`,
+		tenantID, agreementID, models.SubventionObligationScheduled, stopDate, stopDate).Scan(&obligationID, &from)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get current obligation: %w", err)
+	}
+	if err == nil {
+		draws, err := s.loanDraws(tx, tenantID, financingID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE subvention_obligations SET period_to = ?, due_date = ?, loan_balance = ?, amount = ?
+			WHERE id = ? AND tenant_id = ?`,
+			stopDate, stopDate.AddDate(0, 0, subventionDueDays), loanBalanceAt(draws, stopDate),
+			subventionInterest(draws, rate, from, stopDate), obligationID, tenantID); err != nil {
+			return fmt.Errorf("failed to cut obligation short: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE subvention_agreements SET status = ?, stop_reason = ?, stopped_on = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`, models.SubventionStopped, reason, stopDate, now, agreementID, tenantID); err != nil {
+		return fmt.Errorf("failed to stop subvention agreement: %w", err)
+	}
+	return nil
+}
+
+func (s *SubventionService) getAgreements(tenantID, where string, args ...interface{}) ([]models.SubventionAgreement, error) {
+	rows, err := s.DB.Query(`SELECT sa.id, sa.tenant_id, sa.agreement_number, sa.financing_id, sa.booking_id,
+		sa.bank_id, COALESCE(sa.project_id, ''), COALESCE(sa.scheme_name, ''), sa.interest_rate, sa.start_date,
+		sa.end_date, sa.max_amount, sa.status, COALESCE(sa.stop_reason, ''), sa.stopped_on,
+		COALESCE(SUM(CASE WHEN o.status IN ('provisioned', 'paid') THEN o.amount END), 0),
+		COALESCE(SUM(CASE WHEN o.status = 'paid' THEN o.amount END), 0),
+		COALESCE(SUM(CASE WHEN o.status = 'scheduled' THEN o.amount END), 0),
+		sa.created_by, sa.created_at, sa.updated_at
+		FROM subvention_agreements sa
+		LEFT JOIN subvention_obligations o ON o.agreement_id = sa.id AND o.tenant_id = sa.tenant_id
+		WHERE sa.tenant_id = ? `+where+`
+		GROUP BY sa.id ORDER BY sa.created_at DESC`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subvention agreements: %w", err)
+	}
+	defer rows.Close()
+
+	agreements := []models.SubventionAgreement{}
+	for rows.Next() {
+		var a models.SubventionAgreement
+		var stoppedOn sql.NullTime
+		var createdBy sql.NullString
+		if err := rows.Scan(&a.ID, &a.TenantID, &a.AgreementNumber, &a.FinancingID, &a.BookingID, &a.BankID,
+			&a.ProjectID, &a.SchemeName, &a.InterestRate, &a.StartDate, &a.EndDate, &a.MaxAmount, &a.Status,
+			&a.StopReason, &stoppedOn, &a.Provisioned, &a.Paid, &a.Projected, &createdBy, &a.CreatedAt,
+			&a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subvention agreement: %w", err)
+		}
+		if stoppedOn.Valid {
+			a.StoppedOn = &stoppedOn.Time
+		}
+		a.CreatedBy = nullStringPtr(createdBy)
+		a.Payable = roundTo2(a.Provisioned - a.Paid)
+		agreements = append(agreements, a)
+	}
+	return agreements, rows.Err()
+}
+
+func (s *SubventionService) getObligations(db interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, tenantID, where string, args ...interface{}) ([]models.SubventionObligation, error) {
+	rows, err := db.Query(`SELECT id, tenant_id, agreement_id, period_from, period_to, due_date, loan_balance, amount,
+		status, journal_entry_id, provisioned_at, payment_id, paid_at
+		FROM subvention_obligations WHERE tenant_id = ? `+where, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subvention obligations: %w", err)
+	}
+	defer rows.Close()
+
+	obligations := []models.SubventionObligation{}
+	for rows.Next() {
+		var o models.SubventionObligation
+		var entryID, paymentID sql.NullString
+		var provisionedAt, paidAt sql.NullTime
+		if err := rows.Scan(&o.ID, &o.TenantID, &o.AgreementID, &o.PeriodFrom, &o.PeriodTo, &o.DueDate,
+			&o.LoanBalance, &o.Amount, &o.Status, &entryID, &provisionedAt, &paymentID, &paidAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subvention obligation: %w", err)
+		}
+		o.JournalEntryID = nullStringPtr(entryID)
+		o.PaymentID = nullStringPtr(paymentID)
+		if provisionedAt.Valid {
+			o.ProvisionedAt = &provisionedAt.Time
+		}
+		if paidAt.Valid {
+			o.PaidAt = &paidAt.Time
+		}
+		obligations = append(obligations, o)
+	}
+	return obligations, rows.Err()
+}
+
+func insertSubventionObligation(tx *sql.Tx, o *models.SubventionObligation) error {
+	if _, err := tx.Exec(`INSERT INTO subvention_obligations
+		(id, tenant_id, agreement_id, period_from, period_to, due_date, loan_balance, amount, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		o.ID, o.TenantID, o.AgreementID, o.PeriodFrom, o.PeriodTo, o.DueDate, o.LoanBalance, o.Amount,
+		o.Status); err != nil {
+		return fmt.Errorf("failed to schedule subvention obligation: %w", err)
+	}
+	return nil
+}
+
+// loanDraws returns the disbursements credited on a loan, oldest first
+func (s *SubventionService) loanDraws(db interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, tenantID, financingID string) ([]loanDraw, error) {
+	rows, err := db.Query(`SELECT actual_date, actual_amount FROM bank_disbursement
+		WHERE tenant_id = ? AND financing_id = ? AND status IN ('released', 'credited')
+		AND actual_date IS NOT NULL AND actual_amount > 0 AND deleted_at IS NULL
+		ORDER BY actual_date`, tenantID, financingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loan disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	draws := []loanDraw{}
+	for rows.Next() {
+		var d loanDraw
+		if err := rows.Scan(&d.Date, &d.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan loan disbursement: %w", err)
+		}
+		draws = append(draws, d)
+	}
+	return draws, rows.Err()
+}
+
+// ============================================================================
+// PROVISIONING
+// ============================================================================
+
+// Provision accrues every month that has ended by the as-of date. Agreements whose
+// booking has been handed over or cancelled are stopped first, so interest after
+// possession or cancellation is never provided for. Each month's interest is
+// recomputed on the disbursements actually credited and capped at the agreement's
+// maximum.
+func (s *SubventionService) Provision(tenantID, userID string, req *models.ProvisionSubventionRequest) (*models.SubventionProvisionRun, error) {
+	asOf := time.Now().Truncate(24 * time.Hour)
+	if req.AsOf != nil {
+		asOf = req.AsOf.Truncate(24 * time.Hour)
+	}
+	run := &models.SubventionProvisionRun{AsOf: asOf, Obligations: []models.SubventionObligation{}}
+
+	stopped, err := s.stopEnded(tenantID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	run.AgreementsStopped = stopped
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT o.id, o.agreement_id, o.period_from, o.period_to, sa.financing_id, sa.interest_rate,
+		sa.max_amount, sa.agreement_number,
+		(SELECT COALESCE(SUM(p.amount), 0) FROM subvention_obligations p WHERE p.tenant_id = sa.tenant_id
+		 AND p.agreement_id = sa.id AND p.status IN ('provisioned', 'paid'))
+		FROM subvention_obligations o
+		JOIN subvention_agreements sa ON sa.id = o.agreement_id AND sa.tenant_id = o.tenant_id
+		WHERE o.tenant_id = ? AND o.status = ? AND o.period_to <= ? AND sa.status IN (?, ?)
+		ORDER BY o.agreement_id, o.period_from FOR UPDATE`,
+		tenantID, models.SubventionObligationScheduled, asOf, models.SubventionActive, models.SubventionStopped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due obligations: %w", err)
+	}
+	type dueObligation struct {
+		models.SubventionObligation
+		FinancingID     string
+		Rate            float64
+		MaxAmount       float64
+		AgreementNumber string
+		Provisioned     float64
+	}
+	var due []dueObligation
+	for rows.Next() {
+		var d dueObligation
+		if err := rows.Scan(&d.ID, &d.AgreementID, &d.PeriodFrom, &d.PeriodTo, &d.FinancingID, &d.Rate, &d.MaxAmount,
+			&d.AgreementNumber, &d.Provisioned); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan due obligation: %w", err)
+		}
+		d.TenantID = tenantID
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	draws := map[string][]loanDraw{}
+	provisioned := map[string]float64{}
+	for _, d := range due {
+		if _, ok := draws[d.FinancingID]; !ok {
+			if draws[d.FinancingID], err = s.loanDraws(tx, tenantID, d.FinancingID); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := provisioned[d.AgreementID]; !ok {
+			provisioned[d.AgreementID] = d.Provisioned
+		}
+		o := d.SubventionObligation
+		o.LoanBalance = loanBalanceAt(draws[d.FinancingID], o.PeriodTo)
+		o.Amount = capSubvention(subventionInterest(draws[d.FinancingID], d.Rate, o.PeriodFrom, o.PeriodTo),
+			d.MaxAmount, provisioned[d.AgreementID])
+		provisioned[d.AgreementID] += o.Amount
+		o.Status = models.SubventionObligationProvisioned
+		o.ProvisionedAt = &now
+		if o.Amount > 0 {
+			entryID := fmt.Sprintf("JE-SUBV-PROV-%s", o.ID)
+			o.JournalEntryID = &entryID
+			if err := s.postProvision(tx, tenantID, userID, &o, asOf); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tx.Exec(`UPDATE subvention_obligations SET loan_balance = ?, amount = ?, status = ?,
+			journal_entry_id = ?, provisioned_at = ? WHERE id = ? AND tenant_id = ?`,
+			o.LoanBalance, o.Amount, o.Status, o.JournalEntryID, now, o.ID, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to provision obligation: %w", err)
+		}
+		run.Provisioned += o.Amount
+		run.Obligations = append(run.Obligations, o)
+	}
+	if _, err := tx.Exec(`UPDATE subvention_agreements sa SET status = ?, updated_at = ?
+		WHERE sa.tenant_id = ? AND sa.status = ? AND sa.end_date <= ?
+		AND NOT EXISTS (SELECT 1 FROM subvention_obligations o WHERE o.tenant_id = sa.tenant_id
+		AND o.agreement_id = sa.id AND o.status = ?)`,
+		models.SubventionCompleted, now, tenantID, models.SubventionActive, asOf,
+		models.SubventionObligationScheduled); err != nil {
+		return nil, fmt.Errorf("failed to complete subvention agreements: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit provisioning: %w", err)
+	}
+	run.Provisioned = roundTo2(run.Provisioned)
+	return run, nil
+}
+
+// postProvision posts an obligation's provision to the GL in the transaction that
+// marks it provisioned
+func (s *SubventionService) postProvision(tx *sql.Tx, tenantID, userID string, o *models.SubventionObligation, asOf time.Time) error {
+	entry := &models.JournalEntry{
+		ID:            *o.JournalEntryID,
+		TenantID:      tenantID,
+		EntryDate:     asOf,
+		ReferenceType: "Subvention_Provision",
+		ReferenceID:   &o.ID,
+		Description:   fmt.Sprintf("Pre-EMI subvention %s to %s", o.PeriodFrom.Format("02 Jan 2006"), o.PeriodTo.AddDate(0, 0, -1).Format("02 Jan 2006")),
+		Amount:        o.Amount,
+		Narration:     fmt.Sprintf("Interest on loan of %.2f", o.LoanBalance),
+		EntryStatus:   "Draft",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-SUBVENTION-EXPENSE", DebitAmount: o.Amount, Description: "Pre-EMI interest borne"},
+		{AccountID: "ACC-SUBVENTION-PAYABLE", CreditAmount: o.Amount, Description: "Payable to lender"},
+	}
+	return s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID)
+}
+
+// stopEnded stops active agreements whose booking was handed over or cancelled by the as-of date
+func (s *SubventionService) stopEnded(tenantID string, asOf time.Time) (int, error) {
+	rows, err := s.DB.Query(`SELECT sa.id, sa.booking_id,
+		(SELECT MIN(ps.possession_date) FROM possession_statuses ps WHERE ps.tenant_id = sa.tenant_id
+		 AND ps.booking_id = sa.booking_id AND ps.status = 'completed' AND ps.deleted_at IS NULL),
+		(SELECT MIN(bc.approved_at) FROM booking_cancellations bc WHERE bc.tenant_id = sa.tenant_id
+		 AND bc.booking_id = sa.booking_id AND bc.status IN (?, ?, ?))
+		FROM subvention_agreements sa WHERE sa.tenant_id = ? AND sa.status = ?`,
+		models.CancellationStatusPendingSettlement, models.CancellationStatusApproved, models.CancellationStatusRefunded,
+		tenantID, models.SubventionActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch active agreements: %w", err)
+	}
+	type ended struct {
+		BookingID string
+		Reason    string
+		On        time.Time
+	}
+	var toStop []ended
+	for rows.Next() {
+		var id, bookingID string
+		var possession, cancelled sql.NullTime
+		if err := rows.Scan(&id, &bookingID, &possession, &cancelled); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan active agreement: %w", err)
+		}
+		if reason, on, ok := subventionStopEvent(possession, cancelled, asOf); ok {
+			toStop = append(toStop, ended{BookingID: bookingID, Reason: reason, On: on})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, e := range toStop {
+		if err := s.StopForBooking(tenantID, e.BookingID, e.Reason, e.On); err != nil {
+			return 0, err
+		}
+	}
+	return len(toStop), nil
+}
+
+// ============================================================================
+// PAYMENTS TO LENDERS
+// ============================================================================
+
+// RecordPayment pays provisioned obligations to the lender and clears the provision
+func (s *SubventionService) RecordPayment(tenantID, userID string, req *models.RecordSubventionPaymentRequest) (*models.SubventionPayment, error) {
+	if len(req.ObligationIDs) == 0 {
+		return nil, fmt.Errorf("obligation_ids are required")
+	}
+	if strings.TrimSpace(req.PaymentMode) == "" {
+		return nil, fmt.Errorf("payment_mode is required")
+	}
+	now := time.Now()
+	p := &models.SubventionPayment{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		PaymentNumber: interestNoteNumber("SUBP"),
+		BankID:        req.BankID,
+		PaymentDate:   now,
+		PaymentMode:   req.PaymentMode,
+		Reference:     req.Reference,
+		ObligationIDs: req.ObligationIDs,
+		CreatedBy:     optionalString(userID),
+		CreatedAt:     now,
+	}
+	if req.PaymentDate != nil {
+		p.PaymentDate = *req.PaymentDate
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(req.ObligationIDs)), ", ")
+	args := []interface{}{}
+	for _, id := range req.ObligationIDs {
+		args = append(args, id)
+	}
+	obligations, err := s.getObligations(tx, tenantID, "AND id IN ("+placeholders+") FOR UPDATE", args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(obligations) != len(req.ObligationIDs) {
+		return nil, fmt.Errorf("one or more obligations not found")
+	}
+	var agreementBank string
+	for _, o := range obligations {
+		if o.Status != models.SubventionObligationProvisioned {
+			return nil, fmt.Errorf("obligation for %s is %s", o.PeriodFrom.Format("Jan 2006"), o.Status)
+		}
+		if err := tx.QueryRow(`SELECT bank_id FROM subvention_agreements WHERE id = ? AND tenant_id = ?`,
+			o.AgreementID, tenantID).Scan(&agreementBank); err != nil {
+			return nil, fmt.Errorf("failed to get subvention agreement: %w", err)
+		}
+		if agreementBank != req.BankID {
+			return nil, fmt.Errorf("obligation for %s is owed to a different bank", o.PeriodFrom.Format("Jan 2006"))
+		}
+		p.Amount += o.Amount
+	}
+	p.Amount = roundTo2(p.Amount)
+	if p.Amount <= 0 {
+		return nil, fmt.Errorf("nothing to pay on the selected obligations")
+	}
+	entryID := fmt.Sprintf("JE-SUBV-PAY-%s", p.ID)
+	p.JournalEntryID = &entryID
+
+	if _, err := tx.Exec(`INSERT INTO subvention_payments
+		(id, tenant_id, payment_number, bank_id, payment_date, amount, payment_mode, reference, journal_entry_id,
+		 created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, tenantID, p.PaymentNumber, p.BankID, p.PaymentDate, p.Amount, p.PaymentMode, nullIfEmpty(p.Reference),
+		entryID, p.CreatedBy, now); err != nil {
+		return nil, fmt.Errorf("failed to record subvention payment: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE subvention_obligations SET status = ?, payment_id = ?, paid_at = ?
+		WHERE tenant_id = ? AND id IN (`+placeholders+`)`,
+		append([]interface{}{models.SubventionObligationPaid, p.ID, p.PaymentDate, tenantID}, args...)...); err != nil {
+		return nil, fmt.Errorf("failed to mark obligations paid: %w", err)
+	}
+
+	reference := p.PaymentNumber
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       p.PaymentDate,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Subvention_Payment",
+		ReferenceID:     &p.ID,
+		Description:     fmt.Sprintf("Pre-EMI subvention paid for %d month(s)", len(obligations)),
+		Amount:          p.Amount,
+		Narration:       fmt.Sprintf("%s %s", p.PaymentMode, p.Reference),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-SUBVENTION-PAYABLE", DebitAmount: p.Amount, Description: "Subvention paid to lender"},
+		{AccountID: "ACC-BANK-CASH", CreditAmount: p.Amount, Description: fmt.Sprintf("Subvention payment %s", p.Reference)},
+	}
+	if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit subvention payment: %w", err)
+	}
+	return p, nil
+}
+
+// ============================================================================
+// LIABILITY REPORT
+// ============================================================================
+
+// subventionLiabilityLine is an obligation with the project and lender it is reported under
+type subventionLiabilityLine struct {
+	AgreementID string
+	ProjectID   string
+	ProjectName string
+	BankID      string
+	BankName    string
+	Active      bool
+	LoanBalance float64
+	Obligation  models.SubventionObligation
+}
+
+// GetLiabilityReport returns the subvention liability by project and lender:
+// provisioned but unpaid, overdue, and what remains committed to the scheme end
+func (s *SubventionService) GetLiabilityReport(tenantID, projectID string, asOf time.Time) (*models.SubventionLiabilityReport, error) {
+	query := `SELECT sa.id, COALESCE(sa.project_id, ''), COALESCE(p.project_name, ''), sa.bank_id,
+		COALESCE(bk.bank_name, ''), sa.status, COALESCE(f.disbursed_amount, 0),
+		o.id, o.period_from, o.period_to, o.due_date, o.amount, o.status
+		FROM subvention_obligations o
+		JOIN subvention_agreements sa ON sa.id = o.agreement_id AND sa.tenant_id = o.tenant_id
+		LEFT JOIN property_projects p ON p.id = sa.project_id
+		LEFT JOIN bank bk ON bk.id = sa.bank_id
+		LEFT JOIN bank_financing f ON f.id = sa.financing_id
+		WHERE o.tenant_id = ? AND o.status <> ?`
+	args := []interface{}{tenantID, models.SubventionObligationCancelled}
+	if projectID != "" {
+		query += " AND sa.project_id = ?"
+		args = append(args, projectID)
+	}
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subvention obligations: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []subventionLiabilityLine{}
+	for rows.Next() {
+		var l subventionLiabilityLine
+		var status string
+		o := &l.Obligation
+		if err := rows.Scan(&l.AgreementID, &l.ProjectID, &l.ProjectName, &l.BankID, &l.BankName, &status,
+			&l.LoanBalance, &o.ID, &o.PeriodFrom, &o.PeriodTo, &o.DueDate, &o.Amount, &o.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan subvention obligation: %w", err)
+		}
+		l.Active = status == models.SubventionActive
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildSubventionLiability(lines, asOf), nil
+}
+
+// ============================================================================
+// SUBVENTION CALCULATIONS
+// ============================================================================
+
+// subventionPeriods splits the scheme into calendar months. The first month runs
+// from the start date and the last ends on the scheme end date.
+func subventionPeriods(start, end time.Time) []subventionPeriod {
+	periods := []subventionPeriod{}
+	for from := start; from.Before(end); {
+		to := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, from.Location())
+		if to.After(end) {
+			to = end
+		}
+		periods = append(periods, subventionPeriod{From: from, To: to})
+		from = to
+	}
+	return periods
+}
+
+// subventionInterest is the pre-EMI on each draw from the later of its credit
+// date and the period start to the period end, on an actual/365 basis
+func subventionInterest(draws []loanDraw, rate float64, from, to time.Time) float64 {
+	interest := 0.0
+	for _, d := range draws {
+		start := laterOf(from, d.Date)
+		if !start.Before(to) {
+			continue
+		}
+		days := math.Round(to.Sub(start).Hours() / 24)
+		interest += d.Amount * rate / 100 * days / 365
+	}
+	return roundTo2(interest)
+}
+
+// loanBalanceAt is the loan drawn before a date
+func loanBalanceAt(draws []loanDraw, at time.Time) float64 {
+	balance := 0.0
+	for _, d := range draws {
+		if d.Date.Before(at) {
+			balance += d.Amount
+		}
+	}
+	return roundTo2(balance)
+}
+
+// capSubvention limits a month's interest to what remains under the agreement's cap
+func capSubvention(amount, maxAmount, provisioned float64) float64 {
+	if maxAmount <= 0 {
+		return amount
+	}
+	return roundTo2(math.Max(math.Min(amount, maxAmount-provisioned), 0))
+}
+
+// subventionStopEvent returns the earlier of possession and cancellation when one
+// has happened by the as-of date
+func subventionStopEvent(possession, cancelled sql.NullTime, asOf time.Time) (string, time.Time, bool) {
+	reason, on, ok := "", time.Time{}, false
+	if possession.Valid && !possession.Time.After(asOf) {
+		reason, on, ok = models.SubventionStopPossession, possession.Time, true
+	}
+	if cancelled.Valid && !cancelled.Time.After(asOf) && (!ok || cancelled.Time.Before(on)) {
+		reason, on, ok = models.SubventionStopCancellation, cancelled.Time, true
+	}
+	return reason, on, ok
+}
+
+// buildSubventionLiability totals obligations by project and lender
+func buildSubventionLiability(lines []subventionLiabilityLine, asOf time.Time) *models.SubventionLiabilityReport {
+	report := &models.SubventionLiabilityReport{AsOf: asOf, Rows: []models.SubventionLiabilityRow{}}
+	index := map[string]int{}
+	counted := map[string]bool{}
+	for _, l := range lines {
+		key := l.ProjectID + "|" + l.BankID
+		i, ok := index[key]
+		if !ok {
+			report.Rows = append(report.Rows, models.SubventionLiabilityRow{
+				ProjectID: l.ProjectID, ProjectName: l.ProjectName, BankID: l.BankID, BankName: l.BankName,
+			})
+			i = len(report.Rows) - 1
+			index[key] = i
+		}
+		row := &report.Rows[i]
+		if l.Active && !counted[l.AgreementID] {
+			counted[l.AgreementID] = true
+			row.Agreements++
+			row.LoanBalance += l.LoanBalance
+		}
+		o := l.Obligation
+		switch o.Status {
+		case models.SubventionObligationScheduled:
+			row.Committed += o.Amount
+		case models.SubventionObligationProvisioned:
+			row.Provisioned += o.Amount
+			row.Payable += o.Amount
+			if o.DueDate.Before(asOf) {
+				row.Overdue += o.Amount
+			}
+		case models.SubventionObligationPaid:
+			row.Provisioned += o.Amount
+			row.Paid += o.Amount
+		}
+	}
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		row.LoanBalance = roundTo2(row.LoanBalance)
+		row.Provisioned = roundTo2(row.Provisioned)
+		row.Paid = roundTo2(row.Paid)
+		row.Payable = roundTo2(row.Payable)
+		row.Overdue = roundTo2(row.Overdue)
+		row.Committed = roundTo2(row.Committed)
+		report.Payable += row.Payable
+		report.Overdue += row.Overdue
+		report.Committed += row.Committed
+	}
+	report.Payable = roundTo2(report.Payable)
+	report.Overdue = roundTo2(report.Overdue)
+	report.Committed = roundTo2(report.Committed)
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		if report.Rows[i].ProjectName != report.Rows[j].ProjectName {
+			return report.Rows[i].ProjectName < report.Rows[j].ProjectName
+		}
+		return report.Rows[i].BankName < report.Rows[j].BankName
+	})
+	return report
+}
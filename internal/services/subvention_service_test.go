@@ -0,0 +1,93 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSubventionSchedule tests monthly periods and pre-EMI on staggered disbursements
+func TestSubventionSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+	periods := subventionPeriods(start, end)
+	assert.Len(t, periods, 4)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), periods[0].To)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), periods[3].From)
+	assert.Equal(t, end, periods[3].To)
+
+	draws := []loanDraw{
+		{Date: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Amount: 3650000},
+		{Date: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), Amount: 1825000},
+	}
+	// January: 17 days on the first draw at 8%
+	assert.Equal(t, 13600.0, subventionInterest(draws, 8, periods[0].From, periods[0].To))
+	// February: 28 days on the first draw and 14 on the second
+	assert.Equal(t, 28000.0, subventionInterest(draws, 8, periods[1].From, periods[1].To))
+	assert.Equal(t, 3650000.0, loanBalanceAt(draws, periods[1].From))
+	assert.Equal(t, 5475000.0, loanBalanceAt(draws, periods[1].To))
+
+	assert.Equal(t, 28000.0, capSubvention(28000, 0, 100000))
+	assert.Equal(t, 5000.0, capSubvention(28000, 50000, 45000))
+	assert.Equal(t, 0.0, capSubvention(28000, 50000, 50000))
+}
+
+// TestSubventionStopEvent tests that the earlier of possession and cancellation stops the scheme
+func TestSubventionStopEvent(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	possession := sql.NullTime{Time: time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), Valid: true}
+	cancelled := sql.NullTime{Time: time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	_, _, ok := subventionStopEvent(sql.NullTime{}, sql.NullTime{}, asOf)
+	assert.False(t, ok)
+
+	reason, on, ok := subventionStopEvent(possession, sql.NullTime{}, asOf)
+	assert.True(t, ok)
+	assert.Equal(t, models.SubventionStopPossession, reason)
+	assert.Equal(t, possession.Time, on)
+
+	reason, on, ok = subventionStopEvent(possession, cancelled, asOf)
+	assert.True(t, ok)
+	assert.Equal(t, models.SubventionStopCancellation, reason)
+	assert.Equal(t, cancelled.Time, on)
+
+	// Possession after the as-of date has not happened yet
+	_, _, ok = subventionStopEvent(sql.NullTime{Time: asOf.AddDate(0, 0, 1), Valid: true}, sql.NullTime{}, asOf)
+	assert.False(t, ok)
+}
+
+// TestBuildSubventionLiability tests the liability totals by project and lender
+func TestBuildSubventionLiability(t *testing.T) {
+	asOf := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	line := func(agreement, project, bank string, active bool, status string, amount float64, due time.Time) subventionLiabilityLine {
+		return subventionLiabilityLine{
+			AgreementID: agreement, ProjectID: project, ProjectName: project, BankID: bank, BankName: bank,
+			Active: active, LoanBalance: 1000000,
+			Obligation: models.SubventionObligation{Status: status, Amount: amount, DueDate: due},
+		}
+	}
+	may := time.Date(2026, 6, 5, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2026, 7, 5, 0, 0, 0, 0, time.UTC)
+	report := buildSubventionLiability([]subventionLiabilityLine{
+		line("a1", "Skyline", "HDFC", true, models.SubventionObligationPaid, 6000, may),
+		line("a1", "Skyline", "HDFC", true, models.SubventionObligationProvisioned, 6500, may),
+		line("a1", "Skyline", "HDFC", true, models.SubventionObligationScheduled, 6500, june),
+		line("a2", "Skyline", "HDFC", false, models.SubventionObligationProvisioned, 3000, june),
+		line("a3", "Meadows", "SBI", true, models.SubventionObligationScheduled, 4000, june),
+	}, asOf)
+
+	assert.Len(t, report.Rows, 2)
+	meadows, skyline := report.Rows[0], report.Rows[1]
+	assert.Equal(t, "Meadows", meadows.ProjectName)
+	assert.Equal(t, 4000.0, meadows.Committed)
+	assert.Equal(t, 1, skyline.Agreements) // the stopped agreement is not counted
+	assert.Equal(t, 15500.0, skyline.Provisioned)
+	assert.Equal(t, 9500.0, skyline.Payable)
+	assert.Equal(t, 6500.0, skyline.Overdue)
+	assert.Equal(t, 9500.0, report.Payable)
+	assert.Equal(t, 10500.0, report.Committed)
+}
@@ -0,0 +1,775 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// TDS ENGINE
+// ============================================================================
+// Section-wise TDS on vendor bills, broker and channel partner commission and
+// property purchases. Every assessed payment or credit lands in tds_ledger,
+// which drives the TDS payable position, challan allocation and the
+// quarterly 26Q / 27Q statements.
+
+// TDSService assesses, records and reports TDS
+type TDSService struct {
+	DB *sql.DB
+}
+
+// NewTDSService creates a new TDS service
+func NewTDSService(db *sql.DB) *TDSService {
+	return &TDSService{DB: db}
+}
+
+// tdsInterestRatePerMonth is section 201(1A) interest on TDS deposited late
+const tdsInterestRatePerMonth = 1.5
+
+// panNotAvailable is what the statements carry for deductees without a PAN
+const panNotAvailable = "PANNOTAVBL"
+
+var panPattern = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+
+// tdsSectionRules are the rates and thresholds from FY 2025-26
+var tdsSectionRules = map[string]models.TDSSectionRule{
+	models.TDSSection194C: {
+		Section: models.TDSSection194C, Description: "Payment to contractors and sub-contractors",
+		RateIndividual: 1, RateOthers: 2, NoPANRate: 20,
+		SingleThreshold: 30000, AggregateThreshold: 100000,
+	},
+	models.TDSSection194H: {
+		Section: models.TDSSection194H, Description: "Commission or brokerage",
+		RateIndividual: 2, RateOthers: 2, NoPANRate: 20,
+		AggregateThreshold: 20000,
+	},
+	models.TDSSection194J: {
+		Section: models.TDSSection194J, Description: "Fees for professional services",
+		RateIndividual: 10, RateOthers: 10, NoPANRate: 20,
+		AggregateThreshold: 50000,
+	},
+	models.TDSSection194IA: {
+		Section: models.TDSSection194IA, Description: "Purchase of immovable property (other than agricultural land)",
+		RateIndividual: 1, RateOthers: 1, NoPANRate: 20,
+		ConsiderationThreshold: 5000000,
+	},
+}
+
+// GetSectionRules lists the sections the engine deducts under
+func (s *TDSService) GetSectionRules() []models.TDSSectionRule {
+	rules := make([]models.TDSSectionRule, 0, len(tdsSectionRules))
+	for _, rule := range tdsSectionRules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Section < rules[j].Section })
+	return rules
+}
+
+// ComputeTDS previews the deduction on a payment without recording it
+func (s *TDSService) ComputeTDS(req *models.ComputeTDSRequest) (*models.TDSComputation, error) {
+	rule, ok := tdsSectionRules[normalizeTDSSection(req.Section)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TDS section %q", req.Section)
+	}
+	comp := computeTDS(rule, strings.ToUpper(req.PAN), req.RateOverride, req.Amount,
+		req.AggregatePaid, req.AggregateUndeducted, req.TotalConsideration)
+	return &comp, nil
+}
+
+// ============================================================================
+// DEDUCTION
+// ============================================================================
+
+// Deduct assesses a payment or credit and records it in the TDS ledger. A source
+// is assessed once; calling again returns the existing entry. Payments below
+// the aggregate threshold are recorded with no TDS so that the year's total is
+// known, and are caught up when a later payment crosses the threshold.
+func (s *TDSService) Deduct(tenantID string, in *models.TDSDeductionInput) (*models.TDSLedgerEntry, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry, err := s.DeductTx(tx, tenantID, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tds ledger entry: %w", err)
+	}
+	return entry, nil
+}
+
+// DeductTx is Deduct inside the caller's transaction, so the deduction is only
+// recorded if the payment it is taken from is saved
+func (s *TDSService) DeductTx(tx *sql.Tx, tenantID string, in *models.TDSDeductionInput) (*models.TDSLedgerEntry, error) {
+	if existing, err := getLedgerEntryBySource(tx, tenantID, in.SourceType, in.SourceID); err == nil {
+		return existing, nil
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	section := normalizeTDSSection(in.Section)
+	rule, ok := tdsSectionRules[section]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TDS section %q", in.Section)
+	}
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	fy := gstFinancialYear(in.TransactionDate)
+	paid, undeducted, err := getPayeeAggregate(tx, tenantID, in.PayeeType, in.PayeeID, section, fy)
+	if err != nil {
+		return nil, err
+	}
+	comp := computeTDS(rule, strings.ToUpper(strings.TrimSpace(in.PAN)), in.RateOverride, in.Amount, paid, undeducted, in.TotalConsideration)
+	return s.RecordDeductionTx(tx, tenantID, in, comp)
+}
+
+// RecordDeduction writes an already computed deduction to the TDS ledger
+func (s *TDSService) RecordDeduction(tenantID string, in *models.TDSDeductionInput, comp models.TDSComputation) (*models.TDSLedgerEntry, error) {
+	entry := newTDSLedgerEntry(tenantID, in, comp)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordTDSLedgerEntry(tx, entry, in, comp); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tds ledger entry: %w", err)
+	}
+	return entry, nil
+}
+
+// RecordDeductionTx is RecordDeduction inside the caller's transaction, so the
+// ledger entry commits or rolls back with the payment it belongs to
+func (s *TDSService) RecordDeductionTx(tx *sql.Tx, tenantID string, in *models.TDSDeductionInput, comp models.TDSComputation) (*models.TDSLedgerEntry, error) {
+	entry := newTDSLedgerEntry(tenantID, in, comp)
+	if err := recordTDSLedgerEntry(tx, entry, in, comp); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func recordTDSLedgerEntry(tx *sql.Tx, entry *models.TDSLedgerEntry, in *models.TDSDeductionInput, comp models.TDSComputation) error {
+	_, err := tx.Exec(`INSERT INTO tds_ledger (
+		id, tenant_id, financial_year, quarter, form, section_code, source_type, source_id, payee_type, payee_id,
+		payee_name, pan, transaction_date, amount, rate, base_amount, tds_amount, pan_missing, deposit_due_date,
+		status, deduction_id, created_by, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.TenantID, entry.FinancialYear, entry.Quarter, entry.Form, entry.SectionCode,
+		entry.SourceType, entry.SourceID, entry.PayeeType, entry.PayeeID, entry.PayeeName, nullIfEmpty(entry.PAN),
+		entry.TransactionDate, entry.Amount, entry.Rate, entry.BaseAmount, entry.TDSAmount, entry.PANMissing,
+		entry.DepositDueDate, entry.Status, nullIfEmpty(in.DeductionID), nullIfEmpty(entry.CreatedBy), entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record tds ledger entry: %w", err)
+	}
+
+	// Earlier payments below the threshold are now covered by this deduction
+	if !comp.BelowThreshold && comp.BaseAmount > entry.Amount {
+		if _, err := tx.Exec(`UPDATE tds_ledger SET status = ?
+			WHERE tenant_id = ? AND payee_type = ? AND payee_id = ? AND section_code = ? AND financial_year = ? AND status = ?`,
+			models.TDSStatusCaughtUp, entry.TenantID, in.PayeeType, in.PayeeID, entry.SectionCode, entry.FinancialYear, models.TDSStatusBelowThreshold); err != nil {
+			return fmt.Errorf("failed to update earlier tds ledger entries: %w", err)
+		}
+	}
+	return nil
+}
+
+func newTDSLedgerEntry(tenantID string, in *models.TDSDeductionInput, comp models.TDSComputation) *models.TDSLedgerEntry {
+	section := normalizeTDSSection(in.Section)
+	fy := gstFinancialYear(in.TransactionDate)
+	entry := &models.TDSLedgerEntry{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		FinancialYear:   fy,
+		Quarter:         tdsQuarter(in.TransactionDate),
+		Form:            tdsFormFor(section, in.NonResident),
+		SectionCode:     section,
+		SourceType:      in.SourceType,
+		SourceID:        in.SourceID,
+		PayeeType:       in.PayeeType,
+		PayeeID:         in.PayeeID,
+		PayeeName:       in.PayeeName,
+		PAN:             strings.ToUpper(strings.TrimSpace(in.PAN)),
+		TransactionDate: in.TransactionDate,
+		Amount:          roundTo2(in.Amount),
+		Rate:            comp.Rate,
+		BaseAmount:      comp.BaseAmount,
+		TDSAmount:       comp.TDSAmount,
+		PANMissing:      comp.PANMissing,
+		Status:          models.TDSStatusPendingDeposit,
+		CreatedBy:       in.CreatedBy,
+		CreatedAt:       time.Now(),
+	}
+	if in.DeductionID != "" {
+		entry.DeductionID = &in.DeductionID
+	}
+	if comp.BelowThreshold {
+		entry.Status = models.TDSStatusBelowThreshold
+	} else {
+		due := tdsDepositDueDate(in.TransactionDate)
+		entry.DepositDueDate = &due
+	}
+	return entry
+}
+
+// RecordPropertyPurchaseTDS deducts 194-IA on an instalment paid to a resident seller
+func (s *TDSService) RecordPropertyPurchaseTDS(tenantID, userID string, req *models.RecordPropertyPurchaseTDSRequest) (*models.TDSLedgerEntry, error) {
+	paymentDate, err := time.Parse("2006-01-02", req.PaymentDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment_date: %w", err)
+	}
+	if req.PaymentAmount > req.TotalConsideration {
+		return nil, fmt.Errorf("payment amount exceeds total consideration")
+	}
+
+	return s.Deduct(tenantID, &models.TDSDeductionInput{
+		SourceType:         models.TDSSourcePropertyPurchase,
+		SourceID:           req.PaymentReference,
+		PayeeType:          models.TDSPayeePropertySeller,
+		PayeeID:            strings.ToUpper(req.SellerPAN),
+		PayeeName:          req.SellerName,
+		PAN:                req.SellerPAN,
+		Section:            models.TDSSection194IA,
+		Amount:             req.PaymentAmount,
+		TotalConsideration: req.TotalConsideration,
+		TransactionDate:    paymentDate,
+		CreatedBy:          userID,
+	})
+}
+
+func getPayeeAggregate(q sqlRowQuerier, tenantID, payeeType, payeeID, section, fy string) (float64, float64, error) {
+	var paid, undeducted float64
+	err := q.QueryRow(`SELECT COALESCE(SUM(amount), 0),
+		COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0)
+		FROM tds_ledger WHERE tenant_id = ? AND payee_type = ? AND payee_id = ? AND section_code = ? AND financial_year = ?`,
+		models.TDSStatusBelowThreshold, tenantID, payeeType, payeeID, section, fy).Scan(&paid, &undeducted)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch payee tds aggregate: %w", err)
+	}
+	return paid, undeducted, nil
+}
+
+// ============================================================================
+// CHALLANS
+// ============================================================================
+
+// CreateChallan records an ITNS 281 deposit and marks the covered ledger entries deposited
+func (s *TDSService) CreateChallan(tenantID, userID string, req *models.CreateTDSChallanRequest) (*models.TDSChallan, error) {
+	depositDate, err := time.Parse("2006-01-02", req.DepositDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deposit_date: %w", err)
+	}
+	if len(req.EntryIDs) == 0 {
+		return nil, fmt.Errorf("at least one ledger entry is required")
+	}
+
+	challan := &models.TDSChallan{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		ChallanSerialNo: req.ChallanSerialNo,
+		BSRCode:         req.BSRCode,
+		DepositDate:     depositDate,
+		InterestPaid:    req.InterestPaid,
+		FeePaid:         req.FeePaid,
+		EntryCount:      len(req.EntryIDs),
+		CreatedBy:       userID,
+		CreatedAt:       time.Now(),
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range req.EntryIDs {
+		var status string
+		var amount float64
+		var transactionDate time.Time
+		var deductionID sql.NullString
+		err := tx.QueryRow(`SELECT status, tds_amount, transaction_date, deduction_id FROM tds_ledger
+			WHERE id = ? AND tenant_id = ? FOR UPDATE`, id, tenantID).Scan(&status, &amount, &transactionDate, &deductionID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tds ledger entry %s not found", id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tds ledger entry: %w", err)
+		}
+		if status != models.TDSStatusPendingDeposit {
+			return nil, fmt.Errorf("tds ledger entry %s is %s", id, status)
+		}
+
+		challan.TDSAmount += amount
+		challan.InterestDue += tdsLateInterest(amount, transactionDate, depositDate)
+
+		if _, err := tx.Exec(`UPDATE tds_ledger SET status = ?, challan_id = ? WHERE id = ? AND tenant_id = ?`,
+			models.TDSStatusDeposited, challan.ID, id, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to update tds ledger entry: %w", err)
+		}
+		if deductionID.Valid {
+			if _, err := tx.Exec(`UPDATE tds_deductions SET status = 'deposited', challan_id = ? WHERE id = ? AND tenant_id = ?`,
+				challan.ID, deductionID.String, tenantID); err != nil {
+				return nil, fmt.Errorf("failed to update tds deduction: %w", err)
+			}
+		}
+	}
+	challan.TDSAmount = roundTo2(challan.TDSAmount)
+	challan.InterestDue = roundTo2(challan.InterestDue)
+	challan.TotalAmount = roundTo2(challan.TDSAmount + challan.InterestPaid + challan.FeePaid)
+
+	_, err = tx.Exec(`INSERT INTO tds_challans (
+		id, tenant_id, challan_serial_no, bsr_code, deposit_date, tds_amount, interest_due, interest_paid,
+		fee_paid, total_amount, entry_count, created_by, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		challan.ID, challan.TenantID, challan.ChallanSerialNo, challan.BSRCode, challan.DepositDate,
+		challan.TDSAmount, challan.InterestDue, challan.InterestPaid, challan.FeePaid, challan.TotalAmount,
+		challan.EntryCount, nullIfEmpty(challan.CreatedBy), challan.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tds challan: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tds challan: %w", err)
+	}
+	return challan, nil
+}
+
+// ListChallans lists TDS deposits, newest first
+func (s *TDSService) ListChallans(tenantID string, from, to time.Time) ([]models.TDSChallan, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, challan_serial_no, bsr_code, deposit_date, tds_amount, interest_due,
+		interest_paid, fee_paid, total_amount, entry_count, COALESCE(created_by, ''), created_at
+		FROM tds_challans WHERE tenant_id = ? AND deposit_date >= ? AND deposit_date < ?
+		ORDER BY deposit_date DESC`, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tds challans: %w", err)
+	}
+	defer rows.Close()
+
+	challans := []models.TDSChallan{}
+	for rows.Next() {
+		var c models.TDSChallan
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.ChallanSerialNo, &c.BSRCode, &c.DepositDate, &c.TDSAmount,
+			&c.InterestDue, &c.InterestPaid, &c.FeePaid, &c.TotalAmount, &c.EntryCount, &c.CreatedBy, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tds challan: %w", err)
+		}
+		challans = append(challans, c)
+	}
+	return challans, rows.Err()
+}
+
+// ============================================================================
+// LEDGER AND STATEMENTS
+// ============================================================================
+
+// GetPayableLedger returns the year's TDS ledger with deducted, deposited and overdue totals per section
+func (s *TDSService) GetPayableLedger(tenantID, fy string, asOf time.Time) (*models.TDSPayableLedger, error) {
+	entries, err := s.getLedgerEntries(tenantID, "financial_year = ?", fy)
+	if err != nil {
+		return nil, err
+	}
+	return buildTDSPayableLedger(fy, asOf, entries), nil
+}
+
+// GenerateReturn builds the quarterly 26Q or 27Q statement data
+func (s *TDSService) GenerateReturn(tenantID, form, fy, quarter string) (*models.TDSReturnData, error) {
+	form = strings.ToUpper(form)
+	if form != models.TDSForm26Q && form != models.TDSForm27Q {
+		return nil, fmt.Errorf("form must be 26Q or 27Q")
+	}
+	if _, _, err := ParseTDSQuarter(fy, quarter); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.getLedgerEntries(tenantID, "financial_year = ? AND quarter = ? AND form = ? AND tds_amount > 0",
+		fy, strings.ToUpper(quarter), form)
+	if err != nil {
+		return nil, err
+	}
+	challans, err := s.getChallansByID(tenantID, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var deductorPAN sql.NullString
+	err = s.DB.QueryRow(`SELECT it_pan FROM tax_configuration
+		WHERE tenant_id = ? AND is_active = TRUE AND deleted_at IS NULL
+		ORDER BY created_at DESC LIMIT 1`, tenantID).Scan(&deductorPAN)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get deductor PAN: %w", err)
+	}
+
+	return buildTDSReturn(form, fy, strings.ToUpper(quarter), strings.ToUpper(deductorPAN.String), entries, challans), nil
+}
+
+// ExportReturnExcel writes the statement's challan and deductee details to a workbook
+func (s *TDSService) ExportReturnExcel(data *models.TDSReturnData) ([]byte, error) {
+	f := newGSTWorkbook("Challans")
+	defer f.Close()
+
+	var challanRows [][]interface{}
+	for _, c := range data.Challans {
+		challanRows = append(challanRows, []interface{}{c.SerialNo, c.BSRCode, c.DepositDate, c.ChallanSerialNo,
+			c.TDSAmount, c.Interest, c.Fee, c.Total, c.DeducteeCount})
+	}
+	if err := writeGSTSheet(f, "Challans", []string{"Sr No", "BSR Code", "Date of Deposit", "Challan Serial No",
+		"TDS", "Interest", "Fee", "Total", "Deductees"}, challanRows); err != nil {
+		return nil, err
+	}
+
+	var deducteeRows [][]interface{}
+	for _, d := range data.Deductees {
+		deducteeRows = append(deducteeRows, []interface{}{d.ChallanSerialNo, d.Section, d.PAN, d.DeducteeName,
+			d.PaymentDate, d.AmountPaid, d.Rate, d.TDSDeducted, d.TDSDeposited, d.DeductionDate, d.ReasonCode})
+	}
+	if err := writeGSTSheet(f, "Deductees", []string{"Challan Sr No", "Section", "PAN", "Deductee Name",
+		"Date of Payment/Credit", "Amount Paid/Credited", "Rate", "TDS Deducted", "TDS Deposited",
+		"Date of Deduction", "Reason"}, deducteeRows); err != nil {
+		return nil, err
+	}
+
+	var issueRows [][]interface{}
+	for _, issue := range data.Issues {
+		issueRows = append(issueRows, []interface{}{issue.Severity, issue.Payee, issue.Message})
+	}
+	if err := writeGSTSheet(f, "Issues", []string{"Severity", "Payee", "Message"}, issueRows); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+const tdsLedgerColumns = `id, tenant_id, financial_year, quarter, form, section_code, source_type, source_id,
+	payee_type, payee_id, COALESCE(payee_name, ''), COALESCE(pan, ''), transaction_date, amount, rate,
+	base_amount, tds_amount, pan_missing, deposit_due_date, status, challan_id, deduction_id,
+	COALESCE(created_by, ''), created_at`
+
+func scanTDSLedgerEntry(scanner interface{ Scan(...interface{}) error }) (*models.TDSLedgerEntry, error) {
+	var e models.TDSLedgerEntry
+	var dueDate sql.NullTime
+	var challanID, deductionID sql.NullString
+	if err := scanner.Scan(&e.ID, &e.TenantID, &e.FinancialYear, &e.Quarter, &e.Form, &e.SectionCode,
+		&e.SourceType, &e.SourceID, &e.PayeeType, &e.PayeeID, &e.PayeeName, &e.PAN, &e.TransactionDate,
+		&e.Amount, &e.Rate, &e.BaseAmount, &e.TDSAmount, &e.PANMissing, &dueDate, &e.Status, &challanID,
+		&deductionID, &e.CreatedBy, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if dueDate.Valid {
+		e.DepositDueDate = &dueDate.Time
+	}
+	if challanID.Valid {
+		e.ChallanID = &challanID.String
+	}
+	if deductionID.Valid {
+		e.DeductionID = &deductionID.String
+	}
+	return &e, nil
+}
+
+func getLedgerEntryBySource(q sqlRowQuerier, tenantID, sourceType, sourceID string) (*models.TDSLedgerEntry, error) {
+	row := q.QueryRow(`SELECT `+tdsLedgerColumns+` FROM tds_ledger
+		WHERE tenant_id = ? AND source_type = ? AND source_id = ?`, tenantID, sourceType, sourceID)
+	entry, err := scanTDSLedgerEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tds ledger entry: %w", err)
+	}
+	return entry, nil
+}
+
+func (s *TDSService) getLedgerEntries(tenantID, where string, args ...interface{}) ([]models.TDSLedgerEntry, error) {
+	rows, err := s.DB.Query(`SELECT `+tdsLedgerColumns+` FROM tds_ledger WHERE tenant_id = ? AND `+where+`
+		ORDER BY transaction_date, created_at`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tds ledger: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.TDSLedgerEntry{}
+	for rows.Next() {
+		entry, err := scanTDSLedgerEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tds ledger entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+func (s *TDSService) getChallansByID(tenantID string, entries []models.TDSLedgerEntry) (map[string]models.TDSChallan, error) {
+	challans := map[string]models.TDSChallan{}
+	for _, e := range entries {
+		if e.ChallanID == nil {
+			continue
+		}
+		if _, ok := challans[*e.ChallanID]; ok {
+			continue
+		}
+		var c models.TDSChallan
+		err := s.DB.QueryRow(`SELECT id, tenant_id, challan_serial_no, bsr_code, deposit_date, tds_amount, interest_due,
+			interest_paid, fee_paid, total_amount, entry_count, COALESCE(created_by, ''), created_at
+			FROM tds_challans WHERE id = ? AND tenant_id = ?`, *e.ChallanID, tenantID).Scan(
+			&c.ID, &c.TenantID, &c.ChallanSerialNo, &c.BSRCode, &c.DepositDate, &c.TDSAmount, &c.InterestDue,
+			&c.InterestPaid, &c.FeePaid, &c.TotalAmount, &c.EntryCount, &c.CreatedBy, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tds challan: %w", err)
+		}
+		challans[c.ID] = c
+	}
+	return challans, nil
+}
+
+// ============================================================================
+// RULES
+// ============================================================================
+
+func normalizeTDSSection(section string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(section))
+}
+
+// tdsPayeeRate picks the individual / HUF or other rate from the PAN's fourth character
+func tdsPayeeRate(rule models.TDSSectionRule, pan string) float64 {
+	if len(pan) == 10 && (pan[3] == 'P' || pan[3] == 'H') {
+		return rule.RateIndividual
+	}
+	return rule.RateOthers
+}
+
+// computeTDS applies a section's thresholds and rates. aggregatePaid is what was
+// paid to the payee earlier in the year; aggregateUndeducted is the part of it on
+// which nothing was deducted, caught up once the aggregate threshold is crossed.
+func computeTDS(rule models.TDSSectionRule, pan string, rateOverride, amount, aggregatePaid, aggregateUndeducted, consideration float64) models.TDSComputation {
+	comp := models.TDSComputation{Section: rule.Section}
+
+	rate := tdsPayeeRate(rule, pan)
+	if rateOverride > 0 {
+		rate = rateOverride
+	}
+	if !panPattern.MatchString(pan) {
+		comp.PANMissing = true
+		rate = math.Max(rule.NoPANRate, 2*rate)
+		comp.Note = "PAN not furnished, deducted at the section 206AA rate"
+	}
+	comp.Rate = rate
+
+	switch {
+	case rule.ConsiderationThreshold > 0:
+		if consideration < rule.ConsiderationThreshold {
+			comp.BelowThreshold = true
+			comp.Note = fmt.Sprintf("consideration below %.0f", rule.ConsiderationThreshold)
+			return comp
+		}
+		comp.BaseAmount = amount
+	case rule.SingleThreshold > 0 && amount > rule.SingleThreshold:
+		comp.BaseAmount = amount
+	case rule.AggregateThreshold > 0 && aggregatePaid+amount > rule.AggregateThreshold:
+		comp.BaseAmount = amount + aggregateUndeducted
+		if aggregateUndeducted > 0 {
+			comp.Note = fmt.Sprintf("aggregate for the year crossed %.0f, earlier payments included", rule.AggregateThreshold)
+		}
+	default:
+		comp.BelowThreshold = true
+		comp.Note = "below threshold"
+		return comp
+	}
+
+	comp.BaseAmount = roundTo2(comp.BaseAmount)
+	comp.TDSAmount = roundTo2(comp.BaseAmount * rate / 100)
+	return comp
+}
+
+// tdsFormFor is the statement a deduction is reported in
+func tdsFormFor(section string, nonResident bool) string {
+	if section == models.TDSSection194IA {
+		return models.TDSForm26QB
+	}
+	if nonResident {
+		return models.TDSForm27Q
+	}
+	return models.TDSForm26Q
+}
+
+// tdsQuarter is the financial year quarter (Q1 = April-June) of a date
+func tdsQuarter(d time.Time) string {
+	return fmt.Sprintf("Q%d", (int(d.Month())+8)%12/3+1)
+}
+
+// FinancialYearOf is the April-March financial year ("2025-26") of a date
+func FinancialYearOf(d time.Time) string {
+	return gstFinancialYear(d)
+}
+
+// ParseTDSQuarter parses a financial year ("2025-26") and quarter into [from, to)
+func ParseTDSQuarter(fy, quarter string) (time.Time, time.Time, error) {
+	var startYear, endYear int
+	if _, err := fmt.Sscanf(fy, "%d-%d", &startYear, &endYear); err != nil || (startYear+1)%100 != endYear {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q, expected YYYY-YY", fy)
+	}
+	var q int
+	if _, err := fmt.Sscanf(strings.ToUpper(quarter), "Q%d", &q); err != nil || q < 1 || q > 4 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid quarter %q, expected Q1-Q4", quarter)
+	}
+	from := time.Date(startYear, time.April, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 3*(q-1), 0)
+	return from, from.AddDate(0, 3, 0), nil
+}
+
+// tdsDepositDueDate is the 7th of the following month, or 30 April for March deductions
+func tdsDepositDueDate(deducted time.Time) time.Time {
+	if deducted.Month() == time.March {
+		return time.Date(deducted.Year(), time.April, 30, 0, 0, 0, 0, deducted.Location())
+	}
+	return time.Date(deducted.Year(), deducted.Month()+1, 7, 0, 0, 0, 0, deducted.Location())
+}
+
+// tdsLateInterest is 1.5% a month, part of a month counted as a month, from the
+// date of deduction when the deposit misses the due date
+func tdsLateInterest(amount float64, deducted, deposited time.Time) float64 {
+	if !deposited.After(tdsDepositDueDate(deducted)) {
+		return 0
+	}
+	months := (deposited.Year()-deducted.Year())*12 + int(deposited.Month()) - int(deducted.Month()) + 1
+	return roundTo2(amount * tdsInterestRatePerMonth / 100 * float64(months))
+}
+
+// panFromGSTIN extracts the PAN embedded in characters 3-12 of a GSTIN
+func panFromGSTIN(gstin string) string {
+	gstin = strings.ToUpper(strings.TrimSpace(gstin))
+	if !gstinPattern.MatchString(gstin) {
+		return ""
+	}
+	return gstin[2:12]
+}
+
+// ============================================================================
+// REPORT BUILDERS
+// ============================================================================
+
+func buildTDSPayableLedger(fy string, asOf time.Time, entries []models.TDSLedgerEntry) *models.TDSPayableLedger {
+	ledger := &models.TDSPayableLedger{FinancialYear: fy, AsOf: asOf, Sections: []models.TDSPayableSection{}, Entries: entries}
+
+	bySection := map[string]*models.TDSPayableSection{}
+	for _, e := range entries {
+		if e.TDSAmount <= 0 {
+			continue
+		}
+		sec, ok := bySection[e.SectionCode]
+		if !ok {
+			sec = &models.TDSPayableSection{SectionCode: e.SectionCode}
+			bySection[e.SectionCode] = sec
+		}
+		sec.Deducted += e.TDSAmount
+		if e.Status == models.TDSStatusDeposited {
+			sec.Deposited += e.TDSAmount
+		} else if e.DepositDueDate != nil && asOf.After(*e.DepositDueDate) {
+			sec.Overdue += e.TDSAmount
+		}
+	}
+	for _, sec := range bySection {
+		sec.Deducted = roundTo2(sec.Deducted)
+		sec.Deposited = roundTo2(sec.Deposited)
+		sec.Outstanding = roundTo2(sec.Deducted - sec.Deposited)
+		sec.Overdue = roundTo2(sec.Overdue)
+		ledger.Sections = append(ledger.Sections, *sec)
+	}
+	sort.Slice(ledger.Sections, func(i, j int) bool { return ledger.Sections[i].SectionCode < ledger.Sections[j].SectionCode })
+	return ledger
+}
+
+// buildTDSReturn lays out the deductees of a quarter under their challans
+func buildTDSReturn(form, fy, quarter, deductorPAN string, entries []models.TDSLedgerEntry, challans map[string]models.TDSChallan) *models.TDSReturnData {
+	data := &models.TDSReturnData{
+		Form:          form,
+		FinancialYear: fy,
+		Quarter:       quarter,
+		DeductorPAN:   deductorPAN,
+		Challans:      []models.TDSReturnChallan{},
+		Deductees:     []models.TDSReturnDeductee{},
+		Issues:        []models.TDSReturnIssue{},
+	}
+	if !panPattern.MatchString(deductorPAN) {
+		data.Issues = append(data.Issues, models.TDSReturnIssue{Severity: models.GSTIssueError, Message: "deductor PAN is not configured in tax configuration"})
+	}
+
+	// Challans are numbered by deposit date
+	var ordered []models.TDSChallan
+	for _, c := range challans {
+		ordered = append(ordered, c)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if !ordered[i].DepositDate.Equal(ordered[j].DepositDate) {
+			return ordered[i].DepositDate.Before(ordered[j].DepositDate)
+		}
+		return ordered[i].ChallanSerialNo < ordered[j].ChallanSerialNo
+	})
+	serial := map[string]int{}
+	for i, c := range ordered {
+		serial[c.ID] = i + 1
+		data.Challans = append(data.Challans, models.TDSReturnChallan{
+			SerialNo:        i + 1,
+			ChallanSerialNo: c.ChallanSerialNo,
+			BSRCode:         c.BSRCode,
+			DepositDate:     c.DepositDate.Format("02/01/2006"),
+			TDSAmount:       c.TDSAmount,
+			Interest:        c.InterestPaid,
+			Fee:             c.FeePaid,
+			Total:           c.TotalAmount,
+		})
+	}
+
+	for _, e := range entries {
+		d := models.TDSReturnDeductee{
+			Section:       e.SectionCode,
+			PAN:           e.PAN,
+			DeducteeName:  e.PayeeName,
+			PaymentDate:   e.TransactionDate.Format("02/01/2006"),
+			AmountPaid:    e.BaseAmount,
+			Rate:          e.Rate,
+			TDSDeducted:   e.TDSAmount,
+			DeductionDate: e.TransactionDate.Format("02/01/2006"),
+			SourceType:    e.SourceType,
+			SourceID:      e.SourceID,
+		}
+		if e.PANMissing {
+			d.PAN = panNotAvailable
+			d.ReasonCode = "C"
+			data.Issues = append(data.Issues, models.TDSReturnIssue{Severity: models.GSTIssueWarning, Payee: e.PayeeName,
+				Message: "PAN not available, deducted at higher rate"})
+		}
+		if e.ChallanID != nil {
+			d.ChallanSerialNo = serial[*e.ChallanID]
+			d.TDSDeposited = e.TDSAmount
+			data.Challans[d.ChallanSerialNo-1].DeducteeCount++
+		} else {
+			data.Issues = append(data.Issues, models.TDSReturnIssue{Severity: models.GSTIssueError, Payee: e.PayeeName,
+				Message: fmt.Sprintf("TDS of %.2f u/s %s dated %s is not yet deposited", e.TDSAmount, e.SectionCode, d.DeductionDate)})
+		}
+		data.Deductees = append(data.Deductees, d)
+		data.TotalPaid += e.BaseAmount
+		data.TotalTDS += e.TDSAmount
+	}
+	data.TotalPaid = roundTo2(data.TotalPaid)
+	data.TotalTDS = roundTo2(data.TotalTDS)
+	return data
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComputeTDS tests section thresholds, payee rates and the no-PAN rate
+func TestComputeTDS(t *testing.T) {
+	c := tdsSectionRules[models.TDSSection194C]
+
+	// Single contract below 30,000 and year below 1,00,000
+	comp := computeTDS(c, "ABCPK1234L", 0, 25000, 50000, 50000, 0)
+	assert.True(t, comp.BelowThreshold)
+	assert.Equal(t, 0.0, comp.TDSAmount)
+
+	// Single contract above 30,000: individual 1%, company 2%
+	comp = computeTDS(c, "ABCPK1234L", 0, 40000, 0, 0, 0)
+	assert.Equal(t, 400.0, comp.TDSAmount)
+	comp = computeTDS(c, "ABCCK1234L", 0, 40000, 0, 0, 0)
+	assert.Equal(t, 2.0, comp.Rate)
+	assert.Equal(t, 800.0, comp.TDSAmount)
+
+	// Aggregate crosses 1,00,000: earlier undeducted payments are caught up
+	comp = computeTDS(c, "ABCCK1234L", 0, 25000, 90000, 90000, 0)
+	assert.Equal(t, 115000.0, comp.BaseAmount)
+	assert.Equal(t, 2300.0, comp.TDSAmount)
+
+	// No PAN: 20%
+	comp = computeTDS(c, "", 0, 40000, 0, 0, 0)
+	assert.True(t, comp.PANMissing)
+	assert.Equal(t, 8000.0, comp.TDSAmount)
+
+	// Vendor rate override, e.g. a lower deduction certificate
+	comp = computeTDS(tdsSectionRules[models.TDSSection194J], "ABCCK1234L", 2, 60000, 0, 0, 0)
+	assert.Equal(t, 1200.0, comp.TDSAmount)
+
+	// 194H at 2% once commission for the year crosses 20,000
+	h := tdsSectionRules[models.TDSSection194H]
+	assert.True(t, computeTDS(h, "ABCPK1234L", 0, 15000, 0, 0, 0).BelowThreshold)
+	assert.Equal(t, 500.0, computeTDS(h, "ABCPK1234L", 0, 10000, 15000, 15000, 0).TDSAmount)
+
+	// 194-IA: 1% of each instalment once consideration is 50 lakh or more
+	ia := tdsSectionRules[models.TDSSection194IA]
+	assert.True(t, computeTDS(ia, "ABCPK1234L", 0, 1000000, 0, 0, 4999999).BelowThreshold)
+	assert.Equal(t, 10000.0, computeTDS(ia, "ABCPK1234L", 0, 1000000, 0, 0, 5000000).TDSAmount)
+	assert.Equal(t, 200000.0, computeTDS(ia, "", 0, 1000000, 0, 0, 8000000).TDSAmount)
+}
+
+// TestTDSDates tests quarters, deposit due dates and late deposit interest
+func TestTDSDates(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, "Q1", tdsQuarter(date(2025, 4, 1)))
+	assert.Equal(t, "Q2", tdsQuarter(date(2025, 9, 30)))
+	assert.Equal(t, "Q3", tdsQuarter(date(2025, 12, 31)))
+	assert.Equal(t, "Q4", tdsQuarter(date(2026, 3, 15)))
+
+	from, to, err := ParseTDSQuarter("2025-26", "q4")
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 1), from)
+	assert.Equal(t, date(2026, 4, 1), to)
+	_, _, err = ParseTDSQuarter("2025-27", "Q1")
+	assert.Error(t, err)
+
+	assert.Equal(t, date(2025, 5, 7), tdsDepositDueDate(date(2025, 4, 10)))
+	assert.Equal(t, date(2026, 4, 30), tdsDepositDueDate(date(2026, 3, 20)))
+	assert.Equal(t, date(2026, 1, 7), tdsDepositDueDate(date(2025, 12, 5)))
+
+	assert.Equal(t, 0.0, tdsLateInterest(10000, date(2025, 4, 10), date(2025, 5, 7)))
+	// April and May: two months at 1.5%
+	assert.Equal(t, 300.0, tdsLateInterest(10000, date(2025, 4, 10), date(2025, 5, 8)))
+
+	assert.Equal(t, "AAACV1234F", panFromGSTIN("27AAACV1234F1Z5"))
+	assert.Equal(t, "", panFromGSTIN("NOT-A-GSTIN"))
+}
+
+// TestBuildTDSReturn tests deductee and challan layout of a quarterly statement
+func TestBuildTDSReturn(t *testing.T) {
+	challanID := "ch-1"
+	entries := []models.TDSLedgerEntry{
+		{SectionCode: "194C", PayeeName: "Builders Co", PAN: "ABCCK1234L", TransactionDate: time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC),
+			BaseAmount: 40000, Rate: 2, TDSAmount: 800, ChallanID: &challanID},
+		{SectionCode: "194H", PayeeName: "Broker", TransactionDate: time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+			BaseAmount: 25000, Rate: 20, TDSAmount: 5000, PANMissing: true},
+	}
+	challans := map[string]models.TDSChallan{
+		challanID: {ID: challanID, ChallanSerialNo: "00012", BSRCode: "0510002", DepositDate: time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC),
+			TDSAmount: 800, TotalAmount: 800},
+	}
+
+	data := buildTDSReturn(models.TDSForm26Q, "2025-26", "Q1", "AAACV1234F", entries, challans)
+	assert.Len(t, data.Challans, 1)
+	assert.Equal(t, "05/05/2025", data.Challans[0].DepositDate)
+	assert.Equal(t, 1, data.Challans[0].DeducteeCount)
+	assert.Len(t, data.Deductees, 2)
+	assert.Equal(t, 1, data.Deductees[0].ChallanSerialNo)
+	assert.Equal(t, panNotAvailable, data.Deductees[1].PAN)
+	assert.Equal(t, "C", data.Deductees[1].ReasonCode)
+	assert.Equal(t, 5800.0, data.TotalTDS)
+	// one undeposited error plus one missing PAN warning
+	assert.Len(t, data.Issues, 2)
+
+	overdue := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	ledger := buildTDSPayableLedger("2025-26", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), []models.TDSLedgerEntry{
+		{SectionCode: "194C", TDSAmount: 800, Status: models.TDSStatusDeposited},
+		{SectionCode: "194C", TDSAmount: 300, Status: models.TDSStatusPendingDeposit, DepositDueDate: &overdue},
+		{SectionCode: "194H", TDSAmount: 0, Status: models.TDSStatusBelowThreshold},
+	})
+	assert.Len(t, ledger.Sections, 1)
+	assert.Equal(t, 1100.0, ledger.Sections[0].Deducted)
+	assert.Equal(t, 300.0, ledger.Sections[0].Outstanding)
+	assert.Equal(t, 300.0, ledger.Sections[0].Overdue)
+}
@@ -0,0 +1,832 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+)
+
+// ============================================
+// Chain of Title
+// ============================================
+// Parcels are identified by survey number and village. Each parcel carries its
+// registered deeds (with transferor/transferee parties) and recorded
+// encumbrances. AnalyzeTitleChain traces the deeds back over the 30-year search
+// period and keeps the parcel's chain gaps in step: new gaps raise a TitleIssue
+// on the parcel's clearance, and gaps that disappear resolve theirs.
+
+var validDeedTypes = map[string]bool{
+	models.DeedTypeSale: true, models.DeedTypeGift: true, models.DeedTypePartition: true,
+	models.DeedTypeJDA: true, models.DeedTypeSettlement: true, models.DeedTypeRelease: true,
+	models.DeedTypeWill: true, models.DeedTypeInheritance: true, models.DeedTypeCourtDecree: true,
+}
+
+var validDeedPartyRoles = map[string]bool{
+	models.DeedPartyTransferor: true, models.DeedPartyTransferee: true,
+	models.DeedPartyConsenting: true, models.DeedPartyWitness: true,
+}
+
+// encumbranceIssueTypes maps encumbrance types to the title issue raised for them
+var encumbranceIssueTypes = map[string]string{
+	models.EncumbranceMortgage:   "mortgage",
+	models.EncumbranceLien:       "lien",
+	models.EncumbranceLitigation: "legal_claim",
+	models.EncumbranceLease:      "encumbrance",
+	models.EncumbranceEasement:   "encumbrance",
+	models.EncumbranceAttachment: "legal_claim",
+}
+
+// encumbranceSeverities is the severity of the issue raised for each encumbrance type
+var encumbranceSeverities = map[string]string{
+	models.EncumbranceMortgage:   "high",
+	models.EncumbranceLien:       "high",
+	models.EncumbranceLitigation: "critical",
+	models.EncumbranceLease:      "medium",
+	models.EncumbranceEasement:   "low",
+	models.EncumbranceAttachment: "critical",
+}
+
+// CreateTitleParcel registers a parcel by survey number
+func (ts *TitleService) CreateTitleParcel(tenantID, userID int64, req *models.CreateTitleParcelRequest) (*models.TitleParcel, error) {
+	if strings.TrimSpace(req.SurveyNumber) == "" || strings.TrimSpace(req.Village) == "" {
+		return nil, errors.New("survey_number and village are required")
+	}
+	if req.ClearanceID != nil {
+		if _, err := ts.GetTitleClearance(tenantID, *req.ClearanceID); err != nil {
+			return nil, err
+		}
+	}
+
+	parcel := &models.TitleParcel{
+		TenantID:     tenantID,
+		ClearanceID:  req.ClearanceID,
+		SurveyNumber: strings.TrimSpace(req.SurveyNumber),
+		SubDivision:  req.SubDivision,
+		Village:      strings.TrimSpace(req.Village),
+		Taluk:        req.Taluk,
+		District:     req.District,
+		State:        req.State,
+		AreaAcres:    req.AreaAcres,
+		LandType:     req.LandType,
+		Notes:        req.Notes,
+		CreatedBy:    &userID,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	query := `INSERT INTO title_parcels
+		(tenant_id, clearance_id, survey_number, sub_division, village, taluk, district, state, area_acres,
+		 land_type, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	err := ts.db.QueryRow(query,
+		parcel.TenantID, parcel.ClearanceID, parcel.SurveyNumber, parcel.SubDivision, parcel.Village,
+		parcel.Taluk, parcel.District, parcel.State, parcel.AreaAcres, parcel.LandType, parcel.Notes,
+		parcel.CreatedBy, parcel.CreatedAt, parcel.UpdatedAt).Scan(&parcel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return parcel, nil
+}
+
+// GetTitleParcel retrieves a parcel by ID
+func (ts *TitleService) GetTitleParcel(tenantID, parcelID int64) (*models.TitleParcel, error) {
+	parcels, err := ts.getTitleParcels(`id = ?`, []interface{}{parcelID}, tenantID, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(parcels) == 0 {
+		return nil, errors.New("parcel not found")
+	}
+	return parcels[0], nil
+}
+
+// ListTitleParcels lists parcels, optionally by survey number and village
+func (ts *TitleService) ListTitleParcels(tenantID int64, surveyNumber, village string, limit, offset int) ([]*models.TitleParcel, int, error) {
+	where, args := "1 = 1", []interface{}{}
+	if surveyNumber != "" {
+		where += " AND survey_number = ?"
+		args = append(args, surveyNumber)
+	}
+	if village != "" {
+		where += " AND village = ?"
+		args = append(args, village)
+	}
+
+	var total int
+	err := ts.db.QueryRow(`SELECT COUNT(*) FROM title_parcels WHERE tenant_id = ? AND deleted_at IS NULL AND `+where,
+		append([]interface{}{tenantID}, args...)...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parcels, err := ts.getTitleParcels(where, args, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return parcels, total, nil
+}
+
+func (ts *TitleService) getTitleParcels(where string, args []interface{}, tenantID int64, limit, offset int) ([]*models.TitleParcel, error) {
+	query := `SELECT id, tenant_id, clearance_id, survey_number, sub_division, village, taluk, district, state,
+		area_acres, land_type, notes, created_by, created_at, updated_at, deleted_at
+		FROM title_parcels
+		WHERE tenant_id = ? AND deleted_at IS NULL AND ` + where + `
+		ORDER BY village, survey_number, sub_division
+		LIMIT ? OFFSET ?`
+
+	rows, err := ts.db.Query(query, append(append([]interface{}{tenantID}, args...), limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parcels := []*models.TitleParcel{}
+	for rows.Next() {
+		parcel := &models.TitleParcel{}
+		err := rows.Scan(
+			&parcel.ID, &parcel.TenantID, &parcel.ClearanceID, &parcel.SurveyNumber, &parcel.SubDivision,
+			&parcel.Village, &parcel.Taluk, &parcel.District, &parcel.State, &parcel.AreaAcres,
+			&parcel.LandType, &parcel.Notes, &parcel.CreatedBy, &parcel.CreatedAt, &parcel.UpdatedAt,
+			&parcel.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		parcels = append(parcels, parcel)
+	}
+
+	return parcels, rows.Err()
+}
+
+// CreateTitleDeed records a deed and its parties in a parcel's chain, then
+// re-analyses the chain so gaps it opens or closes are reflected in the issues
+func (ts *TitleService) CreateTitleDeed(tenantID, userID, parcelID int64, req *models.CreateTitleDeedRequest) (*models.TitleDeed, error) {
+	if err := validateTitleDeed(req); err != nil {
+		return nil, err
+	}
+	if _, err := ts.GetTitleParcel(tenantID, parcelID); err != nil {
+		return nil, err
+	}
+
+	deed := &models.TitleDeed{
+		TenantID:           tenantID,
+		ParcelID:           parcelID,
+		DeedType:           req.DeedType,
+		DocumentNumber:     strings.TrimSpace(req.DocumentNumber),
+		RegistrationDate:   req.RegistrationDate,
+		ExecutionDate:      req.ExecutionDate,
+		SubRegistrarOffice: req.SubRegistrarOffice,
+		Consideration:      req.Consideration,
+		DocumentURL:        req.DocumentURL,
+		Notes:              req.Notes,
+		CreatedBy:          &userID,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	tx, err := ts.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO title_deeds
+		(tenant_id, parcel_id, deed_type, document_number, registration_date, execution_date,
+		 sub_registrar_office, consideration, document_url, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	err = tx.QueryRow(query,
+		deed.TenantID, deed.ParcelID, deed.DeedType, deed.DocumentNumber, deed.RegistrationDate,
+		deed.ExecutionDate, deed.SubRegistrarOffice, deed.Consideration, deed.DocumentURL, deed.Notes,
+		deed.CreatedBy, deed.CreatedAt, deed.UpdatedAt).Scan(&deed.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range req.Parties {
+		party := models.TitleDeedParty{
+			TenantID:          tenantID,
+			DeedID:            deed.ID,
+			PartyRole:         p.PartyRole,
+			PartyName:         strings.TrimSpace(p.PartyName),
+			PartyType:         p.PartyType,
+			IdentityReference: p.IdentityReference,
+		}
+		err = tx.QueryRow(`INSERT INTO title_deed_parties
+			(tenant_id, deed_id, party_role, party_name, party_type, identity_reference)
+			VALUES (?, ?, ?, ?, ?, ?)
+			RETURNING id`,
+			party.TenantID, party.DeedID, party.PartyRole, party.PartyName, party.PartyType,
+			party.IdentityReference).Scan(&party.ID)
+		if err != nil {
+			return nil, err
+		}
+		deed.Parties = append(deed.Parties, party)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.AnalyzeTitleChain(tenantID, userID, parcelID); err != nil {
+		return nil, fmt.Errorf("deed recorded but chain analysis failed: %v", err)
+	}
+
+	return deed, nil
+}
+
+// ListTitleDeeds lists a parcel's deeds with their parties in registration order
+func (ts *TitleService) ListTitleDeeds(tenantID, parcelID int64) ([]*models.TitleDeed, error) {
+	query := `SELECT id, tenant_id, parcel_id, deed_type, document_number, registration_date, execution_date,
+		sub_registrar_office, consideration, document_url, notes, created_by, created_at, updated_at, deleted_at
+		FROM title_deeds
+		WHERE tenant_id = ? AND parcel_id = ? AND deleted_at IS NULL
+		ORDER BY registration_date, id`
+
+	rows, err := ts.db.Query(query, tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deeds := []*models.TitleDeed{}
+	byID := map[int64]*models.TitleDeed{}
+	for rows.Next() {
+		deed := &models.TitleDeed{}
+		err := rows.Scan(
+			&deed.ID, &deed.TenantID, &deed.ParcelID, &deed.DeedType, &deed.DocumentNumber,
+			&deed.RegistrationDate, &deed.ExecutionDate, &deed.SubRegistrarOffice, &deed.Consideration,
+			&deed.DocumentURL, &deed.Notes, &deed.CreatedBy, &deed.CreatedAt, &deed.UpdatedAt, &deed.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		deed.Parties = []models.TitleDeedParty{}
+		deeds = append(deeds, deed)
+		byID[deed.ID] = deed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	partyRows, err := ts.db.Query(`SELECT p.id, p.tenant_id, p.deed_id, p.party_role, p.party_name, p.party_type,
+		p.identity_reference
+		FROM title_deed_parties p
+		JOIN title_deeds d ON d.id = p.deed_id
+		WHERE p.tenant_id = ? AND d.parcel_id = ? AND d.deleted_at IS NULL
+		ORDER BY p.id`, tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	defer partyRows.Close()
+
+	for partyRows.Next() {
+		var party models.TitleDeedParty
+		err := partyRows.Scan(&party.ID, &party.TenantID, &party.DeedID, &party.PartyRole, &party.PartyName,
+			&party.PartyType, &party.IdentityReference)
+		if err != nil {
+			return nil, err
+		}
+		if deed, ok := byID[party.DeedID]; ok {
+			deed.Parties = append(deed.Parties, party)
+		}
+	}
+
+	return deeds, partyRows.Err()
+}
+
+// CreateTitleEncumbrance records an encumbrance on a parcel and raises a title
+// issue for it on the parcel's clearance
+func (ts *TitleService) CreateTitleEncumbrance(tenantID, userID, parcelID int64, req *models.CreateTitleEncumbranceRequest) (*models.TitleEncumbrance, error) {
+	issueType, ok := encumbranceIssueTypes[req.EncumbranceType]
+	if !ok {
+		return nil, errors.New("encumbrance_type must be mortgage, lien, litigation, lease, easement or attachment")
+	}
+	if strings.TrimSpace(req.HolderName) == "" || req.RecordedDate.IsZero() {
+		return nil, errors.New("holder_name and recorded_date are required")
+	}
+	parcel, err := ts.GetTitleParcel(tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &models.TitleEncumbrance{
+		TenantID:        tenantID,
+		ParcelID:        parcelID,
+		DeedID:          req.DeedID,
+		EncumbranceType: req.EncumbranceType,
+		HolderName:      strings.TrimSpace(req.HolderName),
+		ReferenceNumber: req.ReferenceNumber,
+		RecordedDate:    req.RecordedDate,
+		Amount:          req.Amount,
+		Status:          models.EncumbranceSubsisting,
+		Notes:           req.Notes,
+		CreatedBy:       &userID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if parcel.ClearanceID != nil {
+		description := fmt.Sprintf("%s in favour of %s recorded on %s", req.EncumbranceType, enc.HolderName,
+			enc.RecordedDate.Format("02-Jan-2006"))
+		issue, err := ts.CreateTitleIssue(tenantID, *parcel.ClearanceID, &models.CreateTitleIssueRequest{
+			IssueType:        issueType,
+			IssueTitle:       fmt.Sprintf("Subsisting %s on Sy. No. %s, %s", req.EncumbranceType, parcel.SurveyNumber, parcel.Village),
+			IssueDescription: &description,
+			Severity:         encumbranceSeverities[req.EncumbranceType],
+			SourceDocument:   req.ReferenceNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		enc.IssueID = &issue.ID
+	}
+
+	query := `INSERT INTO title_encumbrances
+		(tenant_id, parcel_id, deed_id, encumbrance_type, holder_name, reference_number, recorded_date, amount,
+		 status, issue_id, notes, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	err = ts.db.QueryRow(query,
+		enc.TenantID, enc.ParcelID, enc.DeedID, enc.EncumbranceType, enc.HolderName, enc.ReferenceNumber,
+		enc.RecordedDate, enc.Amount, enc.Status, enc.IssueID, enc.Notes, enc.CreatedBy,
+		enc.CreatedAt, enc.UpdatedAt).Scan(&enc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+// DischargeTitleEncumbrance records the release of an encumbrance and resolves its title issue
+func (ts *TitleService) DischargeTitleEncumbrance(tenantID, userID, encumbranceID int64, req *models.DischargeTitleEncumbranceRequest) error {
+	if req.DischargedDate.IsZero() || strings.TrimSpace(req.DischargeReference) == "" {
+		return errors.New("discharged_date and discharge_reference are required")
+	}
+
+	var issueID sql.NullInt64
+	err := ts.db.QueryRow(`SELECT issue_id FROM title_encumbrances WHERE id = ? AND tenant_id = ?`,
+		encumbranceID, tenantID).Scan(&issueID)
+	if err == sql.ErrNoRows {
+		return errors.New("encumbrance not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := ts.db.Exec(`UPDATE title_encumbrances SET status = ?, discharged_date = ?, discharge_reference = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.EncumbranceDischarged, req.DischargedDate, req.DischargeReference, time.Now(),
+		encumbranceID, tenantID, models.EncumbranceSubsisting)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("encumbrance is already discharged")
+	}
+
+	if issueID.Valid {
+		return ts.ResolveTitleIssue(tenantID, issueID.Int64, userID, &models.ResolveTitleIssueRequest{
+			Status:           "resolved",
+			ResolutionMethod: "rectification",
+		})
+	}
+	return nil
+}
+
+// ListTitleEncumbrances lists a parcel's encumbrances, optionally by status
+func (ts *TitleService) ListTitleEncumbrances(tenantID, parcelID int64, status string) ([]*models.TitleEncumbrance, error) {
+	query := `SELECT id, tenant_id, parcel_id, deed_id, encumbrance_type, holder_name, reference_number,
+		recorded_date, amount, status, discharged_date, discharge_reference, issue_id, notes, created_by,
+		created_at, updated_at
+		FROM title_encumbrances
+		WHERE tenant_id = ? AND parcel_id = ?`
+	args := []interface{}{tenantID, parcelID}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY recorded_date, id"
+
+	rows, err := ts.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	encumbrances := []*models.TitleEncumbrance{}
+	for rows.Next() {
+		enc := &models.TitleEncumbrance{}
+		err := rows.Scan(
+			&enc.ID, &enc.TenantID, &enc.ParcelID, &enc.DeedID, &enc.EncumbranceType, &enc.HolderName,
+			&enc.ReferenceNumber, &enc.RecordedDate, &enc.Amount, &enc.Status, &enc.DischargedDate,
+			&enc.DischargeReference, &enc.IssueID, &enc.Notes, &enc.CreatedBy, &enc.CreatedAt, &enc.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		encumbrances = append(encumbrances, enc)
+	}
+
+	return encumbrances, rows.Err()
+}
+
+// AnalyzeTitleChain traces the parcel's deeds over the 30-year search period and
+// syncs its chain gaps: new gaps are recorded and raise a chain_gap issue on the
+// parcel's clearance, and open gaps no longer found are closed and their issues resolved
+func (ts *TitleService) AnalyzeTitleChain(tenantID, userID, parcelID int64) (*models.TitleChainAnalysis, error) {
+	parcel, err := ts.GetTitleParcel(tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	deeds, err := ts.ListTitleDeeds(tenantID, parcelID)
+	if err != nil {
+		return nil, err
+	}
+	encumbrances, err := ts.ListTitleEncumbrances(tenantID, parcelID, models.EncumbranceSubsisting)
+	if err != nil {
+		return nil, err
+	}
+
+	periodEnd := time.Now()
+	periodStart := periodEnd.AddDate(-models.TitleChainYears, 0, 0)
+	detected := detectChainGaps(deeds, periodStart, periodEnd)
+
+	open, err := ts.listChainGaps(tenantID, parcelID, models.ChainGapStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*models.TitleChainGap{}
+	for _, gap := range open {
+		existing[chainGapKey(gap)] = gap
+	}
+
+	analysis := &models.TitleChainAnalysis{
+		Parcel:        parcel,
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		Deeds:         deeds,
+		Gaps:          []*models.TitleChainGap{},
+		Encumbrances:  encumbrances,
+		CurrentOwners: currentTitleHolders(deeds),
+	}
+
+	for _, gap := range detected {
+		key := chainGapKey(gap)
+		if prior, ok := existing[key]; ok {
+			delete(existing, key)
+			analysis.Gaps = append(analysis.Gaps, prior)
+			continue
+		}
+		gap.TenantID = tenantID
+		gap.ParcelID = parcelID
+		if parcel.ClearanceID != nil {
+			issue, err := ts.CreateTitleIssue(tenantID, *parcel.ClearanceID, chainGapIssueRequest(parcel, gap, deeds))
+			if err != nil {
+				return nil, err
+			}
+			gap.IssueID = &issue.ID
+			analysis.IssuesRaised++
+		}
+		if err := ts.insertChainGap(gap); err != nil {
+			return nil, err
+		}
+		analysis.Gaps = append(analysis.Gaps, gap)
+	}
+
+	// Open gaps that were not detected again have been closed by deeds added or corrected since
+	for _, gap := range existing {
+		if _, err := ts.db.Exec(`UPDATE title_chain_gaps SET status = ?, closed_at = ? WHERE id = ? AND tenant_id = ?`,
+			models.ChainGapStatusClosed, periodEnd, gap.ID, tenantID); err != nil {
+			return nil, err
+		}
+		if gap.IssueID != nil {
+			if err := ts.ResolveTitleIssue(tenantID, *gap.IssueID, userID, &models.ResolveTitleIssueRequest{
+				Status:           "resolved",
+				ResolutionMethod: "rectification",
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	analysis.IsComplete = len(analysis.Gaps) == 0
+	return analysis, nil
+}
+
+// GetSurveyTimeline returns the dated title events of every parcel under a survey number
+func (ts *TitleService) GetSurveyTimeline(tenantID int64, surveyNumber, village string) ([]*models.TitleTimelineEvent, error) {
+	if surveyNumber == "" {
+		return nil, errors.New("survey_number is required")
+	}
+	parcels, _, err := ts.ListTitleParcels(tenantID, surveyNumber, village, 1000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*models.TitleTimelineEvent{}
+	for _, parcel := range parcels {
+		deeds, err := ts.ListTitleDeeds(tenantID, parcel.ID)
+		if err != nil {
+			return nil, err
+		}
+		encumbrances, err := ts.ListTitleEncumbrances(tenantID, parcel.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		gaps, err := ts.listChainGaps(tenantID, parcel.ID, models.ChainGapStatusOpen)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, buildTitleTimeline(parcel.ID, deeds, encumbrances, gaps)...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events, nil
+}
+
+// ListTitleChainGaps lists a parcel's chain gaps, optionally by status
+func (ts *TitleService) ListTitleChainGaps(tenantID, parcelID int64, status string) ([]*models.TitleChainGap, error) {
+	return ts.listChainGaps(tenantID, parcelID, status)
+}
+
+func (ts *TitleService) listChainGaps(tenantID, parcelID int64, status string) ([]*models.TitleChainGap, error) {
+	query := `SELECT id, tenant_id, parcel_id, gap_kind, from_date, to_date, preceding_deed_id, following_deed_id,
+		description, status, issue_id, detected_at, closed_at
+		FROM title_chain_gaps
+		WHERE tenant_id = ? AND parcel_id = ?`
+	args := []interface{}{tenantID, parcelID}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY from_date, id"
+
+	rows, err := ts.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	gaps := []*models.TitleChainGap{}
+	for rows.Next() {
+		gap := &models.TitleChainGap{}
+		err := rows.Scan(
+			&gap.ID, &gap.TenantID, &gap.ParcelID, &gap.GapKind, &gap.FromDate, &gap.ToDate,
+			&gap.PrecedingDeedID, &gap.FollowingDeedID, &gap.Description, &gap.Status, &gap.IssueID,
+			&gap.DetectedAt, &gap.ClosedAt)
+		if err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, rows.Err()
+}
+
+func (ts *TitleService) insertChainGap(gap *models.TitleChainGap) error {
+	query := `INSERT INTO title_chain_gaps
+		(tenant_id, parcel_id, gap_kind, from_date, to_date, preceding_deed_id, following_deed_id, description,
+		 status, issue_id, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	return ts.db.QueryRow(query,
+		gap.TenantID, gap.ParcelID, gap.GapKind, gap.FromDate, gap.ToDate, gap.PrecedingDeedID,
+		gap.FollowingDeedID, gap.Description, gap.Status, gap.IssueID, gap.DetectedAt).Scan(&gap.ID)
+}
+
+// Chain of title helpers
+
+func validateTitleDeed(req *models.CreateTitleDeedRequest) error {
+	if !validDeedTypes[req.DeedType] {
+		return errors.New("deed_type must be sale, gift, partition, jda, settlement, release, will, inheritance or court_decree")
+	}
+	if strings.TrimSpace(req.DocumentNumber) == "" || req.RegistrationDate.IsZero() {
+		return errors.New("document_number and registration_date are required")
+	}
+	transferors, transferees := 0, 0
+	for _, p := range req.Parties {
+		if !validDeedPartyRoles[p.PartyRole] {
+			return fmt.Errorf("invalid party_role %q", p.PartyRole)
+		}
+		if strings.TrimSpace(p.PartyName) == "" {
+			return errors.New("party_name is required for every party")
+		}
+		switch p.PartyRole {
+		case models.DeedPartyTransferor:
+			transferors++
+		case models.DeedPartyTransferee:
+			transferees++
+		}
+	}
+	if transferors == 0 || transferees == 0 {
+		return errors.New("a deed needs at least one transferor and one transferee")
+	}
+	return nil
+}
+
+// normalizePartyName compares party names case- and whitespace-insensitively
+func normalizePartyName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func deedPartyNames(deed *models.TitleDeed, role string) []string {
+	names := []string{}
+	for _, p := range deed.Parties {
+		if p.PartyRole == role {
+			names = append(names, p.PartyName)
+		}
+	}
+	return names
+}
+
+// titleHolders returns who holds title after a deed: its transferees, and under
+// a JDA also the landowners, who keep title while the developer gets development rights
+func titleHolders(deed *models.TitleDeed) []string {
+	holders := deedPartyNames(deed, models.DeedPartyTransferee)
+	if deed.DeedType == models.DeedTypeJDA {
+		holders = append(deedPartyNames(deed, models.DeedPartyTransferor), holders...)
+	}
+	return holders
+}
+
+func currentTitleHolders(deeds []*models.TitleDeed) []string {
+	if len(deeds) == 0 {
+		return []string{}
+	}
+	sorted := sortedDeeds(deeds)
+	return titleHolders(sorted[len(sorted)-1])
+}
+
+func sortedDeeds(deeds []*models.TitleDeed) []*models.TitleDeed {
+	sorted := append([]*models.TitleDeed{}, deeds...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RegistrationDate.Before(sorted[j].RegistrationDate)
+	})
+	return sorted
+}
+
+// detectChainGaps finds breaks in the chain of title over [periodStart, periodEnd].
+// The chain must start from a root deed registered on or before periodStart, and
+// every later deed's transferors must include a holder under the deed before it.
+func detectChainGaps(deeds []*models.TitleDeed, periodStart, periodEnd time.Time) []*models.TitleChainGap {
+	now := time.Now()
+	newGap := func(kind string, from, to time.Time, preceding, following *models.TitleDeed, description string) *models.TitleChainGap {
+		gap := &models.TitleChainGap{
+			GapKind:     kind,
+			FromDate:    from,
+			ToDate:      to,
+			Description: description,
+			Status:      models.ChainGapStatusOpen,
+			DetectedAt:  now,
+		}
+		if preceding != nil {
+			gap.PrecedingDeedID = &preceding.ID
+		}
+		if following != nil {
+			gap.FollowingDeedID = &following.ID
+		}
+		return gap
+	}
+
+	gaps := []*models.TitleChainGap{}
+	if len(deeds) == 0 {
+		return append(gaps, newGap(models.ChainGapNoDeeds, periodStart, periodEnd, nil, nil,
+			fmt.Sprintf("No deeds recorded; title must be traced for %d years", models.TitleChainYears)))
+	}
+
+	sorted := sortedDeeds(deeds)
+	root := -1
+	for i, d := range sorted {
+		if !d.RegistrationDate.After(periodStart) {
+			root = i
+		}
+	}
+	if root < 0 {
+		first := sorted[0]
+		gaps = append(gaps, newGap(models.ChainGapNoRootDeed, periodStart, first.RegistrationDate, nil, first,
+			fmt.Sprintf("Earliest deed %s is dated %s; no deed traces title back to %s",
+				first.DocumentNumber, first.RegistrationDate.Format("02-Jan-2006"), periodStart.Format("02-Jan-2006"))))
+		root = 0
+	}
+
+	prev := sorted[root]
+	for _, d := range sorted[root+1:] {
+		holders := map[string]bool{}
+		for _, name := range titleHolders(prev) {
+			holders[normalizePartyName(name)] = true
+		}
+		linked := false
+		transferors := deedPartyNames(d, models.DeedPartyTransferor)
+		for _, name := range transferors {
+			if holders[normalizePartyName(name)] {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			gaps = append(gaps, newGap(models.ChainGapBrokenLink, prev.RegistrationDate, d.RegistrationDate, prev, d,
+				fmt.Sprintf("Transferors under %s (%s) did not hold title under %s (%s)",
+					d.DocumentNumber, strings.Join(transferors, ", "),
+					prev.DocumentNumber, strings.Join(titleHolders(prev), ", "))))
+		}
+		prev = d
+	}
+
+	return gaps
+}
+
+// chainGapKey identifies a gap across analyses by its kind and the deeds around it
+func chainGapKey(gap *models.TitleChainGap) string {
+	id := func(p *int64) int64 {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	return fmt.Sprintf("%s:%d:%d", gap.GapKind, id(gap.PrecedingDeedID), id(gap.FollowingDeedID))
+}
+
+func chainGapIssueRequest(parcel *models.TitleParcel, gap *models.TitleChainGap, deeds []*models.TitleDeed) *models.CreateTitleIssueRequest {
+	severity := "high"
+	if gap.GapKind != models.ChainGapBrokenLink {
+		severity = "critical"
+	}
+	var source *string
+	for _, d := range deeds {
+		if gap.FollowingDeedID != nil && d.ID == *gap.FollowingDeedID {
+			source = &d.DocumentNumber
+		}
+	}
+	description := gap.Description
+	return &models.CreateTitleIssueRequest{
+		IssueType: models.TitleIssueTypeChainGap,
+		IssueTitle: fmt.Sprintf("Chain of title gap on Sy. No. %s, %s (%s to %s)", parcel.SurveyNumber, parcel.Village,
+			gap.FromDate.Format("02-Jan-2006"), gap.ToDate.Format("02-Jan-2006")),
+		IssueDescription: &description,
+		Severity:         severity,
+		SourceDocument:   source,
+	}
+}
+
+// buildTitleTimeline lists a parcel's deeds, encumbrance events and open gaps as dated events
+func buildTitleTimeline(parcelID int64, deeds []*models.TitleDeed, encumbrances []*models.TitleEncumbrance, gaps []*models.TitleChainGap) []*models.TitleTimelineEvent {
+	events := []*models.TitleTimelineEvent{}
+	for _, d := range deeds {
+		reference := d.DocumentNumber
+		events = append(events, &models.TitleTimelineEvent{
+			Date:        d.RegistrationDate,
+			ParcelID:    parcelID,
+			EventType:   "deed",
+			Subtype:     d.DeedType,
+			ReferenceID: d.ID,
+			Reference:   &reference,
+			Description: fmt.Sprintf("%s deed %s", d.DeedType, d.DocumentNumber),
+			FromParties: deedPartyNames(d, models.DeedPartyTransferor),
+			ToParties:   deedPartyNames(d, models.DeedPartyTransferee),
+		})
+	}
+	for _, e := range encumbrances {
+		events = append(events, &models.TitleTimelineEvent{
+			Date:        e.RecordedDate,
+			ParcelID:    parcelID,
+			EventType:   "encumbrance_recorded",
+			Subtype:     e.EncumbranceType,
+			ReferenceID: e.ID,
+			Reference:   e.ReferenceNumber,
+			Description: fmt.Sprintf("%s in favour of %s", e.EncumbranceType, e.HolderName),
+		})
+		if e.Status == models.EncumbranceDischarged && e.DischargedDate != nil {
+			events = append(events, &models.TitleTimelineEvent{
+				Date:        *e.DischargedDate,
+				ParcelID:    parcelID,
+				EventType:   "encumbrance_discharged",
+				Subtype:     e.EncumbranceType,
+				ReferenceID: e.ID,
+				Reference:   e.DischargeReference,
+				Description: fmt.Sprintf("%s in favour of %s discharged", e.EncumbranceType, e.HolderName),
+			})
+		}
+	}
+	for _, g := range gaps {
+		events = append(events, &models.TitleTimelineEvent{
+			Date:        g.FromDate,
+			ParcelID:    parcelID,
+			EventType:   "chain_gap",
+			Subtype:     g.GapKind,
+			ReferenceID: g.ID,
+			Description: g.Description,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDeed(id int64, deedType, doc string, date time.Time, from, to []string) *models.TitleDeed {
+	deed := &models.TitleDeed{ID: id, DeedType: deedType, DocumentNumber: doc, RegistrationDate: date}
+	for _, name := range from {
+		deed.Parties = append(deed.Parties, models.TitleDeedParty{PartyRole: models.DeedPartyTransferor, PartyName: name})
+	}
+	for _, name := range to {
+		deed.Parties = append(deed.Parties, models.TitleDeedParty{PartyRole: models.DeedPartyTransferee, PartyName: name})
+	}
+	return deed
+}
+
+// TestDetectChainGaps tests root deed, continuity and JDA handling in the chain of title
+func TestDetectChainGaps(t *testing.T) {
+	end := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(-models.TitleChainYears, 0, 0)
+
+	gaps := detectChainGaps(nil, start, end)
+	assert.Len(t, gaps, 1)
+	assert.Equal(t, models.ChainGapNoDeeds, gaps[0].GapKind)
+
+	chain := []*models.TitleDeed{
+		testDeed(3, models.DeedTypeJDA, "JDA-3", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), []string{"Ravi Kumar"}, []string{"Vyom Developers"}),
+		testDeed(1, models.DeedTypeSale, "SD-1", time.Date(1990, 2, 1, 0, 0, 0, 0, time.UTC), []string{"Lakshmamma"}, []string{"Nagappa"}),
+		testDeed(2, models.DeedTypeGift, "GD-2", time.Date(2005, 8, 1, 0, 0, 0, 0, time.UTC), []string{" nagappa "}, []string{"Ravi Kumar"}),
+		testDeed(4, models.DeedTypeSale, "SD-4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), []string{"Ravi Kumar"}, []string{"Anita Rao"}),
+	}
+	assert.Empty(t, detectChainGaps(chain, start, end)) // landowner keeps title under the JDA
+	assert.Equal(t, []string{"Anita Rao"}, currentTitleHolders(chain))
+
+	// Root deed is later than the start of the search period
+	late := []*models.TitleDeed{chain[2], chain[0]}
+	gaps = detectChainGaps(late, start, end)
+	assert.Len(t, gaps, 1)
+	assert.Equal(t, models.ChainGapNoRootDeed, gaps[0].GapKind)
+	assert.Equal(t, int64(2), *gaps[0].FollowingDeedID)
+
+	// Transferor who never acquired title
+	broken := append([]*models.TitleDeed{}, chain...)
+	broken[3] = testDeed(4, models.DeedTypeSale, "SD-4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), []string{"Suresh"}, []string{"Anita Rao"})
+	gaps = detectChainGaps(broken, start, end)
+	assert.Len(t, gaps, 1)
+	assert.Equal(t, models.ChainGapBrokenLink, gaps[0].GapKind)
+	assert.Equal(t, int64(3), *gaps[0].PrecedingDeedID)
+	assert.Equal(t, "broken_link:3:4", chainGapKey(gaps[0]))
+}
+
+// TestValidateTitleDeed tests deed type and party validation
+func TestValidateTitleDeed(t *testing.T) {
+	req := &models.CreateTitleDeedRequest{
+		DeedType:         models.DeedTypeSale,
+		DocumentNumber:   "SD-1",
+		RegistrationDate: time.Date(1990, 2, 1, 0, 0, 0, 0, time.UTC),
+		Parties: []models.TitleDeedPartyInput{
+			{PartyRole: models.DeedPartyTransferor, PartyName: "Lakshmamma"},
+			{PartyRole: models.DeedPartyTransferee, PartyName: "Nagappa"},
+		},
+	}
+	assert.NoError(t, validateTitleDeed(req))
+
+	bad := *req
+	bad.DeedType = "lease"
+	assert.Error(t, validateTitleDeed(&bad))
+
+	bad = *req
+	bad.Parties = req.Parties[:1]
+	assert.Error(t, validateTitleDeed(&bad))
+
+	bad = *req
+	bad.Parties = append([]models.TitleDeedPartyInput{{PartyRole: "buyer", PartyName: "X"}}, req.Parties...)
+	assert.Error(t, validateTitleDeed(&bad))
+}
+
+// TestBuildTitleTimeline tests the ordering of deed, encumbrance and gap events
+func TestBuildTitleTimeline(t *testing.T) {
+	discharged := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	deeds := []*models.TitleDeed{
+		testDeed(2, models.DeedTypeGift, "GD-2", time.Date(2005, 8, 1, 0, 0, 0, 0, time.UTC), []string{"Nagappa"}, []string{"Ravi Kumar"}),
+	}
+	encumbrances := []*models.TitleEncumbrance{
+		{ID: 7, EncumbranceType: models.EncumbranceMortgage, HolderName: "Canara Bank", RecordedDate: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+			Status: models.EncumbranceDischarged, DischargedDate: &discharged},
+	}
+	gaps := []*models.TitleChainGap{
+		{ID: 9, GapKind: models.ChainGapNoRootDeed, FromDate: time.Date(1996, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	events := buildTitleTimeline(1, deeds, encumbrances, gaps)
+	assert.Len(t, events, 4)
+	assert.Equal(t, "chain_gap", events[0].EventType)
+	assert.Equal(t, "deed", events[1].EventType)
+	assert.Equal(t, []string{"Ravi Kumar"}, events[1].ToParties)
+	assert.Equal(t, "encumbrance_recorded", events[2].EventType)
+	assert.Equal(t, "encumbrance_discharged", events[3].EventType)
+}
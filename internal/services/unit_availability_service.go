@@ -0,0 +1,392 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// UNIT AVAILABILITY SERVICE
+// ============================================================================
+
+// UnitAvailabilityService builds the tower x floor availability matrix of a project
+// and pushes unit status changes to connected sales screens
+type UnitAvailabilityService struct {
+	DB  *sql.DB
+	Hub *WebSocketHub
+}
+
+// NewUnitAvailabilityService creates a new unit availability service
+func NewUnitAvailabilityService(db *sql.DB, hub *WebSocketHub) *UnitAvailabilityService {
+	return &UnitAvailabilityService{DB: db, Hub: hub}
+}
+
+// manualUnitTransitions are the status changes sales can make by hand; booking and
+// cancellation move units between available and booked themselves
+var manualUnitTransitions = map[string][]string{
+	models.UnitStatusAvailable: {models.UnitStatusHeld, models.UnitStatusBlocked},
+	models.UnitStatusHeld:      {models.UnitStatusAvailable, models.UnitStatusBlocked},
+	models.UnitStatusBlocked:   {models.UnitStatusAvailable, models.UnitStatusHeld},
+	models.UnitStatusBooked:    {models.UnitStatusRegistered},
+}
+
+// ============================================================================
+// MATRIX
+// ============================================================================
+
+// GetMatrix returns the project's availability grid per tower with counts and
+// value by status. Booked units are valued at their locked price and the rest
+// under the price list in force today.
+func (s *UnitAvailabilityService) GetMatrix(tenantID, projectID string, filter *models.UnitMatrixFilter) (*models.UnitAvailabilityMatrix, error) {
+	if filter == nil {
+		filter = &models.UnitMatrixFilter{}
+	}
+	where, args := "u.project_id = ?", []interface{}{projectID}
+	if filter.BlockID != "" {
+		where += " AND u.block_id = ?"
+		args = append(args, filter.BlockID)
+	}
+
+	rows, err := s.DB.Query(`SELECT u.id, u.unit_number, COALESCE(u.block_id, ''), COALESCE(blk.block_name, ''),
+		COALESCE(u.floor, 0), COALESCE(u.unit_type, ''), COALESCE(u.facing, ''), COALESCE(u.carpet_area, 0),
+		COALESCE(u.sbua, 0), COALESCE(u.status, ''), COALESCE(a.view_type, ''), COALESCE(a.is_corner, FALSE),
+		COALESCE(b.id, ''), bpl.agreement_value, ucs.apartment_cost_exc_govt
+		FROM property_units u
+		LEFT JOIN property_blocks blk ON blk.id = u.block_id
+		LEFT JOIN unit_pricing_attributes a ON a.unit_id = u.id AND a.tenant_id = u.tenant_id
+		LEFT JOIN customer_bookings b ON b.unit_id = u.id AND b.tenant_id = u.tenant_id
+			AND b.booking_status = 'active' AND b.deleted_at IS NULL
+		LEFT JOIN booking_price_locks bpl ON bpl.booking_id = b.id AND bpl.tenant_id = u.tenant_id
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = u.id AND ucs.tenant_id = u.tenant_id
+		WHERE u.tenant_id = ? AND u.deleted_at IS NULL AND `+where+`
+		ORDER BY u.unit_number`, append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch units: %w", err)
+	}
+	defer rows.Close()
+
+	type unitRow struct {
+		cell     models.UnitMatrixCell
+		view     string
+		isCorner bool
+		locked   sql.NullFloat64
+		legacy   sql.NullFloat64
+	}
+	units := []unitRow{}
+	blockNames := map[string]string{}
+	for rows.Next() {
+		var u unitRow
+		var blockName, rawStatus string
+		if err := rows.Scan(&u.cell.UnitID, &u.cell.UnitNumber, &u.cell.BlockID, &blockName, &u.cell.Floor,
+			&u.cell.UnitType, &u.cell.Facing, &u.cell.CarpetArea, &u.cell.SBUA, &rawStatus, &u.view, &u.isCorner,
+			&u.cell.BookingID, &u.locked, &u.legacy); err != nil {
+			return nil, fmt.Errorf("failed to scan unit: %w", err)
+		}
+		u.cell.Status = matrixUnitStatus(rawStatus, u.cell.BookingID != "")
+		blockNames[u.cell.BlockID] = blockName
+		units = append(units, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Each tower's price list is resolved once; towers without one fall back to the
+	// unit's legacy cost sheet
+	now := time.Now()
+	priceLists := NewPriceListService(s.DB)
+	resolved := map[string]*models.PriceList{}
+	cells := make([]models.UnitMatrixCell, 0, len(units))
+	for _, u := range units {
+		cell := u.cell
+		cell.PriceBasis = "none"
+		if u.locked.Valid {
+			cell.Price, cell.PriceBasis = roundTo2(u.locked.Float64), "booking"
+		} else {
+			pl, ok := resolved[cell.BlockID]
+			if !ok {
+				pl, _ = priceLists.ResolvePriceList(tenantID, projectID, cell.BlockID, now)
+				resolved[cell.BlockID] = pl
+			}
+			sheet := &models.CostSheet{UnitID: cell.UnitID, UnitNumber: cell.UnitNumber, ProjectID: projectID,
+				BlockID: cell.BlockID, Floor: cell.Floor, UnitType: cell.UnitType, Facing: cell.Facing,
+				SBUA: cell.SBUA, View: u.view, IsCorner: u.isCorner}
+			if pl != nil && cell.SBUA > 0 && buildCostSheet(sheet, pl, nil) == nil {
+				cell.Price, cell.PriceBasis = sheet.AgreementValue, "price_list"
+			} else if u.legacy.Valid {
+				cell.Price, cell.PriceBasis = roundTo2(u.legacy.Float64), "cost_sheet"
+			}
+		}
+		if unitMatchesFilter(cell, filter) {
+			cells = append(cells, cell)
+		}
+	}
+
+	matrix := buildAvailabilityMatrix(projectID, cells, blockNames)
+	matrix.AsOf = now
+	return matrix, nil
+}
+
+// ============================================================================
+// STATUS CHANGES
+// ============================================================================
+
+// SetUnitStatus holds, blocks, releases or registers a unit
+func (s *UnitAvailabilityService) SetUnitStatus(tenantID, userID, unitID string, req *models.SetUnitStatusRequest) (*models.UnitStatusChange, error) {
+	var rawStatus string
+	var booked bool
+	err := s.DB.QueryRow(`SELECT COALESCE(u.status, ''), EXISTS (SELECT 1 FROM customer_bookings b
+			WHERE b.unit_id = u.id AND b.tenant_id = u.tenant_id AND b.booking_status = 'active'
+			AND b.deleted_at IS NULL)
+		FROM property_units u WHERE u.id = ? AND u.tenant_id = ? AND u.deleted_at IS NULL`,
+		unitID, tenantID).Scan(&rawStatus, &booked)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unit not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit: %w", err)
+	}
+
+	if landowner, err := isLandownerUnit(s.DB, tenantID, unitID); err != nil {
+		return nil, err
+	} else if landowner {
+		return nil, fmt.Errorf("unit is allotted to a landowner under a JDA; release the allotment instead")
+	}
+
+	from := matrixUnitStatus(rawStatus, booked)
+	if !canChangeUnitStatus(from, req.Status) {
+		return nil, fmt.Errorf("a %s unit cannot be marked %s", from, req.Status)
+	}
+	if req.Status != models.UnitStatusAvailable && strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	res, err := s.DB.Exec(`UPDATE property_units SET status = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND COALESCE(status, '') = ?`,
+		req.Status, time.Now(), unitID, tenantID, rawStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update unit status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("unit status was changed by someone else")
+	}
+	return s.UnitStatusChanged(tenantID, userID, unitID, from, req.Status, req.Reason)
+}
+
+// BookUnitTx marks a unit booked inside the transaction that creates its booking and
+// returns the status it had. Only an available unit can be booked; held, blocked,
+// booked and landowner units are refused.
+func (s *UnitAvailabilityService) BookUnitTx(tx *sql.Tx, tenantID, unitID string) (string, error) {
+	var rawStatus string
+	var booked bool
+	err := tx.QueryRow(`SELECT COALESCE(u.status, ''), EXISTS (SELECT 1 FROM customer_bookings b
+			WHERE b.unit_id = u.id AND b.tenant_id = u.tenant_id AND b.booking_status = 'active'
+			AND b.deleted_at IS NULL)
+		FROM property_units u WHERE u.id = ? AND u.tenant_id = ? AND u.deleted_at IS NULL FOR UPDATE`,
+		unitID, tenantID).Scan(&rawStatus, &booked)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("unit not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get unit: %w", err)
+	}
+
+	if landowner, err := isLandownerUnit(tx, tenantID, unitID); err != nil {
+		return "", err
+	} else if landowner {
+		return "", fmt.Errorf("unit is allotted to a landowner and cannot be booked")
+	}
+
+	from := matrixUnitStatus(rawStatus, booked)
+	if from != models.UnitStatusAvailable {
+		return "", fmt.Errorf("unit is %s and cannot be booked", from)
+	}
+	res, err := tx.Exec(`UPDATE property_units SET status = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND COALESCE(status, '') = ?`,
+		models.UnitStatusBooked, time.Now(), unitID, tenantID, rawStatus)
+	if err != nil {
+		return "", fmt.Errorf("failed to update unit status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", fmt.Errorf("unit status was changed by someone else")
+	}
+	return from, nil
+}
+
+// UnitStatusChanged records a unit's status change and pushes it to the tenant's
+// connected clients. Callers that change a unit's status themselves, such as
+// booking and cancellation, call it once their change is committed.
+func (s *UnitAvailabilityService) UnitStatusChanged(tenantID, userID, unitID, from, to, reason string) (*models.UnitStatusChange, error) {
+	change := &models.UnitStatusChange{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		UnitID:     unitID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  userID,
+		ChangedAt:  time.Now(),
+	}
+	if _, err := s.DB.Exec(`INSERT INTO unit_status_history
+		(id, tenant_id, unit_id, from_status, to_status, reason, changed_by, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		change.ID, tenantID, unitID, from, to, nullIfEmpty(reason), nullIfEmpty(userID), change.ChangedAt); err != nil {
+		return nil, fmt.Errorf("failed to record unit status change: %w", err)
+	}
+
+	if s.Hub != nil {
+		var projectID, blockID, unitNumber string
+		var floor int
+		if err := s.DB.QueryRow(`SELECT COALESCE(project_id, ''), COALESCE(block_id, ''), unit_number, COALESCE(floor, 0)
+			FROM property_units WHERE id = ? AND tenant_id = ?`, unitID, tenantID).Scan(
+			&projectID, &blockID, &unitNumber, &floor); err != nil {
+			return nil, fmt.Errorf("failed to get unit: %w", err)
+		}
+		s.Hub.BroadcastUnitStatus(tenantID, unitID, to, map[string]interface{}{
+			"project_id":  projectID,
+			"block_id":    blockID,
+			"unit_number": unitNumber,
+			"floor":       floor,
+			"from_status": from,
+		})
+	}
+	return change, nil
+}
+
+// ListStatusHistory lists a unit's status changes, latest first
+func (s *UnitAvailabilityService) ListStatusHistory(tenantID, unitID string) ([]models.UnitStatusChange, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, unit_id, from_status, to_status, COALESCE(reason, ''),
+		COALESCE(changed_by, ''), changed_at
+		FROM unit_status_history WHERE tenant_id = ? AND unit_id = ? ORDER BY changed_at DESC`, tenantID, unitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unit status history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.UnitStatusChange{}
+	for rows.Next() {
+		var c models.UnitStatusChange
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.UnitID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.ChangedBy,
+			&c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan unit status change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// matrixUnitStatus maps a unit's stored status onto the matrix statuses. Older
+// units carry reserved and sold, and a unit with an active booking shows booked
+// whatever its stored status says.
+func matrixUnitStatus(raw string, hasActiveBooking bool) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "held", "hold", "reserved":
+		return models.UnitStatusHeld
+	case "registered", "sold":
+		return models.UnitStatusRegistered
+	case "blocked":
+		return models.UnitStatusBlocked
+	case "booked", "allotted":
+		return models.UnitStatusBooked
+	}
+	if hasActiveBooking {
+		return models.UnitStatusBooked
+	}
+	return models.UnitStatusAvailable
+}
+
+func canChangeUnitStatus(from, to string) bool {
+	for _, allowed := range manualUnitTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// unitMatchesFilter applies the BHK, facing and price band filters to a unit
+func unitMatchesFilter(cell models.UnitMatrixCell, filter *models.UnitMatrixFilter) bool {
+	if filter.UnitType != "" && !strings.EqualFold(cell.UnitType, filter.UnitType) {
+		return false
+	}
+	if filter.Facing != "" && !strings.EqualFold(cell.Facing, filter.Facing) {
+		return false
+	}
+	if filter.MinPrice > 0 && cell.Price < filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice > 0 && cell.Price > filter.MaxPrice {
+		return false
+	}
+	return true
+}
+
+// buildAvailabilityMatrix lays units out per tower, top floor first, and totals
+// them by status per tower and for the project
+func buildAvailabilityMatrix(projectID string, cells []models.UnitMatrixCell, blockNames map[string]string) *models.UnitAvailabilityMatrix {
+	matrix := &models.UnitAvailabilityMatrix{ProjectID: projectID, Blocks: []models.BlockAvailability{}}
+
+	byBlock := map[string][]models.UnitMatrixCell{}
+	for _, c := range cells {
+		byBlock[c.BlockID] = append(byBlock[c.BlockID], c)
+	}
+	blockIDs := make([]string, 0, len(byBlock))
+	for id := range byBlock {
+		blockIDs = append(blockIDs, id)
+	}
+	sort.Slice(blockIDs, func(i, j int) bool {
+		if blockNames[blockIDs[i]] != blockNames[blockIDs[j]] {
+			return blockNames[blockIDs[i]] < blockNames[blockIDs[j]]
+		}
+		return blockIDs[i] < blockIDs[j]
+	})
+
+	for _, id := range blockIDs {
+		units := byBlock[id]
+		block := models.BlockAvailability{BlockID: id, BlockName: blockNames[id], Summary: summarizeUnitStatus(units)}
+		byFloor := map[int][]models.UnitMatrixCell{}
+		floors := []int{}
+		for _, u := range units {
+			if _, ok := byFloor[u.Floor]; !ok {
+				floors = append(floors, u.Floor)
+			}
+			byFloor[u.Floor] = append(byFloor[u.Floor], u)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(floors)))
+		for _, f := range floors {
+			row := byFloor[f]
+			sort.Slice(row, func(i, j int) bool { return row[i].UnitNumber < row[j].UnitNumber })
+			block.Floors = append(block.Floors, models.UnitMatrixFloor{Floor: f, Units: row})
+		}
+		matrix.Blocks = append(matrix.Blocks, block)
+	}
+	matrix.Summary = summarizeUnitStatus(cells)
+	return matrix
+}
+
+// summarizeUnitStatus totals units by status, listing every status in display order
+func summarizeUnitStatus(cells []models.UnitMatrixCell) []models.UnitStatusSummary {
+	totals := map[string]*models.UnitStatusSummary{}
+	summary := make([]models.UnitStatusSummary, len(models.UnitMatrixStatuses))
+	for i, status := range models.UnitMatrixStatuses {
+		summary[i].Status = status
+		totals[status] = &summary[i]
+	}
+	for _, c := range cells {
+		t := totals[c.Status]
+		t.Count++
+		t.Area = roundTo2(t.Area + c.SBUA)
+		t.Value = roundTo2(t.Value + c.Price)
+	}
+	return summary
+}
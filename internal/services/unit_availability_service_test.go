@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatrixUnitStatus tests mapping stored unit statuses onto the matrix
+func TestMatrixUnitStatus(t *testing.T) {
+	assert.Equal(t, models.UnitStatusHeld, matrixUnitStatus("reserved", false))
+	assert.Equal(t, models.UnitStatusRegistered, matrixUnitStatus("sold", true))
+	assert.Equal(t, models.UnitStatusBooked, matrixUnitStatus("available", true))
+	assert.Equal(t, models.UnitStatusBlocked, matrixUnitStatus("Blocked", false))
+	assert.Equal(t, models.UnitStatusAvailable, matrixUnitStatus("", false))
+
+	assert.True(t, canChangeUnitStatus(models.UnitStatusAvailable, models.UnitStatusHeld))
+	assert.True(t, canChangeUnitStatus(models.UnitStatusBooked, models.UnitStatusRegistered))
+	assert.False(t, canChangeUnitStatus(models.UnitStatusBooked, models.UnitStatusAvailable))
+	assert.False(t, canChangeUnitStatus(models.UnitStatusAvailable, models.UnitStatusBooked))
+}
+
+// TestBuildAvailabilityMatrix tests the grid layout, filters and status totals
+func TestBuildAvailabilityMatrix(t *testing.T) {
+	cells := []models.UnitMatrixCell{
+		{UnitID: "u1", UnitNumber: "A-101", BlockID: "b1", Floor: 1, UnitType: "2BHK", Facing: "east", SBUA: 1200, Status: models.UnitStatusAvailable, Price: 6000000},
+		{UnitID: "u2", UnitNumber: "A-102", BlockID: "b1", Floor: 1, UnitType: "3BHK", Facing: "west", SBUA: 1600, Status: models.UnitStatusBooked, Price: 8200000},
+		{UnitID: "u3", UnitNumber: "A-201", BlockID: "b1", Floor: 2, UnitType: "2BHK", Facing: "east", SBUA: 1200, Status: models.UnitStatusHeld, Price: 6100000},
+		{UnitID: "u4", UnitNumber: "B-101", BlockID: "b2", Floor: 1, UnitType: "2BHK", Facing: "north", SBUA: 1250, Status: models.UnitStatusAvailable, Price: 6300000},
+	}
+
+	m := buildAvailabilityMatrix("p1", cells, map[string]string{"b1": "Tower A", "b2": "Tower B"})
+	assert.Len(t, m.Blocks, 2)
+	assert.Equal(t, "Tower A", m.Blocks[0].BlockName)
+	assert.Equal(t, 2, m.Blocks[0].Floors[0].Floor) // top floor first
+	assert.Equal(t, []string{"A-101", "A-102"}, []string{m.Blocks[0].Floors[1].Units[0].UnitNumber, m.Blocks[0].Floors[1].Units[1].UnitNumber})
+
+	assert.Len(t, m.Summary, len(models.UnitMatrixStatuses))
+	assert.Equal(t, models.UnitStatusAvailable, m.Summary[0].Status)
+	assert.Equal(t, 2, m.Summary[0].Count)
+	assert.Equal(t, 12300000.0, m.Summary[0].Value)
+	assert.Equal(t, 2450.0, m.Summary[0].Area)
+	assert.Equal(t, 0, m.Summary[3].Count) // registered is listed even when empty
+
+	filter := &models.UnitMatrixFilter{UnitType: "2bhk", MaxPrice: 6200000}
+	matched := []string{}
+	for _, c := range cells {
+		if unitMatchesFilter(c, filter) {
+			matched = append(matched, c.UnitID)
+		}
+	}
+	assert.Equal(t, []string{"u1", "u3"}, matched)
+
+	assert.False(t, unitMatchesFilter(cells[0], &models.UnitMatrixFilter{Facing: "west"}))
+	assert.False(t, unitMatchesFilter(cells[0], &models.UnitMatrixFilter{MinPrice: 7000000}))
+}
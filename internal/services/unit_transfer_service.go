@@ -0,0 +1,760 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// UNIT TRANSFER SERVICE
+// ============================================================================
+
+// UnitTransferService endorses a booking from its applicants to new buyers on a
+// resale: it computes the dues to settle and the transfer fee, and once both are
+// cleared replaces the co-owners, re-points the customer ledger and issues the
+// endorsement letter.
+type UnitTransferService struct {
+	DB              *sql.DB
+	GL              *GLService
+	PaymentPlans    *PaymentPlanService
+	JointApplicants *JointApplicantService
+	Documents       *DocumentService
+}
+
+// NewUnitTransferService creates a new unit transfer service
+func NewUnitTransferService(db *sql.DB, gl *GLService, paymentPlans *PaymentPlanService, jointApplicants *JointApplicantService, documents *DocumentService) *UnitTransferService {
+	return &UnitTransferService{DB: db, GL: gl, PaymentPlans: paymentPlans, JointApplicants: jointApplicants, Documents: documents}
+}
+
+// ============================================================================
+// TRANSFER FEE POLICIES
+// ============================================================================
+
+// SetFeePolicy creates or replaces the transfer fee for a project, or tenant-wide
+func (s *UnitTransferService) SetFeePolicy(tenantID string, req *models.SetTransferFeePolicyRequest) (*models.TransferFeePolicy, error) {
+	switch req.ChargeType {
+	case models.TransferFeePerSqft, models.TransferFeeLumpsum:
+	case models.TransferFeePercent:
+		if req.Amount > 100 {
+			return nil, fmt.Errorf("a percentage fee cannot exceed 100")
+		}
+	default:
+		return nil, fmt.Errorf("charge_type must be per_sqft, lumpsum or percent")
+	}
+	if req.Amount < 0 || req.GSTRate < 0 {
+		return nil, fmt.Errorf("amount and gst_rate cannot be negative")
+	}
+
+	now := time.Now()
+	policy := &models.TransferFeePolicy{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		ChargeType: req.ChargeType,
+		Amount:     req.Amount,
+		GSTRate:    req.GSTRate,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if req.ProjectID != "" {
+		policy.ProjectID = &req.ProjectID
+	}
+
+	if _, err := s.DB.Exec(`INSERT INTO transfer_fee_policies
+		(id, tenant_id, project_id, charge_type, amount, gst_rate, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE charge_type = VALUES(charge_type), amount = VALUES(amount),
+			gst_rate = VALUES(gst_rate), updated_at = VALUES(updated_at)`,
+		policy.ID, tenantID, req.ProjectID, policy.ChargeType, policy.Amount, policy.GSTRate,
+		policy.CreatedAt, policy.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to set transfer fee policy: %w", err)
+	}
+	return policy, nil
+}
+
+// ListFeePolicies lists the tenant's transfer fees
+func (s *UnitTransferService) ListFeePolicies(tenantID string) ([]models.TransferFeePolicy, error) {
+	return s.getFeePolicies(tenantID, "1 = 1")
+}
+
+// feePolicyFor returns the project's transfer fee, falling back to the tenant-wide one
+func (s *UnitTransferService) feePolicyFor(tenantID, projectID string) (*models.TransferFeePolicy, error) {
+	policies, err := s.getFeePolicies(tenantID, "project_id IN ('', ?)", projectID)
+	if err != nil {
+		return nil, err
+	}
+	var policy *models.TransferFeePolicy
+	for i := range policies {
+		if policy == nil || policies[i].ProjectID != nil {
+			policy = &policies[i]
+		}
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("no transfer fee policy configured for this project")
+	}
+	return policy, nil
+}
+
+func (s *UnitTransferService) getFeePolicies(tenantID, where string, args ...interface{}) ([]models.TransferFeePolicy, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, project_id, charge_type, amount, gst_rate, created_at, updated_at
+		FROM transfer_fee_policies WHERE tenant_id = ? AND `+where+` ORDER BY project_id`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfer fee policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []models.TransferFeePolicy{}
+	for rows.Next() {
+		var p models.TransferFeePolicy
+		var project string
+		if err := rows.Scan(&p.ID, &p.TenantID, &project, &p.ChargeType, &p.Amount, &p.GSTRate,
+			&p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer fee policy: %w", err)
+		}
+		if project != "" {
+			p.ProjectID = &project
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// ============================================================================
+// TRANSFER REQUESTS
+// ============================================================================
+
+// transferBooking is the booking being transferred with what the fee is charged on
+type transferBooking struct {
+	UnitID           string
+	ProjectID        string
+	CustomerID       string
+	Status           string
+	AgreementValue   float64
+	SBUA             float64
+	BookingReference string
+	UnitNumber       string
+	ProjectName      string
+}
+
+func (s *UnitTransferService) getTransferBooking(tenantID, bookingID string) (*transferBooking, error) {
+	var b transferBooking
+	// The price locked on the booking wins over the unit's legacy cost sheet
+	err := s.DB.QueryRow(`SELECT b.unit_id, COALESCE(u.project_id, ''), COALESCE(b.customer_id, ''), b.booking_status,
+		COALESCE(bpl.agreement_value, ucs.apartment_cost_exc_govt, 0), COALESCE(u.sbua, 0),
+		COALESCE(b.booking_reference, ''), COALESCE(u.unit_number, ''), COALESCE(p.project_name, '')
+		FROM customer_bookings b
+		LEFT JOIN property_units u ON u.id = b.unit_id
+		LEFT JOIN property_projects p ON p.id = u.project_id
+		LEFT JOIN booking_price_locks bpl ON bpl.booking_id = b.id AND bpl.tenant_id = b.tenant_id
+		LEFT JOIN unit_cost_sheet ucs ON ucs.unit_id = b.unit_id AND ucs.tenant_id = b.tenant_id
+		WHERE b.id = ? AND b.tenant_id = ? AND b.deleted_at IS NULL`,
+		bookingID, tenantID).Scan(&b.UnitID, &b.ProjectID, &b.CustomerID, &b.Status, &b.AgreementValue, &b.SBUA,
+		&b.BookingReference, &b.UnitNumber, &b.ProjectName)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	return &b, nil
+}
+
+// RequestTransfer records a transfer request with the dues to settle and the fee payable
+func (s *UnitTransferService) RequestTransfer(tenantID, userID string, req *models.CreateTransferRequest) (*models.UnitTransfer, error) {
+	if req.NewCustomerID == "" {
+		return nil, fmt.Errorf("new_customer_id is required")
+	}
+	transferees, err := normalizeTransferees(req.Transferees)
+	if err != nil {
+		return nil, err
+	}
+	ownershipType := req.OwnershipType
+	if ownershipType == "" {
+		ownershipType = "joint_tenant"
+	}
+
+	var open int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM unit_transfers
+		WHERE tenant_id = ? AND booking_id = ? AND status IN (?, ?)`,
+		tenantID, req.BookingID, models.TransferStatusRequested, models.TransferStatusApproved).Scan(&open); err != nil {
+		return nil, fmt.Errorf("failed to check existing transfers: %w", err)
+	}
+	if open > 0 {
+		return nil, fmt.Errorf("booking already has an open transfer")
+	}
+
+	booking, err := s.getTransferBooking(tenantID, req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != "active" {
+		return nil, fmt.Errorf("booking is %s", booking.Status)
+	}
+	if booking.CustomerID == req.NewCustomerID {
+		return nil, fmt.Errorf("the booking already belongs to this customer")
+	}
+
+	policy, err := s.feePolicyFor(tenantID, booking.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	dues, err := s.PaymentPlans.bookingOutstanding(tenantID, req.BookingID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	transferors, err := s.JointApplicants.BookingApplicants(tenantID, req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fee, gst := computeTransferFee(policy, booking.SBUA, booking.AgreementValue)
+	t := &models.UnitTransfer{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		BookingID:       req.BookingID,
+		UnitID:          booking.UnitID,
+		ProjectID:       booking.ProjectID,
+		NewCustomerID:   &req.NewCustomerID,
+		OwnershipType:   ownershipType,
+		AgreementValue:  booking.AgreementValue,
+		DuesOutstanding: roundTo2(math.Max(dues, 0)),
+		TransferFee:     fee,
+		TransferFeeGST:  gst,
+		Status:          models.TransferStatusRequested,
+		Reason:          req.Reason,
+		RequestedBy:     userID,
+		Parties:         append(transferors, transferees...),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if booking.CustomerID != "" {
+		t.OldCustomerID = &booking.CustomerID
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO unit_transfers
+		(id, tenant_id, booking_id, unit_id, project_id, old_customer_id, new_customer_id, ownership_type,
+		 agreement_value, dues_outstanding, transfer_fee, transfer_fee_gst, status, reason, requested_by,
+		 created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, tenantID, t.BookingID, t.UnitID, nullIfEmpty(t.ProjectID), t.OldCustomerID, t.NewCustomerID,
+		t.OwnershipType, t.AgreementValue, t.DuesOutstanding, t.TransferFee, t.TransferFeeGST, t.Status,
+		t.Reason, t.RequestedBy, t.CreatedAt, t.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+	for _, p := range t.Parties {
+		if _, err := tx.Exec(`INSERT INTO unit_transfer_parties
+			(id, tenant_id, transfer_id, role, name, email, phone, pan, id_type, id_number, address,
+			 share_percent, is_primary, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), tenantID, t.ID, p.Role, p.Name, p.Email, p.Phone, p.PAN, p.IDType, p.IDNumber,
+			p.Address, p.SharePercent, p.IsPrimary, now); err != nil {
+			return nil, fmt.Errorf("failed to record transfer party: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return t, nil
+}
+
+// DecideTransfer approves or rejects a transfer. Approval raises the transfer fee
+// and its GST on the booking's customer ledger.
+func (s *UnitTransferService) DecideTransfer(tenantID, userID, transferID string, req *models.TransferDecisionRequest) (*models.UnitTransfer, error) {
+	t, err := s.GetTransfer(tenantID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != models.TransferStatusRequested {
+		return nil, fmt.Errorf("transfer is already %s", t.Status)
+	}
+	if t.RequestedBy == userID {
+		return nil, fmt.Errorf("a transfer cannot be approved by the person who requested it")
+	}
+	if !req.Approve && strings.TrimSpace(req.Comment) == "" {
+		return nil, fmt.Errorf("a comment is required when rejecting")
+	}
+
+	status := models.TransferStatusRejected
+	if req.Approve {
+		status = models.TransferStatusApproved
+	}
+	now := time.Now()
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE unit_transfers SET status = ?, approved_by = ?, approved_at = ?, decision_comment = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ?`,
+		status, userID, now, req.Comment, now, t.ID, tenantID, models.TransferStatusRequested)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide transfer: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("transfer was decided by someone else")
+	}
+
+	if total := roundTo2(t.TransferFee + t.TransferFeeGST); req.Approve && total > 0 {
+		if _, err := insertCustomerLedgerEntry(tx, tenantID, t.BookingID, derefString(t.OldCustomerID), "debit",
+			fmt.Sprintf("Transfer fee %.2f + GST %.2f", t.TransferFee, t.TransferFeeGST), total, t.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer decision: %w", err)
+	}
+	return s.GetTransfer(tenantID, transferID)
+}
+
+// RecordFeeReceipt records the transfer fee received and posts it to the GL
+func (s *UnitTransferService) RecordFeeReceipt(tenantID, userID, transferID string, req *models.RecordTransferFeeRequest) (*models.UnitTransfer, error) {
+	if req.Reference == "" {
+		return nil, fmt.Errorf("reference is required")
+	}
+	receivedOn := time.Now()
+	if req.ReceivedOn != "" {
+		d, err := time.Parse("2006-01-02", req.ReceivedOn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid received_on date")
+		}
+		receivedOn = d
+	}
+
+	t, err := s.GetTransfer(tenantID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != models.TransferStatusApproved {
+		return nil, fmt.Errorf("the transfer fee can only be received on an approved transfer")
+	}
+	total := roundTo2(t.TransferFee + t.TransferFeeGST)
+	if total <= 0 {
+		return nil, fmt.Errorf("no transfer fee is payable")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entryID := fmt.Sprintf("JE-UTF-%s", t.ID)
+	res, err := tx.Exec(`UPDATE unit_transfers SET fee_receipt_reference = ?, fee_paid_at = ?, fee_journal_entry_id = ?,
+		updated_at = ? WHERE id = ? AND tenant_id = ? AND status = ? AND fee_paid_at IS NULL`,
+		req.Reference, receivedOn, entryID, time.Now(), t.ID, tenantID, models.TransferStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record transfer fee: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("transfer fee was already recorded")
+	}
+	if _, err := insertCustomerLedgerEntry(tx, tenantID, t.BookingID, derefString(t.OldCustomerID), "credit",
+		fmt.Sprintf("Transfer fee received - %s", req.Reference), total, req.Reference); err != nil {
+		return nil, err
+	}
+
+	reference := req.Reference
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       receivedOn,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Unit_Transfer_Fee",
+		ReferenceID:     &t.ID,
+		Description:     fmt.Sprintf("Transfer fee on booking %s", t.BookingID),
+		Amount:          total,
+		Narration:       fmt.Sprintf("Received vide %s", req.Reference),
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-BANK-CASH", DebitAmount: total, Description: fmt.Sprintf("Transfer fee %s", req.Reference)},
+		{AccountID: "ACC-TRANSFER-FEE-INCOME", CreditAmount: t.TransferFee, Description: "Transfer fee"},
+	}
+	if t.TransferFeeGST > 0 {
+		lines = append(lines, models.JournalEntryDetail{AccountID: "ACC-OUTPUT-TAX", CreditAmount: t.TransferFeeGST,
+			Description: "GST on transfer fee"})
+	}
+	if err := s.GL.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer fee: %w", err)
+	}
+
+	return s.GetTransfer(tenantID, transferID)
+}
+
+// CompleteTransfer endorses the booking to the transferees once the dues are
+// settled and the fee received: the co-owners and co-ownership agreement are
+// replaced, the booking and its ledger re-pointed to the new customer, and the
+// endorsement letter generated
+func (s *UnitTransferService) CompleteTransfer(tenantID, userID, transferID string) (*models.UnitTransfer, error) {
+	t, err := s.GetTransfer(tenantID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != models.TransferStatusApproved {
+		return nil, fmt.Errorf("only an approved transfer can be completed")
+	}
+	if t.TransferFee+t.TransferFeeGST > 0 && t.FeePaidAt == nil {
+		return nil, fmt.Errorf("transfer fee has not been received")
+	}
+	dues, err := s.PaymentPlans.bookingOutstanding(tenantID, t.BookingID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if dues = roundTo2(dues); dues > 0 {
+		return nil, fmt.Errorf("dues of %.2f are outstanding on the booking", dues)
+	}
+	booking, err := s.getTransferBooking(tenantID, t.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != "active" {
+		return nil, fmt.Errorf("booking is %s", booking.Status)
+	}
+
+	transferors, transferees := []models.TransferParty{}, []models.TransferParty{}
+	for _, p := range t.Parties {
+		if p.Role == models.TransferPartyTransferee {
+			transferees = append(transferees, p)
+		} else {
+			transferors = append(transferors, p)
+		}
+	}
+	if len(transferees) == 0 {
+		return nil, fmt.Errorf("transfer has no transferees")
+	}
+	now := time.Now()
+	newCustomerID := derefString(t.NewCustomerID)
+	reason := fmt.Sprintf("Transferred under unit transfer %s", t.ID)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	agreementRef, err := s.JointApplicants.ReplaceApplicants(tx, tenantID, t.BookingID, reason, transferees,
+		t.OwnershipType, t.AgreementValue, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE customer_bookings SET customer_id = ?, updated_at = ? WHERE id = ? AND tenant_id = ?`,
+		newCustomerID, now, t.BookingID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to re-point booking: %w", err)
+	}
+	// Demand letters and receipts are addressed from the booking's customer details
+	primary := transferees[0]
+	if _, err := tx.Exec(`UPDATE customer_details SET primary_name = ?, primary_email = ?, primary_phone = ?,
+		primary_pan_no = ? WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL`,
+		primary.Name, primary.Email, primary.Phone, primary.PAN, tenantID, t.BookingID); err != nil {
+		return nil, fmt.Errorf("failed to update customer details: %w", err)
+	}
+
+	moved, err := repointCustomerLedger(tx, tenantID, t.BookingID, t.ID, newCustomerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeReference := ""
+	if t.FeeReceiptRef != nil {
+		feeReference = *t.FeeReceiptRef
+	}
+	letter, err := s.Documents.RenderTemplate(tenantID, models.TransferTemplateType, map[string]string{
+		"date":                now.Format("02 Jan 2006"),
+		"booking_reference":   booking.BookingReference,
+		"unit_number":         booking.UnitNumber,
+		"project_name":        booking.ProjectName,
+		"transferors":         partyNames(transferors),
+		"transferees":         partyNames(transferees),
+		"transfer_fee":        fmt.Sprintf("%.2f", t.TransferFee),
+		"transfer_fee_gst":    fmt.Sprintf("%.2f", t.TransferFeeGST),
+		"fee_reference":       feeReference,
+		"ownership_type":      strings.ReplaceAll(t.OwnershipType, "_", " "),
+		"agreement_reference": agreementRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec(`UPDATE unit_transfers SET status = ?, dues_outstanding = 0, agreement_reference = ?,
+		ledger_entries_moved = ?, endorsement_letter = ?, completed_at = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ? AND status = ?`,
+		models.TransferStatusCompleted, agreementRef, moved, letter, now, now, t.ID, tenantID,
+		models.TransferStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete transfer: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("transfer was completed by someone else")
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return s.GetTransfer(tenantID, transferID)
+}
+
+// repointCustomerLedger moves the booking's ledger entries to the new customer,
+// recording each change, and returns how many entries moved
+func repointCustomerLedger(tx *sql.Tx, tenantID, bookingID, transferID, newCustomerID, userID string) (int, error) {
+	rows, err := tx.Query(`SELECT id, customer_id FROM customer_account_ledgers
+		WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+		AND (customer_id IS NULL OR customer_id <> ?)`, tenantID, bookingID, newCustomerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	type entry struct {
+		id       string
+		customer sql.NullString
+	}
+	entries := []entry{}
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.id, &e.customer); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, e := range entries {
+		if _, err := tx.Exec(`INSERT INTO unit_transfer_ledger_audit
+			(id, tenant_id, transfer_id, ledger_entry_id, old_customer_id, new_customer_id, changed_by, changed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), tenantID, transferID, e.id, e.customer, newCustomerID, userID, now); err != nil {
+			return 0, fmt.Errorf("failed to audit ledger entry: %w", err)
+		}
+	}
+	if len(entries) > 0 {
+		if _, err := tx.Exec(`UPDATE customer_account_ledgers SET customer_id = ?, updated_at = ?
+			WHERE tenant_id = ? AND booking_id = ? AND deleted_at IS NULL
+			AND (customer_id IS NULL OR customer_id <> ?)`,
+			newCustomerID, now, tenantID, bookingID, newCustomerID); err != nil {
+			return 0, fmt.Errorf("failed to re-point ledger: %w", err)
+		}
+	}
+	return len(entries), nil
+}
+
+// GetTransfer returns a transfer with its parties
+func (s *UnitTransferService) GetTransfer(tenantID, transferID string) (*models.UnitTransfer, error) {
+	list, err := s.getTransfers(tenantID, "id = ?", transferID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("transfer not found")
+	}
+	parties, err := s.getTransferParties(tenantID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	list[0].Parties = parties
+	return &list[0], nil
+}
+
+// ListTransfers lists transfers, optionally by status and booking
+func (s *UnitTransferService) ListTransfers(tenantID, status, bookingID string) ([]models.UnitTransfer, error) {
+	where, args := "1 = 1", []interface{}{}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	if bookingID != "" {
+		where += " AND booking_id = ?"
+		args = append(args, bookingID)
+	}
+	return s.getTransfers(tenantID, where, args...)
+}
+
+// GetLedgerAudit lists the ledger entries a transfer re-pointed
+func (s *UnitTransferService) GetLedgerAudit(tenantID, transferID string) ([]models.TransferLedgerAudit, error) {
+	rows, err := s.DB.Query(`SELECT id, transfer_id, ledger_entry_id, old_customer_id, new_customer_id, changed_by,
+		changed_at FROM unit_transfer_ledger_audit WHERE tenant_id = ? AND transfer_id = ? ORDER BY changed_at`,
+		tenantID, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger audit: %w", err)
+	}
+	defer rows.Close()
+
+	audit := []models.TransferLedgerAudit{}
+	for rows.Next() {
+		var a models.TransferLedgerAudit
+		var oldCustomer, newCustomer sql.NullString
+		if err := rows.Scan(&a.ID, &a.TransferID, &a.LedgerEntryID, &oldCustomer, &newCustomer, &a.ChangedBy,
+			&a.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger audit: %w", err)
+		}
+		a.OldCustomerID = nullStringPtr(oldCustomer)
+		a.NewCustomerID = nullStringPtr(newCustomer)
+		audit = append(audit, a)
+	}
+	return audit, rows.Err()
+}
+
+func (s *UnitTransferService) getTransfers(tenantID, where string, args ...interface{}) ([]models.UnitTransfer, error) {
+	rows, err := s.DB.Query(`SELECT id, tenant_id, booking_id, unit_id, COALESCE(project_id, ''), old_customer_id,
+		new_customer_id, ownership_type, agreement_value, dues_outstanding, transfer_fee, transfer_fee_gst,
+		fee_receipt_reference, fee_paid_at, fee_journal_entry_id, status, COALESCE(reason, ''), requested_by,
+		approved_by, approved_at, COALESCE(decision_comment, ''), agreement_reference, ledger_entries_moved,
+		COALESCE(endorsement_letter, ''), completed_at, created_at, updated_at
+		FROM unit_transfers WHERE tenant_id = ? AND `+where+` ORDER BY created_at DESC`,
+		append([]interface{}{tenantID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := []models.UnitTransfer{}
+	for rows.Next() {
+		var t models.UnitTransfer
+		var oldCustomer, newCustomer, feeRef, feeEntry, approvedBy, agreementRef sql.NullString
+		var feePaidAt, approvedAt, completedAt sql.NullTime
+		if err := rows.Scan(&t.ID, &t.TenantID, &t.BookingID, &t.UnitID, &t.ProjectID, &oldCustomer, &newCustomer,
+			&t.OwnershipType, &t.AgreementValue, &t.DuesOutstanding, &t.TransferFee, &t.TransferFeeGST, &feeRef,
+			&feePaidAt, &feeEntry, &t.Status, &t.Reason, &t.RequestedBy, &approvedBy, &approvedAt,
+			&t.DecisionComment, &agreementRef, &t.LedgerEntriesMoved, &t.EndorsementLetter, &completedAt,
+			&t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		t.OldCustomerID = nullStringPtr(oldCustomer)
+		t.NewCustomerID = nullStringPtr(newCustomer)
+		t.FeeReceiptRef = nullStringPtr(feeRef)
+		t.FeeJournalEntryID = nullStringPtr(feeEntry)
+		t.ApprovedBy = nullStringPtr(approvedBy)
+		t.AgreementReference = nullStringPtr(agreementRef)
+		if feePaidAt.Valid {
+			t.FeePaidAt = &feePaidAt.Time
+		}
+		if approvedAt.Valid {
+			t.ApprovedAt = &approvedAt.Time
+		}
+		if completedAt.Valid {
+			t.CompletedAt = &completedAt.Time
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+func (s *UnitTransferService) getTransferParties(tenantID, transferID string) ([]models.TransferParty, error) {
+	rows, err := s.DB.Query(`SELECT role, name, email, phone, pan, id_type, id_number, address, share_percent,
+		is_primary FROM unit_transfer_parties WHERE tenant_id = ? AND transfer_id = ?
+		ORDER BY role DESC, is_primary DESC, created_at`, tenantID, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfer parties: %w", err)
+	}
+	defer rows.Close()
+
+	parties := []models.TransferParty{}
+	for rows.Next() {
+		var p models.TransferParty
+		if err := rows.Scan(&p.Role, &p.Name, &p.Email, &p.Phone, &p.PAN, &p.IDType, &p.IDNumber, &p.Address,
+			&p.SharePercent, &p.IsPrimary); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer party: %w", err)
+		}
+		parties = append(parties, p)
+	}
+	return parties, rows.Err()
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+// computeTransferFee returns the transfer fee and its GST under the policy
+func computeTransferFee(policy *models.TransferFeePolicy, sbua, agreementValue float64) (float64, float64) {
+	var fee float64
+	switch policy.ChargeType {
+	case models.TransferFeePerSqft:
+		fee = policy.Amount * sbua
+	case models.TransferFeePercent:
+		fee = agreementValue * policy.Amount / 100
+	default:
+		fee = policy.Amount
+	}
+	fee = roundTo2(fee)
+	return fee, roundTo2(fee * policy.GSTRate / 100)
+}
+
+// normalizeTransferees checks the new buyers' shares add up to the whole unit and
+// makes sure exactly one of them is the primary applicant, listed first
+func normalizeTransferees(parties []models.TransferParty) ([]models.TransferParty, error) {
+	if len(parties) == 0 {
+		return nil, fmt.Errorf("at least one transferee is required")
+	}
+	total, primary := 0.0, -1
+	for i, p := range parties {
+		if strings.TrimSpace(p.Name) == "" {
+			return nil, fmt.Errorf("transferee %d has no name", i+1)
+		}
+		if p.SharePercent <= 0 {
+			return nil, fmt.Errorf("transferee %s must hold a share", p.Name)
+		}
+		if p.IsPrimary {
+			if primary >= 0 {
+				return nil, fmt.Errorf("only one transferee can be the primary applicant")
+			}
+			primary = i
+		}
+		total += p.SharePercent
+	}
+	if math.Abs(total-100) > 0.01 {
+		return nil, fmt.Errorf("transferee shares add up to %.2f%%, not 100%%", total)
+	}
+	if primary < 0 {
+		primary = 0
+	}
+
+	out := make([]models.TransferParty, 0, len(parties))
+	for i, p := range parties {
+		p.Role = models.TransferPartyTransferee
+		p.IsPrimary = i == primary
+		if p.IsPrimary {
+			out = append([]models.TransferParty{p}, out...)
+		} else {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func partyNames(parties []models.TransferParty) string {
+	names := make([]string, len(parties))
+	for i, p := range parties {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComputeTransferFee tests each fee charge type and its GST
+func TestComputeTransferFee(t *testing.T) {
+	fee, gst := computeTransferFee(&models.TransferFeePolicy{ChargeType: models.TransferFeePerSqft, Amount: 150, GSTRate: 18}, 1250, 6000000)
+	assert.Equal(t, 187500.0, fee)
+	assert.Equal(t, 33750.0, gst)
+
+	fee, gst = computeTransferFee(&models.TransferFeePolicy{ChargeType: models.TransferFeePercent, Amount: 1.5, GSTRate: 18}, 1250, 6000000)
+	assert.Equal(t, 90000.0, fee)
+	assert.Equal(t, 16200.0, gst)
+
+	fee, gst = computeTransferFee(&models.TransferFeePolicy{ChargeType: models.TransferFeeLumpsum, Amount: 50000}, 1250, 6000000)
+	assert.Equal(t, 50000.0, fee)
+	assert.Equal(t, 0.0, gst)
+}
+
+// TestNormalizeTransferees tests share validation and primary applicant selection
+func TestNormalizeTransferees(t *testing.T) {
+	parties, err := normalizeTransferees([]models.TransferParty{
+		{Name: "Asha Rao", SharePercent: 40},
+		{Name: "Vikram Rao", SharePercent: 60, IsPrimary: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Vikram Rao", parties[0].Name)
+	assert.True(t, parties[0].IsPrimary)
+	assert.False(t, parties[1].IsPrimary)
+	assert.Equal(t, models.TransferPartyTransferee, parties[1].Role)
+
+	// The first transferee is primary when none is marked
+	parties, err = normalizeTransferees([]models.TransferParty{{Name: "Asha Rao", SharePercent: 100}})
+	assert.NoError(t, err)
+	assert.True(t, parties[0].IsPrimary)
+
+	_, err = normalizeTransferees([]models.TransferParty{{Name: "Asha Rao", SharePercent: 50}, {Name: "Vikram Rao", SharePercent: 40}})
+	assert.Error(t, err)
+
+	_, err = normalizeTransferees([]models.TransferParty{
+		{Name: "Asha Rao", SharePercent: 50, IsPrimary: true},
+		{Name: "Vikram Rao", SharePercent: 50, IsPrimary: true},
+	})
+	assert.Error(t, err)
+
+	_, err = normalizeTransferees(nil)
+	assert.Error(t, err)
+}
+
+// TestRenderTemplate tests placeholder substitution in the endorsement letter
+func TestRenderTemplate(t *testing.T) {
+	letter := renderTemplate(defaultTemplates[models.TransferTemplateType], map[string]string{
+		"unit_number": "A-1203",
+		"transferors": "Ravi Kumar",
+		"transferees": "Asha Rao, Vikram Rao",
+	})
+	assert.Contains(t, letter, "unit A-1203")
+	assert.Contains(t, letter, "from Ravi Kumar to Asha Rao, Vikram Rao")
+	assert.Contains(t, letter, "{{agreement_reference}}")
+}
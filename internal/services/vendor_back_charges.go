@@ -0,0 +1,191 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"vyomtech-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// VENDOR BACK-CHARGES
+// ============================================================================
+// A back-charge recovers a cost caused by a vendor (e.g. rectifying a
+// contractor's defect) by setting it off against one of their open invoices.
+// It reduces the invoice balance picked up by AP ageing and payment runs and
+// moves the amount from accounts payable to back-charge recoveries in the GL.
+
+// RecordVendorBackCharge sets charge.Amount off against the vendor's open invoice.
+// VendorID, InvoiceID, Amount and Reason must be set; SnagID is optional.
+func (s *PurchaseService) RecordVendorBackCharge(tenantID string, charge *models.VendorBackCharge, glService *GLService, userID string) error {
+	charge.Amount = roundTo2(charge.Amount)
+	if charge.Amount <= 0 {
+		return fmt.Errorf("back-charge amount must be positive")
+	}
+	if strings.TrimSpace(charge.Reason) == "" {
+		return fmt.Errorf("back-charge reason is required")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var vendorID, status string
+	err = tx.QueryRow(`SELECT vi.invoice_number, vi.vendor_id, COALESCE(v.name, ''), COALESCE(vi.project_id, ''), vi.status
+		FROM vendor_invoices vi
+		LEFT JOIN vendors v ON v.id = vi.vendor_id
+		WHERE vi.id = ? AND vi.tenant_id = ? FOR UPDATE`, charge.InvoiceID, tenantID).Scan(
+		&charge.InvoiceNumber, &vendorID, &charge.VendorName, &charge.ProjectID, &status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("vendor invoice not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get vendor invoice: %w", err)
+	}
+	if charge.VendorID != "" && charge.VendorID != vendorID {
+		return fmt.Errorf("invoice %s is not from this vendor", charge.InvoiceNumber)
+	}
+	charge.VendorID = vendorID
+	if !isPayableInvoiceStatus(status) {
+		return fmt.Errorf("invoice %s is %s and has no open payable to set off against", charge.InvoiceNumber, status)
+	}
+	if err := validateBackCharge(charge.Amount, invoiceBalance(tx, tenantID, charge.InvoiceID)); err != nil {
+		return err
+	}
+
+	charge.ID = uuid.New().String()
+	charge.TenantID = tenantID
+	charge.BackChargeNumber = interestNoteNumber("BC")
+	charge.Status = models.VendorBackChargeApplied
+	charge.CreatedBy = optionalString(userID)
+	charge.CreatedAt = time.Now()
+	entryID := fmt.Sprintf("JE-PO-BC-%s", charge.ID)
+	charge.JournalEntryID = &entryID
+
+	if _, err := tx.Exec(`INSERT INTO vendor_back_charges (
+		id, tenant_id, back_charge_number, vendor_id, invoice_id, project_id, snag_id, amount, reason,
+		status, journal_entry_id, created_by, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		charge.ID, tenantID, charge.BackChargeNumber, charge.VendorID, charge.InvoiceID, nullIfEmpty(charge.ProjectID),
+		charge.SnagID, charge.Amount, charge.Reason, charge.Status, entryID, charge.CreatedBy, charge.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record back-charge: %w", err)
+	}
+	if invoiceBalance(tx, tenantID, charge.InvoiceID) <= 0.005 {
+		if _, err := tx.Exec(`UPDATE vendor_invoices SET status = 'Paid', updated_at = ? WHERE id = ? AND tenant_id = ?`,
+			time.Now(), charge.InvoiceID, tenantID); err != nil {
+			return fmt.Errorf("failed to update invoice status: %w", err)
+		}
+	}
+
+	reference := charge.InvoiceNumber
+	entry := &models.JournalEntry{
+		ID:              entryID,
+		TenantID:        tenantID,
+		EntryDate:       charge.CreatedAt,
+		ReferenceNumber: &reference,
+		ReferenceType:   "Purchase_Back_Charge",
+		ReferenceID:     &charge.ID,
+		Description:     fmt.Sprintf("Back-charge %s to %s", charge.BackChargeNumber, charge.VendorName),
+		Amount:          charge.Amount,
+		Narration:       charge.Reason,
+		EntryStatus:     "Draft",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	lines := []models.JournalEntryDetail{
+		{AccountID: "ACC-ACCOUNTS-PAYABLE", DebitAmount: charge.Amount, Description: fmt.Sprintf("Set off against invoice %s", charge.InvoiceNumber)},
+		{AccountID: "ACC-BACK-CHARGE-RECOVERY", CreditAmount: charge.Amount, Description: charge.Reason},
+	}
+	if err := glService.PostBalancedEntryTx(tx, tenantID, entry, lines, userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit back-charge: %w", err)
+	}
+	return nil
+}
+
+// ListVendorBackCharges lists back-charges, optionally for one vendor, invoice or snag
+func (s *PurchaseService) ListVendorBackCharges(tenantID, vendorID, invoiceID, snagID string) ([]models.VendorBackCharge, error) {
+	query := `SELECT bc.id, bc.tenant_id, bc.back_charge_number, bc.vendor_id, COALESCE(v.name, ''), bc.invoice_id,
+		COALESCE(vi.invoice_number, ''), COALESCE(bc.project_id, ''), bc.snag_id, bc.amount, bc.reason, bc.status,
+		bc.journal_entry_id, bc.created_by, bc.created_at
+		FROM vendor_back_charges bc
+		LEFT JOIN vendors v ON v.id = bc.vendor_id
+		LEFT JOIN vendor_invoices vi ON vi.id = bc.invoice_id
+		WHERE bc.tenant_id = ?`
+	args := []interface{}{tenantID}
+	if vendorID != "" {
+		query += " AND bc.vendor_id = ?"
+		args = append(args, vendorID)
+	}
+	if invoiceID != "" {
+		query += " AND bc.invoice_id = ?"
+		args = append(args, invoiceID)
+	}
+	if snagID != "" {
+		query += " AND bc.snag_id = ?"
+		args = append(args, snagID)
+	}
+	query += " ORDER BY bc.created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch back-charges: %w", err)
+	}
+	defer rows.Close()
+
+	charges := []models.VendorBackCharge{}
+	for rows.Next() {
+		var c models.VendorBackCharge
+		var snagID, journalID, createdBy sql.NullString
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.BackChargeNumber, &c.VendorID, &c.VendorName, &c.InvoiceID,
+			&c.InvoiceNumber, &c.ProjectID, &snagID, &c.Amount, &c.Reason, &c.Status,
+			&journalID, &createdBy, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan back-charge: %w", err)
+		}
+		c.SnagID = nullStringPtr(snagID)
+		c.JournalEntryID = nullStringPtr(journalID)
+		c.CreatedBy = nullStringPtr(createdBy)
+		charges = append(charges, c)
+	}
+	return charges, rows.Err()
+}
+
+// invoiceBalance returns the invoice total less payments, TDS and back-charges, read
+// through q so a transaction sees its own uncommitted rows
+func invoiceBalance(q sqlRowQuerier, tenantID, invoiceID string) float64 {
+	var outstanding float64
+	q.QueryRow(`SELECT vi.total_payable
+		- COALESCE((SELECT SUM(payment_amount) FROM purchase_payments WHERE tenant_id = vi.tenant_id AND invoice_id = vi.id), 0)
+		- COALESCE((SELECT SUM(tds_amount) FROM tds_deductions WHERE tenant_id = vi.tenant_id AND invoice_id = vi.id), 0)
+		- COALESCE((SELECT SUM(amount) FROM vendor_back_charges WHERE tenant_id = vi.tenant_id AND invoice_id = vi.id AND status = ?), 0)
+		FROM vendor_invoices vi WHERE vi.id = ? AND vi.tenant_id = ?`, models.VendorBackChargeApplied, invoiceID, tenantID).Scan(&outstanding)
+	return outstanding
+}
+
+func isPayableInvoiceStatus(status string) bool {
+	for _, s := range payableInvoiceStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBackCharge checks a back-charge fits within the invoice balance still unpaid
+func validateBackCharge(amount, outstanding float64) error {
+	if amount <= 0 {
+		return fmt.Errorf("back-charge amount must be positive")
+	}
+	if amount > roundTo2(outstanding)+0.005 {
+		return fmt.Errorf("back-charge of %.2f exceeds the invoice balance of %.2f", amount, roundTo2(outstanding))
+	}
+	return nil
+}
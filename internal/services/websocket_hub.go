@@ -183,6 +183,20 @@ func (h *WebSocketHub) BroadcastLeadUpdate(tenantID string, leadID int64, status
 	h.broadcast <- message
 }
 
+// BroadcastUnitStatus broadcasts unit availability changes to the tenant's sales screens
+func (h *WebSocketHub) BroadcastUnitStatus(tenantID, unitID, status string, data map[string]interface{}) {
+	data["unit_id"] = unitID
+	data["status"] = status
+	message := &WebSocketMessage{
+		Type:      "unit_status_updated",
+		EventID:   fmt.Sprintf("unit-status-%s-%d", unitID, time.Now().Unix()),
+		Timestamp: time.Now(),
+		TenantID:  tenantID,
+		Data:      data,
+	}
+	h.broadcast <- message
+}
+
 // BroadcastGamificationEvent broadcasts gamification events (points awarded, badges earned)
 func (h *WebSocketHub) BroadcastGamificationEvent(tenantID string, userID int64, eventType string, data map[string]interface{}) {
 	data["event_type"] = eventType
@@ -0,0 +1,55 @@
+-- Receivables: AR Ageing & Dunning
+-- Staged dunning (reminder -> demand notice -> final notice) for overdue customer installments
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- DUNNING RUNS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS dunning_runs (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    as_of_date DATETIME NOT NULL,
+    channel VARCHAR(50) NOT NULL, -- email, sms, whatsapp
+    bookings_seen INT DEFAULT 0,
+    notices_sent INT DEFAULT 0,
+    notices_failed INT DEFAULT 0,
+    total_overdue DECIMAL(18, 2) DEFAULT 0,
+    status VARCHAR(50) DEFAULT 'completed', -- completed, dry_run
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_project (tenant_id, project_id),
+    KEY idx_as_of_date (as_of_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- DUNNING NOTICES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS dunning_notices (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    dunning_run_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    customer_name VARCHAR(255),
+    stage VARCHAR(50) NOT NULL, -- reminder, demand_notice, final_notice
+    channel VARCHAR(50) NOT NULL,
+    recipient VARCHAR(255),
+    subject VARCHAR(255),
+    body TEXT,
+    amount_overdue DECIMAL(18, 2) DEFAULT 0,
+    days_overdue INT DEFAULT 0,
+    status VARCHAR(50) NOT NULL, -- sent, failed
+    failure_reason TEXT,
+    sent_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_run (dunning_run_id),
+    KEY idx_stage_status (stage, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,67 @@
+-- Delayed-Payment Interest (RERA)
+-- Interest on delayed installments at SBI MCLR + 2%, and compensation for delayed possession
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- RATE HISTORY & POLICY
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS interest_rate_history (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    benchmark VARCHAR(50) NOT NULL DEFAULT 'SBI_MCLR',
+    base_rate DECIMAL(6, 3) NOT NULL, -- % p.a.
+    spread DECIMAL(6, 3) NOT NULL DEFAULT 2.000, -- % p.a.
+    effective_rate DECIMAL(6, 3) NOT NULL, -- base_rate + spread
+    effective_from DATE NOT NULL,
+    effective_to DATE NULL, -- NULL while current
+    notes VARCHAR(500),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_effective (tenant_id, effective_from)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS interest_policies (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL DEFAULT '', -- '' for tenant default
+    grace_days INT DEFAULT 0,
+    method VARCHAR(20) NOT NULL DEFAULT 'simple', -- simple, compound
+    compounding_per_year INT DEFAULT 12,
+    apply_to_possession BOOLEAN DEFAULT TRUE,
+    minimum_interest_note DECIMAL(18, 2) DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- INTEREST NOTES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS interest_notes (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    note_number VARCHAR(50) NOT NULL,
+    note_type VARCHAR(50) NOT NULL, -- delayed_payment_interest, possession_delay_compensation
+    booking_id VARCHAR(36) NOT NULL,
+    stage_id VARCHAR(36),
+    project_id VARCHAR(36),
+    period_from DATETIME NOT NULL,
+    period_to DATETIME NOT NULL,
+    principal DECIMAL(18, 2) NOT NULL,
+    interest_amount DECIMAL(18, 2) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    ledger_entry_id VARCHAR(36),
+    status VARCHAR(20) DEFAULT 'raised', -- raised, cancelled
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_note_number (tenant_id, note_number),
+    KEY idx_booking (tenant_id, booking_id),
+    KEY idx_stage (stage_id),
+    KEY idx_type_status (note_type, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,133 @@
+-- Accounts Payable Payment Runs
+-- Vendor bank accounts, TDS sections, bank bulk-payment layouts, approved payment runs and TDS deductions
+-- Payable ageing by project reads vendor_invoices.project_id
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- VENDOR PAYMENT SETUP
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS vendor_bank_accounts (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    vendor_id VARCHAR(36) NOT NULL,
+    account_name VARCHAR(255) NOT NULL,
+    account_number VARCHAR(50) NOT NULL,
+    ifsc_code VARCHAR(20) NOT NULL,
+    bank_name VARCHAR(255),
+    is_primary BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_vendor (tenant_id, vendor_id, is_primary)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS vendor_tds_sections (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    vendor_id VARCHAR(36) NOT NULL,
+    section_code VARCHAR(10) NOT NULL, -- 194C, 194H, 194J, 194I
+    rate DECIMAL(6, 3) NOT NULL, -- % of taxable value
+    pan VARCHAR(10),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_vendor (tenant_id, vendor_id, is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS bank_payment_formats (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    bank_code VARCHAR(20) NOT NULL,
+    format_name VARCHAR(100) NOT NULL,
+    file_type VARCHAR(10) NOT NULL DEFAULT 'csv', -- csv, neft
+    delimiter VARCHAR(5) NOT NULL DEFAULT ',',
+    include_header BOOLEAN DEFAULT TRUE,
+    columns JSON NOT NULL, -- ordered column keys
+    date_format VARCHAR(20) NOT NULL DEFAULT 'DD/MM/YYYY',
+    debit_account_number VARCHAR(50) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_bank (tenant_id, bank_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- PAYMENT RUNS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS vendor_payment_runs (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    run_number VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'proposed', -- proposed, approved, rejected, processing, processed, failed
+    due_on_or_before DATE NULL,
+    vendor_id VARCHAR(36) NULL,
+    project_id VARCHAR(36) NULL,
+    payment_date DATE NOT NULL,
+    payment_method VARCHAR(20) NOT NULL DEFAULT 'NEFT', -- NEFT, RTGS, IMPS
+    bank_format_id VARCHAR(36) NOT NULL,
+    total_gross DECIMAL(18, 2) DEFAULT 0,
+    total_tds DECIMAL(18, 2) DEFAULT 0,
+    total_net DECIMAL(18, 2) DEFAULT 0,
+    line_count INT DEFAULT 0,
+    created_by VARCHAR(36) NOT NULL,
+    approved_by VARCHAR(36) NULL,
+    approved_at TIMESTAMP NULL,
+    approval_comment VARCHAR(500),
+    processed_at TIMESTAMP NULL,
+    bank_file_name VARCHAR(255),
+    bank_file_content MEDIUMTEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_run_number (tenant_id, run_number),
+    KEY idx_tenant_status (tenant_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS vendor_payment_run_lines (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    run_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    invoice_number VARCHAR(100) NOT NULL,
+    vendor_id VARCHAR(36) NOT NULL,
+    vendor_name VARCHAR(255),
+    due_date DATE NOT NULL,
+    gross_amount DECIMAL(18, 2) NOT NULL,
+    tds_section VARCHAR(10),
+    tds_rate DECIMAL(6, 3) DEFAULT 0,
+    tds_amount DECIMAL(18, 2) DEFAULT 0,
+    net_amount DECIMAL(18, 2) NOT NULL,
+    payment_id VARCHAR(36) NULL,
+    journal_entry_id VARCHAR(100) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'proposed', -- proposed, paid, failed
+    failure_reason VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_run (run_id),
+    KEY idx_tenant_invoice (tenant_id, invoice_id),
+    CONSTRAINT fk_payment_run_line_run FOREIGN KEY (run_id) REFERENCES vendor_payment_runs(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- TDS DEDUCTIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS tds_deductions (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    vendor_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    payment_id VARCHAR(36) NOT NULL,
+    section_code VARCHAR(10) NOT NULL,
+    rate DECIMAL(6, 3) NOT NULL,
+    base_amount DECIMAL(18, 2) NOT NULL,
+    tds_amount DECIMAL(18, 2) NOT NULL,
+    deduction_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_deposit', -- pending_deposit, deposited
+    challan_id VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_invoice (tenant_id, invoice_id),
+    KEY idx_tenant_status (tenant_id, status, deduction_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
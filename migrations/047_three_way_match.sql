@@ -0,0 +1,63 @@
+-- Three-Way Match
+-- Vendor invoice lines matched against PO lines and goods receipts, with tolerances and an exceptions queue
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- INVOICE LINES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS purchase_invoice_line_items (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    po_line_item_id VARCHAR(36) NULL,
+    line_number INT NOT NULL,
+    description VARCHAR(500),
+    quantity DECIMAL(18, 3) NOT NULL,
+    unit_price DECIMAL(18, 2) NOT NULL,
+    line_total DECIMAL(18, 2) NOT NULL,
+    hsn_code VARCHAR(20),
+    tax_rate DECIMAL(6, 3) DEFAULT 0,
+    tax_amount DECIMAL(18, 2) DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_invoice (invoice_id),
+    KEY idx_tenant_po_line (tenant_id, po_line_item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- TOLERANCES & EXCEPTIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS purchase_match_tolerances (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    price_tolerance_pct DECIMAL(6, 3) NOT NULL DEFAULT 2.000, -- invoice price over PO price
+    quantity_tolerance_pct DECIMAL(6, 3) NOT NULL DEFAULT 0.000, -- invoiced over accepted quantity
+    amount_tolerance DECIMAL(18, 2) NOT NULL DEFAULT 1.00, -- invoice amount vs sum of lines
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant (tenant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS invoice_match_exceptions (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    invoice_line_id VARCHAR(36),
+    po_line_item_id VARCHAR(36),
+    exception_type VARCHAR(50) NOT NULL, -- no_purchase_order, vendor_mismatch, unmatched_line, price_variance, quantity_exceeds_ordered, quantity_exceeds_received, invoice_total_mismatch
+    expected_value DECIMAL(18, 3) DEFAULT 0,
+    actual_value DECIMAL(18, 3) DEFAULT 0,
+    variance_pct DECIMAL(10, 2) DEFAULT 0,
+    message VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, accepted, rejected, superseded
+    resolved_by VARCHAR(36) NULL,
+    resolved_at TIMESTAMP NULL,
+    resolution_notes VARCHAR(1000),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_status (tenant_id, status),
+    KEY idx_invoice (invoice_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
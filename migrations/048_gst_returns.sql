@@ -0,0 +1,99 @@
+-- GST Return Preparation
+-- Outward documents and input tax credit with the detail needed to build GSTR-1 and GSTR-3B
+-- document_type distinguishes invoices from credit/debit notes; tax is split into IGST/CGST/SGST/cess
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- OUTWARD SUPPLIES (GSTR-1)
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS gst_invoice_tracking (
+    id VARCHAR(100) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    invoice_number VARCHAR(100) NOT NULL,
+    invoice_date DATE NOT NULL,
+    invoice_amount DECIMAL(18, 2) NOT NULL,
+    customer_id VARCHAR(36),
+    customer_gstin VARCHAR(15),
+    customer_name VARCHAR(255),
+    document_type VARCHAR(3) NOT NULL DEFAULT 'INV', -- INV, CRN, DBN
+    place_of_supply VARCHAR(2), -- state code
+    reverse_charge BOOLEAN DEFAULT FALSE,
+    hsn_code VARCHAR(8),
+    hsn_description VARCHAR(255),
+    uqc VARCHAR(10),
+    quantity DECIMAL(18, 3) DEFAULT 0,
+    original_invoice_number VARCHAR(100),
+    original_invoice_date DATE NULL,
+    gst_rate DECIMAL(5, 2) DEFAULT 0,
+    gst_amount DECIMAL(18, 2) DEFAULT 0,
+    taxable_value DECIMAL(18, 2) DEFAULT 0,
+    igst_amount DECIMAL(18, 2) DEFAULT 0,
+    cgst_amount DECIMAL(18, 2) DEFAULT 0,
+    sgst_amount DECIMAL(18, 2) DEFAULT 0,
+    cess_amount DECIMAL(18, 2) DEFAULT 0,
+    invoice_raised_date DATE NULL,
+    invoice_cancelled BOOLEAN DEFAULT FALSE,
+    cancellation_date DATE NULL,
+    gstr_1_reported BOOLEAN DEFAULT FALSE,
+    gstr_1_filing_month INT,
+    gstr_1_filing_year INT,
+    itc_eligible BOOLEAN DEFAULT TRUE,
+    itc_claimed BOOLEAN DEFAULT FALSE,
+    reconciled BOOLEAN DEFAULT FALSE,
+    reconciliation_date DATE NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    KEY idx_tenant_date (tenant_id, invoice_date),
+    KEY idx_tenant_invoice (tenant_id, invoice_id),
+    KEY idx_customer_gstin (tenant_id, customer_gstin)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- INWARD SUPPLIES / INPUT TAX CREDIT (GSTR-3B TABLE 4)
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS gst_input_credit (
+    id VARCHAR(100) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    purchase_invoice_id VARCHAR(36) NOT NULL,
+    vendor_id VARCHAR(36),
+    vendor_gstin VARCHAR(15),
+    invoice_number VARCHAR(100) NOT NULL,
+    invoice_date DATE NOT NULL,
+    invoice_amount DECIMAL(18, 2) NOT NULL,
+    gst_rate DECIMAL(5, 2) DEFAULT 0,
+    gst_amount DECIMAL(18, 2) DEFAULT 0,
+    taxable_value DECIMAL(18, 2) DEFAULT 0,
+    igst_amount DECIMAL(18, 2) DEFAULT 0,
+    cgst_amount DECIMAL(18, 2) DEFAULT 0,
+    sgst_amount DECIMAL(18, 2) DEFAULT 0,
+    cess_amount DECIMAL(18, 2) DEFAULT 0,
+    place_of_supply VARCHAR(2), -- state code where supply is received
+    reverse_charge BOOLEAN DEFAULT FALSE,
+    itc_eligible BOOLEAN DEFAULT TRUE,
+    itc_ineligible_reason VARCHAR(255),
+    itc_claimed BOOLEAN DEFAULT FALSE,
+    itc_claim_month INT,
+    itc_claim_year INT,
+    blocked_percentage DECIMAL(5, 2) DEFAULT 0, -- rule 42/43 common credit reversal
+    blocked_amount DECIMAL(18, 2) DEFAULT 0,
+    gstr_2_reported BOOLEAN DEFAULT FALSE,
+    gstr_2_filing_month INT,
+    gstr_2_filing_year INT,
+    vendor_gstr_1_reconciled BOOLEAN DEFAULT FALSE,
+    reconciliation_date DATE NULL,
+    discrepancy_found BOOLEAN DEFAULT FALSE,
+    discrepancy_notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    KEY idx_tenant_date (tenant_id, invoice_date),
+    KEY idx_tenant_invoice (tenant_id, purchase_invoice_id),
+    KEY idx_vendor_gstin (tenant_id, vendor_gstin)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,92 @@
+-- GST E-Invoice and E-Way Bill
+-- Seller profile / IRP connection per tenant, IRNs registered against sales invoices,
+-- and e-way bills raised for material movement between sites
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- SETTINGS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS einvoice_settings (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL DEFAULT 'stub', -- stub, gsp
+    api_base_url VARCHAR(255),
+    client_id VARCHAR(255),
+    client_secret VARCHAR(255),
+    username VARCHAR(100),
+    password VARCHAR(255),
+    seller_gstin VARCHAR(15) NOT NULL,
+    legal_name VARCHAR(255) NOT NULL,
+    trade_name VARCHAR(255),
+    address1 VARCHAR(255) NOT NULL,
+    address2 VARCHAR(255),
+    location VARCHAR(100) NOT NULL,
+    pincode INT NOT NULL,
+    state_code VARCHAR(2) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant (tenant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- E-INVOICES (IRN)
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS sales_einvoices (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL,
+    invoice_number VARCHAR(100) NOT NULL,
+    irn VARCHAR(64),
+    ack_no VARCHAR(20),
+    ack_date DATETIME NULL,
+    signed_invoice MEDIUMTEXT,
+    signed_qr_code TEXT,
+    status VARCHAR(20) NOT NULL, -- generated, cancelled, failed
+    error_message VARCHAR(1000),
+    request_payload JSON,
+    cancel_reason_code VARCHAR(2),
+    cancel_remarks VARCHAR(100),
+    cancelled_at TIMESTAMP NULL,
+    cancelled_by VARCHAR(36),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_invoice (tenant_id, invoice_id, created_at),
+    KEY idx_irn (irn)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- E-WAY BILLS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS eway_bills (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    source_type VARCHAR(30) NOT NULL, -- inventory_transfer
+    source_id VARCHAR(36) NOT NULL,
+    document_number VARCHAR(100) NOT NULL,
+    eway_bill_no VARCHAR(20),
+    eway_bill_date DATETIME NULL,
+    valid_upto DATETIME NULL,
+    vehicle_no VARCHAR(20),
+    transport_mode VARCHAR(2) NOT NULL DEFAULT '1', -- 1 road, 2 rail, 3 air, 4 ship
+    distance_km INT NOT NULL,
+    total_value DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- generated, cancelled, failed
+    error_message VARCHAR(1000),
+    request_payload JSON,
+    cancel_reason VARCHAR(150),
+    cancelled_at TIMESTAMP NULL,
+    cancelled_by VARCHAR(36),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_source (tenant_id, source_type, source_id, status),
+    KEY idx_eway_bill_no (eway_bill_no)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
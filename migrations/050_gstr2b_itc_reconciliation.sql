@@ -0,0 +1,82 @@
+-- GSTR-2B Import and ITC Reconciliation
+-- Supplier documents from the monthly GSTR-2B statement and their match
+-- against vendor invoices booked for input tax credit
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- GSTR-2B STATEMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS gstr2b_imports (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    gstin VARCHAR(15) NOT NULL,
+    return_period VARCHAR(6) NOT NULL, -- MMYYYY
+    generated_date VARCHAR(10),
+    document_count INT NOT NULL DEFAULT 0,
+    total_taxable DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_tax DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    imported_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_period (tenant_id, return_period)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS gstr2b_documents (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    import_id CHAR(36) NOT NULL,
+    return_period VARCHAR(6) NOT NULL,
+    supplier_gstin VARCHAR(15) NOT NULL,
+    supplier_name VARCHAR(255),
+    document_type VARCHAR(3) NOT NULL, -- INV, CRN, DBN
+    document_number VARCHAR(50) NOT NULL,
+    normalized_number VARCHAR(50) NOT NULL,
+    document_date DATE NULL,
+    document_value DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    taxable_value DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    igst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    cgst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    sgst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    cess_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    itc_available BOOLEAN NOT NULL DEFAULT TRUE,
+    reverse_charge BOOLEAN NOT NULL DEFAULT FALSE,
+    reason VARCHAR(100),
+    supplier_filed_on VARCHAR(10),
+    supplier_filing_period VARCHAR(6),
+    KEY idx_tenant_period (tenant_id, return_period),
+    KEY idx_supplier_number (tenant_id, supplier_gstin, normalized_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- RECONCILIATION
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS itc_reconciliation_lines (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    return_period VARCHAR(6) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- matched, mismatched, missing_in_2b, missing_in_books
+    match_type VARCHAR(10), -- exact, fuzzy
+    supplier_gstin VARCHAR(15),
+    vendor_id VARCHAR(36),
+    vendor_name VARCHAR(255),
+    vendor_invoice_id VARCHAR(36),
+    books_invoice_number VARCHAR(100),
+    books_invoice_date DATE NULL,
+    books_taxable DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    books_tax DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gstr2b_document_id CHAR(36),
+    gstr2b_number VARCHAR(50),
+    gstr2b_date DATE NULL,
+    gstr2b_taxable DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gstr2b_tax DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    tax_difference DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    itc_at_risk DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    reasons VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_period (tenant_id, return_period, status),
+    KEY idx_vendor_invoice (tenant_id, vendor_invoice_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,64 @@
+-- TDS Engine
+-- Section-wise TDS ledger for vendor bills, broker and channel partner commission
+-- and property purchases, with ITNS 281 challans for the quarterly 26Q / 27Q statements
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- TDS LEDGER
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS tds_ledger (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    financial_year VARCHAR(7) NOT NULL, -- 2025-26
+    quarter VARCHAR(2) NOT NULL, -- Q1..Q4
+    form VARCHAR(4) NOT NULL, -- 26Q, 27Q, 26QB
+    section_code VARCHAR(10) NOT NULL, -- 194C, 194H, 194J, 194IA
+    source_type VARCHAR(30) NOT NULL, -- vendor_invoice, vendor_payment, broker_payout, partner_payout, property_purchase
+    source_id VARCHAR(100) NOT NULL,
+    payee_type VARCHAR(20) NOT NULL, -- vendor, broker, partner, property_seller
+    payee_id VARCHAR(36) NOT NULL,
+    payee_name VARCHAR(255),
+    pan VARCHAR(10),
+    transaction_date DATE NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    rate DECIMAL(6, 3) NOT NULL DEFAULT 0,
+    base_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    tds_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    pan_missing BOOLEAN NOT NULL DEFAULT FALSE,
+    deposit_due_date DATE NULL,
+    status VARCHAR(20) NOT NULL, -- pending_deposit, deposited, below_threshold, caught_up
+    challan_id CHAR(36) NULL,
+    deduction_id CHAR(36) NULL, -- tds_deductions row for vendor invoices
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_source (tenant_id, source_type, source_id),
+    KEY idx_payee_year (tenant_id, payee_type, payee_id, section_code, financial_year),
+    KEY idx_return (tenant_id, financial_year, quarter, form),
+    KEY idx_tenant_status (tenant_id, status, deposit_due_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- CHALLANS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS tds_challans (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    challan_serial_no VARCHAR(10) NOT NULL,
+    bsr_code VARCHAR(7) NOT NULL,
+    deposit_date DATE NOT NULL,
+    tds_amount DECIMAL(18, 2) NOT NULL,
+    interest_due DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    interest_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    fee_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(18, 2) NOT NULL,
+    entry_count INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_challan (tenant_id, bsr_code, deposit_date, challan_serial_no),
+    KEY idx_tenant_date (tenant_id, deposit_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,81 @@
+-- Buyer TDS (Form 26QB) on Bookings
+-- TDS u/s 194-IA deducted by buyers from booking payments, with the 26QB
+-- acknowledgement, Form 16B certificate and 26AS / AIS reconciliation
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- BUYER TDS CREDITS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS booking_payment_tds (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    payment_id VARCHAR(36) NOT NULL,
+    customer_id VARCHAR(36) NULL,
+    buyer_name VARCHAR(255),
+    buyer_pan VARCHAR(10),
+    financial_year VARCHAR(7) NOT NULL, -- 2025-26
+    total_consideration DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    amount_received DECIMAL(18, 2) NOT NULL, -- net of TDS
+    gross_amount DECIMAL(18, 2) NOT NULL,
+    tds_rate DECIMAL(6, 3) NOT NULL DEFAULT 0,
+    tds_amount DECIMAL(18, 2) NOT NULL,
+    expected_tds DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    deduction_date DATE NOT NULL,
+    acknowledgement_number VARCHAR(20) NULL, -- 26QB acknowledgement
+    challan_serial_no VARCHAR(10) NULL,
+    bsr_code VARCHAR(7) NULL,
+    challan_date DATE NULL,
+    certificate_number VARCHAR(30) NULL, -- Form 16B
+    certificate_file_url VARCHAR(500) NULL,
+    certificate_uploaded_at TIMESTAMP NULL,
+    certificate_due_date DATE NOT NULL,
+    status VARCHAR(25) NOT NULL, -- deducted, challan_filed, certificate_received, verified, mismatch
+    ledger_entry_id CHAR(36) NULL, -- customer_account_ledgers credit
+    form26as_entry_id CHAR(36) NULL,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_payment (tenant_id, payment_id),
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_year_status (tenant_id, financial_year, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- 26AS / AIS IMPORTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS form26as_imports (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    financial_year VARCHAR(7) NOT NULL,
+    source VARCHAR(5) NOT NULL, -- 26AS, AIS
+    entry_count INT NOT NULL DEFAULT 0,
+    total_tds DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    imported_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_year (tenant_id, financial_year)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS form26as_entries (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    import_id CHAR(36) NOT NULL,
+    financial_year VARCHAR(7) NOT NULL,
+    deductor_name VARCHAR(255),
+    deductor_pan VARCHAR(10),
+    section_code VARCHAR(10) NOT NULL, -- 194IA
+    transaction_date DATE NOT NULL,
+    amount_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    tds_deposited DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    acknowledgement_number VARCHAR(20) NULL,
+    booking_status VARCHAR(2) NULL, -- F (final), U (unmatched), P (provisional)
+    matched_credit_id CHAR(36) NULL,
+    KEY idx_tenant_year (tenant_id, financial_year),
+    KEY idx_import (import_id),
+    KEY idx_deductor (tenant_id, deductor_pan)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,131 @@
+-- Payment Plans and Demand Letters
+-- Reusable construction-linked, time-linked, down-payment and subvention plans per
+-- project, tower construction milestones, and the demand letters raised when a
+-- milestone completes
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- PAYMENT PLAN TEMPLATES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS payment_plan_templates (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    plan_type VARCHAR(30) NOT NULL, -- construction_linked, time_linked, down_payment, subvention
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_project (tenant_id, project_id, is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS payment_plan_stages (
+    id CHAR(36) PRIMARY KEY,
+    template_id CHAR(36) NOT NULL,
+    stage_number INT NOT NULL,
+    stage_name VARCHAR(255) NOT NULL,
+    percentage DECIMAL(6, 3) NOT NULL,
+    trigger_type VARCHAR(20) NOT NULL, -- on_booking, time, milestone
+    offset_days INT NOT NULL DEFAULT 0, -- time: days after booking
+    milestone_code VARCHAR(50) NULL, -- milestone: e.g. SLAB_5
+    due_days INT NOT NULL DEFAULT 15, -- days allowed to pay after the demand
+    payer VARCHAR(20) NOT NULL DEFAULT 'customer', -- customer, lender
+    UNIQUE KEY uk_template_stage (template_id, stage_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BOOKING PLAN STAGES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS booking_plan_stages (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    template_id CHAR(36) NOT NULL,
+    stage_id CHAR(36) NOT NULL,
+    stage_number INT NOT NULL,
+    stage_name VARCHAR(255) NOT NULL,
+    percentage DECIMAL(6, 3) NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    trigger_type VARCHAR(20) NOT NULL,
+    offset_days INT NOT NULL DEFAULT 0,
+    milestone_code VARCHAR(50) NULL,
+    due_days INT NOT NULL DEFAULT 15,
+    payer VARCHAR(20) NOT NULL DEFAULT 'customer',
+    status VARCHAR(20) NOT NULL, -- pending, scheduled, demanded
+    due_date DATE NULL,
+    schedule_id VARCHAR(36) NULL, -- payment_schedules row
+    demand_letter_id CHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_booking_stage (tenant_id, booking_id, stage_number),
+    KEY idx_pending_milestone (tenant_id, status, trigger_type, milestone_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- TOWER MILESTONES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS tower_milestones (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    block_id VARCHAR(36) NOT NULL, -- property_blocks tower
+    milestone_code VARCHAR(50) NOT NULL,
+    milestone_name VARCHAR(255) NOT NULL,
+    sequence INT NOT NULL DEFAULT 0,
+    planned_date DATE NULL,
+    status VARCHAR(20) NOT NULL, -- pending, completed
+    completed_on DATE NULL,
+    progress_tracking_id VARCHAR(36) NULL, -- site progress entry that evidenced completion
+    architect_certificate_ref VARCHAR(100) NULL,
+    completed_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_block_milestone (tenant_id, block_id, milestone_code),
+    KEY idx_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- DEMAND LETTERS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS demand_letters (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    letter_number VARCHAR(50) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    booking_reference VARCHAR(100),
+    plan_stage_id CHAR(36) NOT NULL, -- booking_plan_stages row
+    milestone_id CHAR(36) NULL,
+    schedule_id VARCHAR(36) NOT NULL,
+    project_name VARCHAR(255),
+    unit_number VARCHAR(50),
+    customer_name VARCHAR(255),
+    customer_email VARCHAR(255),
+    customer_phone VARCHAR(20),
+    stage_name VARCHAR(255) NOT NULL,
+    percentage DECIMAL(6, 3) NOT NULL,
+    stage_amount DECIMAL(18, 2) NOT NULL,
+    previous_outstanding DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_due DECIMAL(18, 2) NOT NULL,
+    demand_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    notify_channel VARCHAR(20) NOT NULL, -- email, sms, whatsapp
+    notify_status VARCHAR(20) NOT NULL, -- pending, sent, failed
+    notify_error TEXT NULL,
+    notified_at TIMESTAMP NULL,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_letter (tenant_id, letter_number),
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_milestone (tenant_id, milestone_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
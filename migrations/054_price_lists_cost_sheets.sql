@@ -0,0 +1,91 @@
+-- Price Lists and Cost Sheets
+-- Versioned, rule-based price lists per project and tower (floor rise, PLC,
+-- parking tiers, club and statutory charges with per-component GST), unit
+-- pricing attributes, and the cost sheet locked on each booking
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- PRICE LISTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS price_lists (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    block_id VARCHAR(36) NULL, -- NULL for a project-wide list
+    version INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    base_rate_per_sqft DECIMAL(12, 2) NOT NULL,
+    base_gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    stamp_duty_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    registration_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    registration_cap DECIMAL(18, 2) NOT NULL DEFAULT 0, -- 0 = no cap
+    status VARCHAR(20) NOT NULL, -- draft, published
+    effective_from DATE NULL,
+    published_by VARCHAR(36) NULL,
+    published_at TIMESTAMP NULL,
+    notes TEXT,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_project_block (tenant_id, project_id, block_id, version),
+    KEY idx_effective (tenant_id, project_id, status, effective_from)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS price_list_rules (
+    id CHAR(36) PRIMARY KEY,
+    price_list_id CHAR(36) NOT NULL,
+    category VARCHAR(20) NOT NULL, -- floor_rise, plc, parking, club, statutory, other
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    charge_type VARCHAR(20) NOT NULL, -- per_sqft, lumpsum, basic_pct
+    amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    from_floor INT NOT NULL DEFAULT 0, -- floor_rise
+    to_floor INT NOT NULL DEFAULT 0, -- floor_rise, 0 = no upper limit
+    attribute VARCHAR(20) NULL, -- plc: facing, view, corner
+    match_value VARCHAR(50) NULL,
+    parking_type VARCHAR(50) NULL,
+    unit_types VARCHAR(255) NULL, -- comma-separated, NULL for all
+    is_optional BOOLEAN NOT NULL DEFAULT FALSE,
+    display_order INT NOT NULL DEFAULT 0,
+    UNIQUE KEY uk_price_list_code (price_list_id, code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- UNIT PRICING ATTRIBUTES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_pricing_attributes (
+    tenant_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    view_type VARCHAR(50) NULL, -- park, pool, road, city
+    is_corner BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, unit_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BOOKING PRICE LOCKS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS booking_price_locks (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    price_list_id CHAR(36) NOT NULL,
+    price_list_version INT NOT NULL,
+    agreement_value DECIMAL(18, 2) NOT NULL,
+    total_gst DECIMAL(18, 2) NOT NULL,
+    total_payable DECIMAL(18, 2) NOT NULL,
+    grand_total DECIMAL(18, 2) NOT NULL,
+    cost_sheet JSON NOT NULL, -- itemised cost sheet as sold
+    locked_by VARCHAR(36),
+    locked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_booking (tenant_id, booking_id),
+    KEY idx_price_list (price_list_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
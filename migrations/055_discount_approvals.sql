@@ -0,0 +1,68 @@
+-- Discount Approvals
+-- Discount limits by role and project (absolute and per sqft), discount
+-- requests on cost sheets and quotations, and their multi-level approval chain
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- DISCOUNT LIMITS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS discount_limits (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL DEFAULT '', -- '' for all projects
+    role VARCHAR(50) NOT NULL,
+    max_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    max_per_sqft DECIMAL(12, 2) NOT NULL DEFAULT 0, -- 0 = no per-sqft cap
+    unlimited BOOLEAN NOT NULL DEFAULT FALSE,
+    approval_level INT NOT NULL DEFAULT 0, -- 0 = not an approver
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project_role (tenant_id, project_id, role)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- DISCOUNT REQUESTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS discount_requests (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NULL,
+    unit_id VARCHAR(36) NULL,
+    quotation_id VARCHAR(36) NULL,
+    price_list_id CHAR(36) NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    requester_role VARCHAR(50) NOT NULL,
+    sbua DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    list_price DECIMAL(18, 2) NOT NULL,
+    discount_amount DECIMAL(18, 2) NOT NULL,
+    discount_per_sqft DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    approved_price DECIMAL(18, 2) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL, -- pending, approved, rejected, used
+    current_level INT NOT NULL DEFAULT 0, -- level awaiting action, 0 when none
+    used_by_booking_id VARCHAR(36) NULL,
+    used_by_sales_order_id VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_status (tenant_id, status),
+    KEY idx_unit (tenant_id, unit_id),
+    KEY idx_quotation (tenant_id, quotation_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS discount_approval_steps (
+    id CHAR(36) PRIMARY KEY,
+    request_id CHAR(36) NOT NULL,
+    level INT NOT NULL,
+    approver_role VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- pending, approved, rejected
+    acted_by VARCHAR(36) NULL,
+    acted_at TIMESTAMP NULL,
+    comment TEXT,
+    UNIQUE KEY uk_request_level (request_id, level),
+    KEY idx_approver (approver_role, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
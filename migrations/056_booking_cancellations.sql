@@ -0,0 +1,90 @@
+-- Booking Cancellations and Refunds
+-- Forfeiture rules per project, cancellation requests with their refund
+-- statement and approval, and broker commission clawbacks on cancelled bookings
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- CANCELLATION POLICIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL DEFAULT '', -- '' for all projects
+    forfeit_percent DECIMAL(5, 2) NOT NULL DEFAULT 0, -- of the agreement value
+    forfeit_min_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    admin_charge DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gst_refundable BOOLEAN NOT NULL DEFAULT FALSE,
+    recover_brokerage BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BOOKING CANCELLATIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS booking_cancellations (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NULL,
+    customer_id VARCHAR(36) NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL, -- requested, approved, rejected, refunded
+    agreement_value DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    amount_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gst_collected DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    forfeit_percent DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    forfeiture_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gst_retained DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    brokerage_recovered DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    admin_charge DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_deductions DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    refund_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    loan_outstanding DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    bank_refund DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    customer_refund DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    financing_id VARCHAR(36) NULL,
+    noc_id VARCHAR(36) NULL,
+    credit_note_number VARCHAR(50) NULL,
+    credit_note_amount DECIMAL(18, 2) NOT NULL DEFAULT 0, -- consideration reversed, excluding GST
+    credit_note_gst DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    journal_entry_id VARCHAR(100) NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    approved_by VARCHAR(36) NULL,
+    approved_at TIMESTAMP NULL,
+    decision_comment TEXT,
+    refund_mode VARCHAR(20) NULL, -- neft, rtgs, cheque
+    refund_reference VARCHAR(100) NULL,
+    refunded_at TIMESTAMP NULL,
+    payout_entry_id VARCHAR(100) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_status (tenant_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BROKER COMMISSION CLAWBACKS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS broker_commission_clawbacks (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    broker_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    link_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, adjusted
+    payout_reference_no VARCHAR(100) NULL, -- payout the clawback was netted against
+    adjusted_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_broker (tenant_id, broker_id, status),
+    KEY idx_booking (tenant_id, booking_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,98 @@
+-- Unit Transfers
+-- Transfer fees per project, resale transfers of a booking from its applicants to
+-- new buyers, and the audit of customer ledger entries re-pointed on transfer
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- TRANSFER FEE POLICIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS transfer_fee_policies (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL DEFAULT '', -- '' for all projects
+    charge_type VARCHAR(20) NOT NULL, -- per_sqft, lumpsum, percent
+    amount DECIMAL(18, 2) NOT NULL DEFAULT 0, -- rate per sqft of SBUA, flat fee, or percent of the agreement value
+    gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 18,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- UNIT TRANSFERS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_transfers (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    old_customer_id VARCHAR(36),
+    new_customer_id VARCHAR(36),
+    ownership_type VARCHAR(30) NOT NULL DEFAULT 'joint_tenant', -- joint_tenant, tenant_in_common
+    agreement_value DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    dues_outstanding DECIMAL(18, 2) NOT NULL DEFAULT 0, -- as at the request
+    transfer_fee DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    transfer_fee_gst DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    fee_receipt_reference VARCHAR(100),
+    fee_paid_at TIMESTAMP NULL,
+    fee_journal_entry_id VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested', -- requested, approved, completed, rejected
+    reason TEXT,
+    requested_by VARCHAR(36) NOT NULL,
+    approved_by VARCHAR(36),
+    approved_at TIMESTAMP NULL,
+    decision_comment TEXT,
+    agreement_reference VARCHAR(100), -- new co-ownership agreement
+    ledger_entries_moved INT NOT NULL DEFAULT 0,
+    endorsement_letter LONGTEXT,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_status (tenant_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- UNIT TRANSFER PARTIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_transfer_parties (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    transfer_id CHAR(36) NOT NULL,
+    role VARCHAR(20) NOT NULL, -- transferor, transferee
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    pan VARCHAR(20) NOT NULL DEFAULT '',
+    id_type VARCHAR(30) NOT NULL DEFAULT '', -- aadhar, pan, passport, driving_license, voter_id
+    id_number VARCHAR(50) NOT NULL DEFAULT '',
+    address VARCHAR(500) NOT NULL DEFAULT '',
+    share_percent DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_transfer (tenant_id, transfer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- LEDGER RE-POINTING AUDIT
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_transfer_ledger_audit (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    transfer_id CHAR(36) NOT NULL,
+    ledger_entry_id VARCHAR(36) NOT NULL,
+    old_customer_id VARCHAR(36),
+    new_customer_id VARCHAR(36),
+    changed_by VARCHAR(36) NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_transfer (tenant_id, transfer_id),
+    KEY idx_ledger_entry (ledger_entry_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,23 @@
+-- Unit Status History
+-- Every change of a unit's availability status (hold, block, booking, cancellation,
+-- registration) with who made it and why, behind the live availability matrix
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- UNIT STATUS HISTORY
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_status_history (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    from_status VARCHAR(20) NOT NULL, -- available, held, booked, registered, blocked
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(500),
+    changed_by VARCHAR(36),
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_unit (tenant_id, unit_id, changed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
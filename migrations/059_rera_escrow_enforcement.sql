@@ -0,0 +1,95 @@
+-- RERA Escrow Enforcement
+-- Designated RERA (70%) and free collection accounts per project, the split of every
+-- customer receipt between them, certified percentage of completion backed by the
+-- engineer, architect and CA certificates, and the withdrawal register
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- RERA ESCROW POLICIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_escrow_policies (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    rera_account_id VARCHAR(100) NOT NULL, -- project_collection_accounts.id
+    free_account_id VARCHAR(100) NOT NULL,
+    escrow_percent DECIMAL(5, 2) NOT NULL DEFAULT 70.00,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id),
+    KEY idx_tenant_rera_account (tenant_id, rera_account_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- RERA RECEIPT SPLITS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_receipt_splits (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    source_type VARCHAR(20) NOT NULL, -- collection, booking_payment
+    source_id VARCHAR(100) NOT NULL,
+    booking_id VARCHAR(36),
+    receipt_amount DECIMAL(18, 2) NOT NULL,
+    escrow_percent DECIMAL(5, 2) NOT NULL,
+    rera_account_id VARCHAR(100) NOT NULL,
+    rera_amount DECIMAL(18, 2) NOT NULL,
+    free_account_id VARCHAR(100) NOT NULL,
+    free_amount DECIMAL(18, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_source (tenant_id, source_type, source_id),
+    KEY idx_tenant_project (tenant_id, project_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- RERA COMPLETION CERTIFICATES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_completion_certificates (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    completion_percent DECIMAL(5, 2) NOT NULL,
+    engineer_document_id CHAR(26) NOT NULL, -- documents.id
+    architect_document_id CHAR(26) NOT NULL,
+    ca_document_id CHAR(26) NOT NULL,
+    certified_on DATE NOT NULL,
+    remarks VARCHAR(500),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_project (tenant_id, project_id, certified_on)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- RERA WITHDRAWAL REGISTER
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_withdrawals (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    withdrawal_number VARCHAR(50) NOT NULL,
+    rera_account_id VARCHAR(100) NOT NULL,
+    certificate_id CHAR(36),
+    utilization_type VARCHAR(50) NOT NULL, -- Construction, Land_Cost, Statutory_Approval, Admin, Interest
+    description VARCHAR(500),
+    bill_number VARCHAR(100),
+    amount DECIMAL(18, 2) NOT NULL,
+    rera_deposits DECIMAL(18, 2) NOT NULL, -- figures the decision was based on
+    completion_percent DECIMAL(5, 2) NOT NULL,
+    allowable_limit DECIMAL(18, 2) NOT NULL,
+    previously_withdrawn DECIMAL(18, 2) NOT NULL,
+    available_before DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- approved, blocked
+    block_reason VARCHAR(500),
+    utilization_id VARCHAR(100), -- project_fund_utilization.id when approved
+    requested_by VARCHAR(36),
+    requested_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_number (tenant_id, withdrawal_number),
+    KEY idx_tenant_project (tenant_id, project_id, status, requested_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,45 @@
+-- RERA Quarterly Progress Reports
+-- Pre-filled QPR snapshots per project and quarter (locked once submitted on the state
+-- portal) and tenant-configurable state export layouts
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- RERA QPR REPORTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_qpr_reports (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    quarter CHAR(7) NOT NULL, -- YYYY-Qn
+    state_code VARCHAR(10) NOT NULL,
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL, -- exclusive
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- draft, submitted
+    data JSON NOT NULL, -- units, collections, towers, approvals, fund_usage
+    issues JSON,
+    acknowledgement_number VARCHAR(100),
+    generated_by VARCHAR(36),
+    generated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    submitted_by VARCHAR(36),
+    submitted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_tenant_project_quarter (tenant_id, project_id, quarter),
+    KEY idx_tenant_status (tenant_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- RERA QPR TEMPLATES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS rera_qpr_templates (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    state_code VARCHAR(10) NOT NULL, -- MH, KA, TN, ...
+    name VARCHAR(255) NOT NULL,
+    sections JSON NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_state (tenant_id, state_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,134 @@
+-- Snag List & Defect Liability
+-- Joint handover inspection checklist, the unit's RERA defect-liability period
+-- started at handover, snags with photos assigned to contractors under a
+-- severity SLA, and back-charges set off against vendor invoices
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- HANDOVER INSPECTIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS handover_inspections (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    inspection_date DATETIME NOT NULL,
+    customer_representative VARCHAR(200),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- draft, signed_off
+    handover_date DATETIME,
+    dlp_expiry DATETIME,
+    snag_count INT NOT NULL DEFAULT 0,
+    inspected_by VARCHAR(36),
+    signed_off_by VARCHAR(36),
+    signed_off_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_project (tenant_id, project_id, inspection_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS handover_checklist_items (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    inspection_id CHAR(36) NOT NULL,
+    line_number INT NOT NULL,
+    area VARCHAR(100) NOT NULL,
+    item VARCHAR(200) NOT NULL,
+    result VARCHAR(10) NOT NULL, -- ok, snag, na
+    remarks VARCHAR(500),
+    snag_id CHAR(36),
+    KEY idx_tenant_inspection (tenant_id, inspection_id, line_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- UNIT DEFECT LIABILITY PERIODS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS unit_defect_liability (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    inspection_id CHAR(36) NOT NULL,
+    handover_date DATETIME NOT NULL,
+    dlp_expiry DATETIME NOT NULL, -- handover + 5 years
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_unit (tenant_id, unit_id),
+    KEY idx_tenant_project_expiry (tenant_id, project_id, dlp_expiry)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- SNAGS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS snags (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    snag_number VARCHAR(50) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    inspection_id CHAR(36),
+    source VARCHAR(30) NOT NULL, -- handover_inspection, customer_portal, staff
+    category VARCHAR(30) NOT NULL, -- seepage, tiles, plumbing, electrical, carpentry, painting, doors_windows, fixtures, civil, other
+    severity VARCHAR(20) NOT NULL, -- critical, major, minor
+    location VARCHAR(200),
+    description TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, assigned, resolved, closed, rejected
+    contractor_id VARCHAR(36), -- vendors.id
+    assigned_at TIMESTAMP NULL,
+    sla_due_date TIMESTAMP NULL,
+    resolved_at TIMESTAMP NULL,
+    resolution_notes TEXT,
+    closed_at TIMESTAMP NULL,
+    review_remarks VARCHAR(500),
+    raised_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_number (tenant_id, snag_number),
+    KEY idx_tenant_unit (tenant_id, unit_id, status),
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_project_status (tenant_id, project_id, status),
+    KEY idx_tenant_contractor_sla (tenant_id, contractor_id, status, sla_due_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS snag_photos (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    snag_id CHAR(36) NOT NULL,
+    stage VARCHAR(20) NOT NULL, -- reported, resolved
+    photo_url VARCHAR(1000) NOT NULL,
+    uploaded_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_snag (tenant_id, snag_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- VENDOR BACK-CHARGES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS vendor_back_charges (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    back_charge_number VARCHAR(50) NOT NULL,
+    vendor_id VARCHAR(36) NOT NULL,
+    invoice_id VARCHAR(36) NOT NULL, -- vendor_invoices.id set off against
+    project_id VARCHAR(36),
+    snag_id CHAR(36),
+    amount DECIMAL(18, 2) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'applied', -- applied
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_number (tenant_id, back_charge_number),
+    KEY idx_tenant_invoice (tenant_id, invoice_id, status),
+    KEY idx_tenant_vendor (tenant_id, vendor_id),
+    KEY idx_tenant_snag (tenant_id, snag_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,140 @@
+-- Maintenance Billing & Association Handover
+-- Per-project maintenance tariff, unit maintenance accounts opened on possession,
+-- deposit and periodic CAM/sinking fund invoices, receipts allocated to invoices,
+-- and the handover of accounts and corpus to the residents' association
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- MAINTENANCE PLANS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS maintenance_plans (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    cam_rate_per_sqft DECIMAL(18, 4) NOT NULL DEFAULT 0, -- per month
+    sinking_fund_per_sqft DECIMAL(18, 4) NOT NULL DEFAULT 0, -- per month
+    deposit_per_sqft DECIMAL(18, 4) NOT NULL DEFAULT 0, -- one-time corpus
+    billing_frequency VARCHAR(20) NOT NULL DEFAULT 'monthly', -- monthly, quarterly, half_yearly, annual
+    gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 18.00,
+    gst_exempt_limit DECIMAL(18, 2) NOT NULL DEFAULT 7500.00, -- monthly contribution per unit
+    due_days INT NOT NULL DEFAULT 15,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE, -- false once handed over
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- MAINTENANCE ACCOUNTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS maintenance_accounts (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    customer_id VARCHAR(36),
+    area_sqft DECIMAL(18, 2) NOT NULL,
+    billing_start_date DATETIME NOT NULL, -- possession date
+    billed_up_to DATETIME, -- exclusive end of the last billed period
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, handed_over
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_unit (tenant_id, unit_id),
+    KEY idx_tenant_project_status (tenant_id, project_id, status),
+    KEY idx_tenant_booking (tenant_id, booking_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- MAINTENANCE INVOICES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS maintenance_invoices (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    invoice_number VARCHAR(50) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    invoice_type VARCHAR(20) NOT NULL, -- deposit, periodic
+    invoice_date DATETIME NOT NULL,
+    period_from DATETIME,
+    period_to DATETIME, -- exclusive
+    cam_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    sinking_fund_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    deposit_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    taxable_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    gst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(18, 2) NOT NULL,
+    amount_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    due_date DATETIME NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'unpaid', -- unpaid, partially_paid, paid
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_number (tenant_id, invoice_number),
+    UNIQUE KEY uk_account_period (account_id, invoice_type, period_from),
+    KEY idx_tenant_account_status (tenant_id, account_id, status, due_date),
+    KEY idx_tenant_project_status (tenant_id, project_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- MAINTENANCE RECEIPTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS maintenance_receipts (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    receipt_number VARCHAR(50) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    receipt_date DATETIME NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    payment_mode VARCHAR(30) NOT NULL,
+    reference VARCHAR(100),
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_number (tenant_id, receipt_number),
+    KEY idx_tenant_account (tenant_id, account_id, receipt_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS maintenance_receipt_allocations (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    receipt_id CHAR(36) NOT NULL,
+    invoice_id CHAR(36) NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    KEY idx_tenant_receipt (tenant_id, receipt_id),
+    KEY idx_tenant_invoice (tenant_id, invoice_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- ASSOCIATION HANDOVERS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS association_handovers (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    handover_number VARCHAR(50) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    association_name VARCHAR(200) NOT NULL,
+    registration_number VARCHAR(100),
+    bank_account VARCHAR(100) NOT NULL,
+    handover_date DATETIME NOT NULL,
+    corpus_transferred DECIMAL(18, 2) NOT NULL DEFAULT 0, -- deposit + sinking fund collected
+    transfer_reference VARCHAR(100) NOT NULL,
+    statement JSON NOT NULL, -- closing statement snapshot
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_project (tenant_id, project_id),
+    UNIQUE KEY uk_tenant_number (tenant_id, handover_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
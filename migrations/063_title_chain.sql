@@ -0,0 +1,115 @@
+-- Chain of Title & Encumbrances
+-- Parcels by survey number with their registered deeds and parties, recorded
+-- encumbrances, and the gaps found when tracing the 30-year chain of title.
+-- Gaps and subsisting encumbrances raise title_issues on the parcel's clearance.
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- PARCELS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS title_parcels (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    clearance_id BIGINT, -- title_clearances.id issues are raised on
+    survey_number VARCHAR(50) NOT NULL,
+    sub_division VARCHAR(50),
+    village VARCHAR(100) NOT NULL,
+    taluk VARCHAR(100),
+    district VARCHAR(100),
+    state VARCHAR(100),
+    area_acres DECIMAL(12, 4) DEFAULT 0,
+    land_type VARCHAR(50), -- agricultural, converted, urban
+    notes TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    KEY idx_tenant_survey (tenant_id, survey_number, village),
+    KEY idx_tenant_clearance (tenant_id, clearance_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- DEEDS & PARTIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS title_deeds (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_id BIGINT NOT NULL,
+    deed_type VARCHAR(30) NOT NULL, -- sale, gift, partition, jda, settlement, release, will, inheritance, court_decree
+    document_number VARCHAR(100) NOT NULL,
+    registration_date DATETIME NOT NULL,
+    execution_date DATETIME,
+    sub_registrar_office VARCHAR(200),
+    consideration DECIMAL(18, 2) DEFAULT 0,
+    document_url VARCHAR(1000),
+    notes TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    KEY idx_tenant_parcel_date (tenant_id, parcel_id, registration_date),
+    KEY idx_tenant_document (tenant_id, document_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS title_deed_parties (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    deed_id BIGINT NOT NULL,
+    party_role VARCHAR(30) NOT NULL, -- transferor, transferee, consenting_party, witness
+    party_name VARCHAR(255) NOT NULL,
+    party_type VARCHAR(30), -- individual, company, huf, trust, government
+    identity_reference VARCHAR(100),
+    KEY idx_tenant_deed (tenant_id, deed_id),
+    KEY idx_tenant_name (tenant_id, party_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- ENCUMBRANCES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS title_encumbrances (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_id BIGINT NOT NULL,
+    deed_id BIGINT,
+    encumbrance_type VARCHAR(30) NOT NULL, -- mortgage, lien, litigation, lease, easement, attachment
+    holder_name VARCHAR(255) NOT NULL,
+    reference_number VARCHAR(100), -- document or case number
+    recorded_date DATETIME NOT NULL,
+    amount DECIMAL(18, 2) DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'subsisting', -- subsisting, discharged
+    discharged_date DATETIME,
+    discharge_reference VARCHAR(100),
+    issue_id BIGINT, -- title_issues.id
+    notes TEXT,
+    created_by BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_parcel_status (tenant_id, parcel_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- CHAIN GAPS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS title_chain_gaps (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_id BIGINT NOT NULL,
+    gap_kind VARCHAR(30) NOT NULL, -- no_root_deed, broken_link, no_deeds
+    from_date DATETIME NOT NULL,
+    to_date DATETIME NOT NULL,
+    preceding_deed_id BIGINT,
+    following_deed_id BIGINT,
+    description VARCHAR(1000) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, closed
+    issue_id BIGINT, -- title_issues.id
+    detected_at DATETIME NOT NULL,
+    closed_at DATETIME,
+    KEY idx_tenant_parcel_status (tenant_id, parcel_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
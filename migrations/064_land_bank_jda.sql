@@ -0,0 +1,164 @@
+-- Land Bank & Joint Development Agreements
+-- Land parcels held ahead of a project with acquisition costs and payments,
+-- joint development agreements with their landowners, units allotted to
+-- landowners against the area share and revenue share payouts
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- LAND PARCELS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS land_parcels (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_code VARCHAR(50) NOT NULL,
+    survey_number VARCHAR(100) NOT NULL,
+    sub_division VARCHAR(100),
+    village VARCHAR(150) NOT NULL,
+    taluk VARCHAR(150),
+    district VARCHAR(150),
+    state VARCHAR(100),
+    area_acres DECIMAL(18, 4) NOT NULL,
+    zoning VARCHAR(50), -- residential, commercial, mixed_use, industrial, agricultural
+    permissible_fsi DECIMAL(8, 3) NOT NULL DEFAULT 0,
+    acquisition_mode VARCHAR(20) NOT NULL DEFAULT 'outright', -- outright, jda
+    status VARCHAR(30) NOT NULL DEFAULT 'identified', -- identified, under_negotiation, acquired, under_jda, dropped
+    project_id VARCHAR(36),
+    title_parcel_id BIGINT, -- title_parcels.id
+    notes TEXT,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_tenant_parcel_code (tenant_id, parcel_code),
+    KEY idx_tenant_survey (tenant_id, village, survey_number),
+    KEY idx_tenant_status (tenant_id, status),
+    KEY idx_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- LAND ACQUISITION COSTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS land_acquisition_costs (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_id VARCHAR(36) NOT NULL,
+    cost_type VARCHAR(30) NOT NULL, -- land_price, stamp_duty, registration, brokerage, conversion, legal, jda_deposit, other
+    payee VARCHAR(255) NOT NULL,
+    description TEXT,
+    cost_date DATETIME NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    amount_paid DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_parcel (tenant_id, parcel_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- LAND PAYMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS land_payments (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    parcel_id VARCHAR(36) NOT NULL,
+    cost_id VARCHAR(36) NOT NULL,
+    payment_date DATETIME NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    payment_mode VARCHAR(30) NOT NULL,
+    reference VARCHAR(100),
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_parcel (tenant_id, parcel_id),
+    KEY idx_tenant_cost (tenant_id, cost_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- JOINT DEVELOPMENT AGREEMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS joint_development_agreements (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    agreement_number VARCHAR(50) NOT NULL,
+    parcel_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL,
+    agreement_date DATETIME NOT NULL,
+    registration_number VARCHAR(100),
+    share_type VARCHAR(20) NOT NULL, -- area, revenue, hybrid
+    area_share_percent DECIMAL(5, 2) NOT NULL DEFAULT 0, -- landowners' share of saleable area
+    revenue_share_percent DECIMAL(5, 2) NOT NULL DEFAULT 0, -- landowners' share of collections
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_agreement (tenant_id, agreement_number),
+    KEY idx_tenant_parcel (tenant_id, parcel_id),
+    KEY idx_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- JDA LANDOWNERS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS jda_landowners (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    jda_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    pan VARCHAR(10),
+    share_percent DECIMAL(5, 2) NOT NULL, -- of the landowners' entitlement; all landowners add up to 100
+    bank_account VARCHAR(100),
+    KEY idx_tenant_jda (tenant_id, jda_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- JDA UNIT ALLOCATIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS jda_unit_allocations (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    jda_id VARCHAR(36) NOT NULL,
+    landowner_id VARCHAR(36) NOT NULL,
+    unit_id VARCHAR(36) NOT NULL,
+    sbua DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'allotted', -- allotted, released
+    allotted_at DATETIME NOT NULL,
+    released_at DATETIME,
+    created_by VARCHAR(36),
+    KEY idx_tenant_jda (tenant_id, jda_id),
+    KEY idx_tenant_unit_status (tenant_id, unit_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- JDA REVENUE SHARE PAYOUTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS jda_revenue_payouts (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    payout_number VARCHAR(50) NOT NULL,
+    jda_id VARCHAR(36) NOT NULL,
+    landowner_id VARCHAR(36) NOT NULL,
+    period_from DATETIME NOT NULL,
+    period_to DATETIME NOT NULL, -- exclusive
+    collections DECIMAL(18, 2) NOT NULL,
+    revenue_share_percent DECIMAL(5, 2) NOT NULL,
+    landowner_share_percent DECIMAL(5, 2) NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    payment_mode VARCHAR(30) NOT NULL,
+    reference VARCHAR(100),
+    paid_at DATETIME,
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_payout_number (tenant_id, payout_number),
+    KEY idx_tenant_jda_period (tenant_id, jda_id, period_from)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,92 @@
+-- Home Loan Disbursement Requests
+-- Lender request formats, disbursement requests raised to the customer's bank
+-- for construction-linked demands with their documents and consent, and credits
+-- received from lenders matched back to the requests
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- LENDER REQUEST FORMATS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS lender_request_formats (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    bank_id VARCHAR(36) NOT NULL,
+    format_name VARCHAR(100),
+    addressed_to VARCHAR(500),
+    subject_template VARCHAR(500) NOT NULL,
+    body_template TEXT NOT NULL, -- {{placeholders}} filled from the demand and loan
+    required_documents JSON NOT NULL, -- document types beyond demand letter, architect certificate and consent
+    submission_email VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_bank (tenant_id, bank_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- LOAN DISBURSEMENT REQUESTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS loan_disbursement_requests (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    request_number VARCHAR(50) NOT NULL,
+    financing_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    bank_id VARCHAR(36) NOT NULL,
+    loan_account_ref VARCHAR(100),
+    demand_letter_id CHAR(36) NOT NULL,
+    plan_stage_id CHAR(36) NOT NULL,
+    schedule_id VARCHAR(36),
+    milestone_id CHAR(36),
+    stage_name VARCHAR(255) NOT NULL,
+    demand_amount DECIMAL(18, 2) NOT NULL,
+    requested_amount DECIMAL(18, 2) NOT NULL,
+    disbursed_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    due_date DATE NOT NULL,
+    addressed_to VARCHAR(500),
+    subject VARCHAR(500) NOT NULL,
+    body TEXT NOT NULL,
+    documents JSON NOT NULL, -- [{document_type, reference, attached, attached_at}]
+    consent_at DATETIME,
+    consent_reference VARCHAR(255),
+    status VARCHAR(30) NOT NULL, -- pending_documents, ready, submitted, partially_disbursed, disbursed, rejected, cancelled
+    disbursement_id VARCHAR(36), -- bank_disbursement row created on submission
+    submitted_at DATETIME,
+    rejection_reason TEXT,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_request_number (tenant_id, request_number),
+    UNIQUE KEY uk_tenant_demand_letter (tenant_id, demand_letter_id),
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_tenant_financing_status (tenant_id, financing_id, status),
+    KEY idx_tenant_bank_status (tenant_id, bank_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- INCOMING LOAN DISBURSEMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS incoming_loan_disbursements (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    bank_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    credited_on DATETIME NOT NULL,
+    bank_reference VARCHAR(100) NOT NULL,
+    loan_account_ref VARCHAR(100),
+    booking_id VARCHAR(36),
+    request_id CHAR(36),
+    payment_id VARCHAR(36), -- booking_payments row recorded on match
+    match_status VARCHAR(20) NOT NULL DEFAULT 'unmatched', -- matched, unmatched
+    matched_by VARCHAR(10), -- auto, manual
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_bank_reference (tenant_id, bank_id, bank_reference),
+    KEY idx_tenant_match_status (tenant_id, match_status),
+    KEY idx_tenant_request (tenant_id, request_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
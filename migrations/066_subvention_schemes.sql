@@ -0,0 +1,78 @@
+-- Subvention Schemes (Builder-Paid Pre-EMI)
+-- Subvention agreements on a booking's bank financing, the monthly pre-EMI
+-- obligations provisioned to the GL as they accrue, and payments to lenders
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- SUBVENTION AGREEMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS subvention_agreements (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    agreement_number VARCHAR(50) NOT NULL,
+    financing_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    bank_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36),
+    scheme_name VARCHAR(255),
+    interest_rate DECIMAL(6, 3) NOT NULL, -- pre-EMI rate, % per annum
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL, -- scheme end, usually the committed possession date
+    max_amount DECIMAL(18, 2) NOT NULL DEFAULT 0, -- cap on interest borne, 0 for none
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, stopped, completed
+    stop_reason VARCHAR(20), -- possession, cancellation, manual
+    stopped_on DATE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_agreement (tenant_id, agreement_number),
+    KEY idx_tenant_financing (tenant_id, financing_id),
+    KEY idx_tenant_booking_status (tenant_id, booking_id, status),
+    KEY idx_tenant_project (tenant_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- SUBVENTION OBLIGATIONS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS subvention_obligations (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    agreement_id CHAR(36) NOT NULL,
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL, -- exclusive
+    due_date DATE NOT NULL,
+    loan_balance DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- scheduled, provisioned, paid, cancelled
+    journal_entry_id VARCHAR(100),
+    provisioned_at DATETIME,
+    payment_id CHAR(36),
+    paid_at DATETIME,
+    KEY idx_tenant_agreement (tenant_id, agreement_id, period_from),
+    KEY idx_tenant_status_period (tenant_id, status, period_to)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- SUBVENTION PAYMENTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS subvention_payments (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    payment_number VARCHAR(50) NOT NULL,
+    bank_id VARCHAR(36) NOT NULL,
+    payment_date DATETIME NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL,
+    payment_mode VARCHAR(30) NOT NULL,
+    reference VARCHAR(100),
+    journal_entry_id VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_payment_number (tenant_id, payment_number),
+    KEY idx_tenant_bank (tenant_id, bank_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
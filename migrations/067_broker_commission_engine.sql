@@ -0,0 +1,104 @@
+-- Broker Commission Engine
+-- Commission plans with triggers, project overrides and quarterly volume slabs,
+-- commission splits between brokers on a booking, commission accruals with slab
+-- re-rating, and broker payouts net of TDS and clawbacks
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- BROKER COMMISSION PLANS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS broker_commission_plans (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    broker_id VARCHAR(36), -- NULL applies to all brokers
+    project_id VARCHAR(36), -- NULL applies to all projects
+    trigger_event VARCHAR(30) NOT NULL, -- agreement_signed, collection_percent
+    trigger_percent DECIMAL(5, 2) NOT NULL DEFAULT 0, -- share of agreement value collected
+    slab_basis VARCHAR(10) NOT NULL DEFAULT 'units', -- units, value
+    slabs JSON NOT NULL, -- [{min_volume, rate}] by quarterly volume
+    gst_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, inactive
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_status (tenant_id, status),
+    KEY idx_tenant_broker_project (tenant_id, broker_id, project_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BROKER COMMISSION SPLITS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS broker_commission_splits (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    link_id VARCHAR(36) NOT NULL,
+    broker_id VARCHAR(36) NOT NULL,
+    split_percent DECIMAL(7, 4) NOT NULL,
+    UNIQUE KEY uk_tenant_link (tenant_id, link_id),
+    KEY idx_tenant_booking (tenant_id, booking_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BROKER COMMISSION ACCRUALS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS broker_commission_accruals (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    link_id VARCHAR(36) NOT NULL,
+    broker_id VARCHAR(36) NOT NULL,
+    booking_id VARCHAR(36) NOT NULL,
+    project_id VARCHAR(36) NOT NULL DEFAULT '',
+    plan_id CHAR(36) NOT NULL,
+    quarter VARCHAR(7) NOT NULL, -- YYYY-Qn the trigger fell in
+    kind VARCHAR(20) NOT NULL, -- commission, slab_adjustment
+    base_amount DECIMAL(18, 2) NOT NULL, -- agreement value
+    rate DECIMAL(6, 3) NOT NULL,
+    split_percent DECIMAL(7, 4) NOT NULL DEFAULT 100,
+    commission DECIMAL(18, 2) NOT NULL, -- negative for a downward re-rating
+    gst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'accrued', -- accrued, paid, cancelled, clawed_back
+    payout_id CHAR(36),
+    triggered_on DATETIME NOT NULL,
+    cancelled_at DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_link (tenant_id, link_id, status),
+    KEY idx_tenant_broker_quarter (tenant_id, broker_id, plan_id, quarter),
+    KEY idx_tenant_broker_status (tenant_id, broker_id, status),
+    KEY idx_tenant_booking (tenant_id, booking_id),
+    KEY idx_payout (payout_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- BROKER PAYOUTS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS broker_payouts (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    payout_number VARCHAR(50) NOT NULL,
+    broker_id VARCHAR(36) NOT NULL,
+    commission DECIMAL(18, 2) NOT NULL,
+    gst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    tds_amount DECIMAL(18, 2) NOT NULL DEFAULT 0, -- section 194H
+    clawback_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'generated', -- generated, paid
+    payment_date DATETIME,
+    payment_mode VARCHAR(30),
+    reference VARCHAR(100),
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_payout_number (tenant_id, payout_number),
+    KEY idx_tenant_broker_status (tenant_id, broker_id, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,148 @@
+-- Channel Partner Portal
+-- Partner lead submissions, lead credits, partner activity and payout line items
+-- used by the partner services, payout columns aligned with the payout service,
+-- and invoices uploaded by partners against their payouts. Partner ids move to
+-- CHAR(36) UUIDs.
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+-- ============================================
+-- PARTNERS AND PARTNER USERS
+-- ============================================
+
+ALTER TABLE partners
+    MODIFY id CHAR(36) NOT NULL,
+    MODIFY approved_by VARCHAR(36);
+
+ALTER TABLE partner_users
+    MODIFY id CHAR(36) NOT NULL;
+
+-- ============================================
+-- PARTNER LEADS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS partner_leads (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    partner_id VARCHAR(36) NOT NULL,
+    lead_id VARCHAR(36), -- sales lead created on approval
+    submission_type VARCHAR(20) NOT NULL DEFAULT 'new_lead', -- new_lead, referral, import_batch
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted', -- submitted, under_review, approved, rejected, converted
+    lead_data JSON NOT NULL,
+    quality_score DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    rejection_reason VARCHAR(500) NOT NULL DEFAULT '',
+    reviewed_by VARCHAR(36),
+    reviewed_at DATETIME,
+    submitted_by VARCHAR(36) NOT NULL, -- partner user
+    conversion_date DATETIME,
+    credit_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    credit_status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected, paid
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    KEY idx_tenant_partner_status (tenant_id, partner_id, status),
+    KEY idx_tenant_partner_created (tenant_id, partner_id, created_at),
+    KEY idx_tenant_lead (tenant_id, lead_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- PARTNER LEAD CREDITS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS partner_lead_credits (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    partner_lead_id CHAR(36) NOT NULL,
+    partner_id VARCHAR(36) NOT NULL,
+    credit_amount DECIMAL(18, 2) NOT NULL,
+    calculation_type VARCHAR(20) NOT NULL, -- percentage, fixed_price
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_approval', -- pending_approval, approved, rejected
+    approved_by VARCHAR(36),
+    approved_at DATETIME,
+    rejection_reason VARCHAR(500) NOT NULL DEFAULT '',
+    notes VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_tenant_partner (tenant_id, partner_id, status),
+    KEY idx_partner_lead (partner_lead_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- PARTNER ACTIVITIES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS partner_activities (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    partner_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36),
+    action VARCHAR(50) NOT NULL, -- lead_submitted, portal_login, invoice_uploaded
+    resource VARCHAR(30) NOT NULL, -- lead, payout, invoice, partner_user
+    resource_id VARCHAR(36) NOT NULL DEFAULT '',
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_tenant_partner_created (tenant_id, partner_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- PARTNER PAYOUTS
+-- ============================================
+
+ALTER TABLE partner_payouts
+    MODIFY id CHAR(36) NOT NULL,
+    MODIFY status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, partially_approved, rejected, paid
+    MODIFY payment_method VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN total_leads_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN approved_leads INT NOT NULL DEFAULT 0,
+    ADD COLUMN converted_leads INT NOT NULL DEFAULT 0,
+    ADD COLUMN total_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN approved_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rejected_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN payment_date DATETIME,
+    ADD COLUMN reference_number VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN reviewed_by VARCHAR(36),
+    ADD COLUMN approved_by VARCHAR(36),
+    ADD COLUMN approved_at DATETIME,
+    ADD COLUMN rejection_notes VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN notes VARCHAR(500) NOT NULL DEFAULT '';
+
+-- ============================================
+-- PARTNER PAYOUT DETAILS
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS partner_payout_details (
+    id CHAR(36) PRIMARY KEY,
+    payout_id CHAR(36) NOT NULL,
+    partner_lead_id CHAR(36) NOT NULL,
+    lead_submission_id VARCHAR(36) NOT NULL DEFAULT '',
+    amount DECIMAL(18, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'approved', -- approved, rejected
+    approval_notes VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_payout (payout_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- PARTNER INVOICES
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS partner_invoices (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(36) NOT NULL,
+    partner_id VARCHAR(36) NOT NULL,
+    payout_id CHAR(36) NOT NULL,
+    invoice_number VARCHAR(50) NOT NULL,
+    invoice_date DATE NOT NULL,
+    amount DECIMAL(18, 2) NOT NULL, -- taxable value
+    gst_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(18, 2) NOT NULL,
+    file_url VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted', -- submitted, accepted, rejected
+    uploaded_by VARCHAR(36) NOT NULL, -- partner user
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_tenant_partner_invoice (tenant_id, partner_id, invoice_number),
+    KEY idx_tenant_payout (tenant_id, payout_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET FOREIGN_KEY_CHECKS = 1;
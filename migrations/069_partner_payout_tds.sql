@@ -0,0 +1,11 @@
+-- Partner Payout TDS
+-- TDS deducted under section 194H and the net amount paid, recorded on the
+-- payout when it is marked paid
+
+-- ============================================
+-- PARTNER PAYOUTS
+-- ============================================
+
+ALTER TABLE partner_payouts
+    ADD COLUMN tds_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN net_amount DECIMAL(18, 2) NOT NULL DEFAULT 0;
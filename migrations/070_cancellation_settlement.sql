@@ -0,0 +1,12 @@
+-- Cancellation Settlement
+-- An approved cancellation stays approved_pending_settlement until the brokerage
+-- clawback, GL reversal, subvention stop and lender NOC have all gone through,
+-- with the last failure kept for the retry
+
+-- ============================================
+-- BOOKING CANCELLATIONS
+-- ============================================
+
+ALTER TABLE booking_cancellations
+    MODIFY COLUMN status VARCHAR(30) NOT NULL, -- requested, approved_pending_settlement, approved, rejected, refunded
+    ADD COLUMN settlement_error TEXT NULL AFTER journal_entry_id;
@@ -0,0 +1,10 @@
+-- Payment Run Bank File Failure
+-- A run whose lines were paid but whose bank file could not be generated or saved
+-- is left bank_file_failed with the error, and can be processed again
+
+-- ============================================
+-- VENDOR PAYMENT RUNS
+-- ============================================
+
+ALTER TABLE vendor_payment_runs
+    ADD COLUMN failure_reason TEXT NULL AFTER bank_file_content;
@@ -0,0 +1,11 @@
+-- Partner Invoice Documents
+-- Partner invoices refer to the uploaded invoice file in the document store,
+-- attached to the payout, instead of a URL supplied by the partner
+
+-- ============================================
+-- PARTNER INVOICES
+-- ============================================
+
+ALTER TABLE partner_invoices
+    MODIFY COLUMN file_url VARCHAR(500) NULL,
+    ADD COLUMN document_id CHAR(26) NULL AFTER total_amount;
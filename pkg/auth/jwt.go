@@ -47,6 +47,10 @@ func (j *JWTManager) ValidateToken(tokenString string) (*jwt.MapClaims, error) {
     }
 
     if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+        // Partner portal tokens never authenticate staff requests
+        if claims["aud"] == PartnerAudience {
+            return nil, errors.New("invalid token")
+        }
         return &claims, nil
     }
 
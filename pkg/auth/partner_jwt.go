@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PartnerAudience marks tokens issued to channel partner portal users
+const PartnerAudience = "partner-portal"
+
+// PartnerClaims identifies a partner portal user and what they may do
+type PartnerClaims struct {
+	PartnerUserID string
+	PartnerID     string
+	TenantID      string
+	Role          string
+	Scopes        []string
+}
+
+// GeneratePartnerToken issues a partner portal token and returns it with its expiry
+func (j *JWTManager) GeneratePartnerToken(c *PartnerClaims) (string, time.Time, error) {
+	expiresAt := time.Now().Add(j.expiration)
+	claims := jwt.MapClaims{
+		"aud":             PartnerAudience,
+		"sub":             c.PartnerUserID,
+		"partner_user_id": c.PartnerUserID,
+		"partner_id":      c.PartnerID,
+		"tenant_id":       c.TenantID,
+		"role":            c.Role,
+		"scopes":          c.Scopes,
+		"exp":             expiresAt.Unix(),
+		"iat":             time.Now().Unix(),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.secret)
+	return token, expiresAt, err
+}
+
+// ValidatePartnerToken accepts only partner portal tokens signed with this manager's secret
+func (j *JWTManager) ValidatePartnerToken(tokenString string) (*PartnerClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return j.secret, nil
+	}, jwt.WithAudience(PartnerAudience))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	c := &PartnerClaims{}
+	c.PartnerUserID, _ = claims["partner_user_id"].(string)
+	c.PartnerID, _ = claims["partner_id"].(string)
+	c.TenantID, _ = claims["tenant_id"].(string)
+	c.Role, _ = claims["role"].(string)
+	if scopes, ok := claims["scopes"].([]interface{}); ok {
+		for _, s := range scopes {
+			if scope, ok := s.(string); ok {
+				c.Scopes = append(c.Scopes, scope)
+			}
+		}
+	}
+	if c.PartnerUserID == "" || c.PartnerID == "" || c.TenantID == "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return c, nil
+}
@@ -2,11 +2,13 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"vyomtech-backend/internal/handlers"
 	"vyomtech-backend/internal/middleware"
+	"vyomtech-backend/internal/models"
 	"vyomtech-backend/internal/services"
 	"vyomtech-backend/pkg/logger"
 )
@@ -169,9 +171,27 @@ func SetupRoutesWithPhase3C(
 	siteVisitHandler *handlers.SiteVisitHandler,
 	integrationHandler *handlers.IntegrationHandler,
 	bankFinancingHandler *handlers.BankFinancingHandler,
+	receivablesHandler *handlers.ReceivablesHandler,
+	delayedInterestHandler *handlers.DelayedInterestHandler,
+	payablesHandler *handlers.PayablesHandler,
+	einvoiceHandler *handlers.EInvoiceHandler,
+	tdsHandler *handlers.TDSHandler,
+	paymentPlanHandler *handlers.PaymentPlanHandler,
+	priceListHandler *handlers.PriceListHandler,
+	discountApprovalHandler *handlers.DiscountApprovalHandler,
+	bookingCancellationHandler *handlers.BookingCancellationHandler,
+	unitTransferHandler *handlers.UnitTransferHandler,
+	unitAvailabilityHandler *handlers.UnitAvailabilityHandler,
+	snagHandler *handlers.SnagHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	landBankHandler *handlers.LandBankHandler,
+	loanDisbursementHandler *handlers.LoanDisbursementHandler,
+	subventionHandler *handlers.SubventionHandler,
+	brokerCommissionHandler *handlers.BrokerCommissionHandler,
+	partnerPortalHandler *handlers.PartnerPortalHandler,
 	log *logger.Logger,
 ) *mux.Router {
-	return setupRoutes(authService, tenantService, passwordResetHandler, agentService, gamificationService, leadService, callService, campaignService, aiOrchestrator, webSocketHub, leadScoringService, dashboardService, taskService, notificationService, customizationService, phase3cServices, salesService, realEstateService, civilService, constructionService, boqService, hrService, glService, rbacService, reraComplianceHandler, hrComplianceHandler, taxComplianceHandler, financialDashboardHandler, hrDashboardHandler, complianceDashboardHandler, salesDashboardHandler, brokerHandler, jointApplicantHandler, documentHandler, possessionHandler, titleHandler, customerPortalHandler, analyticsHandler, userAdminHandler, tenantAdminHandler, mobileHandler, aiRecommendationsHandler, siteVisitHandler, integrationHandler, bankFinancingHandler, log)
+	return setupRoutes(authService, tenantService, passwordResetHandler, agentService, gamificationService, leadService, callService, campaignService, aiOrchestrator, webSocketHub, leadScoringService, dashboardService, taskService, notificationService, customizationService, phase3cServices, salesService, realEstateService, civilService, constructionService, boqService, hrService, glService, rbacService, reraComplianceHandler, hrComplianceHandler, taxComplianceHandler, financialDashboardHandler, hrDashboardHandler, complianceDashboardHandler, salesDashboardHandler, brokerHandler, jointApplicantHandler, documentHandler, possessionHandler, titleHandler, customerPortalHandler, analyticsHandler, userAdminHandler, tenantAdminHandler, mobileHandler, aiRecommendationsHandler, siteVisitHandler, integrationHandler, bankFinancingHandler, receivablesHandler, delayedInterestHandler, payablesHandler, einvoiceHandler, tdsHandler, paymentPlanHandler, priceListHandler, discountApprovalHandler, bookingCancellationHandler, unitTransferHandler, unitAvailabilityHandler, snagHandler, maintenanceHandler, landBankHandler, loanDisbursementHandler, subventionHandler, brokerCommissionHandler, partnerPortalHandler, log)
 }
 
 func setupRoutes(
@@ -220,6 +240,24 @@ func setupRoutes(
 	siteVisitHandler *handlers.SiteVisitHandler,
 	integrationHandler *handlers.IntegrationHandler,
 	bankFinancingHandler *handlers.BankFinancingHandler,
+	receivablesHandler *handlers.ReceivablesHandler,
+	delayedInterestHandler *handlers.DelayedInterestHandler,
+	payablesHandler *handlers.PayablesHandler,
+	einvoiceHandler *handlers.EInvoiceHandler,
+	tdsHandler *handlers.TDSHandler,
+	paymentPlanHandler *handlers.PaymentPlanHandler,
+	priceListHandler *handlers.PriceListHandler,
+	discountApprovalHandler *handlers.DiscountApprovalHandler,
+	bookingCancellationHandler *handlers.BookingCancellationHandler,
+	unitTransferHandler *handlers.UnitTransferHandler,
+	unitAvailabilityHandler *handlers.UnitAvailabilityHandler,
+	snagHandler *handlers.SnagHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	landBankHandler *handlers.LandBankHandler,
+	loanDisbursementHandler *handlers.LoanDisbursementHandler,
+	subventionHandler *handlers.SubventionHandler,
+	brokerCommissionHandler *handlers.BrokerCommissionHandler,
+	partnerPortalHandler *handlers.PartnerPortalHandler,
 	log *logger.Logger,
 ) *mux.Router {
 	r := mux.NewRouter()
@@ -871,7 +909,16 @@ func setupRoutes(
 
 	// ============================================
 	if realEstateService != nil {
-		realEstateHandler := handlers.NewRealEstateHandler(realEstateService.DB, rbacService)
+		var escrow *services.RERAComplianceService
+		if reraComplianceHandler != nil {
+			escrow = reraComplianceHandler.Service
+		}
+		priceLists := services.NewPriceListService(realEstateService.DB)
+		if priceListHandler != nil {
+			priceLists = priceListHandler.Service
+		}
+		realEstateHandler := handlers.NewRealEstateHandler(realEstateService.DB, rbacService,
+			services.NewUnitAvailabilityService(realEstateService.DB, webSocketHub), escrow, priceLists)
 		realEstateRoutes := v1.PathPrefix("/real-estate").Subrouter()
 		realEstateRoutes.Use(middleware.AuthMiddleware(authService, log))
 		realEstateRoutes.Use(middleware.TenantIsolationMiddleware(log))
@@ -1179,6 +1226,28 @@ func setupRoutes(
 		// Approval endpoints
 		titleRoutes.HandleFunc("/{clearance_id}/approvals", titleHandler.ListClearanceApprovals).Methods("GET")
 		titleRoutes.HandleFunc("/{clearance_id}/approvals/{approval_id}/approve", titleHandler.ApproveClearance).Methods("PATCH")
+
+		// Chain of title: parcels, deeds, encumbrances and gap analysis
+		chainRoutes := v1.PathPrefix("/title-chain").Subrouter()
+		chainRoutes.Use(middleware.AuthMiddleware(authService, log))
+		chainRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		chainRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "legal"},
+			log,
+		))
+
+		chainRoutes.HandleFunc("/parcels", titleHandler.CreateTitleParcel).Methods("POST")
+		chainRoutes.HandleFunc("/parcels", titleHandler.ListTitleParcels).Methods("GET")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}", titleHandler.GetTitleParcel).Methods("GET")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/deeds", titleHandler.CreateTitleDeed).Methods("POST")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/deeds", titleHandler.ListTitleDeeds).Methods("GET")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/encumbrances", titleHandler.CreateTitleEncumbrance).Methods("POST")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/encumbrances", titleHandler.ListTitleEncumbrances).Methods("GET")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/analyze", titleHandler.AnalyzeTitleChain).Methods("POST")
+		chainRoutes.HandleFunc("/parcels/{parcel_id}/gaps", titleHandler.ListTitleChainGaps).Methods("GET")
+		chainRoutes.HandleFunc("/encumbrances/{encumbrance_id}/discharge", titleHandler.DischargeTitleEncumbrance).Methods("PATCH")
+		chainRoutes.HandleFunc("/timeline", titleHandler.GetSurveyTimeline).Methods("GET")
 	}
 
 	// CUSTOMER PORTAL ROUTES (Phase 2.3)
@@ -1212,6 +1281,27 @@ func setupRoutes(
 		// Customer booking tracking endpoints
 		bookingRoutes := customerRoutes.PathPrefix("/bookings").Subrouter()
 		bookingRoutes.HandleFunc("/{booking_id}/tracking", customerPortalHandler.GetBookingTracking).Methods("GET")
+		if tdsHandler != nil {
+			bookingRoutes.HandleFunc("/{booking_id}/tds", tdsHandler.RecordBuyerTDS).Methods("POST")
+			bookingRoutes.HandleFunc("/{booking_id}/tds", tdsHandler.ListBuyerTDS).Methods("GET")
+			bookingRoutes.HandleFunc("/{booking_id}/tds/{id}/challan", tdsHandler.UpdateBuyerTDSChallan).Methods("PUT")
+			bookingRoutes.HandleFunc("/{booking_id}/tds/{id}/form16b", tdsHandler.UploadForm16B).Methods("POST")
+		}
+		if snagHandler != nil {
+			bookingRoutes.HandleFunc("/{booking_id}/dlp", snagHandler.GetCustomerDefectLiability).Methods("GET")
+			bookingRoutes.HandleFunc("/{booking_id}/snags", snagHandler.RaiseCustomerSnag).Methods("POST")
+			bookingRoutes.HandleFunc("/{booking_id}/snags", snagHandler.ListCustomerSnags).Methods("GET")
+			bookingRoutes.HandleFunc("/{booking_id}/snags/{id}/photos", snagHandler.AddSnagPhotos).Methods("POST")
+			bookingRoutes.HandleFunc("/{booking_id}/snags/{id}/review", snagHandler.ReviewSnag).Methods("POST")
+		}
+		if maintenanceHandler != nil {
+			bookingRoutes.HandleFunc("/{booking_id}/maintenance", maintenanceHandler.GetCustomerMaintenance).Methods("GET")
+		}
+		if loanDisbursementHandler != nil {
+			bookingRoutes.HandleFunc("/{booking_id}/loan-funding", loanDisbursementHandler.GetFundingSplit).Methods("GET")
+			bookingRoutes.HandleFunc("/{booking_id}/loan-requests", loanDisbursementHandler.ListCustomerRequests).Methods("GET")
+			bookingRoutes.HandleFunc("/{booking_id}/loan-requests/{id}/consent", loanDisbursementHandler.RecordConsent).Methods("POST")
+		}
 
 		// Customer payment tracking endpoints
 		paymentRoutes := customerRoutes.PathPrefix("/payments").Subrouter()
@@ -1494,6 +1584,544 @@ func setupRoutes(
 		bankFinancingRoutes.HandleFunc("/banks", bankFinancingHandler.ListBanksHTTP).Methods("GET")
 	}
 
+	// ============================================
+	// RECEIVABLES (AR AGEING & DUNNING) ROUTES
+	// ============================================
+	if receivablesHandler != nil {
+		receivablesRoutes := v1.PathPrefix("/receivables").Subrouter()
+		receivablesRoutes.Use(middleware.AuthMiddleware(authService, log))
+		receivablesRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		receivablesRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Ageing
+		receivablesRoutes.HandleFunc("/ageing", receivablesHandler.GetAgeingReport).Methods("GET")
+		receivablesRoutes.HandleFunc("/outstanding", receivablesHandler.GetOutstandingItems).Methods("GET")
+
+		// Dunning
+		receivablesRoutes.HandleFunc("/dunning/run", receivablesHandler.RunDunning).Methods("POST")
+		receivablesRoutes.HandleFunc("/dunning/bookings/{booking_id}", receivablesHandler.ListDunningNotices).Methods("GET")
+	}
+
+	// ============================================
+	// DELAYED-PAYMENT INTEREST (RERA) ROUTES
+	// ============================================
+	if delayedInterestHandler != nil {
+		interestRoutes := v1.PathPrefix("/interest").Subrouter()
+		interestRoutes.Use(middleware.AuthMiddleware(authService, log))
+		interestRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		interestRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Rate history & policy
+		interestRoutes.HandleFunc("/rates", delayedInterestHandler.AddInterestRate).Methods("POST")
+		interestRoutes.HandleFunc("/rates", delayedInterestHandler.ListInterestRates).Methods("GET")
+		interestRoutes.HandleFunc("/policy", delayedInterestHandler.UpsertInterestPolicy).Methods("PUT")
+		interestRoutes.HandleFunc("/policy", delayedInterestHandler.GetInterestPolicy).Methods("GET")
+
+		// Delayed installments
+		interestRoutes.HandleFunc("/accrued", delayedInterestHandler.GetAccruedInterest).Methods("GET")
+		interestRoutes.HandleFunc("/notes/raise", delayedInterestHandler.RaiseInterestNotes).Methods("POST")
+		interestRoutes.HandleFunc("/notes/bookings/{booking_id}", delayedInterestHandler.ListInterestNotes).Methods("GET")
+
+		// Delayed possession
+		interestRoutes.HandleFunc("/possession-compensation", delayedInterestHandler.ComputePossessionCompensation).Methods("POST")
+	}
+
+	// ============================================
+	// ACCOUNTS PAYABLE ROUTES
+	// ============================================
+	if payablesHandler != nil {
+		payablesRoutes := v1.PathPrefix("/payables").Subrouter()
+		payablesRoutes.Use(middleware.AuthMiddleware(authService, log))
+		payablesRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		payablesRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// AP ageing
+		payablesRoutes.HandleFunc("/ageing", payablesHandler.GetAPAgeingReport).Methods("GET")
+		payablesRoutes.HandleFunc("/open", payablesHandler.GetOpenPayables).Methods("GET")
+
+		// Vendor payment setup
+		payablesRoutes.HandleFunc("/vendors/{vendor_id}/bank-account", payablesHandler.UpsertVendorBankAccount).Methods("PUT")
+		payablesRoutes.HandleFunc("/vendors/{vendor_id}/tds-section", payablesHandler.SetVendorTDSSection).Methods("PUT")
+		payablesRoutes.HandleFunc("/bank-formats", payablesHandler.CreateBankPaymentFormat).Methods("POST")
+		payablesRoutes.HandleFunc("/bank-formats", payablesHandler.ListBankPaymentFormats).Methods("GET")
+
+		// Payment runs
+		payablesRoutes.HandleFunc("/payment-runs", payablesHandler.CreatePaymentProposal).Methods("POST")
+		payablesRoutes.HandleFunc("/payment-runs", payablesHandler.ListPaymentRuns).Methods("GET")
+		payablesRoutes.HandleFunc("/payment-runs/{id}", payablesHandler.GetPaymentRun).Methods("GET")
+		payablesRoutes.HandleFunc("/payment-runs/{id}/approve", payablesHandler.ApprovePaymentRun).Methods("POST")
+		payablesRoutes.HandleFunc("/payment-runs/{id}/process", payablesHandler.ProcessPaymentRun).Methods("POST")
+		payablesRoutes.HandleFunc("/payment-runs/{id}/bank-file", payablesHandler.DownloadBankFile).Methods("GET")
+		payablesRoutes.HandleFunc("/back-charges", payablesHandler.ListVendorBackCharges).Methods("GET")
+
+		// Three-way match
+		payablesRoutes.HandleFunc("/purchase-orders", payablesHandler.CreatePurchaseOrder).Methods("POST")
+		payablesRoutes.HandleFunc("/goods-receipts", payablesHandler.CreateGoodsReceipt).Methods("POST")
+		payablesRoutes.HandleFunc("/invoices", payablesHandler.CreateVendorInvoice).Methods("POST")
+		payablesRoutes.HandleFunc("/invoices/{id}/match", payablesHandler.MatchVendorInvoice).Methods("POST")
+		payablesRoutes.HandleFunc("/invoices/{id}/post-to-gl", payablesHandler.PostInvoiceToGL).Methods("POST")
+		payablesRoutes.HandleFunc("/match-exceptions", payablesHandler.ListMatchExceptions).Methods("GET")
+		payablesRoutes.HandleFunc("/match-exceptions/{id}/resolve", payablesHandler.ResolveMatchException).Methods("POST")
+		payablesRoutes.HandleFunc("/match-tolerance", payablesHandler.GetMatchTolerance).Methods("GET")
+		payablesRoutes.HandleFunc("/match-tolerance", payablesHandler.UpsertMatchTolerance).Methods("PUT")
+	}
+
+	// ============================================
+	// GST E-INVOICE / E-WAY BILL ROUTES
+	// ============================================
+	if einvoiceHandler != nil {
+		einvoiceRoutes := v1.PathPrefix("/einvoice").Subrouter()
+		einvoiceRoutes.Use(middleware.AuthMiddleware(authService, log))
+		einvoiceRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		einvoiceRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		einvoiceRoutes.HandleFunc("/settings", einvoiceHandler.GetSettings).Methods("GET")
+		einvoiceRoutes.HandleFunc("/settings", einvoiceHandler.UpsertSettings).Methods("PUT")
+
+		// IRN for sales invoices
+		einvoiceRoutes.HandleFunc("/invoices/{invoice_id}", einvoiceHandler.GetEInvoice).Methods("GET")
+		einvoiceRoutes.HandleFunc("/invoices/{invoice_id}/payload", einvoiceHandler.PreviewPayload).Methods("GET")
+		einvoiceRoutes.HandleFunc("/invoices/{invoice_id}/irn", einvoiceHandler.GenerateIRN).Methods("POST")
+		einvoiceRoutes.HandleFunc("/invoices/{invoice_id}/cancel", einvoiceHandler.CancelIRN).Methods("POST")
+
+		// E-way bills for material movement between sites
+		einvoiceRoutes.HandleFunc("/eway-bills/transfers/{transfer_id}", einvoiceHandler.GenerateTransferEWayBill).Methods("POST")
+		einvoiceRoutes.HandleFunc("/eway-bills/{id}", einvoiceHandler.GetEWayBill).Methods("GET")
+		einvoiceRoutes.HandleFunc("/eway-bills/{id}/cancel", einvoiceHandler.CancelEWayBill).Methods("POST")
+	}
+
+	// ============================================
+	// TDS ROUTES
+	// ============================================
+	if tdsHandler != nil {
+		tdsRoutes := v1.PathPrefix("/tds").Subrouter()
+		tdsRoutes.Use(middleware.AuthMiddleware(authService, log))
+		tdsRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		tdsRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		tdsRoutes.HandleFunc("/sections", tdsHandler.GetSectionRules).Methods("GET")
+		tdsRoutes.HandleFunc("/compute", tdsHandler.ComputeTDS).Methods("POST")
+		tdsRoutes.HandleFunc("/property-purchases", tdsHandler.RecordPropertyPurchaseTDS).Methods("POST")
+
+		// TDS payable and deposits
+		tdsRoutes.HandleFunc("/ledger", tdsHandler.GetPayableLedger).Methods("GET")
+		tdsRoutes.HandleFunc("/challans", tdsHandler.CreateChallan).Methods("POST")
+		tdsRoutes.HandleFunc("/challans", tdsHandler.ListChallans).Methods("GET")
+
+		// Quarterly 26Q / 27Q statements
+		tdsRoutes.HandleFunc("/returns", tdsHandler.GetQuarterlyReturn).Methods("GET")
+
+		// Buyer TDS (26QB) on bookings and 26AS reconciliation
+		tdsRoutes.HandleFunc("/buyer-deductions", tdsHandler.RecordBuyerTDS).Methods("POST")
+		tdsRoutes.HandleFunc("/buyer-deductions", tdsHandler.ListBuyerTDS).Methods("GET")
+		tdsRoutes.HandleFunc("/buyer-deductions/{id}/challan", tdsHandler.UpdateBuyerTDSChallan).Methods("PUT")
+		tdsRoutes.HandleFunc("/buyer-deductions/{id}/form16b", tdsHandler.UploadForm16B).Methods("POST")
+		tdsRoutes.HandleFunc("/26as/import", tdsHandler.Import26AS).Methods("POST")
+		tdsRoutes.HandleFunc("/26as/reconcile", tdsHandler.Reconcile26AS).Methods("POST")
+	}
+
+	// ============================================
+	// PAYMENT PLAN ROUTES
+	// ============================================
+	if paymentPlanHandler != nil {
+		paymentPlanRoutes := v1.PathPrefix("/payment-plans").Subrouter()
+		paymentPlanRoutes.Use(middleware.AuthMiddleware(authService, log))
+		paymentPlanRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		paymentPlanRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales", "accountant"},
+			log,
+		))
+
+		paymentPlanRoutes.HandleFunc("/templates", paymentPlanHandler.CreateTemplate).Methods("POST")
+		paymentPlanRoutes.HandleFunc("/templates", paymentPlanHandler.ListTemplates).Methods("GET")
+		paymentPlanRoutes.HandleFunc("/templates/{id}", paymentPlanHandler.GetTemplate).Methods("GET")
+		paymentPlanRoutes.HandleFunc("/bookings/{booking_id}", paymentPlanHandler.AssignPlan).Methods("POST")
+		paymentPlanRoutes.HandleFunc("/bookings/{booking_id}", paymentPlanHandler.GetBookingPlan).Methods("GET")
+
+		// Tower milestones and the demand letters they trigger
+		paymentPlanRoutes.HandleFunc("/milestones", paymentPlanHandler.CreateMilestones).Methods("POST")
+		paymentPlanRoutes.HandleFunc("/milestones", paymentPlanHandler.ListMilestones).Methods("GET")
+		paymentPlanRoutes.HandleFunc("/milestones/{id}/complete", paymentPlanHandler.CompleteMilestone).Methods("POST")
+		paymentPlanRoutes.HandleFunc("/milestones/{id}/demands", paymentPlanHandler.RaiseMilestoneDemands).Methods("POST")
+		paymentPlanRoutes.HandleFunc("/demand-letters", paymentPlanHandler.ListDemandLetters).Methods("GET")
+	}
+
+	// ============================================
+	// PRICE LIST ROUTES
+	// ============================================
+	if priceListHandler != nil {
+		priceListRoutes := v1.PathPrefix("/price-lists").Subrouter()
+		priceListRoutes.Use(middleware.AuthMiddleware(authService, log))
+		priceListRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		priceListRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales"},
+			log,
+		))
+
+		priceListRoutes.HandleFunc("", priceListHandler.CreatePriceList).Methods("POST")
+		priceListRoutes.HandleFunc("", priceListHandler.ListPriceLists).Methods("GET")
+		priceListRoutes.HandleFunc("/{id}", priceListHandler.GetPriceList).Methods("GET")
+		priceListRoutes.HandleFunc("/{id}/publish", priceListHandler.PublishPriceList).Methods("POST")
+
+		// Unit cost sheets and booking price locks
+		priceListRoutes.HandleFunc("/cost-sheet/{unit_id}", priceListHandler.GetCostSheet).Methods("GET")
+		priceListRoutes.HandleFunc("/units/{unit_id}/attributes", priceListHandler.UpdateUnitPricingAttributes).Methods("PUT")
+		priceListRoutes.HandleFunc("/bookings/{booking_id}/lock", priceListHandler.LockBookingPrice).Methods("POST")
+		priceListRoutes.HandleFunc("/bookings/{booking_id}/lock", priceListHandler.GetBookingPriceLock).Methods("GET")
+	}
+
+	// ============================================
+	// DISCOUNT APPROVAL ROUTES
+	// ============================================
+	if discountApprovalHandler != nil {
+		discountRoutes := v1.PathPrefix("/discounts").Subrouter()
+		discountRoutes.Use(middleware.AuthMiddleware(authService, log))
+		discountRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		discountRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales", "sales_manager", "vp", "director"},
+			log,
+		))
+
+		// Limits by role and project
+		discountRoutes.HandleFunc("/limits", discountApprovalHandler.SetLimit).Methods("PUT")
+		discountRoutes.HandleFunc("/limits", discountApprovalHandler.ListLimits).Methods("GET")
+
+		// Requests and the approval chain
+		discountRoutes.HandleFunc("/requests", discountApprovalHandler.RequestDiscount).Methods("POST")
+		discountRoutes.HandleFunc("/requests", discountApprovalHandler.ListDiscountRequests).Methods("GET")
+		discountRoutes.HandleFunc("/requests/{id}", discountApprovalHandler.GetDiscountRequest).Methods("GET")
+		discountRoutes.HandleFunc("/requests/{id}/decision", discountApprovalHandler.DecideDiscount).Methods("POST")
+	}
+
+	// ============================================
+	// BOOKING CANCELLATION ROUTES
+	// ============================================
+	if bookingCancellationHandler != nil {
+		cancellationRoutes := v1.PathPrefix("/cancellations").Subrouter()
+		cancellationRoutes.Use(middleware.AuthMiddleware(authService, log))
+		cancellationRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		cancellationRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales", "accountant"},
+			log,
+		))
+
+		// Forfeiture rules
+		cancellationRoutes.HandleFunc("/policies", bookingCancellationHandler.SetPolicy).Methods("PUT")
+		cancellationRoutes.HandleFunc("/policies", bookingCancellationHandler.ListPolicies).Methods("GET")
+
+		// Refund statement, approval and payout
+		cancellationRoutes.HandleFunc("/bookings/{booking_id}/statement", bookingCancellationHandler.PreviewStatement).Methods("GET")
+		cancellationRoutes.HandleFunc("", bookingCancellationHandler.RequestCancellation).Methods("POST")
+		cancellationRoutes.HandleFunc("", bookingCancellationHandler.ListCancellations).Methods("GET")
+		cancellationRoutes.HandleFunc("/{id}", bookingCancellationHandler.GetCancellation).Methods("GET")
+		cancellationRoutes.HandleFunc("/{id}/decision", bookingCancellationHandler.DecideCancellation).Methods("POST")
+		cancellationRoutes.HandleFunc("/{id}/settle", bookingCancellationHandler.SettleCancellation).Methods("POST")
+		cancellationRoutes.HandleFunc("/{id}/refund", bookingCancellationHandler.RecordRefundPayout).Methods("POST")
+	}
+
+	// ============================================
+	// UNIT TRANSFER ROUTES
+	// ============================================
+	if unitTransferHandler != nil {
+		transferRoutes := v1.PathPrefix("/transfers").Subrouter()
+		transferRoutes.Use(middleware.AuthMiddleware(authService, log))
+		transferRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		transferRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales", "accountant"},
+			log,
+		))
+
+		// Transfer fees
+		transferRoutes.HandleFunc("/fee-policies", unitTransferHandler.SetFeePolicy).Methods("PUT")
+		transferRoutes.HandleFunc("/fee-policies", unitTransferHandler.ListFeePolicies).Methods("GET")
+
+		// Request, approval, fee receipt and endorsement
+		transferRoutes.HandleFunc("", unitTransferHandler.RequestTransfer).Methods("POST")
+		transferRoutes.HandleFunc("", unitTransferHandler.ListTransfers).Methods("GET")
+		transferRoutes.HandleFunc("/{id}", unitTransferHandler.GetTransfer).Methods("GET")
+		transferRoutes.HandleFunc("/{id}/decision", unitTransferHandler.DecideTransfer).Methods("POST")
+		transferRoutes.HandleFunc("/{id}/fee", unitTransferHandler.RecordFeeReceipt).Methods("POST")
+		transferRoutes.HandleFunc("/{id}/complete", unitTransferHandler.CompleteTransfer).Methods("POST")
+		transferRoutes.HandleFunc("/{id}/ledger-audit", unitTransferHandler.GetLedgerAudit).Methods("GET")
+	}
+
+	// ============================================
+	// UNIT AVAILABILITY ROUTES
+	// ============================================
+	if unitAvailabilityHandler != nil {
+		availabilityRoutes := v1.PathPrefix("/availability").Subrouter()
+		availabilityRoutes.Use(middleware.AuthMiddleware(authService, log))
+		availabilityRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		availabilityRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "sales", "sales_manager"},
+			log,
+		))
+
+		// Tower x floor matrix; status changes are pushed over /ws as unit_status_updated
+		availabilityRoutes.HandleFunc("/projects/{project_id}/matrix", unitAvailabilityHandler.GetMatrix).Methods("GET")
+		availabilityRoutes.HandleFunc("/units/{unit_id}/status", unitAvailabilityHandler.SetUnitStatus).Methods("POST")
+		availabilityRoutes.HandleFunc("/units/{unit_id}/history", unitAvailabilityHandler.ListStatusHistory).Methods("GET")
+	}
+
+	// ============================================
+	// SNAG LIST & DEFECT LIABILITY ROUTES
+	// ============================================
+	if snagHandler != nil {
+		snagRoutes := v1.PathPrefix("/snags").Subrouter()
+		snagRoutes.Use(middleware.AuthMiddleware(authService, log))
+		snagRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		snagRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "supervisor", "sales", "accountant"},
+			log,
+		))
+
+		// Joint handover inspection
+		snagRoutes.HandleFunc("/inspections", snagHandler.CreateInspection).Methods("POST")
+		snagRoutes.HandleFunc("/inspections", snagHandler.ListInspections).Methods("GET")
+		snagRoutes.HandleFunc("/inspections/{id}", snagHandler.GetInspection).Methods("GET")
+		snagRoutes.HandleFunc("/inspections/{id}/sign-off", snagHandler.SignOffInspection).Methods("POST")
+
+		// Defect-liability periods
+		snagRoutes.HandleFunc("/dlp", snagHandler.ListDefectLiability).Methods("GET")
+		snagRoutes.HandleFunc("/dlp/units/{unit_id}", snagHandler.GetUnitDefectLiability).Methods("GET")
+
+		// Snags, contractor SLA and back-charges
+		snagRoutes.HandleFunc("", snagHandler.RaiseSnag).Methods("POST")
+		snagRoutes.HandleFunc("", snagHandler.ListSnags).Methods("GET")
+		snagRoutes.HandleFunc("/{id}", snagHandler.GetSnag).Methods("GET")
+		snagRoutes.HandleFunc("/{id}/assign", snagHandler.AssignSnag).Methods("POST")
+		snagRoutes.HandleFunc("/{id}/resolve", snagHandler.ResolveSnag).Methods("POST")
+		snagRoutes.HandleFunc("/{id}/review", snagHandler.ReviewSnag).Methods("POST")
+		snagRoutes.HandleFunc("/{id}/photos", snagHandler.AddSnagPhotos).Methods("POST")
+		snagRoutes.HandleFunc("/{id}/back-charges", snagHandler.BackChargeSnag).Methods("POST")
+	}
+
+	// ============================================
+	// MAINTENANCE BILLING & ASSOCIATION HANDOVER ROUTES
+	// ============================================
+	if maintenanceHandler != nil {
+		maintenanceRoutes := v1.PathPrefix("/maintenance").Subrouter()
+		maintenanceRoutes.Use(middleware.AuthMiddleware(authService, log))
+		maintenanceRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		maintenanceRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Tariff and enrolment of possessed units
+		maintenanceRoutes.HandleFunc("/plans", maintenanceHandler.SetPlan).Methods("PUT")
+		maintenanceRoutes.HandleFunc("/plans/{project_id}", maintenanceHandler.GetPlan).Methods("GET")
+		maintenanceRoutes.HandleFunc("/projects/{project_id}/enroll", maintenanceHandler.EnrollUnits).Methods("POST")
+
+		// Invoices, receipts and arrears
+		maintenanceRoutes.HandleFunc("/invoices/generate", maintenanceHandler.GenerateInvoices).Methods("POST")
+		maintenanceRoutes.HandleFunc("/invoices", maintenanceHandler.ListInvoices).Methods("GET")
+		maintenanceRoutes.HandleFunc("/accounts", maintenanceHandler.ListAccounts).Methods("GET")
+		maintenanceRoutes.HandleFunc("/accounts/{id}", maintenanceHandler.GetAccount).Methods("GET")
+		maintenanceRoutes.HandleFunc("/accounts/{id}/receipts", maintenanceHandler.RecordReceipt).Methods("POST")
+		maintenanceRoutes.HandleFunc("/arrears", maintenanceHandler.GetArrears).Methods("GET")
+
+		// Handover to the residents' association
+		maintenanceRoutes.HandleFunc("/projects/{project_id}/closing-statement", maintenanceHandler.GetClosingStatement).Methods("GET")
+		maintenanceRoutes.HandleFunc("/projects/{project_id}/handover", maintenanceHandler.HandOverToAssociation).Methods("POST")
+		maintenanceRoutes.HandleFunc("/projects/{project_id}/handover", maintenanceHandler.GetHandover).Methods("GET")
+	}
+
+	// ============================================
+	// LAND BANK & JOINT DEVELOPMENT AGREEMENT ROUTES
+	// ============================================
+	if landBankHandler != nil {
+		landBankRoutes := v1.PathPrefix("/land-bank").Subrouter()
+		landBankRoutes.Use(middleware.AuthMiddleware(authService, log))
+		landBankRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		landBankRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant", "legal"},
+			log,
+		))
+
+		// Parcels, acquisition costs and payments
+		landBankRoutes.HandleFunc("/parcels", landBankHandler.CreateParcel).Methods("POST")
+		landBankRoutes.HandleFunc("/parcels", landBankHandler.ListParcels).Methods("GET")
+		landBankRoutes.HandleFunc("/parcels/{parcel_id}", landBankHandler.GetParcel).Methods("GET")
+		landBankRoutes.HandleFunc("/parcels/{parcel_id}", landBankHandler.UpdateParcel).Methods("PUT")
+		landBankRoutes.HandleFunc("/parcels/{parcel_id}/costs", landBankHandler.RecordCost).Methods("POST")
+		landBankRoutes.HandleFunc("/costs/{cost_id}/payments", landBankHandler.RecordPayment).Methods("POST")
+
+		// Joint development agreements and landowner units
+		landBankRoutes.HandleFunc("/jdas", landBankHandler.CreateJDA).Methods("POST")
+		landBankRoutes.HandleFunc("/jdas", landBankHandler.ListJDAs).Methods("GET")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}", landBankHandler.GetJDA).Methods("GET")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/allocations", landBankHandler.AllotUnit).Methods("POST")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/allocations", landBankHandler.ListAllocations).Methods("GET")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/area-position", landBankHandler.GetAreaPosition).Methods("GET")
+		landBankRoutes.HandleFunc("/allocations/{allocation_id}/release", landBankHandler.ReleaseUnit).Methods("POST")
+
+		// Revenue share payouts
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/payouts/preview", landBankHandler.PreviewPayouts).Methods("POST")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/payouts", landBankHandler.RecordPayouts).Methods("POST")
+		landBankRoutes.HandleFunc("/jdas/{jda_id}/payouts", landBankHandler.ListPayouts).Methods("GET")
+	}
+
+	// ============================================
+	// HOME LOAN DISBURSEMENT REQUEST ROUTES
+	// ============================================
+	if loanDisbursementHandler != nil {
+		loanRoutes := v1.PathPrefix("/loan-disbursements").Subrouter()
+		loanRoutes.Use(middleware.AuthMiddleware(authService, log))
+		loanRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		loanRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Lender request formats
+		loanRoutes.HandleFunc("/formats", loanDisbursementHandler.SetFormat).Methods("PUT")
+		loanRoutes.HandleFunc("/formats/{bank_id}", loanDisbursementHandler.GetFormat).Methods("GET")
+
+		// Requests raised from construction demands
+		loanRoutes.HandleFunc("/demand-letters/{letter_id}/request", loanDisbursementHandler.RaiseRequest).Methods("POST")
+		loanRoutes.HandleFunc("/requests", loanDisbursementHandler.ListRequests).Methods("GET")
+		loanRoutes.HandleFunc("/requests/{id}", loanDisbursementHandler.GetRequest).Methods("GET")
+		loanRoutes.HandleFunc("/requests/{id}/consent", loanDisbursementHandler.RecordConsent).Methods("POST")
+		loanRoutes.HandleFunc("/requests/{id}/documents", loanDisbursementHandler.AttachDocument).Methods("POST")
+		loanRoutes.HandleFunc("/requests/{id}/submit", loanDisbursementHandler.SubmitRequest).Methods("POST")
+		loanRoutes.HandleFunc("/requests/{id}/reject", loanDisbursementHandler.RejectRequest).Methods("POST")
+
+		// Credits from lenders
+		loanRoutes.HandleFunc("/incoming", loanDisbursementHandler.RecordIncoming).Methods("POST")
+		loanRoutes.HandleFunc("/incoming", loanDisbursementHandler.ListIncoming).Methods("GET")
+		loanRoutes.HandleFunc("/incoming/{id}/match", loanDisbursementHandler.MatchIncoming).Methods("POST")
+
+		// Loan versus own-contribution split of a booking
+		loanRoutes.HandleFunc("/bookings/{booking_id}/funding", loanDisbursementHandler.GetFundingSplit).Methods("GET")
+	}
+
+	// ============================================
+	// SUBVENTION (BUILDER-PAID PRE-EMI) ROUTES
+	// ============================================
+	if subventionHandler != nil {
+		subventionRoutes := v1.PathPrefix("/subvention").Subrouter()
+		subventionRoutes.Use(middleware.AuthMiddleware(authService, log))
+		subventionRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		subventionRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Agreements and their obligation schedules
+		subventionRoutes.HandleFunc("/agreements", subventionHandler.CreateAgreement).Methods("POST")
+		subventionRoutes.HandleFunc("/agreements", subventionHandler.ListAgreements).Methods("GET")
+		subventionRoutes.HandleFunc("/agreements/{id}", subventionHandler.GetAgreement).Methods("GET")
+		subventionRoutes.HandleFunc("/agreements/{id}/stop", subventionHandler.StopAgreement).Methods("POST")
+
+		// Monthly provisioning and payments to lenders
+		subventionRoutes.HandleFunc("/provision", subventionHandler.Provision).Methods("POST")
+		subventionRoutes.HandleFunc("/payments", subventionHandler.RecordPayment).Methods("POST")
+
+		// Liability by project and lender
+		subventionRoutes.HandleFunc("/liability", subventionHandler.GetLiabilityReport).Methods("GET")
+	}
+
+	// ============================================
+	// BROKER COMMISSION ENGINE
+	// ============================================
+	if brokerCommissionHandler != nil {
+		commissionRoutes := v1.PathPrefix("/broker-commission").Subrouter()
+		commissionRoutes.Use(middleware.AuthMiddleware(authService, log))
+		commissionRoutes.Use(middleware.TenantIsolationMiddleware(log))
+		commissionRoutes.Use(middleware.PermissionBasedAccessMiddleware(
+			rbacService,
+			[]string{"admin", "manager", "accountant"},
+			log,
+		))
+
+		// Plans with triggers, project overrides and quarterly slabs
+		commissionRoutes.HandleFunc("/plans", brokerCommissionHandler.CreatePlan).Methods("POST")
+		commissionRoutes.HandleFunc("/plans", brokerCommissionHandler.ListPlans).Methods("GET")
+
+		// Splits and trigger evaluation
+		commissionRoutes.HandleFunc("/bookings/{bookingId}/splits", brokerCommissionHandler.SetSplits).Methods("PUT")
+		commissionRoutes.HandleFunc("/bookings/{bookingId}/evaluate", brokerCommissionHandler.EvaluateBooking).Methods("POST")
+		commissionRoutes.HandleFunc("/evaluate", brokerCommissionHandler.EvaluatePending).Methods("POST")
+		commissionRoutes.HandleFunc("/accruals", brokerCommissionHandler.ListAccruals).Methods("GET")
+
+		// Payouts net of TDS and clawbacks
+		commissionRoutes.HandleFunc("/payouts", brokerCommissionHandler.GeneratePayout).Methods("POST")
+		commissionRoutes.HandleFunc("/payouts", brokerCommissionHandler.ListPayouts).Methods("GET")
+		commissionRoutes.HandleFunc("/payouts/{id}", brokerCommissionHandler.GetPayout).Methods("GET")
+		commissionRoutes.HandleFunc("/payouts/{id}/paid", brokerCommissionHandler.MarkPayoutPaid).Methods("POST")
+
+		// Broker statements
+		commissionRoutes.HandleFunc("/brokers/{brokerId}/statement", brokerCommissionHandler.GetStatement).Methods("GET")
+	}
+
+	// ============================================
+	// CHANNEL PARTNER PORTAL
+	// ============================================
+	// Partner-facing routes authenticate partner tokens only; staff tokens are
+	// rejected here and partner tokens are rejected everywhere else.
+	if partnerPortalHandler != nil {
+		portalService := partnerPortalHandler.Service
+
+		partnerLogin := v1.PathPrefix("/partner-portal/auth").Subrouter()
+		partnerLogin.Use(middleware.PartnerRateLimitMiddleware(middleware.NewRateLimiter(10, time.Minute), log))
+		partnerLogin.HandleFunc("/login", partnerPortalHandler.Login).Methods("POST")
+
+		partnerRoutes := v1.PathPrefix("/partner-portal").Subrouter()
+		partnerRoutes.Use(middleware.PartnerAuthMiddleware(portalService, log))
+		partnerRoutes.Use(middleware.PartnerRateLimitMiddleware(middleware.NewRateLimiter(120, time.Minute), log))
+
+		scoped := func(scope string, h http.HandlerFunc) http.Handler {
+			return middleware.RequirePartnerScope(scope)(h)
+		}
+
+		partnerRoutes.HandleFunc("/me", partnerPortalHandler.GetProfile).Methods("GET")
+
+		// Leads with booking status
+		partnerRoutes.Handle("/leads", scoped(models.PartnerScopeLeadsWrite, partnerPortalHandler.SubmitLead)).Methods("POST")
+		partnerRoutes.Handle("/leads", scoped(models.PartnerScopeLeadsRead, partnerPortalHandler.ListLeads)).Methods("GET")
+		partnerRoutes.Handle("/leads/{id}", scoped(models.PartnerScopeLeadsRead, partnerPortalHandler.GetLead)).Methods("GET")
+
+		// Payouts and statements
+		partnerRoutes.Handle("/payouts", scoped(models.PartnerScopePayoutsRead, partnerPortalHandler.ListPayouts)).Methods("GET")
+		partnerRoutes.Handle("/payouts/{id}/statement", scoped(models.PartnerScopePayoutsRead, partnerPortalHandler.GetPayoutStatement)).Methods("GET")
+
+		// Invoices against payouts
+		partnerRoutes.Handle("/invoices", scoped(models.PartnerScopeInvoicesWrite, partnerPortalHandler.UploadInvoice)).Methods("POST")
+		partnerRoutes.Handle("/invoices", scoped(models.PartnerScopePayoutsRead, partnerPortalHandler.ListInvoices)).Methods("GET")
+	}
+
 	// ============================================
 	// GENERAL LEDGER (GL) ROUTES
 	// ============================================